@@ -0,0 +1,653 @@
+// Command ast runs library operations against the database directly, without
+// the HTTP server, so they can be scheduled from cron or run from a terminal.
+// It reads the same environment (.env) as the server.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/yourusername/audience-stage-teleprompter/internal/auth"
+	"github.com/yourusername/audience-stage-teleprompter/internal/backup"
+	"github.com/yourusername/audience-stage-teleprompter/internal/database"
+	"github.com/yourusername/audience-stage-teleprompter/internal/demo"
+	"github.com/yourusername/audience-stage-teleprompter/internal/embedded"
+	"github.com/yourusername/audience-stage-teleprompter/internal/export"
+	"github.com/yourusername/audience-stage-teleprompter/internal/gdrive"
+	"github.com/yourusername/audience-stage-teleprompter/internal/handlers"
+	"github.com/yourusername/audience-stage-teleprompter/internal/importer"
+	"github.com/yourusername/audience-stage-teleprompter/internal/meilisearch"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/s3"
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
+	"github.com/yourusername/audience-stage-teleprompter/internal/typesense"
+)
+
+const usage = `usage: ast <command> [flags] [args]
+
+Commands:
+  import   -format opensong|easyworship|videopsalm [-language L] [-library L] [-dry-run] FILE|DIR...
+           Import songs from other worship software (zips are expanded)
+  export   [-format archive|openlyrics|chordpro] [-o FILE]
+           Write a migration archive (default) or a zip of every song; -o - writes to stdout
+  reindex  Rebuild the search index from the database
+  backup   [-type manual] Dump the database into BACKUP_DIR (with pg_dump
+           unless it is SQLite), and upload it to Google Drive if
+           GOOGLE_DRIVE_CREDENTIALS is set and to S3 if S3_BUCKET is set
+  seed-demo
+           Add the demo library (sample songs, setlists, usage) to an empty library
+  restore  [-skip-verify] FILE
+           Restore a backup (.sql, .sql.gz or .enc; a name in BACKUP_DIR
+           or a path) or a migration archive into an empty database, then
+           reindex. A backup that doesn't match its recorded checksum is
+           refused; encrypted ones need BACKUP_ENCRYPTION_KEY
+  user-add [-role admin|editor|operator|viewer] [-name NAME] [-password-stdin] USERNAME
+           Create an account. Without -password-stdin a temporary password is
+           printed, to be changed at first sign-in
+  apikey-add [-role ROLE] [-days N] USERNAME NAME
+           Create an API key for a user's scripts or devices, with their role
+           or the lower one given, and print it
+  migrate  [-status]
+           Apply the pending schema migrations (the server also does this at
+           startup unless AUTO_MIGRATE=false); -status lists them instead
+
+Run "ast <command> -h" for a command's flags.
+`
+
+func main() {
+	log.SetFlags(0)
+	godotenv.Load()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+	var err error
+	switch command {
+	case "import":
+		err = runImport(args)
+	case "export":
+		err = runExport(args)
+	case "reindex":
+		err = runReindex(args)
+	case "backup":
+		err = runBackup(args)
+	case "restore":
+		err = runRestore(args)
+	case "seed-demo":
+		err = runSeedDemo(args)
+	case "user-add":
+		err = runUserAdd(args)
+	case "apikey-add":
+		err = runAPIKeyAdd(args)
+	case "migrate":
+		err = runMigrate(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("ast %s: %v", command, err)
+	}
+}
+
+// app holds the connections a command needs
+type app struct {
+	db      *database.DB
+	ts      search.Backend // nil when search is disabled or unreachable
+	backups *backup.Manager
+	h       *handlers.Handler
+}
+
+// How much a command needs the search engine
+const (
+	noSearch       = iota // never connects
+	optionalSearch        // carries on without it if it can't be reached
+	requiredSearch        // fails if it is disabled or can't be reached
+)
+
+// connect opens the database and, if the command needs it, the search engine
+func connect(need int) (*app, error) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		return nil, fmt.Errorf("DATABASE_URL environment variable is required")
+	}
+	db, err := database.New(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var ts search.Backend
+	if need != noSearch && os.Getenv("DISABLE_TYPESENSE") != "true" {
+		ts = connectSearch()
+		// An embedded index that was never built is not ready, and reindexing
+		// is how it gets built
+		_, isEmbedded := ts.(*embedded.Index)
+		if !ts.Ready() && !(isEmbedded && need == requiredSearch) {
+			if need == requiredSearch {
+				db.Close()
+				return nil, fmt.Errorf("search engine unavailable")
+			}
+			log.Printf("⚠️  Search engine unavailable, songs will not be indexed (run \"ast reindex\" later)")
+			ts = nil
+		}
+	} else if need == requiredSearch {
+		db.Close()
+		return nil, fmt.Errorf("search is disabled (DISABLE_TYPESENSE=true)")
+	}
+
+	backupDir := os.Getenv("BACKUP_DIR")
+	if backupDir == "" {
+		backupDir = "./backups"
+	}
+	backups := backup.NewManager(dsn, backupDir, 100)
+	backups.SetFormat(os.Getenv("BACKUP_COMPRESS") != "false", os.Getenv("BACKUP_ENCRYPTION_KEY"))
+	if db.Embedded() {
+		backups.SetDumper(db)
+	}
+
+	h := handlers.New(db, ts, backups, nil, nil, nil, nil, os.Getenv("SKIP_TYPESENSE") == "true")
+	h.SetTransliterateOnSave(os.Getenv("TRANSLITERATE_ON_SAVE") == "true")
+	if h.LoadSearchTokenization() && need == optionalSearch {
+		log.Printf("⚠️  The search index was built with other tokenization settings (run \"ast reindex\" to apply them)")
+	}
+
+	return &app{db: db, ts: ts, backups: backups, h: h}, nil
+}
+
+// connectSearch connects to the search engine chosen by SEARCH_BACKEND
+func connectSearch() search.Backend {
+	switch os.Getenv("SEARCH_BACKEND") {
+	case "meilisearch":
+		return meilisearch.Connect(os.Getenv("MEILISEARCH_API_KEY"), os.Getenv("MEILISEARCH_HOST"))
+	case "embedded":
+		dir := os.Getenv("DATA_DIR")
+		if dir == "" {
+			dir = "./data"
+		}
+		return embedded.Open(dir)
+	}
+	client := typesense.Connect(os.Getenv("TYPESENSE_API_KEY"), os.Getenv("TYPESENSE_HOST"), os.Getenv("TYPESENSE_PER_LANGUAGE") == "true")
+	if n, err := strconv.Atoi(os.Getenv("TYPESENSE_INDEX_CONCURRENCY")); err == nil {
+		client.SetIndexConcurrency(n)
+	}
+	if n, err := strconv.Atoi(os.Getenv("TYPESENSE_INDEX_BATCH_SIZE")); err == nil {
+		client.SetIndexBatchSize(n)
+	}
+	return client
+}
+
+func (a *app) close() {
+	// Edits to the embedded search index are saved in batches
+	if index, ok := a.ts.(*embedded.Index); ok {
+		if err := index.Flush(); err != nil {
+			log.Printf("Error saving the embedded search index: %v", err)
+		}
+	}
+	a.db.Close()
+}
+
+// reindex rebuilds the search index from the database, if there is one
+func (a *app) reindex() error {
+	if a.ts == nil {
+		return nil
+	}
+	songs, err := a.db.GetAllSongs()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve songs: %w", err)
+	}
+	if err := a.ts.ReindexAll(songs); err != nil {
+		return err
+	}
+	log.Printf("Reindexed %d songs", len(songs))
+	return nil
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "", "opensong, easyworship or videopsalm")
+	language := flags.String("language", "", "language of the songs (default english for OpenSong, detected per song otherwise)")
+	library := flags.String("library", "", "library to file the songs under (default the format's name, or the VideoPsalm songbook)")
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without saving")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return fmt.Errorf("no files given")
+	}
+	switch *format {
+	case handlers.ImportOpenSong:
+		setDefault(language, "english")
+		setDefault(library, "OpenSong")
+	case handlers.ImportEasyWorship:
+		setDefault(language, "auto")
+		setDefault(library, "EasyWorship")
+	case handlers.ImportVideoPsalm:
+		setDefault(language, "auto")
+	default:
+		return fmt.Errorf("-format must be opensong, easyworship or videopsalm")
+	}
+
+	files, err := readFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+	songs, failures, err := handlers.ParseImportFiles(*format, files, *language, *library)
+	if err != nil {
+		return err
+	}
+
+	a, err := connect(optionalSearch)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	result, err := a.h.ImportSongs(songs, failures, *dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to load existing songs: %w", err)
+	}
+	if err := printJSON(result); err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d files or songs failed to import", len(result.Failed))
+	}
+	return nil
+}
+
+func setDefault(value *string, def string) {
+	if *value == "" {
+		*value = def
+	}
+}
+
+// readFiles reads the named files, and every file under named directories,
+// expanding zips
+func readFiles(paths []string) ([]importer.File, error) {
+	var files []importer.File
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), ".") {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			name, err := filepath.Rel(filepath.Dir(root), path)
+			if err != nil {
+				name = path
+			}
+			files = append(files, importer.File{Name: filepath.ToSlash(name), Data: data})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return importer.ExpandZips(files)
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "archive", "archive, openlyrics or chordpro")
+	output := flags.String("o", "", "file to write (default archive-DATE.json or library-DATE.zip, - for stdout)")
+	flags.Parse(args)
+
+	date := time.Now().Format("2006-01-02")
+	switch *format {
+	case "archive":
+		setDefault(output, fmt.Sprintf("archive-%s.json", date))
+	case export.LibraryOpenLyrics, export.LibraryChordPro:
+		setDefault(output, fmt.Sprintf("library-%s.zip", date))
+	default:
+		return fmt.Errorf("-format must be archive, openlyrics or chordpro")
+	}
+
+	a, err := connect(noSearch)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+
+	if *format == "archive" {
+		err = a.h.WriteArchive(w)
+	} else {
+		numbers, nerr := a.db.GetAllSongNumbers()
+		if nerr != nil {
+			return fmt.Errorf("failed to load song numbers: %w", nerr)
+		}
+		err = a.h.WriteLibrary(w, *format, numbers)
+	}
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if *output != "-" {
+		log.Printf("Exported to %s", *output)
+	}
+	return nil
+}
+
+func runReindex(args []string) error {
+	flags := flag.NewFlagSet("reindex", flag.ExitOnError)
+	flags.Parse(args)
+
+	a, err := connect(requiredSearch)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	return a.reindex()
+}
+
+func runBackup(args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	backupType := flags.String("type", "manual", "label in the backup's file name, such as manual or daily")
+	flags.Parse(args)
+
+	a, err := connect(noSearch)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	if creds := os.Getenv("GOOGLE_DRIVE_CREDENTIALS"); creds != "" {
+		drive, err := gdrive.New(creds, os.Getenv("GOOGLE_DRIVE_FOLDER_ID"))
+		if err != nil {
+			return err
+		}
+		a.backups.AddTarget(drive)
+	}
+	if config, ok := s3.EnvConfig(); ok {
+		bucket, err := s3.New(config)
+		if err != nil {
+			return err
+		}
+		a.backups.AddTarget(bucket)
+	}
+	if days, err := strconv.Atoi(os.Getenv("BACKUP_REMOTE_KEEP_DAYS")); err == nil && days >= 0 {
+		a.backups.SetRemoteRetention(days)
+	}
+
+	if err := a.backups.CreateBackup(*backupType); err != nil {
+		return err
+	}
+	// Uploads run in the background; wait for them before exiting
+	a.backups.Wait()
+	for _, target := range a.backups.Status().Remote {
+		if target.LastError != "" {
+			return fmt.Errorf("backup created but not uploaded to %s: %s", target.Target, target.LastError)
+		}
+	}
+	return nil
+}
+
+func runRestore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	skipVerify := flags.Bool("skip-verify", false, "restore a backup even if it doesn't match the checksum in its metadata")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("give one backup or archive file to restore")
+	}
+	file := flags.Arg(0)
+
+	a, err := connect(optionalSearch)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	if backup.IsBackupFile(file) {
+		if err := a.backups.Restore(file, !*skipVerify); err != nil {
+			return err
+		}
+		return a.reindex()
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	archive, err := handlers.ReadArchive(data)
+	if err != nil {
+		return err
+	}
+	result, err := a.db.ImportArchive(archive)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(result); err != nil {
+		return err
+	}
+	if a.ts != nil && len(archive.Songs) > 0 {
+		if err := a.ts.ReindexAll(archive.Songs); err != nil {
+			return err
+		}
+		log.Printf("Reindexed %d songs", len(archive.Songs))
+	}
+	return nil
+}
+
+func runSeedDemo(args []string) error {
+	flags := flag.NewFlagSet("seed-demo", flag.ExitOnError)
+	flags.Parse(args)
+
+	a, err := connect(optionalSearch)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	settings, err := a.db.GetSettings()
+	if err != nil {
+		return err
+	}
+	archive := demo.Archive(time.Now(), settings)
+	result, err := a.db.ImportArchive(archive)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(result); err != nil {
+		return err
+	}
+	if a.ts != nil {
+		if err := a.ts.ReindexAll(archive.Songs); err != nil {
+			return err
+		}
+		log.Printf("Indexed %d songs", len(archive.Songs))
+	}
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func runUserAdd(args []string) error {
+	flags := flag.NewFlagSet("user-add", flag.ExitOnError)
+	role := flags.String("role", models.RoleViewer, "admin, editor, operator or viewer")
+	name := flags.String("name", "", "display name")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from the first line of stdin instead of generating a temporary one")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("give one username")
+	}
+	if !models.ValidRole(*role) {
+		return fmt.Errorf("role must be one of %s", strings.Join(models.Roles, ", "))
+	}
+
+	password, temporary := "", true
+	if *passwordStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		password, temporary = strings.TrimRight(line, "\r\n"), false
+		if len(password) < auth.MinPasswordLength {
+			return fmt.Errorf("passwords need at least %d characters", auth.MinPasswordLength)
+		}
+	} else {
+		var err error
+		if password, err = auth.TemporaryPassword(); err != nil {
+			return err
+		}
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	a, err := connect(noSearch)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	req := &models.CreateUserRequest{Username: flags.Arg(0), DisplayName: *name, Role: *role}
+	user, err := a.db.CreateUser(req, hash, temporary)
+	if err != nil {
+		return err
+	}
+	log.Printf("Created %s %s (id %d)", user.Role, user.Username, user.ID)
+	if temporary {
+		fmt.Printf("Temporary password: %s\n", password)
+	}
+	return nil
+}
+
+func runAPIKeyAdd(args []string) error {
+	flags := flag.NewFlagSet("apikey-add", flag.ExitOnError)
+	role := flags.String("role", "", "admin, editor, operator or viewer (default the user's role)")
+	days := flags.Int("days", 0, "days until the key expires (default never)")
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		return fmt.Errorf("give a username and a name for the key")
+	}
+	if *role != "" && !models.ValidRole(*role) {
+		return fmt.Errorf("role must be one of %s", strings.Join(models.Roles, ", "))
+	}
+	if *days < 0 {
+		return fmt.Errorf("-days can't be negative")
+	}
+
+	a, err := connect(noSearch)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	user, err := a.db.GetUserByUsername(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	req := &models.CreateAPIKeyRequest{Name: flags.Arg(1), Role: *role, ExpiresInDays: *days}
+	if req.Role == "" {
+		req.Role = user.Role
+	}
+	if !models.RoleCovers(user.Role, req.Role) {
+		return fmt.Errorf("%s is %s; a key can't have a higher role", user.Username, user.Role)
+	}
+	var expires *time.Time
+	if *days > 0 {
+		t := time.Now().AddDate(0, 0, *days)
+		expires = &t
+	}
+
+	key, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return err
+	}
+	apiKey, err := a.db.CreateAPIKey(user.ID, req, hash, auth.KeyPrefix(key), expires)
+	if err != nil {
+		return err
+	}
+	log.Printf("Created %s key %q for %s (id %d)", apiKey.Role, apiKey.Name, user.Username, apiKey.ID)
+	fmt.Println(key)
+	return nil
+}
+
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	statusOnly := flags.Bool("status", false, "list the migrations and whether each is applied, without applying any")
+	flags.Parse(args)
+
+	a, err := connect(noSearch)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	if !*statusOnly {
+		applied, err := a.db.Migrate(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Printf("The database is up to date")
+		}
+		return nil
+	}
+
+	status, err := a.db.MigrationStatus(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, m := range status.Migrations {
+		state := "pending"
+		switch {
+		case m.Unknown:
+			state = "applied " + m.AppliedAt.Format("2006-01-02 15:04") + " (unknown to this version)"
+		case m.AppliedAt != nil && m.Modified:
+			state = "applied " + m.AppliedAt.Format("2006-01-02 15:04") + " (file changed since)"
+		case m.AppliedAt != nil:
+			state = "applied " + m.AppliedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%-40s %s\n", m.Name, state)
+	}
+	log.Printf("Version %d of %d, %d pending", status.Current, status.Latest, status.Pending)
+	return nil
+}
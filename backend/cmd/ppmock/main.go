@@ -0,0 +1,34 @@
+// Command ppmock serves a simulated ProPresenter API, so the ProPresenter
+// features can be developed and tried without a ProPresenter machine. Point
+// the server at it with PROPRESENTER_HOST=localhost and PROPRESENTER_PORT set
+// to its port (or the same in the settings).
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/ppmock"
+)
+
+func main() {
+	addr := flag.String("addr", ":4031", "address to listen on")
+	playlist := flag.String("playlist", "Live Queue", "name of the playlist songs are queued in")
+	seed := flag.Bool("seed-demo", true, "add a presentation for every demo library song")
+	latency := flag.Duration("latency", 0, "delay every response, e.g. 300ms")
+	quiet := flag.Bool("quiet", false, "don't log requests")
+	flag.Parse()
+
+	server := ppmock.New(*playlist)
+	server.SetLatency(*latency)
+	server.SetLogging(!*quiet)
+	if *seed {
+		log.Printf("Added %d demo presentations", server.SeedDemo())
+	}
+
+	log.Printf("🎛️  Simulated ProPresenter listening on %s (state at /mock/state)", *addr)
+	srv := &http.Server{Addr: *addr, Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(srv.ListenAndServe())
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/database"
+)
+
+// checkCanSignIn makes sure someone can get in once every route needs an
+// account: an enabled admin, an API key, or single sign-on
+func checkCanSignIn(db *database.DB, ssoEnabled bool) error {
+	if ssoEnabled {
+		return nil
+	}
+	admins, err := db.CountActiveAdmins()
+	if err != nil {
+		return err
+	}
+	keys, err := db.CountUsableAPIKeys()
+	if err != nil {
+		return err
+	}
+	if admins == 0 && keys == 0 {
+		return errors.New(`there is no admin account or API key, so no one could sign in. Create an admin with "ast user-add -role admin NAME", or set AUTH_DISABLED=true to run without sign-in on a trusted network`)
+	}
+	return nil
+}
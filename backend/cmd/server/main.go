@@ -1,26 +1,48 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/joho/godotenv"
+	"github.com/yourusername/audience-stage-teleprompter/internal/audio"
 	"github.com/yourusername/audience-stage-teleprompter/internal/backup"
 	"github.com/yourusername/audience-stage-teleprompter/internal/database"
+	"github.com/yourusername/audience-stage-teleprompter/internal/embedded"
+	"github.com/yourusername/audience-stage-teleprompter/internal/errreport"
+	"github.com/yourusername/audience-stage-teleprompter/internal/gdrive"
 	"github.com/yourusername/audience-stage-teleprompter/internal/handlers"
+	"github.com/yourusername/audience-stage-teleprompter/internal/live"
+	"github.com/yourusername/audience-stage-teleprompter/internal/maintenance"
+	"github.com/yourusername/audience-stage-teleprompter/internal/meilisearch"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/netacl"
+	"github.com/yourusername/audience-stage-teleprompter/internal/oidc"
 	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+	"github.com/yourusername/audience-stage-teleprompter/internal/reqlog"
+	"github.com/yourusername/audience-stage-teleprompter/internal/s3"
+	"github.com/yourusername/audience-stage-teleprompter/internal/scripture"
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
+	"github.com/yourusername/audience-stage-teleprompter/internal/slowlog"
 	"github.com/yourusername/audience-stage-teleprompter/internal/typesense"
+	"github.com/yourusername/audience-stage-teleprompter/internal/ws"
 )
 
 func main() {
+	seedDemo := flag.Bool("seed-demo", false, "add the demo library if the library is empty")
+	flag.Parse()
+
 	// Load environment variables
-	if err := godotenv.Load(); err != nil {
+	if err := loadEnvFile(); err != nil {
 		log.Println("No .env file found, using system environment variables")
 	}
 
@@ -30,18 +52,40 @@ func main() {
 		log.Fatal("DATABASE_URL environment variable is required")
 	}
 
-	// Typesense is optional - can be disabled
+	// Search is optional - can be disabled. Typesense by default, Meilisearch,
+	// or an index embedded in the server
 	disableTypesense := os.Getenv("DISABLE_TYPESENSE") == "true"
+	searchBackend := os.Getenv("SEARCH_BACKEND")
+	if searchBackend == "" {
+		searchBackend = "typesense"
+	}
 	typesenseAPIKey := os.Getenv("TYPESENSE_API_KEY")
 	typesenseHost := os.Getenv("TYPESENSE_HOST")
+	meilisearchAPIKey := os.Getenv("MEILISEARCH_API_KEY")
+	meilisearchHost := os.Getenv("MEILISEARCH_HOST")
+	dataDir := os.Getenv("DATA_DIR")
+	if dataDir == "" {
+		dataDir = "./data"
+	}
 	
-	var ts *typesense.Client
+	var ts search.Backend
 	if !disableTypesense {
-		if typesenseAPIKey == "" {
-			log.Fatal("TYPESENSE_API_KEY environment variable is required (or set DISABLE_TYPESENSE=true)")
-		}
-		if typesenseHost == "" {
-			log.Fatal("TYPESENSE_HOST environment variable is required (or set DISABLE_TYPESENSE=true)")
+		switch searchBackend {
+		case "typesense":
+			if typesenseAPIKey == "" {
+				log.Fatal("TYPESENSE_API_KEY environment variable is required (or set DISABLE_TYPESENSE=true)")
+			}
+			if typesenseHost == "" {
+				log.Fatal("TYPESENSE_HOST environment variable is required (or set DISABLE_TYPESENSE=true)")
+			}
+		case "meilisearch":
+			if meilisearchHost == "" {
+				log.Fatal("MEILISEARCH_HOST environment variable is required with SEARCH_BACKEND=meilisearch")
+			}
+		case "embedded":
+			// Needs no service, only DATA_DIR for the index file
+		default:
+			log.Fatalf("SEARCH_BACKEND must be typesense, meilisearch or embedded, not %q", searchBackend)
 		}
 	}
 
@@ -71,6 +115,22 @@ func main() {
 		ppPort = "4031" // ProPresenter REST API default port
 	}
 
+	// Calls slower than these are logged and counted in the admin stats (0 turns one off)
+	for kind, env := range map[string]string{slowlog.DB: "SLOW_DB_MS", slowlog.Typesense: "SLOW_TYPESENSE_MS", slowlog.ProPresenter: "SLOW_PROPRESENTER_MS", slowlog.Meilisearch: "SLOW_MEILISEARCH_MS"} {
+		if ms, err := strconv.Atoi(os.Getenv(env)); err == nil && ms >= 0 {
+			slowlog.SetThreshold(kind, time.Duration(ms)*time.Millisecond)
+		}
+	}
+
+	// Log level, CORS, backup schedule and rate limits; these can also be
+	// changed without a restart (SIGHUP or POST /api/admin/config/reload)
+	runtimeCfg, err := loadRuntimeConfig()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	reqlog.SetLevel(runtimeCfg.LogLevel)
+	ws.SetAllowedOrigins(runtimeCfg.CORSOrigins)
+
 	// Initialize database
 	db, err := database.New(dbDSN)
 	if err != nil {
@@ -78,21 +138,78 @@ func main() {
 	}
 	defer db.Close()
 
-	// Initialize Typesense (optional)
+	// Bring the schema up to date (AUTO_MIGRATE=false to migrate separately
+	// with "ast migrate")
+	if os.Getenv("AUTO_MIGRATE") != "false" {
+		if _, err := db.Migrate(context.Background()); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+	} else if status, err := db.MigrationStatus(context.Background()); err != nil {
+		log.Printf("⚠️  Could not read migration status: %v", err)
+	} else if status.Pending > 0 {
+		log.Printf("⚠️  %d migrations are pending; run \"ast migrate\"", status.Pending)
+	}
+
+	// Initialize search (optional)
 	if !disableTypesense {
-		ts, err = typesense.New(typesenseAPIKey, typesenseHost)
-		if err != nil {
-			log.Fatalf("Failed to initialize Typesense: %v", err)
+		// Never fatal: if the search engine is down, search uses PostgreSQL until it comes up
+		switch searchBackend {
+		case "meilisearch":
+			ts = meilisearch.Connect(meilisearchAPIKey, meilisearchHost)
+		case "embedded":
+			ts = embedded.Open(dataDir)
+		default:
+			client := typesense.Connect(typesenseAPIKey, typesenseHost, os.Getenv("TYPESENSE_PER_LANGUAGE") == "true")
+			if n, err := strconv.Atoi(os.Getenv("TYPESENSE_INDEX_CONCURRENCY")); err == nil {
+				client.SetIndexConcurrency(n)
+			}
+			if n, err := strconv.Atoi(os.Getenv("TYPESENSE_INDEX_BATCH_SIZE")); err == nil {
+				client.SetIndexBatchSize(n)
+			}
+			ts = client
 		}
-		log.Println("Typesense client initialized")
 	} else {
 		log.Println("⚠️  Typesense is disabled - search will use PostgreSQL")
 	}
 
-	// Initialize backup manager (backup every 100 edits)
-	backupManager := backup.NewManager(dbDSN, backupDir, 100)
+	// Initialize backup manager (daily, and after BACKUP_EVERY_EDITS edits)
+	backupManager := backup.NewManager(dbDSN, backupDir, runtimeCfg.BackupEveryEdits)
+	backupManager.SetSchedule(runtimeCfg.BackupDailyHour, runtimeCfg.BackupEveryEdits)
+	// A SQLite database dumps itself, so the all-in-one mode needs no pg_dump
+	if db.Embedded() {
+		backupManager.SetDumper(db)
+	}
+	// Gzipped unless BACKUP_COMPRESS=false, and encrypted when a key is set
+	backupManager.SetFormat(os.Getenv("BACKUP_COMPRESS") != "false", os.Getenv("BACKUP_ENCRYPTION_KEY"))
+	// Copy each backup to a Google Drive folder as well (optional)
+	if creds := os.Getenv("GOOGLE_DRIVE_CREDENTIALS"); creds != "" {
+		drive, err := gdrive.New(creds, os.Getenv("GOOGLE_DRIVE_FOLDER_ID"))
+		if err != nil {
+			log.Printf("⚠️  Google Drive backups disabled: %v", err)
+		} else {
+			backupManager.AddTarget(drive)
+			log.Println("Backups will be copied to Google Drive")
+		}
+	}
+	// ... and to an S3-compatible bucket such as AWS S3 or MinIO (optional)
+	if config, ok := s3.EnvConfig(); ok {
+		bucket, err := s3.New(config)
+		if err != nil {
+			log.Printf("⚠️  S3 backups disabled: %v", err)
+		} else {
+			backupManager.AddTarget(bucket)
+			log.Printf("Backups will be copied to S3 bucket %s", config.Bucket)
+		}
+	}
+	if days, err := strconv.Atoi(os.Getenv("BACKUP_REMOTE_KEEP_DAYS")); err == nil && days >= 0 {
+		backupManager.SetRemoteRetention(days)
+	}
 	backupManager.Start()
 
+	// Cancelled on Ctrl-C or SIGTERM, which shuts the server down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Initialize ProPresenter client from database settings
 	var ppClient *propresenter.Client
 	settings, err := db.GetSettings()
@@ -122,13 +239,7 @@ func main() {
 				PlaylistID: settings.ProPresenterPlaylist,
 			}
 			ppClient = propresenter.New(ppConfig)
-			if ppClient.IsConnected() {
-				log.Printf("✅ ProPresenter integration enabled and connected: %s:%d", settings.ProPresenterHost, settings.ProPresenterPort)
-			} else {
-				log.Printf("⚠️  ProPresenter integration enabled but not connected: %s:%d", settings.ProPresenterHost, settings.ProPresenterPort)
-			}
-			// Start periodic health checks (every 30 seconds)
-			ppClient.StartPeriodicHealthCheck(30 * time.Second)
+			log.Printf("✅ ProPresenter integration enabled: %s:%d (connecting in the background)", settings.ProPresenterHost, settings.ProPresenterPort)
 		} else {
 			// Fallback to environment variables if database settings are empty
 			if ppEnabled && ppHost != "" {
@@ -140,7 +251,6 @@ func main() {
 				}
 				ppClient = propresenter.New(ppConfig)
 				log.Printf("✅ ProPresenter integration enabled (from env): %s:%s", ppHost, ppPort)
-				ppClient.StartPeriodicHealthCheck(30 * time.Second)
 			} else {
 				ppClient = propresenter.New(nil)
 				log.Println("ℹ️  ProPresenter integration disabled")
@@ -148,79 +258,592 @@ func main() {
 		}
 	}
 
+	// Check the connection until shutdown, also while disabled so enabling
+	// ProPresenter in the settings needs no restart
+	ppClient.StartPeriodicHealthCheck(ctx, handlers.HealthCheckInterval(settings))
+
+	// Follow what ProPresenter shows through its status stream; while the
+	// stream is open the health check doesn't need to ping
+	ppClient.StartStatusListener(ctx)
+
+	// Log ProPresenter changes instead of making them, for operator practice
+	if runtimeCfg.ProPresenterDryRun {
+		ppClient.SetDryRun(true)
+		log.Println("🧪 ProPresenter dry-run mode enabled - triggers and playlist changes are only logged")
+	}
+
+	// Live channel for teleprompter and stage displays
+	liveHub := live.NewHub()
+
+	// Scripture lookups (bible-api.com format; translation can be overridden per request)
+	scriptureClient := scripture.New(os.Getenv("SCRIPTURE_API_URL"), os.Getenv("SCRIPTURE_TRANSLATION"))
+
+	// Linked audio tracks, played locally or through ProPresenter
+	audioDir := os.Getenv("AUDIO_DIR")
+	if audioDir == "" {
+		audioDir = "./audio"
+	}
+	audioPlayer := audio.New(audioDir, os.Getenv("AUDIO_PLAYER_COMMAND"), os.Getenv("AUDIO_OUTPUT"))
+
+	// Public read-only API for the church website and congregation apps (opt-in)
+	publicAPIEnabled := os.Getenv("PUBLIC_API_ENABLED") == "true"
+	publicAPI := handlers.PublicAPIConfig{
+		Anonymous: os.Getenv("PUBLIC_API_ANONYMOUS") == "true",
+	}
+	for _, token := range strings.Split(os.Getenv("PUBLIC_API_TOKENS"), ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			publicAPI.Tokens = append(publicAPI.Tokens, token)
+		}
+	}
+	if publicAPIEnabled && len(publicAPI.Tokens) == 0 && !publicAPI.Anonymous {
+		log.Println("⚠️  PUBLIC_API_ENABLED is set but there are no PUBLIC_API_TOKENS and PUBLIC_API_ANONYMOUS is off - every public request will be rejected")
+	}
+
+	// Rate limits for the public API and congregation song requests, which
+	// get their own per-IP limits; votes are cheaper than submissions
+	publicLimit := newSwapHandler(handlers.PublicAPILimit(runtimeCfg.PublicAPIRateLimit))
+	songRequestLimit := newSwapHandler(handlers.SongRequestLimit(runtimeCfg.SongRequestRateLimit))
+	songVoteLimit := newSwapHandler(handlers.SongRequestLimit(runtimeCfg.SongVoteRateLimit))
+	// ... and everything else under /api, per IP with separate read and write budgets
+	apiLimit := newSwapHandler(handlers.APILimit(runtimeCfg.APIReadRateLimit, runtimeCfg.APIWriteRateLimit))
+
+	// Per-route network allowlists, e.g. admin only from the booth VLAN
+	aclRules, err := netacl.Parse(os.Getenv("NETWORK_ACL"))
+	if err != nil {
+		log.Fatalf("Invalid NETWORK_ACL: %v", err)
+	}
+	for _, rule := range aclRules {
+		log.Printf("🔒 %s restricted to %d network(s)", rule.Prefix+"/", len(rule.Nets))
+	}
+
 	// Initialize handlers
-	h := handlers.New(db, ts, backupManager, ppClient, skipTypesense)
+	h := handlers.New(db, ts, backupManager, ppClient, liveHub, scriptureClient, audioPlayer, skipTypesense)
+
+	// Follow changes the ProPresenter operator makes to the Live Queue playlist
+	// (every PROPRESENTER_QUEUE_SYNC_SECONDS, default 15; 0 turns it off)
+	queueSyncSeconds := 15
+	if n, err := strconv.Atoi(os.Getenv("PROPRESENTER_QUEUE_SYNC_SECONDS")); err == nil && n >= 0 {
+		queueSyncSeconds = n
+	}
+	h.StartQueueMonitor(time.Duration(queueSyncSeconds) * time.Second)
+
+	// Link songs to their ProPresenter presentations by title, so sends go by
+	// pro_uuid (every PROPRESENTER_LIBRARY_SYNC_MINUTES, default 30; 0 turns it off)
+	librarySyncMinutes := 30
+	if n, err := strconv.Atoi(os.Getenv("PROPRESENTER_LIBRARY_SYNC_MINUTES")); err == nil && n >= 0 {
+		librarySyncMinutes = n
+	}
+	h.StartLibrarySync(time.Duration(librarySyncMinutes) * time.Minute)
+
+	// Archive songs not used in ARCHIVE_AFTER_MONTHS (off unless set)
+	if months, err := strconv.Atoi(os.Getenv("ARCHIVE_AFTER_MONTHS")); err == nil && months > 0 {
+		h.StartArchivePolicy(months)
+		log.Printf("🗄️  Songs not used in %d months will be archived daily", months)
+	}
+
+	// Romanize Indic-script songs whenever they are saved, not just on request
+	h.SetTransliterateOnSave(os.Getenv("TRANSLITERATE_ON_SAVE") == "true")
+
+	// Tidy quotes, spacing and section labels in lyrics whenever they are saved
+	h.SetFormatOnSave(os.Getenv("FORMAT_LYRICS_ON_SAVE") == "true")
+
+	// Reviewers approve edits proposed by other contributors
+	review := handlers.ReviewConfig{RequireApproval: os.Getenv("REQUIRE_EDIT_APPROVAL") == "true"}
+	for _, token := range strings.Split(os.Getenv("REVIEWER_TOKENS"), ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			review.Tokens = append(review.Tokens, token)
+		}
+	}
+	if review.RequireApproval && len(review.Tokens) == 0 {
+		log.Println("⚠️  REQUIRE_EDIT_APPROVAL is set but there are no REVIEWER_TOKENS - songs can't be changed or edits approved")
+	}
+	h.SetReviewConfig(review)
+
+	// Accounts: routes need a signed-in user or API key with a high enough
+	// role, unless AUTH_DISABLED opts out
+	authConfig := handlers.AuthConfig{Disabled: os.Getenv("AUTH_DISABLED") == "true"}
+	if os.Getenv("AUTH_ENABLED") != "" {
+		log.Println("⚠️  AUTH_ENABLED is no longer used: sign-in is always required unless AUTH_DISABLED=true")
+	}
+	if days, err := strconv.Atoi(os.Getenv("AUTH_SESSION_DAYS")); err == nil && days > 0 {
+		authConfig.SessionTTL = time.Duration(days) * 24 * time.Hour
+	}
+	h.SetAuthConfig(authConfig)
+
+	// Single sign-on with Google Workspace or another OpenID Connect provider (optional)
+	ssoEnabled := false
+	if clientID := os.Getenv("OIDC_CLIENT_ID"); clientID != "" {
+		issuer := os.Getenv("OIDC_ISSUER")
+		if issuer == "" {
+			issuer = oidc.GoogleIssuer
+		}
+		groupRoles, err := oidc.ParseGroupRoles(os.Getenv("OIDC_GROUP_ROLES"))
+		if err != nil {
+			log.Fatalf("Invalid OIDC_GROUP_ROLES: %v", err)
+		}
+		for group, role := range groupRoles {
+			if !models.ValidRole(role) {
+				log.Fatalf("Invalid OIDC_GROUP_ROLES: %s has unknown role %q", group, role)
+			}
+		}
+		defaultRole := os.Getenv("OIDC_DEFAULT_ROLE")
+		if defaultRole != "" && !models.ValidRole(defaultRole) {
+			log.Fatalf("Invalid OIDC_DEFAULT_ROLE %q", defaultRole)
+		}
+
+		discoverCtx, cancelDiscover := context.WithTimeout(context.Background(), 15*time.Second)
+		provider, err := oidc.Discover(discoverCtx, oidc.Config{
+			Issuer:       issuer,
+			ClientID:     clientID,
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+			HostedDomain: os.Getenv("OIDC_HOSTED_DOMAIN"),
+		})
+		cancelDiscover()
+		if err != nil {
+			log.Printf("⚠️  Single sign-on disabled: %v", err)
+		} else {
+			ssoConfig := handlers.SSOConfig{
+				Provider:    provider,
+				GroupRoles:  groupRoles,
+				DefaultRole: defaultRole,
+				AfterLogin:  os.Getenv("OIDC_AFTER_LOGIN_URL"),
+			}
+			if creds := os.Getenv("OIDC_GOOGLE_GROUPS_CREDENTIALS"); creds != "" {
+				groups, err := oidc.NewGoogleGroups(creds, os.Getenv("OIDC_GOOGLE_ADMIN_EMAIL"))
+				if err != nil {
+					log.Fatalf("Invalid OIDC_GOOGLE_GROUPS_CREDENTIALS: %v", err)
+				}
+				ssoConfig.Groups = groups
+			}
+			h.SetSSOConfig(ssoConfig)
+			ssoEnabled = true
+			log.Printf("🔐 Single sign-on with %s enabled (%d group roles)", issuer, len(groupRoles))
+		}
+	}
+	if authConfig.Disabled {
+		log.Println("🚨 AUTH_DISABLED is set: EVERY ROUTE IS OPEN, admin routes included, to anyone who can reach this server. Only use it on a trusted network.")
+	} else if err := checkCanSignIn(db, ssoEnabled); err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	} else {
+		log.Println("🔐 Sign-in required")
+	}
+
+	// Report panics and failed indexing/ProPresenter calls to Sentry (or compatible)
+	reporter, err := errreport.New(os.Getenv("SENTRY_DSN"), os.Getenv("SENTRY_ENVIRONMENT"), os.Getenv("SENTRY_RELEASE"))
+	if err != nil {
+		log.Fatalf("Invalid SENTRY_DSN: %v", err)
+	}
+	if reporter != nil {
+		log.Println("✅ Error reporting enabled")
+	}
+	h.SetErrorReporter(reporter)
+
+	// Pause changes during restores, migrations and reindexes
+	maintenanceMode := maintenance.New()
+	h.SetMaintenance(maintenanceMode)
+
+	// Check the search index was built with the configured tokenization
+	h.ApplySearchTokenization()
+
+	// Fill an empty library with sample songs for evaluation (--seed-demo)
+	if *seedDemo || os.Getenv("SEED_DEMO") == "true" {
+		if result, _, err := h.SeedDemoLibrary(); err != nil {
+			log.Printf("⚠️  Demo library not added: %v", err)
+		} else {
+			log.Printf("🎵 Demo library added: %d songs, %d setlists", result.Songs, result.Setlists)
+		}
+	}
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		AppName:      "Audience Stage Teleprompter",
 		ServerHeader: "AST",
+		BodyLimit:    50 * 1024 * 1024, // song library imports can be large zips
+		ErrorHandler: h.HandleError,
 	})
 
 	// Middleware
-	app.Use(recover.New())
-	app.Use(logger.New(logger.Config{
-		Format: "[${time}] ${status} - ${latency} ${method} ${path}\n",
-	}))
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
-	}))
+	app.Use(recover.New(recover.Config{EnableStackTrace: true, StackTraceHandler: h.RecoverPanic}))
+	app.Use(reqlog.Middleware())
+	corsHandler := newSwapHandler(newCORS(runtimeCfg.CORSOrigins))
+	app.Use(corsHandler.handle)
+	app.Use(netacl.Middleware(aclRules))
+	app.Use(maintenanceMode.Middleware())
+
+	// Probes for container orchestration: liveness never checks dependencies,
+	// readiness fails until the database and config are usable
+	app.Get("/healthz", h.Healthz)
+	app.Get("/readyz", h.Readyz)
+
+	// Livestream lyrics overlay (OBS browser source)
+	app.Get("/embed/current", h.EmbedCurrent)
 
 	// Routes
 	api := app.Group("/api")
 
+	// Public read-only API, registered first so its own token check and rate
+	// limit apply instead of anything added to the admin routes
+	if publicAPIEnabled {
+		public := api.Group("/public", handlers.PublicAPI(publicAPI), publicLimit.handle)
+		public.Get("/songs", h.GetPublicSongs)
+		public.Get("/songs/:id", h.GetPublicSong)
+		public.Get("/now-playing", h.GetPublicNowPlaying)
+		log.Printf("✅ Public API enabled (%d tokens, anonymous: %t)", len(publicAPI.Tokens), publicAPI.Anonymous)
+	}
+
+	// Everything below counts towards the per-IP limits, sign-in included
+	api.Use(apiLimit.handle)
+
+	// Sessions and API keys are looked up for everything below. Each route
+	// needs at least the role it names; sign-in, display registration and
+	// song requests stay open for screens and the congregation.
+	api.Use(h.Authenticate())
+	viewer := h.RequireRole(models.RolesAtLeast(models.RoleViewer)...)
+	operator := h.RequireRole(models.RolesAtLeast(models.RoleOperator)...)
+	editor := h.RequireRole(models.RolesAtLeast(models.RoleEditor)...)
+	adminOnly := h.RequireRole(models.RoleAdmin)
+	api.Post("/auth/login", h.Login)
+	api.Post("/auth/logout", h.Logout)
+	api.Get("/auth/me", h.Me)
+	api.Post("/auth/password", h.ChangePassword)
+	api.Get("/auth/oidc/login", h.SSOLogin)
+	api.Get("/auth/oidc/callback", h.SSOCallback)
+	api.Get("/auth/keys", h.GetMyAPIKeys)
+	api.Post("/auth/keys", h.CreateAPIKey)
+	api.Delete("/auth/keys/:id", h.DeleteMyAPIKey)
+
 	// Health check
 	api.Get("/health", h.HealthCheck)
+	api.Get("/maintenance", h.GetMaintenance)
 
 	// Songs CRUD
-	api.Post("/songs", h.CreateSong)
-	api.Get("/songs", h.GetAllSongs)
-	api.Get("/songs/:id", h.GetSong)
-	api.Put("/songs/:id", h.UpdateSong)
-	api.Delete("/songs/:id", h.DeleteSong)
+	api.Post("/songs", editor, h.CreateSong)
+	api.Get("/songs", viewer, h.GetAllSongs)
+	api.Get("/songs/trash", adminOnly, h.GetTrash)
+	api.Get("/songs/:id", viewer, h.GetSong)
+	api.Put("/songs/:id", editor, h.UpdateSong)
+	api.Patch("/songs/:id", editor, h.PatchSong)
+	api.Delete("/songs/:id", adminOnly, h.DeleteSong)
+	api.Post("/songs/:id/restore", adminOnly, h.RestoreSong)
+	api.Delete("/songs/:id/purge", adminOnly, h.PurgeSong)
+	api.Get("/songs/:id/export", viewer, h.ExportSong)
+	api.Get("/songs/:id/lyrics", viewer, h.GetSongLyrics)
+	api.Post("/songs/:id/archive", editor, h.ArchiveSong)
+	api.Post("/songs/:id/unarchive", editor, h.UnarchiveSong)
+
+	// Revision history
+	api.Get("/songs/:id/revisions", viewer, h.GetSongRevisions)
+	api.Post("/songs/:id/revisions/:rev/restore", editor, h.RestoreSongRevision)
+
+	// Advisory edit locks
+	api.Get("/songs/:id/lock", viewer, h.GetSongLock)
+	api.Post("/songs/:id/lock", editor, h.LockSong)
+	api.Delete("/songs/:id/lock", editor, h.UnlockSong)
+
+	// Proposed edits and their review
+	api.Get("/songs/:id/edits", viewer, h.GetSongEdits)
+	api.Post("/songs/:id/edits", viewer, h.ProposeSongEdit)
+	api.Get("/edits", viewer, h.GetEditQueue)
+	api.Get("/edits/:id", viewer, h.GetSongEdit)
+	api.Put("/edits/:id", viewer, h.UpdateSongEdit)
+	api.Delete("/edits/:id", viewer, h.DeleteSongEdit)
+	api.Post("/edits/:id/submit", viewer, h.SubmitSongEdit)
+	api.Post("/edits/:id/approve", editor, h.ApproveSongEdit)
+	api.Post("/edits/:id/reject", editor, h.RejectSongEdit)
+
+	// Collaborative editing (WebSocket)
+	api.Get("/songs/:id/collab", editor, h.CollabSong)
+	api.Get("/songs/:id/editors", viewer, h.GetSongEditors)
+
+	// External reference links
+	api.Post("/songs/:id/links/refresh", editor, h.RefreshSongLinks)
+	api.Get("/oembed", viewer, h.PreviewLink)
+
+	// Dual-language pairing
+	api.Get("/songs/:id/pair", viewer, h.GetSongPair)
+	api.Put("/songs/:id/pair", editor, h.UpdateSongPair)
+	api.Delete("/songs/:id/pair", editor, h.DeleteSongPair)
+	api.Get("/songs/:id/paired-slides", viewer, h.GetPairedSlides)
+
+	// Presenter notes (stage displays only)
+	api.Get("/songs/:id/notes", viewer, h.GetSongNotes)
+	api.Post("/songs/:id/notes", editor, h.CreateSongNote)
+	api.Put("/songs/:id/notes/:note_id", editor, h.UpdateSongNote)
+	api.Delete("/songs/:id/notes/:note_id", editor, h.DeleteSongNote)
+
+	// Band cues (stage displays and queue view)
+	api.Get("/songs/:id/cues", viewer, h.GetSongCues)
+	api.Put("/songs/:id/cues", editor, h.UpdateSongCues)
+	api.Delete("/songs/:id/cues", editor, h.DeleteSongCues)
+
+	// Auto-advance timing
+	api.Get("/songs/:id/timing", viewer, h.GetSongTiming)
+	api.Put("/songs/:id/timing", editor, h.UpdateSongTiming)
+	api.Delete("/songs/:id/timing", editor, h.DeleteSongTiming)
+
+	// Linked audio tracks
+	api.Get("/songs/:id/audio", viewer, h.GetSongAudio)
+	api.Put("/songs/:id/audio", editor, h.UpdateSongAudio)
+	api.Delete("/songs/:id/audio", editor, h.DeleteSongAudio)
+	api.Post("/songs/:id/audio/file", editor, h.UploadSongAudio)
+	api.Get("/songs/:id/audio/file", viewer, h.GetSongAudioFile)
+	api.Post("/songs/:id/audio/play", operator, h.PlaySongAudio)
+	api.Get("/audio", viewer, h.GetAudioStatus)
+	api.Post("/audio/stop", operator, h.StopAudio)
+
+	// Synced lyrics (LRC)
+	api.Get("/songs/:id/timed-lyrics", viewer, h.GetTimedLyrics)
+	api.Get("/songs/:id/lrc", viewer, h.ExportSongLRC)
+	api.Put("/songs/:id/lrc", editor, h.ImportSongLRC)
+	api.Delete("/songs/:id/lrc", editor, h.DeleteTimedLyrics)
+	api.Get("/songs/:id/variants", viewer, h.GetLyricVariants)
+	api.Get("/songs/:id/variants/:variant", viewer, h.GetLyricVariant)
+	api.Put("/songs/:id/variants/:variant", editor, h.UpdateLyricVariant)
+	api.Delete("/songs/:id/variants/:variant", editor, h.DeleteLyricVariant)
+	api.Post("/songs/:id/transliterate", editor, h.TransliterateSong)
+
+	// Music ministry view (band tablets)
+	api.Get("/songs/:id/ministry", viewer, h.GetMinistryView)
+
+	// Lyrics formatting (preview, then save once confirmed)
+	api.Post("/lyrics/format", viewer, h.FormatLyrics)
+	api.Get("/songs/:id/format", viewer, h.GetSongFormatting)
+	api.Post("/songs/:id/format", editor, h.FormatSong)
+
+	// Display profiles and checking lyrics against them
+	api.Get("/display-profiles", viewer, h.GetDisplayProfiles)
+	api.Put("/display-profiles/:name", editor, h.SaveDisplayProfile)
+	api.Delete("/display-profiles/:name", adminOnly, h.DeleteDisplayProfile)
+	api.Post("/lyrics/display-check", viewer, h.CheckLyricsDisplay)
+	api.Get("/songs/:id/display-check", viewer, h.CheckSongDisplay)
+
+	// Slide segmentation preview
+	api.Post("/songs/:id/preview-slides", viewer, h.PreviewSlides)
+
+	// Import from other worship software
+	importGroup := api.Group("/import", editor)
+	importGroup.Post("/opensong", h.ImportOpenSong)
+	importGroup.Post("/easyworship", h.ImportEasyWorship)
+	importGroup.Post("/videopsalm", h.ImportVideoPsalm)
 
 	// Search
-	api.Get("/search", h.SearchSongs)
+	api.Get("/search", viewer, h.SearchSongs)
 
 	// Queue management
-	api.Get("/queue", h.GetQueue)
-	api.Post("/queue", h.AddToQueue)
-	api.Delete("/queue/:id", h.RemoveFromQueue)
-	api.Delete("/queue/song/:song_id", h.RemoveFromQueueBySong)
-	api.Put("/queue/reorder", h.ReorderQueue)
-	api.Post("/queue/clear", h.ClearQueue)
+	api.Get("/queue", viewer, h.GetQueue)
+	api.Post("/queue", operator, h.AddToQueue)
+	api.Delete("/queue/:id", operator, h.RemoveFromQueue)
+	api.Delete("/queue/song/:song_id", operator, h.RemoveFromQueueBySong)
+	api.Put("/queue/reorder", operator, h.ReorderQueue)
+	api.Post("/queue/clear", operator, h.ClearQueue)
+	api.Get("/queue/export", viewer, h.ExportQueue)
+
+	// Setlists
+	api.Get("/setlists", viewer, h.GetSetlists)
+	api.Post("/setlists", editor, h.CreateSetlist)
+	api.Get("/setlists/calendar.ics", viewer, h.GetSetlistCalendar)
+	api.Get("/setlists/:id", viewer, h.GetSetlist)
+	api.Put("/setlists/:id", editor, h.UpdateSetlist)
+	api.Delete("/setlists/:id", adminOnly, h.DeleteSetlist)
+	api.Get("/setlists/:id/export", viewer, h.ExportSetlist)
+	api.Get("/setlists/:id/export.pptx", viewer, h.ExportSetlistPPTX)
+
+	// Songbooks (numbered collections such as hymnals)
+	api.Get("/songbooks", viewer, h.GetSongbooks)
+	api.Post("/songbooks", editor, h.CreateSongbook)
+	api.Get("/songbooks/:id", viewer, h.GetSongbook)
+	api.Put("/songbooks/:id", editor, h.UpdateSongbook)
+	api.Delete("/songbooks/:id", adminOnly, h.DeleteSongbook)
+	api.Get("/songbooks/:id/songs", viewer, h.GetSongbookEntries)
+	api.Get("/songbooks/:id/songs/:number", viewer, h.GetSongbookPage)
+	api.Put("/songbooks/:id/songs/:number", editor, h.SetSongbookEntry)
+	api.Delete("/songbooks/:id/songs/:number", editor, h.DeleteSongbookEntry)
+	api.Get("/songbooks/:id/export", viewer, h.ExportSongbook)
+
+	// Scripture readings
+	api.Get("/scripture", viewer, h.GetScripture)
+	api.Post("/scripture/present", operator, h.PresentScripture)
+
+	// Services and post-service reports
+	api.Get("/services", viewer, h.GetServices)
+	api.Post("/services", operator, h.StartService)
+	api.Get("/services/active", viewer, h.GetActiveService)
+	api.Get("/services/:id", viewer, h.GetService)
+	api.Post("/services/:id/archive", operator, h.ArchiveService)
+	api.Get("/services/:id/report", viewer, h.GetServiceReport)
+
+	// Congregation song requests: public submission, admin inbox
+	api.Post("/requests", songRequestLimit.handle, h.SubmitSongRequest)
+	api.Get("/requests", viewer, h.GetOpenSongRequests)
+	api.Post("/requests/:id/vote", songVoteLimit.handle, h.VoteSongRequest)
+
+	// CCLI usage reporting
+	api.Get("/reports/ccli", viewer, h.GetCCLIReport)
 
 	// Admin
-	admin := api.Group("/admin")
+	admin := api.Group("/admin", h.RequireRole(models.RoleAdmin))
+	admin.Get("/users", h.GetUsers)
+	admin.Post("/users", h.CreateUser)
+	admin.Patch("/users/:id", h.UpdateUser)
+	admin.Post("/users/:id/reset-password", h.ResetUserPassword)
+	admin.Delete("/users/:id/sessions", h.DeleteUserSessions)
+	admin.Get("/sessions", h.GetSessions)
+	admin.Delete("/sessions/:id", h.DeleteSession)
+	admin.Get("/api-keys", h.GetAPIKeys)
+	admin.Delete("/api-keys/:id", h.DeleteAPIKey)
 	admin.Post("/reindex", h.ReindexAll)
+	admin.Get("/reindex/:id", h.GetReindexJob)
+	admin.Post("/consistency", h.CheckConsistency)
+	admin.Get("/consistency/:id", h.GetConsistencyJob)
+	admin.Post("/consistency/repair", h.RepairConsistency)
+	admin.Post("/index/cleanup", h.CleanupOrphans)
+	admin.Get("/index/cleanup/:id", h.GetOrphanCleanupJob)
+	admin.Get("/stats", h.GetAdminStats)
+	admin.Get("/migrations", h.GetMigrations)
+	admin.Put("/maintenance", h.UpdateMaintenance)
+	admin.Post("/config/reload", h.ReloadConfig)
+	admin.Get("/propresenter/health-check", h.GetProPresenterHealthCheck)
+	admin.Post("/propresenter/health-check/pause", h.PauseProPresenterHealthCheck)
+	admin.Post("/propresenter/health-check/resume", h.ResumeProPresenterHealthCheck)
+	admin.Post("/normalize", h.NormalizeLibrary)
+	admin.Get("/language-review", h.GetLanguageReview)
+	admin.Get("/displays", h.GetDisplays)
+	admin.Delete("/displays/:name", h.DeleteDisplay)
+	admin.Post("/songs/bulk-delete", h.BulkDeleteSongs)
+	admin.Get("/songs/archived", h.GetArchivedSongs)
+	admin.Get("/duplicates", h.GetDuplicates)
+	admin.Post("/songs/archive-stale", h.ArchiveStaleSongs)
 	admin.Get("/backups", h.GetBackups)
 	admin.Post("/backups", h.CreateBackup)
+	admin.Get("/backups/remote", h.GetRemoteBackups)
+	admin.Get("/backups/:name/verify", h.VerifyBackup)
+	admin.Get("/backups/:name/download", h.DownloadBackup)
+	admin.Get("/export/library", h.ExportLibrary)
+	admin.Get("/export-archive", h.ExportArchive)
+	admin.Post("/import-archive", h.ImportArchive)
+	admin.Post("/import", h.BulkImport)
+	admin.Post("/seed-demo", h.SeedDemo)
+	admin.Get("/requests", h.GetSongRequests)
+	admin.Post("/requests/:id/accept", h.AcceptSongRequest)
+	admin.Post("/requests/:id/reject", h.RejectSongRequest)
+	admin.Put("/requests/:id/status", h.UpdateSongRequestStatus)
+	admin.Post("/requests/:id/convert", h.ConvertSongRequest)
+	admin.Delete("/requests/:id", h.DeleteSongRequest)
+
+	// Usage analytics
+	analytics := admin.Group("/analytics")
+	analytics.Get("/songs-per-month", h.AnalyticsSongsPerMonth)
+	analytics.Get("/top-songs", h.AnalyticsTopSongs)
+	analytics.Get("/language-mix", h.AnalyticsLanguageMix)
+	analytics.Get("/set-length", h.AnalyticsSetLength)
 
 	// Settings
-	api.Get("/settings", h.GetSettings)
-	api.Put("/settings", h.UpdateSettings)
+	api.Get("/settings", viewer, h.GetSettings)
+	api.Put("/settings", adminOnly, h.UpdateSettings)
 
 	// ProPresenter integration
-	pp := api.Group("/propresenter")
+	pp := api.Group("/propresenter", operator)
 	pp.Get("/status", h.ProPresenterStatus)
+	pp.Get("/live", h.ProPresenterLive)
 	pp.Get("/library", h.ProPresenterLibrary)
 	pp.Get("/playlists", h.ProPresenterPlaylists)
+	pp.Get("/presentations/:uuid", h.ProPresenterPresentation)
+	pp.Get("/looks", h.ProPresenterLooks)
+	pp.Get("/media", h.ProPresenterMedia)
+	pp.Get("/audio", h.ProPresenterAudio)
 	pp.Post("/queue", h.ProPresenterSendToQueue)
 	pp.Post("/trigger", h.ProPresenterTrigger)
+	pp.Post("/trigger-group", h.ProPresenterTriggerGroup)
+	pp.Get("/queue-sync", h.GetQueueSync)
+	pp.Post("/queue-sync", h.SyncQueue)
+	pp.Get("/sync/report", h.GetLibrarySyncReport)
+	pp.Post("/sync", h.SyncLibrary)
+	pp.Post("/import", editor, h.ProPresenterImport)
 	pp.Post("/next", h.ProPresenterNextSlide)
 	pp.Post("/previous", h.ProPresenterPreviousSlide)
 	pp.Post("/clear", h.ProPresenterClear)
+	pp.Get("/dry-run", h.GetProPresenterDryRun)
+	pp.Put("/dry-run", h.SetProPresenterDryRun)
+
+	// Display registry (heartbeats from teleprompter and stage displays)
+	api.Post("/displays/register", h.RegisterDisplay)
+	api.Post("/displays/:name/heartbeat", h.DisplayHeartbeat)
+	api.Post("/displays/:id/command", operator, h.SendDisplayCommand)
+
+	// Live channel (displays), also over a WebSocket with the same events as
+	// /api/live/events
+	app.Get("/ws/live", h.Authenticate(), viewer, h.LiveSocket)
+	liveGroup := api.Group("/live", viewer)
+	liveGroup.Get("/events", h.LiveEvents)
+	liveGroup.Get("/state", h.LiveState)
+	liveGroup.Get("/current/tempo", h.GetCurrentTempo)
+	liveGroup.Get("/alerts", h.GetAlerts)
+	liveGroup.Post("/alert", operator, h.SendAlert)
+	liveGroup.Delete("/alert/:id", operator, h.DismissAlert)
+	liveGroup.Post("/blank", operator, h.Blank)
+	liveGroup.Post("/unblank", operator, h.Unblank)
+	liveGroup.Post("/panic", operator, h.Panic)
+	liveGroup.Get("/auto-advance", h.GetAutoAdvance)
+	liveGroup.Post("/auto-advance", operator, h.StartAutoAdvance)
+	liveGroup.Post("/auto-advance/pause", operator, h.PauseAutoAdvance)
+	liveGroup.Post("/auto-advance/resume", operator, h.ResumeAutoAdvance)
+	liveGroup.Delete("/auto-advance", operator, h.StopAutoAdvance)
+	liveGroup.Get("/rehearsal", h.GetRehearsalMode)
+	liveGroup.Put("/rehearsal", operator, h.SetRehearsalMode)
 
 	// Start server
 	log.Printf("Server starting on port %s", port)
 	log.Printf("Backup directory: %s", backupDir)
 	log.Printf("Database connected: %s", dbDSN)
-	if !disableTypesense {
+	if !disableTypesense && searchBackend == "meilisearch" {
+		log.Printf("Meilisearch host: %s", meilisearchHost)
+	} else if !disableTypesense && searchBackend == "embedded" {
+		log.Printf("Embedded search index: %s", dataDir)
+	} else if !disableTypesense {
 		log.Printf("Typesense host: %s", typesenseHost)
 	}
 
+	// Apply configuration changes on SIGHUP or from the admin endpoint
+	configReloader := &reloader{
+		current:          runtimeCfg,
+		db:               db,
+		backups:          backupManager,
+		propresenter:     ppClient,
+		cors:             corsHandler,
+		publicLimit:      publicLimit,
+		songRequestLimit: songRequestLimit,
+		songVoteLimit:    songVoteLimit,
+		apiLimit:         apiLimit,
+	}
+	h.SetConfigReloader(configReloader.Reload)
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			if _, err := configReloader.Reload(); err != nil {
+				log.Printf("⚠️  Configuration not reloaded: %v", err)
+			}
+		}
+	}()
+
+	// In-flight requests get a few seconds to finish
+	go func() {
+		<-ctx.Done()
+		log.Println("Shutting down...")
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			log.Printf("Error shutting down: %v", err)
+		}
+	}()
+
 	if err := app.Listen(":" + port); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+
+	// Edits to the embedded search index are saved in batches
+	if index, ok := ts.(*embedded.Index); ok {
+		if err := index.Flush(); err != nil {
+			log.Printf("Error saving the embedded search index: %v", err)
+		}
+	}
 }
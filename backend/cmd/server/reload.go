@@ -0,0 +1,294 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/joho/godotenv"
+	"github.com/yourusername/audience-stage-teleprompter/internal/backup"
+	"github.com/yourusername/audience-stage-teleprompter/internal/database"
+	"github.com/yourusername/audience-stage-teleprompter/internal/handlers"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+	"github.com/yourusername/audience-stage-teleprompter/internal/reqlog"
+	"github.com/yourusername/audience-stage-teleprompter/internal/ws"
+)
+
+// corsHeaders are the request headers browsers may send from other origins
+const corsHeaders = "Origin, Content-Type, Accept, Authorization, X-Operator, X-Lock-Token, X-Reviewer-Token, X-API-Key"
+
+// Variables set in the real environment win over .env, at startup and on
+// reload. Those that came from .env are remembered so removing one from the
+// file unsets it.
+var (
+	processEnv = make(map[string]bool)
+	fileEnv    = make(map[string]bool)
+)
+
+func init() {
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		processEnv[name] = true
+	}
+}
+
+// loadEnvFile sets the variables in .env that the real environment doesn't
+func loadEnvFile() error {
+	values, err := godotenv.Read()
+	if err != nil {
+		return err
+	}
+	for name := range fileEnv {
+		if _, ok := values[name]; !ok {
+			os.Unsetenv(name)
+			delete(fileEnv, name)
+		}
+	}
+	for name, value := range values {
+		if !processEnv[name] {
+			os.Setenv(name, value)
+			fileEnv[name] = true
+		}
+	}
+	return nil
+}
+
+// runtimeConfig is the configuration that can change without a restart
+type runtimeConfig struct {
+	LogLevel             string
+	CORSOrigins          string
+	BackupDailyHour      int
+	BackupEveryEdits     int
+	PublicAPIRateLimit   int
+	SongRequestRateLimit int
+	SongVoteRateLimit    int
+	APIReadRateLimit     int
+	APIWriteRateLimit    int
+	ProPresenterDryRun   bool
+}
+
+// loadRuntimeConfig reads the reloadable settings from the environment,
+// checking all of them
+func loadRuntimeConfig() (*runtimeConfig, error) {
+	cfg := &runtimeConfig{
+		LogLevel:           strings.ToLower(strings.TrimSpace(os.Getenv("LOG_LEVEL"))),
+		CORSOrigins:        strings.TrimSpace(os.Getenv("CORS_ORIGINS")),
+		ProPresenterDryRun: os.Getenv("PROPRESENTER_DRY_RUN") == "true",
+	}
+	if cfg.LogLevel == "" {
+		cfg.LogLevel = reqlog.Info
+	}
+	if !reqlog.ValidLevel(cfg.LogLevel) {
+		return nil, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, not %q", os.Getenv("LOG_LEVEL"))
+	}
+	if cfg.CORSOrigins == "" {
+		cfg.CORSOrigins = "*"
+	}
+	for _, origin := range strings.Split(cfg.CORSOrigins, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			return nil, fmt.Errorf("CORS_ORIGINS must be * or origins like https://example.org, not %q", origin)
+		}
+	}
+
+	var err error
+	if cfg.BackupDailyHour, err = envInt("BACKUP_DAILY_HOUR", backup.DefaultDailyHour, 0, 23); err != nil {
+		return nil, err
+	}
+	if cfg.BackupEveryEdits, err = envInt("BACKUP_EVERY_EDITS", 100, 1, 1000000); err != nil {
+		return nil, err
+	}
+	if cfg.PublicAPIRateLimit, err = envInt("PUBLIC_API_RATE_LIMIT", 60, 1, 1000000); err != nil {
+		return nil, err
+	}
+	if cfg.SongRequestRateLimit, err = envInt("SONG_REQUEST_RATE_LIMIT", 5, 1, 1000000); err != nil {
+		return nil, err
+	}
+	if cfg.SongVoteRateLimit, err = envInt("SONG_VOTE_RATE_LIMIT", 30, 1, 1000000); err != nil {
+		return nil, err
+	}
+	if cfg.APIReadRateLimit, err = envInt("API_READ_RATE_LIMIT", 600, 0, 1000000); err != nil {
+		return nil, err
+	}
+	if cfg.APIWriteRateLimit, err = envInt("API_WRITE_RATE_LIMIT", 120, 0, 1000000); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// envInt reads a whole number from the environment, def when unset
+func envInt(name string, def, min, max int) (int, error) {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%s must be a number from %d to %d, not %q", name, min, max, value)
+	}
+	return n, nil
+}
+
+// newCORS returns the CORS middleware for a comma-separated origin list
+func newCORS(origins string) fiber.Handler {
+	return cors.New(cors.Config{
+		AllowOrigins: origins,
+		AllowHeaders: corsHeaders,
+	})
+}
+
+// proPresenterConfig is the ProPresenter connection from the settings, or
+// from PROPRESENTER_* when the settings have none; nil when it is off
+func proPresenterConfig(settings *models.Settings) *propresenter.Config {
+	if settings != nil && settings.ProPresenterHost != "" && settings.ProPresenterPort > 0 {
+		return &propresenter.Config{
+			Host:       settings.ProPresenterHost,
+			Port:       strconv.Itoa(settings.ProPresenterPort),
+			Enabled:    true,
+			PlaylistID: settings.ProPresenterPlaylist,
+		}
+	}
+	if os.Getenv("PROPRESENTER_ENABLED") == "true" && os.Getenv("PROPRESENTER_HOST") != "" {
+		port := os.Getenv("PROPRESENTER_PORT")
+		if port == "" {
+			port = "4031"
+		}
+		return &propresenter.Config{
+			Host:       os.Getenv("PROPRESENTER_HOST"),
+			Port:       port,
+			Enabled:    true,
+			PlaylistID: os.Getenv("PROPRESENTER_PLAYLIST"),
+		}
+	}
+	return nil
+}
+
+// swapHandler is middleware a reload can replace. Requests already running
+// finish with the handler they started with.
+type swapHandler struct {
+	current atomic.Value // fiber.Handler
+}
+
+func newSwapHandler(handler fiber.Handler) *swapHandler {
+	s := &swapHandler{}
+	s.current.Store(handler)
+	return s
+}
+
+func (s *swapHandler) set(handler fiber.Handler) {
+	s.current.Store(handler)
+}
+
+func (s *swapHandler) handle(c *fiber.Ctx) error {
+	return s.current.Load().(fiber.Handler)(c)
+}
+
+// reloader applies configuration changes to the running server. Live
+// display connections, sessions and jobs are not touched.
+type reloader struct {
+	mu      sync.Mutex // one reload at a time
+	current *runtimeConfig
+
+	db               *database.DB
+	backups          *backup.Manager
+	propresenter     *propresenter.Client
+	cors             *swapHandler
+	publicLimit      *swapHandler
+	songRequestLimit *swapHandler
+	songVoteLimit    *swapHandler
+	apiLimit         *swapHandler
+}
+
+// Reload reads .env and the settings again and applies what changed. Every
+// value is checked first, so a mistake leaves the running configuration as
+// it was.
+func (r *reloader) Reload() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := loadEnvFile(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: error reading .env: %v", handlers.ErrInvalidConfig, err)
+	}
+	cfg, err := loadRuntimeConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", handlers.ErrInvalidConfig, err)
+	}
+	settings, err := r.db.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	old := r.current
+	changed := make([]string, 0)
+	if cfg.LogLevel != old.LogLevel {
+		reqlog.SetLevel(cfg.LogLevel)
+		changed = append(changed, "LOG_LEVEL")
+	}
+	if cfg.CORSOrigins != old.CORSOrigins {
+		r.cors.set(newCORS(cfg.CORSOrigins))
+		ws.SetAllowedOrigins(cfg.CORSOrigins)
+		changed = append(changed, "CORS_ORIGINS")
+	}
+	if cfg.BackupDailyHour != old.BackupDailyHour {
+		changed = append(changed, "BACKUP_DAILY_HOUR")
+	}
+	if cfg.BackupEveryEdits != old.BackupEveryEdits {
+		changed = append(changed, "BACKUP_EVERY_EDITS")
+	}
+	if cfg.BackupDailyHour != old.BackupDailyHour || cfg.BackupEveryEdits != old.BackupEveryEdits {
+		r.backups.SetSchedule(cfg.BackupDailyHour, cfg.BackupEveryEdits)
+	}
+	// A new limit starts counting afresh
+	if cfg.PublicAPIRateLimit != old.PublicAPIRateLimit {
+		r.publicLimit.set(handlers.PublicAPILimit(cfg.PublicAPIRateLimit))
+		changed = append(changed, "PUBLIC_API_RATE_LIMIT")
+	}
+	if cfg.SongRequestRateLimit != old.SongRequestRateLimit {
+		r.songRequestLimit.set(handlers.SongRequestLimit(cfg.SongRequestRateLimit))
+		changed = append(changed, "SONG_REQUEST_RATE_LIMIT")
+	}
+	if cfg.SongVoteRateLimit != old.SongVoteRateLimit {
+		r.songVoteLimit.set(handlers.SongRequestLimit(cfg.SongVoteRateLimit))
+		changed = append(changed, "SONG_VOTE_RATE_LIMIT")
+	}
+	if cfg.APIReadRateLimit != old.APIReadRateLimit || cfg.APIWriteRateLimit != old.APIWriteRateLimit {
+		r.apiLimit.set(handlers.APILimit(cfg.APIReadRateLimit, cfg.APIWriteRateLimit))
+		if cfg.APIReadRateLimit != old.APIReadRateLimit {
+			changed = append(changed, "API_READ_RATE_LIMIT")
+		}
+		if cfg.APIWriteRateLimit != old.APIWriteRateLimit {
+			changed = append(changed, "API_WRITE_RATE_LIMIT")
+		}
+	}
+	// Only a change in .env, so dry-run mode switched from the API stays as it is
+	if cfg.ProPresenterDryRun != old.ProPresenterDryRun {
+		r.propresenter.SetDryRun(cfg.ProPresenterDryRun)
+		changed = append(changed, "PROPRESENTER_DRY_RUN")
+	}
+
+	want, have := proPresenterConfig(settings), r.propresenter.CurrentConfig()
+	if (want == nil) != (have == nil) || (want != nil && *want != *have) {
+		if err := r.propresenter.Reconfigure(want); err != nil {
+			log.Printf("Warning: Failed to reconfigure ProPresenter: %v", err)
+		}
+		changed = append(changed, "propresenter")
+	}
+	r.propresenter.SetHealthCheckInterval(handlers.HealthCheckInterval(settings))
+
+	r.current = cfg
+	if len(changed) == 0 {
+		log.Println("🔄 Configuration reloaded, nothing changed")
+	} else {
+		log.Printf("🔄 Configuration reloaded: %s", strings.Join(changed, ", "))
+	}
+	return changed, nil
+}
@@ -0,0 +1,264 @@
+// Package advance moves through a presentation's slides on a timer, for
+// announcement loops and pre-service lyrics.
+package advance
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Run states
+const (
+	StateRunning  = "running"
+	StatePaused   = "paused"
+	StateFinished = "finished"
+	StateFailed   = "failed"
+)
+
+// Plan describes what to step through. Durations has one entry per slide.
+type Plan struct {
+	SongID    string
+	Title     string
+	Durations []time.Duration
+	Loop      bool // go back to the first slide after the last
+	Start     int  // slide to start on
+}
+
+// Status is a point-in-time copy of the current run
+type Status struct {
+	SongID     string     `json:"song_id"`
+	Title      string     `json:"title"`
+	State      string     `json:"state"`
+	SlideIndex int        `json:"slide_index"`
+	Slides     int        `json:"slides"`
+	Loop       bool       `json:"loop"`
+	NextAt     *time.Time `json:"next_at,omitempty"`   // when running
+	Remaining  float64    `json:"remaining,omitempty"` // seconds left on the slide when paused
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+}
+
+// StepFunc shows slide next of the run. wrapped is true when a looping run
+// goes from the last slide back to the first.
+type StepFunc func(songID string, next int, wrapped bool) error
+
+// Engine runs at most one timed presentation at a time. Starting a new run
+// replaces the old one.
+type Engine struct {
+	mu        sync.Mutex
+	step      StepFunc
+	onChange  func(Status)
+	plan      Plan
+	status    *Status
+	timer     *time.Timer
+	remaining time.Duration
+	gen       int // invalidates timers from earlier runs
+}
+
+// NewEngine creates an engine that calls step to change slides and onChange
+// (if set) whenever the run's state changes
+func NewEngine(step StepFunc, onChange func(Status)) *Engine {
+	return &Engine{step: step, onChange: onChange}
+}
+
+// Start begins a run, replacing any current one
+func (e *Engine) Start(plan Plan) (Status, error) {
+	if len(plan.Durations) == 0 {
+		return Status{}, fmt.Errorf("no slides to advance through")
+	}
+	if plan.Start < 0 || plan.Start >= len(plan.Durations) {
+		plan.Start = 0
+	}
+
+	e.mu.Lock()
+	e.stopTimerLocked()
+	e.plan = plan
+	e.status = &Status{
+		SongID:     plan.SongID,
+		Title:      plan.Title,
+		State:      StateRunning,
+		SlideIndex: plan.Start,
+		Slides:     len(plan.Durations),
+		Loop:       plan.Loop,
+		StartedAt:  time.Now(),
+	}
+	e.scheduleLocked(plan.Durations[plan.Start])
+	status := e.snapshotLocked()
+	e.mu.Unlock()
+
+	e.changed(status)
+	return status, nil
+}
+
+// Pause holds the current slide, keeping the time left on it
+func (e *Engine) Pause() (Status, error) {
+	e.mu.Lock()
+	if e.status == nil || e.status.State != StateRunning {
+		e.mu.Unlock()
+		return Status{}, fmt.Errorf("auto-advance is not running")
+	}
+	e.remaining = time.Until(*e.status.NextAt)
+	if e.remaining < 0 {
+		e.remaining = 0
+	}
+	e.stopTimerLocked()
+	e.status.State = StatePaused
+	e.status.NextAt = nil
+	status := e.snapshotLocked()
+	e.mu.Unlock()
+
+	e.changed(status)
+	return status, nil
+}
+
+// Resume continues a paused run with the time that was left on its slide
+func (e *Engine) Resume() (Status, error) {
+	e.mu.Lock()
+	if e.status == nil || e.status.State != StatePaused {
+		e.mu.Unlock()
+		return Status{}, fmt.Errorf("auto-advance is not paused")
+	}
+	e.status.State = StateRunning
+	e.scheduleLocked(e.remaining)
+	status := e.snapshotLocked()
+	e.mu.Unlock()
+
+	e.changed(status)
+	return status, nil
+}
+
+// Stop ends the current run. It reports whether one was active.
+func (e *Engine) Stop() bool {
+	e.mu.Lock()
+	if e.status == nil || (e.status.State != StateRunning && e.status.State != StatePaused) {
+		e.mu.Unlock()
+		return false
+	}
+	e.stopTimerLocked()
+	e.status.State = StateFinished
+	e.status.NextAt = nil
+	status := e.snapshotLocked()
+	e.mu.Unlock()
+
+	e.changed(status)
+	return true
+}
+
+// Moved tells the engine the operator changed slides by hand, so the timer
+// restarts for the slide now showing
+func (e *Engine) Moved(delta int) {
+	e.mu.Lock()
+	if e.status == nil || (e.status.State != StateRunning && e.status.State != StatePaused) {
+		e.mu.Unlock()
+		return
+	}
+	index := e.status.SlideIndex + delta
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(e.plan.Durations) {
+		index = len(e.plan.Durations) - 1
+	}
+	e.status.SlideIndex = index
+	if e.status.State == StateRunning {
+		e.stopTimerLocked()
+		e.scheduleLocked(e.plan.Durations[index])
+	} else {
+		e.remaining = e.plan.Durations[index]
+	}
+	status := e.snapshotLocked()
+	e.mu.Unlock()
+
+	e.changed(status)
+}
+
+// Status returns the current or last run, or nil if nothing has run
+func (e *Engine) Status() *Status {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.status == nil {
+		return nil
+	}
+	status := e.snapshotLocked()
+	return &status
+}
+
+func (e *Engine) scheduleLocked(d time.Duration) {
+	e.gen++
+	gen := e.gen
+	next := time.Now().Add(d)
+	e.status.NextAt = &next
+	e.timer = time.AfterFunc(d, func() { e.fire(gen) })
+}
+
+func (e *Engine) stopTimerLocked() {
+	e.gen++
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+}
+
+// fire advances one slide, then schedules the next or finishes the run
+func (e *Engine) fire(gen int) {
+	e.mu.Lock()
+	if gen != e.gen || e.status == nil || e.status.State != StateRunning {
+		e.mu.Unlock()
+		return
+	}
+
+	next := e.status.SlideIndex + 1
+	wrapped := false
+	if next >= len(e.plan.Durations) {
+		if !e.plan.Loop {
+			e.status.State = StateFinished
+			e.status.NextAt = nil
+			status := e.snapshotLocked()
+			e.mu.Unlock()
+			e.changed(status)
+			return
+		}
+		next, wrapped = 0, true
+	}
+	songID := e.plan.SongID
+	e.mu.Unlock()
+
+	// The step talks to ProPresenter, so it runs without the lock
+	err := e.step(songID, next, wrapped)
+
+	e.mu.Lock()
+	if gen != e.gen {
+		// Paused, stopped or replaced while stepping
+		e.mu.Unlock()
+		return
+	}
+	if err != nil {
+		log.Printf("Auto-advance stopped: %v", err)
+		e.status.State = StateFailed
+		e.status.Error = err.Error()
+		e.status.NextAt = nil
+	} else {
+		e.status.SlideIndex = next
+		e.scheduleLocked(e.plan.Durations[next])
+	}
+	status := e.snapshotLocked()
+	e.mu.Unlock()
+
+	e.changed(status)
+}
+
+func (e *Engine) snapshotLocked() Status {
+	status := *e.status
+	if status.State == StatePaused {
+		status.Remaining = e.remaining.Seconds()
+	}
+	return status
+}
+
+func (e *Engine) changed(status Status) {
+	if e.onChange != nil {
+		e.onChange(status)
+	}
+}
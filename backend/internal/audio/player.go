@@ -0,0 +1,174 @@
+// Package audio plays songs' linked recordings on the server's own audio
+// output, for run-throughs with the original track.
+package audio
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCommand plays a file or URL without a window and exits at the end
+const DefaultCommand = "ffplay -nodisp -autoexit -loglevel quiet"
+
+// Outputs a track can be played on
+const (
+	OutputLocal        = "local"
+	OutputProPresenter = "propresenter"
+)
+
+// Extensions lists the audio file types that can be uploaded
+var Extensions = []string{".mp3", ".m4a", ".aac", ".wav", ".ogg", ".flac"}
+
+// Status describes what the player is doing
+type Status struct {
+	Playing   bool       `json:"playing"`
+	SongID    string     `json:"song_id,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	Source    string     `json:"source,omitempty"`
+	Output    string     `json:"output,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Error     string     `json:"error,omitempty"` // why the last track stopped early
+}
+
+// Player stores uploaded tracks under dir and plays one track at a time by
+// running command with the file path or URL as its last argument
+type Player struct {
+	dir     string
+	command []string
+	output  string // default output for playback requests
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	status Status
+	gen    int // ignores exits of processes that were replaced
+}
+
+// New creates a player. An empty command uses DefaultCommand; output is where
+// tracks play when a request doesn't say.
+func New(dir, command, output string) *Player {
+	if strings.TrimSpace(command) == "" {
+		command = DefaultCommand
+	}
+	if output == "" {
+		output = OutputLocal
+	}
+	return &Player{dir: dir, command: strings.Fields(command), output: output}
+}
+
+// DefaultOutput is where tracks play unless a request chooses
+func (p *Player) DefaultOutput() string {
+	return p.output
+}
+
+// Path returns where a stored track file lives
+func (p *Player) Path(name string) string {
+	return filepath.Join(p.dir, filepath.Base(name))
+}
+
+// Save writes an uploaded track, replacing any file of the same name
+func (p *Player) Save(name string, data []byte) error {
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		return fmt.Errorf("error creating audio directory: %w", err)
+	}
+	if err := os.WriteFile(p.Path(name), data, 0644); err != nil {
+		return fmt.Errorf("error saving audio file: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes a stored track file; a missing file is not an error
+func (p *Player) Remove(name string) error {
+	if err := os.Remove(p.Path(name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing audio file: %w", err)
+	}
+	return nil
+}
+
+// Play starts source (a stored file path or URL), stopping whatever was playing
+func (p *Player) Play(songID, title, source string) (Status, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopLocked()
+
+	args := append(append([]string{}, p.command[1:]...), source)
+	cmd := exec.Command(p.command[0], args...)
+	if err := cmd.Start(); err != nil {
+		return p.status, fmt.Errorf("failed to start audio player: %w", err)
+	}
+
+	now := time.Now()
+	p.gen++
+	p.cmd = cmd
+	p.status = Status{Playing: true, SongID: songID, Title: title, Source: source, Output: OutputLocal, StartedAt: &now}
+
+	gen := p.gen
+	go p.wait(cmd, gen)
+	return p.status, nil
+}
+
+// PlayingElsewhere records a track started on another output (such as
+// ProPresenter's audio layer), stopping local playback
+func (p *Player) PlayingElsewhere(songID, title, source, output string) Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopLocked()
+	now := time.Now()
+	p.status = Status{Playing: true, SongID: songID, Title: title, Source: source, Output: output, StartedAt: &now}
+	return p.status
+}
+
+// Stop ends local playback and forgets playback elsewhere. It returns what was
+// playing, if anything, so the caller can stop other outputs.
+func (p *Player) Stop() (Status, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stopped := p.status
+	p.stopLocked()
+	p.status = Status{}
+	return stopped, stopped.Playing
+}
+
+// Status returns what is playing
+func (p *Player) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+func (p *Player) stopLocked() {
+	if p.cmd == nil {
+		return
+	}
+	p.gen++
+	if err := p.cmd.Process.Kill(); err != nil {
+		log.Printf("Error stopping audio player: %v", err)
+	}
+	p.cmd = nil
+	p.status = Status{}
+}
+
+// wait clears the status when the track ends on its own
+func (p *Player) wait(cmd *exec.Cmd, gen int) {
+	err := cmd.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if gen != p.gen {
+		return
+	}
+	p.cmd = nil
+	p.status = Status{}
+	if err != nil {
+		log.Printf("Audio player exited: %v", err)
+		p.status.Error = err.Error()
+	}
+}
@@ -0,0 +1,141 @@
+// Package auth hashes passwords and issues session tokens for signing in.
+// Passwords are stored as PBKDF2-SHA256 hashes; sessions are random tokens
+// of which only a SHA-256 is kept in the database.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MinPasswordLength is the shortest password accepted
+const MinPasswordLength = 8
+
+// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256
+const pbkdf2Iterations = 600000
+
+const hashPrefix = "pbkdf2-sha256"
+
+// HashPassword returns a salted hash of password, written as
+// "pbkdf2-sha256$iterations$salt$hash"
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := pbkdf2([]byte(password), salt, pbkdf2Iterations, sha256.Size)
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("%s$%d$%s$%s", hashPrefix, pbkdf2Iterations, enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// CheckPassword reports whether password matches a hash from HashPassword
+func CheckPassword(hash, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != hashPrefix {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := enc.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	got := pbkdf2([]byte(password), salt, iterations, len(want))
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// pbkdf2 derives a key as in RFC 8018 with HMAC-SHA256
+func pbkdf2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	blocks := (keyLen + sha256.Size - 1) / sha256.Size
+	key := make([]byte, 0, blocks*sha256.Size)
+
+	var counter [4]byte
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
+
+// passwordAlphabet leaves out characters that are easy to misread when a
+// temporary password is read out or copied from a screen
+const passwordAlphabet = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// TemporaryPassword returns a random password for an account whose user
+// must choose their own at the next sign-in
+func TemporaryPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = passwordAlphabet[int(b[i])%len(passwordAlphabet)]
+	}
+	return string(b), nil
+}
+
+// NewSessionToken returns a random session token and the hash to store
+func NewSessionToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// APIKeyPrefix starts every API key, telling it apart from a session token
+const APIKeyPrefix = "ast_"
+
+// NewAPIKey returns a random API key and the hash to store
+func NewAPIKey() (key, hash string, err error) {
+	token, _, err := NewSessionToken()
+	if err != nil {
+		return "", "", err
+	}
+	key = APIKeyPrefix + token
+	return key, HashToken(key), nil
+}
+
+// KeyPrefix is the start of an API key, kept to recognise it by in lists
+func KeyPrefix(key string) string {
+	if len(key) <= len(APIKeyPrefix)+6 {
+		return key
+	}
+	return key[:len(APIKeyPrefix)+6]
+}
+
+// HashToken returns the stored form of a session token or API key
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
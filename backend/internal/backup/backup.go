@@ -1,8 +1,11 @@
 package backup
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
@@ -11,12 +14,49 @@ import (
 	"time"
 )
 
+// Edit-threshold backups wait until edits pause for backupQuietPeriod, so a
+// burst of saves (an import, a rehearsal clean-up) produces one backup. Under
+// continuous editing the backup still runs backupMaxDelay after it became due.
+const (
+	backupQuietPeriod = 30 * time.Second
+	backupMaxDelay    = 5 * time.Minute
+)
+
+// DefaultDailyHour is the hour of the day the daily backup runs unless set
+const DefaultDailyHour = 2
+
+// Dumper writes a database out as SQL and reads it back, for databases
+// pg_dump can't reach: the SQLite file of the all-in-one mode
+type Dumper interface {
+	DumpSQL(w io.Writer) error
+	LoadSQL(r io.Reader) error
+}
+
 type Manager struct {
 	dbDSN          string
+	dumper         Dumper // used instead of pg_dump and psql when set
 	backupDir      string
-	lastEditCount  int
 	editsThreshold int
-	mu             sync.Mutex
+
+	mu           sync.Mutex // guards the schedule, edit trigger and outcomes below
+	dailyHour    int
+	reschedule   chan struct{}
+	pendingEdits int
+	dueSince     time.Time
+	timer        *time.Timer
+	lastSuccess  time.Time
+	lastFailure  time.Time
+	lastError    string
+
+	targets        []Target        // remote copies, see AddTarget
+	remote         []*TargetStatus // outcome per target, same order
+	remoteKeepDays int
+	uploads        sync.WaitGroup
+
+	compress      bool   // gzip new backups
+	encryptionKey string // encrypt new backups and decrypt restores, see SetFormat
+
+	dumpMu sync.Mutex // serializes dumps and restores
 }
 
 func NewManager(dbDSN, backupDir string, editsThreshold int) *Manager {
@@ -24,73 +64,247 @@ func NewManager(dbDSN, backupDir string, editsThreshold int) *Manager {
 		dbDSN:          dbDSN,
 		backupDir:      backupDir,
 		editsThreshold: editsThreshold,
-		lastEditCount:  0,
+		dailyHour:      DefaultDailyHour,
+		reschedule:     make(chan struct{}, 1),
+		remoteKeepDays: DefaultRemoteKeepDays,
+		compress:       true,
 	}
 }
 
+// SetDumper makes backups and restores go through d instead of pg_dump and
+// psql. Call it before Start.
+func (m *Manager) SetDumper(d Dumper) {
+	m.dumper = d
+}
+
+// SetFormat chooses whether new backups are gzipped and, with a non-empty
+// key, encrypted with it. The key is also needed to restore encrypted
+// backups: 64 hex digits are used as the AES-256 key itself, anything else as
+// a passphrase.
+func (m *Manager) SetFormat(compress bool, encryptionKey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.compress = compress
+	m.encryptionKey = encryptionKey
+}
+
+// format returns the current compression setting and encryption key
+func (m *Manager) format() (bool, string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.compress, m.encryptionKey
+}
+
 // Start begins the backup scheduler
 func (m *Manager) Start() {
-	// Daily backup at 2 AM
 	go m.scheduleDailyBackup()
 	log.Println("Backup manager started")
 }
 
-// scheduleDailyBackup runs daily backups
+// SetSchedule changes the hour of the daily backup and how many edits
+// trigger one. The daily backup is rescheduled right away.
+func (m *Manager) SetSchedule(dailyHour, editsThreshold int) {
+	m.mu.Lock()
+	m.dailyHour = dailyHour
+	m.editsThreshold = editsThreshold
+	m.mu.Unlock()
+
+	select {
+	case m.reschedule <- struct{}{}:
+	default:
+	}
+}
+
+// scheduleDailyBackup runs daily backups at the configured hour
 func (m *Manager) scheduleDailyBackup() {
+	// A schedule set before Start is already taken into account
+	select {
+	case <-m.reschedule:
+	default:
+	}
+
 	for {
+		m.mu.Lock()
+		hour := m.dailyHour
+		m.mu.Unlock()
+
 		now := time.Now()
-		next := time.Date(now.Year(), now.Month(), now.Day()+1, 2, 0, 0, 0, now.Location())
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
 		duration := next.Sub(now)
 
-		log.Printf("Next scheduled backup in %v", duration)
-		time.Sleep(duration)
-
-		if err := m.CreateBackup("daily"); err != nil {
-			log.Printf("Error creating daily backup: %v", err)
+		log.Printf("Next scheduled backup in %v", duration.Round(time.Second))
+		timer := time.NewTimer(duration)
+		select {
+		case <-timer.C:
+			if err := m.CreateBackup("daily"); err != nil {
+				log.Printf("Error creating daily backup: %v", err)
+			}
+		case <-m.reschedule:
+			timer.Stop()
 		}
 	}
 }
 
-// CheckEditThreshold checks if we need to backup based on edit count
-func (m *Manager) CheckEditThreshold(currentEditCount int) error {
+// RecordEdits counts song edits and, once editsThreshold have accumulated,
+// schedules a debounced backup. It never blocks on a running backup.
+func (m *Manager) RecordEdits(n int) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if currentEditCount-m.lastEditCount >= m.editsThreshold {
-		if err := m.CreateBackup("edit-threshold"); err != nil {
-			return err
-		}
-		m.lastEditCount = currentEditCount
+	m.pendingEdits += n
+	if m.pendingEdits < m.editsThreshold {
+		return
 	}
 
+	now := time.Now()
+	if m.dueSince.IsZero() {
+		m.dueSince = now
+	}
+	delay := backupQuietPeriod
+	if remaining := m.dueSince.Add(backupMaxDelay).Sub(now); remaining < delay {
+		delay = remaining
+	}
+	if delay < 0 {
+		delay = 0
+	}
+
+	if m.timer == nil {
+		m.timer = time.AfterFunc(delay, m.runEditBackup)
+	} else {
+		m.timer.Reset(delay)
+	}
+}
+
+// runEditBackup takes the pending edits and backs them up. A failed backup
+// puts them back so the next edit schedules another attempt.
+func (m *Manager) runEditBackup() {
+	m.mu.Lock()
+	if m.pendingEdits < m.editsThreshold {
+		m.mu.Unlock()
+		return
+	}
+	edits := m.pendingEdits
+	m.pendingEdits = 0
+	m.dueSince = time.Time{}
+	m.timer = nil
+	m.mu.Unlock()
+
+	if err := m.CreateBackup("edit-threshold"); err != nil {
+		log.Printf("Error creating edit-threshold backup: %v", err)
+		m.mu.Lock()
+		m.pendingEdits += edits
+		m.mu.Unlock()
+	}
+}
+
+// CheckReady verifies backups can be written: the backup directory is
+// writable and, unless a Dumper is set, pg_dump is installed
+func (m *Manager) CheckReady() error {
+	if err := os.MkdirAll(m.backupDir, 0755); err != nil {
+		return fmt.Errorf("backup directory not usable: %w", err)
+	}
+	f, err := os.CreateTemp(m.backupDir, ".ready-*")
+	if err != nil {
+		return fmt.Errorf("backup directory not writable: %w", err)
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	if m.dumper != nil {
+		return nil
+	}
+	if _, err := exec.LookPath("pg_dump"); err != nil {
+		return fmt.Errorf("pg_dump not found: %w", err)
+	}
 	return nil
 }
 
-// CreateBackup creates a PostgreSQL dump
+// Status is the backup manager's recent history, for dashboards
+type Status struct {
+	PendingEdits  int            `json:"pending_edits"`
+	LastSuccessAt *time.Time     `json:"last_success_at,omitempty"`
+	LastFailureAt *time.Time     `json:"last_failure_at,omitempty"`
+	LastError     string         `json:"last_error,omitempty"`
+	Remote        []TargetStatus `json:"remote,omitempty"`
+}
+
+// Status reports pending edits and the outcome of the latest backups made
+// since the server started
+func (m *Manager) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := Status{PendingEdits: m.pendingEdits, LastError: m.lastError, Remote: m.remoteStatus()}
+	if !m.lastSuccess.IsZero() {
+		t := m.lastSuccess
+		status.LastSuccessAt = &t
+	}
+	if !m.lastFailure.IsZero() {
+		t := m.lastFailure
+		status.LastFailureAt = &t
+	}
+	return status
+}
+
+// CreateBackup creates a database dump, records the outcome and starts
+// copying it to the remote targets
 func (m *Manager) CreateBackup(backupType string) error {
+	path, err := m.createBackup(backupType)
+	if err == nil {
+		m.upload(path)
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if err != nil {
+		m.lastFailure, m.lastError = time.Now(), err.Error()
+	} else {
+		m.lastSuccess = time.Now()
+	}
+	return err
+}
+
+func (m *Manager) createBackup(backupType string) (string, error) {
+	m.dumpMu.Lock()
+	defer m.dumpMu.Unlock()
 
 	// Create backup directory if it doesn't exist
 	if err := os.MkdirAll(m.backupDir, 0755); err != nil {
-		return fmt.Errorf("error creating backup directory: %w", err)
+		return "", fmt.Errorf("error creating backup directory: %w", err)
 	}
 
+	compress, key := m.format()
+	compression, encryption := CompressionNone, EncryptionNone
 	timestamp := time.Now().Format("2006-01-02_15-04-05")
 	filename := fmt.Sprintf("backup_%s_%s.sql", backupType, timestamp)
+	if compress {
+		compression = CompressionGzip
+		filename += ".gz"
+	}
+	if key != "" {
+		encryption = EncryptionAES
+		filename += ".enc"
+	}
 	filePath := filepath.Join(m.backupDir, filename)
 
-	// Execute pg_dump
-	cmd := exec.Command("pg_dump", m.dbDSN, "-f", filePath)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("pg_dump failed: %w, output: %s", err, string(output))
+	if err := m.dumpTo(filePath, compress, key); err != nil {
+		os.Remove(filePath)
+		return "", err
 	}
 
 	// Get file size
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
-		return fmt.Errorf("error getting backup file info: %w", err)
+		return "", fmt.Errorf("error getting backup file info: %w", err)
+	}
+
+	// Recorded so a file corrupted on disk is caught before a restore
+	checksum, err := fileChecksum(filePath)
+	if err != nil {
+		return "", fmt.Errorf("error computing backup checksum: %w", err)
 	}
 
 	log.Printf("Backup created: %s (%.2f MB)", filename, float64(fileInfo.Size())/(1024*1024))
@@ -101,6 +315,9 @@ func (m *Manager) CreateBackup(backupType string) error {
 		"timestamp":   timestamp,
 		"size_bytes":  fileInfo.Size(),
 		"filename":    filename,
+		"sha256":      checksum,
+		"compression": compression,
+		"encryption":  encryption,
 	}
 
 	metadataFilename := fmt.Sprintf("backup_%s_%s.json", backupType, timestamp)
@@ -108,16 +325,69 @@ func (m *Manager) CreateBackup(backupType string) error {
 
 	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
 	if err != nil {
-		return fmt.Errorf("error creating metadata: %w", err)
+		return "", fmt.Errorf("error creating metadata: %w", err)
 	}
 
 	if err := os.WriteFile(metadataPath, metadataJSON, 0644); err != nil {
-		return fmt.Errorf("error writing metadata: %w", err)
+		return "", fmt.Errorf("error writing metadata: %w", err)
 	}
 
 	// Clean old backups (keep last 7 days)
 	m.cleanOldBackups(7)
 
+	return filePath, nil
+}
+
+// dumpTo dumps the database into path with pg_dump or the Dumper, gzipping
+// and encrypting the output on the way
+func (m *Manager) dumpTo(path string, compress bool, key string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating backup file: %w", err)
+	}
+	defer f.Close()
+
+	var out io.Writer = f
+	var enc *encryptWriter
+	if key != "" {
+		if enc, err = newEncryptWriter(f, key); err != nil {
+			return fmt.Errorf("error encrypting backup: %w", err)
+		}
+		out = enc
+	}
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(out)
+		out = gz
+	}
+
+	if m.dumper != nil {
+		if err := m.dumper.DumpSQL(out); err != nil {
+			return fmt.Errorf("dump failed: %w", err)
+		}
+	} else {
+		var stderr bytes.Buffer
+		cmd := exec.Command("pg_dump", m.dbDSN)
+		cmd.Stdout = out
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("pg_dump failed: %w, output: %s", err, stderr.String())
+		}
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("error compressing backup: %w", err)
+		}
+	}
+	if enc != nil {
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("error encrypting backup: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing backup file: %w", err)
+	}
 	return nil
 }
 
@@ -187,3 +457,72 @@ func (m *Manager) ListBackups() ([]map[string]interface{}, error) {
 
 	return backups, nil
 }
+
+// Restore loads a backup into the database with psql, or the Dumper when
+// set, in a single transaction that stops at the first error. name is a backup file name in
+// the backup directory or a path to one. The database should be empty: the
+// dump recreates its tables. With verify, a backup whose checksum doesn't
+// match its metadata is refused before anything is loaded.
+func (m *Manager) Restore(name string, verify bool) error {
+	path := name
+	if _, err := os.Stat(path); err != nil {
+		path = filepath.Join(m.backupDir, filepath.Base(name))
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("backup %s not found", name)
+	}
+
+	if verify {
+		v, err := verifyFile(path)
+		if err != nil {
+			return err
+		}
+		if !v.Verified {
+			log.Printf("⚠️  Backup %s has no recorded checksum, restoring it unverified", v.File)
+		}
+	}
+
+	_, key := m.format()
+	compression, encryption := readFormat(path)
+	// psql commits whatever it was sent when its input ends, so a backup
+	// that fails to decrypt or decompress halfway must be caught first
+	if compression != CompressionNone || encryption != EncryptionNone {
+		if err := checkReadable(path, compression, encryption, key); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error reading backup: %w", err)
+	}
+	defer f.Close()
+	sql, release, err := sqlReader(f, compression, encryption, key)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	m.dumpMu.Lock()
+	defer m.dumpMu.Unlock()
+
+	if m.dumper != nil {
+		if err := m.dumper.LoadSQL(sql); err != nil {
+			return err
+		}
+		log.Printf("Backup restored: %s", filepath.Base(path))
+		return nil
+	}
+
+	// The SQL is streamed to psql, so compressed and encrypted backups are
+	// never written out in the clear
+	cmd := exec.Command("psql", m.dbDSN, "-v", "ON_ERROR_STOP=1", "--single-transaction", "-q", "-f", "-")
+	cmd.Stdin = sql
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("psql failed: %w, output: %s", err, string(output))
+	}
+
+	log.Printf("Backup restored: %s", filepath.Base(path))
+	return nil
+}
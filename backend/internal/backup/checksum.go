@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrChecksumMismatch means a backup file no longer matches the checksum
+// recorded in its metadata when it was made
+var ErrChecksumMismatch = errors.New("backup checksum mismatch")
+
+// Verification is the result of checking a backup against its metadata
+type Verification struct {
+	File     string `json:"file"`
+	Path     string `json:"-"`
+	SHA256   string `json:"sha256"`
+	Verified bool   `json:"verified"` // false when the metadata has no checksum to compare with
+}
+
+// fileChecksum returns the hex SHA-256 of a file
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Verify checks a backup in the backup directory against the checksum in its
+// metadata. A changed file returns ErrChecksumMismatch.
+func (m *Manager) Verify(name string) (*Verification, error) {
+	name = filepath.Base(name)
+	if !IsBackupFile(name) {
+		return nil, fmt.Errorf("backup not found")
+	}
+	path := filepath.Join(m.backupDir, name)
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("backup not found")
+	}
+	return verifyFile(path)
+}
+
+// verifyFile checks a dump against the metadata file written next to it.
+// Backups made before checksums were recorded, or copied without their
+// metadata, can't be checked and are returned unverified.
+func verifyFile(path string) (*Verification, error) {
+	sum, err := fileChecksum(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup: %w", err)
+	}
+	v := &Verification{File: filepath.Base(path), Path: path, SHA256: sum}
+
+	metadata, ok := readMetadata(path)
+	if !ok || metadata.SHA256 == "" {
+		return v, nil
+	}
+
+	if !strings.EqualFold(metadata.SHA256, sum) {
+		return nil, fmt.Errorf("%w: %s has SHA-256 %s, expected %s", ErrChecksumMismatch, v.File, sum, metadata.SHA256)
+	}
+	v.Verified = true
+	return v, nil
+}
+
+// backupMetadata is the part of a backup's metadata file needed to check and
+// read it
+type backupMetadata struct {
+	SHA256      string `json:"sha256"`
+	Compression string `json:"compression"`
+	Encryption  string `json:"encryption"`
+}
+
+// readMetadata reads the metadata file written next to a backup
+func readMetadata(path string) (backupMetadata, bool) {
+	var metadata backupMetadata
+	base, ok := backupBase(path)
+	if !ok {
+		return metadata, false
+	}
+	data, err := os.ReadFile(base + ".json")
+	if err != nil {
+		return metadata, false
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return metadata, false
+	}
+	return metadata, true
+}
+
+// readFormat returns how a backup was compressed and encrypted, from its
+// metadata or else its file name. Backups made before either was recorded
+// are plain SQL.
+func readFormat(path string) (compression, encryption string) {
+	compression, encryption = formatFromName(path)
+	if metadata, ok := readMetadata(path); ok {
+		if metadata.Compression != "" {
+			compression = metadata.Compression
+		}
+		if metadata.Encryption != "" {
+			encryption = metadata.Encryption
+		}
+	}
+	return compression, encryption
+}
+
+// checkReadable reads a compressed or encrypted backup through to the end,
+// so a wrong key or a damaged file is found before anything is restored
+func checkReadable(path, compression, encryption, key string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error reading backup: %w", err)
+	}
+	defer f.Close()
+
+	sql, release, err := sqlReader(f, compression, encryption, key)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if _, err := io.Copy(io.Discard, sql); err != nil {
+		return fmt.Errorf("backup %s is unreadable: %w", filepath.Base(path), err)
+	}
+	return nil
+}
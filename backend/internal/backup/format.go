@@ -0,0 +1,319 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Backups are plain pg_dump SQL, optionally gzipped and then optionally
+// encrypted, and named for it: backup_daily_..._02-00-00.sql, .sql.gz or
+// .sql.gz.enc (.sql.enc when encrypted but not compressed). The metadata file
+// records both settings, so a restore can undo them even after the settings
+// changed.
+//
+// Encrypted files are AES-256-GCM in 64 KiB chunks, so dumps of any size
+// are encrypted and decrypted as a stream:
+//
+//	magic "ASTBAK1\n" | salt (16) | nonce prefix (4) | chunks...
+//	chunk: ciphertext length (4, big-endian) | ciphertext
+//
+// Each chunk's nonce is the prefix followed by its 8-byte index, and its
+// additional data marks the last chunk, so reordered, dropped or truncated
+// chunks fail to decrypt.
+
+// Compression and encryption names recorded in the metadata
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	EncryptionNone  = "none"
+	EncryptionAES   = "aes-256-gcm"
+)
+
+// backupExtensions are the file name endings of backups, longest first
+var backupExtensions = []string{".sql.gz.enc", ".sql.enc", ".sql.gz", ".sql"}
+
+// ErrNoEncryptionKey means an encrypted backup can't be read because no key
+// is configured
+var ErrNoEncryptionKey = errors.New("backup is encrypted and BACKUP_ENCRYPTION_KEY is not set")
+
+const (
+	encryptionMagic   = "ASTBAK1\n"
+	encryptionChunk   = 64 * 1024
+	pbkdf2Iterations  = 210000
+	encryptionKeySize = 32
+)
+
+// backupBase returns a backup file name without its backup extension, and
+// false for files that aren't backups
+func backupBase(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	for _, ext := range backupExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return name[:len(name)-len(ext)], true
+		}
+	}
+	return "", false
+}
+
+// IsBackupFile reports whether a file name is a database backup
+func IsBackupFile(name string) bool {
+	_, ok := backupBase(name)
+	return ok
+}
+
+// formatFromName infers how a backup was written from its file name, for
+// backups whose metadata is missing
+func formatFromName(name string) (compression, encryption string) {
+	compression, encryption = CompressionNone, EncryptionNone
+	lower := strings.ToLower(name)
+	if strings.HasSuffix(lower, ".enc") {
+		encryption = EncryptionAES
+		lower = strings.TrimSuffix(lower, ".enc")
+	}
+	if strings.HasSuffix(lower, ".gz") {
+		compression = CompressionGzip
+	}
+	return compression, encryption
+}
+
+// deriveKey turns the configured key into an AES-256 key. A key of 64 hex
+// digits is used as is; anything else is a passphrase stretched with
+// PBKDF2-HMAC-SHA256 over the file's salt.
+func deriveKey(secret string, salt []byte) []byte {
+	if raw, err := hex.DecodeString(secret); err == nil && len(raw) == encryptionKeySize {
+		return raw
+	}
+	return pbkdf2SHA256([]byte(secret), salt, pbkdf2Iterations, encryptionKeySize)
+}
+
+// pbkdf2SHA256 is PBKDF2 (RFC 8018) with HMAC-SHA256
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	blocks := (keyLen + prf.Size() - 1) / prf.Size()
+	key := make([]byte, 0, blocks*prf.Size())
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write([]byte{byte(block >> 24), byte(block >> 16), byte(block >> 8), byte(block)})
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
+
+// encryptWriter encrypts everything written to it in chunks; Close writes
+// the last chunk
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix []byte
+	index  uint64
+	buf    []byte
+	closed bool
+}
+
+func newEncryptWriter(w io.Writer, secret string) (*encryptWriter, error) {
+	header := make([]byte, 16+4)
+	if _, err := rand.Read(header); err != nil {
+		return nil, err
+	}
+	salt, prefix := header[:16], header[16:]
+	aead, err := newAEAD(deriveKey(secret, salt))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, encryptionMagic); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, aead: aead, prefix: prefix, buf: make([]byte, 0, encryptionChunk)}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, index uint64) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint64(nonce[4:], index)
+	return nonce
+}
+
+func chunkAD(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(e.buf[len(e.buf):cap(e.buf)], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+		// A full chunk is only sealed once more data arrives, since the
+		// last chunk is sealed differently
+		if len(e.buf) == cap(e.buf) && len(p) > 0 {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.prefix, e.index), e.buf, chunkAD(last))
+	e.index++
+	e.buf = e.buf[:0]
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+	if _, err := e.w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+// Close seals the last chunk; it doesn't close the underlying writer
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+// decryptReader reads what an encryptWriter wrote
+type decryptReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	index  uint64
+	plain  []byte
+	done   bool
+}
+
+func newDecryptReader(r io.Reader, secret string) (*decryptReader, error) {
+	if secret == "" {
+		return nil, ErrNoEncryptionKey
+	}
+	br := bufio.NewReader(r)
+	header := make([]byte, len(encryptionMagic)+16+4)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("error reading encrypted backup: %w", err)
+	}
+	if !bytes.Equal(header[:len(encryptionMagic)], []byte(encryptionMagic)) {
+		return nil, fmt.Errorf("backup is not an encrypted backup")
+	}
+	salt := header[len(encryptionMagic) : len(encryptionMagic)+16]
+	aead, err := newAEAD(deriveKey(secret, salt))
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{r: br, aead: aead, prefix: header[len(encryptionMagic)+16:]}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+// open decrypts the next chunk
+func (d *decryptReader) open() error {
+	var length [4]byte
+	if _, err := io.ReadFull(d.r, length[:]); err != nil {
+		return fmt.Errorf("encrypted backup is truncated")
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > encryptionChunk+uint32(d.aead.Overhead()) {
+		return fmt.Errorf("encrypted backup is corrupt")
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return fmt.Errorf("encrypted backup is truncated")
+	}
+
+	nonce := chunkNonce(d.prefix, d.index)
+	plain, err := d.aead.Open(nil, nonce, sealed, chunkAD(false))
+	if err != nil {
+		plain, err = d.aead.Open(nil, nonce, sealed, chunkAD(true))
+		if err != nil {
+			return fmt.Errorf("cannot decrypt backup: wrong BACKUP_ENCRYPTION_KEY or corrupt file")
+		}
+		d.done = true
+		if _, err := d.r.Peek(1); err != io.EOF {
+			return fmt.Errorf("encrypted backup has data after its last chunk")
+		}
+	}
+	d.index++
+	d.plain = plain
+	return nil
+}
+
+// sqlReader undoes a backup's encryption and compression, returning the SQL
+// and a function to release what it opened
+func sqlReader(r io.Reader, compression, encryption, secret string) (io.Reader, func(), error) {
+	closeAll := func() {}
+	if encryption == EncryptionAES {
+		d, err := newDecryptReader(r, secret)
+		if err != nil {
+			return nil, nil, err
+		}
+		r = d
+	} else if encryption != "" && encryption != EncryptionNone {
+		return nil, nil, fmt.Errorf("unknown backup encryption %q", encryption)
+	}
+
+	switch compression {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading compressed backup: %w", err)
+		}
+		r, closeAll = gz, func() { gz.Close() }
+	case "", CompressionNone:
+	default:
+		return nil, nil, fmt.Errorf("unknown backup compression %q", compression)
+	}
+	return r, closeAll, nil
+}
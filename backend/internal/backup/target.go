@@ -0,0 +1,159 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// uploadTimeout bounds one upload of a backup to a remote target
+const uploadTimeout = 30 * time.Minute
+
+// DefaultRemoteKeepDays is how long remote copies are kept unless configured
+const DefaultRemoteKeepDays = 30
+
+// Target is somewhere off the server that backups are copied to after they
+// are written to the backup directory, such as Google Drive
+type Target interface {
+	// Name identifies the target in listings and status, e.g. "google_drive"
+	Name() string
+	// Upload copies a backup file
+	Upload(ctx context.Context, path string) (*RemoteBackup, error)
+	// List returns the backups stored on the target
+	List(ctx context.Context) ([]RemoteBackup, error)
+	// Delete removes a stored backup
+	Delete(ctx context.Context, id string) error
+}
+
+// RemoteBackup is a backup stored on a Target
+type RemoteBackup struct {
+	Target    string    `json:"target"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	SizeBytes int64     `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TargetStatus is the outcome of the latest uploads to a target
+type TargetStatus struct {
+	Target        string     `json:"target"`
+	LastUpload    string     `json:"last_upload,omitempty"` // file name of the last successful upload
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// AddTarget copies every backup made from now on to t as well
+func (m *Manager) AddTarget(t Target) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.targets = append(m.targets, t)
+	m.remote = append(m.remote, &TargetStatus{Target: t.Name()})
+}
+
+// SetRemoteRetention sets how many days remote copies are kept; older ones
+// are deleted after each upload. 0 keeps them forever.
+func (m *Manager) SetRemoteRetention(days int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remoteKeepDays = days
+}
+
+// Wait blocks until uploads in progress have finished, for commands that
+// exit right after a backup
+func (m *Manager) Wait() {
+	m.uploads.Wait()
+}
+
+// upload copies a new backup to every target in the background
+func (m *Manager) upload(path string) {
+	m.mu.Lock()
+	targets := append([]Target(nil), m.targets...)
+	statuses := append([]*TargetStatus(nil), m.remote...)
+	keepDays := m.remoteKeepDays
+	m.mu.Unlock()
+
+	for i, t := range targets {
+		m.uploads.Add(1)
+		go func(t Target, status *TargetStatus) {
+			defer m.uploads.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+			defer cancel()
+
+			remote, err := t.Upload(ctx, path)
+			now := time.Now()
+			m.mu.Lock()
+			if err != nil {
+				status.LastFailureAt, status.LastError = &now, err.Error()
+			} else {
+				status.LastSuccessAt, status.LastUpload = &now, remote.Name
+			}
+			m.mu.Unlock()
+			if err != nil {
+				log.Printf("Error uploading backup to %s: %v", t.Name(), err)
+				return
+			}
+			log.Printf("Backup uploaded to %s: %s", t.Name(), remote.Name)
+
+			if keepDays > 0 {
+				if deleted, err := pruneTarget(ctx, t, now.AddDate(0, 0, -keepDays)); err != nil {
+					log.Printf("Error cleaning old backups on %s: %v", t.Name(), err)
+				} else if deleted > 0 {
+					log.Printf("Cleaned up %d old backups on %s", deleted, t.Name())
+				}
+			}
+		}(t, statuses[i])
+	}
+}
+
+// pruneTarget deletes a target's backups created before cutoff
+func pruneTarget(ctx context.Context, t Target, cutoff time.Time) (int, error) {
+	backups, err := t.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, b := range backups {
+		if b.CreatedAt.Before(cutoff) {
+			if err := t.Delete(ctx, b.ID); err != nil {
+				return deleted, fmt.Errorf("error deleting %s: %w", b.Name, err)
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// ListRemoteBackups lists the backups on every target, newest first. A
+// target that can't be listed is reported in errors and skipped.
+func (m *Manager) ListRemoteBackups(ctx context.Context) ([]RemoteBackup, map[string]string) {
+	m.mu.Lock()
+	targets := append([]Target(nil), m.targets...)
+	m.mu.Unlock()
+
+	backups := make([]RemoteBackup, 0)
+	errors := make(map[string]string)
+	for _, t := range targets {
+		list, err := t.List(ctx)
+		if err != nil {
+			errors[t.Name()] = err.Error()
+			continue
+		}
+		backups = append(backups, list...)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, errors
+}
+
+// remoteStatus copies the target statuses; m.mu is held
+func (m *Manager) remoteStatus() []TargetStatus {
+	statuses := make([]TargetStatus, 0, len(m.remote))
+	for _, s := range m.remote {
+		statuses = append(statuses, *s)
+	}
+	return statuses
+}
@@ -0,0 +1,231 @@
+// Package chordpro parses and checks chord charts in ChordPro, the plain-text
+// format OnSong, SongbookPro and most chord apps read: {directives} in braces
+// and [chords] in brackets just before the syllable they are played on.
+package chordpro
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Song is a parsed ChordPro chart
+type Song struct {
+	Directives []Directive // every directive but the section markers, in order
+	Sections   []Section
+}
+
+// Directive is a {name: value} line, with the name's short form expanded
+type Directive struct {
+	Name  string
+	Value string
+	Line  int
+}
+
+// Section is a run of lines in one environment (verse, chorus, bridge, tab,
+// grid) or between them (Kind "")
+type Section struct {
+	Kind  string
+	Label string
+	Lines []Line
+}
+
+// Line is a lyric line with its chords taken out
+type Line struct {
+	Text   string
+	Chords []Chord
+}
+
+// Chord is a chord and the byte offset in the line's text it falls on
+type Chord struct {
+	Name string
+	At   int
+}
+
+// Problem is something wrong with a chart, on a 1-based line
+type Problem struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// shortNames expands the abbreviated directives
+var shortNames = map[string]string{
+	"t": "title", "st": "subtitle", "c": "comment", "ci": "comment_italic", "cb": "comment_box",
+	"soc": "start_of_chorus", "eoc": "end_of_chorus", "sov": "start_of_verse", "eov": "end_of_verse",
+	"sob": "start_of_bridge", "eob": "end_of_bridge", "sot": "start_of_tab", "eot": "end_of_tab",
+	"sog": "start_of_grid", "eog": "end_of_grid", "ns": "new_song", "np": "new_page", "colb": "column_break",
+}
+
+// knownDirectives are the directives of the ChordPro 6 reference besides the
+// section markers. Others are reported, since they are usually typos.
+var knownDirectives = map[string]bool{
+	"title": true, "subtitle": true, "artist": true, "composer": true, "lyricist": true, "arranger": true,
+	"copyright": true, "album": true, "year": true, "key": true, "time": true, "tempo": true,
+	"duration": true, "capo": true, "meta": true, "sorttitle": true,
+	"comment": true, "comment_italic": true, "comment_box": true, "highlight": true, "image": true,
+	"chorus": true, "new_song": true, "new_page": true, "new_physical_page": true, "column_break": true,
+	"columns": true, "pagetype": true, "titles": true, "grid": true, "no_grid": true, "transpose": true,
+	"define": true, "chord": true, "diagrams": true,
+	"textfont": true, "textsize": true, "textcolour": true, "chordfont": true, "chordsize": true,
+	"chordcolour": true, "tabfont": true, "tabsize": true, "tabcolour": true,
+}
+
+// environments are the section kinds a start_of_/end_of_ pair can open
+var environments = map[string]bool{"verse": true, "chorus": true, "bridge": true, "tab": true, "grid": true}
+
+// chordName matches chords such as G, F#m7, Bbsus4, D/F#, Am(add9) and N.C.;
+// annotations starting with * ([*Rit.]) are not chords and aren't checked
+var chordName = regexp.MustCompile(`^(N\.?C\.?|[A-G](#|b|♯|♭)?[a-zA-Z0-9#b♯♭+°ø()-]*(/[A-G](#|b|♯|♭)?)?)$`)
+
+// Parse reads a chart. The song is returned even when there are problems, so
+// callers can decide how strict to be.
+func Parse(text string) (*Song, []Problem) {
+	song := &Song{}
+	var problems []Problem
+	current := Section{}
+	open := "" // the environment a start_of_ opened
+	openedAt := 0
+
+	flush := func() {
+		if len(current.Lines) > 0 || current.Kind != "" {
+			song.Sections = append(song.Sections, current)
+		}
+		current = Section{}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, raw := range lines {
+		n := i + 1
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "#") {
+			continue // comment for whoever edits the file
+		}
+
+		if strings.HasPrefix(trimmed, "{") {
+			if !strings.HasSuffix(trimmed, "}") {
+				problems = append(problems, Problem{n, "directive isn't closed with }"})
+				continue
+			}
+			d := parseDirective(trimmed[1:len(trimmed)-1], n)
+			if d.Name == "" {
+				problems = append(problems, Problem{n, "directive has no name"})
+				continue
+			}
+
+			if kind, ok := strings.CutPrefix(d.Name, "start_of_"); ok {
+				if !environments[kind] {
+					problems = append(problems, Problem{n, fmt.Sprintf("unknown section %q", kind)})
+				}
+				if open != "" {
+					problems = append(problems, Problem{n, fmt.Sprintf("%s starts before the %s from line %d ends", kind, open, openedAt)})
+				}
+				flush()
+				current.Kind, current.Label = kind, d.Value
+				open, openedAt = kind, n
+				continue
+			}
+			if kind, ok := strings.CutPrefix(d.Name, "end_of_"); ok {
+				if open != kind {
+					problems = append(problems, Problem{n, fmt.Sprintf("end_of_%s without a start_of_%s", kind, kind)})
+				}
+				flush()
+				open = ""
+				continue
+			}
+			if !knownDirectives[d.Name] && !strings.HasPrefix(d.Name, "x_") {
+				problems = append(problems, Problem{n, fmt.Sprintf("unknown directive %q", d.Name)})
+			}
+			song.Directives = append(song.Directives, d)
+			continue
+		}
+
+		if trimmed == "" && open == "" {
+			flush()
+			continue
+		}
+		line, lineProblems := parseLine(raw, n, open == "tab" || open == "grid")
+		problems = append(problems, lineProblems...)
+		current.Lines = append(current.Lines, line)
+	}
+
+	if open != "" {
+		problems = append(problems, Problem{openedAt, fmt.Sprintf("start_of_%s is never ended", open)})
+	}
+	flush()
+	return song, problems
+}
+
+// parseDirective splits "name: value" (or "name value") and expands short names
+func parseDirective(body string, line int) Directive {
+	body = strings.TrimSpace(body)
+	name, value := body, ""
+	if i := strings.IndexAny(body, ": "); i >= 0 {
+		name, value = body[:i], strings.TrimSpace(body[i+1:])
+	}
+	name = strings.ToLower(name)
+	if long, ok := shortNames[name]; ok {
+		name = long
+	}
+	return Directive{Name: name, Value: value, Line: line}
+}
+
+// parseLine takes the chords out of a lyric line. Tabs and grids are kept as
+// they are, since brackets mean something else there.
+func parseLine(raw string, n int, verbatim bool) (Line, []Problem) {
+	if verbatim {
+		return Line{Text: raw}, nil
+	}
+
+	var problems []Problem
+	var text strings.Builder
+	var chords []Chord
+	rest := raw
+	for {
+		start := strings.IndexByte(rest, '[')
+		stray := strings.IndexByte(rest, ']')
+		if stray >= 0 && (start < 0 || stray < start) {
+			problems = append(problems, Problem{n, "] without ["})
+			text.WriteString(rest[:stray])
+			rest = rest[stray+1:]
+			continue
+		}
+		if start < 0 {
+			text.WriteString(rest)
+			break
+		}
+		text.WriteString(rest[:start])
+		rest = rest[start+1:]
+
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			problems = append(problems, Problem{n, "[ isn't closed with ]"})
+			break
+		}
+		name := strings.TrimSpace(rest[:end])
+		rest = rest[end+1:]
+		switch {
+		case name == "":
+			problems = append(problems, Problem{n, "empty chord []"})
+		case strings.HasPrefix(name, "*"):
+			// an annotation such as [*Rit.]
+		case !chordName.MatchString(name):
+			problems = append(problems, Problem{n, fmt.Sprintf("%q isn't a chord", name)})
+		}
+		chords = append(chords, Chord{Name: name, At: text.Len()})
+	}
+	return Line{Text: text.String(), Chords: chords}, problems
+}
+
+// Meta returns the value of the first directive with the given name, or ""
+func (s *Song) Meta(name string) string {
+	for _, d := range s.Directives {
+		if d.Name == name {
+			return d.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,421 @@
+// Package collab keeps a shared draft of each song being edited by more than
+// one person. Editors send field-level patches, stamped with the version of
+// the field they edited; patches to different fields or different lyric
+// sections always merge, and a patch made on an out-of-date copy of the same
+// field or section is turned back as a conflict instead of overwriting it.
+package collab
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// Fields that can be patched. Display lyrics are split into sections, which
+// are patched one at a time.
+const (
+	FieldTitle               = "title"
+	FieldArtist              = "artist"
+	FieldLanguage            = "language"
+	FieldDisplayLyrics       = "display_lyrics"
+	FieldMusicMinistryLyrics = "music_ministry_lyrics"
+)
+
+var textFields = map[string]bool{
+	FieldTitle:               true,
+	FieldArtist:              true,
+	FieldLanguage:            true,
+	FieldMusicMinistryLyrics: true,
+}
+
+// Patch operations
+const (
+	OpSet    = "set"
+	OpInsert = "insert" // add a lyric section after After ("" for the top)
+	OpDelete = "delete" // remove a lyric section
+)
+
+// Message types on the collaboration channel
+const (
+	MessageState           = "state"    // the whole draft, on joining or after an outside save
+	MessagePatch           = "patch"    // an applied patch, sent to every editor including its author
+	MessageConflict        = "conflict" // a patch was not applied; sent to its author only
+	MessagePresence        = "presence" // who is editing
+	MessageSave            = "save"     // from an editor: write the draft to the song
+	MessageSaved           = "saved"
+	MessageDisplayWarnings = "display_warnings" // to the editor who saved, when the lyrics won't fit a display
+	MessageError           = "error"
+)
+
+// Patch is one edit. Base is the version of the field or section the editor
+// last saw; for a whole-lyrics set it is the lyrics version.
+type Patch struct {
+	Ref     string `json:"ref,omitempty"` // the editor's own ID for the patch, echoed back
+	Op      string `json:"op"`
+	Field   string `json:"field"`
+	Section string `json:"section,omitempty"` // display lyrics section ID
+	After   string `json:"after,omitempty"`
+	Base    int    `json:"base"`
+	Value   string `json:"value"`
+	Version int    `json:"version,omitempty"` // the field or section's version once applied
+}
+
+// Value is a field and the document version it was last changed at
+type Value struct {
+	Value   string `json:"value"`
+	Version int    `json:"version"`
+}
+
+// Section is a stanza of the display lyrics
+type Section struct {
+	ID      string `json:"id"`
+	Text    string `json:"text"`
+	Version int    `json:"version"`
+}
+
+// Document is the shared draft of a song
+type Document struct {
+	SongID        string           `json:"song_id"`
+	Version       int              `json:"version"`
+	Fields        map[string]Value `json:"fields"`
+	Sections      []Section        `json:"sections"`
+	LyricsVersion int              `json:"lyrics_version"` // last change to any section
+	SavedVersion  int              `json:"saved_version"`
+	nextSection   int
+}
+
+// Message is sent and received on the collaboration channel
+type Message struct {
+	Type     string    `json:"type"`
+	Editor   string    `json:"editor,omitempty"`
+	Editors  []string  `json:"editors,omitempty"`
+	Patch    *Patch    `json:"patch,omitempty"`
+	Document *Document `json:"document,omitempty"`
+	Error    string    `json:"error,omitempty"`
+
+	DisplayWarnings []models.DisplayWarning `json:"display_warnings,omitempty"`
+}
+
+var stanzaBreak = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// NewDocument starts a draft from a song's saved fields
+func NewDocument(songID string, fields map[string]string) Document {
+	doc := Document{SongID: songID, Fields: make(map[string]Value), Sections: make([]Section, 0)}
+	for name := range textFields {
+		doc.Fields[name] = Value{Value: fields[name]}
+	}
+
+	lyrics := strings.TrimSpace(strings.ReplaceAll(fields[FieldDisplayLyrics], "\r\n", "\n"))
+	if lyrics != "" {
+		for _, stanza := range stanzaBreak.Split(lyrics, -1) {
+			doc.Sections = append(doc.Sections, Section{ID: doc.newSectionID(), Text: stanza})
+		}
+	}
+	return doc
+}
+
+// Lyrics joins the display lyrics sections back together
+func (d *Document) Lyrics() string {
+	stanzas := make([]string, len(d.Sections))
+	for i, section := range d.Sections {
+		stanzas[i] = section.Text
+	}
+	return strings.Join(stanzas, "\n\n")
+}
+
+// Dirty reports whether the draft has changes that haven't been saved
+func (d *Document) Dirty() bool {
+	return d.Version != d.SavedVersion
+}
+
+func (d *Document) newSectionID() string {
+	d.nextSection++
+	return fmt.Sprintf("s%d", d.nextSection)
+}
+
+func (d *Document) sectionIndex(id string) int {
+	for i, section := range d.Sections {
+		if section.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// clone copies the draft so it can be handed out while editing carries on
+func (d *Document) clone() Document {
+	out := *d
+	out.Fields = make(map[string]Value, len(d.Fields))
+	for name, value := range d.Fields {
+		out.Fields[name] = value
+	}
+	out.Sections = append(make([]Section, 0, len(d.Sections)), d.Sections...)
+	return out
+}
+
+// apply merges a patch into the draft, returning it as applied or the reason
+// it conflicts
+func (d *Document) apply(p Patch) (Patch, string) {
+	version := d.Version + 1
+
+	if textFields[p.Field] {
+		current := d.Fields[p.Field]
+		if p.Op != OpSet {
+			return p, "only set applies to " + p.Field
+		}
+		if p.Base != current.Version {
+			return p, p.Field + " was changed by someone else"
+		}
+		d.Fields[p.Field] = Value{Value: p.Value, Version: version}
+		d.Version, p.Version = version, version
+		return p, ""
+	}
+	if p.Field != FieldDisplayLyrics {
+		return p, "unknown field " + p.Field
+	}
+
+	switch p.Op {
+	case OpSet:
+		if p.Section == "" {
+			// Replacing all the lyrics only merges with nothing
+			if p.Base != d.LyricsVersion {
+				return p, "the lyrics were changed by someone else"
+			}
+			next := NewDocument(d.SongID, map[string]string{FieldDisplayLyrics: p.Value})
+			d.Sections = make([]Section, 0, len(next.Sections))
+			for _, section := range next.Sections {
+				d.Sections = append(d.Sections, Section{ID: d.newSectionID(), Text: section.Text, Version: version})
+			}
+			break
+		}
+		i := d.sectionIndex(p.Section)
+		if i < 0 {
+			return p, "section was deleted by someone else"
+		}
+		if p.Base != d.Sections[i].Version {
+			return p, "section was changed by someone else"
+		}
+		d.Sections[i].Text, d.Sections[i].Version = strings.TrimSpace(p.Value), version
+
+	case OpInsert:
+		at := 0
+		if p.After != "" {
+			if at = d.sectionIndex(p.After) + 1; at == 0 {
+				return p, "section was deleted by someone else"
+			}
+		}
+		p.Section = d.newSectionID()
+		section := Section{ID: p.Section, Text: strings.TrimSpace(p.Value), Version: version}
+		d.Sections = append(d.Sections[:at], append([]Section{section}, d.Sections[at:]...)...)
+
+	case OpDelete:
+		i := d.sectionIndex(p.Section)
+		if i < 0 {
+			// Already gone, which is what the editor wanted
+			p.Version = d.Version
+			return p, ""
+		}
+		if p.Base != d.Sections[i].Version {
+			return p, "section was changed by someone else"
+		}
+		d.Sections = append(d.Sections[:i], d.Sections[i+1:]...)
+
+	default:
+		return p, "unknown operation " + p.Op
+	}
+
+	d.Version, d.LyricsVersion, p.Version = version, version, version
+	return p, ""
+}
+
+// Editor is one connection to a song's session
+type Editor struct {
+	Name     string
+	Messages chan Message
+	session  *session
+}
+
+type session struct {
+	doc     Document
+	editors map[*Editor]struct{}
+}
+
+func (s *session) names() []string {
+	names := make([]string, 0, len(s.editors))
+	for e := range s.editors {
+		names = append(names, e.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// send delivers a message without blocking. An editor too slow to keep up
+// is disconnected rather than left with a draft that has missed patches;
+// reconnecting sends it the current state.
+func (s *session) send(e *Editor, msg Message) {
+	select {
+	case e.Messages <- msg:
+	default:
+		log.Printf("Collaboration: disconnecting slow editor %q of song %s", e.Name, s.doc.SongID)
+		delete(s.editors, e)
+		close(e.Messages)
+	}
+}
+
+func (s *session) broadcast(msg Message) {
+	for e := range s.editors {
+		s.send(e, msg)
+	}
+}
+
+// Manager holds the sessions of songs being edited
+type Manager struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// NewManager creates a manager with no sessions
+func NewManager() *Manager {
+	return &Manager{sessions: make(map[string]*session)}
+}
+
+// Join adds an editor to a song's session, starting the session from load
+// if nobody else is editing the song. The editor's first message is the
+// current state.
+func (m *Manager) Join(songID, name string, load func() (Document, error)) (*Editor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[songID]
+	if !ok {
+		doc, err := load()
+		if err != nil {
+			return nil, err
+		}
+		s = &session{doc: doc, editors: make(map[*Editor]struct{})}
+		m.sessions[songID] = s
+	}
+
+	e := &Editor{Name: name, Messages: make(chan Message, 64), session: s}
+	s.editors[e] = struct{}{}
+	doc := s.doc.clone()
+	s.send(e, Message{Type: MessageState, Document: &doc, Editors: s.names()})
+	s.broadcast(Message{Type: MessagePresence, Editor: name, Editors: s.names()})
+	return e, nil
+}
+
+// Leave removes an editor. The session, and any unsaved draft, ends with the
+// last editor.
+func (m *Manager) Leave(e *Editor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := e.session
+	if _, ok := s.editors[e]; ok {
+		delete(s.editors, e)
+		close(e.Messages)
+	}
+	if len(s.editors) == 0 {
+		if m.sessions[s.doc.SongID] == s {
+			delete(m.sessions, s.doc.SongID)
+		}
+		return
+	}
+	s.broadcast(Message{Type: MessagePresence, Editor: e.Name, Editors: s.names()})
+}
+
+// Apply merges an editor's patch and sends it to every editor, or sends the
+// author a conflict with the current draft
+func (m *Manager) Apply(e *Editor, p Patch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := e.session
+	if _, ok := s.editors[e]; !ok {
+		return
+	}
+	applied, conflict := s.doc.apply(p)
+	if conflict != "" {
+		doc := s.doc.clone()
+		s.send(e, Message{Type: MessageConflict, Patch: &p, Error: conflict, Document: &doc})
+		return
+	}
+	s.broadcast(Message{Type: MessagePatch, Editor: e.Name, Patch: &applied})
+}
+
+// Snapshot returns a copy of the editor's draft
+func (m *Manager) Snapshot(e *Editor) Document {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return e.session.doc.clone()
+}
+
+// Saved records that the draft was saved as it stood at version and tells
+// every editor
+func (m *Manager) Saved(e *Editor, version int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := e.session
+	if version > s.doc.SavedVersion {
+		s.doc.SavedVersion = version
+	}
+	doc := s.doc.clone()
+	s.broadcast(Message{Type: MessageSaved, Editor: e.Name, Document: &doc})
+}
+
+// Send delivers a message to one editor
+func (m *Manager) Send(e *Editor, msg Message) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := e.session.editors[e]; ok {
+		e.session.send(e, msg)
+	}
+}
+
+// Reload replaces a song's draft after it was saved some other way, such as
+// a plain update, and sends editors the new state. Unsaved edits in the
+// draft are lost, as they would be when saving over an open editor.
+func (m *Manager) Reload(songID string, doc Document) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[songID]
+	if !ok {
+		return
+	}
+	// Keep versions moving forward so patches made on the old draft conflict
+	doc.Version = s.doc.Version + 1
+	doc.SavedVersion, doc.LyricsVersion = doc.Version, doc.Version
+	for name, value := range doc.Fields {
+		value.Version = doc.Version
+		doc.Fields[name] = value
+	}
+	// New section IDs too, so nothing from the old draft matches them
+	doc.nextSection = s.doc.nextSection
+	for i := range doc.Sections {
+		doc.Sections[i].ID, doc.Sections[i].Version = doc.newSectionID(), doc.Version
+	}
+	s.doc = doc
+
+	out := s.doc.clone()
+	s.broadcast(Message{Type: MessageState, Document: &out, Editors: s.names()})
+}
+
+// Editors lists who is editing a song
+func (m *Manager) Editors(songID string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.sessions[songID]; ok {
+		return s.names()
+	}
+	return nil
+}
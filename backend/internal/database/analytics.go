@@ -0,0 +1,144 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// RecordSongUsage marks a song as used in today's service. Repeated triggers
+// on the same day count once.
+func (db *DB) RecordSongUsage(songID string) error {
+	query := `
+		INSERT INTO song_usage (song_id, service_date, used_at)
+		VALUES ($1, CURRENT_DATE, NOW())
+		ON CONFLICT (song_id, service_date) DO NOTHING
+	`
+
+	if _, err := db.Exec(query, songID); err != nil {
+		return fmt.Errorf("error recording song usage: %w", err)
+	}
+	return nil
+}
+
+// GetSongsPerMonth returns usage totals for each of the last n months that had any usage
+func (db *DB) GetSongsPerMonth(months int) ([]models.MonthlySongCount, error) {
+	query := `
+		SELECT TO_CHAR(service_date, 'YYYY-MM') AS month,
+		       COUNT(*) AS uses,
+		       COUNT(DISTINCT song_id) AS unique_songs
+		FROM song_usage
+		WHERE service_date >= ` + db.dialect.monthStart(1) + `
+		GROUP BY month
+		ORDER BY month ASC
+	`
+
+	rows, err := db.Query(query, months)
+	if err != nil {
+		return nil, fmt.Errorf("error getting songs per month: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]models.MonthlySongCount, 0)
+	for rows.Next() {
+		var count models.MonthlySongCount
+		if err := rows.Scan(&count.Month, &count.Uses, &count.UniqueSongs); err != nil {
+			return nil, fmt.Errorf("error scanning monthly count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
+// GetSongUsageRanking returns the most (or least) used songs since the given
+// date (YYYY-MM-DD). The least-used ranking includes songs that were never used.
+func (db *DB) GetSongUsageRanking(since string, limit int, leastUsed bool) ([]models.SongUsageCount, error) {
+	order := "uses DESC, last_used DESC NULLS LAST, s.title ASC"
+	if leastUsed {
+		order = "uses ASC, last_used ASC NULLS FIRST, s.title ASC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT s.id, s.title, s.language,
+		       COUNT(u.id) AS uses,
+		       TO_CHAR(MAX(u.service_date), 'YYYY-MM-DD') AS last_used
+		FROM songs s
+		LEFT JOIN song_usage u ON u.song_id = s.id AND u.service_date >= $1::date
+		WHERE s.deleted_at IS NULL
+		GROUP BY s.id, s.title, s.language
+		ORDER BY %s
+		LIMIT $2
+	`, order)
+
+	rows, err := db.Query(query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting song usage ranking: %w", err)
+	}
+	defer rows.Close()
+
+	ranking := make([]models.SongUsageCount, 0)
+	for rows.Next() {
+		var count models.SongUsageCount
+		if err := rows.Scan(&count.SongID, &count.Title, &count.Language, &count.Uses, &count.LastUsed); err != nil {
+			return nil, fmt.Errorf("error scanning song usage: %w", err)
+		}
+		ranking = append(ranking, count)
+	}
+
+	return ranking, nil
+}
+
+// GetLanguageMix returns usage per language per month for the last n months
+func (db *DB) GetLanguageMix(months int) ([]models.LanguageMonthCount, error) {
+	query := `
+		SELECT TO_CHAR(u.service_date, 'YYYY-MM') AS month, s.language, COUNT(*) AS uses
+		FROM song_usage u
+		JOIN songs s ON s.id = u.song_id
+		WHERE u.service_date >= ` + db.dialect.monthStart(1) + `
+		GROUP BY month, s.language
+		ORDER BY month ASC, uses DESC
+	`
+
+	rows, err := db.Query(query, months)
+	if err != nil {
+		return nil, fmt.Errorf("error getting language mix: %w", err)
+	}
+	defer rows.Close()
+
+	mix := make([]models.LanguageMonthCount, 0)
+	for rows.Next() {
+		var count models.LanguageMonthCount
+		if err := rows.Scan(&count.Month, &count.Language, &count.Uses); err != nil {
+			return nil, fmt.Errorf("error scanning language mix: %w", err)
+		}
+		mix = append(mix, count)
+	}
+
+	return mix, nil
+}
+
+// GetSetLengthStats summarises how many songs were used per service day over the last n months
+func (db *DB) GetSetLengthStats(months int) (*models.SetLengthStats, error) {
+	query := `
+		SELECT COUNT(*),
+		       COALESCE(AVG(songs), 0),
+		       COALESCE(MIN(songs), 0),
+		       COALESCE(MAX(songs), 0)
+		FROM (
+			SELECT service_date, COUNT(*) AS songs
+			FROM song_usage
+			WHERE service_date >= ` + db.dialect.monthStart(1) + `
+			GROUP BY service_date
+		) services
+	`
+
+	var stats models.SetLengthStats
+	err := db.QueryRow(query, months).
+		Scan(&stats.Services, &stats.AverageSongs, &stats.ShortestService, &stats.LongestService)
+	if err != nil {
+		return nil, fmt.Errorf("error getting set length stats: %w", err)
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const apiKeyColumns = `k.id, k.user_id, u.username, k.name, k.role, k.key_prefix, k.created_at, k.last_used_at, k.expires_at`
+
+func scanAPIKey(row interface{ Scan(...interface{}) error }) (*models.APIKey, error) {
+	var k models.APIKey
+	if err := row.Scan(&k.ID, &k.UserID, &k.Username, &k.Name, &k.Role, &k.Prefix, &k.CreatedAt, &k.LastUsedAt, &k.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// CreateAPIKey stores the hash of a new API key for a user
+func (db *DB) CreateAPIKey(userID int, req *models.CreateAPIKeyRequest, keyHash, prefix string, expiresAt *time.Time) (*models.APIKey, error) {
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO api_keys (user_id, name, role, key_hash, key_prefix, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, userID, req.Name, req.Role, keyHash, prefix, expiresAt).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("error creating api key: %w", err)
+	}
+	return db.getAPIKey(`k.id = $1`, id)
+}
+
+// GetAPIKeyUser returns the unexpired API key with a hash and its user,
+// marking the key as used (at most once a minute)
+func (db *DB) GetAPIKeyUser(keyHash string) (*models.User, *models.APIKey, error) {
+	key, err := db.getAPIKey(`k.key_hash = $1 AND (k.expires_at IS NULL OR k.expires_at > NOW())`, keyHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := db.GetUser(key.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > time.Minute {
+		if _, err := db.Exec(`UPDATE api_keys SET last_used_at = NOW() WHERE id = $1`, key.ID); err != nil {
+			return nil, nil, fmt.Errorf("error updating api key: %w", err)
+		}
+	}
+	return user, key, nil
+}
+
+func (db *DB) getAPIKey(where string, arg interface{}) (*models.APIKey, error) {
+	row := db.QueryRow(`
+		SELECT `+apiKeyColumns+`
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE `+where, arg)
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("api key not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting api key: %w", err)
+	}
+	return key, nil
+}
+
+// GetAPIKeys lists API keys, newest first, optionally only those of one user
+// (userID 0 for all)
+func (db *DB) GetAPIKeys(userID int) ([]models.APIKey, error) {
+	rows, err := db.Query(`
+		SELECT `+apiKeyColumns+`
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE ($1 = 0 OR k.user_id = $1)
+		ORDER BY k.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]models.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning api key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+// CountUsableAPIKeys counts the unexpired API keys of enabled users
+func (db *DB) CountUsableAPIKeys() (int, error) {
+	var count int
+	err := db.QueryRow(`
+		SELECT COUNT(*)
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE NOT u.disabled AND (k.expires_at IS NULL OR k.expires_at > NOW())
+	`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting api keys: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteAPIKey revokes an API key, only if it belongs to userID unless that
+// is 0
+func (db *DB) DeleteAPIKey(id, userID int) error {
+	result, err := db.Exec(`DELETE FROM api_keys WHERE id = $1 AND ($2 = 0 OR user_id = $2)`, id, userID)
+	if err != nil {
+		return fmt.Errorf("error deleting api key: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("api key not found")
+	}
+	return nil
+}
@@ -0,0 +1,701 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	pq "github.com/lib/pq"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// ExportArchive reads the whole installation into a migration archive. All
+// sections are read in one repeatable-read transaction so they are consistent.
+// Songs, by far the largest section, are not collected: once the other
+// sections are read the archive is passed to begin, then each song is passed
+// to eachSong as its row is scanned, so callers can stream the archive.
+func (db *DB) ExportArchive(begin func(*models.Archive) error, eachSong func(*models.Song) error) error {
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	archive := &models.Archive{
+		Format:      models.ArchiveFormat,
+		Version:     models.ArchiveVersion,
+		ExportedAt:  time.Now().UTC(),
+		Songbooks:   make([]models.Songbook, 0),
+		SongPairs:   make([]models.SongPair, 0),
+		SongNotes:   make([]models.SongNote, 0),
+		SongCues:    make([]models.SongCues, 0),
+		SongTimings: make([]models.SongTiming, 0),
+		TimedLyrics: make([]models.TimedLyrics, 0),
+		Variants:    make([]models.LyricVariant, 0),
+		SongAudio:   make([]models.SongAudio, 0),
+		Setlists:    make([]models.ArchiveSetlist, 0),
+		SongUsage:   make([]models.ArchiveUsage, 0),
+		CCLIUsage:   make([]models.CCLIUsage, 0),
+		Services:    make([]models.ArchiveService, 0),
+	}
+
+	steps := []struct {
+		name string
+		fn   func(*sql.Tx, *models.Archive) error
+	}{
+		{"songbooks", exportSongbooks},
+		{"song pairs", exportSongPairs},
+		{"song notes", exportSongNotes},
+		{"song cues", exportSongCues},
+		{"song timings", exportSongTimings},
+		{"timed lyrics", exportTimedLyrics},
+		{"lyric variants", exportLyricVariants},
+		{"song audio", exportSongAudio},
+		{"setlists", exportSetlists},
+		{"settings", exportSettings},
+		{"song usage", exportSongUsage},
+		{"CCLI usage", exportCCLIUsage},
+		{"services", exportServices},
+	}
+	for _, step := range steps {
+		if err := step.fn(tx, archive); err != nil {
+			return fmt.Errorf("error exporting %s: %w", step.name, err)
+		}
+	}
+
+	if err := begin(archive); err != nil {
+		return err
+	}
+	if err := exportSongs(tx, eachSong); err != nil {
+		return fmt.Errorf("error exporting songs: %w", err)
+	}
+	return nil
+}
+
+func exportSongs(tx *sql.Tx, eachSong func(*models.Song) error) error {
+	// Songbook numbers and links travel on the songs. They are read first since
+	// the transaction can't run a query while the song rows are open.
+	numbers := make(map[string][]models.SongNumber)
+	numberRows, err := tx.Query(`SELECT song_id, songbook, number FROM song_numbers ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer numberRows.Close()
+	for numberRows.Next() {
+		var songID string
+		var n models.SongNumber
+		if err := numberRows.Scan(&songID, &n.Songbook, &n.Number); err != nil {
+			return err
+		}
+		numbers[songID] = append(numbers[songID], n)
+	}
+	if err := numberRows.Err(); err != nil {
+		return err
+	}
+
+	links := make(map[string][]models.SongLink)
+	linkRows, err := tx.Query(`SELECT song_id, ` + linkColumns + ` FROM song_links ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer linkRows.Close()
+	for linkRows.Next() {
+		var songID string
+		var l models.SongLink
+		if err := linkRows.Scan(append([]interface{}{&songID}, linkFields(&l)...)...); err != nil {
+			return err
+		}
+		links[songID] = append(links[songID], l)
+	}
+	if err := linkRows.Err(); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT ` + songColumns + ` FROM songs ORDER BY created_at ASC, id ASC`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var song models.Song
+		if err := rows.Scan(songFields(&song)...); err != nil {
+			return err
+		}
+		song.Numbers = numbers[song.ID]
+		song.Links = links[song.ID]
+		if err := eachSong(&song); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportSongbooks(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT ` + songbookColumns + ` FROM songbooks ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		book, err := scanSongbook(rows)
+		if err != nil {
+			return err
+		}
+		archive.Songbooks = append(archive.Songbooks, *book)
+	}
+	return rows.Err()
+}
+
+func exportSongPairs(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT id, primary_song_id, secondary_song_id, alignment, created_at, updated_at FROM song_pairs ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pair models.SongPair
+		var alignment []byte
+		if err := rows.Scan(&pair.ID, &pair.PrimarySongID, &pair.SecondarySongID, &alignment, &pair.CreatedAt, &pair.UpdatedAt); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(alignment, &pair.Alignment); err != nil {
+			return err
+		}
+		archive.SongPairs = append(archive.SongPairs, pair)
+	}
+	return rows.Err()
+}
+
+func exportSongNotes(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT id, song_id, section_index, section_label, note, created_at, updated_at FROM song_notes ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note models.SongNote
+		var sectionIndex sql.NullInt64
+		if err := rows.Scan(&note.ID, &note.SongID, &sectionIndex, &note.SectionLabel, &note.Note, &note.CreatedAt, &note.UpdatedAt); err != nil {
+			return err
+		}
+		if sectionIndex.Valid {
+			idx := int(sectionIndex.Int64)
+			note.SectionIndex = &idx
+		}
+		archive.SongNotes = append(archive.SongNotes, note)
+	}
+	return rows.Err()
+}
+
+func exportSongCues(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT ` + cueColumns + ` FROM song_cues ORDER BY song_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		cues, err := scanSongCues(rows)
+		if err != nil {
+			return err
+		}
+		archive.SongCues = append(archive.SongCues, *cues)
+	}
+	return rows.Err()
+}
+
+func exportSongTimings(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT song_id, default_seconds, slide_seconds, loop, updated_at FROM song_timings ORDER BY song_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var timing models.SongTiming
+		var seconds []int64
+		if err := rows.Scan(&timing.SongID, &timing.DefaultSeconds, pq.Array(&seconds), &timing.Loop, &timing.UpdatedAt); err != nil {
+			return err
+		}
+		timing.SlideSeconds = make([]int, len(seconds))
+		for i, s := range seconds {
+			timing.SlideSeconds[i] = int(s)
+		}
+		archive.SongTimings = append(archive.SongTimings, timing)
+	}
+	return rows.Err()
+}
+
+func exportTimedLyrics(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT song_id, lines, offset_ms, updated_at FROM song_timed_lyrics ORDER BY song_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var timed models.TimedLyrics
+		var lines []byte
+		if err := rows.Scan(&timed.SongID, &lines, &timed.OffsetMs, &timed.UpdatedAt); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(lines, &timed.Lines); err != nil {
+			return err
+		}
+		archive.TimedLyrics = append(archive.TimedLyrics, timed)
+	}
+	return rows.Err()
+}
+
+func exportLyricVariants(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT song_id, variant, lyrics, generated, updated_at FROM song_lyric_variants ORDER BY song_id, variant`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v models.LyricVariant
+		if err := rows.Scan(&v.SongID, &v.Variant, &v.Lyrics, &v.Generated, &v.UpdatedAt); err != nil {
+			return err
+		}
+		archive.Variants = append(archive.Variants, v)
+	}
+	return rows.Err()
+}
+
+func exportSongAudio(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT ` + audioColumns + ` FROM song_audio_tracks ORDER BY song_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		track, err := scanSongAudio(rows)
+		if err != nil {
+			return err
+		}
+		archive.SongAudio = append(archive.SongAudio, *track)
+	}
+	return rows.Err()
+}
+
+func exportSetlists(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT ` + setlistColumns + ` FROM setlists ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := make(map[int]int)
+	for rows.Next() {
+		setlist, err := scanSetlist(rows)
+		if err != nil {
+			return err
+		}
+		index[setlist.ID] = len(archive.Setlists)
+		archive.Setlists = append(archive.Setlists, models.ArchiveSetlist{
+			Name:        setlist.Name,
+			ServiceDate: setlist.ServiceDate,
+			SongIDs:     make([]string, 0),
+			CreatedAt:   setlist.CreatedAt,
+			UpdatedAt:   setlist.UpdatedAt,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	songRows, err := tx.Query(`SELECT setlist_id, song_id FROM setlist_songs ORDER BY setlist_id, position`)
+	if err != nil {
+		return err
+	}
+	defer songRows.Close()
+
+	for songRows.Next() {
+		var setlistID int
+		var songID string
+		if err := songRows.Scan(&setlistID, &songID); err != nil {
+			return err
+		}
+		if i, ok := index[setlistID]; ok {
+			archive.Setlists[i].SongIDs = append(archive.Setlists[i].SongIDs, songID)
+		}
+	}
+	return songRows.Err()
+}
+
+func exportSettings(tx *sql.Tx, archive *models.Archive) error {
+	var settings models.ArchiveSettings
+	err := tx.QueryRow(`
+		SELECT COALESCE(propresenter_host, ''),
+		       COALESCE(propresenter_port, 4031),
+		       COALESCE(propresenter_playlist, 'Live Queue'),
+		       COALESCE(rehearsal_playlist, 'Rehearsal'),
+		       COALESCE(ccli_license, ''),
+		       COALESCE(copyright_slide, FALSE)
+		FROM settings WHERE id = 1
+	`).Scan(&settings.ProPresenterHost, &settings.ProPresenterPort, &settings.ProPresenterPlaylist, &settings.RehearsalPlaylist,
+		&settings.CCLILicense, &settings.CopyrightSlide)
+
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	archive.Settings = &settings
+	return nil
+}
+
+func exportSongUsage(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT song_id, TO_CHAR(service_date, 'YYYY-MM-DD'), used_at FROM song_usage ORDER BY service_date, id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var usage models.ArchiveUsage
+		if err := rows.Scan(&usage.SongID, &usage.ServiceDate, &usage.UsedAt); err != nil {
+			return err
+		}
+		archive.SongUsage = append(archive.SongUsage, usage)
+	}
+	return rows.Err()
+}
+
+func exportCCLIUsage(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT song_id, title, artist, copyright, ccli_number, used_at FROM ccli_usage ORDER BY used_at, id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var usage models.CCLIUsage
+		if err := rows.Scan(&usage.SongID, &usage.Title, &usage.Artist, &usage.Copyright, &usage.CCLINumber, &usage.UsedAt); err != nil {
+			return err
+		}
+		archive.CCLIUsage = append(archive.CCLIUsage, usage)
+	}
+	return rows.Err()
+}
+
+func exportServices(tx *sql.Tx, archive *models.Archive) error {
+	rows, err := tx.Query(`SELECT ` + serviceColumns + `, report FROM services ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := make(map[int]int)
+	for rows.Next() {
+		var service models.ArchiveService
+		var archivedAt sql.NullTime
+		var report []byte
+		if err := rows.Scan(&service.ID, &service.Name, &service.ServiceDate, &service.Status, &service.StartedAt, &archivedAt, &report); err != nil {
+			return err
+		}
+		if archivedAt.Valid {
+			service.ArchivedAt = &archivedAt.Time
+		}
+		service.Report = report
+		service.Events = make([]models.ServiceEvent, 0)
+		index[service.ID] = len(archive.Services)
+		archive.Services = append(archive.Services, service)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	eventRows, err := tx.Query(`
+		SELECT id, service_id, event_type, song_id, title, operator, message, occurred_at
+		FROM service_events
+		ORDER BY service_id, occurred_at, id
+	`)
+	if err != nil {
+		return err
+	}
+	defer eventRows.Close()
+
+	for eventRows.Next() {
+		var event models.ServiceEvent
+		var songID sql.NullString
+		if err := eventRows.Scan(&event.ID, &event.ServiceID, &event.EventType, &songID, &event.Title, &event.Operator, &event.Message, &event.OccurredAt); err != nil {
+			return err
+		}
+		if songID.Valid {
+			event.SongID = &songID.String
+		}
+		if i, ok := index[event.ServiceID]; ok {
+			archive.Services[i].Events = append(archive.Services[i].Events, event)
+		}
+	}
+	return eventRows.Err()
+}
+
+// ImportArchive restores a migration archive onto an empty installation in a
+// single transaction, so a failed import leaves nothing behind. Song IDs are
+// kept; other rows get new IDs with references remapped.
+func (db *DB) ImportArchive(archive *models.Archive) (*models.ArchiveImportResult, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var hasSongs bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM songs)`).Scan(&hasSongs); err != nil {
+		return nil, fmt.Errorf("error checking library: %w", err)
+	}
+	if hasSongs {
+		return nil, fmt.Errorf("library is not empty")
+	}
+
+	result := &models.ArchiveImportResult{}
+
+	for _, song := range archive.Songs {
+		_, err := tx.Exec(`
+			INSERT INTO songs (id, title, file_name, library, language, pro_uuid, display_lyrics, music_ministry_lyrics, artist, original_key, performance_key, bpm, time_signature, count_in_beats, background_media, look, copyright, ccli_number, chords, public, archived_at, deleted_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+		`, song.ID, song.Title, song.FileName, song.Library, song.Language, song.ProUUID, song.DisplayLyrics, song.MusicMinistryLyrics,
+			song.Artist, song.OriginalKey, song.PerformanceKey, song.BPM, song.TimeSignature, song.CountInBeats, song.BackgroundMedia, song.Look,
+			song.Copyright, song.CCLINumber, song.Chords, song.Public, song.ArchivedAt, song.DeletedAt,
+			song.CreatedAt, song.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing song %q: %w", song.Title, err)
+		}
+		for _, n := range song.Numbers {
+			if _, err := tx.Exec(`INSERT INTO song_numbers (song_id, songbook, number) VALUES ($1, $2, $3)`, song.ID, n.Songbook, n.Number); err != nil {
+				return nil, fmt.Errorf("error importing number %s %s of song %q: %w", n.Songbook, n.Number, song.Title, err)
+			}
+			result.SongNumbers++
+		}
+		for _, l := range song.Links {
+			_, err := tx.Exec(`
+				INSERT INTO song_links (song_id, `+linkColumns+`)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			`, song.ID, l.Kind, l.URL, l.Title, l.AuthorName, l.ThumbnailURL, l.EmbedHTML, l.FetchedAt)
+			if err != nil {
+				return nil, fmt.Errorf("error importing link %s of song %q: %w", l.URL, song.Title, err)
+			}
+			result.SongLinks++
+		}
+		result.Songs++
+	}
+
+	for _, book := range archive.Songbooks {
+		_, err := tx.Exec(`
+			INSERT INTO songbooks (name, abbreviation, description, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, book.Name, book.Abbreviation, book.Description, book.CreatedAt, book.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing songbook %q: %w", book.Name, err)
+		}
+		result.Songbooks++
+	}
+	// Older archives have no songbooks section; create the ones the numbers use
+	_, err = tx.Exec(`
+		INSERT INTO songbooks (name)
+		SELECT songbook FROM song_numbers n
+		WHERE id = (SELECT MIN(id) FROM song_numbers WHERE LOWER(songbook) = LOWER(n.songbook))
+		ORDER BY LOWER(songbook)
+		ON CONFLICT DO NOTHING
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error importing songbooks: %w", err)
+	}
+
+	for _, pair := range archive.SongPairs {
+		alignment, err := json.Marshal(pair.Alignment)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding alignment: %w", err)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO song_pairs (primary_song_id, secondary_song_id, alignment, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, pair.PrimarySongID, pair.SecondarySongID, alignment, pair.CreatedAt, pair.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing song pair: %w", err)
+		}
+		result.SongPairs++
+	}
+
+	for _, note := range archive.SongNotes {
+		_, err := tx.Exec(`
+			INSERT INTO song_notes (song_id, section_index, section_label, note, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, note.SongID, note.SectionIndex, note.SectionLabel, note.Note, note.CreatedAt, note.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing song note: %w", err)
+		}
+		result.SongNotes++
+	}
+
+	for _, cues := range archive.SongCues {
+		_, err := tx.Exec(`
+			INSERT INTO song_cues (song_id, intro_bars, starts_with, dynamics, ending, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, cues.SongID, cues.IntroBars, cues.StartsWith, cues.Dynamics, cues.Ending, cues.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing song cues: %w", err)
+		}
+		result.SongCues++
+	}
+
+	for _, timing := range archive.SongTimings {
+		seconds := make([]int64, len(timing.SlideSeconds))
+		for i, s := range timing.SlideSeconds {
+			seconds[i] = int64(s)
+		}
+		_, err := tx.Exec(`
+			INSERT INTO song_timings (song_id, default_seconds, slide_seconds, loop, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, timing.SongID, timing.DefaultSeconds, pq.Array(seconds), timing.Loop, timing.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing song timing: %w", err)
+		}
+		result.SongTimings++
+	}
+
+	for _, timed := range archive.TimedLyrics {
+		lines, err := json.Marshal(timed.Lines)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding timed lyrics: %w", err)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO song_timed_lyrics (song_id, lines, offset_ms, updated_at)
+			VALUES ($1, $2, $3, $4)
+		`, timed.SongID, lines, timed.OffsetMs, timed.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing timed lyrics: %w", err)
+		}
+		result.TimedLyrics++
+	}
+
+	for _, v := range archive.Variants {
+		_, err := tx.Exec(`
+			INSERT INTO song_lyric_variants (song_id, variant, lyrics, generated, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, v.SongID, v.Variant, v.Lyrics, v.Generated, v.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing lyric variant: %w", err)
+		}
+		result.Variants++
+	}
+
+	for _, track := range archive.SongAudio {
+		_, err := tx.Exec(`
+			INSERT INTO song_audio_tracks (song_id, url, file_name, propresenter_audio, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, track.SongID, track.URL, track.FileName, track.ProPresenterAudio, track.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing song audio: %w", err)
+		}
+		result.SongAudio++
+	}
+
+	for _, setlist := range archive.Setlists {
+		var id int
+		err := tx.QueryRow(`
+			INSERT INTO setlists (name, service_date, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, setlist.Name, setlist.ServiceDate, setlist.CreatedAt, setlist.UpdatedAt).Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("error importing setlist %q: %w", setlist.Name, err)
+		}
+		if err := replaceSetlistSongs(tx, id, setlist.SongIDs); err != nil {
+			return nil, err
+		}
+		result.Setlists++
+	}
+
+	if s := archive.Settings; s != nil {
+		res, err := tx.Exec(`
+			UPDATE settings
+			SET propresenter_host = $1, propresenter_port = $2, propresenter_playlist = $3, rehearsal_playlist = $4,
+			    ccli_license = $5, copyright_slide = $6, updated_at = NOW()
+			WHERE id = 1
+		`, s.ProPresenterHost, s.ProPresenterPort, s.ProPresenterPlaylist, s.RehearsalPlaylist, s.CCLILicense, s.CopyrightSlide)
+		if err != nil {
+			return nil, fmt.Errorf("error importing settings: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			_, err = tx.Exec(`
+				INSERT INTO settings (id, propresenter_host, propresenter_port, propresenter_playlist, rehearsal_playlist, ccli_license, copyright_slide)
+				VALUES (1, $1, $2, $3, $4, $5, $6)
+			`, s.ProPresenterHost, s.ProPresenterPort, s.ProPresenterPlaylist, s.RehearsalPlaylist, s.CCLILicense, s.CopyrightSlide)
+			if err != nil {
+				return nil, fmt.Errorf("error importing settings: %w", err)
+			}
+		}
+		result.Settings = true
+	}
+
+	for _, usage := range archive.SongUsage {
+		_, err := tx.Exec(`
+			INSERT INTO song_usage (song_id, service_date, used_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (song_id, service_date) DO NOTHING
+		`, usage.SongID, usage.ServiceDate, usage.UsedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing song usage: %w", err)
+		}
+		result.SongUsage++
+	}
+
+	for _, usage := range archive.CCLIUsage {
+		_, err := tx.Exec(`
+			INSERT INTO ccli_usage (song_id, title, artist, copyright, ccli_number, used_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, usage.SongID, usage.Title, usage.Artist, usage.Copyright, usage.CCLINumber, usage.UsedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error importing CCLI usage: %w", err)
+		}
+		result.CCLIUsage++
+	}
+
+	for _, service := range archive.Services {
+		var report interface{}
+		if len(service.Report) > 0 {
+			report = []byte(service.Report)
+		}
+
+		var id int
+		err := tx.QueryRow(`
+			INSERT INTO services (name, service_date, status, report, started_at, archived_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, service.Name, service.ServiceDate, service.Status, report, service.StartedAt, service.ArchivedAt).Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("error importing service %q: %w", service.Name, err)
+		}
+
+		for _, event := range service.Events {
+			_, err := tx.Exec(`
+				INSERT INTO service_events (service_id, event_type, song_id, title, operator, message, occurred_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+			`, id, event.EventType, event.SongID, event.Title, event.Operator, event.Message, event.OccurredAt)
+			if err != nil {
+				return nil, fmt.Errorf("error importing service event: %w", err)
+			}
+		}
+		result.Services++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing import: %w", err)
+	}
+
+	return result, nil
+}
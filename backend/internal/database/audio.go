@@ -0,0 +1,59 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const audioColumns = `song_id, url, file_name, propresenter_audio, updated_at`
+
+func scanSongAudio(row interface{ Scan(...interface{}) error }) (*models.SongAudio, error) {
+	var track models.SongAudio
+	if err := row.Scan(&track.SongID, &track.URL, &track.FileName, &track.ProPresenterAudio, &track.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
+
+// GetSongAudio returns a song's audio track, or nil if it has none
+func (db *DB) GetSongAudio(songID string) (*models.SongAudio, error) {
+	track, err := scanSongAudio(db.QueryRow(`SELECT `+audioColumns+` FROM song_audio_tracks WHERE song_id = $1`, songID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting song audio: %w", err)
+	}
+	return track, nil
+}
+
+// SetSongAudio creates or replaces a song's audio track. Empty strings are
+// stored as NULL.
+func (db *DB) SetSongAudio(track *models.SongAudio) (*models.SongAudio, error) {
+	saved, err := scanSongAudio(db.QueryRow(`
+		INSERT INTO song_audio_tracks (song_id, url, file_name, propresenter_audio, updated_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), NOW())
+		ON CONFLICT (song_id) DO UPDATE
+		SET url = EXCLUDED.url, file_name = EXCLUDED.file_name,
+		    propresenter_audio = EXCLUDED.propresenter_audio, updated_at = NOW()
+		RETURNING `+audioColumns,
+		track.SongID, track.URL, track.FileName, track.ProPresenterAudio))
+	if err != nil {
+		return nil, fmt.Errorf("error saving song audio: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteSongAudio removes a song's audio track
+func (db *DB) DeleteSongAudio(songID string) error {
+	result, err := db.Exec(`DELETE FROM song_audio_tracks WHERE song_id = $1`, songID)
+	if err != nil {
+		return fmt.Errorf("error deleting song audio: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("audio not found")
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"fmt"
+
+	pq "github.com/lib/pq"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// FindSongsForBulkDelete returns the songs matching a bulk delete's filters,
+// by title
+func (db *DB) FindSongsForBulkDelete(req *models.BulkDeleteRequest) ([]models.Song, error) {
+	query := `SELECT ` + songColumns + ` FROM songs s WHERE s.deleted_at IS NULL`
+	args := []interface{}{}
+	argPos := 1
+
+	if req.Language != "" {
+		query += fmt.Sprintf(" AND s.language = $%d", argPos)
+		args = append(args, req.Language)
+		argPos++
+	}
+	if req.Library != "" {
+		query += fmt.Sprintf(" AND s.library = $%d", argPos)
+		args = append(args, req.Library)
+		argPos++
+	}
+	if req.NotUsedSince != nil {
+		query += fmt.Sprintf(" AND NOT EXISTS (SELECT 1 FROM song_usage u WHERE u.song_id = s.id AND u.service_date >= $%d::date)", argPos)
+		args = append(args, *req.NotUsedSince)
+		argPos++
+	}
+	query += " ORDER BY s.title, s.id"
+
+	return db.querySongs(query, args...)
+}
+
+// DeleteSongs moves songs to the trash by ID and returns how many were moved
+func (db *DB) DeleteSongs(ids []string) (int64, error) {
+	result, err := db.Exec(`UPDATE songs SET deleted_at = NOW() WHERE id = ANY($1) AND deleted_at IS NULL`, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("error deleting songs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// querySongs runs a query selecting songColumns and scans the songs
+func (db *DB) querySongs(query string, args ...interface{}) ([]models.Song, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting songs: %w", err)
+	}
+	defer rows.Close()
+
+	songs := make([]models.Song, 0)
+	for rows.Next() {
+		var song models.Song
+		if err := rows.Scan(songFields(&song)...); err != nil {
+			return nil, fmt.Errorf("error scanning song: %w", err)
+		}
+		songs = append(songs, song)
+	}
+	return songs, rows.Err()
+}
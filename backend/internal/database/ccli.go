@@ -0,0 +1,57 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// RecordCCLIUsage logs a reported use of a song, copying the details CCLI needs
+func (db *DB) RecordCCLIUsage(song *models.Song) error {
+	_, err := db.Exec(`
+		INSERT INTO ccli_usage (song_id, title, artist, copyright, ccli_number, used_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+	`, song.ID, song.Title, song.Artist, song.Copyright, song.CCLINumber)
+	if err != nil {
+		return fmt.Errorf("error recording CCLI usage: %w", err)
+	}
+	return nil
+}
+
+// GetCCLIReport totals reported uses between two dates (YYYY-MM-DD, both
+// inclusive). A song used several times on one day counts once, and songs are
+// grouped by CCLI number when they have one so edited titles don't split them.
+func (db *DB) GetCCLIReport(from, to string) ([]models.CCLIReportRow, error) {
+	rows, err := db.Query(`
+		SELECT COALESCE(MAX(ccli_number), ''),
+		       MAX(CASE WHEN latest = 1 THEN title END),
+		       MAX(CASE WHEN latest = 1 THEN artist END),
+		       MAX(CASE WHEN latest = 1 THEN copyright END),
+		       COUNT(DISTINCT used_at::date),
+		       TO_CHAR(MIN(used_at), 'YYYY-MM-DD'),
+		       TO_CHAR(MAX(used_at), 'YYYY-MM-DD')
+		FROM (
+			-- latest = 1 marks each song's most recent use, whose details are reported
+			SELECT *, COALESCE('#' || ccli_number, LOWER(title)) AS song,
+			       ROW_NUMBER() OVER (PARTITION BY COALESCE('#' || ccli_number, LOWER(title)) ORDER BY used_at DESC) AS latest
+			FROM ccli_usage
+			WHERE used_at::date BETWEEN $1::date AND $2::date
+		) uses
+		GROUP BY song
+		ORDER BY 2
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("error getting CCLI report: %w", err)
+	}
+	defer rows.Close()
+
+	report := make([]models.CCLIReportRow, 0)
+	for rows.Next() {
+		var r models.CCLIReportRow
+		if err := rows.Scan(&r.CCLINumber, &r.Title, &r.Artist, &r.Copyright, &r.Digital, &r.FirstUsed, &r.LastUsed); err != nil {
+			return nil, fmt.Errorf("error scanning CCLI report: %w", err)
+		}
+		report = append(report, r)
+	}
+	return report, rows.Err()
+}
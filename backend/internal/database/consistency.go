@@ -0,0 +1,45 @@
+package database
+
+import (
+	"fmt"
+
+	pq "github.com/lib/pq"
+)
+
+// ExistingSongIDs returns which of ids belong to a song in the database (and
+// not in the trash)
+func (db *DB) ExistingSongIDs(ids []string) (map[string]bool, error) {
+	existing := make(map[string]bool, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	rows, err := db.Query(`SELECT id FROM songs WHERE id::text = ANY($1) AND deleted_at IS NULL`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error checking song ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning song id: %w", err)
+		}
+		existing[id] = true
+	}
+	return existing, rows.Err()
+}
+
+// SetSongProUUID links a song to a ProPresenter library item, or unlinks it
+// when uuid is nil. The song's updated_at is left alone since its content
+// hasn't changed.
+func (db *DB) SetSongProUUID(id string, uuid *string) error {
+	result, err := db.Exec(`UPDATE songs SET pro_uuid = $1 WHERE id = $2 AND deleted_at IS NULL`, uuid, id)
+	if err != nil {
+		return fmt.Errorf("error updating pro_uuid: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("song not found")
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	pq "github.com/lib/pq"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const cueColumns = `song_id, intro_bars, starts_with, dynamics, ending, updated_at`
+
+func scanSongCues(row interface{ Scan(...interface{}) error }) (*models.SongCues, error) {
+	var cues models.SongCues
+	if err := row.Scan(&cues.SongID, &cues.IntroBars, &cues.StartsWith, &cues.Dynamics, &cues.Ending, &cues.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &cues, nil
+}
+
+// GetSongCues returns a song's cues, or nil if none are set
+func (db *DB) GetSongCues(songID string) (*models.SongCues, error) {
+	cues, err := scanSongCues(db.QueryRow(`SELECT `+cueColumns+` FROM song_cues WHERE song_id = $1`, songID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting song cues: %w", err)
+	}
+	return cues, nil
+}
+
+// GetCuesForSongs returns the cues of the given songs keyed by song ID; songs
+// without cues are left out
+func (db *DB) GetCuesForSongs(songIDs []string) (map[string]*models.SongCues, error) {
+	cues := make(map[string]*models.SongCues)
+	if len(songIDs) == 0 {
+		return cues, nil
+	}
+
+	rows, err := db.Query(`SELECT `+cueColumns+` FROM song_cues WHERE song_id::text = ANY($1)`, pq.Array(songIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error getting song cues: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanSongCues(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning song cues: %w", err)
+		}
+		cues[c.SongID] = c
+	}
+	return cues, rows.Err()
+}
+
+// SetSongCues creates or replaces a song's cues
+func (db *DB) SetSongCues(songID string, req *models.SongCuesRequest) (*models.SongCues, error) {
+	cues, err := scanSongCues(db.QueryRow(`
+		INSERT INTO song_cues (song_id, intro_bars, starts_with, dynamics, ending, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (song_id) DO UPDATE
+		SET intro_bars = EXCLUDED.intro_bars, starts_with = EXCLUDED.starts_with,
+		    dynamics = EXCLUDED.dynamics, ending = EXCLUDED.ending, updated_at = NOW()
+		RETURNING `+cueColumns,
+		songID, req.IntroBars, req.StartsWith, req.Dynamics, req.Ending))
+	if err != nil {
+		return nil, fmt.Errorf("error saving song cues: %w", err)
+	}
+	return cues, nil
+}
+
+// DeleteSongCues clears a song's cues
+func (db *DB) DeleteSongCues(songID string) error {
+	result, err := db.Exec(`DELETE FROM song_cues WHERE song_id = $1`, songID)
+	if err != nil {
+		return fmt.Errorf("error deleting song cues: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("cues not found")
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	pq "github.com/lib/pq"
@@ -12,9 +14,20 @@ import (
 
 type DB struct {
 	*sql.DB
+	dialect dialect
 }
 
+// New connects to the PostgreSQL database at dsn, or opens the SQLite file
+// of a dsn starting with SQLitePrefix
 func New(dsn string) (*DB, error) {
+	if path, ok := strings.CutPrefix(dsn, SQLitePrefix); ok {
+		db, err := openSQLite(path)
+		if err == nil {
+			log.Printf("SQLite database opened: %s", path)
+		}
+		return db, err
+	}
+
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("error opening database: %w", err)
@@ -31,20 +44,54 @@ func New(dsn string) (*DB, error) {
 	}
 
 	log.Println("Database connection established")
-	return &DB{db}, nil
+	return &DB{DB: db, dialect: postgresDialect{}}, nil
+}
+
+// Embedded reports whether the database is a SQLite file rather than a
+// PostgreSQL server
+func (db *DB) Embedded() bool {
+	_, ok := db.dialect.(sqliteDialect)
+	return ok
+}
+
+// songColumns is the column list matching songFields, for SELECT and RETURNING clauses
+const songColumns = `id, title, file_name, library, language, pro_uuid, display_lyrics, music_ministry_lyrics, artist,
+		original_key, performance_key, bpm, time_signature, count_in_beats, background_media, look, copyright, ccli_number, chords, public, archived_at, deleted_at, created_at, updated_at`
+
+// prefixedSongColumns qualifies songColumns with a table alias for joins
+func prefixedSongColumns(alias string) string {
+	cols := strings.Split(songColumns, ",")
+	for i, col := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(col)
+	}
+	return strings.Join(cols, ", ")
+}
+
+// songFields returns scan destinations for songColumns
+func songFields(song *models.Song) []interface{} {
+	return []interface{}{
+		&song.ID, &song.Title, &song.FileName, &song.Library, &song.Language, &song.ProUUID,
+		&song.DisplayLyrics, &song.MusicMinistryLyrics, &song.Artist,
+		&song.OriginalKey, &song.PerformanceKey, &song.BPM, &song.TimeSignature, &song.CountInBeats,
+		&song.BackgroundMedia, &song.Look, &song.Copyright, &song.CCLINumber, &song.Chords, &song.Public, &song.ArchivedAt, &song.DeletedAt,
+		&song.CreatedAt, &song.UpdatedAt,
+	}
+}
+
+const insertSongQuery = `
+		INSERT INTO songs (title, file_name, library, language, pro_uuid, display_lyrics, music_ministry_lyrics, artist, original_key, performance_key, bpm, time_signature, count_in_beats, background_media, look, copyright, ccli_number, chords, public, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NULLIF($14, ''), NULLIF($15, ''), NULLIF($16, ''), NULLIF($17, ''), NULLIF($18, ''), $19, NOW(), NOW())
+		RETURNING ` + songColumns
+
+func insertSongArgs(song *models.CreateSongRequest) []interface{} {
+	return []interface{}{song.Title, song.FileName, song.Library, song.Language, song.ProUUID, song.DisplayLyrics, song.MusicMinistryLyrics, song.Artist, song.OriginalKey, song.PerformanceKey, song.BPM, song.TimeSignature, song.CountInBeats, song.BackgroundMedia, song.Look, song.Copyright, song.CCLINumber, song.Chords, song.Public}
 }
 
 // CreateSong inserts a new song into the database
 func (db *DB) CreateSong(song *models.CreateSongRequest) (*models.Song, error) {
-	query := `
-		INSERT INTO songs (title, file_name, library, language, pro_uuid, display_lyrics, music_ministry_lyrics, artist, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
-		RETURNING id, title, file_name, library, language, pro_uuid, display_lyrics, music_ministry_lyrics, artist, created_at, updated_at
-	`
-
 	var result models.Song
-	err := db.QueryRow(query, song.Title, song.FileName, song.Library, song.Language, song.ProUUID, song.DisplayLyrics, song.MusicMinistryLyrics, song.Artist).
-		Scan(&result.ID, &result.Title, &result.FileName, &result.Library, &result.Language, &result.ProUUID, &result.DisplayLyrics, &result.MusicMinistryLyrics, &result.Artist, &result.CreatedAt, &result.UpdatedAt)
+	err := db.QueryRow(insertSongQuery, insertSongArgs(song)...).
+		Scan(songFields(&result)...)
 
 	if err != nil {
 		return nil, fmt.Errorf("error creating song: %w", err)
@@ -53,17 +100,67 @@ func (db *DB) CreateSong(song *models.CreateSongRequest) (*models.Song, error) {
 	return &result, nil
 }
 
+// CreateSongs inserts many songs in one transaction, so either all of them
+// are saved or none are. The songs are returned in the order given.
+func (db *DB) CreateSongs(songs []models.CreateSongRequest) ([]models.Song, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(insertSongQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing song insert: %w", err)
+	}
+	defer stmt.Close()
+
+	created := make([]models.Song, len(songs))
+	for i := range songs {
+		if err := stmt.QueryRow(insertSongArgs(&songs[i])...).Scan(songFields(&created[i])...); err != nil {
+			return nil, fmt.Errorf("error creating song %q: %w", songs[i].Title, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing songs: %w", err)
+	}
+	return created, nil
+}
+
 // GetSong retrieves a song by ID
 func (db *DB) GetSong(id string) (*models.Song, error) {
 	query := `
-		SELECT id, title, file_name, library, language, pro_uuid, display_lyrics, music_ministry_lyrics, artist, created_at, updated_at
+		SELECT ` + songColumns + `
 		FROM songs
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var song models.Song
 	err := db.QueryRow(query, id).
-		Scan(&song.ID, &song.Title, &song.FileName, &song.Library, &song.Language, &song.ProUUID, &song.DisplayLyrics, &song.MusicMinistryLyrics, &song.Artist, &song.CreatedAt, &song.UpdatedAt)
+		Scan(songFields(&song)...)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("song not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting song: %w", err)
+	}
+
+	return &song, nil
+}
+
+// GetSongByProUUID retrieves the song linked to a ProPresenter library item
+func (db *DB) GetSongByProUUID(proUUID string) (*models.Song, error) {
+	query := `
+		SELECT ` + songColumns + `
+		FROM songs
+		WHERE pro_uuid::text = LOWER($1) AND deleted_at IS NULL
+	`
+
+	var song models.Song
+	err := db.QueryRow(query, proUUID).
+		Scan(songFields(&song)...)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("song not found")
@@ -78,8 +175,9 @@ func (db *DB) GetSong(id string) (*models.Song, error) {
 // GetAllSongs retrieves all songs
 func (db *DB) GetAllSongs() ([]models.Song, error) {
 	query := `
-		SELECT id, title, file_name, library, language, pro_uuid, display_lyrics, music_ministry_lyrics, artist, created_at, updated_at
+		SELECT ` + songColumns + `
 		FROM songs
+		WHERE deleted_at IS NULL
 		ORDER BY updated_at DESC
 	`
 
@@ -92,7 +190,7 @@ func (db *DB) GetAllSongs() ([]models.Song, error) {
 	var songs []models.Song
 	for rows.Next() {
 		var song models.Song
-		err := rows.Scan(&song.ID, &song.Title, &song.FileName, &song.Library, &song.Language, &song.ProUUID, &song.DisplayLyrics, &song.MusicMinistryLyrics, &song.Artist, &song.CreatedAt, &song.UpdatedAt)
+		err := rows.Scan(songFields(&song)...)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning song: %w", err)
 		}
@@ -102,21 +200,161 @@ func (db *DB) GetAllSongs() ([]models.Song, error) {
 	return songs, nil
 }
 
+// EachSong calls fn for every song in title order, reading rows one at a time
+// so large libraries are never held in memory. An error from fn stops the scan.
+func (db *DB) EachSong(fn func(*models.Song) error) error {
+	return db.eachSong("title ASC, id ASC", fn)
+}
+
+func (db *DB) eachSong(orderBy string, fn func(*models.Song) error) error {
+	return db.eachSongWhere("TRUE", nil, orderBy, fn)
+}
+
+// EachFilteredSong is EachSong for the songs matching a filter, in its order,
+// limited to the filter's page if it has one
+func (db *DB) EachFilteredSong(filter *models.SongFilter, fn func(*models.Song) error) error {
+	where, args := songFilterWhere(filter)
+
+	sort, direction := "updated_at", "DESC"
+	if filter.Sort != "" {
+		if !models.SongSortFields[filter.Sort] {
+			return fmt.Errorf("can't sort songs by %s", filter.Sort)
+		}
+		sort, direction = filter.Sort, "ASC"
+		if filter.Descending {
+			direction = "DESC"
+		}
+	}
+	// Nulls (songs without an artist) go last either way; id keeps ties stable
+	orderBy := fmt.Sprintf("%s %s NULLS LAST, id %s", sort, direction, direction)
+	if filter.Limit > 0 {
+		orderBy += fmt.Sprintf(" LIMIT %d OFFSET %d", filter.Limit, filter.Offset)
+	}
+
+	return db.eachSongWhere(where, args, orderBy, fn)
+}
+
+// CountFilteredSongs counts the songs matching a filter, ignoring its page
+func (db *DB) CountFilteredSongs(filter *models.SongFilter) (int, error) {
+	where, args := songFilterWhere(filter)
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM songs WHERE deleted_at IS NULL AND `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting songs: %w", err)
+	}
+	return count, nil
+}
+
+// SongLibraryVersion changes whenever songs are added, edited, archived,
+// trashed or restored, so a cache of the library can tell when it's stale
+type SongLibraryVersion struct {
+	Songs    int       // songs outside the trash
+	Archived int       // of which archived
+	Updated  time.Time // newest updated_at, zero for an empty library
+}
+
+// GetSongLibraryVersion returns the library's current version
+func (db *DB) GetSongLibraryVersion() (SongLibraryVersion, error) {
+	var version SongLibraryVersion
+	var updated sql.NullTime
+	err := db.QueryRow(`
+		SELECT COUNT(*), COUNT(archived_at), MAX(updated_at)
+		FROM songs WHERE deleted_at IS NULL
+	`).Scan(&version.Songs, &version.Archived, &updated)
+	if err != nil {
+		return version, fmt.Errorf("error getting library version: %w", err)
+	}
+	if updated.Valid {
+		version.Updated = updated.Time
+	}
+	return version, nil
+}
+
+// songFilterWhere builds the condition and arguments for a filter's songs
+func songFilterWhere(filter *models.SongFilter) (string, []interface{}) {
+	where := "TRUE"
+	args := []interface{}{}
+	argPos := 1
+
+	if len(filter.Languages) > 0 {
+		where += fmt.Sprintf(" AND language = ANY($%d)", argPos)
+		args = append(args, pq.Array(filter.Languages))
+		argPos++
+	}
+	if filter.Library != "" {
+		where += fmt.Sprintf(" AND library = $%d", argPos)
+		args = append(args, filter.Library)
+		argPos++
+	}
+	if filter.UpdatedSince != nil {
+		where += fmt.Sprintf(" AND updated_at >= $%d", argPos)
+		args = append(args, *filter.UpdatedSince)
+		argPos++
+	}
+	if filter.Archived != nil {
+		if *filter.Archived {
+			where += " AND archived_at IS NOT NULL"
+		} else {
+			where += " AND archived_at IS NULL"
+		}
+	}
+	return where, args
+}
+
+func (db *DB) eachSongWhere(where string, args []interface{}, orderBy string, fn func(*models.Song) error) error {
+	query := `
+		SELECT ` + songColumns + `
+		FROM songs
+		WHERE deleted_at IS NULL AND ` + where + `
+		ORDER BY ` + orderBy + `
+	`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("error getting songs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var song models.Song
+		if err := rows.Scan(songFields(&song)...); err != nil {
+			return fmt.Errorf("error scanning song: %w", err)
+		}
+		if err := fn(&song); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 // SearchSongs performs a DB search with optional language filter and text query.
-// If query is empty, only language filtering is applied.
-func (db *DB) SearchSongs(query string, languages []string) ([]models.Song, error) {
+// If query is empty, only language filtering is applied. Words are matched as
+// prefixes against the full-text index and results ranked title first; a
+// query without words (only punctuation) falls back to a substring match.
+func (db *DB) SearchSongs(query string, languages []string, includeArchived bool) ([]models.Song, error) {
 	base := `
-		SELECT id, title, file_name, library, language, pro_uuid, display_lyrics, music_ministry_lyrics, artist, created_at, updated_at
+		SELECT ` + songColumns + `
 		FROM songs
-		WHERE 1=1
+		WHERE deleted_at IS NULL
 	`
+	if !includeArchived {
+		base += " AND archived_at IS NULL"
+	}
 	args := []interface{}{}
 	argPos := 1
+	order := "updated_at DESC"
 
 	if query != "" && query != "*" {
-		base += fmt.Sprintf(" AND (title ILIKE $%d OR artist ILIKE $%d OR display_lyrics ILIKE $%d OR music_ministry_lyrics ILIKE $%d)", argPos, argPos, argPos, argPos)
-		args = append(args, "%"+query+"%")
-		argPos++
+		if where, rank, words := db.dialect.fullText(query, argPos); where != "" {
+			base += " AND " + where
+			order = rank
+			args = append(args, words...)
+			argPos += len(words)
+		} else {
+			base += fmt.Sprintf(" AND (title ILIKE $%d OR artist ILIKE $%d OR display_lyrics ILIKE $%d OR music_ministry_lyrics ILIKE $%d)", argPos, argPos, argPos, argPos)
+			args = append(args, "%"+query+"%")
+			argPos++
+		}
 	}
 
 	if len(languages) > 0 {
@@ -125,7 +363,7 @@ func (db *DB) SearchSongs(query string, languages []string) ([]models.Song, erro
 		argPos++
 	}
 
-	base += " ORDER BY updated_at DESC"
+	base += " ORDER BY " + order
 
 	rows, err := db.Query(base, args...)
 	if err != nil {
@@ -136,7 +374,7 @@ func (db *DB) SearchSongs(query string, languages []string) ([]models.Song, erro
 	var songs []models.Song
 	for rows.Next() {
 		var song models.Song
-		if err := rows.Scan(&song.ID, &song.Title, &song.FileName, &song.Library, &song.Language, &song.ProUUID, &song.DisplayLyrics, &song.MusicMinistryLyrics, &song.Artist, &song.CreatedAt, &song.UpdatedAt); err != nil {
+		if err := rows.Scan(songFields(&song)...); err != nil {
 			return nil, fmt.Errorf("error scanning song: %w", err)
 		}
 		songs = append(songs, song)
@@ -145,8 +383,32 @@ func (db *DB) SearchSongs(query string, languages []string) ([]models.Song, erro
 	return songs, nil
 }
 
+// nullableSongColumns are the song columns an update can set to null
+var nullableSongColumns = map[string]bool{
+	"artist":           true,
+	"original_key":     true,
+	"performance_key":  true,
+	"bpm":              true,
+	"time_signature":   true,
+	"count_in_beats":   true,
+	"background_media": true,
+	"look":             true,
+	"copyright":        true,
+	"ccli_number":      true,
+	"chords":           true,
+}
+
 // UpdateSong updates an existing song
 func (db *DB) UpdateSong(id string, updates *models.UpdateSongRequest) (*models.Song, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := lockSongForRevision(tx, id); err != nil {
+		return nil, err
+	}
+
 	// Build dynamic update query
 	query := `UPDATE songs SET updated_at = NOW()`
 	args := []interface{}{}
@@ -182,13 +444,75 @@ func (db *DB) UpdateSong(id string, updates *models.UpdateSongRequest) (*models.
 		args = append(args, *updates.MusicMinistryLyrics)
 		argCount++
 	}
+	if updates.OriginalKey != nil {
+		query += fmt.Sprintf(", original_key = NULLIF($%d, '')", argCount)
+		args = append(args, *updates.OriginalKey)
+		argCount++
+	}
+	if updates.PerformanceKey != nil {
+		query += fmt.Sprintf(", performance_key = NULLIF($%d, '')", argCount)
+		args = append(args, *updates.PerformanceKey)
+		argCount++
+	}
+	// Zero clears the tempo fields
+	if updates.BPM != nil {
+		query += fmt.Sprintf(", bpm = NULLIF($%d, 0)", argCount)
+		args = append(args, *updates.BPM)
+		argCount++
+	}
+	if updates.TimeSignature != nil {
+		query += fmt.Sprintf(", time_signature = NULLIF($%d, '')", argCount)
+		args = append(args, *updates.TimeSignature)
+		argCount++
+	}
+	if updates.CountInBeats != nil {
+		query += fmt.Sprintf(", count_in_beats = $%d", argCount)
+		args = append(args, *updates.CountInBeats)
+		argCount++
+	}
+	if updates.BackgroundMedia != nil {
+		query += fmt.Sprintf(", background_media = NULLIF($%d, '')", argCount)
+		args = append(args, *updates.BackgroundMedia)
+		argCount++
+	}
+	if updates.Look != nil {
+		query += fmt.Sprintf(", look = NULLIF($%d, '')", argCount)
+		args = append(args, *updates.Look)
+		argCount++
+	}
+	if updates.Copyright != nil {
+		query += fmt.Sprintf(", copyright = NULLIF($%d, '')", argCount)
+		args = append(args, *updates.Copyright)
+		argCount++
+	}
+	if updates.CCLINumber != nil {
+		query += fmt.Sprintf(", ccli_number = NULLIF($%d, '')", argCount)
+		args = append(args, *updates.CCLINumber)
+		argCount++
+	}
+	if updates.Chords != nil {
+		query += fmt.Sprintf(", chords = NULLIF($%d, '')", argCount)
+		args = append(args, *updates.Chords)
+		argCount++
+	}
+	if updates.Public != nil {
+		query += fmt.Sprintf(", public = $%d", argCount)
+		args = append(args, *updates.Public)
+		argCount++
+	}
+	for _, field := range updates.Clear {
+		if !nullableSongColumns[field] {
+			return nil, fmt.Errorf("%s can't be cleared", field)
+		}
+		query += ", " + field + " = NULL"
+	}
 
-	query += fmt.Sprintf(" WHERE id = $%d RETURNING id, title, file_name, library, language, pro_uuid, display_lyrics, music_ministry_lyrics, artist, created_at, updated_at", argCount)
+	query += fmt.Sprintf(" WHERE id = $%d RETURNING "+songColumns, argCount)
 	args = append(args, id)
 
 	var song models.Song
-	err := db.QueryRow(query, args...).
-		Scan(&song.ID, &song.Title, &song.FileName, &song.Library, &song.Language, &song.ProUUID, &song.DisplayLyrics, &song.MusicMinistryLyrics, &song.Artist, &song.CreatedAt, &song.UpdatedAt)
+	err = tx.QueryRow(query, args...).
+		Scan(songFields(&song)...)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("song not found")
@@ -197,12 +521,31 @@ func (db *DB) UpdateSong(id string, updates *models.UpdateSongRequest) (*models.
 		return nil, fmt.Errorf("error updating song: %w", err)
 	}
 
+	// Songbook references and links are replaced in the same transaction,
+	// so the edit is saved whole or not at all
+	if updates.Numbers != nil {
+		if err := setSongNumbers(tx, id, *updates.Numbers); err != nil {
+			return nil, err
+		}
+	}
+	if updates.Links != nil {
+		if err := setSongLinks(tx, id, *updates.Links); err != nil {
+			return nil, err
+		}
+	}
+	if err := recordSongRevision(tx, id, updates.EditedBy, updates.RevisionNote); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing song update: %w", err)
+	}
+
 	return &song, nil
 }
 
-// DeleteSong deletes a song by ID
+// DeleteSong moves a song to the trash by ID
 func (db *DB) DeleteSong(id string) error {
-	query := `DELETE FROM songs WHERE id = $1`
+	query := `UPDATE songs SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 	result, err := db.Exec(query, id)
 	if err != nil {
 		return fmt.Errorf("error deleting song: %w", err)
@@ -220,17 +563,6 @@ func (db *DB) DeleteSong(id string) error {
 	return nil
 }
 
-// GetEditCount returns the total number of edits (inserts + updates)
-func (db *DB) GetEditCount() (int, error) {
-	var count int
-	query := `SELECT COUNT(*) FROM songs`
-	err := db.QueryRow(query).Scan(&count)
-	if err != nil {
-		return 0, fmt.Errorf("error getting edit count: %w", err)
-	}
-	return count, nil
-}
-
 // GetSettings retrieves the settings (there's only one row with id=1)
 func (db *DB) GetSettings() (*models.Settings, error) {
 	query := `
@@ -239,16 +571,25 @@ func (db *DB) GetSettings() (*models.Settings, error) {
 		       COALESCE(propresenter_port, 4031) as propresenter_port,
 		       COALESCE(propresenter_playlist, 'Live Queue') as propresenter_playlist,
 		       COALESCE(propresenter_playlist_uuid::text, '00000000-0000-0000-0000-000000000000') as propresenter_playlist_uuid,
+		       COALESCE(rehearsal_playlist, 'Rehearsal') as rehearsal_playlist,
+		       COALESCE(ccli_license, '') as ccli_license,
+		       COALESCE(copyright_slide, FALSE) as copyright_slide,
+		       COALESCE(search_field_locales, '{}') as search_field_locales,
+		       COALESCE(search_token_separators, '') as search_token_separators,
+		       COALESCE(propresenter_health_interval, 30) as propresenter_health_interval,
 		       updated_at
 		FROM settings
 		WHERE id = 1
 	`
 
 	var settings models.Settings
+	var fieldLocales []byte
 	err := db.QueryRow(query).
 		Scan(&settings.ID, &settings.LaptopBIP, &settings.LaptopBPort, &settings.LivePlaylistUUID,
 			&settings.ProPresenterHost, &settings.ProPresenterPort, &settings.ProPresenterPlaylist,
-			&settings.ProPresenterPlaylistUUID, &settings.UpdatedAt)
+			&settings.ProPresenterPlaylistUUID, &settings.RehearsalPlaylist,
+			&settings.CCLILicense, &settings.CopyrightSlide, &fieldLocales, &settings.SearchTokenSeparators,
+			&settings.ProPresenterHealthInterval, &settings.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		// Create default settings if none exist
@@ -258,6 +599,10 @@ func (db *DB) GetSettings() (*models.Settings, error) {
 		return nil, fmt.Errorf("error getting settings: %w", err)
 	}
 
+	if err := json.Unmarshal(fieldLocales, &settings.SearchFieldLocales); err != nil {
+		return nil, fmt.Errorf("error decoding search field locales: %w", err)
+	}
+
 	return &settings, nil
 }
 
@@ -272,19 +617,32 @@ func (db *DB) createDefaultSettings() (*models.Settings, error) {
 		          COALESCE(propresenter_port, 4031) as propresenter_port,
 		          COALESCE(propresenter_playlist, 'Live Queue') as propresenter_playlist,
 		          COALESCE(propresenter_playlist_uuid::text, '00000000-0000-0000-0000-000000000000') as propresenter_playlist_uuid,
+		          COALESCE(rehearsal_playlist, 'Rehearsal') as rehearsal_playlist,
+		          COALESCE(ccli_license, '') as ccli_license,
+		          COALESCE(copyright_slide, FALSE) as copyright_slide,
+		          COALESCE(search_field_locales, '{}') as search_field_locales,
+		          COALESCE(search_token_separators, '') as search_token_separators,
+		          COALESCE(propresenter_health_interval, 30) as propresenter_health_interval,
 		          updated_at
 	`
 
 	var settings models.Settings
+	var fieldLocales []byte
 	err := db.QueryRow(query).
 		Scan(&settings.ID, &settings.LaptopBIP, &settings.LaptopBPort, &settings.LivePlaylistUUID,
 			&settings.ProPresenterHost, &settings.ProPresenterPort, &settings.ProPresenterPlaylist,
-			&settings.ProPresenterPlaylistUUID, &settings.UpdatedAt)
+			&settings.ProPresenterPlaylistUUID, &settings.RehearsalPlaylist,
+			&settings.CCLILicense, &settings.CopyrightSlide, &fieldLocales, &settings.SearchTokenSeparators,
+			&settings.ProPresenterHealthInterval, &settings.UpdatedAt)
 
 	if err != nil {
 		return nil, fmt.Errorf("error creating default settings: %w", err)
 	}
 
+	if err := json.Unmarshal(fieldLocales, &settings.SearchFieldLocales); err != nil {
+		return nil, fmt.Errorf("error decoding search field locales: %w", err)
+	}
+
 	return &settings, nil
 }
 
@@ -309,6 +667,40 @@ func (db *DB) UpdateSettings(updates *models.UpdateSettingsRequest) (*models.Set
 		args = append(args, *updates.ProPresenterPlaylist)
 		argCount++
 	}
+	if updates.RehearsalPlaylist != nil {
+		query += fmt.Sprintf(", rehearsal_playlist = $%d", argCount)
+		args = append(args, *updates.RehearsalPlaylist)
+		argCount++
+	}
+	if updates.CCLILicense != nil {
+		query += fmt.Sprintf(", ccli_license = $%d", argCount)
+		args = append(args, *updates.CCLILicense)
+		argCount++
+	}
+	if updates.CopyrightSlide != nil {
+		query += fmt.Sprintf(", copyright_slide = $%d", argCount)
+		args = append(args, *updates.CopyrightSlide)
+		argCount++
+	}
+	if updates.SearchFieldLocales != nil {
+		fieldLocales, err := json.Marshal(*updates.SearchFieldLocales)
+		if err != nil {
+			return nil, err
+		}
+		query += fmt.Sprintf(", search_field_locales = $%d", argCount)
+		args = append(args, fieldLocales)
+		argCount++
+	}
+	if updates.SearchTokenSeparators != nil {
+		query += fmt.Sprintf(", search_token_separators = $%d", argCount)
+		args = append(args, *updates.SearchTokenSeparators)
+		argCount++
+	}
+	if updates.ProPresenterHealthInterval != nil {
+		query += fmt.Sprintf(", propresenter_health_interval = $%d", argCount)
+		args = append(args, *updates.ProPresenterHealthInterval)
+		argCount++
+	}
 	if updates.ProPresenterPlaylistUUID != nil {
 		uuidValue := *updates.ProPresenterPlaylistUUID
 		// Handle empty string as NULL/default UUID
@@ -331,13 +723,22 @@ func (db *DB) UpdateSettings(updates *models.UpdateSettingsRequest) (*models.Set
 		          COALESCE(propresenter_port, 4031) as propresenter_port,
 		          COALESCE(propresenter_playlist, 'Live Queue') as propresenter_playlist,
 		          COALESCE(propresenter_playlist_uuid::text, '00000000-0000-0000-0000-000000000000') as propresenter_playlist_uuid,
+		          COALESCE(rehearsal_playlist, 'Rehearsal') as rehearsal_playlist,
+		          COALESCE(ccli_license, '') as ccli_license,
+		          COALESCE(copyright_slide, FALSE) as copyright_slide,
+		          COALESCE(search_field_locales, '{}') as search_field_locales,
+		          COALESCE(search_token_separators, '') as search_token_separators,
+		          COALESCE(propresenter_health_interval, 30) as propresenter_health_interval,
 		          updated_at`
 
 	var settings models.Settings
+	var fieldLocales []byte
 	err := db.QueryRow(query, args...).
 		Scan(&settings.ID, &settings.LaptopBIP, &settings.LaptopBPort, &settings.LivePlaylistUUID,
 			&settings.ProPresenterHost, &settings.ProPresenterPort, &settings.ProPresenterPlaylist,
-			&settings.ProPresenterPlaylistUUID, &settings.UpdatedAt)
+			&settings.ProPresenterPlaylistUUID, &settings.RehearsalPlaylist,
+			&settings.CCLILicense, &settings.CopyrightSlide, &fieldLocales, &settings.SearchTokenSeparators,
+			&settings.ProPresenterHealthInterval, &settings.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("settings not found")
@@ -346,6 +747,10 @@ func (db *DB) UpdateSettings(updates *models.UpdateSettingsRequest) (*models.Set
 		return nil, fmt.Errorf("error updating settings: %w", err)
 	}
 
+	if err := json.Unmarshal(fieldLocales, &settings.SearchFieldLocales); err != nil {
+		return nil, fmt.Errorf("error decoding search field locales: %w", err)
+	}
+
 	return &settings, nil
 }
 
@@ -359,6 +764,7 @@ func (db *DB) GetQueue() ([]models.QueueItem, error) {
 		       s.display_lyrics, s.music_ministry_lyrics, s.artist, s.created_at, s.updated_at
 		FROM queue_items q
 		INNER JOIN songs s ON q.song_id = s.id
+		WHERE s.deleted_at IS NULL
 		ORDER BY q.position ASC
 	`
 
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	pq "github.com/lib/pq"
+)
+
+// dialect is what differs between the databases songs can be stored in:
+// PostgreSQL, and SQLite for the all-in-one mode. Queries are written for
+// PostgreSQL and translated on SQLite (see sqlite_sql.go); the ones that
+// can't be translated come from here.
+type dialect interface {
+	// migrationDir is the directory of the embedded migrations
+	migrationDir() string
+	// lockMigrations keeps other servers from migrating until unlock
+	lockMigrations(ctx context.Context, conn *sql.Conn) (unlock func(), err error)
+	// migrationsTableQuery tells whether schema_migrations exists
+	migrationsTableQuery() string
+	// schemaQuery lists the table_name, column_name pairs of the tables in
+	// the array $1
+	schemaQuery() string
+	// fullText returns the condition and ranking of a search for every word
+	// of query as a prefix, with its parameters starting at $argPos; an
+	// empty condition means the query has no words
+	fullText(query string, argPos int) (where, order string, args []interface{})
+	// numberOrder sorts song numbers n.number numerically, then by any
+	// letter suffix (12, 12a, 13)
+	numberOrder() string
+	// monthStart is the first day of the month $arg - 1 months before this one
+	monthStart(arg int) string
+}
+
+type postgresDialect struct{}
+
+// migrationLock is the advisory lock held while migrating, so servers
+// started together don't apply the same migration twice
+const migrationLock = 7246381950
+
+func (postgresDialect) migrationDir() string { return "migrations" }
+
+func (postgresDialect) lockMigrations(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLock); err != nil {
+		return nil, err
+	}
+	return func() { conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLock) }, nil
+}
+
+func (postgresDialect) migrationsTableQuery() string {
+	return `SELECT to_regclass('schema_migrations') IS NOT NULL`
+}
+
+func (postgresDialect) schemaQuery() string {
+	return `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ANY($1)
+	`
+}
+
+func (postgresDialect) fullText(query string, argPos int) (string, string, []interface{}) {
+	tsQuery := prefixTSQuery(query)
+	if tsQuery == "" {
+		return "", "", nil
+	}
+	return fmt.Sprintf("search_vector @@ to_tsquery('simple', $%d)", argPos),
+		fmt.Sprintf("ts_rank(search_vector, to_tsquery('simple', $%d)) DESC, updated_at DESC", argPos),
+		[]interface{}{tsQuery}
+}
+
+func (postgresDialect) numberOrder() string {
+	return `NULLIF(SUBSTRING(n.number FROM '^[0-9]+'), '')::int, LOWER(n.number)`
+}
+
+func (postgresDialect) monthStart(arg int) string {
+	return fmt.Sprintf("date_trunc('month', CURRENT_DATE) - make_interval(months => $%d - 1)", arg)
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) migrationDir() string { return "migrations/sqlite" }
+
+// lockMigrations has nothing to do: a SQLite file belongs to one server,
+// and each migration's transaction holds the write lock
+func (sqliteDialect) lockMigrations(context.Context, *sql.Conn) (func(), error) {
+	return func() {}, nil
+}
+
+func (sqliteDialect) migrationsTableQuery() string {
+	return `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')`
+}
+
+func (sqliteDialect) schemaQuery() string {
+	return `
+		SELECT m.name, c.name
+		FROM sqlite_master m, pragma_table_info(m.name) c
+		WHERE m.type = 'table' AND m.name = ANY($1)
+	`
+}
+
+// fullText has no index to use on SQLite: each word must appear in the
+// title, artist or lyrics, and songs with more of them in the title rank
+// first
+func (sqliteDialect) fullText(query string, argPos int) (string, string, []interface{}) {
+	words := searchWords(query)
+	if len(words) == 0 {
+		return "", "", nil
+	}
+	conditions := make([]string, len(words))
+	ranks := make([]string, len(words))
+	args := make([]interface{}, len(words))
+	for i, word := range words {
+		n := argPos + i
+		conditions[i] = fmt.Sprintf("(title LIKE $%d OR artist LIKE $%d OR display_lyrics LIKE $%d OR music_ministry_lyrics LIKE $%d)", n, n, n, n)
+		ranks[i] = fmt.Sprintf("(title LIKE $%d)", n)
+		args[i] = "%" + word + "%"
+	}
+	return strings.Join(conditions, " AND "), strings.Join(ranks, " + ") + " DESC, updated_at DESC", args
+}
+
+func (sqliteDialect) numberOrder() string {
+	return `CASE WHEN n.number GLOB '[0-9]*' THEN CAST(n.number AS INTEGER) END, LOWER(n.number)`
+}
+
+func (sqliteDialect) monthStart(arg int) string {
+	return fmt.Sprintf("date('now', 'localtime', 'start of month', '-' || ($%d - 1) || ' months')", arg)
+}
+
+// isUniqueViolation reports whether err is a unique constraint failing, on
+// either database
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const displayColumns = `name, role, resolution, user_agent, address, song_id, song_title, slide_index, registered_at, last_seen_at`
+
+func scanDisplay(row interface{ Scan(...interface{}) error }) (*models.Display, error) {
+	var d models.Display
+	var songID sql.NullString
+	var slide sql.NullInt64
+	if err := row.Scan(&d.Name, &d.Role, &d.Resolution, &d.UserAgent, &d.Address, &songID, &d.SongTitle, &slide, &d.RegisteredAt, &d.LastSeenAt); err != nil {
+		return nil, err
+	}
+	if songID.Valid {
+		d.SongID = &songID.String
+	}
+	if slide.Valid {
+		idx := int(slide.Int64)
+		d.SlideIndex = &idx
+	}
+	return &d, nil
+}
+
+// RegisterDisplay creates a display or refreshes the details of one that
+// registered before under the same name
+func (db *DB) RegisterDisplay(name, role, resolution, userAgent, address string) (*models.Display, error) {
+	row := db.QueryRow(`
+		INSERT INTO displays (name, role, resolution, user_agent, address, registered_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (name) DO UPDATE
+		SET role = EXCLUDED.role, resolution = EXCLUDED.resolution, user_agent = EXCLUDED.user_agent,
+		    address = EXCLUDED.address, last_seen_at = NOW()
+		RETURNING `+displayColumns, name, role, resolution, userAgent, address)
+	display, err := scanDisplay(row)
+	if err != nil {
+		return nil, fmt.Errorf("error registering display: %w", err)
+	}
+	return display, nil
+}
+
+// DisplayHeartbeat records that a display is alive and what it is showing.
+// A song ID that isn't in the library is stored as none.
+func (db *DB) DisplayHeartbeat(name, address string, beat *models.DisplayHeartbeat) (*models.Display, error) {
+	row := db.QueryRow(`
+		UPDATE displays
+		SET song_id = (SELECT id FROM songs WHERE id::text = $2), song_title = $3, slide_index = $4,
+		    resolution = COALESCE(NULLIF($5, ''), resolution), address = $6, last_seen_at = NOW()
+		WHERE name = $1
+		RETURNING `+displayColumns, name, beat.SongID, beat.SongTitle, beat.SlideIndex, beat.Resolution, address)
+	display, err := scanDisplay(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("display not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error recording heartbeat: %w", err)
+	}
+	return display, nil
+}
+
+// GetDisplay returns a registered display by name
+func (db *DB) GetDisplay(name string) (*models.Display, error) {
+	display, err := scanDisplay(db.QueryRow(`SELECT `+displayColumns+` FROM displays WHERE name = $1`, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("display not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting display: %w", err)
+	}
+	return display, nil
+}
+
+// GetDisplays returns every registered display by name
+func (db *DB) GetDisplays() ([]models.Display, error) {
+	rows, err := db.Query(`SELECT ` + displayColumns + ` FROM displays ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting displays: %w", err)
+	}
+	defer rows.Close()
+
+	displays := make([]models.Display, 0)
+	for rows.Next() {
+		display, err := scanDisplay(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning display: %w", err)
+		}
+		displays = append(displays, *display)
+	}
+	return displays, rows.Err()
+}
+
+// DeleteDisplay forgets a display
+func (db *DB) DeleteDisplay(name string) error {
+	result, err := db.Exec(`DELETE FROM displays WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("error deleting display: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("display not found")
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const songEditColumns = `e.id, e.song_id, s.title, e.status, e.contributor, e.note, e.changes, e.reviewer, e.review_note,
+	e.created_at, e.updated_at, e.submitted_at, e.reviewed_at`
+
+func scanSongEdit(row interface{ Scan(...interface{}) error }) (*models.SongEdit, error) {
+	var e models.SongEdit
+	var changes []byte
+	if err := row.Scan(&e.ID, &e.SongID, &e.SongTitle, &e.Status, &e.Contributor, &e.Note, &changes, &e.Reviewer, &e.ReviewNote,
+		&e.CreatedAt, &e.UpdatedAt, &e.SubmittedAt, &e.ReviewedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(changes, &e.Changes); err != nil {
+		return nil, fmt.Errorf("error decoding changes: %w", err)
+	}
+	return &e, nil
+}
+
+// CreateSongEdit stores a proposed edit as a draft, or as pending review
+func (db *DB) CreateSongEdit(songID string, req *models.SongEditRequest) (*models.SongEdit, error) {
+	changes, err := json.Marshal(req.Changes)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding changes: %w", err)
+	}
+	status := models.EditDraft
+	if req.Submit {
+		status = models.EditPending
+	}
+
+	var id int
+	err = db.QueryRow(`
+		INSERT INTO song_edits (song_id, status, contributor, note, changes, submitted_at)
+		VALUES ($1, $2, $3, $4, $5, CASE WHEN $2 = 'pending' THEN NOW() END)
+		RETURNING id
+	`, songID, status, req.Contributor, req.Note, changes).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("error creating song edit: %w", err)
+	}
+	return db.GetSongEdit(id)
+}
+
+// GetSongEdit returns a proposed edit
+func (db *DB) GetSongEdit(id int) (*models.SongEdit, error) {
+	row := db.QueryRow(`
+		SELECT `+songEditColumns+`
+		FROM song_edits e
+		JOIN songs s ON s.id = e.song_id
+		WHERE e.id = $1
+	`, id)
+	edit, err := scanSongEdit(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("song edit not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting song edit: %w", err)
+	}
+	return edit, nil
+}
+
+// GetSongEdits lists proposed edits, oldest first, optionally only those of
+// one song or in one state
+func (db *DB) GetSongEdits(songID, status string) ([]models.SongEdit, error) {
+	rows, err := db.Query(`
+		SELECT `+songEditColumns+`
+		FROM song_edits e
+		JOIN songs s ON s.id = e.song_id
+		WHERE ($1 = '' OR e.song_id::text = $1) AND ($2 = '' OR e.status = $2) AND s.deleted_at IS NULL
+		ORDER BY COALESCE(e.submitted_at, e.created_at), e.id
+	`, songID, status)
+	if err != nil {
+		return nil, fmt.Errorf("error getting song edits: %w", err)
+	}
+	defer rows.Close()
+
+	edits := make([]models.SongEdit, 0)
+	for rows.Next() {
+		edit, err := scanSongEdit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning song edit: %w", err)
+		}
+		edits = append(edits, *edit)
+	}
+	return edits, rows.Err()
+}
+
+// UpdateSongEdit revises an edit that hasn't been reviewed yet. Submitting
+// moves a draft into the review queue; revising a pending edit keeps it there.
+func (db *DB) UpdateSongEdit(id int, req *models.SongEditRequest) (*models.SongEdit, error) {
+	changes, err := json.Marshal(req.Changes)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding changes: %w", err)
+	}
+
+	result, err := db.Exec(`
+		UPDATE song_edits
+		SET note = $2, changes = $3,
+		    status = CASE WHEN $4 THEN 'pending' ELSE status END,
+		    submitted_at = CASE WHEN $4 AND submitted_at IS NULL THEN NOW() ELSE submitted_at END,
+		    updated_at = NOW()
+		WHERE id = $1 AND status IN ('draft', 'pending')
+	`, id, req.Note, changes, req.Submit)
+	if err != nil {
+		return nil, fmt.Errorf("error updating song edit: %w", err)
+	}
+	if err := db.checkEditChanged(id, result); err != nil {
+		return nil, err
+	}
+	return db.GetSongEdit(id)
+}
+
+// SubmitSongEdit moves a draft into the review queue
+func (db *DB) SubmitSongEdit(id int) (*models.SongEdit, error) {
+	result, err := db.Exec(`
+		UPDATE song_edits
+		SET status = 'pending', submitted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'draft'
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error submitting song edit: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		edit, err := db.GetSongEdit(id)
+		if err != nil {
+			return nil, err
+		}
+		if edit.Status == models.EditPending {
+			return edit, nil
+		}
+		return nil, fmt.Errorf("song edit already reviewed")
+	}
+	return db.GetSongEdit(id)
+}
+
+// ReviewSongEdit records a reviewer's decision on a pending edit
+func (db *DB) ReviewSongEdit(id int, status, reviewer, note string) (*models.SongEdit, error) {
+	result, err := db.Exec(`
+		UPDATE song_edits
+		SET status = $2, reviewer = $3, review_note = $4, reviewed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id, status, reviewer, note)
+	if err != nil {
+		return nil, fmt.Errorf("error reviewing song edit: %w", err)
+	}
+	if err := db.checkEditChanged(id, result); err != nil {
+		return nil, err
+	}
+	return db.GetSongEdit(id)
+}
+
+// DeleteSongEdit withdraws an edit that hasn't been reviewed yet
+func (db *DB) DeleteSongEdit(id int) error {
+	result, err := db.Exec("DELETE FROM song_edits WHERE id = $1 AND status IN ('draft', 'pending')", id)
+	if err != nil {
+		return fmt.Errorf("error deleting song edit: %w", err)
+	}
+	return db.checkEditChanged(id, result)
+}
+
+// checkEditChanged turns an update that matched no rows into the reason why
+func (db *DB) checkEditChanged(id int, result sql.Result) error {
+	if n, _ := result.RowsAffected(); n > 0 {
+		return nil
+	}
+	if _, err := db.GetSongEdit(id); err != nil {
+		return err
+	}
+	return fmt.Errorf("song edit already reviewed")
+}
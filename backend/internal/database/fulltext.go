@@ -0,0 +1,27 @@
+package database
+
+import (
+	"strings"
+	"unicode"
+)
+
+// prefixTSQuery turns a search box query into a to_tsquery expression that
+// matches every word as a prefix ("amazing gra" finds "Amazing Grace"), so
+// the full-text fallback behaves like search-as-you-type. Punctuation is
+// dropped, which also keeps tsquery operators out; an empty result means the
+// query had no words.
+func prefixTSQuery(query string) string {
+	words := searchWords(query)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// searchWords splits a search box query into lower-case words
+func searchWords(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		// Vowel signs are marks; splitting on them would break Indic words apart
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+}
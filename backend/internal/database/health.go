@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	pq "github.com/lib/pq"
+)
+
+// requiredSchema lists, per table, the columns the server relies on from the
+// later migrations. A database missing any of them has not been fully
+// migrated. Extend it when adding a migration.
+var requiredSchema = map[string][]string{
+	"songs":               {"id", "original_key", "performance_key", "bpm", "time_signature", "count_in_beats", "background_media", "look", "copyright", "ccli_number", "chords", "public", "archived_at", "deleted_at"},
+	"settings":            {"id", "rehearsal_playlist", "ccli_license", "copyright_slide", "search_field_locales", "search_token_separators", "propresenter_health_interval"},
+	"song_pairs":          {"id"},
+	"song_notes":          {"id"},
+	"song_usage":          {"id"},
+	"services":            {"id"},
+	"service_events":      {"id"},
+	"setlists":            {"id"},
+	"setlist_songs":       {"setlist_id"},
+	"song_numbers":        {"song_id", "songbook", "number"},
+	"songbooks":           {"id", "name", "abbreviation"},
+	"song_cues":           {"song_id", "intro_bars", "starts_with", "dynamics", "ending"},
+	"song_timings":        {"song_id", "default_seconds", "slide_seconds", "loop"},
+	"song_timed_lyrics":   {"song_id", "lines", "offset_ms"},
+	"song_audio_tracks":   {"song_id", "url", "file_name", "propresenter_audio"},
+	"song_links":          {"song_id", "kind", "url", "thumbnail_url", "embed_html"},
+	"ccli_usage":          {"id", "song_id", "ccli_number", "used_at"},
+	"song_requests":       {"id", "song_id", "status", "setlist_id", "title_key", "sung_at"},
+	"song_request_votes":  {"request_id", "voter"},
+	"song_lyric_variants": {"song_id", "variant", "lyrics", "generated"},
+	"displays":            {"name", "role", "song_id", "slide_index", "last_seen_at"},
+	"display_profiles":    {"name", "max_chars_per_line", "max_lines_per_slide", "languages"},
+	"song_edits":          {"id", "song_id", "status", "changes", "reviewer", "submitted_at", "reviewed_at"},
+	"users":               {"id", "username", "role", "disabled", "must_reset_password", "password_hash", "oidc_subject"},
+	"user_sessions":       {"id", "user_id", "token_hash", "expires_at"},
+	"api_keys":            {"id", "user_id", "name", "role", "key_hash", "expires_at"},
+	"song_revisions":      {"song_id", "revision", "display_lyrics", "edited_by"},
+}
+
+// CheckReady verifies the database is reachable and migrated
+func (db *DB) CheckReady(ctx context.Context) error {
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database not reachable: %w", err)
+	}
+
+	tables := make([]string, 0, len(requiredSchema))
+	for table := range requiredSchema {
+		tables = append(tables, table)
+	}
+
+	rows, err := db.QueryContext(ctx, db.dialect.schemaQuery(), pq.Array(tables))
+	if err != nil {
+		return fmt.Errorf("error reading schema: %w", err)
+	}
+	defer rows.Close()
+
+	present := make(map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return fmt.Errorf("error reading schema: %w", err)
+		}
+		present[table+"."+column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error reading schema: %w", err)
+	}
+
+	var missing []string
+	for table, columns := range requiredSchema {
+		for _, column := range columns {
+			if !present[table+"."+column] {
+				missing = append(missing, table+"."+column)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("database not migrated, missing %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
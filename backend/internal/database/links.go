@@ -0,0 +1,67 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const linkColumns = `kind, url, title, author_name, thumbnail_url, embed_html, fetched_at`
+
+func linkFields(l *models.SongLink) []interface{} {
+	return []interface{}{&l.Kind, &l.URL, &l.Title, &l.AuthorName, &l.ThumbnailURL, &l.EmbedHTML, &l.FetchedAt}
+}
+
+// GetSongLinks returns a song's external links in the order they were added
+func (db *DB) GetSongLinks(songID string) ([]models.SongLink, error) {
+	rows, err := db.Query(`SELECT `+linkColumns+` FROM song_links WHERE song_id = $1 ORDER BY id`, songID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting song links: %w", err)
+	}
+	defer rows.Close()
+
+	links := make([]models.SongLink, 0)
+	for rows.Next() {
+		var l models.SongLink
+		if err := rows.Scan(linkFields(&l)...); err != nil {
+			return nil, fmt.Errorf("error scanning song link: %w", err)
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+// SetSongLinks replaces a song's external links
+func (db *DB) SetSongLinks(songID string, links []models.SongLink) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setSongLinks(tx, songID, links); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing song links: %w", err)
+	}
+	return nil
+}
+
+// setSongLinks replaces a song's external links within tx
+func setSongLinks(tx *sql.Tx, songID string, links []models.SongLink) error {
+	if _, err := tx.Exec(`DELETE FROM song_links WHERE song_id = $1`, songID); err != nil {
+		return fmt.Errorf("error clearing song links: %w", err)
+	}
+	for _, l := range links {
+		_, err := tx.Exec(`
+			INSERT INTO song_links (song_id, `+linkColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, songID, l.Kind, l.URL, l.Title, l.AuthorName, l.ThumbnailURL, l.EmbedHTML, l.FetchedAt)
+		if err != nil {
+			return fmt.Errorf("error saving song link %s: %w", l.URL, err)
+		}
+	}
+	return nil
+}
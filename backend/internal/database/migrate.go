@@ -0,0 +1,242 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The migrations are compiled into the server and applied in version order,
+// each in its own transaction. Every migration is written to be safe to run
+// again, so a database set up by hand before the schema_migrations table
+// existed is simply brought up to date and recorded.
+//
+// SQLite databases have their own migrations in migrations/sqlite, one for
+// each PostgreSQL migration and with the same name, bringing the schema to
+// the same state. They only ever run on a new file, so they need not be safe
+// to run again.
+//
+//go:embed migrations/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// Migration is one schema migration and whether it has been applied
+type Migration struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	// Modified means the file changed after it was applied
+	Modified bool `json:"modified,omitempty"`
+	// Unknown means the database has it but this server doesn't, i.e. the
+	// database was migrated by a newer version
+	Unknown bool `json:"unknown,omitempty"`
+
+	sql      string
+	checksum string
+}
+
+// MigrationStatus describes the schema version of the database
+type MigrationStatus struct {
+	Current    int         `json:"current"`
+	Latest     int         `json:"latest"`
+	Pending    int         `json:"pending"`
+	Migrations []Migration `json:"migrations"`
+}
+
+// embeddedMigrations returns the migrations in dir compiled into the
+// server, oldest first
+func embeddedMigrations(dir string) ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		number, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s doesn't start with a version number", entry.Name())
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", other, name)
+		}
+		seen[version] = name
+
+		data, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", name, err)
+		}
+		sum := sha256.Sum256(data)
+		migrations = append(migrations, Migration{Version: version, Name: name, sql: string(data), checksum: hex.EncodeToString(sum[:])})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+const createMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,                  -- SHA-256 of the file as applied
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// appliedMigrations returns the recorded migrations by version
+func appliedMigrations(ctx context.Context, q queryer) (map[int]appliedMigration, error) {
+	rows, err := q.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var m appliedMigration
+		if err := rows.Scan(&version, &m.name, &m.checksum, &m.appliedAt); err != nil {
+			return nil, fmt.Errorf("error scanning migration: %w", err)
+		}
+		applied[version] = m
+	}
+	return applied, rows.Err()
+}
+
+// Migrate applies the migrations the database doesn't have yet, oldest
+// first, and returns them. It stops at the first that fails, leaving the
+// ones before it applied.
+func (db *DB) Migrate(ctx context.Context) ([]Migration, error) {
+	migrations, err := embeddedMigrations(db.dialect.migrationDir())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Close()
+	unlock, err := db.dialect.lockMigrations(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("error locking migrations: %w", err)
+	}
+	defer unlock()
+
+	if _, err := conn.ExecContext(ctx, createMigrationsTable); err != nil {
+		return nil, fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range migrations {
+		if a, ok := applied[m.Version]; ok {
+			if a.checksum != m.checksum {
+				log.Printf("⚠️  Migration %s has changed since it was applied on %s; the change is not applied", m.Name, a.appliedAt.Format("2006-01-02"))
+			}
+			continue
+		}
+
+		started := time.Now()
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return done, fmt.Errorf("error starting migration %s: %w", m.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+			tx.Rollback()
+			return done, fmt.Errorf("migration %s failed: %w", m.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+			m.Version, m.Name, m.checksum); err != nil {
+			tx.Rollback()
+			return done, fmt.Errorf("error recording migration %s: %w", m.Name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return done, fmt.Errorf("error committing migration %s: %w", m.Name, err)
+		}
+
+		now := time.Now()
+		m.AppliedAt = &now
+		done = append(done, m)
+		log.Printf("📦 Applied migration %s (%s)", m.Name, time.Since(started).Round(time.Millisecond))
+	}
+
+	for version, a := range applied {
+		if version > migrations[len(migrations)-1].Version {
+			log.Printf("⚠️  The database has migration %s, which this version doesn't know; it was migrated by a newer version", a.name)
+		}
+	}
+	return done, nil
+}
+
+// MigrationStatus lists the migrations this server has and the ones the
+// database has applied
+func (db *DB) MigrationStatus(ctx context.Context) (*MigrationStatus, error) {
+	migrations, err := embeddedMigrations(db.dialect.migrationDir())
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]appliedMigration)
+	var exists bool
+	if err := db.QueryRowContext(ctx, db.dialect.migrationsTableQuery()).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking schema_migrations: %w", err)
+	}
+	if exists {
+		if applied, err = appliedMigrations(ctx, db); err != nil {
+			return nil, err
+		}
+	}
+
+	status := &MigrationStatus{Migrations: make([]Migration, 0, len(migrations))}
+	known := make(map[int]bool, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = true
+		status.Latest = m.Version
+		if a, ok := applied[m.Version]; ok {
+			appliedAt := a.appliedAt
+			m.AppliedAt = &appliedAt
+			m.Modified = a.checksum != m.checksum
+		} else {
+			status.Pending++
+		}
+		status.Migrations = append(status.Migrations, m)
+	}
+	for version, a := range applied {
+		if version > status.Current {
+			status.Current = version
+		}
+		if !known[version] {
+			appliedAt := a.appliedAt
+			status.Migrations = append(status.Migrations, Migration{Version: version, Name: a.name, AppliedAt: &appliedAt, Unknown: true})
+		}
+	}
+	sort.Slice(status.Migrations, func(i, j int) bool { return status.Migrations[i].Version < status.Migrations[j].Version })
+	return status, nil
+}
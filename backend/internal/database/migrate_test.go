@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// testDB opens TEST_DATABASE_URL with a schema of its own, dropped when the
+// test ends, so every migration runs against an empty database
+func testDB(t *testing.T) *DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := New(dsn)
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		admin.Close()
+		t.Fatalf("creating schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`)
+		admin.Close()
+	})
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	db, err := New(dsn + separator + "search_path=" + schema)
+	if err != nil {
+		t.Fatalf("connecting to schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrationsCreateQueue(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	applied, err := db.Migrate(ctx)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	migrations, err := embeddedMigrations(db.dialect.migrationDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrations) {
+		t.Fatalf("applied %d migrations, want %d", len(applied), len(migrations))
+	}
+
+	song, err := db.CreateSong(&models.CreateSongRequest{Title: "Amazing Grace", Library: "Hymns", Language: "english", DisplayLyrics: "Amazing grace, how sweet the sound"})
+	if err != nil {
+		t.Fatalf("CreateSong: %v", err)
+	}
+	item, err := db.AddToQueue(song.ID)
+	if err != nil {
+		t.Fatalf("AddToQueue: %v", err)
+	}
+	if item.Position != 1 {
+		t.Errorf("first queue item has position %d, want 1", item.Position)
+	}
+	if _, err := db.AddToQueue(song.ID); err == nil {
+		t.Error("AddToQueue queued the same song twice")
+	}
+
+	queue, err := db.GetQueue()
+	if err != nil {
+		t.Fatalf("GetQueue: %v", err)
+	}
+	if len(queue) != 1 || queue[0].SongID != song.ID || queue[0].Song == nil || queue[0].Song.Title != song.Title {
+		t.Fatalf("GetQueue = %+v, want the one queued song", queue)
+	}
+}
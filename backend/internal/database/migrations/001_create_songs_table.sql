@@ -0,0 +1,34 @@
+-- Songs and the single settings row. Songs are keyed by UUID, as is the
+-- ProPresenter library item a song was imported from.
+CREATE TABLE IF NOT EXISTS songs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title TEXT NOT NULL,
+    file_name TEXT,
+    library TEXT NOT NULL,
+    language TEXT NOT NULL,
+    pro_uuid UUID,                                   -- the ProPresenter presentation
+    display_lyrics TEXT NOT NULL DEFAULT '',
+    music_ministry_lyrics TEXT NOT NULL DEFAULT '',  -- lyrics with chords for the band
+    artist TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_pro_uuid ON songs(pro_uuid) WHERE pro_uuid IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_songs_library ON songs(library);
+CREATE INDEX IF NOT EXISTS idx_songs_language ON songs(language);
+CREATE INDEX IF NOT EXISTS idx_songs_updated_at ON songs(updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS settings (
+    id INTEGER PRIMARY KEY,
+    laptop_b_ip TEXT NOT NULL DEFAULT '',
+    laptop_b_port INTEGER NOT NULL DEFAULT 0,
+    live_playlist_uuid TEXT NOT NULL DEFAULT '',
+    propresenter_host TEXT DEFAULT '',
+    propresenter_port INTEGER DEFAULT 4031,
+    propresenter_playlist TEXT DEFAULT 'Live Queue',
+    propresenter_playlist_uuid UUID DEFAULT '00000000-0000-0000-0000-000000000000',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO settings (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,14 @@
+-- Links a song with its translation for dual-language (bilingual) display
+CREATE TABLE IF NOT EXISTS song_pairs (
+    id SERIAL PRIMARY KEY,
+    primary_song_id UUID NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    secondary_song_id UUID NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    alignment JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT song_pairs_distinct CHECK (primary_song_id <> secondary_song_id)
+);
+
+-- A song can only be paired with one translation, on either side of the pair
+CREATE UNIQUE INDEX IF NOT EXISTS idx_song_pairs_primary ON song_pairs(primary_song_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_song_pairs_secondary ON song_pairs(secondary_song_id);
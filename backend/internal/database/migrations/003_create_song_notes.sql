@@ -0,0 +1,12 @@
+-- Presenter notes (key changes, "repeat chorus", spoken transitions) shown only on stage displays
+CREATE TABLE IF NOT EXISTS song_notes (
+    id SERIAL PRIMARY KEY,
+    song_id UUID NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    section_index INTEGER,          -- NULL means the note applies to the whole song
+    section_label TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_notes_song_id ON song_notes(song_id);
@@ -0,0 +1,10 @@
+-- One row per song per service day it was put on screen (rehearsals are not recorded)
+CREATE TABLE IF NOT EXISTS song_usage (
+    id SERIAL PRIMARY KEY,
+    song_id UUID NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    service_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (song_id, service_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_usage_service_date ON song_usage(service_date);
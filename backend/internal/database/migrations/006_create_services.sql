@@ -0,0 +1,26 @@
+-- Services group everything that happened on screen during one gathering
+CREATE TABLE IF NOT EXISTS services (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    service_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'archived')),
+    report JSONB,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    archived_at TIMESTAMPTZ
+);
+
+-- Only one service can be running at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_services_single_active ON services(status) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS service_events (
+    id SERIAL PRIMARY KEY,
+    service_id INTEGER NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,          -- 'trigger' or 'error'
+    song_id UUID REFERENCES songs(id) ON DELETE SET NULL,
+    title TEXT NOT NULL DEFAULT '',
+    operator TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_events_service_id ON service_events(service_id, occurred_at);
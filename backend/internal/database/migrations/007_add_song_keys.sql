@@ -0,0 +1,3 @@
+-- Keys for the music ministry view (e.g. "G", "Bb", "F#m")
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS original_key TEXT;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS performance_key TEXT;
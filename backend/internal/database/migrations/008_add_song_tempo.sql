@@ -0,0 +1,4 @@
+-- Click/tempo metadata for the band
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS bpm INTEGER CHECK (bpm BETWEEN 20 AND 300);
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS time_signature TEXT;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS count_in_beats INTEGER CHECK (count_in_beats BETWEEN 0 AND 16);
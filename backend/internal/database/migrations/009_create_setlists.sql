@@ -0,0 +1,15 @@
+-- Planned song lists for a service, used for exports
+CREATE TABLE IF NOT EXISTS setlists (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    service_date DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS setlist_songs (
+    setlist_id INTEGER NOT NULL REFERENCES setlists(id) ON DELETE CASCADE,
+    song_id UUID NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (setlist_id, position)
+);
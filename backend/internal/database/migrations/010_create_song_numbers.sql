@@ -0,0 +1,12 @@
+-- Hymnal references: a song can appear in several songbooks, each under a number
+CREATE TABLE IF NOT EXISTS song_numbers (
+    id SERIAL PRIMARY KEY,
+    song_id UUID NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    songbook TEXT NOT NULL,
+    number TEXT NOT NULL,           -- text so numbers like "123a" are allowed
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A number identifies one song within a songbook
+CREATE UNIQUE INDEX IF NOT EXISTS idx_song_numbers_book_number ON song_numbers(LOWER(songbook), LOWER(number));
+CREATE INDEX IF NOT EXISTS idx_song_numbers_song_id ON song_numbers(song_id);
@@ -0,0 +1,17 @@
+-- Songbooks: hymnals and other numbered collections. A songbook's entries are
+-- the song_numbers rows whose songbook matches its name (case-insensitive).
+CREATE TABLE IF NOT EXISTS songbooks (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    abbreviation TEXT NOT NULL DEFAULT '',    -- e.g. "KK", used by number search
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songbooks_name ON songbooks(LOWER(name));
+
+-- Songbooks already referenced by song numbers
+INSERT INTO songbooks (name)
+SELECT DISTINCT ON (LOWER(songbook)) songbook FROM song_numbers ORDER BY LOWER(songbook), id
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+-- ProPresenter background media item and look applied when a song is triggered
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS background_media TEXT;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS look TEXT;
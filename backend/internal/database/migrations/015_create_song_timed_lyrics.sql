@@ -0,0 +1,7 @@
+-- Per-line timestamps (from LRC files) for synced lyrics: auto-scroll, karaoke highlighting
+CREATE TABLE IF NOT EXISTS song_timed_lyrics (
+    song_id UUID PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE,
+    lines JSONB NOT NULL DEFAULT '[]',      -- [{"time_ms": 12340, "text": "..."}] in time order
+    offset_ms INTEGER NOT NULL DEFAULT 0,   -- LRC [offset:] tag; positive shows lines earlier
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,8 @@
+-- Original recordings linked to songs for run-throughs
+CREATE TABLE IF NOT EXISTS song_audio_tracks (
+    song_id UUID PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE,
+    url TEXT,                  -- streamed by the local player
+    file_name TEXT,            -- uploaded file stored in AUDIO_DIR
+    propresenter_audio TEXT,   -- ProPresenter audio item UUID or name
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
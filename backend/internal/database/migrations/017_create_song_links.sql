@@ -0,0 +1,15 @@
+-- External reference links (YouTube, Spotify, SongSelect, MultiTracks) with cached oEmbed metadata
+CREATE TABLE IF NOT EXISTS song_links (
+    id SERIAL PRIMARY KEY,
+    song_id UUID NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    url TEXT NOT NULL,
+    title TEXT,
+    author_name TEXT,
+    thumbnail_url TEXT,
+    embed_html TEXT,
+    fetched_at TIMESTAMPTZ,   -- when the metadata was last fetched
+    UNIQUE (song_id, url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_links_song_id ON song_links(song_id);
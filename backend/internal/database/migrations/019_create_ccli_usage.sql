@@ -0,0 +1,13 @@
+-- CCLI reported-use log: one row each time a song goes live (rehearsals are not
+-- recorded). Song details are copied so reports survive later edits and deletes.
+CREATE TABLE IF NOT EXISTS ccli_usage (
+    id SERIAL PRIMARY KEY,
+    song_id UUID REFERENCES songs(id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    artist TEXT,
+    copyright TEXT,
+    ccli_number TEXT,
+    used_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ccli_usage_used_at ON ccli_usage(used_at);
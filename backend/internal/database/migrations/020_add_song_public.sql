@@ -0,0 +1,4 @@
+-- Songs approved for the public read-only API (church website, congregation app)
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS public BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_songs_public ON songs(public) WHERE public;
@@ -0,0 +1,14 @@
+-- Song requests submitted by the congregation, reviewed in the admin inbox
+CREATE TABLE IF NOT EXISTS song_requests (
+    id SERIAL PRIMARY KEY,
+    song_id UUID REFERENCES songs(id) ON DELETE SET NULL,   -- set when picked from the public catalog or matched on accept
+    title TEXT NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    requester_name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected')),
+    setlist_id INTEGER REFERENCES setlists(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_requests_status ON song_requests(status, created_at);
@@ -0,0 +1,23 @@
+-- Request voting and status tracking: accepted requests become 'planned', then
+-- 'sung' once the song has been triggered live
+ALTER TABLE song_requests DROP CONSTRAINT IF EXISTS song_requests_status_check;
+UPDATE song_requests SET status = 'planned' WHERE status = 'accepted';
+ALTER TABLE song_requests ADD CONSTRAINT song_requests_status_check CHECK (status IN ('pending', 'planned', 'sung', 'rejected'));
+
+ALTER TABLE song_requests ADD COLUMN IF NOT EXISTS title_key TEXT NOT NULL DEFAULT '';   -- normalized title for collapsing duplicates
+ALTER TABLE song_requests ADD COLUMN IF NOT EXISTS sung_at TIMESTAMPTZ;
+UPDATE song_requests SET title_key = LOWER(TRIM(title)) WHERE title_key = '';
+
+CREATE INDEX IF NOT EXISTS idx_song_requests_title_key ON song_requests(title_key);
+
+-- One vote per request per voter; the submitter's request counts as a vote
+CREATE TABLE IF NOT EXISTS song_request_votes (
+    request_id INTEGER NOT NULL REFERENCES song_requests(id) ON DELETE CASCADE,
+    voter TEXT NOT NULL,   -- hash of the client IP, never the IP itself
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (request_id, voter)
+);
+
+INSERT INTO song_request_votes (request_id, voter)
+SELECT id, 'submitter' FROM song_requests
+ON CONFLICT DO NOTHING;
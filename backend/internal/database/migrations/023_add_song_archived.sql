@@ -0,0 +1,4 @@
+-- Archived songs stay in the library but are left out of search
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_songs_archived_at ON songs(archived_at) WHERE archived_at IS NOT NULL;
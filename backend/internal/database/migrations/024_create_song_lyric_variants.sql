@@ -0,0 +1,10 @@
+-- Alternative renderings of a song's display lyrics, e.g. a romanized
+-- transliteration of Malayalam lyrics for congregants who can't read the script
+CREATE TABLE IF NOT EXISTS song_lyric_variants (
+    song_id UUID NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    variant TEXT NOT NULL,                    -- "romanized"
+    lyrics TEXT NOT NULL,
+    generated BOOLEAN NOT NULL DEFAULT TRUE,  -- false once edited by hand; saves then leave it alone
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (song_id, variant)
+);
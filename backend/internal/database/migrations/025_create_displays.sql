@@ -0,0 +1,14 @@
+-- Teleprompter and stage display clients, kept up to date by heartbeats so
+-- the tech booth can see which screen is stuck
+CREATE TABLE IF NOT EXISTS displays (
+    name TEXT PRIMARY KEY,                  -- the ?display= name used to target it on the live channel
+    role TEXT NOT NULL DEFAULT 'audience',
+    resolution TEXT NOT NULL DEFAULT '',    -- e.g. 1920x1080
+    user_agent TEXT NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    song_id UUID REFERENCES songs(id) ON DELETE SET NULL,
+    song_title TEXT NOT NULL DEFAULT '',
+    slide_index INTEGER,
+    registered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,19 @@
+-- Proposed changes to songs from contributors, applied only once a reviewer
+-- approves them
+CREATE TABLE IF NOT EXISTS song_edits (
+    id SERIAL PRIMARY KEY,
+    song_id UUID NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'draft',   -- draft, pending, approved or rejected
+    contributor TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    changes JSONB NOT NULL DEFAULT '{}',    -- the fields of a song update, as sent to PUT /api/songs/:id
+    reviewer TEXT NOT NULL DEFAULT '',
+    review_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    submitted_at TIMESTAMPTZ,
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_edits_song ON song_edits(song_id);
+CREATE INDEX IF NOT EXISTS idx_song_edits_status ON song_edits(status);
@@ -0,0 +1,2 @@
+-- How often the server checks that ProPresenter is reachable, in seconds
+ALTER TABLE settings ADD COLUMN IF NOT EXISTS propresenter_health_interval INTEGER DEFAULT 30;
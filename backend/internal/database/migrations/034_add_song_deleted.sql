@@ -0,0 +1,10 @@
+-- Deleted songs go to the trash: hidden everywhere, but kept with their notes,
+-- cues, numbers and history until they are restored or deleted for good
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_songs_deleted_at ON songs(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- A song in the trash doesn't keep its ProPresenter item from being imported
+-- or linked again
+DROP INDEX IF EXISTS idx_songs_pro_uuid;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_live_pro_uuid ON songs(pro_uuid) WHERE pro_uuid IS NOT NULL AND deleted_at IS NULL;
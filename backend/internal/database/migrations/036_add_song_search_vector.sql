@@ -0,0 +1,9 @@
+-- Full-text index searched while Typesense is unavailable. The 'simple'
+-- configuration doesn't stem or drop stop words, so Malayalam, Hindi and
+-- transliterated lyrics match as typed.
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(artist, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(display_lyrics, '') || ' ' || coalesce(music_ministry_lyrics, '')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
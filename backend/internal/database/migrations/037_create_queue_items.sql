@@ -0,0 +1,9 @@
+-- The Live Queue: songs waiting to be sent to ProPresenter, in order. Each
+-- song is queued at most once.
+CREATE TABLE IF NOT EXISTS queue_items (
+    id SERIAL PRIMARY KEY,
+    song_id UUID NOT NULL UNIQUE REFERENCES songs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
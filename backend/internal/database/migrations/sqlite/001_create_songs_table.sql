@@ -0,0 +1,38 @@
+-- Songs and the single settings row. UUIDs are stored as text and compared
+-- case-insensitively, as PostgreSQL compares its uuid type.
+CREATE TABLE songs (
+    id TEXT NOT NULL PRIMARY KEY COLLATE NOCASE DEFAULT (
+        lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+        substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', 1 + abs(random()) % 4, 1) ||
+        substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))
+    ),
+    title TEXT NOT NULL,
+    file_name TEXT,
+    library TEXT NOT NULL,
+    language TEXT NOT NULL,
+    pro_uuid TEXT COLLATE NOCASE,                    -- the ProPresenter presentation
+    display_lyrics TEXT NOT NULL DEFAULT '',
+    music_ministry_lyrics TEXT NOT NULL DEFAULT '',  -- lyrics with chords for the band
+    artist TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE UNIQUE INDEX idx_songs_pro_uuid ON songs(pro_uuid) WHERE pro_uuid IS NOT NULL;
+CREATE INDEX idx_songs_library ON songs(library);
+CREATE INDEX idx_songs_language ON songs(language);
+CREATE INDEX idx_songs_updated_at ON songs(updated_at DESC);
+
+CREATE TABLE settings (
+    id INTEGER PRIMARY KEY,
+    laptop_b_ip TEXT NOT NULL DEFAULT '',
+    laptop_b_port INTEGER NOT NULL DEFAULT 0,
+    live_playlist_uuid TEXT NOT NULL DEFAULT '',
+    propresenter_host TEXT DEFAULT '',
+    propresenter_port INTEGER DEFAULT 4031,
+    propresenter_playlist TEXT DEFAULT 'Live Queue',
+    propresenter_playlist_uuid TEXT COLLATE NOCASE DEFAULT '00000000-0000-0000-0000-000000000000',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+INSERT INTO settings (id) VALUES (1);
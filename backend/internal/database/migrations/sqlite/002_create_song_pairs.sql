@@ -0,0 +1,14 @@
+-- Links a song with its translation for dual-language (bilingual) display
+CREATE TABLE song_pairs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    primary_song_id TEXT NOT NULL COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    secondary_song_id TEXT NOT NULL COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    alignment JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    CONSTRAINT song_pairs_distinct CHECK (primary_song_id <> secondary_song_id)
+);
+
+-- A song can only be paired with one translation, on either side of the pair
+CREATE UNIQUE INDEX idx_song_pairs_primary ON song_pairs(primary_song_id);
+CREATE UNIQUE INDEX idx_song_pairs_secondary ON song_pairs(secondary_song_id);
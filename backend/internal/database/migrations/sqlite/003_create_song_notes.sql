@@ -0,0 +1,12 @@
+-- Presenter notes (key changes, "repeat chorus", spoken transitions) shown only on stage displays
+CREATE TABLE song_notes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    song_id TEXT NOT NULL COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    section_index INTEGER,          -- NULL means the note applies to the whole song
+    section_label TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX idx_song_notes_song_id ON song_notes(song_id);
@@ -0,0 +1,2 @@
+-- Playlist used instead of the Live Queue while rehearsal mode is on
+ALTER TABLE settings ADD COLUMN rehearsal_playlist TEXT DEFAULT 'Rehearsal';
@@ -0,0 +1,10 @@
+-- One row per song per service day it was put on screen (rehearsals are not recorded)
+CREATE TABLE song_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    song_id TEXT NOT NULL COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    service_date DATE NOT NULL DEFAULT (date('now', 'localtime')),
+    used_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE (song_id, service_date)
+);
+
+CREATE INDEX idx_song_usage_service_date ON song_usage(service_date);
@@ -0,0 +1,26 @@
+-- Services group everything that happened on screen during one gathering
+CREATE TABLE services (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    service_date DATE NOT NULL DEFAULT (date('now', 'localtime')),
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'archived')),
+    report JSONB,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    archived_at TIMESTAMPTZ
+);
+
+-- Only one service can be running at a time
+CREATE UNIQUE INDEX idx_services_single_active ON services(status) WHERE status = 'active';
+
+CREATE TABLE service_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    service_id INTEGER NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,          -- 'trigger' or 'error'
+    song_id TEXT COLLATE NOCASE REFERENCES songs(id) ON DELETE SET NULL,
+    title TEXT NOT NULL DEFAULT '',
+    operator TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX idx_service_events_service_id ON service_events(service_id, occurred_at);
@@ -0,0 +1,3 @@
+-- Keys for the music ministry view (e.g. "G", "Bb", "F#m")
+ALTER TABLE songs ADD COLUMN original_key TEXT;
+ALTER TABLE songs ADD COLUMN performance_key TEXT;
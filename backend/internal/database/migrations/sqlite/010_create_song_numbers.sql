@@ -0,0 +1,12 @@
+-- Hymnal references: a song can appear in several songbooks, each under a number
+CREATE TABLE song_numbers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    song_id TEXT NOT NULL COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    songbook TEXT NOT NULL,
+    number TEXT NOT NULL,           -- text so numbers like "123a" are allowed
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+-- A number identifies one song within a songbook
+CREATE UNIQUE INDEX idx_song_numbers_book_number ON song_numbers(LOWER(songbook), LOWER(number));
+CREATE INDEX idx_song_numbers_song_id ON song_numbers(song_id);
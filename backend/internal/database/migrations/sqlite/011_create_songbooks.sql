@@ -0,0 +1,12 @@
+-- Songbooks: hymnals and other numbered collections. A songbook's entries are
+-- the song_numbers rows whose songbook matches its name (case-insensitive).
+CREATE TABLE songbooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    abbreviation TEXT NOT NULL DEFAULT '',    -- e.g. "KK", used by number search
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE UNIQUE INDEX idx_songbooks_name ON songbooks(LOWER(name));
@@ -0,0 +1,9 @@
+-- Structured band cues (intro, who starts, dynamics, ending) for stage displays
+CREATE TABLE song_cues (
+    song_id TEXT NOT NULL PRIMARY KEY COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    intro_bars INTEGER CHECK (intro_bars BETWEEN 0 AND 64),
+    starts_with TEXT NOT NULL DEFAULT '',   -- who starts, e.g. "piano" or "acoustic + vocal"
+    dynamics TEXT NOT NULL DEFAULT '',
+    ending TEXT NOT NULL DEFAULT '',        -- see models.SongEndings
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
@@ -0,0 +1,8 @@
+-- Per-slide durations for timed auto-advance (announcement loops, pre-service lyrics)
+CREATE TABLE song_timings (
+    song_id TEXT NOT NULL PRIMARY KEY COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    default_seconds INTEGER NOT NULL CHECK (default_seconds BETWEEN 1 AND 3600),
+    slide_seconds TEXT NOT NULL DEFAULT '{}',   -- per slide, as a PostgreSQL array literal; 0 or missing uses default_seconds
+    loop BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
@@ -0,0 +1,7 @@
+-- Per-line timestamps (from LRC files) for synced lyrics: auto-scroll, karaoke highlighting
+CREATE TABLE song_timed_lyrics (
+    song_id TEXT NOT NULL PRIMARY KEY COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    lines JSONB NOT NULL DEFAULT '[]',      -- [{"time_ms": 12340, "text": "..."}] in time order
+    offset_ms INTEGER NOT NULL DEFAULT 0,   -- LRC [offset:] tag; positive shows lines earlier
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
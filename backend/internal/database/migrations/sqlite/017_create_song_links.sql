@@ -0,0 +1,15 @@
+-- External reference links (YouTube, Spotify, SongSelect, MultiTracks) with cached oEmbed metadata
+CREATE TABLE song_links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    song_id TEXT NOT NULL COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    url TEXT NOT NULL,
+    title TEXT,
+    author_name TEXT,
+    thumbnail_url TEXT,
+    embed_html TEXT,
+    fetched_at TIMESTAMPTZ,   -- when the metadata was last fetched
+    UNIQUE (song_id, url)
+);
+
+CREATE INDEX idx_song_links_song_id ON song_links(song_id);
@@ -0,0 +1,6 @@
+-- Licensing fields for copyright/CCLI attribution slides
+ALTER TABLE songs ADD COLUMN copyright TEXT;     -- e.g. "2004 worshiptogether.com songs"
+ALTER TABLE songs ADD COLUMN ccli_number TEXT;   -- CCLI song number
+
+ALTER TABLE settings ADD COLUMN ccli_license TEXT DEFAULT '';           -- the church's CCLI license number
+ALTER TABLE settings ADD COLUMN copyright_slide BOOLEAN DEFAULT FALSE;  -- append attribution slides by default
@@ -0,0 +1,13 @@
+-- CCLI reported-use log: one row each time a song goes live (rehearsals are not
+-- recorded). Song details are copied so reports survive later edits and deletes.
+CREATE TABLE ccli_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    song_id TEXT COLLATE NOCASE REFERENCES songs(id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    artist TEXT,
+    copyright TEXT,
+    ccli_number TEXT,
+    used_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX idx_ccli_usage_used_at ON ccli_usage(used_at);
@@ -0,0 +1,4 @@
+-- Songs approved for the public read-only API (church website, congregation app)
+ALTER TABLE songs ADD COLUMN public BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_songs_public ON songs(public) WHERE public;
@@ -0,0 +1,16 @@
+-- Song requests submitted by the congregation, reviewed in the admin inbox.
+-- SQLite can't change a CHECK constraint later, so the statuses are the ones
+-- migration 022 brings PostgreSQL to.
+CREATE TABLE song_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    song_id TEXT COLLATE NOCASE REFERENCES songs(id) ON DELETE SET NULL,   -- set when picked from the public catalog or matched on accept
+    title TEXT NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    requester_name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'planned', 'sung', 'rejected')),
+    setlist_id INTEGER REFERENCES setlists(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_song_requests_status ON song_requests(status, created_at);
@@ -0,0 +1,14 @@
+-- Request voting and status tracking: accepted requests become 'planned', then
+-- 'sung' once the song has been triggered live (see 021 for the statuses)
+ALTER TABLE song_requests ADD COLUMN title_key TEXT NOT NULL DEFAULT '';   -- normalized title for collapsing duplicates
+ALTER TABLE song_requests ADD COLUMN sung_at TIMESTAMPTZ;
+
+CREATE INDEX idx_song_requests_title_key ON song_requests(title_key);
+
+-- One vote per request per voter; the submitter's request counts as a vote
+CREATE TABLE song_request_votes (
+    request_id INTEGER NOT NULL REFERENCES song_requests(id) ON DELETE CASCADE,
+    voter TEXT NOT NULL,   -- hash of the client IP, never the IP itself
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY (request_id, voter)
+);
@@ -0,0 +1,10 @@
+-- Alternative renderings of a song's display lyrics, e.g. a romanized
+-- transliteration of Malayalam lyrics for congregants who can't read the script
+CREATE TABLE song_lyric_variants (
+    song_id TEXT NOT NULL COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    variant TEXT NOT NULL,                    -- "romanized"
+    lyrics TEXT NOT NULL,
+    generated BOOLEAN NOT NULL DEFAULT TRUE,  -- false once edited by hand; saves then leave it alone
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY (song_id, variant)
+);
@@ -0,0 +1,10 @@
+-- Limits of the screens lyrics are shown on, e.g. a narrow LED wall, checked
+-- when songs are saved so text never overflows during a service
+CREATE TABLE display_profiles (
+    name TEXT NOT NULL PRIMARY KEY,               -- e.g. "led-wall"
+    max_chars_per_line INTEGER NOT NULL DEFAULT 0,  -- 0 means no limit
+    max_lines_per_slide INTEGER NOT NULL DEFAULT 0,
+    languages JSONB NOT NULL DEFAULT '{}',        -- per-language limits, e.g. {"ml": {"max_chars_per_line": 24}}
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
@@ -0,0 +1,4 @@
+-- How Typesense splits song text into words. Collections are recreated with
+-- these on the next reindex, which starts automatically when they change.
+ALTER TABLE settings ADD COLUMN search_field_locales JSONB DEFAULT '{}';    -- locale per text field, e.g. {"lyrics": "ml"}
+ALTER TABLE settings ADD COLUMN search_token_separators TEXT DEFAULT '';    -- characters that split words besides spaces, e.g. "-/"
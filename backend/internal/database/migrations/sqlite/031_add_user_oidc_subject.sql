@@ -0,0 +1,5 @@
+-- Links an account to its single sign-on identity (the ID token's "sub"),
+-- which unlike the email address never changes
+ALTER TABLE users ADD COLUMN oidc_subject TEXT;
+
+CREATE UNIQUE INDEX idx_users_oidc_subject ON users (oidc_subject) WHERE oidc_subject IS NOT NULL;
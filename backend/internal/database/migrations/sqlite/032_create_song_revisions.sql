@@ -0,0 +1,17 @@
+-- Versions of each song's words, so edits can be compared and rolled back.
+-- A revision is kept whenever the title, artist, language or lyrics change;
+-- a song's first change also keeps the version it replaced.
+CREATE TABLE song_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    song_id TEXT NOT NULL COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,              -- 1, 2, ... per song
+    title TEXT NOT NULL,
+    artist TEXT,
+    language TEXT NOT NULL DEFAULT '',
+    display_lyrics TEXT NOT NULL DEFAULT '',
+    music_ministry_lyrics TEXT NOT NULL DEFAULT '',
+    edited_by TEXT NOT NULL DEFAULT '',     -- who saved this version, when known
+    note TEXT NOT NULL DEFAULT '',          -- e.g. "Restored revision 3"
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE (song_id, revision)
+);
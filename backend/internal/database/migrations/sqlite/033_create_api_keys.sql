@@ -0,0 +1,16 @@
+-- Keys for scripts and devices that can't sign in. Each belongs to a user
+-- and acts with a role no higher than theirs; only a SHA-256 of the key is
+-- stored.
+CREATE TABLE api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    role TEXT NOT NULL,                 -- admin, editor, operator or viewer
+    key_hash TEXT NOT NULL UNIQUE,
+    key_prefix TEXT NOT NULL,           -- the start of the key, to recognise it by
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    last_used_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ              -- NULL: never
+);
+
+CREATE INDEX idx_api_keys_user ON api_keys (user_id);
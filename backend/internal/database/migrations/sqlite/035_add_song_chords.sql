@@ -0,0 +1,2 @@
+-- A song's chord chart in ChordPro, for musicians' apps
+ALTER TABLE songs ADD COLUMN chords TEXT;
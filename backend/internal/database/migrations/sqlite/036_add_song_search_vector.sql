@@ -0,0 +1,2 @@
+-- SQLite has no full-text index to add: the search used while Typesense is
+-- unavailable matches each word with LIKE instead (see sqliteDialect.fullText)
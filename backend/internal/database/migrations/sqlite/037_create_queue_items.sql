@@ -0,0 +1,9 @@
+-- The Live Queue: songs waiting to be sent to ProPresenter, in order. Each
+-- song is queued at most once.
+CREATE TABLE queue_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    song_id TEXT NOT NULL UNIQUE COLLATE NOCASE REFERENCES songs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetSongNotes returns a song's presenter notes, song-wide notes first then by section
+func (db *DB) GetSongNotes(songID string) ([]models.SongNote, error) {
+	query := `
+		SELECT id, song_id, section_index, section_label, note, created_at, updated_at
+		FROM song_notes
+		WHERE song_id = $1
+		ORDER BY section_index ASC NULLS FIRST, id ASC
+	`
+
+	rows, err := db.Query(query, songID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting song notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := make([]models.SongNote, 0)
+	for rows.Next() {
+		var note models.SongNote
+		var sectionIndex sql.NullInt64
+		if err := rows.Scan(&note.ID, &note.SongID, &sectionIndex, &note.SectionLabel, &note.Note, &note.CreatedAt, &note.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning song note: %w", err)
+		}
+		if sectionIndex.Valid {
+			idx := int(sectionIndex.Int64)
+			note.SectionIndex = &idx
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, nil
+}
+
+// CreateSongNote adds a presenter note to a song
+func (db *DB) CreateSongNote(songID string, req *models.SongNoteRequest) (*models.SongNote, error) {
+	query := `
+		INSERT INTO song_notes (song_id, section_index, section_label, note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id, song_id, section_label, note, created_at, updated_at
+	`
+
+	note := models.SongNote{SectionIndex: req.SectionIndex}
+	err := db.QueryRow(query, songID, req.SectionIndex, req.SectionLabel, req.Note).
+		Scan(&note.ID, &note.SongID, &note.SectionLabel, &note.Note, &note.CreatedAt, &note.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error creating song note: %w", err)
+	}
+
+	return &note, nil
+}
+
+// UpdateSongNote replaces the contents of a presenter note
+func (db *DB) UpdateSongNote(songID string, noteID int, req *models.SongNoteRequest) (*models.SongNote, error) {
+	query := `
+		UPDATE song_notes
+		SET section_index = $1, section_label = $2, note = $3, updated_at = NOW()
+		WHERE id = $4 AND song_id = $5
+		RETURNING id, song_id, section_label, note, created_at, updated_at
+	`
+
+	note := models.SongNote{SectionIndex: req.SectionIndex}
+	err := db.QueryRow(query, req.SectionIndex, req.SectionLabel, req.Note, noteID, songID).
+		Scan(&note.ID, &note.SongID, &note.SectionLabel, &note.Note, &note.CreatedAt, &note.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("note not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error updating song note: %w", err)
+	}
+
+	return &note, nil
+}
+
+// DeleteSongNote removes a presenter note
+func (db *DB) DeleteSongNote(songID string, noteID int) error {
+	result, err := db.Exec("DELETE FROM song_notes WHERE id = $1 AND song_id = $2", noteID, songID)
+	if err != nil {
+		return fmt.Errorf("error deleting song note: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("note not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	pq "github.com/lib/pq"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetSongNumbers returns a song's songbook references in the order they were added
+func (db *DB) GetSongNumbers(songID string) ([]models.SongNumber, error) {
+	rows, err := db.Query(`SELECT songbook, number FROM song_numbers WHERE song_id = $1 ORDER BY id`, songID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting song numbers: %w", err)
+	}
+	defer rows.Close()
+
+	numbers := make([]models.SongNumber, 0)
+	for rows.Next() {
+		var n models.SongNumber
+		if err := rows.Scan(&n.Songbook, &n.Number); err != nil {
+			return nil, fmt.Errorf("error scanning song number: %w", err)
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, rows.Err()
+}
+
+// GetAllSongNumbers returns every song's songbook references keyed by song ID
+func (db *DB) GetAllSongNumbers() (map[string][]models.SongNumber, error) {
+	rows, err := db.Query(`SELECT song_id, songbook, number FROM song_numbers ORDER BY song_id, id`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting song numbers: %w", err)
+	}
+	defer rows.Close()
+
+	numbers := make(map[string][]models.SongNumber)
+	for rows.Next() {
+		var songID string
+		var n models.SongNumber
+		if err := rows.Scan(&songID, &n.Songbook, &n.Number); err != nil {
+			return nil, fmt.Errorf("error scanning song number: %w", err)
+		}
+		numbers[songID] = append(numbers[songID], n)
+	}
+	return numbers, rows.Err()
+}
+
+// TakenSongNumbers returns the references in numbers that already belong to
+// a song other than songID (pass "" for a new song). Numbers of songs in the
+// trash are free to take.
+func (db *DB) TakenSongNumbers(songID string, numbers []models.SongNumber) ([]models.SongNumber, error) {
+	taken := make([]models.SongNumber, 0)
+	for _, n := range numbers {
+		var exists bool
+		err := db.QueryRow(`
+			SELECT EXISTS (
+				SELECT 1 FROM song_numbers n
+				JOIN songs s ON s.id = n.song_id
+				WHERE LOWER(n.songbook) = LOWER($1) AND LOWER(n.number) = LOWER($2) AND n.song_id::text <> $3
+				  AND s.deleted_at IS NULL
+			)
+		`, n.Songbook, n.Number, songID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("error checking song number: %w", err)
+		}
+		if exists {
+			taken = append(taken, n)
+		}
+	}
+	return taken, nil
+}
+
+// SetSongNumbers replaces a song's songbook references
+func (db *DB) SetSongNumbers(songID string, numbers []models.SongNumber) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setSongNumbers(tx, songID, numbers); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing song numbers: %w", err)
+	}
+	return nil
+}
+
+// setSongNumbers replaces a song's songbook references within tx
+func setSongNumbers(tx *sql.Tx, songID string, numbers []models.SongNumber) error {
+	if _, err := tx.Exec(`DELETE FROM song_numbers WHERE song_id = $1`, songID); err != nil {
+		return fmt.Errorf("error clearing song numbers: %w", err)
+	}
+	for _, n := range numbers {
+		// A song in the trash gives up a number that is reused
+		if _, err := tx.Exec(`
+			DELETE FROM song_numbers
+			WHERE LOWER(songbook) = LOWER($1) AND LOWER(number) = LOWER($2)
+			  AND song_id IN (SELECT id FROM songs WHERE deleted_at IS NOT NULL)
+		`, n.Songbook, n.Number); err != nil {
+			return fmt.Errorf("error releasing song number %s %s: %w", n.Songbook, n.Number, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO song_numbers (song_id, songbook, number) VALUES ($1, $2, $3)`, songID, n.Songbook, n.Number); err != nil {
+			return fmt.Errorf("error saving song number %s %s: %w", n.Songbook, n.Number, err)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO songbooks (name) SELECT $1
+			WHERE NOT EXISTS (SELECT 1 FROM songbooks WHERE LOWER(name) = LOWER($1))
+		`, n.Songbook); err != nil {
+			return fmt.Errorf("error adding songbook %s: %w", n.Songbook, err)
+		}
+	}
+	return nil
+}
+
+// FindSongsByNumber returns the songs with a number in any of the given
+// songbooks, or in any songbook when songbooks is empty
+func (db *DB) FindSongsByNumber(songbooks []string, number string) ([]models.Song, error) {
+	query := `
+		SELECT DISTINCT ` + prefixedSongColumns("s") + `
+		FROM songs s
+		JOIN song_numbers n ON n.song_id = s.id
+		WHERE LOWER(n.number) = LOWER($1) AND s.deleted_at IS NULL`
+	args := []interface{}{number}
+	if len(songbooks) > 0 {
+		lower := make([]string, len(songbooks))
+		for i, b := range songbooks {
+			lower[i] = strings.ToLower(b)
+		}
+		query += ` AND LOWER(n.songbook) = ANY($2)`
+		args = append(args, pq.Array(lower))
+	}
+	query += ` ORDER BY s.title`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error finding songs by number: %w", err)
+	}
+	defer rows.Close()
+
+	songs := make([]models.Song, 0)
+	for rows.Next() {
+		var song models.Song
+		if err := rows.Scan(songFields(&song)...); err != nil {
+			return nil, fmt.Errorf("error scanning song: %w", err)
+		}
+		songs = append(songs, song)
+	}
+	return songs, rows.Err()
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetSongPair returns the pairing a song takes part in, on either side,
+// unless the other song is in the trash. The result is oriented so that
+// songID is always the primary song.
+func (db *DB) GetSongPair(songID string) (*models.SongPair, error) {
+	query := `
+		SELECT id, primary_song_id, secondary_song_id, alignment, created_at, updated_at
+		FROM song_pairs p
+		WHERE (primary_song_id = $1 OR secondary_song_id = $1)
+		  AND NOT EXISTS (SELECT 1 FROM songs s WHERE s.id IN (p.primary_song_id, p.secondary_song_id) AND s.deleted_at IS NOT NULL)
+	`
+
+	var pair models.SongPair
+	var alignment []byte
+	err := db.QueryRow(query, songID).
+		Scan(&pair.ID, &pair.PrimarySongID, &pair.SecondarySongID, &alignment, &pair.CreatedAt, &pair.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("song pair not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting song pair: %w", err)
+	}
+
+	if err := json.Unmarshal(alignment, &pair.Alignment); err != nil {
+		return nil, fmt.Errorf("error decoding song pair alignment: %w", err)
+	}
+
+	if pair.SecondarySongID == songID {
+		pair.PrimarySongID, pair.SecondarySongID = pair.SecondarySongID, pair.PrimarySongID
+		for i, a := range pair.Alignment {
+			pair.Alignment[i] = models.SectionAlignment{Primary: a.Secondary, Secondary: a.Primary}
+		}
+	}
+
+	return &pair, nil
+}
+
+// SaveSongPair replaces any existing pairing for either song with the given one
+func (db *DB) SaveSongPair(primaryID, secondaryID string, alignment []models.SectionAlignment) (*models.SongPair, error) {
+	alignmentJSON, err := json.Marshal(alignment)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding alignment: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM song_pairs
+		WHERE primary_song_id IN ($1, $2) OR secondary_song_id IN ($1, $2)
+	`, primaryID, secondaryID)
+	if err != nil {
+		return nil, fmt.Errorf("error removing previous pairing: %w", err)
+	}
+
+	query := `
+		INSERT INTO song_pairs (primary_song_id, secondary_song_id, alignment, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING id, primary_song_id, secondary_song_id, created_at, updated_at
+	`
+
+	var pair models.SongPair
+	err = tx.QueryRow(query, primaryID, secondaryID, alignmentJSON).
+		Scan(&pair.ID, &pair.PrimarySongID, &pair.SecondarySongID, &pair.CreatedAt, &pair.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error saving song pair: %w", err)
+	}
+	pair.Alignment = alignment
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return &pair, nil
+}
+
+// DeleteSongPair removes the pairing a song takes part in
+func (db *DB) DeleteSongPair(songID string) error {
+	result, err := db.Exec("DELETE FROM song_pairs WHERE primary_song_id = $1 OR secondary_song_id = $1", songID)
+	if err != nil {
+		return fmt.Errorf("error deleting song pair: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("song pair not found")
+	}
+
+	return nil
+}
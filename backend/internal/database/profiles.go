@@ -0,0 +1,80 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const displayProfileColumns = `name, max_chars_per_line, max_lines_per_slide, languages, created_at, updated_at`
+
+func scanDisplayProfile(row interface{ Scan(...interface{}) error }) (*models.DisplayProfile, error) {
+	var p models.DisplayProfile
+	var languages []byte
+	if err := row.Scan(&p.Name, &p.MaxCharsPerLine, &p.MaxLinesPerSlide, &languages, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(languages, &p.Languages); err != nil {
+		return nil, fmt.Errorf("error decoding language limits: %w", err)
+	}
+	if p.Languages == nil {
+		p.Languages = make(map[string]models.DisplayLimits)
+	}
+	return &p, nil
+}
+
+// GetDisplayProfiles returns every display profile by name
+func (db *DB) GetDisplayProfiles() ([]models.DisplayProfile, error) {
+	rows, err := db.Query(`SELECT ` + displayProfileColumns + ` FROM display_profiles ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting display profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := make([]models.DisplayProfile, 0)
+	for rows.Next() {
+		profile, err := scanDisplayProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning display profile: %w", err)
+		}
+		profiles = append(profiles, *profile)
+	}
+	return profiles, rows.Err()
+}
+
+// SaveDisplayProfile creates or replaces a display profile
+func (db *DB) SaveDisplayProfile(name string, req *models.DisplayProfileRequest) (*models.DisplayProfile, error) {
+	languages, err := json.Marshal(req.Languages)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding language limits: %w", err)
+	}
+	if req.Languages == nil {
+		languages = []byte("{}")
+	}
+
+	row := db.QueryRow(`
+		INSERT INTO display_profiles (name, max_chars_per_line, max_lines_per_slide, languages, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (name) DO UPDATE
+		SET max_chars_per_line = EXCLUDED.max_chars_per_line, max_lines_per_slide = EXCLUDED.max_lines_per_slide,
+		    languages = EXCLUDED.languages, updated_at = NOW()
+		RETURNING `+displayProfileColumns, name, req.MaxCharsPerLine, req.MaxLinesPerSlide, languages)
+	profile, err := scanDisplayProfile(row)
+	if err != nil {
+		return nil, fmt.Errorf("error saving display profile: %w", err)
+	}
+	return profile, nil
+}
+
+// DeleteDisplayProfile removes a display profile
+func (db *DB) DeleteDisplayProfile(name string) error {
+	result, err := db.Exec(`DELETE FROM display_profiles WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("error deleting display profile: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("display profile not found")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetPublicSongs returns the songs approved for the public API, optionally
+// filtered by a title/artist/lyrics query and a language
+func (db *DB) GetPublicSongs(query, language string) ([]models.Song, error) {
+	base := `SELECT ` + songColumns + ` FROM songs WHERE public AND archived_at IS NULL AND deleted_at IS NULL`
+	args := []interface{}{}
+	argPos := 1
+
+	if query != "" && query != "*" {
+		base += fmt.Sprintf(" AND (title ILIKE $%d OR artist ILIKE $%d OR display_lyrics ILIKE $%d)", argPos, argPos, argPos)
+		args = append(args, "%"+query+"%")
+		argPos++
+	}
+	if language != "" {
+		base += fmt.Sprintf(" AND language = $%d", argPos)
+		args = append(args, language)
+		argPos++
+	}
+	base += " ORDER BY title"
+
+	rows, err := db.Query(base, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting public songs: %w", err)
+	}
+	defer rows.Close()
+
+	songs := make([]models.Song, 0)
+	for rows.Next() {
+		var song models.Song
+		if err := rows.Scan(songFields(&song)...); err != nil {
+			return nil, fmt.Errorf("error scanning song: %w", err)
+		}
+		songs = append(songs, song)
+	}
+	return songs, rows.Err()
+}
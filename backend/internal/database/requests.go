@@ -0,0 +1,248 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	pq "github.com/lib/pq"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// songRequestColumns selects from song_requests aliased as r, with the vote count
+const songRequestColumns = `r.id, r.song_id, r.title, r.message, r.requester_name, r.status, r.setlist_id,
+		(SELECT COUNT(*) FROM song_request_votes v WHERE v.request_id = r.id) AS votes, r.created_at, r.reviewed_at, r.sung_at`
+
+func songRequestFields(r *models.SongRequest) []interface{} {
+	return []interface{}{&r.ID, &r.SongID, &r.Title, &r.Message, &r.RequesterName, &r.Status, &r.SetlistID,
+		&r.Votes, &r.CreatedAt, &r.ReviewedAt, &r.SungAt}
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func getSongRequest(q queryRower, id int) (*models.SongRequest, error) {
+	var r models.SongRequest
+	err := q.QueryRow(`SELECT `+songRequestColumns+` FROM song_requests r WHERE r.id = $1`, id).Scan(songRequestFields(&r)...)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("song request not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting song request: %w", err)
+	}
+	return &r, nil
+}
+
+// CreateSongRequest records a congregant's song request with their vote. A
+// request for a song already pending or planned - the same catalog song, or
+// the same title once normalized - is collapsed into the existing request as
+// a vote; duplicate reports whether that happened.
+func (db *DB) CreateSongRequest(songID *string, title, titleKey, message, requesterName, voter string) (request *models.SongRequest, duplicate bool, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`
+		SELECT id FROM song_requests
+		WHERE status IN ('pending', 'planned')
+		  AND (song_id = $1 OR title_key = $2)
+		ORDER BY created_at
+		LIMIT 1
+	`, songID, titleKey).Scan(&id)
+	switch {
+	case err == nil:
+		duplicate = true
+		if songID != nil {
+			// A catalog pick tells us which song a free-text request meant
+			if _, err := tx.Exec(`UPDATE song_requests SET song_id = $2 WHERE id = $1 AND song_id IS NULL`, id, *songID); err != nil {
+				return nil, false, fmt.Errorf("error updating song request: %w", err)
+			}
+		}
+	case err == sql.ErrNoRows:
+		err = tx.QueryRow(`
+			INSERT INTO song_requests (song_id, title, title_key, message, requester_name)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, songID, title, titleKey, message, requesterName).Scan(&id)
+		if err != nil {
+			return nil, false, fmt.Errorf("error creating song request: %w", err)
+		}
+	default:
+		return nil, false, fmt.Errorf("error finding duplicate song request: %w", err)
+	}
+
+	if _, err := tx.Exec(`INSERT INTO song_request_votes (request_id, voter) VALUES ($1, $2) ON CONFLICT DO NOTHING`, id, voter); err != nil {
+		return nil, false, fmt.Errorf("error recording vote: %w", err)
+	}
+
+	request, err = getSongRequest(tx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("error committing song request: %w", err)
+	}
+	return request, duplicate, nil
+}
+
+// VoteSongRequest adds a voter's vote to a pending or planned request. It
+// reports false if they had already voted for it.
+func (db *DB) VoteSongRequest(id int, voter string) (*models.SongRequest, bool, error) {
+	request, err := getSongRequest(db, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if request.Status != models.SongRequestPending && request.Status != models.SongRequestPlanned {
+		return nil, false, fmt.Errorf("song request closed")
+	}
+
+	res, err := db.Exec(`INSERT INTO song_request_votes (request_id, voter) VALUES ($1, $2) ON CONFLICT DO NOTHING`, id, voter)
+	if err != nil {
+		return nil, false, fmt.Errorf("error recording vote: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n > 0 {
+		request.Votes++
+	}
+	return request, n > 0, nil
+}
+
+// GetSongRequests returns requests with the given statuses (all when none),
+// most voted first or, with oldest, in the order they came in
+func (db *DB) GetSongRequests(statuses []string, oldest bool) ([]models.SongRequest, error) {
+	query := `SELECT ` + songRequestColumns + ` FROM song_requests r`
+	args := []interface{}{}
+	if len(statuses) > 0 {
+		query += ` WHERE r.status = ANY($1)`
+		args = append(args, pq.Array(statuses))
+	}
+	if oldest {
+		query += ` ORDER BY r.created_at, r.id`
+	} else {
+		query += ` ORDER BY votes DESC, r.created_at, r.id`
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting song requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := make([]models.SongRequest, 0)
+	for rows.Next() {
+		var r models.SongRequest
+		if err := rows.Scan(songRequestFields(&r)...); err != nil {
+			return nil, fmt.Errorf("error scanning song request: %w", err)
+		}
+		requests = append(requests, r)
+	}
+	return requests, rows.Err()
+}
+
+// GetSongRequest returns one song request
+func (db *DB) GetSongRequest(id int) (*models.SongRequest, error) {
+	return getSongRequest(db, id)
+}
+
+// AcceptSongRequest appends the song to the end of a setlist and marks the
+// pending request planned, in one transaction
+func (db *DB) AcceptSongRequest(id int, songID string, setlistID int) (*models.SongRequest, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE setlists SET updated_at = NOW() WHERE id = $1`, setlistID)
+	if err != nil {
+		return nil, fmt.Errorf("error updating setlist: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("setlist not found")
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO setlist_songs (setlist_id, song_id, position)
+		SELECT $1, $2, COALESCE(MAX(position), 0) + 1 FROM setlist_songs WHERE setlist_id = $1
+	`, setlistID, songID)
+	if err != nil {
+		return nil, fmt.Errorf("error adding song to setlist: %w", err)
+	}
+
+	res, err = tx.Exec(`
+		UPDATE song_requests
+		SET status = 'planned', song_id = $2, setlist_id = $3, reviewed_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id, songID, setlistID)
+	if err != nil {
+		return nil, fmt.Errorf("error accepting song request: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("song request not pending")
+	}
+
+	request, err := getSongRequest(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing song request: %w", err)
+	}
+	return request, nil
+}
+
+// SetSongRequestStatus moves a request to any status, e.g. reopening a
+// rejected request or marking one sung by hand
+func (db *DB) SetSongRequestStatus(id int, status string) (*models.SongRequest, error) {
+	res, err := db.Exec(`
+		UPDATE song_requests
+		SET status = $2,
+		    reviewed_at = CASE WHEN $2 = 'pending' THEN NULL ELSE COALESCE(reviewed_at, NOW()) END,
+		    sung_at = CASE WHEN $2 = 'sung' THEN COALESCE(sung_at, NOW()) ELSE NULL END
+		WHERE id = $1
+	`, id, status)
+	if err != nil {
+		return nil, fmt.Errorf("error updating song request: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("song request not found")
+	}
+	return getSongRequest(db, id)
+}
+
+// MarkSongRequestsSung closes the planned requests for a song once it has
+// been shown live
+func (db *DB) MarkSongRequestsSung(songID string) error {
+	_, err := db.Exec(`
+		UPDATE song_requests SET status = 'sung', sung_at = NOW()
+		WHERE song_id = $1 AND status = 'planned'
+	`, songID)
+	if err != nil {
+		return fmt.Errorf("error updating song requests: %w", err)
+	}
+	return nil
+}
+
+// LinkSongRequest points a pending request at a library song, e.g. a draft
+// created from it
+func (db *DB) LinkSongRequest(id int, songID string) (*models.SongRequest, error) {
+	if _, err := db.Exec(`UPDATE song_requests SET song_id = $2 WHERE id = $1`, id, songID); err != nil {
+		return nil, fmt.Errorf("error updating song request: %w", err)
+	}
+	return getSongRequest(db, id)
+}
+
+// DeleteSongRequest removes a request from the inbox
+func (db *DB) DeleteSongRequest(id int) error {
+	res, err := db.Exec(`DELETE FROM song_requests WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting song request: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("song request not found")
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// Saves by the same person this close together are folded into one
+// revision, so autosaves while editing don't flood the history
+const revisionMergeWindow = "10 minutes"
+
+const revisionColumns = `song_id, revision, title, artist, language, display_lyrics, music_ministry_lyrics, edited_by, note, created_at`
+
+func scanSongRevision(row interface{ Scan(...interface{}) error }) (*models.SongRevision, error) {
+	var r models.SongRevision
+	if err := row.Scan(&r.SongID, &r.Revision, &r.Title, &r.Artist, &r.Language, &r.DisplayLyrics, &r.MusicMinistryLyrics,
+		&r.EditedBy, &r.Note, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// lockSongForRevision locks the song row for an update and, for a song with
+// no history yet, keeps its current version as revision 1
+func lockSongForRevision(tx *sql.Tx, id string) error {
+	var exists bool
+	err := tx.QueryRow(`SELECT TRUE FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("song not found")
+	}
+	if err != nil {
+		return fmt.Errorf("error locking song: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO song_revisions (song_id, revision, title, artist, language, display_lyrics, music_ministry_lyrics, created_at)
+		SELECT id, 1, title, artist, language, display_lyrics, music_ministry_lyrics, updated_at
+		FROM songs
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM song_revisions WHERE song_id = $1)
+	`, id)
+	if err != nil {
+		return fmt.Errorf("error saving song revision: %w", err)
+	}
+	return nil
+}
+
+// recordSongRevision keeps the song's version as just saved, if its words
+// differ from the latest revision. A save by the same person shortly after
+// their last one replaces that revision instead.
+func recordSongRevision(tx *sql.Tx, id, editedBy, note string) error {
+	var latest int
+	var differs, merge bool
+	err := tx.QueryRow(`
+		SELECT r.revision,
+		       (s.title, s.artist, s.language, s.display_lyrics, s.music_ministry_lyrics)
+		           IS DISTINCT FROM (r.title, r.artist, r.language, r.display_lyrics, r.music_ministry_lyrics),
+		       r.revision > 1 AND r.edited_by <> '' AND r.edited_by = $2 AND r.note = '' AND $3 = ''
+		           AND r.created_at > NOW() - INTERVAL '`+revisionMergeWindow+`'
+		FROM songs s
+		JOIN song_revisions r ON r.song_id = s.id
+		WHERE s.id = $1
+		ORDER BY r.revision DESC
+		LIMIT 1
+	`, id, editedBy, note).Scan(&latest, &differs, &merge)
+	if err != nil {
+		return fmt.Errorf("error checking song revisions: %w", err)
+	}
+	if !differs {
+		return nil
+	}
+
+	if merge {
+		_, err = tx.Exec(`
+			UPDATE song_revisions AS r
+			SET title = s.title, artist = s.artist, language = s.language, display_lyrics = s.display_lyrics,
+			    music_ministry_lyrics = s.music_ministry_lyrics, created_at = NOW()
+			FROM songs s
+			WHERE s.id = r.song_id AND r.song_id = $1 AND r.revision = $2
+		`, id, latest)
+	} else {
+		_, err = tx.Exec(`
+			INSERT INTO song_revisions (song_id, revision, title, artist, language, display_lyrics, music_ministry_lyrics, edited_by, note)
+			SELECT id, $2, title, artist, language, display_lyrics, music_ministry_lyrics, $3, $4
+			FROM songs
+			WHERE id = $1
+		`, id, latest+1, editedBy, note)
+	}
+	if err != nil {
+		return fmt.Errorf("error saving song revision: %w", err)
+	}
+	return nil
+}
+
+// GetSongRevisions lists a song's revisions, newest first
+func (db *DB) GetSongRevisions(songID string) ([]models.SongRevision, error) {
+	rows, err := db.Query(`
+		SELECT `+revisionColumns+`
+		FROM song_revisions
+		WHERE song_id = $1
+		ORDER BY revision DESC
+	`, songID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting song revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]models.SongRevision, 0)
+	for rows.Next() {
+		revision, err := scanSongRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning song revision: %w", err)
+		}
+		revisions = append(revisions, *revision)
+	}
+	return revisions, rows.Err()
+}
+
+// GetSongRevision returns one revision of a song
+func (db *DB) GetSongRevision(songID string, revision int) (*models.SongRevision, error) {
+	r, err := scanSongRevision(db.QueryRow(`
+		SELECT `+revisionColumns+` FROM song_revisions WHERE song_id = $1 AND revision = $2
+	`, songID, revision))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("song revision not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting song revision: %w", err)
+	}
+	return r, nil
+}
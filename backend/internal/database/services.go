@@ -0,0 +1,241 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const serviceColumns = `id, name, TO_CHAR(service_date, 'YYYY-MM-DD'), status, started_at, archived_at`
+
+func scanService(row interface{ Scan(...interface{}) error }) (*models.Service, error) {
+	var service models.Service
+	var archivedAt sql.NullTime
+	if err := row.Scan(&service.ID, &service.Name, &service.ServiceDate, &service.Status, &service.StartedAt, &archivedAt); err != nil {
+		return nil, err
+	}
+	if archivedAt.Valid {
+		service.ArchivedAt = &archivedAt.Time
+	}
+	return &service, nil
+}
+
+// CreateService starts a new service. Only one service may be active at a time.
+func (db *DB) CreateService(req *models.CreateServiceRequest) (*models.Service, error) {
+	if _, err := db.GetActiveService(); err == nil {
+		return nil, fmt.Errorf("a service is already active")
+	}
+
+	query := `
+		INSERT INTO services (name, service_date, status, started_at)
+		VALUES ($1, COALESCE(NULLIF($2, '')::date, CURRENT_DATE), 'active', NOW())
+		RETURNING ` + serviceColumns
+
+	service, err := scanService(db.QueryRow(query, req.Name, req.ServiceDate))
+	if err != nil {
+		return nil, fmt.Errorf("error creating service: %w", err)
+	}
+
+	return service, nil
+}
+
+// GetServices returns all services, newest first
+func (db *DB) GetServices() ([]models.Service, error) {
+	rows, err := db.Query(`SELECT ` + serviceColumns + ` FROM services ORDER BY started_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting services: %w", err)
+	}
+	defer rows.Close()
+
+	services := make([]models.Service, 0)
+	for rows.Next() {
+		service, err := scanService(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning service: %w", err)
+		}
+		services = append(services, *service)
+	}
+
+	return services, nil
+}
+
+// GetService retrieves a service by ID
+func (db *DB) GetService(id int) (*models.Service, error) {
+	service, err := scanService(db.QueryRow(`SELECT `+serviceColumns+` FROM services WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("service not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting service: %w", err)
+	}
+
+	return service, nil
+}
+
+// GetActiveService returns the service currently running
+func (db *DB) GetActiveService() (*models.Service, error) {
+	service, err := scanService(db.QueryRow(`SELECT ` + serviceColumns + ` FROM services WHERE status = 'active' LIMIT 1`))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no active service")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting active service: %w", err)
+	}
+
+	return service, nil
+}
+
+// AddServiceEvent records something that happened during a service
+func (db *DB) AddServiceEvent(event *models.ServiceEvent) error {
+	query := `
+		INSERT INTO service_events (service_id, event_type, song_id, title, operator, message, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+	`
+
+	if _, err := db.Exec(query, event.ServiceID, event.EventType, event.SongID, event.Title, event.Operator, event.Message); err != nil {
+		return fmt.Errorf("error adding service event: %w", err)
+	}
+	return nil
+}
+
+// GetServiceEvents returns a service's events in the order they happened
+func (db *DB) GetServiceEvents(serviceID int) ([]models.ServiceEvent, error) {
+	query := `
+		SELECT id, service_id, event_type, song_id::text, title, operator, message, occurred_at
+		FROM service_events
+		WHERE service_id = $1
+		ORDER BY occurred_at ASC, id ASC
+	`
+
+	rows, err := db.Query(query, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting service events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]models.ServiceEvent, 0)
+	for rows.Next() {
+		var event models.ServiceEvent
+		if err := rows.Scan(&event.ID, &event.ServiceID, &event.EventType, &event.SongID, &event.Title, &event.Operator, &event.Message, &event.OccurredAt); err != nil {
+			return nil, fmt.Errorf("error scanning service event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// ArchiveService closes an active service and stores its summary report
+func (db *DB) ArchiveService(id int) (*models.ServiceReport, error) {
+	service, err := db.GetService(id)
+	if err != nil {
+		return nil, err
+	}
+	if service.Status != "active" {
+		return nil, fmt.Errorf("service already archived")
+	}
+
+	events, err := db.GetServiceEvents(id)
+	if err != nil {
+		return nil, err
+	}
+
+	archivedAt := time.Now()
+	report := buildServiceReport(service, events, archivedAt)
+
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding service report: %w", err)
+	}
+
+	result, err := db.Exec(`
+		UPDATE services SET status = 'archived', archived_at = $1, report = $2
+		WHERE id = $3 AND status = 'active'
+	`, archivedAt, reportJSON, id)
+	if err != nil {
+		return nil, fmt.Errorf("error archiving service: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("service already archived")
+	}
+
+	return report, nil
+}
+
+// GetServiceReport returns the report stored when a service was archived
+func (db *DB) GetServiceReport(id int) (*models.ServiceReport, error) {
+	var reportJSON []byte
+	err := db.QueryRow(`SELECT report FROM services WHERE id = $1`, id).Scan(&reportJSON)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("service not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting service report: %w", err)
+	}
+	if reportJSON == nil {
+		return nil, fmt.Errorf("report not found")
+	}
+
+	var report models.ServiceReport
+	if err := json.Unmarshal(reportJSON, &report); err != nil {
+		return nil, fmt.Errorf("error decoding service report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// buildServiceReport lists songs in the order they were first triggered with
+// every trigger time, plus the operators involved and any ProPresenter errors
+func buildServiceReport(service *models.Service, events []models.ServiceEvent, archivedAt time.Time) *models.ServiceReport {
+	report := &models.ServiceReport{
+		ServiceID:   service.ID,
+		Name:        service.Name,
+		ServiceDate: service.ServiceDate,
+		StartedAt:   service.StartedAt,
+		ArchivedAt:  archivedAt,
+		Songs:       make([]models.ServiceReportSong, 0),
+		Operators:   make([]string, 0),
+		Errors:      make([]models.ServiceEvent, 0),
+		GeneratedAt: time.Now(),
+	}
+
+	songIndex := make(map[string]int)
+	operators := make(map[string]bool)
+
+	for _, event := range events {
+		if event.Operator != "" && !operators[event.Operator] {
+			operators[event.Operator] = true
+			report.Operators = append(report.Operators, event.Operator)
+		}
+
+		switch event.EventType {
+		case models.ServiceEventError:
+			report.Errors = append(report.Errors, event)
+		case models.ServiceEventTrigger:
+			key := strings.ToLower(event.Title)
+			if event.SongID != nil {
+				key = *event.SongID
+			}
+			idx, ok := songIndex[key]
+			if !ok {
+				idx = len(report.Songs)
+				songIndex[key] = idx
+				report.Songs = append(report.Songs, models.ServiceReportSong{
+					Position:    idx + 1,
+					SongID:      event.SongID,
+					Title:       event.Title,
+					TriggeredAt: make([]time.Time, 0, 1),
+				})
+			}
+			report.Songs[idx].TriggeredAt = append(report.Songs[idx].TriggeredAt, event.OccurredAt)
+		}
+	}
+
+	sort.Strings(report.Operators)
+	return report
+}
@@ -0,0 +1,206 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const setlistColumns = `id, name, TO_CHAR(service_date, 'YYYY-MM-DD'), created_at, updated_at`
+
+func scanSetlist(row interface{ Scan(...interface{}) error }) (*models.Setlist, error) {
+	var setlist models.Setlist
+	var serviceDate sql.NullString
+	if err := row.Scan(&setlist.ID, &setlist.Name, &serviceDate, &setlist.CreatedAt, &setlist.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if serviceDate.Valid {
+		setlist.ServiceDate = &serviceDate.String
+	}
+	setlist.Songs = make([]models.Song, 0)
+	return &setlist, nil
+}
+
+// GetSetlists returns all setlists without their songs, most recent service first
+func (db *DB) GetSetlists() ([]models.Setlist, error) {
+	rows, err := db.Query(`SELECT ` + setlistColumns + ` FROM setlists ORDER BY service_date DESC NULLS LAST, created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting setlists: %w", err)
+	}
+	defer rows.Close()
+
+	setlists := make([]models.Setlist, 0)
+	for rows.Next() {
+		setlist, err := scanSetlist(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning setlist: %w", err)
+		}
+		setlists = append(setlists, *setlist)
+	}
+
+	return setlists, nil
+}
+
+// GetScheduledSetlists returns setlists dated on or after from, soonest first,
+// with their songs, for the calendar feed
+func (db *DB) GetScheduledSetlists(from string) ([]models.Setlist, error) {
+	rows, err := db.Query(`SELECT `+setlistColumns+` FROM setlists WHERE service_date >= $1::date ORDER BY service_date, id`, from)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scheduled setlists: %w", err)
+	}
+	defer rows.Close()
+
+	setlists := make([]models.Setlist, 0)
+	for rows.Next() {
+		setlist, err := scanSetlist(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning setlist: %w", err)
+		}
+		setlists = append(setlists, *setlist)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting scheduled setlists: %w", err)
+	}
+	rows.Close()
+
+	for i := range setlists {
+		songs, err := db.setlistSongs(setlists[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		setlists[i].Songs = songs
+	}
+	return setlists, nil
+}
+
+// GetSetlist retrieves a setlist with its songs in order
+func (db *DB) GetSetlist(id int) (*models.Setlist, error) {
+	setlist, err := scanSetlist(db.QueryRow(`SELECT `+setlistColumns+` FROM setlists WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("setlist not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting setlist: %w", err)
+	}
+
+	if setlist.Songs, err = db.setlistSongs(id); err != nil {
+		return nil, err
+	}
+	return setlist, nil
+}
+
+// setlistSongs returns a setlist's songs in order
+func (db *DB) setlistSongs(id int) ([]models.Song, error) {
+	query := `
+		SELECT ` + prefixedSongColumns("s") + `
+		FROM setlist_songs ss
+		JOIN songs s ON s.id = ss.song_id
+		WHERE ss.setlist_id = $1 AND s.deleted_at IS NULL
+		ORDER BY ss.position ASC
+	`
+	rows, err := db.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting setlist songs: %w", err)
+	}
+	defer rows.Close()
+
+	songs := make([]models.Song, 0)
+	for rows.Next() {
+		var song models.Song
+		if err := rows.Scan(songFields(&song)...); err != nil {
+			return nil, fmt.Errorf("error scanning setlist song: %w", err)
+		}
+		songs = append(songs, song)
+	}
+
+	return songs, nil
+}
+
+// CreateSetlist creates a setlist with the given songs
+func (db *DB) CreateSetlist(req *models.SetlistRequest) (*models.Setlist, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO setlists (name, service_date, created_at, updated_at)
+		VALUES ($1, $2::date, NOW(), NOW())
+		RETURNING id
+	`, req.Name, req.ServiceDate).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("error creating setlist: %w", err)
+	}
+
+	if err := replaceSetlistSongs(tx, id, req.SongIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing setlist: %w", err)
+	}
+
+	return db.GetSetlist(id)
+}
+
+// UpdateSetlist replaces a setlist's details and songs
+func (db *DB) UpdateSetlist(id int, req *models.SetlistRequest) (*models.Setlist, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE setlists SET name = $1, service_date = $2::date, updated_at = NOW()
+		WHERE id = $3
+	`, req.Name, req.ServiceDate, id)
+	if err != nil {
+		return nil, fmt.Errorf("error updating setlist: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("setlist not found")
+	}
+
+	if err := replaceSetlistSongs(tx, id, req.SongIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing setlist: %w", err)
+	}
+
+	return db.GetSetlist(id)
+}
+
+// DeleteSetlist removes a setlist
+func (db *DB) DeleteSetlist(id int) error {
+	result, err := db.Exec(`DELETE FROM setlists WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting setlist: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("setlist not found")
+	}
+
+	return nil
+}
+
+func replaceSetlistSongs(tx *sql.Tx, setlistID int, songIDs []string) error {
+	if _, err := tx.Exec(`DELETE FROM setlist_songs WHERE setlist_id = $1`, setlistID); err != nil {
+		return fmt.Errorf("error clearing setlist songs: %w", err)
+	}
+	for i, songID := range songIDs {
+		_, err := tx.Exec(`INSERT INTO setlist_songs (setlist_id, song_id, position) VALUES ($1, $2, $3)`, setlistID, songID, i+1)
+		if err != nil {
+			return fmt.Errorf("error adding song %s to setlist: %w", songID, err)
+		}
+	}
+	return nil
+}
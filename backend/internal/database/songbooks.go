@@ -0,0 +1,260 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const songbookColumns = `id, name, abbreviation, description, created_at, updated_at`
+
+func scanSongbook(row interface{ Scan(...interface{}) error }) (*models.Songbook, error) {
+	var book models.Songbook
+	if err := row.Scan(&book.ID, &book.Name, &book.Abbreviation, &book.Description, &book.CreatedAt, &book.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// GetSongbooks returns all songbooks by name, with how many songs each has
+func (db *DB) GetSongbooks() ([]models.Songbook, error) {
+	rows, err := db.Query(`
+		SELECT b.id, b.name, b.abbreviation, b.description, b.created_at, b.updated_at,
+		       (SELECT COUNT(*) FROM song_numbers n WHERE LOWER(n.songbook) = LOWER(b.name))
+		FROM songbooks b
+		ORDER BY LOWER(b.name)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting songbooks: %w", err)
+	}
+	defer rows.Close()
+
+	books := make([]models.Songbook, 0)
+	for rows.Next() {
+		var book models.Songbook
+		if err := rows.Scan(&book.ID, &book.Name, &book.Abbreviation, &book.Description, &book.CreatedAt, &book.UpdatedAt, &book.SongCount); err != nil {
+			return nil, fmt.Errorf("error scanning songbook: %w", err)
+		}
+		books = append(books, book)
+	}
+	return books, rows.Err()
+}
+
+// GetSongbook retrieves a songbook with its song count
+func (db *DB) GetSongbook(id int) (*models.Songbook, error) {
+	book, err := scanSongbook(db.QueryRow(`SELECT `+songbookColumns+` FROM songbooks WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("songbook not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting songbook: %w", err)
+	}
+
+	err = db.QueryRow(`SELECT COUNT(*) FROM song_numbers WHERE LOWER(songbook) = LOWER($1)`, book.Name).Scan(&book.SongCount)
+	if err != nil {
+		return nil, fmt.Errorf("error counting songbook songs: %w", err)
+	}
+	return book, nil
+}
+
+// songbookNameTaken reports whether another songbook (not id) has the name
+func songbookNameTaken(q interface {
+	QueryRow(string, ...interface{}) *sql.Row
+}, name string, id int) (bool, error) {
+	var taken bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM songbooks WHERE LOWER(name) = LOWER($1) AND id <> $2)`, name, id).Scan(&taken)
+	return taken, err
+}
+
+// CreateSongbook adds a songbook
+func (db *DB) CreateSongbook(req *models.SongbookRequest) (*models.Songbook, error) {
+	taken, err := songbookNameTaken(db, req.Name, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error checking songbook name: %w", err)
+	}
+	if taken {
+		return nil, fmt.Errorf("songbook already exists")
+	}
+
+	book, err := scanSongbook(db.QueryRow(`
+		INSERT INTO songbooks (name, abbreviation, description, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING `+songbookColumns,
+		req.Name, req.Abbreviation, req.Description))
+	if err != nil {
+		return nil, fmt.Errorf("error creating songbook: %w", err)
+	}
+	return book, nil
+}
+
+// UpdateSongbook changes a songbook's details. Renaming it renames the
+// references in song_numbers too, so its songs stay in it.
+func (db *DB) UpdateSongbook(id int, req *models.SongbookRequest) (*models.Songbook, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldName string
+	err = tx.QueryRow(`SELECT name FROM songbooks WHERE id = $1 FOR UPDATE`, id).Scan(&oldName)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("songbook not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting songbook: %w", err)
+	}
+
+	taken, err := songbookNameTaken(tx, req.Name, id)
+	if err != nil {
+		return nil, fmt.Errorf("error checking songbook name: %w", err)
+	}
+	if taken {
+		return nil, fmt.Errorf("songbook already exists")
+	}
+
+	_, err = tx.Exec(`
+		UPDATE songbooks SET name = $1, abbreviation = $2, description = $3, updated_at = NOW()
+		WHERE id = $4
+	`, req.Name, req.Abbreviation, req.Description, id)
+	if err != nil {
+		return nil, fmt.Errorf("error updating songbook: %w", err)
+	}
+	if oldName != req.Name {
+		if _, err := tx.Exec(`UPDATE song_numbers SET songbook = $1 WHERE LOWER(songbook) = LOWER($2)`, req.Name, oldName); err != nil {
+			return nil, fmt.Errorf("error renaming songbook references: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing songbook: %w", err)
+	}
+	return db.GetSongbook(id)
+}
+
+// DeleteSongbook removes a songbook and the song numbers in it. The songs
+// themselves are kept.
+func (db *DB) DeleteSongbook(id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var name string
+	err = tx.QueryRow(`DELETE FROM songbooks WHERE id = $1 RETURNING name`, id).Scan(&name)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("songbook not found")
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting songbook: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM song_numbers WHERE LOWER(songbook) = LOWER($1)`, name); err != nil {
+		return fmt.Errorf("error deleting songbook numbers: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing songbook deletion: %w", err)
+	}
+	return nil
+}
+
+// GetSongbookEntries lists a songbook's songs in number order
+func (db *DB) GetSongbookEntries(id int) ([]models.SongbookEntry, error) {
+	rows, err := db.Query(`
+		SELECT n.number, s.id, s.title, s.language
+		FROM songbooks b
+		JOIN song_numbers n ON LOWER(n.songbook) = LOWER(b.name)
+		JOIN songs s ON s.id = n.song_id
+		WHERE b.id = $1 AND s.deleted_at IS NULL
+		ORDER BY `+db.dialect.numberOrder(), id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting songbook entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]models.SongbookEntry, 0)
+	for rows.Next() {
+		var e models.SongbookEntry
+		if err := rows.Scan(&e.Number, &e.SongID, &e.Title, &e.Language); err != nil {
+			return nil, fmt.Errorf("error scanning songbook entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetSongbookSongs returns a songbook's songs in number order, each with
+// Numbers set to its entry in this songbook
+func (db *DB) GetSongbookSongs(id int) ([]models.Song, error) {
+	rows, err := db.Query(`
+		SELECT `+prefixedSongColumns("s")+`, b.name, n.number
+		FROM songbooks b
+		JOIN song_numbers n ON LOWER(n.songbook) = LOWER(b.name)
+		JOIN songs s ON s.id = n.song_id
+		WHERE b.id = $1 AND s.deleted_at IS NULL
+		ORDER BY `+db.dialect.numberOrder(), id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting songbook songs: %w", err)
+	}
+	defer rows.Close()
+
+	songs := make([]models.Song, 0)
+	for rows.Next() {
+		var song models.Song
+		var n models.SongNumber
+		if err := rows.Scan(append(songFields(&song), &n.Songbook, &n.Number)...); err != nil {
+			return nil, fmt.Errorf("error scanning songbook song: %w", err)
+		}
+		song.Numbers = []models.SongNumber{n}
+		songs = append(songs, song)
+	}
+	return songs, rows.Err()
+}
+
+// SetSongbookEntry puts a song at a number in a songbook
+func (db *DB) SetSongbookEntry(id int, number, songID string) error {
+	book, err := db.GetSongbook(id)
+	if err != nil {
+		return err
+	}
+	if _, err := db.GetSong(songID); err != nil {
+		return err
+	}
+
+	taken, err := db.TakenSongNumbers(songID, []models.SongNumber{{Songbook: book.Name, Number: number}})
+	if err != nil {
+		return err
+	}
+	if len(taken) > 0 {
+		return fmt.Errorf("song number already used")
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO song_numbers (song_id, songbook, number)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (
+			SELECT 1 FROM song_numbers WHERE LOWER(songbook) = LOWER($2) AND LOWER(number) = LOWER($3)
+		)
+	`, songID, book.Name, number)
+	if err != nil {
+		return fmt.Errorf("error adding song to songbook: %w", err)
+	}
+	return nil
+}
+
+// DeleteSongbookEntry removes the song at a number from a songbook
+func (db *DB) DeleteSongbookEntry(id int, number string) error {
+	result, err := db.Exec(`
+		DELETE FROM song_numbers
+		WHERE LOWER(songbook) = (SELECT LOWER(name) FROM songbooks WHERE id = $1) AND LOWER(number) = LOWER($2)
+	`, id, number)
+	if err != nil {
+		return fmt.Errorf("error removing song from songbook: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("song number not found")
+	}
+	return nil
+}
@@ -0,0 +1,458 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the cgo-free "sqlite" driver
+)
+
+// SQLitePrefix marks a DATABASE_URL that is a SQLite file, e.g.
+// sqlite:./data/ast.db, for running without a PostgreSQL server
+const SQLitePrefix = "sqlite:"
+
+// sqlitePragmas are set on every connection: foreign keys are off by default
+// in SQLite, and a write waits for another connection's to finish instead of
+// failing straight away
+var sqlitePragmas = []string{
+	`PRAGMA foreign_keys = ON`,
+	`PRAGMA busy_timeout = 10000`,
+	`PRAGMA journal_mode = WAL`,
+	`PRAGMA synchronous = NORMAL`,
+}
+
+var registerSQLite sync.Once
+
+// openSQLite opens (creating it if needed) the SQLite database at path
+func openSQLite(path string) (*DB, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("error creating database directory: %w", err)
+		}
+	}
+
+	registerSQLite.Do(func() {
+		db, err := sql.Open("sqlite", "")
+		if err != nil {
+			return
+		}
+		sql.Register("ast-sqlite", sqliteDriver{db.Driver()})
+		db.Close()
+	})
+	db, err := sql.Open("ast-sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+	// Connections are cheap, but only one can write at a time
+	db.SetMaxOpenConns(8)
+	db.SetMaxIdleConns(8)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error opening database %s: %w", path, err)
+	}
+	return &DB{DB: db, dialect: sqliteDialect{}}, nil
+}
+
+// sqliteDriver wraps the SQLite driver so the package's PostgreSQL queries
+// and values work unchanged: queries are translated (see sqlite_sql.go),
+// times are stored as text in sqliteTimeFormat and read back as time.Time,
+// and transactions take the write lock when they begin.
+type sqliteDriver struct {
+	base driver.Driver
+}
+
+func (d sqliteDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	c := &sqliteConn{base: conn}
+	for _, pragma := range sqlitePragmas {
+		if err := c.exec(context.Background(), pragma); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error setting %s: %w", pragma, err)
+		}
+	}
+	return c, nil
+}
+
+// sqliteQueries caches translated queries; the package builds its queries
+// from a fixed set of pieces, so there are only so many
+var sqliteQueries sync.Map
+
+func translated(query string) sqliteQuery {
+	if q, ok := sqliteQueries.Load(query); ok {
+		return q.(sqliteQuery)
+	}
+	q := translateSQLite(query)
+	sqliteQueries.Store(query, q)
+	return q
+}
+
+type sqliteConn struct {
+	base driver.Conn
+}
+
+// exec runs SQL on the underlying connection as it is, untranslated
+func (c *sqliteConn) exec(ctx context.Context, query string) error {
+	if execer, ok := c.base.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, query, nil)
+		return err
+	}
+	stmt, err := c.base.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(nil)
+	return err
+}
+
+func (c *sqliteConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	q := translated(query)
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.base.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, q.sql)
+	} else {
+		stmt, err = c.base.Prepare(q.sql)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteStmt{base: stmt, query: q}, nil
+}
+
+func (c *sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.base.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	q := translated(query)
+	return execer.ExecContext(ctx, q.sql, sqliteArgs(q, args))
+}
+
+func (c *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.base.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	q := translated(query)
+	rows, err := queryer.QueryContext(ctx, q.sql, sqliteArgs(q, args))
+	if err != nil {
+		return nil, err
+	}
+	return newSQLiteRows(rows), nil
+}
+
+func (c *sqliteConn) Close() error { return c.base.Close() }
+
+func (c *sqliteConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts a transaction. One that may write takes the write lock
+// straight away, so it waits for other writers instead of failing when it
+// first writes; SQLite transactions are serializable either way.
+func (c *sqliteConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	begin := `BEGIN IMMEDIATE`
+	if opts.ReadOnly {
+		begin = `BEGIN`
+	}
+	if err := c.exec(ctx, begin); err != nil {
+		return nil, err
+	}
+	return sqliteTx{c}, nil
+}
+
+// CheckNamedValue leaves time.Time to sqliteArgs and everything else to
+// database/sql's usual conversions
+func (c *sqliteConn) CheckNamedValue(v *driver.NamedValue) error {
+	if _, ok := v.Value.(time.Time); ok {
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+type sqliteTx struct{ c *sqliteConn }
+
+func (tx sqliteTx) Commit() error   { return tx.c.exec(context.Background(), `COMMIT`) }
+func (tx sqliteTx) Rollback() error { return tx.c.exec(context.Background(), `ROLLBACK`) }
+
+type sqliteStmt struct {
+	base  driver.Stmt
+	query sqliteQuery
+}
+
+func (s *sqliteStmt) Close() error { return s.base.Close() }
+
+// NumInput is unknown: a numbered parameter can be used more than once
+func (s *sqliteStmt) NumInput() int { return -1 }
+
+func (s *sqliteStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *sqliteStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *sqliteStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	args = sqliteArgs(s.query, args)
+	if execer, ok := s.base.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+	return s.base.Exec(driverValues(args))
+}
+
+func (s *sqliteStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	args = sqliteArgs(s.query, args)
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.base.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.base.Query(driverValues(args))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newSQLiteRows(rows), nil
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+func driverValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	return values
+}
+
+// sqliteArgs converts arguments to what the translated query expects: times
+// as sqliteTimeFormat text and = ANY arrays as JSON
+func sqliteArgs(q sqliteQuery, args []driver.NamedValue) []driver.NamedValue {
+	converted := make([]driver.NamedValue, len(args))
+	for i, a := range args {
+		switch v := a.Value.(type) {
+		case time.Time:
+			a.Value = v.UTC().Format(sqliteTimeFormat)
+		case string:
+			if q.arrays[a.Ordinal] {
+				a.Value = sqliteArray(v)
+			}
+		case []byte:
+			if q.arrays[a.Ordinal] {
+				a.Value = sqliteArray(string(v))
+			}
+		}
+		converted[i] = a
+	}
+	return converted
+}
+
+// sqliteRows reads stored times back as time.Time: text in a column declared
+// as a timestamp or date, and text in exactly sqliteTimeFormat in a computed
+// column such as MAX(updated_at)
+type sqliteRows struct {
+	base  driver.Rows
+	types []string
+}
+
+func newSQLiteRows(rows driver.Rows) *sqliteRows {
+	r := &sqliteRows{base: rows, types: make([]string, len(rows.Columns()))}
+	if typed, ok := rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		for i := range r.types {
+			r.types[i] = strings.ToUpper(typed.ColumnTypeDatabaseTypeName(i))
+		}
+	}
+	return r
+}
+
+func (r *sqliteRows) Columns() []string { return r.base.Columns() }
+func (r *sqliteRows) Close() error      { return r.base.Close() }
+
+func (r *sqliteRows) Next(dest []driver.Value) error {
+	if err := r.base.Next(dest); err != nil {
+		return err
+	}
+	for i, v := range dest {
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		default:
+			continue
+		}
+		switch r.types[i] {
+		case "TIMESTAMPTZ", "TIMESTAMP", "DATE":
+			if t, ok := parseSQLiteTime(s); ok {
+				dest[i] = t
+			}
+		case "":
+			if len(s) == len(sqliteTimeFormat) && s[10] == 'T' && s[len(s)-1] == 'Z' {
+				if t, err := time.Parse(sqliteTimeFormat, s); err == nil {
+					dest[i] = t
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sqliteTimeLayouts are the layouts a stored time is read with: the one it
+// is written in, then the ones SQLite's own date functions produce
+var sqliteTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+func parseSQLiteTime(s string) (time.Time, bool) {
+	for _, layout := range sqliteTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// DumpSQL writes a SQLite database out as SQL statements that recreate it,
+// for backups; LoadSQL reads them back
+func (db *DB) DumpSQL(w io.Writer) error {
+	if !db.Embedded() {
+		return fmt.Errorf("only a SQLite database can be dumped this way; use pg_dump")
+	}
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("error starting dump: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT type, name, sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite\_%' ESCAPE '\'
+		ORDER BY CASE type WHEN 'table' THEN 0 ELSE 1 END, rowid
+	`)
+	if err != nil {
+		return fmt.Errorf("error reading schema: %w", err)
+	}
+	type object struct{ kind, name, sql string }
+	var objects []object
+	for rows.Next() {
+		var o object
+		if err := rows.Scan(&o.kind, &o.name, &o.sql); err != nil {
+			rows.Close()
+			return fmt.Errorf("error reading schema: %w", err)
+		}
+		objects = append(objects, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error reading schema: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "-- SQLite dump, %s\nPRAGMA defer_foreign_keys = ON;\n", time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	for _, o := range objects {
+		if _, err := fmt.Fprintf(w, "%s;\n", o.sql); err != nil {
+			return err
+		}
+		if o.kind == "table" {
+			if err := dumpTable(tx, w, o.name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dumpTable writes an INSERT for each of a table's rows. SQLite's quote()
+// writes each value as a literal, so values come back exactly as stored.
+func dumpTable(tx *sql.Tx, w io.Writer, table string) error {
+	name := `"` + strings.ReplaceAll(table, `"`, `""`) + `"`
+	rows, err := tx.Query(`SELECT name FROM pragma_table_info($1) ORDER BY cid`, table)
+	if err != nil {
+		return fmt.Errorf("error reading columns of %s: %w", table, err)
+	}
+	var values []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			rows.Close()
+			return fmt.Errorf("error reading columns of %s: %w", table, err)
+		}
+		values = append(values, `quote("`+strings.ReplaceAll(column, `"`, `""`)+`")`)
+	}
+	rows.Close()
+	if len(values) == 0 {
+		return nil
+	}
+
+	rows, err = tx.Query(`SELECT 'INSERT INTO ` + strings.ReplaceAll(name, `'`, `''`) + ` VALUES (' || ` + strings.Join(values, ` || ', ' || `) + ` || ');' FROM ` + name)
+	if err != nil {
+		return fmt.Errorf("error dumping %s: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var insert string
+		if err := rows.Scan(&insert); err != nil {
+			return fmt.Errorf("error dumping %s: %w", table, err)
+		}
+		if _, err := io.WriteString(w, insert+"\n"); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// LoadSQL runs a dump written by DumpSQL in one transaction. Like a pg_dump
+// restore it recreates the tables, so the database must be empty.
+func (db *DB) LoadSQL(r io.Reader) error {
+	if !db.Embedded() {
+		return fmt.Errorf("only a SQLite database can be loaded this way; use psql")
+	}
+	dump, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("error reading backup: %w", err)
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Close()
+	// The dump is SQLite's own SQL, so it skips the translation
+	return conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*sqliteConn)
+		if err := c.exec(ctx, `BEGIN IMMEDIATE`); err != nil {
+			return err
+		}
+		if err := c.exec(ctx, string(dump)); err != nil {
+			c.exec(ctx, `ROLLBACK`)
+			return fmt.Errorf("error loading backup: %w", err)
+		}
+		return c.exec(ctx, `COMMIT`)
+	})
+}
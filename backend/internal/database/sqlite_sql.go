@@ -0,0 +1,493 @@
+package database
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// The queries in this package are written for PostgreSQL. On SQLite the
+// driver translates each one before it is prepared, rewriting the handful of
+// PostgreSQL constructs the package uses into their SQLite equivalents:
+//
+//	$1                        ?1
+//	NOW()                     the current UTC time, in sqliteTimeFormat
+//	NOW() - INTERVAL '7 days' the same, seven days ago
+//	CURRENT_DATE              today's date in local time
+//	x::date, x::text, x::int  date(x), CAST(x AS TEXT), CAST(x AS INTEGER)
+//	x::uuid, x::jsonb         x
+//	x = ANY($1)               x IN (SELECT value FROM json_each(?1)), with
+//	                          the array argument passed as JSON
+//	TO_CHAR(x, 'YYYY-MM-DD')  strftime('%Y-%m-%d', x)
+//	ILIKE                     LIKE, which is case-insensitive in SQLite
+//	IS [NOT] DISTINCT FROM    IS NOT / IS
+//	FOR UPDATE                nothing; SQLite transactions lock the database
+//
+// ORDER BY terms also get an explicit NULLS FIRST or NULLS LAST, since
+// SQLite sorts nulls first where PostgreSQL sorts them last. Everything else
+// is assumed to mean the same in both; the few queries that don't are
+// chosen by the dialect instead.
+
+// sqliteTimeFormat is how times are stored on SQLite: UTC with milliseconds,
+// which sorts and compares as text
+const sqliteTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// sqliteNow is NOW() in sqliteTimeFormat
+const sqliteNow = `strftime('%Y-%m-%dT%H:%M:%fZ', 'now')`
+
+// sqliteQuery is a query translated for SQLite
+type sqliteQuery struct {
+	sql string
+	// arrays are the parameters compared with = ANY, whose PostgreSQL array
+	// arguments are passed to json_each as JSON
+	arrays map[int]bool
+}
+
+type tokenKind int
+
+const (
+	tokSpace tokenKind = iota
+	tokWord
+	tokNumber
+	tokString
+	tokIdent // "quoted identifier"
+	tokParam
+	tokPunct
+	tokRaw // translated SQL, kept as one operand
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits a query into tokens, keeping comments and whitespace as
+// space so the translated query reads like the original
+func tokenize(query string) []token {
+	var tokens []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		start := i
+		kind := tokPunct
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			for i < len(query) && strings.IndexByte(" \t\n\r", query[i]) >= 0 {
+				i++
+			}
+			kind = tokSpace
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			kind = tokSpace
+		case c == '\'' || c == '"':
+			i++
+			for i < len(query) {
+				if query[i] == c {
+					if i+1 < len(query) && query[i+1] == c {
+						i += 2
+						continue
+					}
+					break
+				}
+				i++
+			}
+			i++
+			if i > len(query) {
+				i = len(query)
+			}
+			kind = tokString
+			if c == '"' {
+				kind = tokIdent
+			}
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			i++
+			for i < len(query) && isDigit(query[i]) {
+				i++
+			}
+			kind = tokParam
+		case isWordStart(c):
+			for i < len(query) && (isWordStart(query[i]) || isDigit(query[i])) {
+				i++
+			}
+			kind = tokWord
+		case isDigit(c):
+			for i < len(query) && (isDigit(query[i]) || query[i] == '.') {
+				i++
+			}
+			kind = tokNumber
+		case c == ':' && strings.HasPrefix(query[i:], "::"):
+			i += 2
+		default:
+			i++
+		}
+		tokens = append(tokens, token{kind, query[start:i]})
+	}
+	return tokens
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+// is reports whether t is the keyword or punctuation s
+func (t token) is(s string) bool {
+	return (t.kind == tokWord || t.kind == tokPunct) && strings.EqualFold(t.text, s)
+}
+
+// next returns the index of the first token at or after i that isn't space,
+// or len(tokens)
+func next(tokens []token, i int) int {
+	for i < len(tokens) && tokens[i].kind == tokSpace {
+		i++
+	}
+	return i
+}
+
+// matchSeq reports whether the tokens from i, skipping space, are words, and
+// returns the index after the last
+func matchSeq(tokens []token, i int, words ...string) (int, bool) {
+	for _, w := range words {
+		i = next(tokens, i)
+		if i >= len(tokens) || !tokens[i].is(w) {
+			return 0, false
+		}
+		i++
+	}
+	return i, true
+}
+
+// closing returns the index of the parenthesis closing the one at open
+func closing(tokens []token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is("("):
+			depth++
+		case tokens[i].is(")"):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+func joinTokens(tokens []token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString(t.text)
+	}
+	return b.String()
+}
+
+// translateSQLite rewrites a PostgreSQL query for SQLite
+func translateSQLite(query string) sqliteQuery {
+	q := sqliteQuery{arrays: make(map[int]bool)}
+	tokens := translateFunctions(tokenize(query), q.arrays)
+	tokens = translateCasts(tokens)
+	tokens = orderNullsLast(tokens)
+	q.sql = joinTokens(tokens)
+	return q
+}
+
+// translateFunctions rewrites the functions, keywords and parameters
+func translateFunctions(tokens []token, arrays map[int]bool) []token {
+	out := make([]token, 0, len(tokens))
+	raw := func(s string) {
+		// An expression used as a column default must be parenthesized
+		last := len(out) - 1
+		for last >= 0 && out[last].kind == tokSpace {
+			last--
+		}
+		if last >= 0 && out[last].is("DEFAULT") {
+			s = "(" + s + ")"
+		}
+		out = append(out, token{tokRaw, s})
+	}
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.kind == tokParam:
+			out = append(out, token{tokParam, "?" + t.text[1:]})
+
+		case t.is("NOW"):
+			end, ok := matchSeq(tokens, i+1, "(", ")")
+			if !ok {
+				out = append(out, t)
+				break
+			}
+			// NOW() - INTERVAL '7 days'
+			if j, ok := matchSeq(tokens, end, "-", "INTERVAL"); ok {
+				if k := next(tokens, j); k < len(tokens) && tokens[k].kind == tokString {
+					interval := strings.Trim(tokens[k].text, "'")
+					raw(`strftime('%Y-%m-%dT%H:%M:%fZ', 'now', '-` + interval + `')`)
+					i = k
+					break
+				}
+			}
+			raw(sqliteNow)
+			i = end - 1
+
+		case t.is("CURRENT_DATE"):
+			raw(`date('now', 'localtime')`)
+
+		case t.is("ILIKE"):
+			out = append(out, token{tokWord, "LIKE"})
+
+		case t.is("FOR"):
+			if end, ok := matchSeq(tokens, i+1, "UPDATE"); ok {
+				for len(out) > 0 && out[len(out)-1].kind == tokSpace {
+					out = out[:len(out)-1]
+				}
+				i = end - 1
+				break
+			}
+			out = append(out, t)
+
+		case t.is("IS"):
+			if end, ok := matchSeq(tokens, i+1, "DISTINCT", "FROM"); ok {
+				out = append(out, token{tokWord, "IS NOT"})
+				i = end - 1
+			} else if end, ok := matchSeq(tokens, i+1, "NOT", "DISTINCT", "FROM"); ok {
+				out = append(out, t)
+				i = end - 1
+			} else {
+				out = append(out, t)
+			}
+
+		case t.is("="):
+			// x = ANY($1)
+			j, ok := matchSeq(tokens, i+1, "ANY", "(")
+			if p := next(tokens, j); ok && p < len(tokens) && tokens[p].kind == tokParam {
+				if end, ok := matchSeq(tokens, p+1, ")"); ok {
+					n, _ := strconv.Atoi(tokens[p].text[1:])
+					arrays[n] = true
+					out = append(out, token{tokRaw, "IN (SELECT value FROM json_each(?" + strconv.Itoa(n) + "))"})
+					i = end - 1
+					break
+				}
+			}
+			out = append(out, t)
+
+		case t.is("TO_CHAR"):
+			open := next(tokens, i+1)
+			if open >= len(tokens) || !tokens[open].is("(") {
+				out = append(out, t)
+				break
+			}
+			end := closing(tokens, open)
+			args := splitArgs(tokens[open+1 : end])
+			if len(args) != 2 {
+				out = append(out, t)
+				break
+			}
+			format := strings.TrimSpace(joinTokens(args[1]))
+			expr := joinTokens(translateCasts(translateFunctions(args[0], arrays)))
+			raw("strftime(" + strftimeFormat(format) + ", " + strings.TrimSpace(expr) + ")")
+			i = end
+
+		default:
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// splitArgs splits a function's argument tokens at the top-level commas
+func splitArgs(tokens []token) [][]token {
+	var args [][]token
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		case t.is(",") && depth == 0:
+			args = append(args, tokens[start:i])
+			start = i + 1
+		}
+	}
+	return append(args, tokens[start:])
+}
+
+// strftimeFormat turns a TO_CHAR format literal into a strftime one
+func strftimeFormat(format string) string {
+	return strings.NewReplacer("YYYY", "%Y", "MM", "%m", "DD", "%d", "HH24", "%H", "MI", "%M", "SS", "%S").Replace(format)
+}
+
+// translateCasts rewrites x::type casts
+func translateCasts(tokens []token) []token {
+	out := make([]token, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		typ := next(tokens, i+1)
+		if !t.is("::") || typ >= len(tokens) {
+			out = append(out, t)
+			continue
+		}
+
+		// The operand: a parenthesized group with the function it belongs
+		// to, or a (qualified) name, parameter or literal
+		start := len(out) - 1
+		for start > 0 && out[start].kind == tokSpace {
+			start--
+		}
+		if start < 0 {
+			out = append(out, t)
+			continue
+		}
+		if out[start].is(")") {
+			depth := 0
+			for ; start >= 0; start-- {
+				if out[start].is(")") {
+					depth++
+				} else if out[start].is("(") {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			if start > 0 && out[start-1].kind == tokWord {
+				start--
+			}
+		} else {
+			for start >= 2 && out[start-1].is(".") && out[start-2].kind == tokWord {
+				start -= 2
+			}
+		}
+		operand := joinTokens(out[start:])
+		out = out[:start]
+
+		switch strings.ToLower(tokens[typ].text) {
+		case "date":
+			operand = "date(" + operand + ")"
+		case "text":
+			operand = "CAST(" + operand + " AS TEXT)"
+		case "int", "integer", "bigint":
+			operand = "CAST(" + operand + " AS INTEGER)"
+		}
+		out = append(out, token{tokRaw, operand})
+		i = typ
+	}
+	return out
+}
+
+// orderNullsLast gives every ORDER BY term PostgreSQL's null ordering: last
+// when ascending, first when descending
+func orderNullsLast(tokens []token) []token {
+	out := make([]token, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		out = append(out, tokens[i])
+		end, ok := matchSeq(tokens, i+1, "BY")
+		if !tokens[i].is("ORDER") || !ok {
+			continue
+		}
+		out = append(out, tokens[i+1:end]...)
+		i = end
+
+		// The terms run to the end of the clause: a closing parenthesis,
+		// LIMIT, OFFSET, an upsert's ON CONFLICT or the end of the statement
+		depth := 0
+		var term []token
+		flush := func() {
+			last := len(term) - 1
+			for last >= 0 && term[last].kind == tokSpace {
+				last--
+			}
+			nulls, desc := false, false
+			for _, t := range term {
+				nulls = nulls || t.is("NULLS")
+			}
+			if last >= 0 {
+				desc = term[last].is("DESC")
+			}
+			out = append(out, term[:last+1]...)
+			switch {
+			case last < 0 || nulls:
+			case desc:
+				out = append(out, token{tokRaw, " NULLS FIRST"})
+			default:
+				out = append(out, token{tokRaw, " NULLS LAST"})
+			}
+			out = append(out, term[last+1:]...)
+			term = nil
+		}
+		for ; i < len(tokens); i++ {
+			t := tokens[i]
+			if depth == 0 && (t.is(")") || t.is(";") || t.is("LIMIT") || t.is("OFFSET") || t.is("FOR") || t.is("ON")) {
+				break
+			}
+			switch {
+			case t.is("("):
+				depth++
+			case t.is(")"):
+				depth--
+			case t.is(",") && depth == 0:
+				flush()
+				out = append(out, t)
+				continue
+			}
+			term = append(term, t)
+		}
+		flush()
+		i--
+	}
+	return out
+}
+
+// sqliteArray turns a PostgreSQL array literal such as {"a","b"} into the
+// JSON array json_each reads
+func sqliteArray(literal string) string {
+	literal = strings.TrimSpace(literal)
+	if len(literal) < 2 || literal[0] != '{' || literal[len(literal)-1] != '}' {
+		return "[]"
+	}
+	body := literal[1 : len(literal)-1]
+	var items []string
+	for i := 0; i < len(body); {
+		if body[i] == '"' {
+			var b strings.Builder
+			i++
+			for i < len(body) && body[i] != '"' {
+				if body[i] == '\\' && i+1 < len(body) {
+					i++
+				}
+				b.WriteByte(body[i])
+				i++
+			}
+			items = append(items, jsonString(b.String()))
+			i++
+		} else {
+			end := strings.IndexByte(body[i:], ',')
+			if end < 0 {
+				end = len(body) - i
+			}
+			item := strings.TrimSpace(body[i : i+end])
+			switch _, err := strconv.ParseFloat(item, 64); {
+			case strings.EqualFold(item, "NULL"):
+				items = append(items, "null")
+			case err == nil:
+				items = append(items, item)
+			default:
+				items = append(items, jsonString(item))
+			}
+			i += end
+		}
+		// Skip the separating comma
+		for i < len(body) && (body[i] == ',' || body[i] == ' ') {
+			i++
+		}
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
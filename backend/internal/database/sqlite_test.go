@@ -0,0 +1,180 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// sqliteTestDB opens a migrated SQLite database in a temporary directory
+func sqliteTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := New(SQLitePrefix + filepath.Join(t.TempDir(), "ast.db"))
+	if err != nil {
+		t.Fatalf("opening: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	return db
+}
+
+func TestSQLiteMigrationsMatchPostgres(t *testing.T) {
+	pg, err := embeddedMigrations(postgresDialect{}.migrationDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lite, err := embeddedMigrations(sqliteDialect{}.migrationDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pg) != len(lite) {
+		t.Fatalf("%d SQLite migrations, want one for each of the %d PostgreSQL ones", len(lite), len(pg))
+	}
+	for i := range pg {
+		if pg[i].Version != lite[i].Version || pg[i].Name != lite[i].Name {
+			t.Errorf("SQLite migration %d is %s, want %s", lite[i].Version, lite[i].Name, pg[i].Name)
+		}
+	}
+}
+
+func TestSQLiteDatabase(t *testing.T) {
+	db := sqliteTestDB(t)
+	ctx := context.Background()
+
+	if err := db.CheckReady(ctx); err != nil {
+		t.Fatalf("CheckReady: %v", err)
+	}
+	status, err := db.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	if status.Pending != 0 || status.Current != status.Latest {
+		t.Errorf("MigrationStatus = %+v, want nothing pending", status)
+	}
+
+	proUUID := "5A3F8C9E-1B2D-4E6F-8A7B-9C0D1E2F3A4B"
+	song, err := db.CreateSong(&models.CreateSongRequest{Title: "Amazing Grace", Library: "Hymns", Language: "english", ProUUID: &proUUID, DisplayLyrics: "Amazing grace, how sweet the sound"})
+	if err != nil {
+		t.Fatalf("CreateSong: %v", err)
+	}
+	if len(song.ID) != 36 || song.CreatedAt.IsZero() {
+		t.Errorf("CreateSong = %+v, want a generated id and creation time", song)
+	}
+	if _, err := db.CreateSong(&models.CreateSongRequest{Title: "How Great Thou Art", Library: "Hymns", Language: "english", DisplayLyrics: "O Lord my God"}); err != nil {
+		t.Fatalf("CreateSong: %v", err)
+	}
+
+	found, err := db.GetSongByProUUID(strings.ToLower(proUUID))
+	if err != nil || found == nil || found.ID != song.ID {
+		t.Errorf("GetSongByProUUID = %+v, %v; want %s whatever the case of the UUID", found, err, song.ID)
+	}
+	results, err := db.SearchSongs("amaz swe", nil, false)
+	if err != nil {
+		t.Fatalf("SearchSongs: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != song.ID {
+		t.Errorf("SearchSongs found %d songs, want only %s", len(results), song.Title)
+	}
+
+	if _, err := db.AddToQueue(song.ID); err != nil {
+		t.Fatalf("AddToQueue: %v", err)
+	}
+	if _, err := db.AddToQueue(song.ID); err == nil {
+		t.Error("AddToQueue queued the same song twice")
+	}
+	queue, err := db.GetQueue()
+	if err != nil {
+		t.Fatalf("GetQueue: %v", err)
+	}
+	if len(queue) != 1 || queue[0].Song == nil || queue[0].Song.Title != song.Title {
+		t.Errorf("GetQueue = %+v, want the one queued song", queue)
+	}
+
+	if err := db.RecordSongUsage(song.ID); err != nil {
+		t.Fatalf("RecordSongUsage: %v", err)
+	}
+	months, err := db.GetSongsPerMonth(1)
+	if err != nil {
+		t.Fatalf("GetSongsPerMonth: %v", err)
+	}
+	if len(months) != 1 || months[0].Month != time.Now().Format("2006-01") || months[0].Uses != 1 {
+		t.Errorf("GetSongsPerMonth = %+v, want one use this month", months)
+	}
+
+	if err := db.RecordCCLIUsage(song); err != nil {
+		t.Fatalf("RecordCCLIUsage: %v", err)
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	report, err := db.GetCCLIReport(today, today)
+	if err != nil {
+		t.Fatalf("GetCCLIReport: %v", err)
+	}
+	if len(report) != 1 || report[0].Title != song.Title || report[0].Digital != 1 || report[0].FirstUsed != today {
+		t.Errorf("GetCCLIReport = %+v, want one use of %s today", report, song.Title)
+	}
+
+	if _, err := db.CreateUser(&models.CreateUserRequest{Username: "Admin", Role: models.RoleAdmin}, "", false); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := db.CreateUser(&models.CreateUserRequest{Username: "admin", Role: models.RoleViewer}, "", false); err == nil || err.Error() != "username already taken" {
+		t.Errorf("CreateUser with a taken username: %v, want \"username already taken\"", err)
+	}
+}
+
+func TestSQLiteDumpAndLoad(t *testing.T) {
+	db := sqliteTestDB(t)
+	song, err := db.CreateSong(&models.CreateSongRequest{Title: "It's Well", Library: "Hymns", Language: "english", DisplayLyrics: "When peace like a river"})
+	if err != nil {
+		t.Fatalf("CreateSong: %v", err)
+	}
+
+	var dump bytes.Buffer
+	if err := db.DumpSQL(&dump); err != nil {
+		t.Fatalf("DumpSQL: %v", err)
+	}
+	restored, err := New(SQLitePrefix + filepath.Join(t.TempDir(), "restored.db"))
+	if err != nil {
+		t.Fatalf("opening: %v", err)
+	}
+	defer restored.Close()
+	if err := restored.LoadSQL(&dump); err != nil {
+		t.Fatalf("LoadSQL: %v", err)
+	}
+
+	got, err := restored.GetSong(song.ID)
+	if err != nil {
+		t.Fatalf("GetSong: %v", err)
+	}
+	if got.Title != song.Title || !got.UpdatedAt.Equal(song.UpdatedAt) {
+		t.Errorf("restored song = %+v, want %+v", got, song)
+	}
+	status, err := restored.MigrationStatus(context.Background())
+	if err != nil || status.Pending != 0 {
+		t.Errorf("restored MigrationStatus = %+v, %v; want nothing pending", status, err)
+	}
+}
+
+func TestTranslateSQLite(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{`SELECT * FROM songs WHERE id = $1`, `SELECT * FROM songs WHERE id = ?1`},
+		{`UPDATE songs SET updated_at = NOW() WHERE id = $2`, `UPDATE songs SET updated_at = ` + sqliteNow + ` WHERE id = ?2`},
+		{`SELECT id FROM songs WHERE id = ANY($1)`, `SELECT id FROM songs WHERE id IN (SELECT value FROM json_each(?1))`},
+		{`SELECT title FROM songs WHERE title ILIKE $1 ORDER BY updated_at DESC LIMIT 5`, `SELECT title FROM songs WHERE title LIKE ?1 ORDER BY updated_at DESC NULLS FIRST LIMIT 5`},
+		{`SELECT COUNT(*)::int FROM song_usage WHERE service_date = CURRENT_DATE`, `SELECT CAST(COUNT(*) AS INTEGER) FROM song_usage WHERE service_date = date('now', 'localtime')`},
+		{`SELECT 'it''s $1::text' FROM songs FOR UPDATE`, `SELECT 'it''s $1::text' FROM songs`},
+	}
+	for _, tt := range tests {
+		if got := translateSQLite(tt.query).sql; got != tt.want {
+			t.Errorf("translateSQLite(%q)\n got %q\nwant %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	pq "github.com/lib/pq"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// SetSongArchived archives or restores a song
+func (db *DB) SetSongArchived(id string, archived bool) (*models.Song, error) {
+	var song models.Song
+	err := db.QueryRow(`
+		UPDATE songs
+		SET archived_at = CASE WHEN $2 THEN COALESCE(archived_at, NOW()) END
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING `+songColumns, id, archived).Scan(songFields(&song)...)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("song not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error archiving song: %w", err)
+	}
+	return &song, nil
+}
+
+// GetArchivedSongs returns archived songs, most recently archived first
+func (db *DB) GetArchivedSongs() ([]models.Song, error) {
+	return db.querySongs(`SELECT ` + songColumns + ` FROM songs WHERE archived_at IS NOT NULL AND deleted_at IS NULL ORDER BY archived_at DESC, title`)
+}
+
+// FindStaleSongs returns unarchived songs created before cutoff that haven't
+// been shown live since it
+func (db *DB) FindStaleSongs(cutoff time.Time) ([]models.Song, error) {
+	return db.querySongs(`
+		SELECT `+songColumns+` FROM songs s
+		WHERE s.archived_at IS NULL AND s.deleted_at IS NULL
+		  AND s.created_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM song_usage u WHERE u.song_id = s.id AND u.used_at >= $1)
+		ORDER BY s.title, s.id
+	`, cutoff)
+}
+
+// ArchiveSongs archives the given songs and returns how many were archived
+func (db *DB) ArchiveSongs(ids []string) (int64, error) {
+	result, err := db.Exec(`UPDATE songs SET archived_at = NOW() WHERE id = ANY($1) AND archived_at IS NULL AND deleted_at IS NULL`, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("error archiving songs: %w", err)
+	}
+	return result.RowsAffected()
+}
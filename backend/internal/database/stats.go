@@ -0,0 +1,83 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetLibraryStats counts songs overall, by state and by language
+func (db *DB) GetLibraryStats() (*models.LibraryStats, error) {
+	stats := &models.LibraryStats{ByLanguage: make([]models.LanguageCount, 0)}
+	err := db.QueryRow(`
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE archived_at IS NULL),
+		       COUNT(*) FILTER (WHERE archived_at IS NOT NULL),
+		       COUNT(*) FILTER (WHERE public),
+		       MAX(updated_at) FILTER (WHERE archived_at IS NULL),
+		       (SELECT COUNT(*) FROM songs WHERE deleted_at IS NOT NULL)
+		FROM songs
+		WHERE deleted_at IS NULL
+	`).Scan(&stats.Songs, &stats.Active, &stats.Archived, &stats.Public, &stats.LastEditAt, &stats.Trash)
+	if err != nil {
+		return nil, fmt.Errorf("error counting songs: %w", err)
+	}
+
+	rows, err := db.Query(`
+		SELECT LOWER(language), COUNT(*) AS songs
+		FROM songs
+		WHERE deleted_at IS NULL
+		GROUP BY LOWER(language)
+		ORDER BY songs DESC, LOWER(language)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error counting songs by language: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count models.LanguageCount
+		if err := rows.Scan(&count.Language, &count.Songs); err != nil {
+			return nil, fmt.Errorf("error scanning language count: %w", err)
+		}
+		stats.ByLanguage = append(stats.ByLanguage, count)
+	}
+	return stats, rows.Err()
+}
+
+// GetEditActivity counts recent edits and lists the most recently edited songs
+func (db *DB) GetEditActivity(recent int) (*models.EditActivity, error) {
+	activity := &models.EditActivity{Recent: make([]models.RecentEdit, 0)}
+	err := db.QueryRow(`
+		SELECT COUNT(*) FILTER (WHERE updated_at >= NOW() - INTERVAL '1 day'),
+		       COUNT(*) FILTER (WHERE updated_at >= NOW() - INTERVAL '7 days'),
+		       COUNT(*) FILTER (WHERE updated_at >= NOW() - INTERVAL '30 days'),
+		       COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '7 days')
+		FROM songs
+		WHERE deleted_at IS NULL
+	`).Scan(&activity.EditedLastDay, &activity.EditedLastWeek, &activity.EditedLastMonth, &activity.CreatedLastWeek)
+	if err != nil {
+		return nil, fmt.Errorf("error counting edits: %w", err)
+	}
+
+	rows, err := db.Query(`
+		SELECT id, title, language, updated_at
+		FROM songs
+		WHERE deleted_at IS NULL
+		ORDER BY updated_at DESC
+		LIMIT $1
+	`, recent)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recent edits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var edit models.RecentEdit
+		if err := rows.Scan(&edit.SongID, &edit.Title, &edit.Language, &edit.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning recent edit: %w", err)
+		}
+		activity.Recent = append(activity.Recent, edit)
+	}
+	return activity, rows.Err()
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetTimedLyrics returns a song's synced lyrics, or nil if it has none
+func (db *DB) GetTimedLyrics(songID string) (*models.TimedLyrics, error) {
+	var timed models.TimedLyrics
+	var lines []byte
+	err := db.QueryRow(`
+		SELECT song_id, lines, offset_ms, updated_at FROM song_timed_lyrics WHERE song_id = $1
+	`, songID).Scan(&timed.SongID, &lines, &timed.OffsetMs, &timed.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting timed lyrics: %w", err)
+	}
+	if err := json.Unmarshal(lines, &timed.Lines); err != nil {
+		return nil, fmt.Errorf("error decoding timed lyrics: %w", err)
+	}
+	return &timed, nil
+}
+
+// SetTimedLyrics creates or replaces a song's synced lyrics
+func (db *DB) SetTimedLyrics(songID string, lines []models.TimedLine, offsetMs int) (*models.TimedLyrics, error) {
+	data, err := json.Marshal(lines)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding timed lyrics: %w", err)
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO song_timed_lyrics (song_id, lines, offset_ms, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (song_id) DO UPDATE
+		SET lines = EXCLUDED.lines, offset_ms = EXCLUDED.offset_ms, updated_at = NOW()
+	`, songID, data, offsetMs)
+	if err != nil {
+		return nil, fmt.Errorf("error saving timed lyrics: %w", err)
+	}
+	return db.GetTimedLyrics(songID)
+}
+
+// DeleteTimedLyrics removes a song's synced lyrics
+func (db *DB) DeleteTimedLyrics(songID string) error {
+	result, err := db.Exec(`DELETE FROM song_timed_lyrics WHERE song_id = $1`, songID)
+	if err != nil {
+		return fmt.Errorf("error deleting timed lyrics: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("timed lyrics not found")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/slowlog"
+)
+
+// The methods below shadow *sql.DB's so every query made through DB is timed
+// for the slow query log. Queries inside transactions are not.
+
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	defer slowlog.Observe(slowlog.DB, query, time.Now())
+	return db.DB.Query(query, args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer slowlog.Observe(slowlog.DB, query, time.Now())
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	defer slowlog.Observe(slowlog.DB, query, time.Now())
+	return db.DB.QueryRow(query, args...)
+}
+
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	defer slowlog.Observe(slowlog.DB, query, time.Now())
+	return db.DB.Exec(query, args...)
+}
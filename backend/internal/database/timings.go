@@ -0,0 +1,63 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	pq "github.com/lib/pq"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetSongTiming returns a song's auto-advance timing, or nil if none is set
+func (db *DB) GetSongTiming(songID string) (*models.SongTiming, error) {
+	var timing models.SongTiming
+	var seconds []int64
+	err := db.QueryRow(`
+		SELECT song_id, default_seconds, slide_seconds, loop, updated_at
+		FROM song_timings WHERE song_id = $1
+	`, songID).Scan(&timing.SongID, &timing.DefaultSeconds, pq.Array(&seconds), &timing.Loop, &timing.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting song timing: %w", err)
+	}
+
+	timing.SlideSeconds = make([]int, len(seconds))
+	for i, s := range seconds {
+		timing.SlideSeconds[i] = int(s)
+	}
+	return &timing, nil
+}
+
+// SetSongTiming creates or replaces a song's auto-advance timing
+func (db *DB) SetSongTiming(songID string, req *models.SongTimingRequest) (*models.SongTiming, error) {
+	seconds := make([]int64, len(req.SlideSeconds))
+	for i, s := range req.SlideSeconds {
+		seconds[i] = int64(s)
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO song_timings (song_id, default_seconds, slide_seconds, loop, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (song_id) DO UPDATE
+		SET default_seconds = EXCLUDED.default_seconds, slide_seconds = EXCLUDED.slide_seconds,
+		    loop = EXCLUDED.loop, updated_at = NOW()
+	`, songID, req.DefaultSeconds, pq.Array(seconds), req.Loop)
+	if err != nil {
+		return nil, fmt.Errorf("error saving song timing: %w", err)
+	}
+	return db.GetSongTiming(songID)
+}
+
+// DeleteSongTiming clears a song's auto-advance timing
+func (db *DB) DeleteSongTiming(songID string) error {
+	result, err := db.Exec(`DELETE FROM song_timings WHERE song_id = $1`, songID)
+	if err != nil {
+		return fmt.Errorf("error deleting song timing: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("timing not found")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetDeletedSongs returns the songs in the trash, most recently deleted first
+func (db *DB) GetDeletedSongs() ([]models.Song, error) {
+	return db.querySongs(`SELECT ` + songColumns + ` FROM songs WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, title`)
+}
+
+// RestoreSong takes a song out of the trash. If another song has been linked
+// to its ProPresenter item meanwhile, the restored song is unlinked.
+func (db *DB) RestoreSong(id string) (*models.Song, error) {
+	var song models.Song
+	err := db.QueryRow(`
+		UPDATE songs AS s
+		SET deleted_at = NULL,
+		    pro_uuid = CASE WHEN EXISTS (
+		        SELECT 1 FROM songs o WHERE o.pro_uuid = s.pro_uuid AND o.id <> s.id AND o.deleted_at IS NULL
+		    ) THEN NULL ELSE s.pro_uuid END
+		WHERE s.id = $1 AND s.deleted_at IS NOT NULL
+		RETURNING `+songColumns, id).Scan(songFields(&song)...)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("song not in trash")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error restoring song: %w", err)
+	}
+	return &song, nil
+}
+
+// PurgeSong deletes a song in the trash for good, with everything attached
+// to it
+func (db *DB) PurgeSong(id string) error {
+	result, err := db.Exec(`DELETE FROM songs WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting song: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("song not in trash")
+	}
+	return nil
+}
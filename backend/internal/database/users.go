@@ -0,0 +1,296 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const userColumns = `id, username, display_name, email, role, disabled, must_reset_password, password_hash,
+	COALESCE(oidc_subject, ''), last_login_at, created_at, updated_at`
+
+func scanUser(row interface{ Scan(...interface{}) error }) (*models.User, error) {
+	var u models.User
+	if err := row.Scan(&u.ID, &u.Username, &u.DisplayName, &u.Email, &u.Role, &u.Disabled, &u.MustResetPassword, &u.PasswordHash,
+		&u.OIDCSubject, &u.LastLoginAt, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return nil, err
+	}
+	u.HasPassword = u.PasswordHash != ""
+	u.SSO = u.OIDCSubject != ""
+	return &u, nil
+}
+
+// CreateUser adds an account. passwordHash may be empty for an account that
+// can't sign in with a password yet.
+func (db *DB) CreateUser(req *models.CreateUserRequest, passwordHash string, mustReset bool) (*models.User, error) {
+	return db.createUser(req, passwordHash, mustReset, nil)
+}
+
+// CreateOIDCUser adds an account that signs in with single sign-on only
+func (db *DB) CreateOIDCUser(req *models.CreateUserRequest, subject string) (*models.User, error) {
+	return db.createUser(req, "", false, &subject)
+}
+
+func (db *DB) createUser(req *models.CreateUserRequest, passwordHash string, mustReset bool, subject *string) (*models.User, error) {
+	row := db.QueryRow(`
+		INSERT INTO users (username, display_name, email, role, password_hash, must_reset_password, oidc_subject)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+userColumns, req.Username, req.DisplayName, req.Email, req.Role, passwordHash, mustReset, subject)
+	user, err := scanUser(row)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("username already taken")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating user: %w", err)
+	}
+	return user, nil
+}
+
+// GetUsers lists every account by username
+func (db *DB) GetUsers() ([]models.User, error) {
+	rows, err := db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY LOWER(username)`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning user: %w", err)
+		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
+
+// GetUser returns an account
+func (db *DB) GetUser(id int) (*models.User, error) {
+	user, err := scanUser(db.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+	return user, nil
+}
+
+// GetUserByUsername returns an account by username, ignoring case
+func (db *DB) GetUserByUsername(username string) (*models.User, error) {
+	user, err := scanUser(db.QueryRow(`SELECT `+userColumns+` FROM users WHERE LOWER(username) = LOWER($1)`, username))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+	return user, nil
+}
+
+// GetUserByOIDCSubject returns the account linked to a single sign-on identity
+func (db *DB) GetUserByOIDCSubject(subject string) (*models.User, error) {
+	user, err := scanUser(db.QueryRow(`SELECT `+userColumns+` FROM users WHERE oidc_subject = $1`, subject))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+	return user, nil
+}
+
+// GetUserByEmail returns the account with an email address, or whose
+// username is that address, ignoring case
+func (db *DB) GetUserByEmail(email string) (*models.User, error) {
+	user, err := scanUser(db.QueryRow(`
+		SELECT `+userColumns+` FROM users
+		WHERE LOWER(email) = LOWER($1) OR LOWER(username) = LOWER($1)
+		ORDER BY LOWER(username) = LOWER($1) DESC, id
+		LIMIT 1
+	`, email))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+	return user, nil
+}
+
+// LinkOIDCSubject links an account to a single sign-on identity
+func (db *DB) LinkOIDCSubject(id int, subject string) error {
+	result, err := db.Exec(`UPDATE users SET oidc_subject = $2, updated_at = NOW() WHERE id = $1`, id, subject)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("identity already linked to another user")
+	}
+	if err != nil {
+		return fmt.Errorf("error linking user: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// UpdateUser changes the fields of an account that are set in req
+func (db *DB) UpdateUser(id int, req *models.UpdateUserRequest) (*models.User, error) {
+	row := db.QueryRow(`
+		UPDATE users
+		SET display_name = COALESCE($2, display_name), email = COALESCE($3, email),
+		    role = COALESCE($4, role), disabled = COALESCE($5, disabled), updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+userColumns, id, req.DisplayName, req.Email, req.Role, req.Disabled)
+	user, err := scanUser(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error updating user: %w", err)
+	}
+	return user, nil
+}
+
+// SetUserPassword replaces an account's password hash
+func (db *DB) SetUserPassword(id int, passwordHash string, mustReset bool) error {
+	result, err := db.Exec(`
+		UPDATE users SET password_hash = $2, must_reset_password = $3, updated_at = NOW() WHERE id = $1
+	`, id, passwordHash, mustReset)
+	if err != nil {
+		return fmt.Errorf("error setting password: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// CountActiveAdmins counts the enabled admin accounts, so the last one can't
+// be disabled or demoted
+func (db *DB) CountActiveAdmins() (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM users WHERE role = 'admin' AND NOT disabled`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting admins: %w", err)
+	}
+	return count, nil
+}
+
+const sessionColumns = `s.id, s.user_id, u.username, s.user_agent, s.ip, s.created_at, s.last_seen_at, s.expires_at`
+
+func scanSession(row interface{ Scan(...interface{}) error }) (*models.UserSession, error) {
+	var s models.UserSession
+	if err := row.Scan(&s.ID, &s.UserID, &s.Username, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// CreateSession signs a user in, storing the hash of the session token and
+// recording the sign-in. Expired sessions are cleared out at the same time.
+func (db *DB) CreateSession(userID int, tokenHash, userAgent, ip string, expiresAt time.Time) (*models.UserSession, error) {
+	if _, err := db.Exec(`DELETE FROM user_sessions WHERE expires_at < NOW()`); err != nil {
+		return nil, fmt.Errorf("error clearing expired sessions: %w", err)
+	}
+	if _, err := db.Exec(`UPDATE users SET last_login_at = NOW() WHERE id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("error recording sign-in: %w", err)
+	}
+
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO user_sessions (user_id, token_hash, user_agent, ip, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, userID, tokenHash, userAgent, ip, expiresAt).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session: %w", err)
+	}
+	return db.getSession(`s.id = $1`, id)
+}
+
+// GetSessionUser returns the unexpired session with a token hash and its
+// user, marking the session as seen (at most once a minute)
+func (db *DB) GetSessionUser(tokenHash string) (*models.User, *models.UserSession, error) {
+	session, err := db.getSession(`s.token_hash = $1 AND s.expires_at > NOW()`, tokenHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := db.GetUser(session.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if time.Since(session.LastSeenAt) > time.Minute {
+		if _, err := db.Exec(`UPDATE user_sessions SET last_seen_at = NOW() WHERE id = $1`, session.ID); err != nil {
+			return nil, nil, fmt.Errorf("error updating session: %w", err)
+		}
+	}
+	return user, session, nil
+}
+
+func (db *DB) getSession(where string, arg interface{}) (*models.UserSession, error) {
+	row := db.QueryRow(`
+		SELECT `+sessionColumns+`
+		FROM user_sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE `+where, arg)
+	session, err := scanSession(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting session: %w", err)
+	}
+	return session, nil
+}
+
+// GetActiveSessions lists unexpired sessions, most recently seen first,
+// optionally only those of one user (userID 0 for all)
+func (db *DB) GetActiveSessions(userID int) ([]models.UserSession, error) {
+	rows, err := db.Query(`
+		SELECT `+sessionColumns+`
+		FROM user_sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.expires_at > NOW() AND ($1 = 0 OR s.user_id = $1)
+		ORDER BY s.last_seen_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]models.UserSession, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+// DeleteSession signs a session out
+func (db *DB) DeleteSession(id int) error {
+	result, err := db.Exec(`DELETE FROM user_sessions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting session: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("session not found")
+	}
+	return nil
+}
+
+// DeleteUserSessions signs a user out everywhere except keepID (0 to keep
+// none) and returns how many sessions ended
+func (db *DB) DeleteUserSessions(userID, keepID int) (int, error) {
+	result, err := db.Exec(`DELETE FROM user_sessions WHERE user_id = $1 AND id <> $2`, userID, keepID)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting sessions: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return int(rows), nil
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetLyricVariants returns a song's lyric variants by name
+func (db *DB) GetLyricVariants(songID string) ([]models.LyricVariant, error) {
+	rows, err := db.Query(`
+		SELECT song_id, variant, lyrics, generated, updated_at
+		FROM song_lyric_variants
+		WHERE song_id = $1
+		ORDER BY variant
+	`, songID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting lyric variants: %w", err)
+	}
+	defer rows.Close()
+
+	variants := make([]models.LyricVariant, 0)
+	for rows.Next() {
+		var v models.LyricVariant
+		if err := rows.Scan(&v.SongID, &v.Variant, &v.Lyrics, &v.Generated, &v.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning lyric variant: %w", err)
+		}
+		variants = append(variants, v)
+	}
+	return variants, rows.Err()
+}
+
+// GetLyricVariant returns one of a song's lyric variants, or nil if it has none
+func (db *DB) GetLyricVariant(songID, variant string) (*models.LyricVariant, error) {
+	var v models.LyricVariant
+	err := db.QueryRow(`
+		SELECT song_id, variant, lyrics, generated, updated_at
+		FROM song_lyric_variants
+		WHERE song_id = $1 AND variant = $2
+	`, songID, variant).Scan(&v.SongID, &v.Variant, &v.Lyrics, &v.Generated, &v.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting lyric variant: %w", err)
+	}
+	return &v, nil
+}
+
+// SetLyricVariant creates or replaces a song's lyric variant. A generated
+// variant does not overwrite one edited by hand unless force is set.
+func (db *DB) SetLyricVariant(songID, variant, lyrics string, generated, force bool) (*models.LyricVariant, error) {
+	_, err := db.Exec(`
+		INSERT INTO song_lyric_variants (song_id, variant, lyrics, generated, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (song_id, variant) DO UPDATE
+		SET lyrics = EXCLUDED.lyrics, generated = EXCLUDED.generated, updated_at = NOW()
+		WHERE song_lyric_variants.generated OR NOT EXCLUDED.generated OR $5
+	`, songID, variant, lyrics, generated, force)
+	if err != nil {
+		return nil, fmt.Errorf("error saving lyric variant: %w", err)
+	}
+	return db.GetLyricVariant(songID, variant)
+}
+
+// DeleteLyricVariant removes one of a song's lyric variants
+func (db *DB) DeleteLyricVariant(songID, variant string) error {
+	result, err := db.Exec(`DELETE FROM song_lyric_variants WHERE song_id = $1 AND variant = $2`, songID, variant)
+	if err != nil {
+		return fmt.Errorf("error deleting lyric variant: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("lyric variant not found")
+	}
+	return nil
+}
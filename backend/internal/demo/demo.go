@@ -0,0 +1,132 @@
+// Package demo builds a small sample library, so a new church or a frontend
+// developer can try the system without importing their own songs first. It
+// is a migration archive, imported like any other into an empty install.
+package demo
+
+import (
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// Library is the library name the demo songs are filed under
+const Library = "Demo"
+
+// Archive returns the demo library: songs, a numbered songbook, band cues and
+// presenter notes, last and next Sunday's setlists, and a couple of months
+// of usage so analytics have something to show. Dates are relative to now.
+// ProPresenter settings are taken from current so seeding never disconnects
+// a configured ProPresenter.
+func Archive(now time.Time, current *models.Settings) *models.Archive {
+	archive := &models.Archive{
+		Format:     models.ArchiveFormat,
+		Version:    models.ArchiveVersion,
+		ExportedAt: now,
+		Songbooks: []models.Songbook{{
+			Name:         songbookName,
+			Abbreviation: songbookAbbreviation,
+			Description:  "Public domain hymns from the demo library",
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}},
+	}
+
+	for _, s := range songs {
+		archive.Songs = append(archive.Songs, s.model(now))
+	}
+
+	intro := 4
+	archive.SongCues = []models.SongCues{
+		{SongID: songs[0].id, IntroBars: &intro, StartsWith: "Piano alone", Dynamics: "Build from verse 3", Ending: "ritard", UpdatedAt: now},
+		{SongID: songs[2].id, StartsWith: "Full band", Dynamics: "Drop to piano on verse 3", Ending: "cold", UpdatedAt: now},
+	}
+	section := 2
+	archive.SongNotes = []models.SongNote{
+		{SongID: songs[0].id, SectionIndex: &section, SectionLabel: "Verse 3", Note: "Key change up a tone", CreatedAt: now, UpdatedAt: now},
+		{SongID: songs[2].id, Note: "Congregation sings the last chorus a cappella", CreatedAt: now, UpdatedAt: now},
+	}
+
+	lastSunday := sunday(now)
+	nextSunday := lastSunday.AddDate(0, 0, 7)
+	archive.Setlists = []models.ArchiveSetlist{
+		setlist("Sunday Service", lastSunday, now, songs[1].id, songs[3].id, songs[7].id, songs[2].id),
+		setlist("Sunday Service", nextSunday, now, songs[5].id, songs[0].id, songs[8].id, songs[4].id),
+	}
+
+	// Two songs a Sunday for the last eight weeks
+	for week := 0; week < 8; week++ {
+		day := lastSunday.AddDate(0, 0, -7*week)
+		for i := 0; i < 2; i++ {
+			s := songs[(week*2+i)%len(songs)]
+			archive.SongUsage = append(archive.SongUsage, models.ArchiveUsage{
+				SongID:      s.id,
+				ServiceDate: day.Format("2006-01-02"),
+				UsedAt:      day.Add(10*time.Hour + time.Duration(i)*10*time.Minute),
+			})
+		}
+	}
+
+	settings := &models.ArchiveSettings{
+		ProPresenterPort:     4031,
+		ProPresenterPlaylist: "Live Queue",
+		RehearsalPlaylist:    "Rehearsal",
+	}
+	if current != nil {
+		settings.ProPresenterHost = current.ProPresenterHost
+		settings.ProPresenterPort = current.ProPresenterPort
+		settings.ProPresenterPlaylist = current.ProPresenterPlaylist
+		settings.RehearsalPlaylist = current.RehearsalPlaylist
+		settings.CCLILicense = current.CCLILicense
+		settings.CopyrightSlide = current.CopyrightSlide
+	}
+	archive.Settings = settings
+
+	return archive
+}
+
+func (s song) model(now time.Time) models.Song {
+	song := models.Song{
+		ID:                  s.id,
+		Title:               s.title,
+		Library:             Library,
+		Language:            s.language,
+		DisplayLyrics:       s.lyrics,
+		MusicMinistryLyrics: s.ministry,
+		Artist:              optional(s.artist),
+		OriginalKey:         optional(s.key),
+		TimeSignature:       optional(s.time),
+		Copyright:           optional(s.copyright),
+		Public:              true,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if song.MusicMinistryLyrics == "" {
+		song.MusicMinistryLyrics = s.lyrics
+	}
+	if s.bpm > 0 {
+		bpm := s.bpm
+		song.BPM = &bpm
+	}
+	if s.number != "" {
+		song.Numbers = []models.SongNumber{{Songbook: songbookName, Number: s.number}}
+	}
+	return song
+}
+
+func setlist(name string, day, now time.Time, ids ...string) models.ArchiveSetlist {
+	date := day.Format("2006-01-02")
+	return models.ArchiveSetlist{Name: name, ServiceDate: &date, SongIDs: ids, CreatedAt: now, UpdatedAt: now}
+}
+
+// sunday is the most recent Sunday on or before now, at midnight
+func sunday(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
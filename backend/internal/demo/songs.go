@@ -0,0 +1,372 @@
+package demo
+
+// song is one song of the demo library. Lyrics are public domain hymns, and
+// two short Psalm 23 paraphrases written for the demo so search and display
+// can be tried with Malayalam and Hindi.
+type song struct {
+	id        string
+	title     string
+	artist    string
+	language  string
+	key       string
+	bpm       int
+	time      string
+	number    string // in the demo songbook, "" for none
+	copyright string
+	lyrics    string
+	ministry  string // "" uses the lyrics
+}
+
+// Songbook the demo songs are numbered in
+const (
+	songbookName         = "Demo Hymnal"
+	songbookAbbreviation = "DH"
+)
+
+var songs = []song{
+	{
+		id:        "de000000-0000-4000-8000-000000000001",
+		title:     "Amazing Grace",
+		artist:    "John Newton",
+		language:  "english",
+		key:       "G",
+		bpm:       80,
+		time:      "3/4",
+		number:    "1",
+		copyright: "Public domain",
+		lyrics: `Verse 1
+Amazing grace! How sweet the sound
+That saved a wretch like me!
+I once was lost, but now am found;
+Was blind, but now I see.
+
+Verse 2
+'Twas grace that taught my heart to fear,
+And grace my fears relieved;
+How precious did that grace appear
+The hour I first believed.
+
+Verse 3
+Through many dangers, toils and snares,
+I have already come;
+'Tis grace hath brought me safe thus far,
+And grace will lead me home.
+
+Verse 4
+When we've been there ten thousand years,
+Bright shining as the sun,
+We've no less days to sing God's praise
+Than when we'd first begun.`,
+		ministry: `Verse 1
+G          G7        C      G
+Amazing grace! How sweet the sound
+                       D
+That saved a wretch like me!
+G         G7          C        G
+I once was lost, but now am found;
+        Em     D      G
+Was blind, but now I see.
+
+Verse 2
+G            G7          C          G
+'Twas grace that taught my heart to fear,
+                      D
+And grace my fears relieved;
+G           G7          C        G
+How precious did that grace appear
+        Em   D      G
+The hour I first believed.
+
+Verse 3
+G            G7           C       G
+Through many dangers, toils and snares,
+                   D
+I have already come;
+G              G7             C          G
+'Tis grace hath brought me safe thus far,
+        Em      D     G
+And grace will lead me home.
+
+Verse 4
+G             G7            C      G
+When we've been there ten thousand years,
+                    D
+Bright shining as the sun,
+G           G7           C         G
+We've no less days to sing God's praise
+        Em       D      G
+Than when we'd first begun.`,
+	},
+	{
+		id:        "de000000-0000-4000-8000-000000000002",
+		title:     "Holy, Holy, Holy",
+		artist:    "Reginald Heber",
+		language:  "english",
+		key:       "D",
+		bpm:       88,
+		time:      "4/4",
+		number:    "2",
+		copyright: "Public domain",
+		lyrics: `Verse 1
+Holy, holy, holy! Lord God Almighty!
+Early in the morning our song shall rise to Thee;
+Holy, holy, holy, merciful and mighty!
+God in three Persons, blessed Trinity!
+
+Verse 2
+Holy, holy, holy! All the saints adore Thee,
+Casting down their golden crowns around the glassy sea;
+Cherubim and seraphim falling down before Thee,
+Which wert, and art, and evermore shalt be.
+
+Verse 3
+Holy, holy, holy! Though the darkness hide Thee,
+Though the eye of sinful man Thy glory may not see,
+Only Thou art holy; there is none beside Thee,
+Perfect in power, in love, and purity.
+
+Verse 4
+Holy, holy, holy! Lord God Almighty!
+All Thy works shall praise Thy name, in earth, and sky, and sea;
+Holy, holy, holy, merciful and mighty!
+God in three Persons, blessed Trinity!`,
+	},
+	{
+		id:        "de000000-0000-4000-8000-000000000003",
+		title:     "It Is Well with My Soul",
+		artist:    "Horatio Spafford",
+		language:  "english",
+		key:       "C",
+		bpm:       72,
+		time:      "4/4",
+		number:    "3",
+		copyright: "Public domain",
+		lyrics: `Verse 1
+When peace like a river attendeth my way,
+When sorrows like sea billows roll;
+Whatever my lot, Thou hast taught me to say,
+It is well, it is well with my soul.
+
+Chorus
+It is well with my soul,
+It is well, it is well with my soul.
+
+Verse 2
+Though Satan should buffet, though trials should come,
+Let this blest assurance control,
+That Christ has regarded my helpless estate,
+And hath shed His own blood for my soul.
+
+Chorus
+
+Verse 3
+My sin, oh, the bliss of this glorious thought!
+My sin, not in part but the whole,
+Is nailed to the cross, and I bear it no more,
+Praise the Lord, praise the Lord, O my soul!
+
+Chorus
+
+Verse 4
+And Lord, haste the day when my faith shall be sight,
+The clouds be rolled back as a scroll;
+The trump shall resound, and the Lord shall descend,
+Even so, it is well with my soul.
+
+Chorus`,
+	},
+	{
+		id:        "de000000-0000-4000-8000-000000000004",
+		title:     "Blessed Assurance",
+		artist:    "Fanny Crosby",
+		language:  "english",
+		key:       "D",
+		bpm:       66,
+		time:      "9/8",
+		number:    "4",
+		copyright: "Public domain",
+		lyrics: `Verse 1
+Blessed assurance, Jesus is mine!
+O what a foretaste of glory divine!
+Heir of salvation, purchase of God,
+Born of His Spirit, washed in His blood.
+
+Chorus
+This is my story, this is my song,
+Praising my Savior all the day long;
+This is my story, this is my song,
+Praising my Savior all the day long.
+
+Verse 2
+Perfect submission, perfect delight,
+Visions of rapture now burst on my sight;
+Angels descending bring from above
+Echoes of mercy, whispers of love.
+
+Chorus
+
+Verse 3
+Perfect submission, all is at rest,
+I in my Savior am happy and blest,
+Watching and waiting, looking above,
+Filled with His goodness, lost in His love.
+
+Chorus`,
+	},
+	{
+		id:        "de000000-0000-4000-8000-000000000005",
+		title:     "Be Thou My Vision",
+		artist:    "Traditional Irish, tr. Mary Byrne and Eleanor Hull",
+		language:  "english",
+		key:       "D",
+		bpm:       96,
+		time:      "3/4",
+		number:    "5",
+		copyright: "Public domain",
+		lyrics: `Verse 1
+Be Thou my Vision, O Lord of my heart;
+Naught be all else to me, save that Thou art.
+Thou my best Thought, by day or by night,
+Waking or sleeping, Thy presence my light.
+
+Verse 2
+Be Thou my Wisdom, and Thou my true Word;
+I ever with Thee and Thou with me, Lord;
+Thou my great Father, I Thy true son;
+Thou in me dwelling, and I with Thee one.
+
+Verse 3
+Riches I heed not, nor man's empty praise,
+Thou mine Inheritance, now and always:
+Thou and Thou only, first in my heart,
+High King of Heaven, my Treasure Thou art.
+
+Verse 4
+High King of Heaven, my victory won,
+May I reach Heaven's joys, O bright Heaven's Sun!
+Heart of my own heart, whatever befall,
+Still be my Vision, O Ruler of all.`,
+	},
+	{
+		id:        "de000000-0000-4000-8000-000000000006",
+		title:     "Joyful, Joyful, We Adore Thee",
+		artist:    "Henry van Dyke",
+		language:  "english",
+		key:       "G",
+		bpm:       100,
+		time:      "4/4",
+		number:    "6",
+		copyright: "Public domain",
+		lyrics: `Verse 1
+Joyful, joyful, we adore Thee,
+God of glory, Lord of love;
+Hearts unfold like flowers before Thee,
+Opening to the sun above.
+Melt the clouds of sin and sadness;
+Drive the dark of doubt away;
+Giver of immortal gladness,
+Fill us with the light of day!
+
+Verse 2
+All Thy works with joy surround Thee,
+Earth and heaven reflect Thy rays,
+Stars and angels sing around Thee,
+Center of unbroken praise.
+Field and forest, vale and mountain,
+Flowery meadow, flashing sea,
+Singing bird and flowing fountain
+Call us to rejoice in Thee.`,
+	},
+	{
+		id:        "de000000-0000-4000-8000-000000000007",
+		title:     "Come, Thou Fount of Every Blessing",
+		artist:    "Robert Robinson",
+		language:  "english",
+		key:       "D",
+		bpm:       92,
+		time:      "3/4",
+		number:    "7",
+		copyright: "Public domain",
+		lyrics: `Verse 1
+Come, Thou Fount of every blessing,
+Tune my heart to sing Thy grace;
+Streams of mercy, never ceasing,
+Call for songs of loudest praise.
+Teach me some melodious sonnet,
+Sung by flaming tongues above.
+Praise the mount! I'm fixed upon it,
+Mount of Thy redeeming love.
+
+Verse 2
+Here I raise mine Ebenezer;
+Hither by Thy help I'm come;
+And I hope, by Thy good pleasure,
+Safely to arrive at home.
+Jesus sought me when a stranger,
+Wandering from the fold of God;
+He, to rescue me from danger,
+Interposed His precious blood.
+
+Verse 3
+O to grace how great a debtor
+Daily I'm constrained to be!
+Let Thy goodness, like a fetter,
+Bind my wandering heart to Thee.
+Prone to wander, Lord, I feel it,
+Prone to leave the God I love;
+Here's my heart, O take and seal it,
+Seal it for Thy courts above.`,
+	},
+	{
+		id:       "de000000-0000-4000-8000-000000000008",
+		title:    "യേശുവേ നീ എൻ ഇടയൻ",
+		artist:   "Demo",
+		language: "malayalam",
+		key:      "E",
+		bpm:      76,
+		time:     "4/4",
+		lyrics: `Verse 1
+യേശുവേ നീ എൻ ഇടയൻ
+എനിക്കൊന്നിനും മുട്ടില്ല
+പച്ചയായ പുൽപ്പുറങ്ങളിൽ
+നീ എന്നെ കിടത്തുന്നു
+
+Chorus
+ഹാലേലൂയ്യാ ഹാലേലൂയ്യാ
+നിന്നെ ഞാൻ സ്തുതിക്കും
+
+Verse 2
+സ്വസ്ഥതയുള്ള വെള്ളത്തിനരികെ
+നീ എന്നെ നടത്തുന്നു
+എന്റെ പ്രാണനെ തണുപ്പിക്കുന്നു
+നിൻ നാമം നിമിത്തം
+
+Chorus`,
+	},
+	{
+		id:       "de000000-0000-4000-8000-000000000009",
+		title:    "यीशु मेरा चरवाहा",
+		artist:   "Demo",
+		language: "hindi",
+		key:      "A",
+		bpm:      84,
+		time:     "4/4",
+		lyrics: `Verse 1
+यीशु मेरा चरवाहा है
+मुझे कुछ घटी न होगी
+हरी हरी चराइयों में
+वह मुझे बैठाता है
+
+Chorus
+हल्लेलूय्याह, हल्लेलूय्याह
+मैं तेरी स्तुति गाऊँगा
+
+Verse 2
+सुखदाई जल के झरने के पास
+वह मुझे ले चलता है
+मेरे जी में जी ले आता है
+अपने नाम के निमित्त
+
+Chorus`,
+	},
+}
@@ -0,0 +1,151 @@
+// Package duplicates tells whether two songs are likely the same song
+// entered twice. Titles are compared after normalizing case, punctuation and
+// bracketed notes such as "(Live)"; lyrics are compared by their character
+// trigrams, so line breaks, section labels, markup and small spelling
+// differences don't hide a duplicate.
+package duplicates
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+)
+
+// Thresholds for the Dice coefficient of two songs' lyric trigrams. Songs
+// with the same title need less lyric overlap to count, since a second copy
+// often has an extra verse or a different chorus layout.
+const (
+	TitleMatchSimilarity = 0.6
+	LyricsOnlySimilarity = 0.85
+)
+
+// Fingerprint is the part of a song that duplicates are found by
+type Fingerprint struct {
+	Title     string // normalized title
+	FirstLine string // normalized first line of lyrics
+	trigrams  map[string]struct{}
+}
+
+// Of fingerprints a song from its title and display lyrics
+func Of(title, text string) Fingerprint {
+	words := make([]string, 0)
+	firstLine := ""
+	for _, line := range strings.Split(lyrics.StripMarkup(text), "\n") {
+		if lyrics.IsSectionLabel(line) {
+			continue
+		}
+		normalized := normalize(line)
+		if normalized == "" {
+			continue
+		}
+		if firstLine == "" {
+			firstLine = normalized
+		}
+		words = append(words, normalized)
+	}
+
+	return Fingerprint{
+		Title:     NormalizeTitle(title),
+		FirstLine: firstLine,
+		trigrams:  trigrams(strings.Join(words, " ")),
+	}
+}
+
+// NormalizeTitle lowercases a title and drops punctuation and bracketed
+// notes, so "Amazing Grace (Live)" and "amazing grace!" compare equal
+func NormalizeTitle(title string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range title {
+		switch r {
+		case '(', '[':
+			depth++
+			b.WriteRune(' ')
+			continue
+		case ')', ']':
+			if depth > 0 {
+				depth--
+			}
+			b.WriteRune(' ')
+			continue
+		}
+		if depth == 0 {
+			b.WriteRune(r)
+		}
+	}
+	return normalize(b.String())
+}
+
+// normalize lowercases text and keeps only its words, separated by single spaces
+func normalize(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		// Vowel signs are marks and belong to their word in Indic scripts
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	return strings.Join(words, " ")
+}
+
+func trigrams(text string) map[string]struct{} {
+	runes := []rune(text)
+	set := make(map[string]struct{})
+	for i := 0; i+3 <= len(runes); i++ {
+		set[string(runes[i:i+3])] = struct{}{}
+	}
+	return set
+}
+
+// Similarity is the Dice coefficient of two songs' lyric trigrams, from 0
+// (nothing shared) to 1 (the same lyrics)
+func Similarity(a, b Fingerprint) float64 {
+	if len(a.trigrams) == 0 || len(b.trigrams) == 0 {
+		return 0
+	}
+	small, large := a.trigrams, b.trigrams
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	shared := 0
+	for gram := range small {
+		if _, ok := large[gram]; ok {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a.trigrams)+len(b.trigrams))
+}
+
+// Size is how many distinct trigrams a song's lyrics have
+func (f Fingerprint) Size() int {
+	return len(f.trigrams)
+}
+
+// LyricsOnlySizes is the range of sizes a fingerprint needs to reach
+// LyricsOnlySimilarity with one of size n. The Dice coefficient of sets of
+// sizes a <= b is at most 2a/(a+b), so lyrics much shorter or longer than
+// n's can only match with the same title.
+func LyricsOnlySizes(n int) (min, max int) {
+	const epsilon = 1e-9
+	min = int(math.Ceil(float64(n)*LyricsOnlySimilarity/(2-LyricsOnlySimilarity) - epsilon))
+	max = int(math.Floor(float64(n)*(2-LyricsOnlySimilarity)/LyricsOnlySimilarity + epsilon))
+	return min, max
+}
+
+// Match is why two songs look like duplicates
+type Match struct {
+	TitleMatch       bool    `json:"title_match"`
+	LyricsSimilarity float64 `json:"lyrics_similarity"`
+}
+
+// Compare reports whether two songs look like duplicates: the same title
+// with similar lyrics, or nearly the same lyrics under any title
+func Compare(a, b Fingerprint) (Match, bool) {
+	match := Match{
+		TitleMatch:       a.Title != "" && a.Title == b.Title,
+		LyricsSimilarity: Similarity(a, b),
+	}
+	if match.TitleMatch && match.LyricsSimilarity >= TitleMatchSimilarity {
+		return match, true
+	}
+	return match, match.LyricsSimilarity >= LyricsOnlySimilarity
+}
@@ -0,0 +1,131 @@
+// Package editlock keeps advisory, expiring locks on songs being edited so
+// two volunteers don't overwrite each other's changes. Locks live in memory:
+// a restart simply releases them.
+package editlock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// DefaultTTL is how long a lock lasts without being renewed. Editors renew it
+// by acquiring again with their token while the song is open.
+const DefaultTTL = 2 * time.Minute
+
+// ErrHeld is returned when someone else holds an unexpired lock
+var ErrHeld = errors.New("song is locked by someone else")
+
+// ErrNotHolder is returned when releasing a lock with the wrong token
+var ErrNotHolder = errors.New("lock is held by someone else")
+
+// public returns the lock without its token, as shown to other editors
+func public(l models.EditLock) models.EditLock {
+	l.Token = ""
+	return l
+}
+
+// Manager holds the current locks
+type Manager struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	locks map[string]models.EditLock
+}
+
+// New creates a lock manager; ttl <= 0 uses DefaultTTL
+func New(ttl time.Duration) *Manager {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Manager{ttl: ttl, locks: make(map[string]models.EditLock)}
+}
+
+// Acquire locks a song for holder, or renews the lock when token matches the
+// current one. If someone else holds it, the current lock is returned with
+// ErrHeld unless takeover is set.
+func (m *Manager) Acquire(songID, holder, token string, takeover bool) (models.EditLock, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	current, held := m.active(songID, now)
+	if held && token != "" && current.Token == token {
+		current.ExpiresAt = now.Add(m.ttl)
+		if holder != "" {
+			current.Holder = holder
+		}
+		m.locks[songID] = current
+		return current, nil
+	}
+	if held && !takeover {
+		return public(current), ErrHeld
+	}
+
+	lock := models.EditLock{SongID: songID, Holder: holder, Token: newToken(), AcquiredAt: now, ExpiresAt: now.Add(m.ttl)}
+	if held {
+		lock.TakenFrom = current.Holder
+	}
+	m.locks[songID] = lock
+	return lock, nil
+}
+
+// Release removes a lock held with token. Releasing a song that isn't locked
+// is not an error.
+func (m *Manager) Release(songID, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, held := m.active(songID, time.Now())
+	if !held {
+		return nil
+	}
+	if current.Token != token {
+		return ErrNotHolder
+	}
+	delete(m.locks, songID)
+	return nil
+}
+
+// Get returns the unexpired lock on a song, without its token
+func (m *Manager) Get(songID string) (models.EditLock, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, held := m.active(songID, time.Now())
+	return public(current), held
+}
+
+// HeldBy reports whether token holds the song's lock
+func (m *Manager) HeldBy(songID, token string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, held := m.active(songID, time.Now())
+	return held && token != "" && current.Token == token
+}
+
+// active returns the lock on a song, dropping it if it has expired
+func (m *Manager) active(songID string, now time.Time) (models.EditLock, bool) {
+	lock, ok := m.locks[songID]
+	if !ok {
+		return models.EditLock{}, false
+	}
+	if now.After(lock.ExpiresAt) {
+		delete(m.locks, songID)
+		return models.EditLock{}, false
+	}
+	return lock, true
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,571 @@
+// Package embedded is a search backend that runs inside the server, for
+// portable setups (camps, outreach events) with no search service to run.
+// The index is held in memory and saved as a JSON file in the data
+// directory, so it survives restarts without a reindex.
+package embedded
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
+)
+
+// ErrNotBuilt is returned by searches before the index was first built
+var ErrNotBuilt = errors.New("embedded search index has not been built yet")
+
+// indexFile is the index's file name in the data directory
+const indexFile = "search-index.json"
+
+// fileVersion is bumped when the file layout changes; older files are
+// rebuilt by the next reindex
+const fileVersion = 1
+
+// resultLimit is how many hits a search returns, as with the other backends
+const resultLimit = 50
+
+// saveDelay batches the saves of songs edited in quick succession
+const saveDelay = 2 * time.Second
+
+// Fields a word was found in
+const (
+	fieldTitle uint8 = 1 << iota
+	fieldArtist
+	fieldLyrics
+)
+
+// fieldWeight ranks a title match above an artist match above a lyrics match
+func fieldWeight(fields uint8) int {
+	switch {
+	case fields&fieldTitle != 0:
+		return 4
+	case fields&fieldArtist != 0:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// document is what the index keeps of a song: the fields of search.Document
+type document struct {
+	ID        string  `json:"id"`
+	Title     string  `json:"title"`
+	Artist    *string `json:"artist,omitempty"`
+	Lyrics    string  `json:"lyrics"`
+	Content   string  `json:"content,omitempty"`
+	Language  string  `json:"language"`
+	UpdatedAt int64   `json:"updated_at"`
+}
+
+func newDocument(song *models.Song) *document {
+	return &document{
+		ID:        song.ID,
+		Title:     song.Title,
+		Artist:    song.Artist,
+		Lyrics:    song.DisplayLyrics,
+		Content:   song.MusicMinistryLyrics,
+		Language:  song.Language,
+		UpdatedAt: song.UpdatedAt.Unix(),
+	}
+}
+
+func (d *document) song() models.Song {
+	return models.Song{
+		ID:                  d.ID,
+		Title:               d.Title,
+		Artist:              d.Artist,
+		DisplayLyrics:       d.Lyrics,
+		MusicMinistryLyrics: d.Content,
+		Language:            d.Language,
+		CreatedAt:           time.Now(), // Not stored in the index
+		UpdatedAt:           time.Unix(d.UpdatedAt, 0),
+	}
+}
+
+// savedIndex is the layout of the index file
+type savedIndex struct {
+	Version         int         `json:"version"`
+	TokenSeparators string      `json:"token_separators,omitempty"`
+	Documents       []*document `json:"documents"`
+}
+
+// Index is the embedded search backend. Its words map to the documents and
+// fields they occur in; the file only stores the documents.
+type Index struct {
+	path string
+
+	mu         sync.RWMutex
+	docs       map[string]*document
+	words      map[string]map[string]uint8 // word -> document ID -> fields
+	built      bool                        // a reindex has filled it, or it was loaded from the file
+	separators string                      // the token separators the words were split with
+	configured string                      // the token separators from the settings, applied by the next reindex
+
+	saveMu      sync.Mutex
+	savePending bool
+}
+
+// Open loads the index from dir. A missing or unreadable file leaves the
+// index unbuilt: searches fall back to PostgreSQL until a reindex fills it,
+// which the server starts by itself (see SetTokenization).
+func Open(dir string) *Index {
+	x := &Index{
+		path:  filepath.Join(dir, indexFile),
+		docs:  make(map[string]*document),
+		words: make(map[string]map[string]uint8),
+	}
+
+	switch err := x.load(); {
+	case err == nil:
+		log.Printf("Embedded search index loaded: %d songs", len(x.docs))
+	case errors.Is(err, fs.ErrNotExist):
+		log.Printf("⚠️  Embedded search index %s not found, searching PostgreSQL until it is built", x.path)
+	default:
+		log.Printf("⚠️  Embedded search index %s could not be read, searching PostgreSQL until it is rebuilt: %v", x.path, err)
+	}
+	return x
+}
+
+func (x *Index) load() error {
+	data, err := os.ReadFile(x.path)
+	if err != nil {
+		return err
+	}
+	var saved savedIndex
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	if saved.Version != fileVersion {
+		return fmt.Errorf("file version %d, expected %d", saved.Version, fileVersion)
+	}
+
+	x.separators = saved.TokenSeparators
+	x.configured = saved.TokenSeparators
+	for _, doc := range saved.Documents {
+		x.add(doc)
+	}
+	x.built = true
+	return nil
+}
+
+// save writes the index to its file, through a temporary file so a crash
+// never leaves a half-written index
+func (x *Index) save() error {
+	x.mu.RLock()
+	saved := savedIndex{Version: fileVersion, TokenSeparators: x.separators, Documents: make([]*document, 0, len(x.docs))}
+	for _, doc := range x.docs {
+		saved.Documents = append(saved.Documents, doc)
+	}
+	data, err := json.Marshal(saved)
+	x.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(x.path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(x.path), indexFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), x.path)
+}
+
+// scheduleSave saves the index shortly after a change, once for all the
+// changes made meanwhile. An unbuilt index is not saved, since the file
+// would pass for a complete one at the next start.
+func (x *Index) scheduleSave() {
+	x.saveMu.Lock()
+	defer x.saveMu.Unlock()
+	if x.savePending {
+		return
+	}
+	x.savePending = true
+	time.AfterFunc(saveDelay, func() {
+		x.saveMu.Lock()
+		x.savePending = false
+		x.saveMu.Unlock()
+		if err := x.save(); err != nil {
+			log.Printf("Error saving the embedded search index: %v", err)
+		}
+	})
+}
+
+// Flush saves changes still waiting for their batched save, before the
+// process exits
+func (x *Index) Flush() error {
+	x.saveMu.Lock()
+	pending := x.savePending
+	x.saveMu.Unlock()
+	if !pending {
+		return nil
+	}
+	return x.save()
+}
+
+// tokenize lowercases text and splits it into words at anything but letters,
+// digits and combining marks, so Indic vowel signs stay in their word, and
+// at the configured separators
+func tokenize(text, separators string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		if strings.ContainsRune(separators, r) {
+			return true
+		}
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+}
+
+// fields maps each word of a document to the fields it occurs in
+func (x *Index) fields(doc *document) map[string]uint8 {
+	found := make(map[string]uint8)
+	mark := func(text string, field uint8) {
+		for _, word := range tokenize(text, x.separators) {
+			found[word] |= field
+		}
+	}
+	mark(doc.Title, fieldTitle)
+	if doc.Artist != nil {
+		mark(*doc.Artist, fieldArtist)
+	}
+	mark(doc.Lyrics, fieldLyrics)
+	return found
+}
+
+// add stores a document, replacing the one with its ID. Callers hold mu.
+func (x *Index) add(doc *document) {
+	x.remove(doc.ID)
+	x.docs[doc.ID] = doc
+	for word, fields := range x.fields(doc) {
+		if x.words[word] == nil {
+			x.words[word] = make(map[string]uint8)
+		}
+		x.words[word][doc.ID] = fields
+	}
+}
+
+// remove drops a document and its words. Callers hold mu.
+func (x *Index) remove(id string) {
+	doc, ok := x.docs[id]
+	if !ok {
+		return
+	}
+	for word := range x.fields(doc) {
+		delete(x.words[word], id)
+		if len(x.words[word]) == 0 {
+			delete(x.words, word)
+		}
+	}
+	delete(x.docs, id)
+}
+
+// CheckReady verifies the index has been built and its directory can be
+// written
+func (x *Index) CheckReady(ctx context.Context) error {
+	if !x.Ready() {
+		return ErrNotBuilt
+	}
+	dir := filepath.Dir(x.path)
+	if info, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("data directory not available: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("data directory %s is not a directory", dir)
+	}
+	return nil
+}
+
+// Ready reports whether the index has been built. Until then searches use
+// PostgreSQL.
+func (x *Index) Ready() bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.built
+}
+
+// Stale is always false: changes are applied even before the index is built
+func (x *Index) Stale() bool {
+	return false
+}
+
+// OnReady does nothing: the index only becomes ready by being rebuilt,
+// which leaves nothing to catch up on
+func (x *Index) OnReady(fn func(stale bool)) {}
+
+// IndexSong upserts a song's document, or removes it if the song is archived
+func (x *Index) IndexSong(song *models.Song) error {
+	x.mu.Lock()
+	if song.ArchivedAt != nil {
+		x.remove(song.ID)
+	} else {
+		x.add(newDocument(song))
+	}
+	built := x.built
+	x.mu.Unlock()
+
+	if built {
+		x.scheduleSave()
+	}
+	return nil
+}
+
+// IndexSongs upserts many songs
+func (x *Index) IndexSongs(songs []models.Song) error {
+	for i := range songs {
+		x.IndexSong(&songs[i])
+	}
+	return nil
+}
+
+// DeleteSong removes a song's document
+func (x *Index) DeleteSong(id string) error {
+	x.mu.Lock()
+	x.remove(id)
+	built := x.built
+	x.mu.Unlock()
+
+	if built {
+		x.scheduleSave()
+	}
+	return nil
+}
+
+// ReindexAll rebuilds the index from songs
+func (x *Index) ReindexAll(songs []models.Song) error {
+	progress := &search.Counter{}
+	if err := x.Reindex(songs, progress); err != nil {
+		return err
+	}
+	return progress.Err()
+}
+
+// Reindex replaces the index with songs, tokenized with the configured
+// separators, and saves it
+func (x *Index) Reindex(songs []models.Song, progress search.ReindexProgress) error {
+	log.Println("Starting full reindex...")
+	started := time.Now()
+
+	songs = search.Searchable(songs)
+	progress.SetTotal(len(songs), 1)
+	progress.StartBatch(1)
+
+	x.mu.Lock()
+	x.separators = x.configured
+	x.docs = make(map[string]*document, len(songs))
+	x.words = make(map[string]map[string]uint8)
+	for i := range songs {
+		x.add(newDocument(&songs[i]))
+	}
+	x.built = true
+	x.mu.Unlock()
+
+	if err := x.save(); err != nil {
+		return fmt.Errorf("error saving index: %w", err)
+	}
+	progress.Done(len(songs))
+
+	log.Printf("Reindex complete: %d songs processed in %s", len(songs), time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// SetTokenization applies the token separators from the settings at the next
+// reindex, and reports whether the index needs one: when it was built with
+// other separators, or not built at all. Field locales have no effect, since
+// words are split by Unicode character class in every language.
+func (x *Index) SetTokenization(ctx context.Context, t search.Tokenization) (bool, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.configured = t.TokenSeparators
+	return !x.built || x.separators != t.TokenSeparators, nil
+}
+
+// hit is a document matching every word of a query
+type hit struct {
+	doc   *document
+	score int
+}
+
+// Search finds songs by title, artist and lyrics, optionally in some
+// languages. Every word of the query must match the start of a word in the
+// song; words of four letters or more also match with one typo. "*" matches
+// every song.
+func (x *Index) Search(query string, languages []string) (*search.Result, error) {
+	started := time.Now()
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if !x.built {
+		return nil, ErrNotBuilt
+	}
+
+	allowed := make(map[string]bool)
+	for _, lang := range languages {
+		if lo := strings.ToLower(strings.TrimSpace(lang)); lo != "" {
+			allowed[lo] = true
+		}
+	}
+	inLanguage := func(doc *document) bool {
+		return len(allowed) == 0 || allowed[strings.ToLower(strings.TrimSpace(doc.Language))]
+	}
+
+	var hits []hit
+	terms := tokenize(query, x.separators)
+	if query == "*" || len(terms) == 0 {
+		for _, doc := range x.docs {
+			if inLanguage(doc) {
+				hits = append(hits, hit{doc: doc})
+			}
+		}
+	} else {
+		var scores map[string]int
+		for i, term := range terms {
+			matched := x.match(term)
+			if i == 0 {
+				scores = matched
+			} else {
+				for id, score := range scores {
+					if extra, ok := matched[id]; ok {
+						scores[id] = score + extra
+					} else {
+						delete(scores, id)
+					}
+				}
+			}
+			if len(scores) == 0 {
+				break
+			}
+		}
+		for id, score := range scores {
+			if doc := x.docs[id]; inLanguage(doc) {
+				hits = append(hits, hit{doc: doc, score: score})
+			}
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		if hits[i].doc.UpdatedAt != hits[j].doc.UpdatedAt {
+			return hits[i].doc.UpdatedAt > hits[j].doc.UpdatedAt
+		}
+		return hits[i].doc.ID < hits[j].doc.ID
+	})
+
+	total := len(hits)
+	if len(hits) > resultLimit {
+		hits = hits[:resultLimit]
+	}
+	songs := make([]models.Song, 0, len(hits))
+	for _, h := range hits {
+		songs = append(songs, h.doc.song())
+	}
+
+	return &search.Result{
+		Songs:      songs,
+		TotalFound: total,
+		SearchTime: int(time.Since(started).Milliseconds()),
+	}, nil
+}
+
+// match scores the documents containing term: a whole word counts three
+// times the field weight, a word starting with term twice, and a word one
+// typo away once. Callers hold mu.
+func (x *Index) match(term string) map[string]int {
+	fuzzy := len([]rune(term)) >= 4
+	scores := make(map[string]int)
+	for word, docs := range x.words {
+		var strength int
+		switch {
+		case word == term:
+			strength = 3
+		case strings.HasPrefix(word, term):
+			strength = 2
+		case fuzzy && oneEditApart(word, term):
+			strength = 1
+		default:
+			continue
+		}
+		for id, fields := range docs {
+			if score := strength * fieldWeight(fields); score > scores[id] {
+				scores[id] = score
+			}
+		}
+	}
+	return scores
+}
+
+// oneEditApart reports whether a and b differ by exactly one inserted,
+// deleted or substituted character
+func oneEditApart(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(ra)-len(rb) > 1 {
+		return false
+	}
+	i := 0
+	for i < len(rb) && ra[i] == rb[i] {
+		i++
+	}
+	if len(ra) == len(rb) {
+		// One substitution: the rest must match
+		return i < len(ra) && string(ra[i+1:]) == string(rb[i+1:])
+	}
+	// One insertion into the shorter word
+	return string(ra[i+1:]) == string(rb[i:])
+}
+
+// IndexedSongs lists every document in the index
+func (x *Index) IndexedSongs() ([]search.IndexedSong, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if !x.built {
+		return nil, ErrNotBuilt
+	}
+	songs := make([]search.IndexedSong, 0, len(x.docs))
+	for _, doc := range x.docs {
+		songs = append(songs, search.IndexedSong{ID: doc.ID, Title: doc.Title, UpdatedAt: doc.UpdatedAt})
+	}
+	return songs, nil
+}
+
+// Stats returns the document count and the newest document's update time
+func (x *Index) Stats() (*search.IndexStats, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if !x.built {
+		return nil, ErrNotBuilt
+	}
+	stats := &search.IndexStats{Documents: int64(len(x.docs))}
+	var newest int64
+	for _, doc := range x.docs {
+		if doc.UpdatedAt > newest {
+			newest = doc.UpdatedAt
+		}
+	}
+	if newest > 0 {
+		t := time.Unix(newest, 0)
+		stats.LastIndexedAt = &t
+	}
+	return stats, nil
+}
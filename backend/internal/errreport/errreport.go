@@ -0,0 +1,271 @@
+// Package errreport sends errors and recovered panics to Sentry, or any
+// service that accepts Sentry's store API (GlitchTip, self-hosted Sentry).
+// Events are queued and sent in the background; when the queue is full they
+// are dropped rather than slowing a request down.
+package errreport
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// queueSize bounds the events waiting to be sent
+const queueSize = 100
+
+// Levels an event can be reported at
+const (
+	LevelError = "error"
+	LevelFatal = "fatal"
+)
+
+// Request is the HTTP request an event happened during
+type Request struct {
+	Method   string            `json:"method,omitempty"`
+	URL      string            `json:"url,omitempty"`
+	Query    string            `json:"query_string,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	ClientIP string            `json:"-"`
+}
+
+// Frame is one stack frame, oldest call first as Sentry expects
+type Frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	File     string `json:"abs_path"`
+	Line     int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// Event is what gets reported
+type Event struct {
+	Level   string
+	Message string
+	Err     error
+	Request *Request
+	Tags    map[string]string
+	Stack   []Frame
+}
+
+// Reporter sends events to one project
+type Reporter struct {
+	storeURL    string
+	auth        string
+	environment string
+	release     string
+	serverName  string
+	httpClient  *http.Client
+	queue       chan []byte
+}
+
+// New creates a reporter for a DSN like https://<key>@sentry.example.com/42.
+// An empty DSN returns a nil reporter, which ignores everything.
+func New(dsn, environment, release string) (*Reporter, error) {
+	if dsn == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid DSN: missing public key")
+	}
+	path := strings.Trim(u.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	project := path[slash+1:]
+	if project == "" {
+		return nil, fmt.Errorf("invalid DSN: missing project ID")
+	}
+	prefix := ""
+	if slash >= 0 {
+		prefix = "/" + path[:slash]
+	}
+
+	auth := "Sentry sentry_version=7, sentry_client=ast-errreport/1.0, sentry_key=" + u.User.Username()
+	if secret, ok := u.User.Password(); ok && secret != "" {
+		auth += ", sentry_secret=" + secret
+	}
+	hostname, _ := os.Hostname()
+
+	r := &Reporter{
+		storeURL:    fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, project),
+		auth:        auth,
+		environment: environment,
+		release:     release,
+		serverName:  hostname,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan []byte, queueSize),
+	}
+	go r.run()
+	return r, nil
+}
+
+// Capture queues an event. It is safe to call on a nil reporter.
+func (r *Reporter) Capture(event Event) {
+	if r == nil {
+		return
+	}
+	body, err := json.Marshal(r.payload(event))
+	if err != nil {
+		log.Printf("Error encoding error report: %v", err)
+		return
+	}
+	select {
+	case r.queue <- body:
+	default:
+		log.Printf("Error report queue full, dropping: %s", event.Message)
+	}
+}
+
+// CaptureError reports err with a short description of what failed
+func (r *Reporter) CaptureError(message string, err error, req *Request, tags map[string]string) {
+	r.Capture(Event{Level: LevelError, Message: message, Err: err, Request: req, Tags: tags})
+}
+
+// CapturePanic reports a recovered panic. Call it from the deferred function
+// that recovered, so the stack still shows where the panic happened.
+func (r *Reporter) CapturePanic(recovered interface{}, req *Request) {
+	if r == nil {
+		return
+	}
+	r.Capture(Event{
+		Level:   LevelFatal,
+		Message: fmt.Sprintf("panic: %v", recovered),
+		Request: req,
+		Stack:   Stack(2),
+	})
+}
+
+func (r *Reporter) run() {
+	for body := range r.queue {
+		if err := r.send(body); err != nil {
+			log.Printf("Error sending error report: %v", err)
+		}
+	}
+}
+
+func (r *Reporter) send(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, r.storeURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// payload builds the store API event
+func (r *Reporter) payload(event Event) map[string]interface{} {
+	level := event.Level
+	if level == "" {
+		level = LevelError
+	}
+	message := event.Message
+	if event.Err != nil {
+		message = fmt.Sprintf("%s: %v", event.Message, event.Err)
+	}
+
+	p := map[string]interface{}{
+		"event_id":    newEventID(),
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"level":       level,
+		"platform":    "go",
+		"logger":      "audience-stage-teleprompter",
+		"server_name": r.serverName,
+		"message":     map[string]string{"formatted": message},
+	}
+	if r.environment != "" {
+		p["environment"] = r.environment
+	}
+	if r.release != "" {
+		p["release"] = r.release
+	}
+	if len(event.Tags) > 0 {
+		p["tags"] = event.Tags
+	}
+	if event.Request != nil {
+		p["request"] = event.Request
+		if event.Request.ClientIP != "" {
+			p["user"] = map[string]string{"ip_address": event.Request.ClientIP}
+		}
+	}
+
+	// Group by what failed rather than by the full message, which usually
+	// carries IDs and addresses
+	exception := map[string]interface{}{"type": event.Message, "value": message}
+	if event.Err != nil {
+		exception["type"] = fmt.Sprintf("%T", event.Err)
+		p["fingerprint"] = []string{event.Message}
+	}
+	if len(event.Stack) > 0 {
+		exception["stacktrace"] = map[string]interface{}{"frames": event.Stack}
+	}
+	p["exception"] = map[string]interface{}{"values": []interface{}{exception}}
+	return p
+}
+
+// Stack returns the caller's stack, oldest call first, skipping skip frames
+// (0 is Stack itself)
+func Stack(skip int) []Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []Frame
+	for {
+		f, more := frames.Next()
+		module, function := splitFunction(f.Function)
+		stack = append(stack, Frame{
+			Function: function,
+			Module:   module,
+			File:     f.File,
+			Line:     f.Line,
+			InApp:    strings.Contains(module, "audience-stage-teleprompter"),
+		})
+		if !more {
+			break
+		}
+	}
+	for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+		stack[i], stack[j] = stack[j], stack[i]
+	}
+	return stack
+}
+
+// splitFunction splits "github.com/x/y/pkg.(*T).Method" into its package
+// path and function name
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:slash+1+dot], name[slash+2+dot:]
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
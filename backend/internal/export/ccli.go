@@ -0,0 +1,32 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// ccliReportHeader follows the columns of CCLI's bulk usage reporting: song
+// number and title, then a count for each reporting category
+var ccliReportHeader = []string{"CCLI Song #", "Song Title", "Authors", "Copyright", "Digital", "Print", "Record", "Translate"}
+
+// CCLIReportCSV renders a usage report for upload to CCLI reporting
+func CCLIReportCSV(report []models.CCLIReportRow) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(ccliReportHeader)
+	for _, r := range report {
+		w.Write([]string{r.CCLINumber, r.Title, deref(r.Artist), deref(r.Copyright), strconv.Itoa(r.Digital), "0", "0", "0"})
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,147 @@
+// Package export renders songs into formats used by other tools
+// (ChordPro, OpenLyrics, PowerPoint, PDF and whole-library zip archives).
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/chordpro"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+var fileNameUnsafe = regexp.MustCompile(`[^\p{L}\p{N}\-_. ]+`)
+
+var (
+	chorusLabel = regexp.MustCompile(`^(chorus|refrain|c\s*\d+$)`)
+	verseLabel  = regexp.MustCompile(`^(verse|v\s*\d+$)`)
+	bridgeLabel = regexp.MustCompile(`^(bridge|b\s*\d+$)`)
+)
+
+// ChordPro renders a song as a ChordPro file. A song with a chord chart gets
+// the chart, with the song's title, artist and key added where the chart
+// lacks them. Otherwise the music ministry lyrics are preferred since they
+// are the band's version; sections become ChordPro verse/chorus/bridge
+// environments.
+func ChordPro(song *models.Song) string {
+	if song.Chords != nil && strings.TrimSpace(*song.Chords) != "" {
+		return chartWithMeta(song, *song.Chords)
+	}
+
+	var b strings.Builder
+
+	directive(&b, "title", song.Title)
+	if song.Artist != nil {
+		directive(&b, "artist", *song.Artist)
+	}
+	if key := songKey(song); key != "" {
+		directive(&b, "key", key)
+	}
+	if song.BPM != nil {
+		directive(&b, "tempo", fmt.Sprintf("%d", *song.BPM))
+	}
+	if song.TimeSignature != nil {
+		directive(&b, "time", *song.TimeSignature)
+	}
+	directive(&b, "meta", "language "+song.Language)
+	if song.Library != "" {
+		directive(&b, "meta", "library "+song.Library)
+	}
+
+	text := song.MusicMinistryLyrics
+	if strings.TrimSpace(text) == "" {
+		text = song.DisplayLyrics
+	}
+
+	for _, section := range lyrics.ParseSections(text) {
+		b.WriteString("\n")
+		env := sectionEnvironment(section.Label)
+		switch {
+		case env != "":
+			directive(&b, "start_of_"+env, section.Label)
+		case section.Label != "":
+			directive(&b, "comment", section.Label)
+		}
+		for _, line := range section.Lines {
+			b.WriteString(lyrics.PlainLine(line))
+			b.WriteString("\n")
+		}
+		if env != "" {
+			directive(&b, "end_of_"+env, "")
+		}
+	}
+
+	return b.String()
+}
+
+// chartWithMeta puts the directives a chart is missing in front of it
+func chartWithMeta(song *models.Song, chart string) string {
+	parsed, _ := chordpro.Parse(chart)
+	var b strings.Builder
+	if parsed.Meta("title") == "" {
+		directive(&b, "title", song.Title)
+	}
+	if song.Artist != nil && parsed.Meta("artist") == "" {
+		directive(&b, "artist", *song.Artist)
+	}
+	if key := songKey(song); key != "" && parsed.Meta("key") == "" {
+		directive(&b, "key", key)
+	}
+	b.WriteString(strings.TrimRight(chart, "\n"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// ChordProSet renders several songs into one ChordPro file separated by {new_song}
+func ChordProSet(songs []models.Song) string {
+	parts := make([]string, len(songs))
+	for i := range songs {
+		parts[i] = ChordPro(&songs[i])
+	}
+	return strings.Join(parts, "\n{new_song}\n")
+}
+
+// FileName turns a song title into a safe download file name with the given extension
+func FileName(title, ext string) string {
+	name := strings.TrimSpace(fileNameUnsafe.ReplaceAllString(title, ""))
+	if name == "" {
+		name = "song"
+	}
+	return name + ext
+}
+
+func directive(b *strings.Builder, name, value string) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "}", ")")
+	if value == "" {
+		fmt.Fprintf(b, "{%s}\n", name)
+		return
+	}
+	fmt.Fprintf(b, "{%s: %s}\n", name, value)
+}
+
+// songKey prefers the key the band actually plays in
+func songKey(song *models.Song) string {
+	if song.PerformanceKey != nil && *song.PerformanceKey != "" {
+		return *song.PerformanceKey
+	}
+	if song.OriginalKey != nil {
+		return *song.OriginalKey
+	}
+	return ""
+}
+
+// sectionEnvironment maps a section label onto a ChordPro environment name
+func sectionEnvironment(label string) string {
+	normalized := lyrics.NormalizeLabel(label)
+	switch {
+	case chorusLabel.MatchString(normalized):
+		return "chorus"
+	case verseLabel.MatchString(normalized):
+		return "verse"
+	case bridgeLabel.MatchString(normalized):
+		return "bridge"
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// ICalOptions places services at a time of day. A zero Start makes every
+// service an all-day event.
+type ICalOptions struct {
+	Name     string        // calendar name shown by calendar apps
+	Start    time.Duration // offset from midnight, local (floating) time
+	Duration time.Duration
+}
+
+// ICal renders dated setlists as an iCalendar (RFC 5545) feed, one event per
+// setlist with its song titles in the description. Setlists without a
+// service date are skipped.
+func ICal(setlists []models.Setlist, opts ICalOptions) string {
+	var b strings.Builder
+	line := func(s string) { b.WriteString(foldICalLine(s)) }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Audience Stage Teleprompter//Setlists//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	if opts.Name != "" {
+		line("X-WR-CALNAME:" + escapeICal(opts.Name))
+	}
+
+	for _, s := range setlists {
+		if s.ServiceDate == nil {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", *s.ServiceDate)
+		if err != nil {
+			continue
+		}
+
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:setlist-%d@audience-stage-teleprompter", s.ID))
+		line("DTSTAMP:" + s.UpdatedAt.UTC().Format("20060102T150405Z"))
+		line("LAST-MODIFIED:" + s.UpdatedAt.UTC().Format("20060102T150405Z"))
+		if opts.Start > 0 {
+			start := date.Add(opts.Start)
+			line("DTSTART:" + start.Format("20060102T150405"))
+			line("DTEND:" + start.Add(opts.Duration).Format("20060102T150405"))
+		} else {
+			line("DTSTART;VALUE=DATE:" + date.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format("20060102"))
+		}
+		line("SUMMARY:" + escapeICal(s.Name))
+		if len(s.Songs) > 0 {
+			line("DESCRIPTION:" + escapeICal(setlistDescription(s.Songs)))
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// setlistDescription numbers the songs with the key each is played in
+func setlistDescription(songs []models.Song) string {
+	lines := make([]string, len(songs))
+	for i, song := range songs {
+		lines[i] = fmt.Sprintf("%d. %s", i+1, song.Title)
+		if key := songKey(&song); key != "" {
+			lines[i] += " (" + key + ")"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICal(s string) string {
+	return icalEscaper.Replace(s)
+}
+
+// foldICalLine ends a content line with CRLF, folding it at 75 octets without
+// splitting a UTF-8 sequence
+func foldICalLine(s string) string {
+	var b strings.Builder
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// Library archive formats, one file per song
+const (
+	LibraryOpenLyrics = "openlyrics"
+	LibraryChordPro   = "chordpro"
+)
+
+// LibraryManifest describes the contents of a library archive. It is written
+// last, as manifest.json, once every song has been added.
+type LibraryManifest struct {
+	ExportedAt time.Time              `json:"exported_at"`
+	Format     string                 `json:"format"`
+	SongCount  int                    `json:"song_count"`
+	Songs      []LibraryManifestEntry `json:"songs"`
+}
+
+// LibraryManifestEntry is one song in a library archive
+type LibraryManifestEntry struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Language    string    `json:"language"`
+	Library     string    `json:"library"`
+	File        string    `json:"file"`
+	Attachments []string  `json:"attachments,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// LibraryWriter streams songs into a zip archive as they are added, so only
+// the manifest is kept in memory
+type LibraryWriter struct {
+	zw       *zip.Writer
+	format   string
+	manifest LibraryManifest
+	names    map[string]int
+	entries  map[string]int // song ID -> manifest index
+}
+
+// NewLibraryWriter starts a library archive written to w
+func NewLibraryWriter(w io.Writer, format string) (*LibraryWriter, error) {
+	if format != LibraryOpenLyrics && format != LibraryChordPro {
+		return nil, fmt.Errorf("format must be %s or %s", LibraryOpenLyrics, LibraryChordPro)
+	}
+
+	return &LibraryWriter{
+		zw:       zip.NewWriter(w),
+		format:   format,
+		manifest: LibraryManifest{ExportedAt: time.Now().UTC(), Format: format, Songs: make([]LibraryManifestEntry, 0)},
+		names:    make(map[string]int),
+		entries:  make(map[string]int),
+	}, nil
+}
+
+// AddSong writes one song file
+func (lw *LibraryWriter) AddSong(song *models.Song) error {
+	var body, ext string
+	switch lw.format {
+	case LibraryChordPro:
+		body, ext = ChordPro(song), ".cho"
+	default:
+		body, ext = OpenLyrics(song), ".xml"
+	}
+
+	name := lw.uniqueName("songs/" + FileName(song.Title, ext))
+	if err := lw.writeFile(name, song.UpdatedAt, strings.NewReader(body)); err != nil {
+		return err
+	}
+
+	lw.entries[song.ID] = len(lw.manifest.Songs)
+	lw.manifest.Songs = append(lw.manifest.Songs, LibraryManifestEntry{
+		ID:        song.ID,
+		Title:     song.Title,
+		Language:  song.Language,
+		Library:   song.Library,
+		File:      name,
+		UpdatedAt: song.UpdatedAt,
+	})
+	lw.manifest.SongCount++
+	return nil
+}
+
+// AddAttachment writes a file belonging to an already added song under
+// attachments/<song file name>/
+func (lw *LibraryWriter) AddAttachment(songID, fileName string, modified time.Time, r io.Reader) error {
+	idx, ok := lw.entries[songID]
+	if !ok {
+		return fmt.Errorf("song %s has not been added", songID)
+	}
+
+	entry := &lw.manifest.Songs[idx]
+	dir := strings.TrimSuffix(path.Base(entry.File), path.Ext(entry.File))
+	name := lw.uniqueName(path.Join("attachments", dir, FileName(strings.TrimSuffix(fileName, path.Ext(fileName)), path.Ext(fileName))))
+	if err := lw.writeFile(name, modified, r); err != nil {
+		return err
+	}
+
+	entry.Attachments = append(entry.Attachments, name)
+	return nil
+}
+
+// Close writes the manifest and finishes the archive
+func (lw *LibraryWriter) Close() error {
+	w, err := lw.zw.CreateHeader(&zip.FileHeader{Name: "manifest.json", Method: zip.Deflate, Modified: lw.manifest.ExportedAt})
+	if err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(lw.manifest); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	return lw.zw.Close()
+}
+
+func (lw *LibraryWriter) writeFile(name string, modified time.Time, r io.Reader) error {
+	w, err := lw.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("error adding %s: %w", name, err)
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// uniqueName numbers repeated file names: "Amazing Grace.xml", "Amazing Grace (2).xml"
+func (lw *LibraryWriter) uniqueName(name string) string {
+	lw.names[name]++
+	n := lw.names[name]
+	if n == 1 {
+		return name
+	}
+
+	ext := path.Ext(name)
+	numbered := fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), n, ext)
+	if _, taken := lw.names[numbered]; taken {
+		return lw.uniqueName(name)
+	}
+	lw.names[numbered] = 1
+	return numbered
+}
@@ -0,0 +1,27 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// LRC renders a song's synced lyrics as an LRC file with centisecond timestamps
+func LRC(song *models.Song, timed *models.TimedLyrics) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[ti:%s]\n", song.Title)
+	if song.Artist != nil && *song.Artist != "" {
+		fmt.Fprintf(&b, "[ar:%s]\n", *song.Artist)
+	}
+	if timed.OffsetMs != 0 {
+		fmt.Fprintf(&b, "[offset:%+d]\n", timed.OffsetMs)
+	}
+
+	for _, line := range timed.Lines {
+		cs := line.TimeMs / 10
+		fmt.Fprintf(&b, "[%02d:%02d.%02d]%s\n", cs/6000, cs/100%60, cs%100, line.Text)
+	}
+	return b.String()
+}
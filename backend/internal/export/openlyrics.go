@@ -0,0 +1,104 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/language"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+var labelNumber = regexp.MustCompile(`\d+`)
+
+// OpenLyrics renders a song as an OpenLyrics 0.9 XML document, the format
+// read by OpenLP, FreeWorship and other presentation tools. The display
+// lyrics are exported since those are what the congregation sees.
+func OpenLyrics(song *models.Song) string {
+	var b strings.Builder
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&b, `<song xmlns="http://openlyrics.info/namespace/2009/song" version="0.9" createdIn="audience-stage-teleprompter" modifiedDate="%s">`+"\n",
+		song.UpdatedAt.UTC().Format(time.RFC3339))
+
+	b.WriteString("  <properties>\n")
+	fmt.Fprintf(&b, "    <titles>\n      <title>%s</title>\n    </titles>\n", escapeXML(song.Title))
+	if song.Artist != nil && *song.Artist != "" {
+		fmt.Fprintf(&b, "    <authors>\n      <author>%s</author>\n    </authors>\n", escapeXML(*song.Artist))
+	}
+	if song.OriginalKey != nil && *song.OriginalKey != "" {
+		fmt.Fprintf(&b, "    <key>%s</key>\n", escapeXML(*song.OriginalKey))
+	}
+	if song.BPM != nil {
+		fmt.Fprintf(&b, "    <tempo type=\"bpm\">%d</tempo>\n", *song.BPM)
+	}
+	if len(song.Numbers) > 0 {
+		b.WriteString("    <songbooks>\n")
+		for _, n := range song.Numbers {
+			fmt.Fprintf(&b, "      <songbook name=\"%s\" entry=\"%s\"/>\n", escapeXML(n.Songbook), escapeXML(n.Number))
+		}
+		b.WriteString("    </songbooks>\n")
+	}
+	if song.Library != "" {
+		fmt.Fprintf(&b, "    <themes>\n      <theme>%s</theme>\n    </themes>\n", escapeXML(song.Library))
+	}
+	b.WriteString("  </properties>\n")
+
+	lang := ""
+	if code := language.Code(song.Language); code != "" {
+		lang = fmt.Sprintf(` lang="%s"`, code)
+	}
+
+	b.WriteString("  <lyrics>\n")
+	counts := make(map[string]int)
+	for _, section := range lyrics.ParseSections(song.DisplayLyrics) {
+		if len(section.Lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "    <verse name=\"%s\"%s>\n      <lines>", verseName(section.Label, counts), lang)
+		for i, line := range section.Lines {
+			if i > 0 {
+				b.WriteString("<br/>")
+			}
+			b.WriteString(escapeXML(lyrics.PlainLine(line)))
+		}
+		b.WriteString("</lines>\n    </verse>\n")
+	}
+	b.WriteString("  </lyrics>\n</song>\n")
+
+	return b.String()
+}
+
+// verseName maps a section label onto an OpenLyrics verse name (v1, c1, b1,
+// p1 for pre-chorus, e1 for endings, o1 otherwise). Labels with a number keep
+// it; unnumbered ones are numbered in order of appearance.
+func verseName(label string, counts map[string]int) string {
+	normalized := lyrics.NormalizeLabel(label)
+
+	prefix := "o"
+	switch {
+	case strings.HasPrefix(normalized, "pre"):
+		prefix = "p"
+	case strings.HasPrefix(normalized, "ending"), strings.HasPrefix(normalized, "outro"), strings.HasPrefix(normalized, "tag"), strings.HasPrefix(normalized, "coda"):
+		prefix = "e"
+	case strings.HasPrefix(normalized, "intro"):
+		prefix = "i"
+	default:
+		switch sectionEnvironment(label) {
+		case "verse":
+			prefix = "v"
+		case "chorus":
+			prefix = "c"
+		case "bridge":
+			prefix = "b"
+		}
+	}
+
+	if n := labelNumber.FindString(normalized); n != "" {
+		return prefix + n
+	}
+	counts[prefix]++
+	return fmt.Sprintf("%s%d", prefix, counts[prefix])
+}
@@ -0,0 +1,178 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/pdf"
+)
+
+var chordToken = regexp.MustCompile(`^\(?[A-G][#b♯♭]?(m|maj|min|dim|aug|sus|add|M)?\d*(sus\d*|add\d*|maj\d*|[b#]\d+)*(/[A-G][#b♯♭]?)?\)?$`)
+
+// PDFFont is the pair of fonts lyrics in one language are set in
+type PDFFont struct {
+	Regular *pdf.Font
+	Bold    *pdf.Font
+}
+
+// PDFOptions controls how lyric sheets are rendered
+type PDFOptions struct {
+	// Chords prints the music ministry lyrics with their chord lines
+	// instead of the display lyrics
+	Chords bool
+	// Fonts maps a song language to the fonts its lyrics need
+	Fonts map[string]PDFFont
+}
+
+// LoadPDFFonts loads fonts from a spec like
+// "malayalam=/fonts/NotoSansMalayalam-Regular.ttf,malayalam-bold=/fonts/NotoSansMalayalam-Bold.ttf".
+// Fonts that fail to load are reported and skipped.
+func LoadPDFFonts(spec string) (map[string]PDFFont, []error) {
+	fonts := make(map[string]PDFFont)
+	var errs []error
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		language, path, ok := strings.Cut(entry, "=")
+		if !ok {
+			errs = append(errs, fmt.Errorf("invalid font entry %q, expected language=path", entry))
+			continue
+		}
+
+		language = strings.ToLower(strings.TrimSpace(language))
+		language, bold := strings.CutSuffix(language, "-bold")
+
+		font, err := pdf.LoadFont(strings.TrimSpace(path))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("font for %s: %w", language, err))
+			continue
+		}
+
+		pair := fonts[language]
+		if bold {
+			pair.Bold = font
+		} else {
+			pair.Regular = font
+		}
+		fonts[language] = pair
+	}
+
+	return fonts, errs
+}
+
+// PDF renders songs as a printable lyric sheet, one song per page. With a
+// title (a setlist name) the first page lists the songs in order.
+func PDF(title string, songs []models.Song, opts PDFOptions) []byte {
+	doc := pdf.New("")
+
+	if title != "" {
+		doc.SetFonts(coverFonts(songs, opts))
+		doc.Title(title)
+		for i, song := range songs {
+			doc.SetFonts(fontsFor(song.Language, opts))
+			doc.Text(fmt.Sprintf("%d. %s", i+1, songSummary(&song)))
+		}
+	}
+
+	for i := range songs {
+		if i > 0 || title != "" {
+			doc.PageBreak()
+		}
+		writePDFSong(doc, &songs[i], opts)
+	}
+
+	return doc.Bytes()
+}
+
+func writePDFSong(doc *pdf.Document, song *models.Song, opts PDFOptions) {
+	doc.SetFonts(fontsFor(song.Language, opts))
+	doc.Title(song.Title)
+
+	var meta []string
+	if song.Artist != nil && *song.Artist != "" {
+		meta = append(meta, *song.Artist)
+	}
+	if opts.Chords {
+		if key := songKey(song); key != "" {
+			meta = append(meta, "Key "+key)
+		}
+		if song.BPM != nil {
+			meta = append(meta, fmt.Sprintf("%d BPM", *song.BPM))
+		}
+		if song.TimeSignature != nil {
+			meta = append(meta, *song.TimeSignature)
+		}
+	}
+	if len(meta) > 0 {
+		doc.Text(strings.Join(meta, "  ·  "))
+		doc.Blank()
+	}
+
+	text := song.DisplayLyrics
+	if opts.Chords && strings.TrimSpace(song.MusicMinistryLyrics) != "" {
+		text = song.MusicMinistryLyrics
+	}
+
+	for _, section := range lyrics.ParseSections(text) {
+		if section.Label != "" {
+			doc.Strong(section.Label)
+		}
+		for _, line := range section.Lines {
+			line = lyrics.PlainLine(line)
+			if opts.Chords && isChordLine(line) {
+				doc.Mono(line)
+				continue
+			}
+			doc.Text(line)
+		}
+		doc.Blank()
+	}
+}
+
+// fontsFor picks the configured fonts for a language, if any
+func fontsFor(language string, opts PDFOptions) (*pdf.Font, *pdf.Font) {
+	pair := opts.Fonts[strings.ToLower(language)]
+	return pair.Regular, pair.Bold
+}
+
+// coverFonts picks fonts for the setlist title, which is not tied to one
+// song: the first configured font among the songs' languages
+func coverFonts(songs []models.Song, opts PDFOptions) (*pdf.Font, *pdf.Font) {
+	for _, song := range songs {
+		if regular, bold := fontsFor(song.Language, opts); regular != nil {
+			return regular, bold
+		}
+	}
+	return nil, nil
+}
+
+func songSummary(song *models.Song) string {
+	if key := songKey(song); key != "" {
+		return fmt.Sprintf("%s (%s)", song.Title, key)
+	}
+	return song.Title
+}
+
+// isChordLine reports whether every token on a line is a chord (or a bar
+// marker), so it is printed in a fixed-width font above its lyric
+func isChordLine(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, field := range fields {
+		if field == "|" || field == "-" || field == "/" || field == "N.C." || field == "NC" {
+			continue
+		}
+		if !chordToken.MatchString(field) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,278 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// PPTXTemplate controls how exported slides look
+type PPTXTemplate struct {
+	Background  string `json:"background"` // hex RGB, e.g. "000000"
+	TextColor   string `json:"text_color"`
+	Font        string `json:"font"`
+	FontSize    int    `json:"font_size"` // points
+	TitleSlides bool   `json:"title_slides"`
+	Widescreen  bool   `json:"widescreen"` // 16:9, otherwise 4:3
+
+	// CopyrightSlides ends each song with its attribution slide, using
+	// CCLILicense as the church's license number. Both come from settings.
+	CopyrightSlides bool   `json:"-"`
+	CCLILicense     string `json:"-"`
+}
+
+// DefaultPPTXTemplate is white text on black, like a typical lyrics screen
+var DefaultPPTXTemplate = PPTXTemplate{
+	Background:  "000000",
+	TextColor:   "FFFFFF",
+	Font:        "Arial",
+	FontSize:    40,
+	TitleSlides: true,
+	Widescreen:  true,
+}
+
+var hexColor = regexp.MustCompile(`^[0-9A-Fa-f]{6}$`)
+
+// Validate checks the template values
+func (t PPTXTemplate) Validate() error {
+	if !hexColor.MatchString(t.Background) {
+		return fmt.Errorf("background must be a 6-digit hex colour")
+	}
+	if !hexColor.MatchString(t.TextColor) {
+		return fmt.Errorf("text_color must be a 6-digit hex colour")
+	}
+	if t.Font == "" {
+		return fmt.Errorf("font is required")
+	}
+	if t.FontSize < 8 || t.FontSize > 200 {
+		return fmt.Errorf("font_size must be between 8 and 200")
+	}
+	return nil
+}
+
+const (
+	nsA = "http://schemas.openxmlformats.org/drawingml/2006/main"
+	nsR = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	nsP = "http://schemas.openxmlformats.org/presentationml/2006/main"
+
+	relOfficeDocument = nsR + "/officeDocument"
+	relSlideMaster    = nsR + "/slideMaster"
+	relSlideLayout    = nsR + "/slideLayout"
+	relSlide          = nsR + "/slide"
+	relTheme          = nsR + "/theme"
+	relExtendedProps  = nsR + "/extended-properties"
+	relCoreProps      = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"
+)
+
+type zipEntry struct {
+	name    string
+	content string
+}
+
+type pptxSlide struct {
+	lines    []string
+	title    bool
+	subtitle string
+}
+
+// PPTX renders songs into a PowerPoint deck: an optional title slide per song
+// followed by its lyric slides, split the same way as for ProPresenter, and
+// optionally its copyright slide
+func PPTX(title string, songs []models.Song, tmpl PPTXTemplate) ([]byte, error) {
+	var slides []pptxSlide
+	for _, song := range songs {
+		if tmpl.TitleSlides {
+			slide := pptxSlide{lines: []string{song.Title}, title: true}
+			if song.Artist != nil {
+				slide.subtitle = *song.Artist
+			}
+			slides = append(slides, slide)
+		}
+		segmented, _ := lyrics.Segment(song.DisplayLyrics, lyrics.DefaultSegmentOptions)
+		for _, s := range segmented {
+			slides = append(slides, pptxSlide{lines: s.Lines})
+		}
+		if tmpl.CopyrightSlides {
+			if lines := lyrics.CopyrightSlide(&song, tmpl.CCLILicense); lines != nil {
+				slides = append(slides, pptxSlide{lines: lines})
+			}
+		}
+	}
+
+	width, height := 12192000, 6858000
+	if !tmpl.Widescreen {
+		width = 9144000
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	files := []zipEntry{
+		{"[Content_Types].xml", pptxContentTypes(len(slides))},
+		{"_rels/.rels", pptxRels([][2]string{
+			{relOfficeDocument, "ppt/presentation.xml"},
+			{relCoreProps, "docProps/core.xml"},
+			{relExtendedProps, "docProps/app.xml"},
+		})},
+		{"docProps/core.xml", pptxCore(title)},
+		{"docProps/app.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Application>Audience Stage Teleprompter</Application></Properties>`},
+		{"ppt/presentation.xml", pptxPresentation(len(slides), width, height)},
+		{"ppt/_rels/presentation.xml.rels", pptxPresentationRels(len(slides))},
+		{"ppt/slideMasters/slideMaster1.xml", pptxMaster},
+		{"ppt/slideMasters/_rels/slideMaster1.xml.rels", pptxRels([][2]string{
+			{relSlideLayout, "../slideLayouts/slideLayout1.xml"},
+			{relTheme, "../theme/theme1.xml"},
+		})},
+		{"ppt/slideLayouts/slideLayout1.xml", pptxLayout},
+		{"ppt/slideLayouts/_rels/slideLayout1.xml.rels", pptxRels([][2]string{
+			{relSlideMaster, "../slideMasters/slideMaster1.xml"},
+		})},
+		{"ppt/theme/theme1.xml", pptxTheme},
+	}
+
+	for i, slide := range slides {
+		files = append(files,
+			zipEntry{fmt.Sprintf("ppt/slides/slide%d.xml", i+1), pptxSlideXML(slide, tmpl, width, height)},
+			zipEntry{fmt.Sprintf("ppt/slides/_rels/slide%d.xml.rels", i+1), pptxRels([][2]string{
+				{relSlideLayout, "../slideLayouts/slideLayout1.xml"},
+			})},
+		)
+	}
+
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, fmt.Errorf("error adding %s: %w", f.name, err)
+		}
+		if _, err := io.WriteString(w, f.content); err != nil {
+			return nil, fmt.Errorf("error writing %s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("error finishing pptx: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func pptxRels(targets [][2]string) string {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i, t := range targets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="%s" Target="%s"/>`, i+1, t[0], t[1])
+	}
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+func pptxContentTypes(slideCount int) string {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/ppt/presentation.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.presentation.main+xml"/>
+<Override PartName="/ppt/slideMasters/slideMaster1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideMaster+xml"/>
+<Override PartName="/ppt/slideLayouts/slideLayout1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideLayout+xml"/>
+<Override PartName="/ppt/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"/>
+<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+<Override PartName="/docProps/app.xml" ContentType="application/vnd.openxmlformats-officedocument.extended-properties+xml"/>
+`)
+	for i := 1; i <= slideCount; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/ppt/slides/slide%d.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slide+xml"/>`+"\n", i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func pptxCore(title string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><dc:title>%s</dc:title><dcterms:created xsi:type="dcterms:W3CDTF">%s</dcterms:created></cp:coreProperties>`,
+		escapeXML(title), time.Now().UTC().Format(time.RFC3339))
+}
+
+func pptxPresentation(slideCount, width, height int) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:presentation xmlns:a="%s" xmlns:r="%s" xmlns:p="%s"><p:sldMasterIdLst><p:sldMasterId id="2147483648" r:id="rId1"/></p:sldMasterIdLst>`, nsA, nsR, nsP)
+	if slideCount > 0 {
+		b.WriteString(`<p:sldIdLst>`)
+		for i := 0; i < slideCount; i++ {
+			fmt.Fprintf(&b, `<p:sldId id="%d" r:id="rId%d"/>`, 256+i, i+3)
+		}
+		b.WriteString(`</p:sldIdLst>`)
+	}
+	fmt.Fprintf(&b, `<p:sldSz cx="%d" cy="%d"/><p:notesSz cx="6858000" cy="9144000"/></p:presentation>`, width, height)
+	return b.String()
+}
+
+func pptxPresentationRels(slideCount int) string {
+	targets := [][2]string{
+		{relSlideMaster, "slideMasters/slideMaster1.xml"},
+		{relTheme, "theme/theme1.xml"},
+	}
+	for i := 1; i <= slideCount; i++ {
+		targets = append(targets, [2]string{relSlide, fmt.Sprintf("slides/slide%d.xml", i)})
+	}
+	return pptxRels(targets)
+}
+
+func pptxSlideXML(slide pptxSlide, tmpl PPTXTemplate, width, height int) string {
+	marginX, marginY := width/20, height/20
+
+	var paras bytes.Buffer
+	run := func(text string, size int, bold bool) {
+		b := 0
+		if bold {
+			b = 1
+		}
+		fmt.Fprintf(&paras, `<a:p><a:pPr algn="ctr"/><a:r><a:rPr lang="en-US" sz="%d" b="%d" dirty="0"><a:solidFill><a:srgbClr val="%s"/></a:solidFill><a:latin typeface="%s"/><a:cs typeface="%s"/></a:rPr><a:t>%s</a:t></a:r></a:p>`,
+			size*100, b, tmpl.TextColor, escapeXML(tmpl.Font), escapeXML(tmpl.Font), escapeXML(text))
+	}
+
+	if slide.title {
+		for _, line := range slide.lines {
+			run(line, tmpl.FontSize*5/4, true)
+		}
+		if slide.subtitle != "" {
+			run(slide.subtitle, tmpl.FontSize*3/5, false)
+		}
+	} else {
+		for _, line := range slide.lines {
+			run(line, tmpl.FontSize, false)
+		}
+	}
+	if paras.Len() == 0 {
+		paras.WriteString(`<a:p><a:endParaRPr lang="en-US"/></a:p>`)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sld xmlns:a="%s" xmlns:r="%s" xmlns:p="%s"><p:cSld><p:bg><p:bgPr><a:solidFill><a:srgbClr val="%s"/></a:solidFill><a:effectLst/></p:bgPr></p:bg><p:spTree>%s<p:sp><p:nvSpPr><p:cNvPr id="2" name="Lyrics"/><p:cNvSpPr txBox="1"/><p:nvPr/></p:nvSpPr><p:spPr><a:xfrm><a:off x="%d" y="%d"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom><a:noFill/></p:spPr><p:txBody><a:bodyPr wrap="square" anchor="ctr"><a:normAutofit/></a:bodyPr><a:lstStyle/>%s</p:txBody></p:sp></p:spTree></p:cSld><p:clrMapOvr><a:masterClrMapping/></p:clrMapOvr></p:sld>`,
+		nsA, nsR, nsP, tmpl.Background, pptxGroupProps, marginX, marginY, width-2*marginX, height-2*marginY, paras.String())
+}
+
+const pptxGroupProps = `<p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="0" cy="0"/><a:chOff x="0" y="0"/><a:chExt cx="0" cy="0"/></a:xfrm></p:grpSpPr>`
+
+const pptxMaster = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sldMaster xmlns:a="` + nsA + `" xmlns:r="` + nsR + `" xmlns:p="` + nsP + `"><p:cSld><p:bg><p:bgRef idx="1001"><a:schemeClr val="bg1"/></p:bgRef></p:bg><p:spTree>` + pptxGroupProps + `</p:spTree></p:cSld><p:clrMap bg1="lt1" tx1="dk1" bg2="lt2" tx2="dk2" accent1="accent1" accent2="accent2" accent3="accent3" accent4="accent4" accent5="accent5" accent6="accent6" hlink="hlink" folHlink="folHlink"/><p:sldLayoutIdLst><p:sldLayoutId id="2147483649" r:id="rId1"/></p:sldLayoutIdLst><p:txStyles><p:titleStyle/><p:bodyStyle/><p:otherStyle/></p:txStyles></p:sldMaster>`
+
+const pptxLayout = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sldLayout xmlns:a="` + nsA + `" xmlns:r="` + nsR + `" xmlns:p="` + nsP + `" type="blank" preserve="1"><p:cSld name="Blank"><p:spTree>` + pptxGroupProps + `</p:spTree></p:cSld><p:clrMapOvr><a:masterClrMapping/></p:clrMapOvr></p:sldLayout>`
+
+const pptxTheme = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<a:theme xmlns:a="` + nsA + `" name="Lyrics"><a:themeElements><a:clrScheme name="Lyrics"><a:dk1><a:srgbClr val="000000"/></a:dk1><a:lt1><a:srgbClr val="FFFFFF"/></a:lt1><a:dk2><a:srgbClr val="1F497D"/></a:dk2><a:lt2><a:srgbClr val="EEECE1"/></a:lt2><a:accent1><a:srgbClr val="4F81BD"/></a:accent1><a:accent2><a:srgbClr val="C0504D"/></a:accent2><a:accent3><a:srgbClr val="9BBB59"/></a:accent3><a:accent4><a:srgbClr val="8064A2"/></a:accent4><a:accent5><a:srgbClr val="4BACC6"/></a:accent5><a:accent6><a:srgbClr val="F79646"/></a:accent6><a:hlink><a:srgbClr val="0000FF"/></a:hlink><a:folHlink><a:srgbClr val="800080"/></a:folHlink></a:clrScheme><a:fontScheme name="Lyrics"><a:majorFont><a:latin typeface="Arial"/><a:ea typeface=""/><a:cs typeface=""/></a:majorFont><a:minorFont><a:latin typeface="Arial"/><a:ea typeface=""/><a:cs typeface=""/></a:minorFont></a:fontScheme><a:fmtScheme name="Lyrics"><a:fillStyleLst><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:fillStyleLst><a:lnStyleLst><a:ln w="9525"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:ln><a:ln w="25400"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:ln><a:ln w="38100"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:ln></a:lnStyleLst><a:effectStyleLst><a:effectStyle><a:effectLst/></a:effectStyle><a:effectStyle><a:effectLst/></a:effectStyle><a:effectStyle><a:effectLst/></a:effectStyle></a:effectStyleLst><a:bgFillStyleLst><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:bgFillStyleLst></a:fmtScheme></a:themeElements></a:theme>`
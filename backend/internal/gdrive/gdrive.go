@@ -0,0 +1,269 @@
+// Package gdrive copies backups to a Google Drive folder with a service
+// account. It talks to the Drive v3 REST API directly.
+//
+// Service accounts have no storage of their own, so the folder should be in a
+// Shared Drive the service account's email has been added to (as Content
+// manager), or a folder shared with it in a Workspace domain that allows it.
+package gdrive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/backup"
+	"github.com/yourusername/audience-stage-teleprompter/internal/googleauth"
+)
+
+const (
+	apiURL    = "https://www.googleapis.com/drive/v3"
+	uploadURL = "https://www.googleapis.com/upload/drive/v3"
+	scope     = "https://www.googleapis.com/auth/drive"
+)
+
+// TargetName identifies Drive copies in the backups API
+const TargetName = "google_drive"
+
+// Client uploads backups to one Drive folder
+type Client struct {
+	folderID   string
+	token      *googleauth.TokenSource
+	httpClient *http.Client
+}
+
+// New reads a service account key file (the JSON downloaded from the Google
+// Cloud console) and checks that the folder can be reached
+func New(credentialsFile, folderID string) (*Client, error) {
+	if folderID == "" {
+		return nil, fmt.Errorf("google drive folder ID is required")
+	}
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading google drive credentials: %w", err)
+	}
+	httpClient := &http.Client{Timeout: 2 * time.Minute}
+	token, err := googleauth.NewTokenSource(data, []string{scope}, "", httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{folderID: folderID, token: token, httpClient: httpClient}
+	if err := c.checkFolder(context.Background()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Name implements backup.Target
+func (c *Client) Name() string {
+	return TargetName
+}
+
+// apiError is Drive's error response
+type apiError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func errorFrom(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	var e apiError
+	if json.Unmarshal(body, &e) == nil && e.Error.Message != "" {
+		return fmt.Errorf("google drive %d: %s", resp.StatusCode, e.Error.Message)
+	}
+	return fmt.Errorf("google drive %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// do sends an authorized request and decodes the JSON response into out (if
+// not nil)
+func (c *Client) do(req *http.Request, out interface{}) error {
+	token, err := c.token.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errorFrom(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// file is the part of a Drive file resource we ask for
+type file struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	MimeType    string    `json:"mimeType"`
+	Size        string    `json:"size"` // int64 as a string
+	CreatedTime time.Time `json:"createdTime"`
+}
+
+const fileFields = "id,name,mimeType,size,createdTime"
+
+func (f file) remote() backup.RemoteBackup {
+	size, _ := strconv.ParseInt(f.Size, 10, 64)
+	return backup.RemoteBackup{
+		Target:    TargetName,
+		ID:        f.ID,
+		Name:      f.Name,
+		SizeBytes: size,
+		CreatedAt: f.CreatedTime,
+	}
+}
+
+// checkFolder makes sure the folder exists and is a folder
+func (c *Client) checkFolder(ctx context.Context) error {
+	query := url.Values{"fields": {fileFields}, "supportsAllDrives": {"true"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/files/"+url.PathEscape(c.folderID)+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	var folder file
+	if err := c.do(req, &folder); err != nil {
+		return fmt.Errorf("google drive folder not reachable: %w", err)
+	}
+	if folder.MimeType != "application/vnd.google-apps.folder" {
+		return fmt.Errorf("google drive ID %s is not a folder", c.folderID)
+	}
+	return nil
+}
+
+// Upload copies a file into the folder with a resumable upload, which Drive
+// recommends for files that can be large
+func (c *Client) Upload(ctx context.Context, path string) (*backup.RemoteBackup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// Start the session with the file's metadata
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"name":    filepath.Base(path),
+		"parents": []string{c.folderID},
+	})
+	query := url.Values{"uploadType": {"resumable"}, "supportsAllDrives": {"true"}, "fields": {fileFields}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL+"/files?"+query.Encode(), bytes.NewReader(metadata))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(info.Size(), 10))
+
+	token, err := c.token.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error starting google drive upload: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("error starting google drive upload: %w", errorFrom(resp))
+	}
+	resp.Body.Close()
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return nil, fmt.Errorf("google drive did not return an upload session")
+	}
+
+	// Send the content in one request; the session URL carries the
+	// authorization, and the client timeout is too short for big dumps
+	req, err = http.NewRequestWithContext(ctx, http.MethodPut, session, f)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err = (&http.Client{Transport: c.httpClient.Transport}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading to google drive: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("error uploading to google drive: %w", errorFrom(resp))
+	}
+
+	var uploaded file
+	if err := json.NewDecoder(resp.Body).Decode(&uploaded); err != nil {
+		return nil, fmt.Errorf("error decoding google drive upload: %w", err)
+	}
+	remote := uploaded.remote()
+	return &remote, nil
+}
+
+// List returns the backups in the folder, newest first
+func (c *Client) List(ctx context.Context) ([]backup.RemoteBackup, error) {
+	backups := make([]backup.RemoteBackup, 0)
+	pageToken := ""
+	for {
+		query := url.Values{
+			"q":                         {fmt.Sprintf("'%s' in parents and trashed = false and name contains 'backup_'", strings.ReplaceAll(c.folderID, "'", `\'`))},
+			"fields":                    {"nextPageToken,files(" + fileFields + ")"},
+			"orderBy":                   {"createdTime desc"},
+			"pageSize":                  {"1000"},
+			"supportsAllDrives":         {"true"},
+			"includeItemsFromAllDrives": {"true"},
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/files?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			NextPageToken string `json:"nextPageToken"`
+			Files         []file `json:"files"`
+		}
+		if err := c.do(req, &page); err != nil {
+			return nil, fmt.Errorf("error listing google drive backups: %w", err)
+		}
+		for _, f := range page.Files {
+			if f.MimeType == "application/vnd.google-apps.folder" {
+				continue
+			}
+			backups = append(backups, f.remote())
+		}
+		if page.NextPageToken == "" {
+			return backups, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// Delete removes a backup from the folder
+func (c *Client) Delete(ctx context.Context, id string) error {
+	query := url.Values{"supportsAllDrives": {"true"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, apiURL+"/files/"+url.PathEscape(id)+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, nil)
+}
@@ -0,0 +1,158 @@
+// Package googleauth gets Google API access tokens for a service account
+// from its JSON key file, using the OAuth 2.0 JWT bearer grant. It is shared
+// by the Google Drive backups and the Workspace group lookup for sign-in.
+package googleauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultTokenURI = "https://oauth2.googleapis.com/token"
+
+// serviceAccountKey is the part of a service account key file we use
+type serviceAccountKey struct {
+	Type        string `json:"type"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// TokenSource gets access tokens with the service account's signed JWT and
+// reuses them until shortly before they expire
+type TokenSource struct {
+	email      string
+	key        *rsa.PrivateKey
+	tokenURI   string
+	scopes     string
+	subject    string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewTokenSource reads a service account key file's contents. subject is
+// the Workspace user to act as with domain-wide delegation, or empty for the
+// service account itself.
+func NewTokenSource(keyJSON []byte, scopes []string, subject string, httpClient *http.Client) (*TokenSource, error) {
+	var sa serviceAccountKey
+	if err := json.Unmarshal(keyJSON, &sa); err != nil {
+		return nil, fmt.Errorf("error parsing google credentials: %w", err)
+	}
+	if sa.Type != "service_account" || sa.ClientEmail == "" || sa.PrivateKey == "" {
+		return nil, fmt.Errorf("google credentials are not a service account key")
+	}
+
+	block, _ := pem.Decode([]byte(sa.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("google credentials have no private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing google private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("google private key is not an RSA key")
+	}
+
+	tokenURI := sa.TokenURI
+	if tokenURI == "" {
+		tokenURI = defaultTokenURI
+	}
+	return &TokenSource{
+		email:      sa.ClientEmail,
+		key:        key,
+		tokenURI:   tokenURI,
+		scopes:     strings.Join(scopes, " "),
+		subject:    subject,
+		httpClient: httpClient,
+	}, nil
+}
+
+// Token returns a valid access token
+func (t *TokenSource) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && time.Now().Before(t.expires) {
+		return t.token, nil
+	}
+
+	assertion, err := t.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error getting google access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("error getting google access token, status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("error decoding google access token: %w", err)
+	}
+
+	t.token = result.AccessToken
+	// Renew a minute early so a token never expires mid-request
+	t.expires = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return t.token, nil
+}
+
+// assertion is the signed JWT exchanged for an access token
+func (t *TokenSource) assertion(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims := map[string]interface{}{
+		"iss":   t.email,
+		"scope": t.scopes,
+		"aud":   t.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	if t.subject != "" {
+		claims["sub"] = t.subject
+	}
+	payload, _ := json.Marshal(claims)
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(payload)
+	sum := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, t.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing google token request: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(signature), nil
+}
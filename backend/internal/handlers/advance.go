@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/advance"
+	"github.com/yourusername/audience-stage-teleprompter/internal/live"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const maxSlideSeconds = 3600
+
+// GetSongTiming returns a song's auto-advance timing
+func (h *Handler) GetSongTiming(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	timing, err := h.db.GetSongTiming(id)
+	if err != nil {
+		log.Printf("Error getting song timing: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get timing"})
+	}
+	if timing == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "No timing set for this song"})
+	}
+
+	return c.JSON(timing)
+}
+
+// UpdateSongTiming sets a song's per-slide durations
+func (h *Handler) UpdateSongTiming(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req models.SongTimingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.DefaultSeconds < 1 || req.DefaultSeconds > maxSlideSeconds {
+		return c.Status(400).JSON(fiber.Map{"error": "default_seconds must be between 1 and 3600"})
+	}
+	for _, s := range req.SlideSeconds {
+		if s < 0 || s > maxSlideSeconds {
+			return c.Status(400).JSON(fiber.Map{"error": "slide_seconds must be between 0 and 3600 (0 uses default_seconds)"})
+		}
+	}
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	timing, err := h.db.SetSongTiming(id, &req)
+	if err != nil {
+		log.Printf("Error saving song timing: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save timing"})
+	}
+
+	return c.JSON(timing)
+}
+
+// DeleteSongTiming clears a song's auto-advance timing
+func (h *Handler) DeleteSongTiming(c *fiber.Ctx) error {
+	if err := h.db.DeleteSongTiming(c.Params("id")); err != nil {
+		if err.Error() == "timing not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "No timing set for this song"})
+		}
+		log.Printf("Error deleting song timing: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete timing"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Timing deleted successfully"})
+}
+
+// GetAutoAdvance reports the current or last auto-advance run
+func (h *Handler) GetAutoAdvance(c *fiber.Ctx) error {
+	status := h.advance.Status()
+	if status == nil {
+		return c.JSON(fiber.Map{"state": "idle"})
+	}
+	return c.JSON(status)
+}
+
+// StartAutoAdvance steps through what is on screen on a timer, starting from
+// the current slide. The song's saved timing is used unless seconds (one
+// duration for every slide) or loop are given.
+func (h *Handler) StartAutoAdvance(c *fiber.Ctx) error {
+	var req struct {
+		Seconds int   `json:"seconds"`
+		Loop    *bool `json:"loop"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+	if req.Seconds < 0 || req.Seconds > maxSlideSeconds {
+		return c.Status(400).JSON(fiber.Map{"error": "seconds must be between 1 and 3600"})
+	}
+
+	current := h.live.Current()
+	if current == nil {
+		return c.Status(409).JSON(fiber.Map{"error": "Nothing is on screen"})
+	}
+
+	plan, err := h.advancePlan(current, req.Seconds)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if req.Loop != nil {
+		plan.Loop = *req.Loop
+	}
+
+	status, err := h.advance.Start(plan)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(status)
+}
+
+// PauseAutoAdvance holds the current slide
+func (h *Handler) PauseAutoAdvance(c *fiber.Ctx) error {
+	status, err := h.advance.Pause()
+	if err != nil {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(status)
+}
+
+// ResumeAutoAdvance continues a paused run
+func (h *Handler) ResumeAutoAdvance(c *fiber.Ctx) error {
+	status, err := h.advance.Resume()
+	if err != nil {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(status)
+}
+
+// StopAutoAdvance ends the current run
+func (h *Handler) StopAutoAdvance(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"success": true, "stopped": h.advance.Stop()})
+}
+
+// advancePlan builds the slide durations for what is on screen. Songs are
+// split the same way as their ProPresenter presentations; other content (such
+// as scripture) carries its slides in the live state.
+func (h *Handler) advancePlan(current *live.NowShowing, seconds int) (advance.Plan, error) {
+	plan := advance.Plan{SongID: current.SongID, Title: current.Title, Start: current.SlideIndex}
+
+	slides := len(current.Slides)
+	var timing *models.SongTiming
+	if current.SongID != "" {
+		song, err := h.db.GetSong(current.SongID)
+		if err != nil {
+			return plan, fmt.Errorf("live song not found")
+		}
+		segmented, _ := lyrics.Segment(song.DisplayLyrics, lyrics.DefaultSegmentOptions)
+		slides = len(segmented)
+
+		if timing, err = h.db.GetSongTiming(song.ID); err != nil {
+			log.Printf("Error getting timing for %s: %v", song.ID, err)
+		}
+	}
+	if slides == 0 {
+		return plan, fmt.Errorf("nothing to advance through")
+	}
+
+	if seconds > 0 {
+		timing = &models.SongTiming{DefaultSeconds: seconds}
+	}
+	if timing == nil {
+		return plan, fmt.Errorf("no timing set for this song; pass seconds")
+	}
+
+	plan.Loop = timing.Loop
+	plan.Durations = make([]time.Duration, slides)
+	for i := range plan.Durations {
+		plan.Durations[i] = time.Duration(timing.Seconds(i)) * time.Second
+	}
+	return plan, nil
+}
+
+// advanceStep shows the next slide in ProPresenter (when connected) and on the
+// teleprompter displays
+func (h *Handler) advanceStep(songID string, next int, wrapped bool) error {
+	current := h.live.Current()
+	if current == nil || current.SongID != songID {
+		return fmt.Errorf("the live content changed")
+	}
+
+	if h.propresenter != nil && h.propresenter.IsEnabled() {
+		var err error
+		if wrapped && current.PresentationUUID != "" {
+			err = h.propresenter.TriggerPresentationSlide(current.PresentationUUID, 0)
+		} else if !wrapped {
+			err = h.propresenter.TriggerNextSlide()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	h.live.GoToSlide(next)
+	return nil
+}
+
+// publishAutoAdvance tells displays and operator screens about run changes
+func (h *Handler) publishAutoAdvance(status advance.Status) {
+	h.live.Publish(live.Event{Type: live.EventAutoAdvance, Data: status, Timestamp: time.Now()})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultAnalyticsMonths = 12
+	maxAnalyticsMonths     = 60
+)
+
+// analyticsMonths reads the ?months= window, clamped to a sensible range
+func analyticsMonths(c *fiber.Ctx) int {
+	months := c.QueryInt("months", defaultAnalyticsMonths)
+	if months < 1 {
+		months = 1
+	}
+	if months > maxAnalyticsMonths {
+		months = maxAnalyticsMonths
+	}
+	return months
+}
+
+// AnalyticsSongsPerMonth returns song usage totals per month
+func (h *Handler) AnalyticsSongsPerMonth(c *fiber.Ctx) error {
+	counts, err := h.db.GetSongsPerMonth(analyticsMonths(c))
+	if err != nil {
+		log.Printf("Error getting songs per month: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get songs per month"})
+	}
+
+	return c.JSON(counts)
+}
+
+// AnalyticsTopSongs returns the most used songs, or the least used with ?order=least
+func (h *Handler) AnalyticsTopSongs(c *fiber.Ctx) error {
+	order := c.Query("order", "most")
+	if order != "most" && order != "least" {
+		return c.Status(400).JSON(fiber.Map{"error": "order must be most or least"})
+	}
+
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 || limit > 100 {
+		return c.Status(400).JSON(fiber.Map{"error": "limit must be between 1 and 100"})
+	}
+
+	since := c.Query("since", "")
+	if since == "" {
+		since = time.Now().AddDate(0, -analyticsMonths(c), 0).Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", since); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "since must be a date (YYYY-MM-DD)"})
+	}
+
+	ranking, err := h.db.GetSongUsageRanking(since, limit, order == "least")
+	if err != nil {
+		log.Printf("Error getting song usage ranking: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get song usage"})
+	}
+
+	return c.JSON(fiber.Map{
+		"order": order,
+		"since": since,
+		"songs": ranking,
+	})
+}
+
+// AnalyticsLanguageMix returns usage per language per month
+func (h *Handler) AnalyticsLanguageMix(c *fiber.Ctx) error {
+	mix, err := h.db.GetLanguageMix(analyticsMonths(c))
+	if err != nil {
+		log.Printf("Error getting language mix: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get language mix"})
+	}
+
+	return c.JSON(mix)
+}
+
+// AnalyticsSetLength returns the average number of songs per service
+func (h *Handler) AnalyticsSetLength(c *fiber.Ctx) error {
+	stats, err := h.db.GetSetLengthStats(analyticsMonths(c))
+	if err != nil {
+		log.Printf("Error getting set length stats: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get set length stats"})
+	}
+
+	return c.JSON(stats)
+}
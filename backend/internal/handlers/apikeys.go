@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/auth"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetMyAPIKeys lists the signed-in user's API keys
+func (h *Handler) GetMyAPIKeys(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(fiber.Map{"error": "Sign in required"})
+	}
+	return h.listAPIKeys(c, user.ID)
+}
+
+// CreateAPIKey makes an API key for the signed-in user, with their role or
+// a lower one. The key is returned once; only its hash is kept.
+func (h *Handler) CreateAPIKey(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(fiber.Map{"error": "Sign in required"})
+	}
+	if currentAPIKey(c) != nil {
+		return c.Status(403).JSON(fiber.Map{"error": "API keys can't create API keys"})
+	}
+	var req models.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Name is required"})
+	}
+	if req.Role == "" {
+		req.Role = user.Role
+	}
+	if !models.ValidRole(req.Role) {
+		return c.Status(400).JSON(fiber.Map{"error": "Role must be one of " + strings.Join(models.Roles, ", ")})
+	}
+	if !models.RoleCovers(user.Role, req.Role) {
+		return c.Status(403).JSON(fiber.Map{"error": "A key can't have a higher role than yours"})
+	}
+	if req.ExpiresInDays < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "expires_in_days can't be negative"})
+	}
+	var expires *time.Time
+	if req.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		expires = &t
+	}
+
+	key, hash, err := auth.NewAPIKey()
+	if err != nil {
+		h.reportError(c, "Error creating api key", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create API key"})
+	}
+	apiKey, err := h.db.CreateAPIKey(user.ID, &req, hash, auth.KeyPrefix(key), expires)
+	if err != nil {
+		h.reportError(c, "Error creating api key", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create API key"})
+	}
+	return c.Status(201).JSON(fiber.Map{"api_key": apiKey, "key": key})
+}
+
+// DeleteMyAPIKey revokes one of the signed-in user's API keys
+func (h *Handler) DeleteMyAPIKey(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(fiber.Map{"error": "Sign in required"})
+	}
+	return h.deleteAPIKey(c, user.ID)
+}
+
+// GetAPIKeys lists every API key (user_id to filter)
+func (h *Handler) GetAPIKeys(c *fiber.Ctx) error {
+	return h.listAPIKeys(c, c.QueryInt("user_id", 0))
+}
+
+// DeleteAPIKey revokes any API key
+func (h *Handler) DeleteAPIKey(c *fiber.Ctx) error {
+	return h.deleteAPIKey(c, 0)
+}
+
+func (h *Handler) listAPIKeys(c *fiber.Ctx, userID int) error {
+	keys, err := h.db.GetAPIKeys(userID)
+	if err != nil {
+		h.reportError(c, "Error listing api keys", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to list API keys"})
+	}
+	return c.JSON(keys)
+}
+
+func (h *Handler) deleteAPIKey(c *fiber.Ctx, userID int) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid API key ID"})
+	}
+	if err := h.db.DeleteAPIKey(id, userID); err != nil {
+		if err.Error() == "api key not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "API key not found"})
+		}
+		h.reportError(c, "Error revoking api key", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to revoke API key"})
+	}
+	return c.JSON(fiber.Map{"message": "API key revoked"})
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/jobs"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// ExportArchive downloads the whole installation as a versioned JSON
+// migration archive, restorable with ImportArchive on any Postgres version.
+// Songs are streamed as they are read, after the other sections.
+func (h *Handler) ExportArchive(c *fiber.Ctx) error {
+	setAttachment(c, fmt.Sprintf("archive-%s.json", time.Now().Format("2006-01-02")))
+	c.Set("Content-Type", "application/json")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.WriteArchive(w); err != nil {
+			// Headers are already sent; the truncated archive will not import
+			log.Printf("Error exporting archive: %v", err)
+			return
+		}
+		w.Flush()
+	})
+
+	return nil
+}
+
+// WriteArchive writes the migration archive to w, songs last as they are read
+func (h *Handler) WriteArchive(w *bufio.Writer) error {
+	songs := newJSONArrayWriter(w)
+	err := h.db.ExportArchive(func(archive *models.Archive) error {
+		head, err := json.Marshal(archive)
+		if err != nil {
+			return err
+		}
+		// Reopen the object to append the songs as its last field
+		if _, err := w.Write(head[:len(head)-1]); err != nil {
+			return err
+		}
+		_, err = w.WriteString(`,"songs":`)
+		return err
+	}, func(song *models.Song) error {
+		return songs.Write(song)
+	})
+	if err != nil {
+		return err
+	}
+	if err := songs.Close(); err != nil {
+		return err
+	}
+	_, err = w.WriteString("}")
+	return err
+}
+
+// ReadArchive decodes a migration archive, checking it is one this server can read
+func ReadArchive(data []byte) (*models.Archive, error) {
+	var archive models.Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("Invalid archive: %w", err)
+	}
+	if archive.Format != models.ArchiveFormat {
+		return nil, fmt.Errorf("Not a migration archive")
+	}
+	if archive.Version < 1 || archive.Version > models.ArchiveVersion {
+		return nil, fmt.Errorf("Unsupported archive version %d (this server reads up to %d)", archive.Version, models.ArchiveVersion)
+	}
+	return &archive, nil
+}
+
+// ImportArchive restores a migration archive onto a fresh install. The archive
+// is the request body, or an uploaded file in the "archive" form field.
+func (h *Handler) ImportArchive(c *fiber.Ctx) error {
+	body := c.Body()
+	if file, err := c.FormFile("archive"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Failed to read uploaded archive"})
+		}
+		defer f.Close()
+		if body, err = io.ReadAll(f); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Failed to read uploaded archive"})
+		}
+	}
+
+	archive, err := ReadArchive(body)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// Pause other changes until the library is in place
+	end := h.maintenance.Begin("restore")
+	result, err := h.db.ImportArchive(archive)
+	end()
+	if err != nil {
+		if err.Error() == "library is not empty" {
+			return c.Status(409).JSON(fiber.Map{"error": "Archives can only be imported into an empty library"})
+		}
+		log.Printf("Error importing archive: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to import archive: " + err.Error()})
+	}
+
+	response := fiber.Map{"message": "Archive imported successfully", "imported": result}
+	if jobID := h.indexImported(archive.Songs); jobID != "" {
+		response["reindex_job_id"] = jobID
+	}
+
+	return c.JSON(response)
+}
+
+// indexImported rebuilds search from songs just imported into an empty
+// library, in the background; its progress is at /admin/reindex/:id. It
+// returns the job's ID, or "" if there is nothing to index.
+func (h *Handler) indexImported(songs []models.Song) string {
+	if h.skipTypesense || h.ts == nil || len(songs) == 0 {
+		return ""
+	}
+	job, _ := h.jobs.Start(reindexJob, func(job *jobs.Job) error {
+		return h.ts.Reindex(songs, job)
+	})
+	return job.Status().ID
+}
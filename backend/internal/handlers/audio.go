@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/audio"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const maxAudioFileSize = 50 * 1024 * 1024
+
+// GetSongAudio returns a song's linked audio track
+func (h *Handler) GetSongAudio(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	track, err := h.db.GetSongAudio(id)
+	if err != nil {
+		log.Printf("Error getting song audio: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get audio track"})
+	}
+	if track == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "No audio track for this song"})
+	}
+
+	return c.JSON(track)
+}
+
+// UpdateSongAudio sets a track's URL and ProPresenter audio item. Fields left
+// out keep their value; an uploaded file is kept and takes precedence over the
+// URL for local playback.
+func (h *Handler) UpdateSongAudio(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req models.SongAudioRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.URL != nil {
+		*req.URL = strings.TrimSpace(*req.URL)
+		if *req.URL != "" {
+			if u, err := url.Parse(*req.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return c.Status(400).JSON(fiber.Map{"error": "url must be an http or https URL"})
+			}
+		}
+	}
+	if req.ProPresenterAudio != nil {
+		*req.ProPresenterAudio = strings.TrimSpace(*req.ProPresenterAudio)
+	}
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	track, err := h.db.GetSongAudio(id)
+	if err != nil {
+		log.Printf("Error getting song audio: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get audio track"})
+	}
+	if track == nil {
+		track = &models.SongAudio{SongID: id}
+	}
+	if req.URL != nil {
+		track.URL = req.URL
+	}
+	if req.ProPresenterAudio != nil {
+		track.ProPresenterAudio = req.ProPresenterAudio
+	}
+
+	saved, err := h.db.SetSongAudio(track)
+	if err != nil {
+		log.Printf("Error saving song audio: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save audio track"})
+	}
+
+	return c.JSON(saved)
+}
+
+// UploadSongAudio stores an uploaded recording (multipart "file") for a song,
+// replacing any earlier upload
+func (h *Handler) UploadSongAudio(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "expected a multipart upload with a file"})
+	}
+	ext := strings.ToLower(filepath.Ext(fh.Filename))
+	if !containsString(audio.Extensions, ext) {
+		return c.Status(400).JSON(fiber.Map{"error": "file must be one of " + strings.Join(audio.Extensions, ", ")})
+	}
+	if fh.Size > maxAudioFileSize {
+		return c.Status(400).JSON(fiber.Map{"error": "file must be 50 MB or smaller"})
+	}
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Failed to read upload"})
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Failed to read upload"})
+	}
+
+	track, err := h.db.GetSongAudio(id)
+	if err != nil {
+		log.Printf("Error getting song audio: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get audio track"})
+	}
+	if track == nil {
+		track = &models.SongAudio{SongID: id}
+	}
+
+	// Stored by song ID so a new upload replaces the old one
+	name := id + ext
+	if track.FileName != nil && *track.FileName != name {
+		if err := h.audio.Remove(*track.FileName); err != nil {
+			log.Printf("Error removing old audio file for %s: %v", id, err)
+		}
+	}
+	if err := h.audio.Save(name, data); err != nil {
+		log.Printf("Error saving audio file: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save audio file"})
+	}
+	track.FileName = &name
+
+	saved, err := h.db.SetSongAudio(track)
+	if err != nil {
+		log.Printf("Error saving song audio: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save audio track"})
+	}
+
+	return c.JSON(saved)
+}
+
+// GetSongAudioFile streams a song's uploaded recording, for playback in the browser
+func (h *Handler) GetSongAudioFile(c *fiber.Ctx) error {
+	track, err := h.db.GetSongAudio(c.Params("id"))
+	if err != nil {
+		log.Printf("Error getting song audio: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get audio track"})
+	}
+	if track == nil || track.FileName == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "No audio file for this song"})
+	}
+
+	return c.SendFile(h.audio.Path(*track.FileName))
+}
+
+// DeleteSongAudio unlinks a song's audio track and removes its uploaded file
+func (h *Handler) DeleteSongAudio(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	track, err := h.db.GetSongAudio(id)
+	if err != nil {
+		log.Printf("Error getting song audio: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get audio track"})
+	}
+
+	if err := h.db.DeleteSongAudio(id); err != nil {
+		if err.Error() == "audio not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "No audio track for this song"})
+		}
+		log.Printf("Error deleting song audio: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete audio track"})
+	}
+
+	if track != nil && track.FileName != nil {
+		if err := h.audio.Remove(*track.FileName); err != nil {
+			log.Printf("Error removing audio file for %s: %v", id, err)
+		}
+	}
+
+	return c.JSON(fiber.Map{"message": "Audio track deleted successfully"})
+}
+
+// PlaySongAudio starts a song's track on the local player or ProPresenter
+// (?output= or body "output", defaulting to AUDIO_OUTPUT), stopping whatever
+// was playing
+func (h *Handler) PlaySongAudio(c *fiber.Ctx) error {
+	var req struct {
+		Output string `json:"output"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+	output := c.Query("output", req.Output)
+	if output == "" {
+		output = h.audio.DefaultOutput()
+	}
+	if output != audio.OutputLocal && output != audio.OutputProPresenter {
+		return c.Status(400).JSON(fiber.Map{"error": "output must be local or propresenter"})
+	}
+
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+	track, err := h.db.GetSongAudio(song.ID)
+	if err != nil {
+		log.Printf("Error getting song audio: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get audio track"})
+	}
+	if track == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "No audio track for this song"})
+	}
+
+	if output == audio.OutputProPresenter {
+		if track.ProPresenterAudio == nil {
+			return c.Status(400).JSON(fiber.Map{"error": "No ProPresenter audio item set for this song"})
+		}
+		if h.propresenter == nil || !h.propresenter.IsEnabled() {
+			return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+		}
+		if err := h.propresenter.TriggerAudio(*track.ProPresenterAudio); err != nil {
+			h.reportError(c, "Error playing ProPresenter audio", fmt.Errorf("%q: %w", *track.ProPresenterAudio, err))
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(h.audio.PlayingElsewhere(song.ID, song.Title, *track.ProPresenterAudio, output))
+	}
+
+	var source string
+	switch {
+	case track.FileName != nil:
+		source = h.audio.Path(*track.FileName)
+	case track.URL != nil:
+		source = *track.URL
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "No audio file or URL set for this song"})
+	}
+
+	status, err := h.audio.Play(song.ID, song.Title, source)
+	if err != nil {
+		log.Printf("Error playing audio for %s: %v", song.Title, err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(status)
+}
+
+// GetAudioStatus reports what is playing
+func (h *Handler) GetAudioStatus(c *fiber.Ctx) error {
+	return c.JSON(h.audio.Status())
+}
+
+// StopAudio stops playback on whichever output is playing
+func (h *Handler) StopAudio(c *fiber.Ctx) error {
+	stopped, ok := h.stopAudio()
+	if !ok {
+		return c.JSON(fiber.Map{"success": true, "stopped": false})
+	}
+	return c.JSON(fiber.Map{"success": true, "stopped": true, "output": stopped.Output})
+}
+
+// stopAudio stops the local player and, if the track was playing there,
+// clears ProPresenter's audio layer
+func (h *Handler) stopAudio() (audio.Status, bool) {
+	stopped, ok := h.audio.Stop()
+	if ok && stopped.Output == audio.OutputProPresenter && h.propresenter != nil && h.propresenter.IsEnabled() {
+		if err := h.propresenter.ClearLayer("audio"); err != nil {
+			h.reportError(nil, "Error clearing ProPresenter audio", err)
+		}
+	}
+	return stopped, ok
+}
+
+// ProPresenterAudio returns the ProPresenter audio items songs can be linked to
+func (h *Handler) ProPresenterAudio(c *fiber.Ctx) error {
+	if h.propresenter == nil || !h.propresenter.IsEnabled() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+
+	items, err := h.propresenter.GetAudioItems()
+	if err != nil {
+		h.reportError(c, "Error fetching ProPresenter audio", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"audio": items,
+		"count": len(items),
+	})
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/auth"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// sessionCookie carries the session token for browsers; other clients can
+// send it as "Authorization: Bearer <token>"
+const sessionCookie = "ast_session"
+
+// apiKeyHeader carries an API key for clients that don't send it as a
+// bearer token
+const apiKeyHeader = "X-API-Key"
+
+// DefaultSessionTTL is how long a sign-in lasts unless configured
+const DefaultSessionTTL = 14 * 24 * time.Hour
+
+// AuthConfig controls signing in. Routes require an account with a high
+// enough role unless Disabled (AUTH_DISABLED) opens them to everyone.
+type AuthConfig struct {
+	Disabled   bool
+	SessionTTL time.Duration
+}
+
+// SetAuthConfig sets whether routes require signing in and for how long a
+// sign-in lasts
+func (h *Handler) SetAuthConfig(cfg AuthConfig) {
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = DefaultSessionTTL
+	}
+	h.auth = cfg
+}
+
+// sessionToken returns the session token or API key a request carries, if
+// any
+func sessionToken(c *fiber.Ctx) string {
+	if key := c.Get(apiKeyHeader); key != "" {
+		return strings.TrimSpace(key)
+	}
+	if header := c.Get(fiber.HeaderAuthorization); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	return c.Cookies(sessionCookie)
+}
+
+// Authenticate looks up the request's session or API key and, if it is
+// valid, makes its user available to the handlers. Requests without one
+// carry on anonymously; RequireRole decides whether that is enough.
+func (h *Handler) Authenticate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := sessionToken(c)
+		if token == "" {
+			return c.Next()
+		}
+		if strings.HasPrefix(token, auth.APIKeyPrefix) {
+			user, key, err := h.db.GetAPIKeyUser(auth.HashToken(token))
+			if err == nil && !user.Disabled {
+				// A key acts with its own role, or its user's if that has
+				// since been lowered
+				if models.RoleCovers(user.Role, key.Role) {
+					user.Role = key.Role
+				}
+				c.Locals("user", user)
+				c.Locals("api_key", key)
+			} else if err != nil && err.Error() != "api key not found" {
+				h.reportError(c, "Error checking api key", err)
+			}
+			return c.Next()
+		}
+		user, session, err := h.db.GetSessionUser(auth.HashToken(token))
+		if err == nil && !user.Disabled {
+			c.Locals("user", user)
+			c.Locals("session", session)
+		} else if err != nil && err.Error() != "session not found" {
+			h.reportError(c, "Error checking session", err)
+		}
+		return c.Next()
+	}
+}
+
+// RequireRole lets through signed-in users with one of roles. Only with
+// signing in disabled does it let everyone through.
+func (h *Handler) RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.auth.Disabled {
+			return c.Next()
+		}
+		user := currentUser(c)
+		if user == nil {
+			return c.Status(401).JSON(fiber.Map{"error": "Sign in required"})
+		}
+		if user.MustResetPassword && !user.SSO {
+			return c.Status(403).JSON(fiber.Map{"error": "Choose a new password first", "must_reset_password": true})
+		}
+		for _, role := range roles {
+			if user.Role == role {
+				return c.Next()
+			}
+		}
+		return c.Status(403).JSON(fiber.Map{"error": "Your role can't do this"})
+	}
+}
+
+// currentUser returns the signed-in user, or nil
+func currentUser(c *fiber.Ctx) *models.User {
+	user, _ := c.Locals("user").(*models.User)
+	return user
+}
+
+// currentAPIKey returns the API key the request was made with, or nil
+func currentAPIKey(c *fiber.Ctx) *models.APIKey {
+	key, _ := c.Locals("api_key").(*models.APIKey)
+	return key
+}
+
+// currentSession returns the request's session, or nil
+func currentSession(c *fiber.Ctx) *models.UserSession {
+	session, _ := c.Locals("session").(*models.UserSession)
+	return session
+}
+
+// Login signs in with a username and password, setting the session cookie
+// and returning the token for clients that don't keep cookies
+func (h *Handler) Login(c *fiber.Ctx) error {
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	user, err := h.db.GetUserByUsername(strings.TrimSpace(req.Username))
+	if err != nil && err.Error() != "user not found" {
+		h.reportError(c, "Error signing in", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to sign in"})
+	}
+	if user == nil || !user.HasPassword || !auth.CheckPassword(user.PasswordHash, req.Password) {
+		return c.Status(401).JSON(fiber.Map{"error": "Wrong username or password"})
+	}
+	if user.Disabled {
+		return c.Status(403).JSON(fiber.Map{"error": "This account is disabled"})
+	}
+
+	token, session, err := h.startSession(c, user)
+	if err != nil {
+		h.reportError(c, "Error signing in", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to sign in"})
+	}
+	return c.JSON(fiber.Map{
+		"user":                user,
+		"token":               token,
+		"expires_at":          session.ExpiresAt,
+		"must_reset_password": user.MustResetPassword,
+	})
+}
+
+// startSession signs user in and sets the session cookie
+func (h *Handler) startSession(c *fiber.Ctx, user *models.User) (string, *models.UserSession, error) {
+	token, hash, err := auth.NewSessionToken()
+	if err != nil {
+		return "", nil, err
+	}
+	expires := time.Now().Add(h.auth.SessionTTL)
+	session, err := h.db.CreateSession(user.ID, hash, c.Get(fiber.HeaderUserAgent), c.IP(), expires)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HTTPOnly: true,
+		SameSite: "Lax",
+		Secure:   c.Protocol() == "https",
+	})
+	return token, session, nil
+}
+
+// Logout ends the request's session
+func (h *Handler) Logout(c *fiber.Ctx) error {
+	if session := currentSession(c); session != nil {
+		if err := h.db.DeleteSession(session.ID); err != nil && err.Error() != "session not found" {
+			h.reportError(c, "Error signing out", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to sign out"})
+		}
+	}
+	c.ClearCookie(sessionCookie)
+	return c.JSON(fiber.Map{"message": "Signed out"})
+}
+
+// Me returns the signed-in user, and the API key when the request used one
+func (h *Handler) Me(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(fiber.Map{"error": "Not signed in", "auth_enabled": !h.auth.Disabled, "sso_enabled": h.sso.provider != nil})
+	}
+	return c.JSON(fiber.Map{"user": user, "session": currentSession(c), "api_key": currentAPIKey(c), "auth_enabled": !h.auth.Disabled, "sso_enabled": h.sso.provider != nil})
+}
+
+// ChangePassword sets the signed-in user's password and signs out their
+// other sessions. It is how a temporary password is replaced.
+func (h *Handler) ChangePassword(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(fiber.Map{"error": "Sign in required"})
+	}
+	if currentAPIKey(c) != nil {
+		return c.Status(403).JSON(fiber.Map{"error": "API keys can't change passwords"})
+	}
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if user.HasPassword && !auth.CheckPassword(user.PasswordHash, req.CurrentPassword) {
+		return c.Status(403).JSON(fiber.Map{"error": "Current password is wrong"})
+	}
+	if len(req.NewPassword) < auth.MinPasswordLength {
+		return c.Status(400).JSON(fiber.Map{"error": "Passwords need at least 8 characters"})
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return c.Status(400).JSON(fiber.Map{"error": "Choose a password different from the current one"})
+	}
+
+	hash, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		h.reportError(c, "Error changing password", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to change password"})
+	}
+	if err := h.db.SetUserPassword(user.ID, hash, false); err != nil {
+		h.reportError(c, "Error changing password", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to change password"})
+	}
+	keep := 0
+	if session := currentSession(c); session != nil {
+		keep = session.ID
+	}
+	if _, err := h.db.DeleteUserSessions(user.ID, keep); err != nil {
+		h.reportError(c, "Error signing out other sessions", err)
+	}
+	return c.JSON(fiber.Map{"message": "Password changed"})
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+func TestRequireRoleByDefault(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   AuthConfig
+		user   *models.User
+		status int
+	}{
+		{"anonymous", AuthConfig{}, nil, 401},
+		{"role too low", AuthConfig{}, &models.User{Role: models.RoleViewer}, 403},
+		{"role high enough", AuthConfig{}, &models.User{Role: models.RoleOperator}, 200},
+		{"sign-in disabled", AuthConfig{Disabled: true}, nil, 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{}
+			h.SetAuthConfig(tt.auth)
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				if tt.user != nil {
+					c.Locals("user", tt.user)
+				}
+				return c.Next()
+			})
+			app.Post("/live/blank", h.RequireRole(models.RolesAtLeast(models.RoleOperator)...), func(c *fiber.Ctx) error {
+				return c.SendStatus(200)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("POST", "/live/blank", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
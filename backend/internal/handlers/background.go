@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// ProPresenterLooks returns the ProPresenter looks songs can be assigned
+func (h *Handler) ProPresenterLooks(c *fiber.Ctx) error {
+	if h.propresenter == nil || !h.propresenter.IsEnabled() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+
+	looks, err := h.propresenter.GetLooks()
+	if err != nil {
+		h.reportError(c, "Error fetching ProPresenter looks", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"looks": looks,
+		"count": len(looks),
+	})
+}
+
+// ProPresenterMedia returns the media items songs can use as backgrounds
+func (h *Handler) ProPresenterMedia(c *fiber.Ctx) error {
+	if h.propresenter == nil || !h.propresenter.IsEnabled() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+
+	items, err := h.propresenter.GetMediaItems()
+	if err != nil {
+		h.reportError(c, "Error fetching ProPresenter media", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"media": items,
+		"count": len(items),
+	})
+}
+
+// applySongBackground switches ProPresenter to the look and background media
+// assigned to a song. The lyrics are already live, so failures are logged and
+// recorded rather than failing the trigger. It returns what was applied.
+func (h *Handler) applySongBackground(c *fiber.Ctx, song *models.Song) fiber.Map {
+	applied := fiber.Map{}
+
+	if song.Look != nil && *song.Look != "" {
+		if err := h.propresenter.TriggerLook(*song.Look); err != nil {
+			log.Printf("Error applying look %q for %s: %v", *song.Look, song.Title, err)
+			h.recordServiceEvent(c, models.ServiceEventError, song.ID, song.Title, "look failed: "+err.Error())
+		} else {
+			applied["look"] = *song.Look
+		}
+	}
+
+	if song.BackgroundMedia != nil && *song.BackgroundMedia != "" {
+		if err := h.propresenter.TriggerMedia(*song.BackgroundMedia); err != nil {
+			log.Printf("Error applying background %q for %s: %v", *song.BackgroundMedia, song.Title, err)
+			h.recordServiceEvent(c, models.ServiceEventError, song.ID, song.Title, "background failed: "+err.Error())
+		} else {
+			applied["background_media"] = *song.BackgroundMedia
+		}
+	}
+
+	return applied
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/backup"
+)
+
+// remoteListTimeout bounds listing the remote backup targets
+const remoteListTimeout = 30 * time.Second
+
+// GetRemoteBackups lists the backups copied off the server (Google Drive, S3),
+// newest first, with the outcome of the latest upload to each target. A
+// target that can't be listed is reported under errors.
+func (h *Handler) GetRemoteBackups(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteListTimeout)
+	defer cancel()
+
+	backups, failed := h.backupManager.ListRemoteBackups(ctx)
+	return c.JSON(fiber.Map{
+		"backups": backups,
+		"targets": h.backupManager.Status().Remote,
+		"errors":  failed,
+	})
+}
+
+// verifyBackup checks a backup's checksum, answering 404 or 409 itself when
+// it can't be used
+func (h *Handler) verifyBackup(c *fiber.Ctx) (*backup.Verification, error) {
+	v, err := h.backupManager.Verify(c.Params("name"))
+	if err == nil {
+		return v, nil
+	}
+	if errors.Is(err, backup.ErrChecksumMismatch) {
+		h.reportError(c, "Backup failed verification", err)
+		return nil, c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err.Error() == "backup not found" {
+		return nil, c.Status(404).JSON(fiber.Map{"error": "Backup not found"})
+	}
+	h.reportError(c, "Error verifying backup", err)
+	return nil, c.Status(500).JSON(fiber.Map{"error": "Failed to verify backup"})
+}
+
+// VerifyBackup checks a backup file against the checksum recorded when it
+// was made
+func (h *Handler) VerifyBackup(c *fiber.Ctx) error {
+	v, err := h.verifyBackup(c)
+	if v == nil {
+		return err
+	}
+	return c.JSON(v)
+}
+
+// DownloadBackup sends a backup file after checking it against its checksum,
+// so a copy corrupted on disk is never handed out as a good one
+func (h *Handler) DownloadBackup(c *fiber.Ctx) error {
+	v, err := h.verifyBackup(c)
+	if v == nil {
+		return err
+	}
+	c.Set("X-Backup-SHA256", v.SHA256)
+	return c.Download(v.Path, v.File)
+}
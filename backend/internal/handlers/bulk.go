@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// bulkDeleteToken fingerprints the set of songs a preview showed, so a delete
+// only goes ahead if it would remove exactly those songs
+func bulkDeleteToken(songs []models.Song) string {
+	ids := make([]string, len(songs))
+	for i, song := range songs {
+		ids[i] = song.ID
+	}
+	sort.Strings(ids)
+	sum := sha256.Sum256([]byte(strings.Join(ids, ",")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// BulkDeleteSongs moves every song matching language, library and/or
+// not_used_since to the trash. It only previews unless dry_run is false and confirm holds
+// the token from a preview of the same songs. A backup is taken first.
+func (h *Handler) BulkDeleteSongs(c *fiber.Ctx) error {
+	var req models.BulkDeleteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Language = strings.TrimSpace(req.Language)
+	req.Library = strings.TrimSpace(req.Library)
+	if req.NotUsedSince != nil {
+		if _, err := time.Parse("2006-01-02", *req.NotUsedSince); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "not_used_since must be YYYY-MM-DD"})
+		}
+	}
+	if req.Language == "" && req.Library == "" && req.NotUsedSince == nil {
+		return c.Status(400).JSON(fiber.Map{"error": "At least one of language, library or not_used_since is required"})
+	}
+
+	songs, err := h.db.FindSongsForBulkDelete(&req)
+	if err != nil {
+		log.Printf("Error finding songs for bulk delete: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to find songs"})
+	}
+
+	matched := make([]fiber.Map, len(songs))
+	for i, song := range songs {
+		matched[i] = fiber.Map{"id": song.ID, "title": song.Title, "language": song.Language, "library": song.Library}
+	}
+	token := bulkDeleteToken(songs)
+
+	if req.DryRun == nil || *req.DryRun {
+		return c.JSON(fiber.Map{"dry_run": true, "count": len(songs), "songs": matched, "confirm": token})
+	}
+	if req.Confirm == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "confirm is required; run a dry run first and pass its confirm token"})
+	}
+	if req.Confirm != token {
+		return c.Status(409).JSON(fiber.Map{
+			"error":   "The matching songs have changed since the dry run; review the new preview",
+			"count":   len(songs),
+			"songs":   matched,
+			"confirm": token,
+		})
+	}
+	if len(songs) == 0 {
+		return c.JSON(fiber.Map{"dry_run": false, "deleted": 0})
+	}
+
+	if !req.SkipBackup {
+		if err := h.backupManager.CreateBackup("pre-bulk-delete"); err != nil {
+			log.Printf("Error creating backup before bulk delete: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to create a backup; nothing was deleted (pass skip_backup to delete anyway)"})
+		}
+	}
+
+	ids := make([]string, len(songs))
+	for i, song := range songs {
+		ids[i] = song.ID
+	}
+	deleted, err := h.db.DeleteSongs(ids)
+	if err != nil {
+		log.Printf("Error bulk deleting songs: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete songs"})
+	}
+	log.Printf("🗑️  Bulk deleted %d songs to the trash (language=%q library=%q)", deleted, req.Language, req.Library)
+
+	// Remove the songs from the search index too
+	indexFailures := 0
+	if h.ts != nil {
+		for _, id := range ids {
+			if err := h.ts.DeleteSong(id); err != nil {
+				h.reportError(c, "Error deleting song from Typesense", fmt.Errorf("song %s: %w", id, err))
+				indexFailures++
+			}
+		}
+	}
+
+	return c.JSON(fiber.Map{"dry_run": false, "deleted": deleted, "songs": matched, "index_failures": indexFailures})
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/importer"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// maxBulkImportRows bounds one bulk import; bigger libraries are split
+const maxBulkImportRows = 5000
+
+// Statuses of a row in a bulk import report
+const (
+	BulkImported  = "imported"
+	BulkDuplicate = "duplicate"
+	BulkInvalid   = "invalid"
+)
+
+// BulkImportRow reports what happened to one row of a bulk import
+type BulkImportRow struct {
+	Row      int      `json:"row"`
+	Status   string   `json:"status"`
+	Title    string   `json:"title,omitempty"`
+	ID       string   `json:"id,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	// DuplicateOf is the existing song, or the earlier row, with the same
+	// title and language
+	DuplicateOf    string `json:"duplicate_of,omitempty"`
+	DuplicateOfRow int    `json:"duplicate_of_row,omitempty"`
+}
+
+// BulkImportResult is the per-row report of a bulk import
+type BulkImportResult struct {
+	DryRun     bool            `json:"dry_run"`
+	Total      int             `json:"total"`
+	Imported   int             `json:"imported"`
+	Duplicates int             `json:"duplicates"`
+	Invalid    int             `json:"invalid"`
+	Rows       []BulkImportRow `json:"rows"`
+}
+
+// BulkImport creates songs from a CSV or JSON body, or a "file" upload, in
+// one transaction: if saving fails nothing is imported. Rows that can't be
+// read or fail validation, and songs whose title already exists in the same
+// language (in the library or earlier in the file), are reported and left
+// out. ?library= and ?language= fill rows without them (language defaults to
+// detection); ?dry_run=true reports without saving.
+func (h *Handler) BulkImport(c *fiber.Ctx) error {
+	data, format, err := bulkImportBody(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var rows []importer.BulkRow
+	switch format {
+	case "csv":
+		rows, err = importer.ParseBulkCSV(data)
+	case "json":
+		rows, err = importer.ParseBulkJSON(data)
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "Send CSV or JSON (set format=csv or format=json, or the Content-Type)"})
+	}
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if len(rows) == 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "No songs to import"})
+	}
+	if len(rows) > maxBulkImportRows {
+		return c.Status(413).JSON(fiber.Map{"error": fmt.Sprintf("At most %d songs can be imported at once", maxBulkImportRows)})
+	}
+
+	existing, err := h.db.GetAllSongs()
+	if err != nil {
+		log.Printf("Error loading songs for import: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load existing songs"})
+	}
+	songIDs := make(map[string]string, len(existing))
+	for _, s := range existing {
+		songIDs[importKey(s.Title, s.Language)] = s.ID
+	}
+
+	result := &BulkImportResult{DryRun: c.Query("dry_run") == "true", Total: len(rows), Rows: make([]BulkImportRow, len(rows))}
+	library, language := strings.TrimSpace(c.Query("library")), strings.TrimSpace(c.Query("language", "auto"))
+	seenRows := make(map[string]int)
+	var requests []models.CreateSongRequest
+	var requestRows []int // index in result.Rows of each request
+
+	for i := range rows {
+		row := &result.Rows[i]
+		row.Row = rows[i].Row
+		req := &rows[i].Request
+		row.Title = strings.TrimSpace(req.Title)
+		if rows[i].Error != "" {
+			row.Status, row.Error = BulkInvalid, rows[i].Error
+			continue
+		}
+		if req.Library == "" {
+			req.Library = library
+		}
+		if req.Language == "" {
+			req.Language = language
+		}
+
+		msg, warnings := h.checkBulkSong(req)
+		row.Title, row.Warnings = req.Title, warnings
+		if msg != "" {
+			row.Status, row.Error = BulkInvalid, msg
+			continue
+		}
+
+		key := importKey(req.Title, req.Language)
+		if id, ok := songIDs[key]; ok {
+			row.Status, row.DuplicateOf = BulkDuplicate, id
+			continue
+		}
+		if earlier, ok := seenRows[key]; ok {
+			row.Status, row.DuplicateOfRow = BulkDuplicate, earlier
+			continue
+		}
+		seenRows[key] = row.Row
+
+		row.Status = BulkImported
+		requests = append(requests, *req)
+		requestRows = append(requestRows, i)
+	}
+
+	if !result.DryRun && len(requests) > 0 {
+		created, err := h.db.CreateSongs(requests)
+		if err != nil {
+			h.reportError(c, "Error saving bulk import", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to save songs; nothing was imported"})
+		}
+		for i := range created {
+			result.Rows[requestRows[i]].ID = created[i].ID
+			h.refreshRomanized(&created[i])
+		}
+		if !h.skipTypesense && h.ts != nil {
+			if err := h.ts.IndexSongs(created); err != nil {
+				h.reportError(c, "Error indexing imported songs in Typesense", err)
+			}
+		}
+		h.backupManager.RecordEdits(len(created))
+	}
+
+	for _, row := range result.Rows {
+		switch row.Status {
+		case BulkImported:
+			result.Imported++
+		case BulkDuplicate:
+			result.Duplicates++
+		case BulkInvalid:
+			result.Invalid++
+		}
+	}
+	if !result.DryRun && result.Imported > 0 {
+		log.Printf("Bulk imported %d songs (%d duplicates, %d invalid)", result.Imported, result.Duplicates, result.Invalid)
+	}
+	return c.JSON(result)
+}
+
+// checkBulkSong normalizes and validates a song the way creating it would,
+// returning why it can't be imported and anything worth a look
+func (h *Handler) checkBulkSong(req *models.CreateSongRequest) (string, []string) {
+	var warnings []string
+	if len(req.Numbers) > 0 || len(req.Links) > 0 {
+		warnings = append(warnings, "songbook numbers and links aren't bulk imported; add them to the song afterwards")
+		req.Numbers, req.Links = nil, nil
+	}
+	normalizeSongRequest(req)
+	h.formatSongRequest(req)
+
+	if req.Title == "" || req.DisplayLyrics == "" || req.Library == "" {
+		return "Title, display lyrics, and library are required", warnings
+	}
+	if warning := detectSongLanguage(req); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if err := normalizeKeyField("original_key", req.OriginalKey); err != nil {
+		return err.Error(), warnings
+	}
+	if err := normalizeKeyField("performance_key", req.PerformanceKey); err != nil {
+		return err.Error(), warnings
+	}
+	if err := validateTempoFields(req.BPM, req.TimeSignature, req.CountInBeats); err != nil {
+		return err.Error(), warnings
+	}
+	if problems := chordsProblems(req.Chords); len(problems) > 0 {
+		return "chords isn't valid ChordPro: " + problems[0].Error(), warnings
+	}
+	return "", warnings
+}
+
+// bulkImportBody returns the import data and its format ("csv" or "json"),
+// from ?format=, the uploaded file's name or the Content-Type
+func bulkImportBody(c *fiber.Ctx) ([]byte, string, error) {
+	format := strings.ToLower(c.Query("format"))
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading %s: %w", fh.Filename, err)
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading %s: %w", fh.Filename, err)
+		}
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(path.Ext(fh.Filename)), ".")
+		}
+		return data, format, nil
+	}
+
+	if format == "" {
+		switch contentType := strings.ToLower(c.Get(fiber.HeaderContentType)); {
+		case strings.HasPrefix(contentType, "text/csv"):
+			format = "csv"
+		case strings.HasPrefix(contentType, fiber.MIMEApplicationJSON):
+			format = "json"
+		}
+	}
+	if len(c.Body()) == 0 {
+		return nil, "", fmt.Errorf("the request has no songs")
+	}
+	return c.Body(), format, nil
+}
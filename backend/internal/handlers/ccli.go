@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/export"
+)
+
+// GetCCLIReport exports reported song uses between ?from= and ?to=
+// (YYYY-MM-DD, default the last 6 months) as CSV for CCLI reporting, or as JSON
+// with ?format=json
+func (h *Handler) GetCCLIReport(c *fiber.Ctx) error {
+	to := c.Query("to", time.Now().Format("2006-01-02"))
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "to must be a date (YYYY-MM-DD)"})
+	}
+	from := c.Query("from", toDate.AddDate(0, -6, 0).Format("2006-01-02"))
+	fromDate, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "from must be a date (YYYY-MM-DD)"})
+	}
+	if fromDate.After(toDate) {
+		return c.Status(400).JSON(fiber.Map{"error": "from must not be after to"})
+	}
+
+	report, err := h.db.GetCCLIReport(from, to)
+	if err != nil {
+		log.Printf("Error getting CCLI report: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to build CCLI report"})
+	}
+
+	switch c.Query("format", "csv") {
+	case "csv":
+		setAttachment(c, fmt.Sprintf("ccli-report-%s-to-%s.csv", from, to))
+		c.Set("Content-Type", "text/csv; charset=utf-8")
+		return c.Send(export.CCLIReportCSV(report))
+	case "json":
+		missing := 0
+		for _, r := range report {
+			if r.CCLINumber == "" {
+				missing++
+			}
+		}
+		return c.JSON(fiber.Map{
+			"from":         from,
+			"to":           to,
+			"songs":        report,
+			"missing_ccli": missing,
+		})
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "format must be csv or json"})
+	}
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/chordpro"
+)
+
+// chordsProblems checks the ChordPro chart sent with a song. An empty chart
+// clears it and isn't checked.
+func chordsProblems(chords *string) []chordpro.Problem {
+	if chords == nil || strings.TrimSpace(*chords) == "" {
+		return nil
+	}
+	_, problems := chordpro.Parse(*chords)
+	return problems
+}
+
+// invalidChords answers a save whose chart has problems, listing them by line
+func invalidChords(c *fiber.Ctx, problems []chordpro.Problem) error {
+	return c.Status(400).JSON(fiber.Map{"error": "chords isn't valid ChordPro: " + problems[0].Error(), "problems": problems})
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/collab"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/ws"
+)
+
+// songDocument starts a collaborative draft from a saved song
+func songDocument(song *models.Song) collab.Document {
+	fields := map[string]string{
+		collab.FieldTitle:               song.Title,
+		collab.FieldLanguage:            song.Language,
+		collab.FieldDisplayLyrics:       song.DisplayLyrics,
+		collab.FieldMusicMinistryLyrics: song.MusicMinistryLyrics,
+	}
+	if song.Artist != nil {
+		fields[collab.FieldArtist] = *song.Artist
+	}
+	return collab.NewDocument(song.ID, fields)
+}
+
+// CollabSong opens a WebSocket for editing a song together with others
+// (?editor=<name>). Each editor gets the shared draft, then every patch
+// applied to it; see the collab package for how patches merge. A "save"
+// message writes the draft to the song, if the editor may change songs.
+func (h *Handler) CollabSong(c *fiber.Ctx) error {
+	if !ws.IsUpgrade(c) {
+		return c.Status(426).JSON(fiber.Map{"error": "Collaborative editing needs a WebSocket connection"})
+	}
+	editor := strings.TrimSpace(c.Query("editor"))
+	if editor == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Editor name is required"})
+	}
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+	canSave := !h.review.RequireApproval || h.isReviewer(c)
+
+	err = ws.Upgrade(c, func(conn *ws.Conn) {
+		e, err := h.collab.Join(song.ID, editor, func() (collab.Document, error) {
+			return songDocument(song), nil
+		})
+		if err != nil {
+			log.Printf("Error starting collaboration on song %s: %v", song.ID, err)
+			return
+		}
+		defer h.collab.Leave(e)
+		go writeCollab(conn, e)
+
+		for {
+			data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var msg collab.Message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				h.collab.Send(e, collab.Message{Type: collab.MessageError, Error: "Invalid message"})
+				continue
+			}
+			switch msg.Type {
+			case collab.MessagePatch:
+				if msg.Patch == nil {
+					h.collab.Send(e, collab.Message{Type: collab.MessageError, Error: "Patch is required"})
+					continue
+				}
+				h.collab.Apply(e, *msg.Patch)
+			case collab.MessageSave:
+				if !canSave {
+					h.collab.Send(e, collab.Message{Type: collab.MessageError, Error: "Saving needs a reviewer; propose the changes as an edit instead"})
+					continue
+				}
+				h.saveCollabDraft(e)
+			default:
+				h.collab.Send(e, collab.Message{Type: collab.MessageError, Error: "Unknown message type"})
+			}
+		}
+	})
+	return socketError(c, err)
+}
+
+// writeCollab sends an editor's messages until the session drops them
+func writeCollab(conn *ws.Conn, e *collab.Editor) {
+	ticker := time.NewTicker(liveKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg, ok := <-e.Messages:
+			if !ok || conn.WriteJSON(msg) != nil {
+				// Closing also ends the reader
+				conn.Close()
+				return
+			}
+		case <-ticker.C:
+			if conn.Ping() != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// saveCollabDraft writes an editor's draft to the song, the way a plain
+// update would
+func (h *Handler) saveCollabDraft(e *collab.Editor) {
+	doc := h.collab.Snapshot(e)
+	title := doc.Fields[collab.FieldTitle].Value
+	lang := doc.Fields[collab.FieldLanguage].Value
+	displayLyrics := doc.Lyrics()
+	ministryLyrics := doc.Fields[collab.FieldMusicMinistryLyrics].Value
+	req := models.UpdateSongRequest{Title: &title, Language: &lang, DisplayLyrics: &displayLyrics, MusicMinistryLyrics: &ministryLyrics, EditedBy: e.Name}
+	if artist := doc.Fields[collab.FieldArtist]; artist.Version > 0 {
+		req.Artist = &artist.Value
+	}
+	normalizeSongUpdate(&req)
+	h.formatSongUpdate(&req)
+	if *req.Title == "" {
+		h.collab.Send(e, collab.Message{Type: collab.MessageError, Error: "Title is required"})
+		return
+	}
+
+	song, err := h.db.UpdateSong(doc.SongID, &req)
+	if err != nil {
+		log.Printf("Error saving collaborative draft of song %s: %v", doc.SongID, err)
+		h.collab.Send(e, collab.Message{Type: collab.MessageError, Error: "Failed to save song"})
+		return
+	}
+	h.songSaved(song)
+
+	h.collab.Saved(e, doc.Version)
+	if warnings := h.displayWarnings(song); len(warnings) > 0 {
+		h.collab.Send(e, collab.Message{Type: collab.MessageDisplayWarnings, DisplayWarnings: warnings})
+	}
+}
+
+// GetSongEditors lists who has a song open for collaborative editing
+func (h *Handler) GetSongEditors(c *fiber.Ctx) error {
+	editors := h.collab.Editors(c.Params("id"))
+	if editors == nil {
+		editors = make([]string, 0)
+	}
+
+	return c.JSON(fiber.Map{"editors": editors})
+}
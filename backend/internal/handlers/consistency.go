@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/jobs"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const consistencyJob = "consistency"
+
+// Kinds of inconsistency the checker reports
+const (
+	issueMissingFromIndex = "missing_from_index" // active song with no search document
+	issueStaleIndex       = "stale_index"        // search document older than the song
+	issueOrphanedDocument = "orphaned_document"  // search document for a deleted or archived song
+	issueBrokenProUUID    = "broken_pro_uuid"    // pro_uuid not in the ProPresenter library
+)
+
+// Repairs, one per issue
+const (
+	repairIndex          = "index"
+	repairDeleteDocument = "delete_document"
+	repairRelink         = "relink" // point pro_uuid at the library item with the song's title
+	repairUnlink         = "unlink"
+)
+
+type consistencyIssue struct {
+	Kind    string `json:"kind"`
+	SongID  string `json:"song_id"`
+	Title   string `json:"title"`
+	Detail  string `json:"detail"`
+	Repair  string `json:"repair"`
+	ProUUID string `json:"pro_uuid,omitempty"` // the item a relink will point at
+}
+
+type consistencyReport struct {
+	Songs        int                `json:"songs"`
+	IndexChecked bool               `json:"index_checked"`
+	Indexed      int                `json:"indexed"`
+	ProChecked   bool               `json:"propresenter_checked"`
+	ProItems     int                `json:"propresenter_items"`
+	Issues       []consistencyIssue `json:"issues"`
+	Skipped      []string           `json:"skipped"` // checks that could not run, and why
+}
+
+// songState is what the checker needs to know about a song
+type songState struct {
+	title     string
+	updatedAt int64
+	archived  bool
+	proUUID   string
+}
+
+// checkConsistency cross-checks the database against the search index and,
+// when checkPro is set, the ProPresenter library
+func (h *Handler) checkConsistency(job *jobs.Job, checkPro bool) error {
+	report := consistencyReport{Issues: make([]consistencyIssue, 0), Skipped: make([]string, 0)}
+
+	songs := make(map[string]songState)
+	order := make([]string, 0)
+	err := h.db.EachSong(func(song *models.Song) error {
+		state := songState{title: song.Title, updatedAt: song.UpdatedAt.Unix(), archived: song.ArchivedAt != nil}
+		if song.ProUUID != nil {
+			state.proUUID = *song.ProUUID
+		}
+		songs[song.ID] = state
+		order = append(order, song.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load songs: %w", err)
+	}
+	report.Songs = len(songs)
+	job.SetTotal(len(songs), 0)
+
+	switch {
+	case h.ts == nil || h.skipTypesense:
+		report.Skipped = append(report.Skipped, "search index: Typesense is disabled")
+	default:
+		indexed, err := h.ts.IndexedSongs()
+		if err != nil {
+			report.Skipped = append(report.Skipped, "search index: "+err.Error())
+			break
+		}
+		report.IndexChecked = true
+		report.Indexed = len(indexed)
+
+		seen := make(map[string]bool, len(indexed))
+		for _, doc := range indexed {
+			seen[doc.ID] = true
+			song, ok := songs[doc.ID]
+			switch {
+			case !ok:
+				report.Issues = append(report.Issues, consistencyIssue{Kind: issueOrphanedDocument, SongID: doc.ID, Title: doc.Title, Detail: "song no longer exists", Repair: repairDeleteDocument})
+			case song.archived:
+				report.Issues = append(report.Issues, consistencyIssue{Kind: issueOrphanedDocument, SongID: doc.ID, Title: song.title, Detail: "song is archived", Repair: repairDeleteDocument})
+			case doc.UpdatedAt != song.updatedAt || doc.Title != song.title:
+				report.Issues = append(report.Issues, consistencyIssue{
+					Kind: issueStaleIndex, SongID: doc.ID, Title: song.title, Repair: repairIndex,
+					Detail: fmt.Sprintf("indexed version is from %s, song was updated %s",
+						time.Unix(doc.UpdatedAt, 0).UTC().Format(time.RFC3339), time.Unix(song.updatedAt, 0).UTC().Format(time.RFC3339)),
+				})
+			}
+		}
+		for _, id := range order {
+			if song := songs[id]; !song.archived && !seen[id] {
+				report.Issues = append(report.Issues, consistencyIssue{Kind: issueMissingFromIndex, SongID: id, Title: song.title, Detail: "song is not in the search index", Repair: repairIndex})
+			}
+		}
+	}
+
+	switch {
+	case !checkPro:
+	case !h.propresenter.IsEnabled():
+		report.Skipped = append(report.Skipped, "ProPresenter library: integration is disabled")
+	default:
+		items, err := h.propresenter.GetLibrary()
+		if err != nil {
+			report.Skipped = append(report.Skipped, "ProPresenter library: "+err.Error())
+			break
+		}
+		report.ProChecked = true
+		report.ProItems = len(items)
+
+		uuids := make(map[string]bool, len(items))
+		byTitle := make(map[string]string, len(items))
+		for _, item := range items {
+			uuids[item.ID.UUID] = true
+			byTitle[normalizeLyricLine(item.ID.Name)] = item.ID.UUID
+		}
+		for _, id := range order {
+			song := songs[id]
+			if song.proUUID == "" || uuids[song.proUUID] {
+				continue
+			}
+			issue := consistencyIssue{Kind: issueBrokenProUUID, SongID: id, Title: song.title, Detail: "pro_uuid " + song.proUUID + " is not in the library", Repair: repairUnlink}
+			if uuid, ok := byTitle[normalizeLyricLine(song.title)]; ok {
+				issue.Repair, issue.ProUUID = repairRelink, uuid
+			}
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	job.Done(len(songs))
+	job.SetResult(report)
+	return nil
+}
+
+// CheckConsistency starts a background check of the database against the
+// search index (and the ProPresenter library with ?propresenter=true). The
+// report is in the job's result at GET /admin/consistency/:id.
+func (h *Handler) CheckConsistency(c *fiber.Ctx) error {
+	checkPro := c.Query("propresenter") == "true"
+	job, started := h.jobs.Start(consistencyJob, func(job *jobs.Job) error {
+		return h.checkConsistency(job, checkPro)
+	})
+	status := job.Status()
+	if !started {
+		return c.Status(409).JSON(fiber.Map{"error": "A consistency check is already running", "job_id": status.ID})
+	}
+
+	return c.Status(202).JSON(fiber.Map{
+		"message": "Consistency check started",
+		"job_id":  status.ID,
+	})
+}
+
+// GetConsistencyJob reports the progress of a consistency check, with its
+// report once finished
+func (h *Handler) GetConsistencyJob(c *fiber.Ctx) error {
+	status, ok := h.jobs.Get(c.Params("id"))
+	if !ok || status.Kind != consistencyJob {
+		return c.Status(404).JSON(fiber.Map{"error": "Consistency check not found"})
+	}
+
+	return c.JSON(status)
+}
+
+// RepairConsistency fixes one reported issue (kind and song_id, plus pro_uuid
+// for a relink). The current state is checked again first, so repairing an
+// issue that has since gone away is harmless.
+func (h *Handler) RepairConsistency(c *fiber.Ctx) error {
+	var req struct {
+		Kind    string `json:"kind"`
+		SongID  string `json:"song_id"`
+		ProUUID string `json:"pro_uuid"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.SongID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "song_id is required"})
+	}
+
+	switch req.Kind {
+	case issueMissingFromIndex, issueStaleIndex, issueOrphanedDocument:
+		if h.ts == nil || h.skipTypesense {
+			return c.Status(400).JSON(fiber.Map{"error": "Typesense is disabled"})
+		}
+		song, err := h.db.GetSong(req.SongID)
+		if err != nil {
+			// Gone from the database, so only the search document is left
+			if err := h.ts.DeleteSong(req.SongID); err != nil {
+				h.reportError(c, "Error deleting orphaned search document", fmt.Errorf("song %s: %w", req.SongID, err))
+				return c.Status(502).JSON(fiber.Map{"error": "Failed to delete search document"})
+			}
+			return c.JSON(fiber.Map{"message": "Search document deleted", "repair": repairDeleteDocument})
+		}
+		// Indexing an archived song removes its document
+		if err := h.ts.IndexSong(song); err != nil {
+			h.reportError(c, "Error reindexing song", fmt.Errorf("song %s: %w", song.ID, err))
+			return c.Status(502).JSON(fiber.Map{"error": "Failed to update search index"})
+		}
+		if song.ArchivedAt != nil {
+			return c.JSON(fiber.Map{"message": "Search document deleted", "repair": repairDeleteDocument})
+		}
+		return c.JSON(fiber.Map{"message": "Song reindexed", "repair": repairIndex})
+
+	case issueBrokenProUUID:
+		var uuid *string
+		repair := repairUnlink
+		if req.ProUUID != "" {
+			items, err := h.propresenter.GetLibrary()
+			if err != nil {
+				return c.Status(502).JSON(fiber.Map{"error": err.Error()})
+			}
+			found := false
+			for _, item := range items {
+				found = found || item.ID.UUID == req.ProUUID
+			}
+			if !found {
+				return c.Status(409).JSON(fiber.Map{"error": "pro_uuid is not in the ProPresenter library"})
+			}
+			uuid, repair = &req.ProUUID, repairRelink
+		}
+		if err := h.db.SetSongProUUID(req.SongID, uuid); err != nil {
+			if err.Error() == "song not found" {
+				return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+			}
+			log.Printf("Error updating pro_uuid: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to update song"})
+		}
+		if repair == repairRelink {
+			return c.JSON(fiber.Map{"message": "Song relinked to ProPresenter", "repair": repair})
+		}
+		return c.JSON(fiber.Map{"message": "Song unlinked from ProPresenter", "repair": repair})
+
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "Unknown issue kind"})
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// copyrightSlides reports whether attribution slides should be added to
+// rendered output: ?copyright=true|false wins, otherwise the copyright_slide
+// setting. It also returns the church's CCLI license number.
+func (h *Handler) copyrightSlides(c *fiber.Ctx) (bool, string) {
+	settings, err := h.db.GetSettings()
+	if err != nil {
+		log.Printf("Error loading settings for copyright slide: %v", err)
+		return c.Query("copyright") == "true", ""
+	}
+
+	enabled := settings.CopyrightSlide
+	if v := c.Query("copyright"); v != "" {
+		enabled = v == "true"
+	}
+	return enabled, settings.CCLILicense
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const maxCueLength = 200
+
+// GetSongCues returns a song's band cues
+func (h *Handler) GetSongCues(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	cues, err := h.db.GetSongCues(id)
+	if err != nil {
+		log.Printf("Error getting song cues: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get cues"})
+	}
+	if cues == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "No cues set for this song"})
+	}
+
+	return c.JSON(cues)
+}
+
+// UpdateSongCues sets all of a song's band cues
+func (h *Handler) UpdateSongCues(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	req, errMsg := parseSongCuesRequest(c)
+	if errMsg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": errMsg})
+	}
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	cues, err := h.db.SetSongCues(id, req)
+	if err != nil {
+		log.Printf("Error saving song cues: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save cues"})
+	}
+
+	h.refreshStageCues(id)
+	return c.JSON(cues)
+}
+
+// DeleteSongCues clears a song's band cues
+func (h *Handler) DeleteSongCues(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.db.DeleteSongCues(id); err != nil {
+		if err.Error() == "cues not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "No cues set for this song"})
+		}
+		log.Printf("Error deleting song cues: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete cues"})
+	}
+
+	h.refreshStageCues(id)
+	return c.JSON(fiber.Map{"message": "Cues deleted successfully"})
+}
+
+// parseSongCuesRequest reads and validates a cues body, returning an error message on failure
+func parseSongCuesRequest(c *fiber.Ctx) (*models.SongCuesRequest, string) {
+	var req models.SongCuesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, "Invalid request body"
+	}
+
+	req.StartsWith = strings.TrimSpace(req.StartsWith)
+	req.Dynamics = strings.TrimSpace(req.Dynamics)
+	req.Ending = strings.ToLower(strings.TrimSpace(req.Ending))
+
+	if req.IntroBars != nil && (*req.IntroBars < 0 || *req.IntroBars > 64) {
+		return nil, "intro_bars must be between 0 and 64"
+	}
+	if len(req.StartsWith) > maxCueLength || len(req.Dynamics) > maxCueLength {
+		return nil, "starts_with and dynamics must be 200 characters or fewer"
+	}
+	if req.Ending != "" && !containsString(models.SongEndings, req.Ending) {
+		return nil, "ending must be one of " + strings.Join(models.SongEndings, ", ")
+	}
+
+	return &req, ""
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// publishStageCues sends the cues for the song now on screen to stage displays
+func (h *Handler) publishStageCues(songID string) {
+	if songID == "" {
+		h.live.SetCues("", nil)
+		return
+	}
+
+	cues, err := h.db.GetSongCues(songID)
+	if err != nil {
+		log.Printf("Error loading cues for live song %s: %v", songID, err)
+	}
+	h.live.SetCues(songID, cues)
+}
+
+// refreshStageCues re-sends cues when the edited song is currently live
+func (h *Handler) refreshStageCues(songID string) {
+	if current := h.live.Current(); current != nil && current.SongID == songID {
+		h.publishStageCues(songID)
+	}
+}
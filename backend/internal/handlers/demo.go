@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/demo"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// SeedDemoLibrary imports the demo library into an empty install and starts
+// indexing it. It returns the reindex job's ID, or "" without search. A
+// library that already has songs is left alone with "library is not empty".
+func (h *Handler) SeedDemoLibrary() (*models.ArchiveImportResult, string, error) {
+	settings, err := h.db.GetSettings()
+	if err != nil {
+		return nil, "", err
+	}
+	archive := demo.Archive(time.Now(), settings)
+
+	end := h.maintenance.Begin("demo")
+	result, err := h.db.ImportArchive(archive)
+	end()
+	if err != nil {
+		return nil, "", err
+	}
+	return result, h.indexImported(archive.Songs), nil
+}
+
+// SeedDemo fills an empty library with sample songs, a songbook, setlists
+// and usage history, for evaluating the system without real data
+func (h *Handler) SeedDemo(c *fiber.Ctx) error {
+	result, jobID, err := h.SeedDemoLibrary()
+	if err != nil {
+		if err.Error() == "library is not empty" {
+			return c.Status(409).JSON(fiber.Map{"error": "The demo library can only be added to an empty library"})
+		}
+		log.Printf("Error seeding demo library: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to seed demo library: " + err.Error()})
+	}
+
+	response := fiber.Map{"message": "Demo library added", "imported": result}
+	if jobID != "" {
+		response["reindex_job_id"] = jobID
+	}
+	return c.JSON(response)
+}
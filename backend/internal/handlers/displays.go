@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/live"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// Displays are expected to send a heartbeat every 15 seconds
+const (
+	displayStaleAfter   = 45 * time.Second
+	displayOfflineAfter = 2 * time.Minute
+	// How long a display may lag behind a slide change before it counts as stuck
+	displaySyncGrace = 10 * time.Second
+)
+
+// RegisterDisplay registers a display client under the name it uses on the
+// live channel (?display=). Registering again refreshes its details.
+func (h *Handler) RegisterDisplay(c *fiber.Ctx) error {
+	var req models.RegisterDisplay
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Name is required"})
+	}
+
+	display, err := h.db.RegisterDisplay(req.Name, live.NormalizeRole(req.Role), strings.TrimSpace(req.Resolution), c.Get("User-Agent"), c.IP())
+	if err != nil {
+		log.Printf("Error registering display: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to register display"})
+	}
+
+	return c.Status(201).JSON(display)
+}
+
+// DisplayHeartbeat records that a display is alive and what it is showing. A
+// 404 means the display should register again.
+func (h *Handler) DisplayHeartbeat(c *fiber.Ctx) error {
+	var beat models.DisplayHeartbeat
+	if err := c.BodyParser(&beat); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	display, err := h.db.DisplayHeartbeat(c.Params("name"), c.IP(), &beat)
+	if err != nil {
+		if err.Error() == "display not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Display not registered"})
+		}
+		log.Printf("Error recording display heartbeat: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to record heartbeat"})
+	}
+
+	return c.JSON(display)
+}
+
+// GetDisplays lists registered displays with their health, most urgent
+// first: offline, then stuck or stale, then healthy
+func (h *Handler) GetDisplays(c *fiber.Ctx) error {
+	displays, err := h.db.GetDisplays()
+	if err != nil {
+		log.Printf("Error getting displays: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get displays"})
+	}
+
+	now := time.Now()
+	connected := h.live.ConnectedDisplays()
+	current := h.live.Current()
+	blanked := h.live.IsBlanked()
+
+	attention := 0
+	for i := range displays {
+		d := &displays[i]
+		d.Connected = connected[d.Name]
+		age := now.Sub(d.LastSeenAt)
+		switch {
+		case age > displayOfflineAfter:
+			d.Status = models.DisplayOffline
+			d.Problem = fmt.Sprintf("no heartbeat for %s", age.Round(time.Second))
+		case age > displayStaleAfter:
+			d.Status = models.DisplayStale
+			d.Problem = fmt.Sprintf("no heartbeat for %s", age.Round(time.Second))
+		default:
+			d.Status = models.DisplayOnline
+		}
+		if d.Status != models.DisplayOffline && !d.Connected {
+			d.Problem = "not connected to the live channel"
+		}
+		if d.Status != models.DisplayOffline && !blanked && current != nil && now.Sub(current.UpdatedAt) > displaySyncGrace {
+			d.OutOfSync = !showing(d, current)
+			if d.OutOfSync && d.Problem == "" {
+				d.Problem = "not showing what is live"
+			}
+		}
+		if d.Problem != "" {
+			attention++
+		}
+	}
+	sort.SliceStable(displays, func(i, j int) bool {
+		return displayUrgency(&displays[i]) > displayUrgency(&displays[j])
+	})
+
+	return c.JSON(fiber.Map{
+		"displays":        displays,
+		"count":           len(displays),
+		"needs_attention": attention,
+	})
+}
+
+func displayUrgency(d *models.Display) int {
+	switch {
+	case d.Status == models.DisplayOffline:
+		return 2
+	case d.Problem != "":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// showing reports whether a display's last heartbeat matches what is live
+func showing(d *models.Display, current *live.NowShowing) bool {
+	if current.SongID != "" && (d.SongID == nil || *d.SongID != current.SongID) {
+		return false
+	}
+	return d.SlideIndex == nil || *d.SlideIndex == current.SlideIndex
+}
+
+// Font sizes a display can be told to use, in px
+const (
+	minDisplayFontSize = 8
+	maxDisplayFontSize = 400
+)
+
+// SendDisplayCommand sends a remote control command to one registered display
+// over the live channel: font_size (size or delta), jump_to (section label or
+// index), reload, or theme. The response says whether the display was
+// connected to receive it.
+func (h *Handler) SendDisplayCommand(c *fiber.Ctx) error {
+	var cmd live.Command
+	if err := c.BodyParser(&cmd); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	switch cmd.Command {
+	case live.CommandFontSize:
+		switch {
+		case cmd.Size != nil:
+			if *cmd.Size < minDisplayFontSize || *cmd.Size > maxDisplayFontSize {
+				return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("size must be between %d and %d", minDisplayFontSize, maxDisplayFontSize)})
+			}
+			cmd.Delta = nil
+		case cmd.Delta != nil && *cmd.Delta != 0:
+		default:
+			return c.Status(400).JSON(fiber.Map{"error": "font_size needs a size or a non-zero delta"})
+		}
+	case live.CommandJumpTo:
+		cmd.Section = strings.TrimSpace(cmd.Section)
+		if cmd.Section == "" && (cmd.Index == nil || *cmd.Index < 0) {
+			return c.Status(400).JSON(fiber.Map{"error": "jump_to needs a section label or index"})
+		}
+	case live.CommandReload:
+	case live.CommandTheme:
+		if cmd.Theme = strings.TrimSpace(cmd.Theme); cmd.Theme == "" {
+			return c.Status(400).JSON(fiber.Map{"error": "theme is required"})
+		}
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "command must be font_size, jump_to, reload or theme"})
+	}
+
+	display, err := h.db.GetDisplay(c.Params("id"))
+	if err != nil {
+		if err.Error() == "display not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Display not found"})
+		}
+		log.Printf("Error getting display: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get display"})
+	}
+
+	sent, delivered := h.live.SendCommand(display.Name, cmd)
+	if !delivered {
+		log.Printf("Display %q is not connected; %s command not delivered", display.Name, cmd.Command)
+	}
+
+	return c.JSON(fiber.Map{
+		"display":   display.Name,
+		"command":   sent,
+		"delivered": delivered,
+	})
+}
+
+// DeleteDisplay forgets a display, e.g. one that was replaced
+func (h *Handler) DeleteDisplay(c *fiber.Ctx) error {
+	if err := h.db.DeleteDisplay(c.Params("name")); err != nil {
+		if err.Error() == "display not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Display not found"})
+		}
+		log.Printf("Error deleting display: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete display"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Display deleted successfully"})
+}
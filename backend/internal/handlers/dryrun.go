@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+)
+
+// proPresenterFor returns the ProPresenter client for a request. With
+// ?dry_run=true, or while dry-run mode is on, it is a copy that logs what it
+// would change instead of changing it, and dry holds the log.
+func (h *Handler) proPresenterFor(c *fiber.Ctx) (pp *propresenter.Client, dry *propresenter.DryRunLog) {
+	if c.Query("dry_run") == "true" || h.propresenter.DryRunEnabled() {
+		return h.propresenter.DryRun()
+	}
+	return h.propresenter, nil
+}
+
+// dryRunResponse answers a dry run with the operations ProPresenter would
+// have been sent, plus the handler's own fields
+func dryRunResponse(c *fiber.Ctx, dry *propresenter.DryRunLog, response fiber.Map) error {
+	response["dry_run"] = true
+	response["operations"] = dry.Operations()
+	return c.JSON(response)
+}
+
+// GetProPresenterDryRun reports whether dry-run mode is on
+func (h *Handler) GetProPresenterDryRun(c *fiber.Ctx) error {
+	if h.propresenter == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+	return c.JSON(fiber.Map{"dry_run": h.propresenter.DryRunEnabled()})
+}
+
+// SetProPresenterDryRun turns dry-run mode on or off. While it is on nothing
+// the server does changes ProPresenter; changes are logged instead.
+func (h *Handler) SetProPresenterDryRun(c *fiber.Ctx) error {
+	if h.propresenter == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Enabled == nil {
+		return c.Status(400).JSON(fiber.Map{"error": "enabled is required"})
+	}
+
+	changed := h.propresenter.DryRunEnabled() != *req.Enabled
+	h.propresenter.SetDryRun(*req.Enabled)
+	if changed && *req.Enabled {
+		log.Println("🧪 ProPresenter dry-run mode enabled")
+	} else if changed {
+		log.Println("ProPresenter dry-run mode disabled")
+	}
+
+	return c.JSON(fiber.Map{"success": true, "dry_run": *req.Enabled, "changed": changed})
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/database"
+	"github.com/yourusername/audience-stage-teleprompter/internal/duplicates"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// maxDuplicateCandidates caps the candidates returned with a 409
+const maxDuplicateCandidates = 5
+
+// fingerprintedSong is a library song prepared for duplicate checks
+type fingerprintedSong struct {
+	song        models.DuplicateSong
+	fingerprint duplicates.Fingerprint
+}
+
+// fingerprintRefreshSlack is how far back a refresh rereads edited songs, so
+// an edit whose transaction started before the last refresh but committed
+// after it isn't missed
+const fingerprintRefreshSlack = time.Minute
+
+// fingerprintCache keeps the library's fingerprints between duplicate checks
+// instead of reading every song for each new one. Before a check the
+// library's version is compared with the one the cache was built from: new
+// and edited songs are then fingerprinted on their own, and the cache is only
+// rebuilt when songs have been archived, restored or have left the library.
+// Writes from the CLI or another server show up the same way.
+type fingerprintCache struct {
+	mu      sync.Mutex
+	songs   map[string]fingerprintedSong // by song ID, nil until loaded
+	version database.SongLibraryVersion
+	bySize  []fingerprintedSong // by trigram count, for lyrics-only matches
+	byTitle map[string][]fingerprintedSong
+}
+
+// libraryFingerprints brings the cache up to date and returns it locked; the
+// caller unlocks it once done with it
+func (h *Handler) libraryFingerprints() (*fingerprintCache, error) {
+	cache := &h.fingerprints
+	cache.mu.Lock()
+	if err := cache.refresh(h.db); err != nil {
+		cache.mu.Unlock()
+		return nil, err
+	}
+	return cache, nil
+}
+
+func (c *fingerprintCache) refresh(db *database.DB) error {
+	version, err := db.GetSongLibraryVersion()
+	if err != nil {
+		return err
+	}
+	if c.songs != nil && version == c.version {
+		return nil
+	}
+
+	if c.songs != nil && version.Archived == c.version.Archived {
+		since := c.version.Updated.Add(-fingerprintRefreshSlack)
+		if err := db.EachFilteredSong(&models.SongFilter{UpdatedSince: &since}, c.add); err != nil {
+			return err
+		}
+	}
+	// Trashed songs don't show up as updated, so a count that doesn't add up
+	// means some are gone
+	if c.songs == nil || version.Archived != c.version.Archived || len(c.songs) != version.Songs {
+		c.songs = make(map[string]fingerprintedSong)
+		if err := db.EachSong(c.add); err != nil {
+			c.songs = nil
+			return err
+		}
+	}
+
+	c.version = version
+	c.index()
+	return nil
+}
+
+func (c *fingerprintCache) add(song *models.Song) error {
+	c.songs[song.ID] = fingerprintedSong{
+		song:        duplicateSong(song),
+		fingerprint: duplicates.Of(song.Title, song.DisplayLyrics),
+	}
+	return nil
+}
+
+func (c *fingerprintCache) index() {
+	c.bySize = make([]fingerprintedSong, 0, len(c.songs))
+	c.byTitle = make(map[string][]fingerprintedSong)
+	for _, song := range c.songs {
+		c.bySize = append(c.bySize, song)
+		if song.fingerprint.Title != "" {
+			c.byTitle[song.fingerprint.Title] = append(c.byTitle[song.fingerprint.Title], song)
+		}
+	}
+	sort.Slice(c.bySize, func(i, j int) bool {
+		a, b := c.bySize[i], c.bySize[j]
+		if a.fingerprint.Size() != b.fingerprint.Size() {
+			return a.fingerprint.Size() < b.fingerprint.Size()
+		}
+		return a.song.ID < b.song.ID
+	})
+}
+
+// candidates returns the songs that could match a fingerprint: those with
+// its title, and those with lyrics close enough in size to match without it
+func (c *fingerprintCache) candidates(fingerprint duplicates.Fingerprint) []fingerprintedSong {
+	seen := make(map[string]bool)
+	candidates := make([]fingerprintedSong, 0)
+	for _, song := range c.byTitle[fingerprint.Title] {
+		seen[song.song.ID] = true
+		candidates = append(candidates, song)
+	}
+
+	if fingerprint.Size() == 0 {
+		return candidates
+	}
+	min, max := duplicates.LyricsOnlySizes(fingerprint.Size())
+	first := sort.Search(len(c.bySize), func(i int) bool {
+		return c.bySize[i].fingerprint.Size() >= min
+	})
+	for _, song := range c.bySize[first:] {
+		if song.fingerprint.Size() > max {
+			break
+		}
+		if !seen[song.song.ID] {
+			candidates = append(candidates, song)
+		}
+	}
+	return candidates
+}
+
+func duplicateSong(song *models.Song) models.DuplicateSong {
+	return models.DuplicateSong{
+		ID:       song.ID,
+		Title:    song.Title,
+		Artist:   song.Artist,
+		Language: song.Language,
+		Library:  song.Library,
+		Archived: song.ArchivedAt != nil,
+	}
+}
+
+// duplicateCandidates lists the library songs a new song looks like, most
+// similar first
+func (h *Handler) duplicateCandidates(req *models.CreateSongRequest) ([]models.DuplicateCandidate, error) {
+	library, err := h.libraryFingerprints()
+	if err != nil {
+		return nil, err
+	}
+	defer library.mu.Unlock()
+
+	fingerprint := duplicates.Of(req.Title, req.DisplayLyrics)
+	candidates := make([]models.DuplicateCandidate, 0)
+	for _, existing := range library.candidates(fingerprint) {
+		match, ok := duplicates.Compare(fingerprint, existing.fingerprint)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, models.DuplicateCandidate{
+			DuplicateSong:    existing.song,
+			TitleMatch:       match.TitleMatch,
+			LyricsSimilarity: roundSimilarity(match.LyricsSimilarity),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].LyricsSimilarity > candidates[j].LyricsSimilarity
+	})
+	if len(candidates) > maxDuplicateCandidates {
+		candidates = candidates[:maxDuplicateCandidates]
+	}
+	return candidates, nil
+}
+
+// rejectDuplicate answers a create with 409 when the song looks like one
+// already in the library. ?force=true skips the check. A failed check is
+// logged and lets the song through rather than blocking new songs.
+func (h *Handler) rejectDuplicate(c *fiber.Ctx, req *models.CreateSongRequest) (bool, error) {
+	if c.Query("force") == "true" {
+		return false, nil
+	}
+	candidates, err := h.duplicateCandidates(req)
+	if err != nil {
+		log.Printf("Error checking for duplicate songs: %v", err)
+		return false, nil
+	}
+	if len(candidates) == 0 {
+		return false, nil
+	}
+	return true, c.Status(409).JSON(fiber.Map{
+		"error":      "A similar song is already in the library; send force=true to create it anyway",
+		"candidates": candidates,
+	})
+}
+
+// GetDuplicates lists pairs of library songs that look like the same song,
+// most similar first. Songs are only compared with songs sharing their
+// normalized title or first line, which keeps the check quick on large
+// libraries while still catching retitled copies.
+func (h *Handler) GetDuplicates(c *fiber.Ctx) error {
+	cache, err := h.libraryFingerprints()
+	if err != nil {
+		log.Printf("Error getting songs for duplicate check: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to check for duplicates"})
+	}
+	defer cache.mu.Unlock()
+	// Title order puts each pair's songs in a stable order
+	library := append([]fingerprintedSong(nil), cache.bySize...)
+	sort.Slice(library, func(i, j int) bool {
+		if library[i].song.Title != library[j].song.Title {
+			return library[i].song.Title < library[j].song.Title
+		}
+		return library[i].song.ID < library[j].song.ID
+	})
+
+	blocks := make(map[string][]int)
+	for i, song := range library {
+		if song.fingerprint.Title != "" {
+			blocks["title:"+song.fingerprint.Title] = append(blocks["title:"+song.fingerprint.Title], i)
+		}
+		if song.fingerprint.FirstLine != "" {
+			blocks["line:"+song.fingerprint.FirstLine] = append(blocks["line:"+song.fingerprint.FirstLine], i)
+		}
+	}
+
+	type pairKey struct{ a, b int }
+	seen := make(map[pairKey]bool)
+	pairs := make([]models.DuplicatePair, 0)
+	for _, block := range blocks {
+		for x := 0; x < len(block); x++ {
+			for y := x + 1; y < len(block); y++ {
+				key := pairKey{block[x], block[y]}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				a, b := library[key.a], library[key.b]
+				match, ok := duplicates.Compare(a.fingerprint, b.fingerprint)
+				if !ok {
+					continue
+				}
+				pairs = append(pairs, models.DuplicatePair{
+					Songs:            [2]models.DuplicateSong{a.song, b.song},
+					TitleMatch:       match.TitleMatch,
+					LyricsSimilarity: roundSimilarity(match.LyricsSimilarity),
+				})
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].LyricsSimilarity != pairs[j].LyricsSimilarity {
+			return pairs[i].LyricsSimilarity > pairs[j].LyricsSimilarity
+		}
+		return pairs[i].Songs[0].Title < pairs[j].Songs[0].Title
+	})
+
+	return c.JSON(fiber.Map{
+		"pairs": pairs,
+		"count": len(pairs),
+	})
+}
+
+// roundSimilarity keeps two decimals, which is all a reviewer needs
+func roundSimilarity(similarity float64) float64 {
+	return math.Round(similarity*100) / 100
+}
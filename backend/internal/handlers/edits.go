@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// reviewerHeader carries a reviewer's token
+const reviewerHeader = "X-Reviewer-Token"
+
+// ReviewConfig controls who may change songs directly. Contributors without
+// a reviewer token propose edits, which a reviewer approves or rejects.
+type ReviewConfig struct {
+	Tokens          []string // reviewer tokens, sent as X-Reviewer-Token
+	RequireApproval bool     // only reviewers may update songs directly
+}
+
+// SetReviewConfig sets the reviewer tokens and whether song changes need approval
+func (h *Handler) SetReviewConfig(cfg ReviewConfig) {
+	h.review = cfg
+}
+
+// isReviewer reports whether the request carries a reviewer token or comes
+// from a signed-in editor or admin
+func (h *Handler) isReviewer(c *fiber.Ctx) bool {
+	if user := currentUser(c); user != nil && models.RoleCovers(user.Role, models.RoleEditor) {
+		return true
+	}
+	token := c.Get(reviewerHeader)
+	if token == "" {
+		return false
+	}
+	for _, t := range h.review.Tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEditChanges validates and normalizes proposed changes the way an
+// update would, returning why they can't be proposed
+func checkEditChanges(changes *models.UpdateSongRequest) string {
+	if changes.Numbers != nil || changes.Links != nil {
+		return "Songbook numbers and links can't be proposed as edits"
+	}
+	if err := normalizeKeyField("original_key", changes.OriginalKey); err != nil {
+		return err.Error()
+	}
+	if err := normalizeKeyField("performance_key", changes.PerformanceKey); err != nil {
+		return err.Error()
+	}
+	if err := validateTempoFields(changes.BPM, changes.TimeSignature, changes.CountInBeats); err != nil {
+		return err.Error()
+	}
+	normalizeSongUpdate(changes)
+	if changes.Title != nil && *changes.Title == "" {
+		return "Title can't be empty"
+	}
+	if fields, _ := json.Marshal(changes); string(fields) == "{}" {
+		return "No changes proposed"
+	}
+	return ""
+}
+
+// currentValues returns the song's values of the fields an edit changes, so
+// a reviewer can compare them
+func currentValues(song *models.Song, changes *models.UpdateSongRequest) map[string]interface{} {
+	var proposed, saved map[string]interface{}
+	data, _ := json.Marshal(changes)
+	json.Unmarshal(data, &proposed)
+	data, _ = json.Marshal(song)
+	json.Unmarshal(data, &saved)
+
+	current := make(map[string]interface{}, len(proposed))
+	for field := range proposed {
+		current[field] = saved[field]
+	}
+	return current
+}
+
+func (h *Handler) songEditError(c *fiber.Ctx, err error, action string) error {
+	switch err.Error() {
+	case "song edit not found":
+		return c.Status(404).JSON(fiber.Map{"error": "Song edit not found"})
+	case "song edit already reviewed":
+		return c.Status(409).JSON(fiber.Map{"error": "Song edit has already been reviewed"})
+	}
+	log.Printf("Error %s song edit: %v", action, err)
+	return c.Status(500).JSON(fiber.Map{"error": "Failed to " + action + " song edit"})
+}
+
+// ProposeSongEdit stores a contributor's proposed change to a song, as a
+// draft or (submit=true) straight into the review queue
+func (h *Handler) ProposeSongEdit(c *fiber.Ctx) error {
+	var req models.SongEditRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Contributor = strings.TrimSpace(req.Contributor)
+	if req.Contributor == "" {
+		req.Contributor = strings.TrimSpace(c.Get(operatorHeader))
+	}
+	if req.Contributor == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Contributor is required"})
+	}
+	if msg := checkEditChanges(&req.Changes); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	h.formatSongUpdate(&req.Changes)
+
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	edit, err := h.db.CreateSongEdit(song.ID, &req)
+	if err != nil {
+		log.Printf("Error creating song edit: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save song edit"})
+	}
+
+	return c.Status(201).JSON(edit)
+}
+
+// GetSongEdits lists the edits proposed for a song (?status= to filter)
+func (h *Handler) GetSongEdits(c *fiber.Ctx) error {
+	edits, err := h.db.GetSongEdits(c.Params("id"), c.Query("status"))
+	if err != nil {
+		log.Printf("Error getting song edits: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get song edits"})
+	}
+
+	return c.JSON(edits)
+}
+
+// GetEditQueue lists proposed edits across all songs, pending ones unless
+// ?status= asks for another state (or "all")
+func (h *Handler) GetEditQueue(c *fiber.Ctx) error {
+	status := c.Query("status", models.EditPending)
+	if status == "all" {
+		status = ""
+	}
+
+	edits, err := h.db.GetSongEdits("", status)
+	if err != nil {
+		log.Printf("Error getting song edits: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get song edits"})
+	}
+
+	return c.JSON(edits)
+}
+
+// GetSongEdit returns a proposed edit with the song's current values of the
+// fields it changes
+func (h *Handler) GetSongEdit(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid edit ID"})
+	}
+
+	edit, err := h.db.GetSongEdit(id)
+	if err != nil {
+		return h.songEditError(c, err, "get")
+	}
+	if song, err := h.db.GetSong(edit.SongID); err == nil {
+		edit.Current = currentValues(song, &edit.Changes)
+	}
+
+	return c.JSON(edit)
+}
+
+// UpdateSongEdit revises an edit that hasn't been reviewed yet
+// (submit=true also sends a draft for review)
+func (h *Handler) UpdateSongEdit(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid edit ID"})
+	}
+
+	var req models.SongEditRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if msg := checkEditChanges(&req.Changes); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	h.formatSongUpdate(&req.Changes)
+
+	edit, err := h.db.UpdateSongEdit(id, &req)
+	if err != nil {
+		return h.songEditError(c, err, "update")
+	}
+
+	return c.JSON(edit)
+}
+
+// SubmitSongEdit sends a draft edit for review
+func (h *Handler) SubmitSongEdit(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid edit ID"})
+	}
+
+	edit, err := h.db.SubmitSongEdit(id)
+	if err != nil {
+		return h.songEditError(c, err, "submit")
+	}
+
+	return c.JSON(edit)
+}
+
+// ApproveSongEdit applies a pending edit to the song, which updates its
+// search document like any other save. Reviewers only.
+func (h *Handler) ApproveSongEdit(c *fiber.Ctx) error {
+	if !h.isReviewer(c) {
+		return c.Status(403).JSON(fiber.Map{"error": "Only reviewers can approve edits"})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid edit ID"})
+	}
+	var review models.SongEditReview
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&review); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	edit, err := h.db.GetSongEdit(id)
+	if err != nil {
+		return h.songEditError(c, err, "get")
+	}
+	if edit.Status != models.EditPending {
+		return c.Status(409).JSON(fiber.Map{"error": "Only edits pending review can be approved"})
+	}
+
+	edit.Changes.EditedBy = edit.Contributor
+	edit.Changes.RevisionNote = fmt.Sprintf("Approved edit %d", id)
+	song, err := h.db.UpdateSong(edit.SongID, &edit.Changes)
+	if err != nil {
+		log.Printf("Error applying song edit %d: %v", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update song"})
+	}
+	h.songSaved(song)
+	h.collab.Reload(song.ID, songDocument(song))
+	song.DisplayWarnings = h.displayWarnings(song)
+
+	if edit, err = h.db.ReviewSongEdit(id, models.EditApproved, strings.TrimSpace(c.Get(operatorHeader)), strings.TrimSpace(review.Note)); err != nil {
+		return h.songEditError(c, err, "approve")
+	}
+
+	return c.JSON(fiber.Map{"edit": edit, "song": song})
+}
+
+// RejectSongEdit turns down a pending edit, with an optional note for the
+// contributor. Reviewers only.
+func (h *Handler) RejectSongEdit(c *fiber.Ctx) error {
+	if !h.isReviewer(c) {
+		return c.Status(403).JSON(fiber.Map{"error": "Only reviewers can reject edits"})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid edit ID"})
+	}
+	var review models.SongEditReview
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&review); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	edit, err := h.db.GetSongEdit(id)
+	if err != nil {
+		return h.songEditError(c, err, "get")
+	}
+	if edit.Status != models.EditPending {
+		return c.Status(409).JSON(fiber.Map{"error": "Only edits pending review can be rejected"})
+	}
+
+	if edit, err = h.db.ReviewSongEdit(id, models.EditRejected, strings.TrimSpace(c.Get(operatorHeader)), strings.TrimSpace(review.Note)); err != nil {
+		return h.songEditError(c, err, "reject")
+	}
+
+	return c.JSON(edit)
+}
+
+// DeleteSongEdit withdraws an edit that hasn't been reviewed yet
+func (h *Handler) DeleteSongEdit(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid edit ID"})
+	}
+
+	if err := h.db.DeleteSongEdit(id); err != nil {
+		return h.songEditError(c, err, "delete")
+	}
+
+	return c.JSON(fiber.Map{"message": "Song edit deleted successfully"})
+}
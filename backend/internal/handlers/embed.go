@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/live"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// slideLines returns the plain-text lines of the slide on screen: the
+// segmented slide of song, or the pushed slide text for content outside the
+// song library. It returns nil when the index is out of range.
+func slideLines(current *live.NowShowing, song *models.Song) []string {
+	if song != nil {
+		slides, _ := lyrics.Segment(song.DisplayLyrics, lyrics.DefaultSegmentOptions)
+		if current.SlideIndex >= 0 && current.SlideIndex < len(slides) {
+			return slides[current.SlideIndex].Lines
+		}
+		return nil
+	}
+	if current.SlideIndex >= 0 && current.SlideIndex < len(current.Slides) {
+		return strings.Split(current.Slides[current.SlideIndex], "\n")
+	}
+	return nil
+}
+
+var (
+	// embedColor accepts hex colors without the leading # (which would start
+	// the URL fragment), CSS color names and "transparent"
+	embedColor = regexp.MustCompile(`^(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8}|[a-zA-Z]+)$`)
+	embedFont  = regexp.MustCompile(`^[\w ,'-]{1,80}$`)
+)
+
+// embedStyle is the look of the widget, set from query parameters
+type embedStyle struct {
+	Color   string
+	BG      string
+	Font    string
+	Size    int
+	Align   string
+	Shadow  bool
+	Title   bool
+	Refresh int // seconds between polls
+}
+
+func parseEmbedStyle(c *fiber.Ctx) (embedStyle, error) {
+	style := embedStyle{
+		Color:   "#ffffff",
+		BG:      "transparent",
+		Font:    "sans-serif",
+		Size:    48,
+		Align:   "center",
+		Shadow:  c.Query("shadow", "true") != "false",
+		Title:   c.Query("title") == "true",
+		Refresh: 1,
+	}
+
+	color := func(name, value string) (string, error) {
+		if !embedColor.MatchString(value) {
+			return "", fiber.NewError(400, name+" must be a hex color without # or a color name")
+		}
+		if _, err := strconv.ParseUint(value, 16, 32); err == nil {
+			return "#" + value, nil
+		}
+		return value, nil
+	}
+	var err error
+	if v := c.Query("color"); v != "" {
+		if style.Color, err = color("color", v); err != nil {
+			return style, err
+		}
+	}
+	if v := c.Query("bg"); v != "" {
+		if style.BG, err = color("bg", v); err != nil {
+			return style, err
+		}
+	}
+	if v := c.Query("font"); v != "" {
+		if !embedFont.MatchString(v) {
+			return style, fiber.NewError(400, "font may only contain letters, digits, spaces, commas, quotes and dashes")
+		}
+		style.Font = v
+	}
+	if v := c.Query("size"); v != "" {
+		if style.Size, err = strconv.Atoi(v); err != nil || style.Size < 8 || style.Size > 300 {
+			return style, fiber.NewError(400, "size must be between 8 and 300 pixels")
+		}
+	}
+	switch v := c.Query("align", "center"); v {
+	case "left", "center", "right":
+		style.Align = v
+	default:
+		return style, fiber.NewError(400, "align must be left, center or right")
+	}
+	if v := c.Query("refresh"); v != "" {
+		if style.Refresh, err = strconv.Atoi(v); err != nil || style.Refresh < 1 || style.Refresh > 30 {
+			return style, fiber.NewError(400, "refresh must be between 1 and 30 seconds")
+		}
+	}
+	return style, nil
+}
+
+// CSS renders the style; every value has been validated by parseEmbedStyle
+func (s embedStyle) CSS() template.CSS {
+	css := "color:" + s.Color + ";background:" + s.BG + ";font-family:" + s.Font +
+		";font-size:" + strconv.Itoa(s.Size) + "px;text-align:" + s.Align + ";"
+	if s.Shadow {
+		css += "text-shadow:0 2px 6px rgba(0,0,0,.8);"
+	}
+	return template.CSS(css)
+}
+
+// embedTemplate is a bare page for OBS browser sources. It fetches the JSON
+// form of the same URL to update in place, and falls back to reloading when
+// scripts are off.
+var embedTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8">
+<noscript><meta http-equiv="refresh" content="{{.Style.Refresh}}"></noscript>
+<title>Lyrics</title>
+<style>
+html,body{margin:0;height:100%;overflow:hidden}
+body{display:flex;flex-direction:column;justify-content:flex-end;padding:4vh 4vw;box-sizing:border-box;line-height:1.25;{{.Style.CSS}}}
+#title{font-size:.5em;opacity:.8;margin-bottom:.4em}
+</style></head>
+<body>
+{{if .Style.Title}}<div id="title">{{.Title}}</div>{{end}}
+<div id="lines">{{range .Lines}}<div>{{.}}</div>{{end}}</div>
+<script>
+(function () {
+  var url = new URL(location.href);
+  url.searchParams.set("format", "json");
+  var title = document.getElementById("title"), box = document.getElementById("lines"), last = "";
+  function render(data) {
+    var key = JSON.stringify(data);
+    if (key === last) return;
+    last = key;
+    if (title) title.textContent = data.title || "";
+    box.textContent = "";
+    (data.lines || []).forEach(function (line) {
+      var div = document.createElement("div");
+      div.textContent = line;
+      box.appendChild(div);
+    });
+  }
+  setInterval(function () {
+    fetch(url, {cache: "no-store"}).then(function (r) { return r.json(); }).then(render).catch(function () {});
+  }, {{.Style.Refresh}} * 1000);
+})();
+</script>
+</body></html>
+`))
+
+// EmbedCurrent serves a minimal HTML view of the slide on screen for
+// livestream overlays (e.g. an OBS browser source). Styling comes from query
+// parameters; ?format=json returns just the title and lines the page polls.
+func (h *Handler) EmbedCurrent(c *fiber.Ctx) error {
+	style, err := parseEmbedStyle(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	title := ""
+	lines := []string{}
+	if current := h.live.Current(); current != nil && !h.live.IsBlanked() {
+		title = current.Title
+		var song *models.Song
+		if current.SongID != "" {
+			song, _ = h.db.GetSong(current.SongID)
+		}
+		if l := slideLines(current, song); l != nil {
+			lines = l
+		}
+	}
+
+	c.Set("Cache-Control", "no-store")
+	if c.Query("format") == "json" {
+		return c.JSON(fiber.Map{"title": title, "lines": lines})
+	}
+
+	c.Type("html")
+	return embedTemplate.Execute(c, fiber.Map{"Style": style, "Title": title, "Lines": lines})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/errreport"
+)
+
+// reportedHeaders are the request headers sent along with an error report.
+// Token headers are left out.
+var reportedHeaders = []string{"User-Agent", "Referer", "Content-Type", operatorHeader}
+
+// SetErrorReporter sends panics, 5xx responses and background failures (search
+// indexing, ProPresenter calls) to an error tracker as well as the log
+func (h *Handler) SetErrorReporter(r *errreport.Reporter) {
+	h.reporter = r
+}
+
+// requestContext describes the request being handled for an error report
+func requestContext(c *fiber.Ctx) *errreport.Request {
+	if c == nil {
+		return nil
+	}
+	_, query, _ := strings.Cut(c.OriginalURL(), "?")
+	req := &errreport.Request{
+		Method:   c.Method(),
+		URL:      c.BaseURL() + c.Path(),
+		Query:    query,
+		Headers:  make(map[string]string),
+		ClientIP: c.IP(),
+	}
+	for _, name := range reportedHeaders {
+		if value := c.Get(name); value != "" {
+			req.Headers[name] = value
+		}
+	}
+	return req
+}
+
+// reportError logs a failure that doesn't fail the request and reports it.
+// c may be nil for work done outside a request.
+func (h *Handler) reportError(c *fiber.Ctx, message string, err error) {
+	log.Printf("%s: %v", message, err)
+	var tags map[string]string
+	if c != nil && c.Route() != nil {
+		tags = map[string]string{"route": c.Route().Path}
+	}
+	h.reporter.CaptureError(message, err, requestContext(c), tags)
+}
+
+// RecoverPanic is the recover middleware's stack trace handler: it logs the
+// stack and reports the panic
+func (h *Handler) RecoverPanic(c *fiber.Ctx, e interface{}) {
+	log.Printf("panic: %v\n%s", e, debug.Stack())
+	h.reporter.CapturePanic(e, requestContext(c))
+}
+
+// HandleError is the app's error handler. Errors that end in a 5xx are
+// reported; the response is Fiber's default.
+func (h *Handler) HandleError(c *fiber.Ctx, err error) error {
+	code := 500
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		code = fe.Code
+	}
+	if code >= 500 {
+		h.reporter.CaptureError("Unhandled error", err, requestContext(c), map[string]string{"status": strconv.Itoa(code)})
+	}
+	return fiber.DefaultErrorHandler(c, err)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/export"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+var (
+	pdfFontsOnce sync.Once
+	pdfFonts     map[string]export.PDFFont
+	pdfFontErrs  []error
+)
+
+// ExportSong downloads a song in another format (?format=chordpro|pdf)
+func (h *Handler) ExportSong(c *fiber.Ctx) error {
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	switch c.Query("format", "chordpro") {
+	case "chordpro":
+		setAttachment(c, export.FileName(song.Title, ".cho"))
+		c.Set("Content-Type", "text/plain; charset=utf-8")
+		return c.SendString(export.ChordPro(song))
+	case "pdf":
+		setAttachment(c, export.FileName(song.Title, ".pdf"))
+		c.Set("Content-Type", "application/pdf")
+		return c.Send(export.PDF("", []models.Song{*song}, pdfOptions(c)))
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "format must be chordpro or pdf"})
+	}
+}
+
+// ExportQueue downloads every song in the queue as one setlist file
+func (h *Handler) ExportQueue(c *fiber.Ctx) error {
+	songs, err := h.queueSongs()
+	if err != nil {
+		log.Printf("Error loading queue for export: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load queue"})
+	}
+	if len(songs) == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "Queue is empty"})
+	}
+
+	name := fmt.Sprintf("setlist-%s", time.Now().Format("2006-01-02"))
+
+	switch c.Query("format", "chordpro") {
+	case "chordpro":
+		setAttachment(c, name+".cho")
+		c.Set("Content-Type", "text/plain; charset=utf-8")
+		return c.SendString(export.ChordProSet(songs))
+	case "pdf":
+		setAttachment(c, name+".pdf")
+		c.Set("Content-Type", "application/pdf")
+		return c.Send(export.PDF("", songs, pdfOptions(c)))
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "format must be chordpro or pdf"})
+	}
+}
+
+// ExportLibrary streams every song into a zip archive, one file per song
+// (?format=openlyrics|chordpro) plus presenter notes and a manifest. The
+// archive is written as songs are read, so it is never held in memory.
+func (h *Handler) ExportLibrary(c *fiber.Ctx) error {
+	format := c.Query("format", export.LibraryOpenLyrics)
+	if format != export.LibraryOpenLyrics && format != export.LibraryChordPro {
+		return c.Status(400).JSON(fiber.Map{"error": "format must be openlyrics or chordpro"})
+	}
+
+	numbers, err := h.db.GetAllSongNumbers()
+	if err != nil {
+		log.Printf("Error loading song numbers for export: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to export library"})
+	}
+
+	setAttachment(c, fmt.Sprintf("library-%s.zip", time.Now().Format("2006-01-02")))
+	c.Set("Content-Type", "application/zip")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.WriteLibrary(w, format, numbers); err != nil {
+			// Headers are already sent, so the client just gets a truncated archive
+			log.Printf("Error exporting library: %v", err)
+			return
+		}
+		w.Flush()
+	})
+
+	return nil
+}
+
+// WriteLibrary writes every song to w as a zip in the given library format,
+// with each song's numbers from GetAllSongNumbers
+func (h *Handler) WriteLibrary(w io.Writer, format string, numbers map[string][]models.SongNumber) error {
+	lw, err := export.NewLibraryWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	err = h.db.EachSong(func(song *models.Song) error {
+		song.Numbers = numbers[song.ID]
+		if err := lw.AddSong(song); err != nil {
+			return err
+		}
+		return h.addNotesAttachment(lw, song)
+	})
+	if err != nil {
+		return err
+	}
+	return lw.Close()
+}
+
+// addNotesAttachment stores a song's presenter notes alongside it in the archive
+func (h *Handler) addNotesAttachment(lw *export.LibraryWriter, song *models.Song) error {
+	notes, err := h.db.GetSongNotes(song.ID)
+	if err != nil {
+		return err
+	}
+	if len(notes) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return lw.AddAttachment(song.ID, "presenter-notes.json", song.UpdatedAt, bytes.NewReader(data))
+}
+
+// queueSongs loads the full song record for each queue item, in queue order
+func (h *Handler) queueSongs() ([]models.Song, error) {
+	items, err := h.db.GetQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	songs := make([]models.Song, 0, len(items))
+	for _, item := range items {
+		song, err := h.db.GetSong(item.SongID)
+		if err != nil {
+			return nil, err
+		}
+		songs = append(songs, *song)
+	}
+	return songs, nil
+}
+
+// loadPDFFonts loads the fonts for non-Latin scripts from PDF_FONTS on first
+// use, returning them with any fonts that failed to load
+func loadPDFFonts() (map[string]export.PDFFont, []error) {
+	pdfFontsOnce.Do(func() {
+		pdfFonts, pdfFontErrs = export.LoadPDFFonts(os.Getenv("PDF_FONTS"))
+		for _, err := range pdfFontErrs {
+			log.Printf("Warning: PDF_FONTS: %v", err)
+		}
+	})
+	return pdfFonts, pdfFontErrs
+}
+
+// pdfOptions reads lyric sheet options from the query (?chords=true)
+func pdfOptions(c *fiber.Ctx) export.PDFOptions {
+	fonts, _ := loadPDFFonts()
+	return export.PDFOptions{
+		Chords: c.Query("chords") == "true",
+		Fonts:  fonts,
+	}
+}
+
+// setAttachment marks the response as a download, keeping non-ASCII titles intact
+func setAttachment(c *fiber.Ctx, fileName string) {
+	c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`,
+		asciiFileName(fileName), url.PathEscape(fileName)))
+}
+
+// asciiFileName is the fallback name for clients that ignore filename*
+func asciiFileName(name string) string {
+	out := make([]rune, 0, len(name))
+	for _, r := range name {
+		if r < 128 && r != '"' && r != '\\' {
+			out = append(out, r)
+		} else {
+			out = append(out, '_')
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// SetFormatOnSave makes created and updated songs' lyrics go through the
+// formatter, so they are saved with straight quotes and consistent section
+// labels without a preview first
+func (h *Handler) SetFormatOnSave(enabled bool) {
+	h.formatOnSave = enabled
+}
+
+// formatSongRequest formats a new song's lyrics when formatting on save is on
+func (h *Handler) formatSongRequest(req *models.CreateSongRequest) {
+	if !h.formatOnSave {
+		return
+	}
+	req.DisplayLyrics = lyrics.Format(req.DisplayLyrics).Lyrics
+	req.MusicMinistryLyrics = lyrics.Format(req.MusicMinistryLyrics).Lyrics
+}
+
+// formatSongUpdate does the same for the lyrics present in an update
+func (h *Handler) formatSongUpdate(req *models.UpdateSongRequest) {
+	if !h.formatOnSave {
+		return
+	}
+	if req.DisplayLyrics != nil {
+		*req.DisplayLyrics = lyrics.Format(*req.DisplayLyrics).Lyrics
+	}
+	if req.MusicMinistryLyrics != nil {
+		*req.MusicMinistryLyrics = lyrics.Format(*req.MusicMinistryLyrics).Lyrics
+	}
+}
+
+// FormatLyrics previews the formatter on lyrics that haven't been saved,
+// returning the cleaned text and a line-by-line diff to confirm
+func (h *Handler) FormatLyrics(c *fiber.Ctx) error {
+	var req struct {
+		Lyrics string `json:"lyrics"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	return c.JSON(lyrics.Format(req.Lyrics))
+}
+
+// GetSongFormatting previews the formatter on a song's saved lyrics
+func (h *Handler) GetSongFormatting(c *fiber.Ctx) error {
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	display, ministry := lyrics.Format(song.DisplayLyrics), lyrics.Format(song.MusicMinistryLyrics)
+	return c.JSON(fiber.Map{
+		"changed":               display.Changed || ministry.Changed,
+		"display_lyrics":        display,
+		"music_ministry_lyrics": ministry,
+	})
+}
+
+// FormatSong saves a song's lyrics as the formatter would leave them, once
+// the editor has confirmed the preview
+func (h *Handler) FormatSong(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if h.review.RequireApproval && !h.isReviewer(c) {
+		return c.Status(403).JSON(fiber.Map{"error": "Song changes need a reviewer's approval; propose them at POST /api/songs/" + id + "/edits"})
+	}
+
+	song, err := h.db.GetSong(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	display, ministry := lyrics.Format(song.DisplayLyrics), lyrics.Format(song.MusicMinistryLyrics)
+	if display.Changed || ministry.Changed {
+		update := models.UpdateSongRequest{DisplayLyrics: &display.Lyrics, MusicMinistryLyrics: &ministry.Lyrics,
+			EditedBy: editorName(c), RevisionNote: "Formatted lyrics"}
+		if song, err = h.db.UpdateSong(id, &update); err != nil {
+			log.Printf("Error saving formatted lyrics: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to update song"})
+		}
+		h.songSaved(song)
+		h.collab.Reload(id, songDocument(song))
+	}
+
+	return c.JSON(fiber.Map{
+		"changed":               display.Changed || ministry.Changed,
+		"display_lyrics":        display,
+		"music_ministry_lyrics": ministry,
+		"song":                  song,
+	})
+}
@@ -1,34 +1,90 @@
 package handlers
 
 import (
+	"bufio"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/advance"
+	"github.com/yourusername/audience-stage-teleprompter/internal/audio"
 	"github.com/yourusername/audience-stage-teleprompter/internal/backup"
+	"github.com/yourusername/audience-stage-teleprompter/internal/collab"
 	"github.com/yourusername/audience-stage-teleprompter/internal/database"
+	"github.com/yourusername/audience-stage-teleprompter/internal/editlock"
+	"github.com/yourusername/audience-stage-teleprompter/internal/errreport"
+	"github.com/yourusername/audience-stage-teleprompter/internal/export"
+	"github.com/yourusername/audience-stage-teleprompter/internal/jobs"
+	"github.com/yourusername/audience-stage-teleprompter/internal/links"
+	"github.com/yourusername/audience-stage-teleprompter/internal/live"
+	"github.com/yourusername/audience-stage-teleprompter/internal/maintenance"
 	"github.com/yourusername/audience-stage-teleprompter/internal/models"
 	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
-	"github.com/yourusername/audience-stage-teleprompter/internal/typesense"
+	"github.com/yourusername/audience-stage-teleprompter/internal/scripture"
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
 )
 
 type Handler struct {
 	db            *database.DB
-	ts            *typesense.Client
+	ts            search.Backend
 	backupManager *backup.Manager
 	propresenter  *propresenter.Client
+	live          *live.Hub
+	scripture     *scripture.Client
+	audio         *audio.Player
+	jobs          *jobs.Manager
+	locks         *editlock.Manager
+	collab        *collab.Manager
+	oembed        *links.Fetcher
+	advance       *advance.Engine
+	reporter      *errreport.Reporter
+	maintenance   *maintenance.Mode
+	queueSync     queueSync
+	librarySync   librarySync
+	fingerprints  fingerprintCache
 	skipTypesense bool
+
+	transliterateOnSave bool
+	review              ReviewConfig
+	formatOnSave        bool
+	auth                AuthConfig
+	sso                 sso
+	reloadConfig        ConfigReloader
 }
 
-func New(db *database.DB, ts *typesense.Client, backupManager *backup.Manager, pp *propresenter.Client, skipTypesense bool) *Handler {
-	return &Handler{
+func New(db *database.DB, ts search.Backend, backupManager *backup.Manager, pp *propresenter.Client, hub *live.Hub, sc *scripture.Client, player *audio.Player, skipTypesense bool) *Handler {
+	h := &Handler{
 		db:            db,
 		ts:            ts,
 		backupManager: backupManager,
 		propresenter:  pp,
+		live:          hub,
+		scripture:     sc,
+		audio:         player,
+		jobs:          jobs.NewManager(),
+		locks:         editlock.New(editlock.DefaultTTL),
+		collab:        collab.NewManager(),
+		oembed:        links.NewFetcher(),
 		skipTypesense: skipTypesense,
 	}
+	h.advance = advance.NewEngine(h.advanceStep, h.publishAutoAdvance)
+
+	// Songs changed while Typesense was unavailable are picked up by a full reindex
+	if ts != nil {
+		ts.OnReady(func(stale bool) {
+			if stale && !skipTypesense {
+				job, _ := h.startReindex()
+				log.Printf("Songs changed while Typesense was unavailable, reindex job %s started", job.Status().ID)
+				return
+			}
+			// Tokenization settings could not be checked against the index
+			// while it was unavailable
+			h.ApplySearchTokenization()
+		})
+	}
+
+	return h
 }
 
 // CreateSong creates a new song
@@ -37,10 +93,36 @@ func (h *Handler) CreateSong(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 	}
+	normalizeSongRequest(&req)
+	h.formatSongRequest(&req)
 
 	// Validation
-	if req.Title == "" || req.DisplayLyrics == "" || req.Language == "" || req.Library == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "Title, display lyrics, language, and library are required"})
+	if req.Title == "" || req.DisplayLyrics == "" || req.Library == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Title, display lyrics, and library are required"})
+	}
+	languageWarning := detectSongLanguage(&req)
+	if err := normalizeKeyField("original_key", req.OriginalKey); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := normalizeKeyField("performance_key", req.PerformanceKey); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := validateTempoFields(req.BPM, req.TimeSignature, req.CountInBeats); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if problems := chordsProblems(req.Chords); len(problems) > 0 {
+		return invalidChords(c, problems)
+	}
+	numbers, status, msg := h.checkSongNumbers("", req.Numbers)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{"error": msg})
+	}
+	songLinks, err := normalizeSongLinks(req.Links)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if rejected, err := h.rejectDuplicate(c, &req); rejected {
+		return err
 	}
 
 	// Create in database
@@ -49,23 +131,36 @@ func (h *Handler) CreateSong(c *fiber.Ctx) error {
 		log.Printf("Error creating song: %v", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to create song"})
 	}
+	if len(numbers) > 0 {
+		if err := h.db.SetSongNumbers(song.ID, numbers); err != nil {
+			log.Printf("Error saving song numbers: %v", err)
+		} else {
+			song.Numbers = numbers
+		}
+	}
+	if len(songLinks) > 0 {
+		songLinks = h.fillLinkMetadata("", songLinks, false)
+		if err := h.db.SetSongLinks(song.ID, songLinks); err != nil {
+			log.Printf("Error saving song links: %v", err)
+		} else {
+			song.Links = songLinks
+		}
+	}
 
 	// Index in Typesense (skip if skipTypesense is enabled or Typesense is disabled)
 	if !h.skipTypesense && h.ts != nil {
 		if err := h.ts.IndexSong(song); err != nil {
-			log.Printf("Error indexing song in Typesense: %v", err)
+			h.reportError(c, "Error indexing song in Typesense", err)
 			// Don't fail the request, just log the error
 		}
 	}
 
-	// Check backup threshold (async - don't block response)
-	go func() {
-		count, _ := h.db.GetEditCount()
-		if err := h.backupManager.CheckEditThreshold(count); err != nil {
-			log.Printf("Error checking backup threshold: %v", err)
-		}
-	}()
+	// Count the edit towards the next backup (debounced, never blocks)
+	h.backupManager.RecordEdits(1)
+	h.refreshRomanized(song)
 
+	song.LanguageWarning = languageWarning
+	song.DisplayWarnings = h.displayWarnings(song)
 	return c.Status(201).JSON(song)
 }
 
@@ -80,19 +175,62 @@ func (h *Handler) GetSong(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
 	}
+	if song.Numbers, err = h.db.GetSongNumbers(id); err != nil {
+		log.Printf("Error getting song numbers: %v", err)
+	}
+	if song.Links, err = h.db.GetSongLinks(id); err != nil {
+		log.Printf("Error getting song links: %v", err)
+	}
+	h.attachLock(c, song, false)
 
-	return c.JSON(song)
+	switch c.Query("format", "json") {
+	case "json":
+		return c.JSON(song)
+	case "chordpro":
+		// Shown inline for musicians; /export downloads it as a file
+		c.Set("Content-Type", "text/plain; charset=utf-8")
+		return c.SendString(export.ChordPro(song))
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "format must be json or chordpro"})
+	}
 }
 
-// GetAllSongs retrieves all songs
+// GetAllSongs retrieves a page of songs with the total, the first page
+// unless another is asked for. With all=true it streams every song as the
+// rows are read.
 func (h *Handler) GetAllSongs(c *fiber.Ctx) error {
-	songs, err := h.db.GetAllSongs()
+	filter, paged, err := parseSongFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	fields, err := parseSongFields(c)
 	if err != nil {
-		log.Printf("Error getting songs: %v", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve songs"})
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if paged {
+		return h.getSongPage(c, filter, fields)
 	}
 
-	return c.JSON(songs)
+	c.Set("Content-Type", "application/json")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		songs := newJSONArrayWriter(w)
+		err := h.db.EachFilteredSong(filter, func(song *models.Song) error {
+			if fields != nil {
+				return songs.Write(sparseSong{song: song, fields: fields})
+			}
+			return songs.Write(song)
+		})
+		if err != nil {
+			// Headers are already sent, so the client gets truncated JSON
+			log.Printf("Error getting songs: %v", err)
+			return
+		}
+		if err := songs.Close(); err != nil {
+			log.Printf("Error writing songs: %v", err)
+		}
+	})
+
+	return nil
 }
 
 // UpdateSong updates an existing song
@@ -107,32 +245,83 @@ func (h *Handler) UpdateSong(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 
-	// Update in database
-	song, err := h.db.UpdateSong(id, &req)
+	return h.saveSongUpdate(c, id, &req)
+}
+
+// saveSongUpdate validates an update to a song and saves it, for both PUT
+// and PATCH
+func (h *Handler) saveSongUpdate(c *fiber.Ctx, id string, req *models.UpdateSongRequest) error {
+	if err := normalizeKeyField("original_key", req.OriginalKey); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := normalizeKeyField("performance_key", req.PerformanceKey); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := validateTempoFields(req.BPM, req.TimeSignature, req.CountInBeats); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if problems := chordsProblems(req.Chords); len(problems) > 0 {
+		return invalidChords(c, problems)
+	}
+	if h.review.RequireApproval && !h.isReviewer(c) {
+		return c.Status(403).JSON(fiber.Map{"error": "Song changes need a reviewer's approval; propose them at POST /api/songs/" + id + "/edits"})
+	}
+	normalizeSongUpdate(req)
+	h.formatSongUpdate(req)
+	req.EditedBy = editorName(c)
+	if req.Numbers != nil {
+		numbers, status, msg := h.checkSongNumbers(id, *req.Numbers)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": msg})
+		}
+		req.Numbers = &numbers
+	}
+	if req.Links != nil {
+		songLinks, err := normalizeSongLinks(*req.Links)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		songLinks = h.fillLinkMetadata(id, songLinks, false)
+		req.Links = &songLinks
+	}
+
+	// Update in database, with the songbook references and links
+	song, err := h.db.UpdateSong(id, req)
 	if err != nil {
 		log.Printf("Error updating song: %v", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to update song"})
 	}
+	if song.Numbers, err = h.db.GetSongNumbers(id); err != nil {
+		log.Printf("Error getting song numbers: %v", err)
+	}
+	if song.Links, err = h.db.GetSongLinks(id); err != nil {
+		log.Printf("Error getting song links: %v", err)
+	}
+
+	h.songSaved(song)
+	h.attachLock(c, song, true)
+	song.DisplayWarnings = h.displayWarnings(song)
+	// Anyone editing the song together now works from what was just saved
+	h.collab.Reload(id, songDocument(song))
 
-	// Update in Typesense
+	return c.JSON(song)
+}
+
+// songSaved updates what follows from a changed song: its search document,
+// the backup schedule and its romanized lyrics
+func (h *Handler) songSaved(song *models.Song) {
 	if h.ts != nil {
 		if err := h.ts.IndexSong(song); err != nil {
-			log.Printf("Error updating song in Typesense: %v", err)
+			h.reportError(nil, "Error updating song in Typesense", fmt.Errorf("song %s: %w", song.ID, err))
 		}
 	}
 
-	// Check backup threshold (async - don't block response)
-	go func() {
-		count, _ := h.db.GetEditCount()
-		if err := h.backupManager.CheckEditThreshold(count); err != nil {
-			log.Printf("Error checking backup threshold: %v", err)
-		}
-	}()
-
-	return c.JSON(song)
+	// Count the edit towards the next backup (debounced, never blocks)
+	h.backupManager.RecordEdits(1)
+	h.refreshRomanized(song)
 }
 
-// DeleteSong deletes a song
+// DeleteSong moves a song to the trash, from where it can be restored
 func (h *Handler) DeleteSong(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
@@ -147,11 +336,11 @@ func (h *Handler) DeleteSong(c *fiber.Ctx) error {
 	// Delete from Typesense
 	if h.ts != nil {
 		if err := h.ts.DeleteSong(id); err != nil {
-			log.Printf("Error deleting song from Typesense: %v", err)
+			h.reportError(c, "Error deleting song from Typesense", err)
 		}
 	}
 
-	return c.JSON(fiber.Map{"message": "Song deleted successfully"})
+	return c.JSON(fiber.Map{"message": "Song moved to the trash"})
 }
 
 // SearchSongs searches for songs using Typesense
@@ -179,10 +368,24 @@ func (h *Handler) SearchSongs(c *fiber.Ctx) error {
 		}
 	}
 
+	// Archived songs aren't in the search index, so including them means
+	// searching PostgreSQL
+	includeArchived := c.Query("include_archived") == "true"
+
+	// Hymnal lookups such as "KK 123" go straight to the song numbers
+	if songs, ok := h.searchByNumber(query); ok {
+		if len(languages) > 0 {
+			songs = reorderByLanguage(filterToLanguages(songs, languages), languages)
+		}
+		if len(songs) > 0 {
+			return c.JSON(search.Result{Songs: songs, TotalFound: len(songs)})
+		}
+	}
+
 	// If no text query (wildcard) and languages selected, filter from DB directly to guarantee language-only view.
 	if len(languages) > 0 {
 		q := strings.TrimSpace(query)
-		songs, err := h.db.SearchSongs(q, languages)
+		songs, err := h.db.SearchSongs(q, languages, includeArchived)
 		if err != nil {
 			log.Printf("Error searching songs in DB: %v", err)
 			return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
@@ -191,7 +394,7 @@ func (h *Handler) SearchSongs(c *fiber.Ctx) error {
 		// Reorder by preference (stable within language)
 		songs = reorderByLanguage(songs, languages)
 
-		return c.JSON(typesense.SearchResult{
+		return c.JSON(search.Result{
 			Songs:      songs,
 			TotalFound: len(songs),
 			SearchTime: 0,
@@ -199,30 +402,14 @@ func (h *Handler) SearchSongs(c *fiber.Ctx) error {
 	}
 
 	// Use Typesense if available, otherwise fall back to PostgreSQL
-	if h.ts == nil {
-		// Fall back to PostgreSQL search
-		songs, err := h.db.SearchSongs(query, languages)
-		if err != nil {
-			log.Printf("Error searching songs in DB: %v", err)
-			return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
-		}
-		
-		// Reorder by preference (stable within language)
-		if len(languages) > 0 {
-			songs = reorderByLanguage(songs, languages)
-		}
-		
-		return c.JSON(typesense.SearchResult{
-			Songs:      songs,
-			TotalFound: len(songs),
-			SearchTime: 0,
-		})
+	if h.ts == nil || !h.ts.Ready() || includeArchived {
+		return h.searchDB(c, query, languages, includeArchived)
 	}
 	
 	results, err := h.ts.Search(query, languages)
 	if err != nil {
-		log.Printf("Error searching songs: %v", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
+		h.reportError(c, "Error searching songs in Typesense, using PostgreSQL", err)
+		return h.searchDB(c, query, languages, false)
 	}
 
 	// If specific languages are selected, drop others and prioritize selected languages in order.
@@ -234,6 +421,26 @@ func (h *Handler) SearchSongs(c *fiber.Ctx) error {
 	return c.JSON(results)
 }
 
+// searchDB is the PostgreSQL search used when Typesense is disabled or down
+func (h *Handler) searchDB(c *fiber.Ctx, query string, languages []string, includeArchived bool) error {
+	songs, err := h.db.SearchSongs(query, languages, includeArchived)
+	if err != nil {
+		log.Printf("Error searching songs in DB: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
+	}
+
+	// Reorder by preference (stable within language)
+	if len(languages) > 0 {
+		songs = reorderByLanguage(songs, languages)
+	}
+
+	return c.JSON(search.Result{
+		Songs:      songs,
+		TotalFound: len(songs),
+		SearchTime: 0,
+	})
+}
+
 // filterToLanguages keeps only songs whose Language matches the given preferences (case-insensitive).
 func filterToLanguages(songs []models.Song, preferences []string) []models.Song {
 	if len(preferences) == 0 || len(songs) == 0 {
@@ -302,29 +509,49 @@ func reorderByLanguage(songs []models.Song, preferences []string) []models.Song
 	return ordered
 }
 
-// ReindexAll reindexes all songs from database to Typesense
+// reindexJob is the job kind for full Typesense reindexes
+const reindexJob = "reindex"
+
+// startReindex starts a reindex job, or returns the running one with false
+func (h *Handler) startReindex() (*jobs.Job, bool) {
+	return h.jobs.Start(reindexJob, func(job *jobs.Job) error {
+		songs, err := h.db.GetAllSongs()
+		if err != nil {
+			return fmt.Errorf("failed to retrieve songs: %w", err)
+		}
+		return h.ts.Reindex(songs, job)
+	})
+}
+
+// ReindexAll starts a background reindex of all songs from database to
+// Typesense and returns its job ID; progress is at GET /admin/reindex/:id
 func (h *Handler) ReindexAll(c *fiber.Ctx) error {
 	if h.ts == nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Typesense is disabled"})
 	}
-	
-	songs, err := h.db.GetAllSongs()
-	if err != nil {
-		log.Printf("Error getting songs for reindex: %v", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve songs"})
-	}
 
-	if err := h.ts.ReindexAll(songs); err != nil {
-		log.Printf("Error reindexing: %v", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Reindex failed"})
+	job, started := h.startReindex()
+	status := job.Status()
+	if !started {
+		return c.Status(409).JSON(fiber.Map{"error": "A reindex is already running", "job_id": status.ID})
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "Reindex completed successfully",
-		"count":   len(songs),
+	return c.Status(202).JSON(fiber.Map{
+		"message": "Reindex started",
+		"job_id":  status.ID,
 	})
 }
 
+// GetReindexJob reports the progress of a reindex job
+func (h *Handler) GetReindexJob(c *fiber.Ctx) error {
+	status, ok := h.jobs.Get(c.Params("id"))
+	if !ok || status.Kind != reindexJob {
+		return c.Status(404).JSON(fiber.Map{"error": "Reindex job not found"})
+	}
+
+	return c.JSON(status)
+}
+
 // GetBackups lists all backups
 func (h *Handler) GetBackups(c *fiber.Ctx) error {
 	backups, err := h.backupManager.ListBackups()
@@ -403,6 +630,9 @@ func (h *Handler) ProPresenterLibrary(c *fiber.Ctx) error {
 	}
 
 	query := c.Query("q", "")
+	if c.Query("refresh") == "true" {
+		h.propresenter.InvalidateLibrary()
+	}
 	
 	var items []propresenter.LibraryItem
 	var err error
@@ -414,7 +644,7 @@ func (h *Handler) ProPresenterLibrary(c *fiber.Ctx) error {
 	}
 	
 	if err != nil {
-		log.Printf("Error fetching ProPresenter library: %v", err)
+		h.reportError(c, "Error fetching ProPresenter library", err)
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -432,7 +662,7 @@ func (h *Handler) ProPresenterPlaylists(c *fiber.Ctx) error {
 
 	playlists, err := h.propresenter.GetPlaylists()
 	if err != nil {
-		log.Printf("Error fetching ProPresenter playlists: %v", err)
+		h.reportError(c, "Error fetching ProPresenter playlists", err)
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -442,6 +672,30 @@ func (h *Handler) ProPresenterPlaylists(c *fiber.Ctx) error {
 	})
 }
 
+// ProPresenterPresentation returns a presentation's groups and slides with
+// their slide indices, and the song it belongs to if one is linked
+func (h *Handler) ProPresenterPresentation(c *fiber.Ctx) error {
+	if h.propresenter == nil || !h.propresenter.IsEnabled() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+
+	uuid := c.Params("uuid")
+	presentation, err := h.propresenter.GetPresentation(uuid)
+	if err != nil {
+		if err.Error() == "presentation not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Presentation not found in ProPresenter"})
+		}
+		h.reportError(c, "Error fetching ProPresenter presentation", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := fiber.Map{"presentation": presentation.Detail()}
+	if song, err := h.db.GetSongByProUUID(uuid); err == nil {
+		response["song_id"] = song.ID
+	}
+	return c.JSON(response)
+}
+
 // ProPresenterSendToQueue sends a song to the ProPresenter playlist using pro_uuid from database
 func (h *Handler) ProPresenterSendToQueue(c *fiber.Ctx) error {
 	if h.propresenter == nil || !h.propresenter.IsEnabled() {
@@ -459,6 +713,7 @@ func (h *Handler) ProPresenterSendToQueue(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 	}
+	pp, dry := h.proPresenterFor(c)
 
 	// Get song from database to retrieve pro_uuid
 	var song *models.Song
@@ -495,6 +750,11 @@ func (h *Handler) ProPresenterSendToQueue(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve settings"})
 	}
 
+	// Rehearsal mode never touches the Live Queue
+	if h.live.IsRehearsal() {
+		return h.sendToRehearsalPlaylist(c, pp, dry, song, settings)
+	}
+
 	// Use ProPresenter playlist UUID from settings, fallback to live_playlist_uuid
 	playlistUUID := settings.ProPresenterPlaylistUUID
 	if playlistUUID == "" || playlistUUID == "00000000-0000-0000-0000-000000000000" {
@@ -511,16 +771,18 @@ func (h *Handler) ProPresenterSendToQueue(c *fiber.Ctx) error {
 
 	// If playlist UUID is default/empty, try to find playlist by name
 	if (playlistUUID == "" || playlistUUID == "00000000-0000-0000-0000-000000000000") && playlistName != "" {
-		playlists, err := h.propresenter.GetPlaylists()
+		playlists, err := pp.GetPlaylists()
 		if err == nil {
 			for _, pl := range playlists {
 				if strings.EqualFold(pl.ID.Name, playlistName) {
 					playlistUUID = pl.ID.UUID
 					// Update settings with the found UUID
-					updates := models.UpdateSettingsRequest{
-						ProPresenterPlaylistUUID: &pl.ID.UUID,
+					if dry == nil {
+						updates := models.UpdateSettingsRequest{
+							ProPresenterPlaylistUUID: &pl.ID.UUID,
+						}
+						h.db.UpdateSettings(&updates)
 					}
-					h.db.UpdateSettings(&updates)
 					break
 				}
 			}
@@ -528,9 +790,10 @@ func (h *Handler) ProPresenterSendToQueue(c *fiber.Ctx) error {
 	}
 
 	// Add song to playlist using pro_uuid
-	err = h.propresenter.AddToPlaylist(playlistUUID, *song.ProUUID)
+	err = pp.AddToPlaylist(playlistUUID, *song.ProUUID)
 	if err != nil {
-		log.Printf("Error adding song to ProPresenter playlist: %v", err)
+		h.reportError(c, "Error adding song to ProPresenter playlist", err)
+		h.recordServiceEvent(c, models.ServiceEventError, song.ID, song.Title, "add to playlist failed: "+err.Error())
 		return c.Status(503).JSON(fiber.Map{
 			"error":      "Failed to sync with ProPresenter",
 			"message":    err.Error(),
@@ -547,13 +810,18 @@ func (h *Handler) ProPresenterSendToQueue(c *fiber.Ctx) error {
 		log.Printf("Theme application requested: %s (feature pending ProPresenter theme API integration)", req.ThemeName)
 	}
 
-	return c.JSON(fiber.Map{
+	response := fiber.Map{
 		"success":      true,
 		"message":      "Song added to ProPresenter playlist",
 		"song_title":   song.Title,
 		"playlist":     playlistName,
 		"pp_item_uuid": uuid,
-	})
+	}
+	if dry != nil {
+		response["message"] = "Dry run: song would be added to ProPresenter playlist"
+		return dryRunResponse(c, dry, response)
+	}
+	return c.JSON(response)
 }
 
 // ProPresenterTrigger triggers a library item in ProPresenter
@@ -570,12 +838,13 @@ func (h *Handler) ProPresenterTrigger(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 	}
+	pp, dry := h.proPresenterFor(c)
 
 	uuid := req.UUID
 	
 	// If no UUID, try to find by title
 	if uuid == "" && req.SongTitle != "" {
-		item, err := h.propresenter.FindSongByTitle(req.SongTitle)
+		item, err := pp.FindSongByTitle(req.SongTitle)
 		if err != nil {
 			return c.Status(404).JSON(fiber.Map{"error": "Song not found in ProPresenter library"})
 		}
@@ -586,15 +855,57 @@ func (h *Handler) ProPresenterTrigger(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "uuid or song_title is required"})
 	}
 
-	if err := h.propresenter.TriggerLibraryItem(uuid); err != nil {
-		log.Printf("Error triggering ProPresenter item: %v", err)
+	if err := pp.TriggerLibraryItem(uuid); err != nil {
+		h.reportError(c, "Error triggering ProPresenter item", err)
+		h.recordServiceEvent(c, models.ServiceEventError, "", req.SongTitle, "trigger failed: "+err.Error())
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	// A dry run leaves displays, usage and the service report alone
+	if dry != nil {
+		return dryRunResponse(c, dry, fiber.Map{
+			"success": true,
+			"message": "Dry run: song would be triggered in ProPresenter",
+			"uuid":    uuid,
+		})
+	}
+
+	// Tell displays what is now on screen; a timed run of the previous item ends
+	h.advance.Stop()
+	nowShowing := live.NowShowing{Title: req.SongTitle, PresentationUUID: uuid}
+	song, err := h.db.GetSongByProUUID(uuid)
+	if err == nil {
+		nowShowing.SongID = song.ID
+		nowShowing.Title = song.Title
+	}
+	h.live.SetCurrent(nowShowing)
+	var background fiber.Map
+	if song != nil {
+		h.publishTempo(song)
+		background = h.applySongBackground(c, song)
+	}
+	h.publishStageNotes(nowShowing.SongID)
+	h.publishStageCues(nowShowing.SongID)
+	h.recordServiceEvent(c, models.ServiceEventTrigger, nowShowing.SongID, nowShowing.Title, "")
+
+	// Rehearsals never count towards usage stats, CCLI reporting or song requests
+	if nowShowing.SongID != "" && !h.live.IsRehearsal() {
+		if err := h.db.RecordSongUsage(nowShowing.SongID); err != nil {
+			log.Printf("Error recording song usage: %v", err)
+		}
+		if err := h.db.RecordCCLIUsage(song); err != nil {
+			log.Printf("Error recording CCLI usage: %v", err)
+		}
+		if err := h.db.MarkSongRequestsSung(nowShowing.SongID); err != nil {
+			log.Printf("Error updating song requests: %v", err)
+		}
+	}
+
 	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Song triggered in ProPresenter",
-		"uuid":    uuid,
+		"success":    true,
+		"message":    "Song triggered in ProPresenter",
+		"uuid":       uuid,
+		"background": background,
 	})
 }
 
@@ -604,9 +915,16 @@ func (h *Handler) ProPresenterNextSlide(c *fiber.Ctx) error {
 		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
 	}
 
-	if err := h.propresenter.TriggerNextSlide(); err != nil {
+	pp, dry := h.proPresenterFor(c)
+	if err := pp.TriggerNextSlide(); err != nil {
+		h.recordServiceEvent(c, models.ServiceEventError, "", "", "next slide failed: "+err.Error())
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
+	if dry != nil {
+		return dryRunResponse(c, dry, fiber.Map{"success": true, "message": "Dry run: would advance to next slide"})
+	}
+	h.live.AdvanceSlide(1)
+	h.advance.Moved(1)
 
 	return c.JSON(fiber.Map{"success": true, "message": "Advanced to next slide"})
 }
@@ -617,13 +935,83 @@ func (h *Handler) ProPresenterPreviousSlide(c *fiber.Ctx) error {
 		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
 	}
 
-	if err := h.propresenter.TriggerPreviousSlide(); err != nil {
+	pp, dry := h.proPresenterFor(c)
+	if err := pp.TriggerPreviousSlide(); err != nil {
+		h.recordServiceEvent(c, models.ServiceEventError, "", "", "previous slide failed: "+err.Error())
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
+	if dry != nil {
+		return dryRunResponse(c, dry, fiber.Map{"success": true, "message": "Dry run: would go to previous slide"})
+	}
+	h.live.AdvanceSlide(-1)
+	h.advance.Moved(-1)
 
 	return c.JSON(fiber.Map{"success": true, "message": "Went to previous slide"})
 }
 
+// ProPresenterTriggerGroup jumps to a section of a presentation by group name
+// ("Chorus", "Bridge"), triggering the group's first slide
+func (h *Handler) ProPresenterTriggerGroup(c *fiber.Ctx) error {
+	if h.propresenter == nil || !h.propresenter.IsEnabled() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+
+	var req struct {
+		UUID  string `json:"uuid"`
+		Group string `json:"group"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.UUID = strings.TrimSpace(req.UUID)
+	req.Group = strings.TrimSpace(req.Group)
+	if req.UUID == "" || req.Group == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "uuid and group are required"})
+	}
+
+	pp, dry := h.proPresenterFor(c)
+	index, group, err := pp.TriggerGroup(req.UUID, req.Group)
+	if err != nil {
+		if err.Error() == "presentation not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Presentation not found in ProPresenter"})
+		}
+		if err.Error() == "slide group not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Presentation has no group named " + req.Group})
+		}
+		h.reportError(c, "Error triggering ProPresenter slide group", err)
+		h.recordServiceEvent(c, models.ServiceEventError, "", "", "trigger group failed: "+err.Error())
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if dry != nil {
+		return dryRunResponse(c, dry, fiber.Map{"success": true, "group": group.Name, "slide_index": index})
+	}
+
+	// Within the presentation on screen this is a slide move; otherwise the
+	// presentation itself went live
+	current := h.live.Current()
+	if current != nil && current.PresentationUUID == req.UUID {
+		h.advance.Moved(index - current.SlideIndex)
+		h.live.GoToSlide(index)
+	} else {
+		h.advance.Stop()
+		nowShowing := live.NowShowing{PresentationUUID: req.UUID, SlideIndex: index}
+		if song, err := h.db.GetSongByProUUID(req.UUID); err == nil {
+			nowShowing.SongID = song.ID
+			nowShowing.Title = song.Title
+		}
+		h.live.SetCurrent(nowShowing)
+		h.publishStageNotes(nowShowing.SongID)
+		h.publishStageCues(nowShowing.SongID)
+	}
+
+	return c.JSON(fiber.Map{
+		"success":     true,
+		"group":       group.Name,
+		"slide_index": index,
+	})
+}
+
 // ProPresenterClear clears a layer in ProPresenter
 func (h *Handler) ProPresenterClear(c *fiber.Ctx) error {
 	if h.propresenter == nil || !h.propresenter.IsEnabled() {
@@ -632,10 +1020,15 @@ func (h *Handler) ProPresenterClear(c *fiber.Ctx) error {
 
 	layer := c.Query("layer", "slide")
 	
-	if err := h.propresenter.ClearLayer(layer); err != nil {
+	pp, dry := h.proPresenterFor(c)
+	if err := pp.ClearLayer(layer); err != nil {
+		h.recordServiceEvent(c, models.ServiceEventError, "", "", "clear "+layer+" failed: "+err.Error())
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	if dry != nil {
+		return dryRunResponse(c, dry, fiber.Map{"success": true, "message": "Dry run: layer would be cleared", "layer": layer})
+	}
 	return c.JSON(fiber.Map{"success": true, "message": "Layer cleared", "layer": layer})
 }
 
@@ -658,6 +1051,12 @@ func (h *Handler) UpdateSettings(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 	}
+	if err := validateTokenizationUpdate(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := validateHealthIntervalUpdate(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
 
 	settings, err := h.db.UpdateSettings(&req)
 	if err != nil {
@@ -690,6 +1089,14 @@ func (h *Handler) UpdateSettings(c *fiber.Ctx) error {
 			// Disable if settings are empty
 			h.propresenter.Reconfigure(nil)
 		}
+		h.propresenter.SetHealthCheckInterval(HealthCheckInterval(settings))
+	}
+
+	// Collections keep the tokenization they were created with
+	if req.SearchFieldLocales != nil || req.SearchTokenSeparators != nil {
+		if h.setTokenization(settings) {
+			h.reindexForTokenization()
+		}
 	}
 
 	return c.JSON(settings)
@@ -705,6 +1112,19 @@ func (h *Handler) GetQueue(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve queue"})
 	}
 
+	// Band cues, so the queue view shows how each song starts and ends
+	songIDs := make([]string, len(items))
+	for i, item := range items {
+		songIDs[i] = item.SongID
+	}
+	if cues, err := h.db.GetCuesForSongs(songIDs); err == nil {
+		for i := range items {
+			items[i].Cues = cues[items[i].SongID]
+		}
+	} else {
+		log.Printf("Error getting queue cues: %v", err)
+	}
+
 	return c.JSON(items)
 }
 
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// readyTimeout bounds each dependency check so a hung dependency makes the
+// instance unready rather than hanging the probe
+const readyTimeout = 2 * time.Second
+
+// Healthz is the liveness probe: it answers as long as the process is serving
+// requests and never touches dependencies, so an outage of Postgres or
+// Typesense doesn't get the container restarted
+func (h *Handler) Healthz(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "alive"})
+}
+
+// Readyz is the readiness probe: 200 only when the database is reachable and
+// migrated and the configuration is usable; 503 with the failing checks
+// otherwise. Typesense is reported but doesn't gate readiness.
+func (h *Handler) Readyz(c *fiber.Ctx) error {
+	checks := fiber.Map{}
+	ready := true
+
+	record := func(name string, err error) {
+		if err != nil {
+			ready = false
+			checks[name] = fiber.Map{"ok": false, "error": err.Error()}
+			return
+		}
+		checks[name] = fiber.Map{"ok": true}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), readyTimeout)
+	record("database", h.db.CheckReady(ctx))
+	cancel()
+
+	// Search falls back to PostgreSQL while Typesense is down, so an
+	// unavailable Typesense is reported as degraded rather than unready
+	if h.ts != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), readyTimeout)
+		if err := h.ts.CheckReady(ctx); err != nil {
+			checks["typesense"] = fiber.Map{"ok": false, "degraded": true, "error": err.Error()}
+		} else {
+			checks["typesense"] = fiber.Map{"ok": true}
+		}
+		cancel()
+	} else {
+		checks["typesense"] = fiber.Map{"ok": true, "disabled": true}
+	}
+
+	record("config", h.checkConfig())
+
+	status := "ready"
+	code := 200
+	if !ready {
+		status = "not_ready"
+		code = 503
+	}
+	return c.Status(code).JSON(fiber.Map{"status": status, "checks": checks})
+}
+
+// checkConfig reports the first configuration problem that would make
+// features fail at request time
+func (h *Handler) checkConfig() error {
+	if err := h.backupManager.CheckReady(); err != nil {
+		return err
+	}
+	if _, err := basePPTXTemplate(); err != nil {
+		return err
+	}
+	if _, errs := loadPDFFonts(); len(errs) > 0 {
+		return fmt.Errorf("PDF_FONTS: %w", errs[0])
+	}
+	return nil
+}
+
+// GetMigrations lists the schema migrations and which the database has applied
+func (h *Handler) GetMigrations(c *fiber.Ctx) error {
+	status, err := h.db.MigrationStatus(context.Background())
+	if err != nil {
+		h.reportError(c, "Error reading migration status", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to read migration status"})
+	}
+	return c.JSON(status)
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/importer"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// ImportFailure describes a file that could not be imported
+type ImportFailure struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// Import formats accepted by ParseImportFiles
+const (
+	ImportOpenSong    = "opensong"
+	ImportEasyWorship = "easyworship"
+	ImportVideoPsalm  = "videopsalm"
+)
+
+// ImportOpenSong imports OpenSong song files uploaded as multipart "files"
+// (a whole directory and/or zip archives)
+func (h *Handler) ImportOpenSong(c *fiber.Ctx) error {
+	return h.importUpload(c, ImportOpenSong, c.FormValue("language", "english"), c.FormValue("library", "OpenSong"))
+}
+
+// ImportEasyWorship imports songs from EasyWorship databases uploaded as
+// Songs.db + SongWords.db (EasyWorship 6/7), an Access .mdb, or a zip of either.
+// The language defaults to per-song detection.
+func (h *Handler) ImportEasyWorship(c *fiber.Ctx) error {
+	return h.importUpload(c, ImportEasyWorship, c.FormValue("language", "auto"), c.FormValue("library", "EasyWorship"))
+}
+
+// ImportVideoPsalm imports VideoPsalm songbooks uploaded as .json or .vpc bundles.
+// The language defaults to per-song detection since bundles often mix languages.
+func (h *Handler) ImportVideoPsalm(c *fiber.Ctx) error {
+	library := c.FormValue("library", "") // defaults to the songbook name
+	return h.importUpload(c, ImportVideoPsalm, c.FormValue("language", "auto"), library)
+}
+
+// importUpload parses the uploaded files in one format and imports them
+func (h *Handler) importUpload(c *fiber.Ctx, format, language, library string) error {
+	files, err := readUploadedFiles(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	songs, failures, err := ParseImportFiles(format, files, language, library)
+	if err != nil {
+		log.Printf("Error reading %s import: %v", format, err)
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return h.importSongs(c, songs, failures)
+}
+
+// ParseImportFiles parses files exported from other worship software. Files
+// that can't be read are returned as failures; an error means the import as
+// a whole can't be read (such as an EasyWorship upload missing its database).
+func ParseImportFiles(format string, files []importer.File, language, library string) ([]importer.Song, []ImportFailure, error) {
+	var songs []importer.Song
+	var failures []ImportFailure
+
+	switch format {
+	case ImportOpenSong:
+		for _, f := range files {
+			if !importer.IsOpenSong(f.Data) {
+				failures = append(failures, ImportFailure{File: f.Name, Error: "not an OpenSong song file"})
+				continue
+			}
+			song, err := importer.ParseOpenSong(f, language, library)
+			if err != nil {
+				failures = append(failures, ImportFailure{File: f.Name, Error: err.Error()})
+				continue
+			}
+			songs = append(songs, *song)
+		}
+	case ImportEasyWorship:
+		parsed, err := importer.ParseEasyWorship(files, language, library)
+		if err != nil {
+			return nil, nil, err
+		}
+		songs = parsed
+	case ImportVideoPsalm:
+		for _, f := range files {
+			ext := strings.ToLower(path.Ext(f.Name))
+			if ext != ".json" && ext != ".vpc" {
+				failures = append(failures, ImportFailure{File: f.Name, Error: "not a VideoPsalm .json or .vpc file"})
+				continue
+			}
+			parsed, err := importer.ParseVideoPsalm(f, language, library)
+			if err != nil {
+				failures = append(failures, ImportFailure{File: f.Name, Error: err.Error()})
+				continue
+			}
+			songs = append(songs, parsed...)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown import format %s", format)
+	}
+
+	return songs, failures, nil
+}
+
+// readUploadedFiles reads every multipart file (fields "files" and "file") and expands zips
+func readUploadedFiles(c *fiber.Ctx) ([]importer.File, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, fmt.Errorf("expected a multipart upload with one or more files")
+	}
+
+	var headers []*multipart.FileHeader
+	headers = append(headers, form.File["files"]...)
+	headers = append(headers, form.File["file"]...)
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("no files uploaded")
+	}
+
+	files := make([]importer.File, 0, len(headers))
+	for _, fh := range headers {
+		f, err := fh.Open()
+		if err != nil {
+			return nil, fmt.Errorf("error opening %s: %w", fh.Filename, err)
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", fh.Filename, err)
+		}
+		files = append(files, importer.File{Name: fh.Filename, Data: data})
+	}
+
+	return importer.ExpandZips(files)
+}
+
+// ImportResult reports what an import created, skipped and couldn't read
+type ImportResult struct {
+	DryRun         bool            `json:"dry_run"`
+	Imported       []fiber.Map     `json:"imported"`
+	Skipped        []fiber.Map     `json:"skipped"`
+	Failed         []ImportFailure `json:"failed"`
+	LanguageReview []fiber.Map     `json:"language_review"`
+}
+
+// importSongs creates parsed songs, skipping any whose title already exists in
+// the same language. With dry_run=true nothing is written.
+func (h *Handler) importSongs(c *fiber.Ctx, songs []importer.Song, failures []ImportFailure) error {
+	result, err := h.ImportSongs(songs, failures, c.FormValue("dry_run") == "true")
+	if err != nil {
+		log.Printf("Error loading songs for import: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load existing songs"})
+	}
+	return c.JSON(result)
+}
+
+// ImportSongs creates parsed songs, skipping any whose title already exists in
+// the same language, and indexes them. With dryRun nothing is written.
+func (h *Handler) ImportSongs(songs []importer.Song, failures []ImportFailure, dryRun bool) (*ImportResult, error) {
+	existing, err := h.db.GetAllSongs()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, s := range existing {
+		seen[importKey(s.Title, s.Language)] = true
+	}
+
+	result := &ImportResult{
+		DryRun:         dryRun,
+		Imported:       make([]fiber.Map, 0, len(songs)),
+		Skipped:        make([]fiber.Map, 0),
+		Failed:         failures,
+		LanguageReview: make([]fiber.Map, 0),
+	}
+	if result.Failed == nil {
+		result.Failed = make([]ImportFailure, 0)
+	}
+
+	created := make([]models.Song, 0, len(songs))
+	for _, s := range songs {
+		normalizeImportedSong(&s.Request)
+		if warning := detectSongLanguage(&s.Request); warning != "" {
+			result.LanguageReview = append(result.LanguageReview, fiber.Map{"file": s.Source, "title": s.Request.Title, "language": s.Request.Language, "warning": warning})
+		}
+		key := importKey(s.Request.Title, s.Request.Language)
+		if seen[key] {
+			result.Skipped = append(result.Skipped, fiber.Map{"file": s.Source, "title": s.Request.Title, "reason": "a song with this title already exists"})
+			continue
+		}
+		seen[key] = true
+
+		if dryRun {
+			result.Imported = append(result.Imported, fiber.Map{"file": s.Source, "title": s.Request.Title})
+			continue
+		}
+
+		song, err := h.db.CreateSong(&s.Request)
+		if err != nil {
+			log.Printf("Error importing song %q: %v", s.Request.Title, err)
+			result.Failed = append(result.Failed, ImportFailure{File: s.Source, Error: "failed to save song"})
+			continue
+		}
+		created = append(created, *song)
+		h.refreshRomanized(song)
+		result.Imported = append(result.Imported, fiber.Map{"file": s.Source, "title": song.Title, "id": song.ID})
+	}
+
+	if len(created) > 0 && !h.skipTypesense && h.ts != nil {
+		if err := h.ts.IndexSongs(created); err != nil {
+			h.reportError(nil, "Error indexing imported songs in Typesense", err)
+		}
+	}
+
+	if !dryRun && len(result.Imported) > 0 {
+		log.Printf("Imported %d songs (%d skipped, %d failed)", len(result.Imported), len(result.Skipped), len(result.Failed))
+		h.backupManager.RecordEdits(len(result.Imported))
+	}
+
+	return result, nil
+}
+
+func importKey(title, language string) string {
+	return strings.ToLower(strings.TrimSpace(title)) + "|" + strings.ToLower(language)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/language"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// detectSongLanguage fills in the language of req when it is missing or
+// "auto", and otherwise checks the declared language against the lyrics. It
+// returns a review warning when the two confidently disagree.
+func detectSongLanguage(req *models.CreateSongRequest) string {
+	text := req.Title + "\n" + req.DisplayLyrics
+	if req.Language == "" || req.Language == "auto" {
+		req.Language = language.Detect(text)
+		return ""
+	}
+	if guess, mismatch := language.Mismatch(req.Language, text); mismatch {
+		return fmt.Sprintf("lyrics look %s, not %s", guess.Language, req.Language)
+	}
+	return ""
+}
+
+// GetLanguageReview lists songs whose lyrics confidently look like a
+// different language than the one they are filed under
+func (h *Handler) GetLanguageReview(c *fiber.Ctx) error {
+	flagged := make([]fiber.Map, 0)
+	err := h.db.EachSong(func(song *models.Song) error {
+		guess, mismatch := language.Mismatch(song.Language, song.Title+"\n"+song.DisplayLyrics)
+		if mismatch {
+			flagged = append(flagged, fiber.Map{
+				"id":         song.ID,
+				"title":      song.Title,
+				"library":    song.Library,
+				"language":   song.Language,
+				"detected":   guess.Language,
+				"confidence": guess.Confidence,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error scanning songs for language review: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to scan songs"})
+	}
+
+	return c.JSON(fiber.Map{"songs": flagged, "count": len(flagged)})
+}
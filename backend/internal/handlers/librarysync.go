@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// The library sync links songs to their ProPresenter presentations, so
+// sending a song to ProPresenter goes by pro_uuid instead of searching the
+// library for its title. Each run pulls the whole library: songs whose
+// pro_uuid is still in it stay linked, and the remaining items are matched to
+// unlinked songs by title. A title is only linked when exactly one item and
+// one song share it; anything else is left for an operator, listed in the
+// report along with the items no song matched.
+
+// Reasons a library item was left unmatched
+const (
+	syncNoSong     = "no_song"        // no unlinked song has the item's title
+	syncAmbiguous  = "ambiguous"      // several songs or items share the title
+	syncLinkFailed = "link_failed"    // storing the link failed
+	syncItemGone   = "item_not_found" // a song's pro_uuid is no longer in the library
+)
+
+// LibrarySyncLink is a song linked to a library item by the last sync
+type LibrarySyncLink struct {
+	SongID  string `json:"song_id"`
+	Title   string `json:"title"`
+	ProUUID string `json:"pro_uuid"`
+}
+
+// LibrarySyncItem is a library item the last sync couldn't link
+type LibrarySyncItem struct {
+	UUID   string   `json:"uuid"`
+	Name   string   `json:"name"`
+	Reason string   `json:"reason"`
+	Songs  []string `json:"song_ids,omitempty"` // the songs sharing the title, when ambiguous
+	Error  string   `json:"error,omitempty"`
+}
+
+// LibrarySyncSong is a linked song whose library item has gone
+type LibrarySyncSong struct {
+	SongID  string `json:"song_id"`
+	Title   string `json:"title"`
+	ProUUID string `json:"pro_uuid"`
+	Reason  string `json:"reason"`
+}
+
+// LibrarySyncReport is the result of the last library sync
+type LibrarySyncReport struct {
+	Enabled       bool              `json:"enabled"`
+	Interval      int               `json:"interval_seconds,omitempty"`
+	SyncedAt      *time.Time        `json:"synced_at,omitempty"`
+	Items         int               `json:"items"`          // library items
+	AlreadyLinked int               `json:"already_linked"` // items a song was linked to before the sync
+	Linked        []LibrarySyncLink `json:"linked"`         // songs linked by the sync
+	Unmatched     []LibrarySyncItem `json:"unmatched"`      // items no song is linked to
+	Missing       []LibrarySyncSong `json:"missing"`        // songs linked to an item no longer in the library
+	Unlinked      int               `json:"unlinked_songs"` // songs with no library item
+	Error         string            `json:"error,omitempty"`
+}
+
+// librarySyncFirstRun is how long after startup the first sync runs, giving
+// the health check time to find ProPresenter
+const librarySyncFirstRun = time.Minute
+
+// librarySync is the last report, and keeps two syncs from running at once
+type librarySync struct {
+	mu     sync.Mutex
+	report LibrarySyncReport
+}
+
+// StartLibrarySync links songs to the ProPresenter library every interval,
+// the first time shortly after startup
+func (h *Handler) StartLibrarySync(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	h.librarySync.mu.Lock()
+	h.librarySync.report.Enabled = true
+	h.librarySync.report.Interval = int(interval / time.Second)
+	h.librarySync.mu.Unlock()
+
+	go func() {
+		time.Sleep(librarySyncFirstRun)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := h.syncLibrary(); err != nil {
+				log.Printf("Error syncing the ProPresenter library: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// syncLibrary pulls the library and links songs to its items. A disabled or
+// disconnected ProPresenter skips the run.
+func (h *Handler) syncLibrary() (LibrarySyncReport, error) {
+	h.librarySync.mu.Lock()
+	defer h.librarySync.mu.Unlock()
+
+	if h.propresenter == nil || !h.propresenter.IsEnabled() || !h.propresenter.IsConnected() {
+		return h.librarySync.report, nil
+	}
+
+	report := LibrarySyncReport{
+		Enabled:   h.librarySync.report.Enabled,
+		Interval:  h.librarySync.report.Interval,
+		Linked:    make([]LibrarySyncLink, 0),
+		Unmatched: make([]LibrarySyncItem, 0),
+		Missing:   make([]LibrarySyncSong, 0),
+	}
+	now := time.Now()
+	report.SyncedAt = &now
+
+	fail := func(err error) (LibrarySyncReport, error) {
+		report.Error = err.Error()
+		h.librarySync.report = report
+		return report, err
+	}
+
+	// Items created or renamed since the cache was filled count too
+	h.propresenter.InvalidateLibrary()
+	items, err := h.propresenter.GetLibrary()
+	if err != nil {
+		return fail(err)
+	}
+	report.Items = len(items)
+
+	inLibrary := make(map[string]bool, len(items))
+	for _, item := range items {
+		inLibrary[strings.ToLower(item.ID.UUID)] = true
+	}
+
+	// Songs by title, leaving out those still linked to an item
+	claimed := make(map[string]bool)
+	unlinked := make(map[string][]LibrarySyncSong)
+	var missing []LibrarySyncSong
+	err = h.db.EachSong(func(song *models.Song) error {
+		entry := LibrarySyncSong{SongID: song.ID, Title: song.Title}
+		if song.ProUUID != nil && *song.ProUUID != "" {
+			uuid := strings.ToLower(*song.ProUUID)
+			if inLibrary[uuid] {
+				claimed[uuid] = true
+				return nil
+			}
+			entry.ProUUID, entry.Reason = *song.ProUUID, syncItemGone
+			missing = append(missing, entry)
+		}
+		title := normalizeLyricLine(song.Title)
+		unlinked[title] = append(unlinked[title], entry)
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+	report.AlreadyLinked = len(claimed)
+
+	// Library items by title, leaving out those a song is linked to
+	byTitle := make(map[string][]int)
+	for i, item := range items {
+		if claimed[strings.ToLower(item.ID.UUID)] {
+			continue
+		}
+		title := normalizeLyricLine(item.ID.Name)
+		byTitle[title] = append(byTitle[title], i)
+	}
+
+	linkedSongs := make(map[string]bool)
+	for i, item := range items {
+		if claimed[strings.ToLower(item.ID.UUID)] {
+			continue
+		}
+		unmatched := LibrarySyncItem{UUID: item.ID.UUID, Name: item.ID.Name}
+		title := normalizeLyricLine(item.ID.Name)
+		songs := unlinked[title]
+		switch {
+		case title == "" || len(songs) == 0:
+			unmatched.Reason = syncNoSong
+		case len(songs) > 1 || len(byTitle[title]) > 1:
+			unmatched.Reason = syncAmbiguous
+			for _, song := range songs {
+				unmatched.Songs = append(unmatched.Songs, song.SongID)
+			}
+		default:
+			song := songs[0]
+			uuid := items[i].ID.UUID
+			if err := h.db.SetSongProUUID(song.SongID, &uuid); err != nil {
+				unmatched.Reason, unmatched.Error = syncLinkFailed, err.Error()
+				break
+			}
+			linkedSongs[song.SongID] = true
+			report.Linked = append(report.Linked, LibrarySyncLink{SongID: song.SongID, Title: song.Title, ProUUID: uuid})
+			continue
+		}
+		report.Unmatched = append(report.Unmatched, unmatched)
+	}
+
+	for _, song := range missing {
+		if !linkedSongs[song.SongID] {
+			report.Missing = append(report.Missing, song)
+		}
+	}
+	for _, songs := range unlinked {
+		for _, song := range songs {
+			if !linkedSongs[song.SongID] && song.ProUUID == "" {
+				report.Unlinked++
+			}
+		}
+	}
+
+	if len(report.Linked) > 0 {
+		log.Printf("🔗 Linked %d songs to the ProPresenter library (%d items unmatched)", len(report.Linked), len(report.Unmatched))
+	}
+
+	h.librarySync.report = report
+	return report, nil
+}
+
+// GetLibrarySyncReport returns the result of the last library sync: the
+// songs it linked, library items no song matched and songs whose item is gone
+func (h *Handler) GetLibrarySyncReport(c *fiber.Ctx) error {
+	h.librarySync.mu.Lock()
+	defer h.librarySync.mu.Unlock()
+	return c.JSON(h.librarySync.report)
+}
+
+// SyncLibrary links songs to the ProPresenter library now
+func (h *Handler) SyncLibrary(c *fiber.Ctx) error {
+	if h.propresenter == nil || !h.propresenter.IsEnabled() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+	if !h.propresenter.IsConnected() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter is not connected"})
+	}
+
+	report, err := h.syncLibrary()
+	if err != nil {
+		h.reportError(c, "Error syncing the ProPresenter library", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error(), "report": report})
+	}
+	return c.JSON(report)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/links"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const maxSongLinks = 20
+
+// normalizeSongLinks validates links and drops duplicates, keeping only the
+// kind and URL; metadata is filled in by fillLinkMetadata
+func normalizeSongLinks(in []models.SongLink) ([]models.SongLink, error) {
+	if len(in) > maxSongLinks {
+		return nil, errors.New("a song can have at most 20 links")
+	}
+
+	out := make([]models.SongLink, 0, len(in))
+	seen := make(map[string]bool)
+	for _, l := range in {
+		kind, u, err := links.Normalize(l.Kind, l.URL)
+		if err != nil {
+			return nil, err
+		}
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		out = append(out, models.SongLink{Kind: kind, URL: u})
+	}
+	return out, nil
+}
+
+// fillLinkMetadata copies metadata from a song's saved links for URLs that
+// haven't changed and fetches it for the rest. Fetch failures leave the
+// metadata empty; the link is still saved.
+func (h *Handler) fillLinkMetadata(songID string, in []models.SongLink, refresh bool) []models.SongLink {
+	saved := make(map[string]models.SongLink)
+	if songID != "" && !refresh {
+		existing, err := h.db.GetSongLinks(songID)
+		if err != nil {
+			log.Printf("Error getting song links: %v", err)
+		}
+		for _, l := range existing {
+			saved[l.URL] = l
+		}
+	}
+
+	out := make([]models.SongLink, len(in))
+	for i, l := range in {
+		if prev, ok := saved[l.URL]; ok && prev.FetchedAt != nil {
+			out[i] = prev
+			continue
+		}
+		out[i] = l
+
+		meta, err := h.oembed.Fetch(l.Kind, l.URL)
+		if err != nil {
+			if err != links.ErrNoOEmbed {
+				log.Printf("Error fetching oEmbed for %s: %v", l.URL, err)
+			}
+			continue
+		}
+		now := time.Now()
+		out[i].Title = nonEmpty(meta.Title)
+		out[i].AuthorName = nonEmpty(meta.AuthorName)
+		out[i].ThumbnailURL = nonEmpty(meta.ThumbnailURL)
+		out[i].EmbedHTML = nonEmpty(meta.HTML)
+		out[i].FetchedAt = &now
+	}
+	return out
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// PreviewLink validates a link and returns its oEmbed metadata without saving
+// anything, so the planning UI can show it while a song is being edited
+func (h *Handler) PreviewLink(c *fiber.Ctx) error {
+	kind, u, err := links.Normalize(c.Query("kind"), c.Query("url"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := fiber.Map{"kind": kind, "url": u}
+	meta, err := h.oembed.Fetch(kind, u)
+	if err != nil && err != links.ErrNoOEmbed {
+		log.Printf("Error fetching oEmbed for %s: %v", u, err)
+		return c.Status(502).JSON(fiber.Map{"error": "Failed to fetch link metadata", "kind": kind, "url": u})
+	}
+	if meta != nil {
+		response["metadata"] = meta
+	}
+	return c.JSON(response)
+}
+
+// RefreshSongLinks fetches the metadata of every link on a song again
+func (h *Handler) RefreshSongLinks(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	current, err := h.db.GetSongLinks(id)
+	if err != nil {
+		log.Printf("Error getting song links: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get song links"})
+	}
+
+	refreshed := h.fillLinkMetadata(id, current, true)
+	if err := h.db.SetSongLinks(id, refreshed); err != nil {
+		log.Printf("Error saving song links: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save song links"})
+	}
+
+	return c.JSON(fiber.Map{"links": refreshed})
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// songListFields are the song fields a listing can be narrowed to
+var songListFields = map[string]bool{
+	"id": true, "title": true, "file_name": true, "library": true, "language": true, "pro_uuid": true,
+	"display_lyrics": true, "music_ministry_lyrics": true, "artist": true,
+	"original_key": true, "performance_key": true, "bpm": true, "time_signature": true, "count_in_beats": true,
+	"background_media": true, "look": true, "copyright": true, "ccli_number": true, "chords": true, "public": true,
+	"archived_at": true, "created_at": true, "updated_at": true,
+}
+
+// Songs per page of a paginated listing
+const (
+	defaultSongsPerPage = 50
+	maxSongsPerPage     = 500
+)
+
+// parseSongFilter reads GET /api/songs query parameters: language (one or
+// more, comma-separated), library (or tag), updated_since (RFC 3339 or YYYY-MM-DD),
+// archived (true/false), sort (a field, "-" first for descending), order
+// (asc/desc, overriding the "-") and page and per_page, which default to
+// the first page of defaultSongsPerPage songs. all=true asks for every song
+// instead, and is the only way to get them in one response; it reports
+// false then.
+func parseSongFilter(c *fiber.Ctx) (*models.SongFilter, bool, error) {
+	filter := &models.SongFilter{Library: strings.TrimSpace(c.Query("library"))}
+	if filter.Library == "" {
+		filter.Library = strings.TrimSpace(c.Query("tag")) // songs are tagged by library
+	}
+
+	for _, lang := range strings.Split(c.Query("language"), ",") {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			filter.Languages = append(filter.Languages, lang)
+		}
+	}
+
+	if since := c.Query("updated_since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", since); err != nil {
+				return nil, false, fmt.Errorf("updated_since must be a date (YYYY-MM-DD) or RFC 3339 time")
+			}
+		}
+		filter.UpdatedSince = &t
+	}
+
+	switch c.Query("archived") {
+	case "":
+	case "true":
+		archived := true
+		filter.Archived = &archived
+	case "false":
+		archived := false
+		filter.Archived = &archived
+	default:
+		return nil, false, fmt.Errorf("archived must be true or false")
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		filter.Descending = strings.HasPrefix(sort, "-")
+		filter.Sort = strings.TrimPrefix(sort, "-")
+		if !models.SongSortFields[filter.Sort] {
+			return nil, false, fmt.Errorf("sort must be one of title, artist, language, library, created_at or updated_at")
+		}
+	}
+
+	switch order := strings.ToLower(c.Query("order")); order {
+	case "":
+	case "asc", "desc":
+		if filter.Sort == "" {
+			filter.Sort = "updated_at"
+		}
+		filter.Descending = order == "desc"
+	default:
+		return nil, false, fmt.Errorf("order must be asc or desc")
+	}
+
+	switch c.Query("all") {
+	case "", "false":
+	case "true":
+		if c.Query("page") != "" || c.Query("per_page") != "" {
+			return nil, false, fmt.Errorf("all=true can't be combined with page or per_page")
+		}
+		return filter, false, nil
+	default:
+		return nil, false, fmt.Errorf("all must be true or false")
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		return nil, false, fmt.Errorf("page must be a positive number")
+	}
+	perPage, err := strconv.Atoi(c.Query("per_page", strconv.Itoa(defaultSongsPerPage)))
+	if err != nil || perPage < 1 || perPage > maxSongsPerPage {
+		return nil, false, fmt.Errorf("per_page must be between 1 and %d", maxSongsPerPage)
+	}
+	filter.Limit, filter.Offset = perPage, (page-1)*perPage
+	return filter, true, nil
+}
+
+// parseSongFields reads ?fields=id,title,display_lyrics; nil means every field
+func parseSongFields(c *fiber.Ctx) ([]string, error) {
+	spec := c.Query("fields")
+	if spec == "" {
+		return nil, nil
+	}
+	fields := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		if !songListFields[field] {
+			return nil, fmt.Errorf("unknown field %s", field)
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// sparseSong is a song narrowed to some of its fields, in the order asked for
+type sparseSong struct {
+	song   *models.Song
+	fields []string
+}
+
+func (s sparseSong) MarshalJSON() ([]byte, error) {
+	full, err := json.Marshal(s.song)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(full, &values); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	n := 0
+	for _, field := range s.fields {
+		value, ok := values[field]
+		if !ok {
+			continue // left out when empty, as in full records
+		}
+		if n > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%q:", field)
+		b.Write(value)
+		n++
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// getSongPage answers a paginated GET /api/songs with one page and the total
+func (h *Handler) getSongPage(c *fiber.Ctx, filter *models.SongFilter, fields []string) error {
+	total, err := h.db.CountFilteredSongs(filter)
+	if err != nil {
+		h.reportError(c, "Error counting songs", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get songs"})
+	}
+
+	page := &models.SongPage{
+		Songs:      make([]interface{}, 0, filter.Limit),
+		Page:       filter.Offset/filter.Limit + 1,
+		PerPage:    filter.Limit,
+		Total:      total,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+	}
+	if filter.Offset < total {
+		err = h.db.EachFilteredSong(filter, func(song *models.Song) error {
+			if fields != nil {
+				page.Songs = append(page.Songs, sparseSong{song: song, fields: fields})
+			} else {
+				page.Songs = append(page.Songs, song)
+			}
+			return nil
+		})
+		if err != nil {
+			h.reportError(c, "Error getting songs", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to get songs"})
+		}
+	}
+	return c.JSON(page)
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/live"
+	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+	"github.com/yourusername/audience-stage-teleprompter/internal/ws"
+)
+
+// Alert durations are clamped so a forgotten announcement can't stay up all service
+const (
+	defaultAlertDuration = 60 * time.Second
+	maxAlertDuration     = 30 * time.Minute
+	liveKeepAlive        = 15 * time.Second
+)
+
+var alertPriorities = map[string]bool{
+	"low":    true,
+	"normal": true,
+	"high":   true,
+	"urgent": true,
+}
+
+// LiveEvents streams live channel events to a display using Server-Sent Events.
+// Displays identify themselves with ?display=<name> so they can be targeted individually,
+// and stage/confidence monitors pass ?role=stage to receive presenter notes.
+func (h *Handler) LiveEvents(c *fiber.Ctx) error {
+	display := strings.TrimSpace(c.Query("display", ""))
+	role := c.Query("role", live.RoleAudience)
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	sub := h.live.Subscribe(display, role)
+	snapshot := h.live.Snapshot(display, role)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.live.Unsubscribe(sub)
+
+		if err := writeLiveEvent(w, live.Event{Type: live.EventState, Data: snapshot, Timestamp: time.Now()}); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(liveKeepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case evt, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				if err := writeLiveEvent(w, evt); err != nil {
+					return
+				}
+			case <-ticker.C:
+				// Comment lines keep proxies from closing idle connections
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeLiveEvent writes a single SSE frame and flushes it to the client
+func writeLiveEvent(w *bufio.Writer, evt live.Event) error {
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		log.Printf("Error encoding live event: %v", err)
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Type, payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// LiveSocket is the live channel over a WebSocket, for displays that would
+// rather keep one socket open than an event stream. It takes the same
+// ?display= and ?role=, sends the "state" snapshot first and then every
+// event as a JSON message, the same as LiveEvents. Messages from the display
+// are ignored.
+func (h *Handler) LiveSocket(c *fiber.Ctx) error {
+	if !ws.IsUpgrade(c) {
+		return c.Status(426).JSON(fiber.Map{"error": "This live channel needs a WebSocket connection; use /api/live/events for Server-Sent Events"})
+	}
+	display := strings.TrimSpace(c.Query("display", ""))
+	role := c.Query("role", live.RoleAudience)
+
+	err := ws.Upgrade(c, func(conn *ws.Conn) {
+		sub := h.live.Subscribe(display, role)
+		defer h.live.Unsubscribe(sub)
+		go writeLiveSocket(conn, sub, h.live.Snapshot(display, role))
+
+		// Reading notices when the display goes away
+		for {
+			if _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	return socketError(c, err)
+}
+
+// socketError answers a WebSocket handshake that failed, if it did
+func socketError(c *fiber.Ctx, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ws.ErrForbiddenOrigin):
+		return c.Status(403).JSON(fiber.Map{"error": "WebSocket connections from this origin are not allowed; add it to CORS_ORIGINS"})
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid WebSocket handshake"})
+	}
+}
+
+// writeLiveSocket sends the snapshot and then live events to a WebSocket
+// display until it is unsubscribed or stops answering
+func writeLiveSocket(conn *ws.Conn, sub *live.Subscriber, snapshot live.State) {
+	if conn.WriteJSON(live.Event{Type: live.EventState, Data: snapshot, Timestamp: time.Now()}) != nil {
+		conn.Close()
+		return
+	}
+
+	ticker := time.NewTicker(liveKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case evt, ok := <-sub.Events:
+			if !ok || conn.WriteJSON(evt) != nil {
+				// Closing also ends the reader
+				conn.Close()
+				return
+			}
+		case <-ticker.C:
+			if conn.Ping() != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// LiveState returns the current live state snapshot
+func (h *Handler) LiveState(c *fiber.Ctx) error {
+	return c.JSON(h.live.Snapshot(c.Query("display", ""), c.Query("role", live.RoleAudience)))
+}
+
+// SendAlert broadcasts a short announcement to all or selected displays
+func (h *Handler) SendAlert(c *fiber.Ctx) error {
+	var req struct {
+		Message         string   `json:"message"`
+		Priority        string   `json:"priority"`
+		DurationSeconds int      `json:"duration_seconds"`
+		Displays        []string `json:"displays"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "message is required"})
+	}
+	if len(req.Message) > 280 {
+		return c.Status(400).JSON(fiber.Map{"error": "message must be 280 characters or fewer"})
+	}
+
+	priority := strings.ToLower(strings.TrimSpace(req.Priority))
+	if priority == "" {
+		priority = "normal"
+	}
+	if !alertPriorities[priority] {
+		return c.Status(400).JSON(fiber.Map{"error": "priority must be one of low, normal, high, urgent"})
+	}
+
+	duration := defaultAlertDuration
+	if req.DurationSeconds > 0 {
+		duration = time.Duration(req.DurationSeconds) * time.Second
+	}
+	if duration > maxAlertDuration {
+		duration = maxAlertDuration
+	}
+
+	displays := make([]string, 0, len(req.Displays))
+	for _, d := range req.Displays {
+		if trimmed := strings.TrimSpace(d); trimmed != "" {
+			displays = append(displays, trimmed)
+		}
+	}
+
+	alert := h.live.SendAlert(req.Message, priority, duration, displays)
+	log.Printf("Live alert %s sent (%s): %q", alert.ID, priority, alert.Message)
+
+	return c.Status(201).JSON(alert)
+}
+
+// Blank clears the ProPresenter slide layer and blanks every teleprompter display,
+// e.g. for prayer moments. Displays keep their content so Unblank can restore it.
+func (h *Handler) Blank(c *fiber.Ctx) error {
+	changed := h.live.SetBlanked(true)
+
+	response := fiber.Map{
+		"success": true,
+		"blanked": true,
+		"changed": changed,
+	}
+
+	if h.propresenter != nil && h.propresenter.IsEnabled() {
+		if err := h.propresenter.ClearLayer("slide"); err != nil {
+			h.reportError(c, "Error clearing ProPresenter slide layer during blank", err)
+			response["propresenter_error"] = err.Error()
+		}
+	}
+
+	return c.JSON(response)
+}
+
+// Unblank restores displays and re-triggers the slide that was showing in ProPresenter
+func (h *Handler) Unblank(c *fiber.Ctx) error {
+	changed := h.live.SetBlanked(false)
+
+	response := fiber.Map{
+		"success": true,
+		"blanked": false,
+		"changed": changed,
+	}
+
+	current := h.live.Current()
+	if changed && current != nil && current.PresentationUUID != "" && h.propresenter != nil && h.propresenter.IsEnabled() {
+		if err := h.propresenter.TriggerPresentationSlide(current.PresentationUUID, current.SlideIndex); err != nil {
+			h.reportError(c, "Error restoring ProPresenter slide after unblank", err)
+			response["propresenter_error"] = err.Error()
+		}
+	}
+	response["current"] = current
+
+	return c.JSON(response)
+}
+
+// Panic is the one-button recovery: it clears every ProPresenter layer, stops
+// timers and blanks all displays. Failures are reported but never stop the
+// remaining steps.
+func (h *Handler) Panic(c *fiber.Ctx) error {
+	log.Printf("⚠️  PANIC clear-all triggered from %s", c.IP())
+
+	h.live.Panic()
+	h.advance.Stop()
+	h.audio.Stop() // ProPresenter's audio layer is cleared below
+
+	response := fiber.Map{
+		"success": true,
+		"blanked": true,
+	}
+
+	if h.propresenter != nil && h.propresenter.IsEnabled() {
+		errs := fiber.Map{}
+		for _, layer := range propresenter.Layers {
+			if err := h.propresenter.ClearLayer(layer); err != nil {
+				errs[layer] = err.Error()
+			}
+		}
+		if err := h.propresenter.StopAllTimers(); err != nil {
+			errs["timers"] = err.Error()
+		}
+		if len(errs) > 0 {
+			h.reportError(c, "PANIC clear-all completed with ProPresenter errors", fmt.Errorf("%v", errs))
+			response["propresenter_errors"] = errs
+		}
+	}
+
+	return c.JSON(response)
+}
+
+// GetAlerts lists the alerts currently shown on displays
+func (h *Handler) GetAlerts(c *fiber.Ctx) error {
+	return c.JSON(h.live.ActiveAlerts())
+}
+
+// DismissAlert removes an alert from all displays before it expires
+func (h *Handler) DismissAlert(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.live.DismissAlert(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Alert not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Alert dismissed"})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/live"
+	"github.com/yourusername/audience-stage-teleprompter/internal/ws"
+)
+
+func TestLiveSocketChecksOrigin(t *testing.T) {
+	h := &Handler{live: live.NewHub()}
+	app := fiber.New()
+	app.Get("/ws/live", h.LiveSocket)
+	t.Cleanup(func() { ws.SetAllowedOrigins("") })
+
+	tests := []struct {
+		name    string
+		allowed string
+		origin  string
+		status  int
+	}{
+		{"foreign origin", "", "https://evil.example", 403},
+		{"wildcard allows no foreign origin", "*", "https://evil.example", 403},
+		{"same host", "", "http://teleprompter.local", 101},
+		{"allowed origin", "https://stage.example.org", "https://stage.example.org", 101},
+		{"no origin", "", "", 101},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws.SetAllowedOrigins(tt.allowed)
+			req := httptest.NewRequest("GET", "http://teleprompter.local/ws/live", nil)
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Sec-WebSocket-Version", "13")
+			req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
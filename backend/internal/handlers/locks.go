@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/editlock"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// lockToken is the edit lock token sent with a request, from the
+// X-Lock-Token header or ?lock_token=
+func lockToken(c *fiber.Ctx) string {
+	if token := c.Get("X-Lock-Token"); token != "" {
+		return token
+	}
+	return c.Query("lock_token")
+}
+
+// attachLock adds the song's current edit lock to a response. When the
+// request was a save by someone not holding the lock, it also says so:
+// locks are advisory, so the save still went through.
+func (h *Handler) attachLock(c *fiber.Ctx, song *models.Song, saved bool) {
+	lock, held := h.locks.Get(song.ID)
+	if !held {
+		return
+	}
+	song.Lock = &lock
+	if saved && !h.locks.HeldBy(song.ID, lockToken(c)) {
+		song.LockWarning = "saved while " + lock.Holder + " is editing this song; their changes may overwrite yours"
+	}
+}
+
+// LockSong takes or renews the advisory edit lock on a song. Send the token
+// from a previous lock to renew it before it expires; takeover=true takes the
+// lock from whoever holds it.
+func (h *Handler) LockSong(c *fiber.Ctx) error {
+	var req struct {
+		Holder   string `json:"holder"`
+		Token    string `json:"token"`
+		Takeover bool   `json:"takeover"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Holder = strings.TrimSpace(req.Holder)
+	if req.Token == "" {
+		req.Token = lockToken(c)
+	}
+
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+	// Renewing can leave out the holder; a new lock needs one
+	if req.Holder == "" && !h.locks.HeldBy(song.ID, req.Token) {
+		return c.Status(400).JSON(fiber.Map{"error": "Holder is required"})
+	}
+
+	lock, err := h.locks.Acquire(song.ID, req.Holder, req.Token, req.Takeover)
+	if err == editlock.ErrHeld {
+		return c.Status(409).JSON(fiber.Map{
+			"error": lock.Holder + " is editing this song",
+			"lock":  lock,
+		})
+	}
+
+	return c.JSON(lock)
+}
+
+// GetSongLock returns who is editing a song, if anyone
+func (h *Handler) GetSongLock(c *fiber.Ctx) error {
+	lock, held := h.locks.Get(c.Params("id"))
+	if !held {
+		return c.JSON(fiber.Map{"locked": false})
+	}
+
+	return c.JSON(fiber.Map{"locked": true, "lock": lock})
+}
+
+// UnlockSong releases the edit lock held with the given token
+func (h *Handler) UnlockSong(c *fiber.Ctx) error {
+	if err := h.locks.Release(c.Params("id"), lockToken(c)); err != nil {
+		return c.Status(409).JSON(fiber.Map{"error": "The lock is held by someone else"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Lock released successfully"})
+}
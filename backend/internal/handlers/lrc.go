@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/export"
+	"github.com/yourusername/audience-stage-teleprompter/internal/importer"
+)
+
+// GetTimedLyrics returns a song's synced lyrics as JSON
+func (h *Handler) GetTimedLyrics(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	timed, err := h.db.GetTimedLyrics(id)
+	if err != nil {
+		log.Printf("Error getting timed lyrics: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get timed lyrics"})
+	}
+	if timed == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "No timed lyrics for this song"})
+	}
+
+	return c.JSON(timed)
+}
+
+// ExportSongLRC downloads a song's synced lyrics as an .lrc file
+func (h *Handler) ExportSongLRC(c *fiber.Ctx) error {
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	timed, err := h.db.GetTimedLyrics(song.ID)
+	if err != nil {
+		log.Printf("Error getting timed lyrics: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get timed lyrics"})
+	}
+	if timed == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "No timed lyrics for this song"})
+	}
+
+	setAttachment(c, export.FileName(song.Title, ".lrc"))
+	c.Set("Content-Type", "text/plain; charset=utf-8")
+	return c.SendString(export.LRC(song, timed))
+}
+
+// ImportSongLRC attaches the timestamps from an uploaded .lrc file to a song,
+// replacing any it had. The song's lyrics are left as they are; the response
+// reports how many timed lines match a line of the display lyrics so mismatched
+// files are easy to spot.
+func (h *Handler) ImportSongLRC(c *fiber.Ctx) error {
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	files, err := readUploadedFiles(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if len(files) != 1 {
+		return c.Status(400).JSON(fiber.Map{"error": "Upload exactly one .lrc file"})
+	}
+
+	lrc, err := importer.ParseLRC(files[0].Data)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid LRC file: " + err.Error()})
+	}
+
+	timed, err := h.db.SetTimedLyrics(song.ID, lrc.Lines, lrc.OffsetMs)
+	if err != nil {
+		log.Printf("Error saving timed lyrics: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save timed lyrics"})
+	}
+
+	known := make(map[string]bool)
+	for _, line := range strings.Split(song.DisplayLyrics, "\n") {
+		if key := normalizeLyricLine(line); key != "" {
+			known[key] = true
+		}
+	}
+	matched := 0
+	for _, line := range lrc.Lines {
+		if known[normalizeLyricLine(line.Text)] {
+			matched++
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"timed_lyrics":  timed,
+		"lines":         len(lrc.Lines),
+		"matched_lines": matched,
+	})
+}
+
+// DeleteTimedLyrics clears a song's synced lyrics
+func (h *Handler) DeleteTimedLyrics(c *fiber.Ctx) error {
+	if err := h.db.DeleteTimedLyrics(c.Params("id")); err != nil {
+		if err.Error() == "timed lyrics not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "No timed lyrics for this song"})
+		}
+		log.Printf("Error deleting timed lyrics: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete timed lyrics"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Timed lyrics deleted successfully"})
+}
+
+// normalizeLyricLine lowercases a line and drops punctuation for loose matching
+func normalizeLyricLine(line string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(line), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
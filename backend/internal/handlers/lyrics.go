@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+)
+
+// GetSongLyrics returns a song's lyrics by section, rendered for a display
+// (?format=plain|html|spans, ?source=display|music_ministry|romanized). Teleprompters
+// use html or spans to show emphasis; plain is what ProPresenter receives. A
+// "Copyright" section is appended when attribution slides are on.
+func (h *Handler) GetSongLyrics(c *fiber.Ctx) error {
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	text := song.DisplayLyrics
+	switch c.Query("source", "display") {
+	case "display":
+	case "music_ministry":
+		text = song.MusicMinistryLyrics
+	case "romanized":
+		if text, err = h.romanizedLyrics(song); err != nil {
+			log.Printf("Error getting romanized lyrics: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to get romanized lyrics"})
+		}
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "source must be display, music_ministry or romanized"})
+	}
+
+	format := c.Query("format", "html")
+	var render func(string) interface{}
+	switch format {
+	case "plain":
+		render = func(line string) interface{} { return lyrics.PlainLine(line) }
+	case "html":
+		render = func(line string) interface{} { return lyrics.HTMLLine(line) }
+	case "spans":
+		render = func(line string) interface{} { return lyrics.ParseMarkup(line) }
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "format must be plain, html or spans"})
+	}
+
+	sections := make([]fiber.Map, 0)
+	for _, section := range lyrics.ParseSections(text) {
+		lines := make([]interface{}, len(section.Lines))
+		for i, line := range section.Lines {
+			lines[i] = render(line)
+		}
+		sections = append(sections, fiber.Map{"label": section.Label, "lines": lines})
+	}
+	if enabled, license := h.copyrightSlides(c); enabled {
+		if attribution := lyrics.CopyrightSlide(song, license); attribution != nil {
+			lines := make([]interface{}, len(attribution))
+			for i, line := range attribution {
+				lines[i] = render(lyrics.EscapeMarkup(line))
+			}
+			sections = append(sections, fiber.Map{"label": lyrics.CopyrightLabel, "lines": lines})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"song_id":  song.ID,
+		"format":   format,
+		"sections": sections,
+	})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/maintenance"
+)
+
+// SetMaintenance sets the switch that GetMaintenance and UpdateMaintenance
+// report and flip. Archive imports turn it on while they run.
+func (h *Handler) SetMaintenance(m *maintenance.Mode) {
+	h.maintenance = m
+}
+
+// GetMaintenance reports whether changes are paused, so clients can show
+// the message instead of letting saves fail
+func (h *Handler) GetMaintenance(c *fiber.Ctx) error {
+	return c.JSON(h.maintenance.Status())
+}
+
+// UpdateMaintenance turns maintenance mode on or off
+func (h *Handler) UpdateMaintenance(c *fiber.Ctx) error {
+	if h.maintenance == nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Maintenance mode is not available"})
+	}
+
+	var req struct {
+		Enabled bool   `json:"enabled"`
+		Reason  string `json:"reason"`  // e.g. "restore", "migration", "reindex"
+		Message string `json:"message"` // shown to users; a default is used if empty
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if req.Enabled {
+		h.maintenance.Enable(req.Reason, req.Message)
+		log.Printf("🚧 Maintenance mode on (%s): changes are paused", req.Reason)
+	} else {
+		h.maintenance.Disable()
+		log.Println("✅ Maintenance mode off")
+	}
+
+	return c.JSON(h.maintenance.Status())
+}
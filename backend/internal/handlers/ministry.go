@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/music"
+)
+
+// GetMinistryView returns the music ministry lyrics with key and capo data for the band's tablets.
+// ?key= previews a different performance key without saving it.
+func (h *Handler) GetMinistryView(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	song, err := h.db.GetSong(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	lyrics := song.MusicMinistryLyrics
+	if lyrics == "" {
+		lyrics = song.DisplayLyrics
+	}
+
+	response := fiber.Map{
+		"song_id":         song.ID,
+		"title":           song.Title,
+		"artist":          song.Artist,
+		"lyrics":          lyrics,
+		"original_key":    song.OriginalKey,
+		"performance_key": song.PerformanceKey,
+	}
+
+	performance := song.PerformanceKey
+	if override := c.Query("key", ""); override != "" {
+		performance = &override
+	}
+	if performance == nil || *performance == "" {
+		performance = song.OriginalKey
+	}
+	if performance == nil || *performance == "" {
+		return c.JSON(response)
+	}
+
+	perfKey, err := music.ParseKey(*performance)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	response["performance_key"] = perfKey.String()
+	response["capo_options"] = music.CapoOptions(perfKey)
+
+	if song.OriginalKey != nil && *song.OriginalKey != "" {
+		if origKey, err := music.ParseKey(*song.OriginalKey); err == nil {
+			response["transpose_semitones"] = music.Interval(origKey, perfKey)
+		}
+	}
+
+	return c.JSON(response)
+}
+
+// normalizeKeyField validates a key in a song request and rewrites it to its preferred spelling.
+// An empty string is left as is so updates can clear the key.
+func normalizeKeyField(field string, value *string) error {
+	if value == nil || *value == "" {
+		return nil
+	}
+
+	key, err := music.ParseKey(*value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	*value = key.String()
+	return nil
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/language"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// normalizeSongRequest normalizes the text of a new song and cleans its markup
+func normalizeSongRequest(req *models.CreateSongRequest) {
+	req.Title = language.NormalizeLine(req.Title)
+	if req.Artist != nil {
+		*req.Artist = language.NormalizeLine(*req.Artist)
+	}
+	req.DisplayLyrics = lyrics.SanitizeMarkup(language.Normalize(req.DisplayLyrics))
+	req.MusicMinistryLyrics = lyrics.SanitizeMarkup(language.Normalize(req.MusicMinistryLyrics))
+}
+
+// normalizeSongUpdate does the same for the fields present in an update
+func normalizeSongUpdate(req *models.UpdateSongRequest) {
+	if req.Title != nil {
+		*req.Title = language.NormalizeLine(*req.Title)
+	}
+	if req.Artist != nil {
+		*req.Artist = language.NormalizeLine(*req.Artist)
+	}
+	if req.DisplayLyrics != nil {
+		*req.DisplayLyrics = lyrics.SanitizeMarkup(language.Normalize(*req.DisplayLyrics))
+	}
+	if req.MusicMinistryLyrics != nil {
+		*req.MusicMinistryLyrics = lyrics.SanitizeMarkup(language.Normalize(*req.MusicMinistryLyrics))
+	}
+}
+
+// normalizeImportedSong normalizes a song read from another program. Those
+// have no emphasis markup, so any asterisks are escaped to stay literal.
+func normalizeImportedSong(req *models.CreateSongRequest) {
+	req.Title = language.NormalizeLine(req.Title)
+	if req.Artist != nil {
+		*req.Artist = language.NormalizeLine(*req.Artist)
+	}
+	req.DisplayLyrics = lyrics.EscapeMarkup(language.Normalize(req.DisplayLyrics))
+	req.MusicMinistryLyrics = lyrics.EscapeMarkup(language.Normalize(req.MusicMinistryLyrics))
+}
+
+// NormalizeLibrary re-runs text normalization over songs stored before it
+// existed, updating and reindexing only the songs that change (?dry_run=true
+// lists them without saving)
+func (h *Handler) NormalizeLibrary(c *fiber.Ctx) error {
+	dryRun := c.Query("dry_run") == "true"
+
+	type pending struct {
+		id, title string
+		update    models.UpdateSongRequest
+	}
+	var changes []pending
+	scanned := 0
+
+	err := h.db.EachSong(func(song *models.Song) error {
+		scanned++
+		var update models.UpdateSongRequest
+		changed := false
+
+		normalizeField := func(current string, normalized string, field **string) {
+			if normalized != current {
+				*field = &normalized
+				changed = true
+			}
+		}
+		normalizeField(song.Title, language.NormalizeLine(song.Title), &update.Title)
+		normalizeField(song.DisplayLyrics, language.Normalize(song.DisplayLyrics), &update.DisplayLyrics)
+		normalizeField(song.MusicMinistryLyrics, language.Normalize(song.MusicMinistryLyrics), &update.MusicMinistryLyrics)
+		if song.Artist != nil {
+			normalizeField(*song.Artist, language.NormalizeLine(*song.Artist), &update.Artist)
+		}
+
+		if changed {
+			changes = append(changes, pending{id: song.ID, title: song.Title, update: update})
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error scanning songs for normalization: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to scan songs"})
+	}
+
+	songs := make([]fiber.Map, 0, len(changes))
+	failed := 0
+	updated := make([]models.Song, 0, len(changes))
+	for _, change := range changes {
+		if !dryRun {
+			change.update.EditedBy = editorName(c)
+			change.update.RevisionNote = "Library normalization"
+			song, err := h.db.UpdateSong(change.id, &change.update)
+			if err != nil {
+				log.Printf("Error normalizing song %s: %v", change.id, err)
+				failed++
+				continue
+			}
+			updated = append(updated, *song)
+		}
+		songs = append(songs, fiber.Map{"id": change.id, "title": change.title})
+	}
+
+	if len(updated) > 0 {
+		h.backupManager.RecordEdits(len(updated))
+	}
+	if len(updated) > 0 && !h.skipTypesense && h.ts != nil {
+		if err := h.ts.IndexSongs(updated); err != nil {
+			h.reportError(c, "Error reindexing normalized songs", err)
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"dry_run": dryRun,
+		"scanned": scanned,
+		"changed": len(songs),
+		"failed":  failed,
+		"songs":   songs,
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+const maxNoteLength = 500
+
+// GetSongNotes lists the presenter notes for a song
+func (h *Handler) GetSongNotes(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	notes, err := h.db.GetSongNotes(id)
+	if err != nil {
+		log.Printf("Error getting song notes: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get notes"})
+	}
+
+	return c.JSON(notes)
+}
+
+// CreateSongNote adds a presenter note to a song or one of its sections
+func (h *Handler) CreateSongNote(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	req, errMsg := parseSongNoteRequest(c)
+	if errMsg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": errMsg})
+	}
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	note, err := h.db.CreateSongNote(id, req)
+	if err != nil {
+		log.Printf("Error creating song note: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create note"})
+	}
+
+	h.refreshStageNotes(id)
+	return c.Status(201).JSON(note)
+}
+
+// UpdateSongNote replaces a presenter note
+func (h *Handler) UpdateSongNote(c *fiber.Ctx) error {
+	id := c.Params("id")
+	noteID, err := strconv.Atoi(c.Params("note_id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid note ID"})
+	}
+
+	req, errMsg := parseSongNoteRequest(c)
+	if errMsg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": errMsg})
+	}
+
+	note, err := h.db.UpdateSongNote(id, noteID, req)
+	if err != nil {
+		if err.Error() == "note not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Note not found"})
+		}
+		log.Printf("Error updating song note: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update note"})
+	}
+
+	h.refreshStageNotes(id)
+	return c.JSON(note)
+}
+
+// DeleteSongNote removes a presenter note
+func (h *Handler) DeleteSongNote(c *fiber.Ctx) error {
+	id := c.Params("id")
+	noteID, err := strconv.Atoi(c.Params("note_id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid note ID"})
+	}
+
+	if err := h.db.DeleteSongNote(id, noteID); err != nil {
+		if err.Error() == "note not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Note not found"})
+		}
+		log.Printf("Error deleting song note: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete note"})
+	}
+
+	h.refreshStageNotes(id)
+	return c.JSON(fiber.Map{"message": "Note deleted successfully"})
+}
+
+// parseSongNoteRequest reads and validates a note body, returning an error message on failure
+func parseSongNoteRequest(c *fiber.Ctx) (*models.SongNoteRequest, string) {
+	var req models.SongNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, "Invalid request body"
+	}
+
+	req.Note = strings.TrimSpace(req.Note)
+	req.SectionLabel = strings.TrimSpace(req.SectionLabel)
+
+	if req.Note == "" {
+		return nil, "note is required"
+	}
+	if len(req.Note) > maxNoteLength {
+		return nil, "note must be 500 characters or fewer"
+	}
+	if req.SectionIndex != nil && *req.SectionIndex < 0 {
+		return nil, "section_index must be zero or greater"
+	}
+
+	return &req, ""
+}
+
+// publishStageNotes sends the notes for the song now on screen to stage displays.
+// Notes never go to audience displays or ProPresenter.
+func (h *Handler) publishStageNotes(songID string) {
+	if songID == "" {
+		h.live.SetNotes("", nil)
+		return
+	}
+
+	notes, err := h.db.GetSongNotes(songID)
+	if err != nil {
+		log.Printf("Error loading presenter notes for live song %s: %v", songID, err)
+		notes = nil
+	}
+	h.live.SetNotes(songID, notes)
+}
+
+// refreshStageNotes re-sends notes when the edited song is currently live
+func (h *Handler) refreshStageNotes(songID string) {
+	if current := h.live.Current(); current != nil && current.SongID == songID {
+		h.publishStageNotes(songID)
+	}
+}
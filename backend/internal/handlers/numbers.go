@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/language"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+var (
+	songNumberPattern = regexp.MustCompile(`^\d{1,5}[A-Za-z]?$`)
+
+	// "KK 123", "KK#123", "Kristheeya Keerthanangal no. 123" or just "123"
+	numberQueryPattern = regexp.MustCompile(`^(.*?)\s*(?:#|(?i:no\.?|number))?\s*(\d{1,5}[A-Za-z]?)$`)
+)
+
+// normalizeSongNumbers cleans and validates songbook references from a song
+// request, dropping exact duplicates
+func normalizeSongNumbers(numbers []models.SongNumber) ([]models.SongNumber, error) {
+	out := make([]models.SongNumber, 0, len(numbers))
+	seen := make(map[string]bool)
+	for _, n := range numbers {
+		n.Songbook = language.NormalizeLine(n.Songbook)
+		n.Number = strings.TrimPrefix(strings.TrimSpace(n.Number), "#")
+		if n.Songbook == "" {
+			return nil, fmt.Errorf("songbook is required for each song number")
+		}
+		if !songNumberPattern.MatchString(n.Number) {
+			return nil, fmt.Errorf("invalid number %q for %s (use digits, optionally followed by a letter)", n.Number, n.Songbook)
+		}
+
+		key := strings.ToLower(n.Songbook + "|" + n.Number)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// checkSongNumbers normalizes the song numbers in a request and makes sure no
+// song other than songID uses them. On failure it returns the status code and
+// message to respond with.
+func (h *Handler) checkSongNumbers(songID string, numbers []models.SongNumber) ([]models.SongNumber, int, string) {
+	numbers, err := normalizeSongNumbers(numbers)
+	if err != nil {
+		return nil, 400, err.Error()
+	}
+	if len(numbers) == 0 {
+		return numbers, 0, ""
+	}
+
+	taken, err := h.db.TakenSongNumbers(songID, numbers)
+	if err != nil {
+		log.Printf("Error checking song numbers: %v", err)
+		return nil, 500, "Failed to check song numbers"
+	}
+	if len(taken) > 0 {
+		refs := make([]string, len(taken))
+		for i, n := range taken {
+			refs[i] = fmt.Sprintf("%s #%s", n.Songbook, n.Number)
+		}
+		return nil, 409, "Already used by another song: " + strings.Join(refs, ", ")
+	}
+	return numbers, 0, ""
+}
+
+// searchByNumber answers queries such as "KK 123". The part before the number
+// is matched against songbooks (abbreviation, full name, initials, or a prefix
+// of at least three letters); a bare number matches every songbook. ok is false when
+// the query isn't a number lookup or nothing matches, so a normal text search
+// can run instead.
+func (h *Handler) searchByNumber(query string) (songs []models.Song, ok bool) {
+	m := numberQueryPattern.FindStringSubmatch(strings.TrimSpace(query))
+	if m == nil {
+		return nil, false
+	}
+	book, number := m[1], m[2]
+
+	var songbooks []string
+	if book != "" {
+		books, err := h.db.GetSongbooks()
+		if err != nil || len(books) == 0 {
+			return nil, false
+		}
+		for _, b := range books {
+			if strings.EqualFold(b.Abbreviation, strings.TrimSpace(book)) || songbookMatches(b.Name, book) {
+				songbooks = append(songbooks, b.Name)
+			}
+		}
+		if len(songbooks) == 0 {
+			return nil, false
+		}
+	}
+
+	songs, err := h.db.FindSongsByNumber(songbooks, number)
+	if err != nil || len(songs) == 0 {
+		return nil, false
+	}
+	for i := range songs {
+		if numbers, err := h.db.GetSongNumbers(songs[i].ID); err == nil {
+			songs[i].Numbers = numbers
+		}
+	}
+	return songs, true
+}
+
+// songbookMatches reports whether a query abbreviation refers to a songbook
+func songbookMatches(name, query string) bool {
+	name = strings.ToLower(name)
+	query = strings.ToLower(strings.TrimSpace(query))
+	compact := strings.NewReplacer(".", "", " ", "").Replace(query)
+
+	if query == name || compact == songbookInitials(name) {
+		return true
+	}
+	return len([]rune(query)) >= 3 && strings.HasPrefix(name, query)
+}
+
+// songbookInitials returns the first letter of each word, e.g. "kk" for
+// "kristheeya keerthanangal"
+func songbookInitials(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		for _, r := range word {
+			b.WriteRune(r)
+			break
+		}
+	}
+	return b.String()
+}
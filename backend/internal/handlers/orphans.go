@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/jobs"
+)
+
+const orphanCleanupJob = "orphan-cleanup"
+
+type orphanedDocument struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type orphanCleanupReport struct {
+	DryRun  bool               `json:"dry_run"`
+	Indexed int                `json:"indexed"`
+	Orphans []orphanedDocument `json:"orphans"`
+	Deleted int                `json:"deleted"`
+}
+
+// cleanupOrphans removes search documents whose song no longer exists in the
+// database, usually left behind by a delete that failed to reach Typesense
+func (h *Handler) cleanupOrphans(job *jobs.Job, dryRun bool) error {
+	indexed, err := h.ts.IndexedSongs()
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(indexed))
+	for i, doc := range indexed {
+		ids[i] = doc.ID
+	}
+	existing, err := h.db.ExistingSongIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	report := orphanCleanupReport{DryRun: dryRun, Indexed: len(indexed), Orphans: make([]orphanedDocument, 0)}
+	for _, doc := range indexed {
+		if !existing[doc.ID] {
+			report.Orphans = append(report.Orphans, orphanedDocument{ID: doc.ID, Title: doc.Title})
+		}
+	}
+	job.SetTotal(len(report.Orphans), 0)
+
+	if !dryRun {
+		for _, orphan := range report.Orphans {
+			if err := h.ts.DeleteSong(orphan.ID); err != nil {
+				job.Fail(fmt.Errorf("document %s (%s): %w", orphan.ID, orphan.Title, err))
+				continue
+			}
+			report.Deleted++
+			job.Done(1)
+		}
+	}
+
+	job.SetResult(report)
+	return nil
+}
+
+// CleanupOrphans starts a background job that deletes search documents for
+// songs that no longer exist (dry_run=true only lists them). The result is at
+// GET /admin/index/cleanup/:id.
+func (h *Handler) CleanupOrphans(c *fiber.Ctx) error {
+	if h.ts == nil || h.skipTypesense {
+		return c.Status(400).JSON(fiber.Map{"error": "Typesense is disabled"})
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	job, started := h.jobs.Start(orphanCleanupJob, func(job *jobs.Job) error {
+		return h.cleanupOrphans(job, dryRun)
+	})
+	status := job.Status()
+	if !started {
+		return c.Status(409).JSON(fiber.Map{"error": "An orphan cleanup is already running", "job_id": status.ID})
+	}
+
+	return c.Status(202).JSON(fiber.Map{
+		"message": "Orphan cleanup started",
+		"job_id":  status.ID,
+	})
+}
+
+// GetOrphanCleanupJob reports the progress and result of an orphan cleanup
+func (h *Handler) GetOrphanCleanupJob(c *fiber.Ctx) error {
+	status, ok := h.jobs.Get(c.Params("id"))
+	if !ok || status.Kind != orphanCleanupJob {
+		return c.Status(404).JSON(fiber.Map{"error": "Orphan cleanup job not found"})
+	}
+
+	return c.JSON(status)
+}
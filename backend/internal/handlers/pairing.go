@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// pairedSongView is one side of a pairing as shown in the pairing editor
+type pairedSongView struct {
+	ID       string           `json:"id"`
+	Title    string           `json:"title"`
+	Language string           `json:"language"`
+	Sections []lyrics.Section `json:"sections"`
+}
+
+func newPairedSongView(song *models.Song) pairedSongView {
+	return pairedSongView{
+		ID:       song.ID,
+		Title:    song.Title,
+		Language: song.Language,
+		Sections: lyrics.ParseSections(song.DisplayLyrics),
+	}
+}
+
+// loadSongPair fetches a pairing and both of its songs
+func (h *Handler) loadSongPair(songID string) (*models.SongPair, *models.Song, *models.Song, error) {
+	pair, err := h.db.GetSongPair(songID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	primary, err := h.db.GetSong(pair.PrimarySongID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	secondary, err := h.db.GetSong(pair.SecondarySongID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pair, primary, secondary, nil
+}
+
+// GetSongPair returns a song's translation pairing with both songs split into sections
+func (h *Handler) GetSongPair(c *fiber.Ctx) error {
+	pair, primary, secondary, err := h.loadSongPair(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song is not paired"})
+	}
+
+	return c.JSON(fiber.Map{
+		"pair":      pair,
+		"primary":   newPairedSongView(primary),
+		"secondary": newPairedSongView(secondary),
+	})
+}
+
+// UpdateSongPair pairs a song with its translation and stores the section alignment.
+// When no alignment is given the sections are aligned automatically.
+func (h *Handler) UpdateSongPair(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req models.UpdateSongPairRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if req.PairedSongID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "paired_song_id is required"})
+	}
+	if req.PairedSongID == id {
+		return c.Status(400).JSON(fiber.Map{"error": "A song cannot be paired with itself"})
+	}
+
+	primary, err := h.db.GetSong(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+	secondary, err := h.db.GetSong(req.PairedSongID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Paired song not found"})
+	}
+
+	primarySections := lyrics.ParseSections(primary.DisplayLyrics)
+	secondarySections := lyrics.ParseSections(secondary.DisplayLyrics)
+
+	alignment := req.Alignment
+	if len(alignment) == 0 {
+		alignment = lyrics.AutoAlign(primarySections, secondarySections)
+	} else if !lyrics.ValidateAlignment(alignment, len(primarySections), len(secondarySections)) {
+		return c.Status(400).JSON(fiber.Map{"error": "Alignment refers to sections that do not exist"})
+	}
+
+	pair, err := h.db.SaveSongPair(primary.ID, secondary.ID, alignment)
+	if err != nil {
+		log.Printf("Error saving song pair: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save song pair"})
+	}
+
+	return c.JSON(fiber.Map{
+		"pair":      pair,
+		"primary":   newPairedSongView(primary),
+		"secondary": newPairedSongView(secondary),
+	})
+}
+
+// DeleteSongPair unpairs a song from its translation
+func (h *Handler) DeleteSongPair(c *fiber.Ctx) error {
+	if err := h.db.DeleteSongPair(c.Params("id")); err != nil {
+		if err.Error() == "song pair not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Song is not paired"})
+		}
+		log.Printf("Error deleting song pair: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete song pair"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Song pair removed successfully"})
+}
+
+// GetPairedSlides returns the combined bilingual slide stream for a paired song
+func (h *Handler) GetPairedSlides(c *fiber.Ctx) error {
+	pair, primary, secondary, err := h.loadSongPair(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song is not paired"})
+	}
+
+	linesPerSlide := c.QueryInt("lines_per_slide", 2)
+	if linesPerSlide < 1 || linesPerSlide > 8 {
+		return c.Status(400).JSON(fiber.Map{"error": "lines_per_slide must be between 1 and 8"})
+	}
+
+	slides := lyrics.PairSlides(
+		lyrics.ParseSections(primary.DisplayLyrics),
+		lyrics.ParseSections(secondary.DisplayLyrics),
+		pair.Alignment,
+		linesPerSlide,
+	)
+
+	return c.JSON(fiber.Map{
+		"primary_song_id":    primary.ID,
+		"primary_language":   primary.Language,
+		"secondary_song_id":  secondary.ID,
+		"secondary_language": secondary.Language,
+		"slides":             slides,
+		"count":              len(slides),
+	})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// What an explicit null does to each song field in a merge patch
+const (
+	nullClears   = iota // the field becomes null
+	nullEmpties         // the field becomes empty: "" lyrics, no numbers or links
+	nullRejected        // the song can't be without it
+)
+
+var songPatchFields = map[string]int{
+	"title":                 nullRejected,
+	"library":               nullRejected,
+	"language":              nullRejected,
+	"display_lyrics":        nullRejected,
+	"public":                nullRejected,
+	"music_ministry_lyrics": nullEmpties,
+	"numbers":               nullEmpties,
+	"links":                 nullEmpties,
+	"artist":                nullClears,
+	"original_key":          nullClears,
+	"performance_key":       nullClears,
+	"bpm":                   nullClears,
+	"time_signature":        nullClears,
+	"count_in_beats":        nullClears,
+	"background_media":      nullClears,
+	"look":                  nullClears,
+	"copyright":             nullClears,
+	"ccli_number":           nullClears,
+	"chords":                nullClears,
+}
+
+// PatchSong updates a song with an RFC 7386 JSON merge patch: members that
+// are present are set, members that are absent are left alone, and a null
+// removes the field's value, e.g. {"artist": null}
+func (h *Handler) PatchSong(c *fiber.Ctx) error {
+	id := c.Params("id")
+	contentType := strings.ToLower(c.Get(fiber.HeaderContentType))
+	if !strings.HasPrefix(contentType, "application/merge-patch+json") && !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		return c.Status(415).JSON(fiber.Map{"error": "PATCH takes application/merge-patch+json"})
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &patch); err != nil || patch == nil {
+		return c.Status(400).JSON(fiber.Map{"error": "A merge patch must be a JSON object"})
+	}
+
+	var req models.UpdateSongRequest
+	set := make(map[string]json.RawMessage, len(patch))
+	for field, value := range patch {
+		onNull, known := songPatchFields[field]
+		if !known {
+			return c.Status(400).JSON(fiber.Map{"error": "Unknown field " + field})
+		}
+		if !bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			set[field] = value
+			continue
+		}
+		switch onNull {
+		case nullClears:
+			req.Clear = append(req.Clear, field)
+		case nullEmpties:
+			set[field] = emptyValue(field)
+		default:
+			return c.Status(400).JSON(fiber.Map{"error": field + " can't be removed"})
+		}
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	return h.saveSongUpdate(c, id, &req)
+}
+
+// emptyValue is the JSON a nullEmpties field takes when patched to null
+func emptyValue(field string) json.RawMessage {
+	if field == "numbers" || field == "links" {
+		return json.RawMessage("[]")
+	}
+	return json.RawMessage(`""`)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+)
+
+// maxHealthCheckInterval is the longest allowed gap between connection checks
+const maxHealthCheckInterval = time.Hour
+
+// HealthCheckInterval is the connection check interval the settings ask for
+func HealthCheckInterval(settings *models.Settings) time.Duration {
+	if settings == nil || settings.ProPresenterHealthInterval <= 0 {
+		return propresenter.DefaultHealthCheckInterval
+	}
+	return time.Duration(settings.ProPresenterHealthInterval) * time.Second
+}
+
+// validateHealthIntervalUpdate rejects intervals outside 5 seconds to an hour
+func validateHealthIntervalUpdate(req *models.UpdateSettingsRequest) error {
+	if req.ProPresenterHealthInterval == nil {
+		return nil
+	}
+	interval := time.Duration(*req.ProPresenterHealthInterval) * time.Second
+	if interval < propresenter.MinHealthCheckInterval || interval > maxHealthCheckInterval {
+		return fmt.Errorf("propresenter_health_interval must be between %d and %d seconds",
+			int(propresenter.MinHealthCheckInterval/time.Second), int(maxHealthCheckInterval/time.Second))
+	}
+	return nil
+}
+
+// GetProPresenterHealthCheck reports the periodic connection check
+func (h *Handler) GetProPresenterHealthCheck(c *fiber.Ctx) error {
+	if h.propresenter == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+	return c.JSON(h.propresenter.HealthCheckStatus())
+}
+
+// PauseProPresenterHealthCheck stops the periodic connection check, e.g.
+// while the ProPresenter machine is being worked on
+func (h *Handler) PauseProPresenterHealthCheck(c *fiber.Ctx) error {
+	if h.propresenter == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+	h.propresenter.PauseHealthCheck()
+	return c.JSON(h.propresenter.HealthCheckStatus())
+}
+
+// ResumeProPresenterHealthCheck restarts a paused connection check
+func (h *Handler) ResumeProPresenterHealthCheck(c *fiber.Ctx) error {
+	if h.propresenter == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+	h.propresenter.ResumeHealthCheck()
+	return c.JSON(h.propresenter.HealthCheckStatus())
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// defaultImportLibrary is the library of songs imported from ProPresenter
+// when the request names none
+const defaultImportLibrary = "ProPresenter"
+
+// ProPresenterImport reads a presentation's slide groups and saves them as a
+// song's display lyrics. The song linked to the presentation is updated; if
+// there is none, a song is created under the presentation's name and linked
+// to it, with the artist, copyright and CCLI number from its attribution
+// slide. dry_run returns the song without saving it.
+func (h *Handler) ProPresenterImport(c *fiber.Ctx) error {
+	if h.propresenter == nil || !h.propresenter.IsEnabled() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+
+	var req struct {
+		UUID     string `json:"uuid"`
+		Library  string `json:"library"`  // for a new song, default ProPresenter
+		Language string `json:"language"` // for a new song, default auto
+		DryRun   bool   `json:"dry_run"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.UUID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "uuid is required"})
+	}
+
+	presentation, err := h.propresenter.GetPresentation(req.UUID)
+	if err != nil {
+		if err.Error() == "presentation not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Presentation not found in ProPresenter"})
+		}
+		h.reportError(c, "Error fetching ProPresenter presentation", err)
+		return c.Status(502).JSON(fiber.Map{"error": err.Error()})
+	}
+	uuid := presentation.ID.UUID
+	if uuid == "" {
+		uuid = req.UUID
+	}
+
+	text, attribution := presentation.Lyrics()
+	if text == "" {
+		return c.Status(422).JSON(fiber.Map{"error": "Presentation has no slide text to import"})
+	}
+
+	if existing, err := h.db.GetSongByProUUID(uuid); err == nil {
+		return h.updateFromPresentation(c, existing, text, req.DryRun)
+	}
+
+	title := strings.TrimSpace(presentation.ID.Name)
+	if title == "" {
+		return c.Status(422).JSON(fiber.Map{"error": "Presentation has no name to use as the song title"})
+	}
+	create := models.CreateSongRequest{
+		Title:         title,
+		Library:       req.Library,
+		Language:      req.Language,
+		ProUUID:       &uuid,
+		DisplayLyrics: text,
+	}
+	if create.Library == "" {
+		create.Library = defaultImportLibrary
+	}
+	credit := lyrics.ParseCopyrightSlide(attribution)
+	if credit.Artist != "" {
+		create.Artist = &credit.Artist
+	}
+	if credit.Copyright != "" {
+		create.Copyright = &credit.Copyright
+	}
+	if credit.CCLINumber != "" {
+		create.CCLINumber = &credit.CCLINumber
+	}
+
+	normalizeSongRequest(&create)
+	h.formatSongRequest(&create)
+	languageWarning := detectSongLanguage(&create)
+	if rejected, err := h.rejectDuplicate(c, &create); rejected {
+		return err
+	}
+	if req.DryRun {
+		return c.JSON(fiber.Map{"action": "create", "dry_run": true, "song": create, "language_warning": languageWarning})
+	}
+
+	song, err := h.db.CreateSong(&create)
+	if err != nil {
+		log.Printf("Error creating song from ProPresenter presentation: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create song"})
+	}
+	h.songSaved(song)
+
+	song.LanguageWarning = languageWarning
+	song.DisplayWarnings = h.displayWarnings(song)
+	return c.Status(201).JSON(fiber.Map{"action": "created", "song": song})
+}
+
+// updateFromPresentation replaces a linked song's display lyrics with those
+// read from its presentation, recorded in the song's history like any edit
+func (h *Handler) updateFromPresentation(c *fiber.Ctx, song *models.Song, text string, dryRun bool) error {
+	update := models.UpdateSongRequest{DisplayLyrics: &text}
+	normalizeSongUpdate(&update)
+	h.formatSongUpdate(&update)
+	if *update.DisplayLyrics == song.DisplayLyrics {
+		return c.JSON(fiber.Map{"action": "unchanged", "song": song})
+	}
+	if h.review.RequireApproval && !h.isReviewer(c) {
+		return c.Status(403).JSON(fiber.Map{"error": "Song changes need a reviewer's approval; propose them at POST /api/songs/" + song.ID + "/edits"})
+	}
+	if dryRun {
+		song.DisplayLyrics = *update.DisplayLyrics
+		return c.JSON(fiber.Map{"action": "update", "dry_run": true, "song": song})
+	}
+
+	update.EditedBy = editorName(c)
+	update.RevisionNote = "Imported from ProPresenter"
+	updated, err := h.db.UpdateSong(song.ID, &update)
+	if err != nil {
+		h.reportError(c, "Error updating song from ProPresenter presentation", fmt.Errorf("song %s: %w", song.ID, err))
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update song"})
+	}
+	h.songSaved(updated)
+	updated.DisplayWarnings = h.displayWarnings(updated)
+	// Anyone editing the song together now works from what was just saved
+	h.collab.Reload(updated.ID, songDocument(updated))
+
+	return c.JSON(fiber.Map{"action": "updated", "song": updated})
+}
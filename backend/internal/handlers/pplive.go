@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProPresenterLive returns what ProPresenter is showing, as last reported by
+// its status stream, with the linked song when there is one
+func (h *Handler) ProPresenterLive(c *fiber.Ctx) error {
+	if h.propresenter == nil || !h.propresenter.IsEnabled() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+
+	status := h.propresenter.LiveStatus()
+	response := fiber.Map{"live": status}
+	if status.Presentation != nil {
+		if song, err := h.db.GetSongByProUUID(status.Presentation.UUID); err == nil {
+			response["song_id"] = song.ID
+		}
+	}
+	return c.JSON(response)
+}
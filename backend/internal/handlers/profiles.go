@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// maxDisplayLimit keeps a typo from disabling a check in practice
+const maxDisplayLimit = 500
+
+func validDisplayLimits(limits models.DisplayLimits) bool {
+	return limits.MaxCharsPerLine >= 0 && limits.MaxCharsPerLine <= maxDisplayLimit &&
+		limits.MaxLinesPerSlide >= 0 && limits.MaxLinesPerSlide <= maxDisplayLimit
+}
+
+// displayWarnings checks a saved song's display lyrics against the display
+// profiles. Failing to load the profiles only skips the check.
+func (h *Handler) displayWarnings(song *models.Song) []models.DisplayWarning {
+	profiles, err := h.db.GetDisplayProfiles()
+	if err != nil {
+		log.Printf("Error getting display profiles: %v", err)
+		return nil
+	}
+	warnings := lyrics.CheckDisplay(song.DisplayLyrics, song.Language, profiles)
+	if len(warnings) == 0 {
+		return nil
+	}
+	return warnings
+}
+
+// GetDisplayProfiles lists the display profiles lyrics are checked against
+func (h *Handler) GetDisplayProfiles(c *fiber.Ctx) error {
+	profiles, err := h.db.GetDisplayProfiles()
+	if err != nil {
+		log.Printf("Error getting display profiles: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get display profiles"})
+	}
+
+	return c.JSON(profiles)
+}
+
+// SaveDisplayProfile creates or replaces a display profile
+func (h *Handler) SaveDisplayProfile(c *fiber.Ctx) error {
+	name := strings.TrimSpace(c.Params("name"))
+	if name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Name is required"})
+	}
+
+	var req models.DisplayProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if !validDisplayLimits(req.DisplayLimits) {
+		return c.Status(400).JSON(fiber.Map{"error": "Limits must be between 0 (no limit) and 500"})
+	}
+	for lang, limits := range req.Languages {
+		if !validDisplayLimits(limits) {
+			return c.Status(400).JSON(fiber.Map{"error": "Limits for " + lang + " must be between 0 (no limit) and 500"})
+		}
+	}
+
+	profile, err := h.db.SaveDisplayProfile(name, &req)
+	if err != nil {
+		log.Printf("Error saving display profile: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save display profile"})
+	}
+
+	return c.JSON(profile)
+}
+
+// DeleteDisplayProfile removes a display profile
+func (h *Handler) DeleteDisplayProfile(c *fiber.Ctx) error {
+	if err := h.db.DeleteDisplayProfile(c.Params("name")); err != nil {
+		if err.Error() == "display profile not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Display profile not found"})
+		}
+		log.Printf("Error deleting display profile: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete display profile"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Display profile deleted successfully"})
+}
+
+// CheckLyricsDisplay checks unsaved lyrics against the display profiles
+func (h *Handler) CheckLyricsDisplay(c *fiber.Ctx) error {
+	var req struct {
+		Lyrics   string `json:"lyrics"`
+		Language string `json:"language"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	profiles, err := h.db.GetDisplayProfiles()
+	if err != nil {
+		log.Printf("Error getting display profiles: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get display profiles"})
+	}
+
+	return c.JSON(fiber.Map{"warnings": lyrics.CheckDisplay(req.Lyrics, req.Language, profiles)})
+}
+
+// CheckSongDisplay checks a song's display lyrics against the display profiles
+func (h *Handler) CheckSongDisplay(c *fiber.Ctx) error {
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	profiles, err := h.db.GetDisplayProfiles()
+	if err != nil {
+		log.Printf("Error getting display profiles: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get display profiles"})
+	}
+
+	return c.JSON(fiber.Map{"warnings": lyrics.CheckDisplay(song.DisplayLyrics, song.Language, profiles)})
+}
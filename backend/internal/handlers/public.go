@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// PublicAPIConfig controls access to the read-only public API used by the
+// church website and congregation-facing apps
+type PublicAPIConfig struct {
+	Tokens    []string // accepted as "Authorization: Bearer <token>" or ?token=
+	Anonymous bool     // allow requests without a token
+}
+
+// PublicAPI returns the middleware checking the public API token. Put
+// PublicAPILimit after it.
+func PublicAPI(cfg PublicAPIConfig) fiber.Handler {
+	tokenOf := func(c *fiber.Ctx) string {
+		if auth := c.Get(fiber.HeaderAuthorization); strings.HasPrefix(auth, "Bearer ") {
+			return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+		}
+		return c.Query("token")
+	}
+	valid := func(token string) bool {
+		for _, t := range cfg.Tokens {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *fiber.Ctx) error {
+		token := tokenOf(c)
+		if token == "" && cfg.Anonymous {
+			return c.Next()
+		}
+		if token == "" || !valid(token) {
+			return c.Status(401).JSON(fiber.Map{"error": "A valid API token is required"})
+		}
+		c.Locals("public_token", token)
+		return c.Next()
+	}
+}
+
+// PublicAPILimit returns the public API's rate limit, separate from the rest
+// of the API: perMinute requests per token, or per client IP for anonymous
+// requests (default 60)
+func PublicAPILimit(perMinute int) fiber.Handler {
+	if perMinute <= 0 {
+		perMinute = 60
+	}
+	return limiter.New(limiter.Config{
+		Max:        perMinute,
+		Expiration: time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			if token, ok := c.Locals("public_token").(string); ok {
+				return "token:" + token
+			}
+			return "ip:" + c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(429).JSON(fiber.Map{"error": "Rate limit exceeded, try again shortly"})
+		},
+	})
+}
+
+// publicSong is the subset of a song exposed on the public API
+type publicSong struct {
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Artist   *string `json:"artist,omitempty"`
+	Language string  `json:"language"`
+}
+
+func toPublicSong(song *models.Song) publicSong {
+	return publicSong{ID: song.ID, Title: song.Title, Artist: song.Artist, Language: song.Language}
+}
+
+// GetPublicSongs lists the songs approved for the public API
+func (h *Handler) GetPublicSongs(c *fiber.Ctx) error {
+	songs, err := h.db.GetPublicSongs(strings.TrimSpace(c.Query("q")), strings.TrimSpace(c.Query("language")))
+	if err != nil {
+		log.Printf("Error getting public songs: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get songs"})
+	}
+
+	result := make([]publicSong, len(songs))
+	for i := range songs {
+		result[i] = toPublicSong(&songs[i])
+	}
+	return c.JSON(fiber.Map{"songs": result})
+}
+
+// GetPublicSong returns an approved song's display lyrics as plain-text
+// sections. Unapproved songs are reported as not found.
+func (h *Handler) GetPublicSong(c *fiber.Ctx) error {
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil || !song.Public {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	sections := make([]fiber.Map, 0)
+	for _, section := range lyrics.ParseSections(song.DisplayLyrics) {
+		lines := make([]string, len(section.Lines))
+		for i, line := range section.Lines {
+			lines[i] = lyrics.PlainLine(line)
+		}
+		sections = append(sections, fiber.Map{"label": section.Label, "lines": lines})
+	}
+
+	response := fiber.Map{"song": toPublicSong(song), "sections": sections}
+	if settings, err := h.db.GetSettings(); err == nil {
+		if attribution := lyrics.CopyrightSlide(song, settings.CCLILicense); attribution != nil {
+			response["copyright"] = attribution
+		}
+	}
+	return c.JSON(response)
+}
+
+// GetPublicNowPlaying reports what is on screen. Song details and lines are
+// only included for approved songs; nothing is shown while blanked or during
+// rehearsal.
+func (h *Handler) GetPublicNowPlaying(c *fiber.Ctx) error {
+	current := h.live.Current()
+	if current == nil || h.live.IsBlanked() || h.live.IsRehearsal() {
+		return c.JSON(fiber.Map{"playing": false})
+	}
+
+	response := fiber.Map{"playing": true, "slide_index": current.SlideIndex, "updated_at": current.UpdatedAt}
+	switch {
+	case current.SongID != "":
+		song, err := h.db.GetSong(current.SongID)
+		if err != nil || !song.Public {
+			break
+		}
+		response["song"] = toPublicSong(song)
+		if lines := slideLines(current, song); lines != nil {
+			response["lines"] = lines
+		}
+	case len(current.Slides) > 0:
+		// Content outside the song library (scripture) is shown as-is
+		response["title"] = current.Title
+		if lines := slideLines(current, nil); lines != nil {
+			response["lines"] = lines
+		}
+	}
+	return c.JSON(response)
+}
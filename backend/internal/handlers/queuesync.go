@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// The queue monitor keeps our queue in step with the Live Queue playlist when
+// the ProPresenter operator changes it directly. Each check compares the
+// playlist with the previous check: presentations added in ProPresenter are
+// added to the queue, and ones removed there are removed here. Songs queued
+// here but never sent to ProPresenter are left alone, and the first check
+// only records the playlist, so a restart never rewrites the queue.
+
+const emptyPlaylistUUID = "00000000-0000-0000-0000-000000000000"
+
+// QueueSyncStatus is the result of the last queue check
+type QueueSyncStatus struct {
+	Enabled   bool       `json:"enabled"`
+	Interval  int        `json:"interval_seconds,omitempty"`
+	Playlist  string     `json:"playlist,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	Added     []string   `json:"added"`    // songs added to the queue at the last check
+	Removed   []string   `json:"removed"`  // songs removed from the queue at the last check
+	Unlinked  []string   `json:"unlinked"` // playlist items not linked to a song
+	Error     string     `json:"error,omitempty"`
+}
+
+// queueSync is the monitor's memory between checks
+type queueSync struct {
+	mu       sync.Mutex
+	seen     map[string]bool // presentation UUIDs in the playlist at the last check, nil before the first
+	playlist string          // UUID the snapshot was taken from
+	status   QueueSyncStatus
+}
+
+// StartQueueMonitor checks the Live Queue playlist every interval
+func (h *Handler) StartQueueMonitor(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	h.queueSync.mu.Lock()
+	h.queueSync.status.Enabled = true
+	h.queueSync.status.Interval = int(interval / time.Second)
+	h.queueSync.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := h.syncQueue(); err != nil {
+				log.Printf("Error checking the ProPresenter queue playlist: %v", err)
+			}
+		}
+	}()
+}
+
+// syncQueue compares the playlist with the last check and applies the
+// difference to the queue. A disconnected ProPresenter or rehearsal mode
+// (which uses another playlist) skips the check.
+func (h *Handler) syncQueue() (QueueSyncStatus, error) {
+	h.queueSync.mu.Lock()
+	defer h.queueSync.mu.Unlock()
+
+	if h.propresenter == nil || !h.propresenter.IsEnabled() || !h.propresenter.IsConnected() || h.live.IsRehearsal() {
+		return h.queueSync.status, nil
+	}
+
+	status := h.queueSync.status
+	now := time.Now()
+	status.CheckedAt = &now
+	status.Added = make([]string, 0)
+	status.Removed = make([]string, 0)
+	status.Unlinked = make([]string, 0)
+	status.Error = ""
+
+	fail := func(err error) (QueueSyncStatus, error) {
+		status.Error = err.Error()
+		h.queueSync.status = status
+		return status, err
+	}
+
+	settings, err := h.db.GetSettings()
+	if err != nil {
+		return fail(err)
+	}
+	playlistUUID, name, err := h.queuePlaylist(settings)
+	if err != nil {
+		return fail(err)
+	}
+	status.Playlist = name
+
+	playlist, err := h.propresenter.GetPlaylist(playlistUUID)
+	if err != nil {
+		return fail(err)
+	}
+	queue, err := h.db.GetQueue()
+	if err != nil {
+		return fail(err)
+	}
+
+	current := make(map[string]bool)
+	for _, item := range playlist.Items {
+		if item.Type != "" && item.Type != "presentation" {
+			continue
+		}
+		current[strings.ToLower(item.ID.UUID)] = true
+	}
+	queued := make(map[string]models.QueueItem)
+	for _, item := range queue {
+		if item.Song != nil && item.Song.ProUUID != nil {
+			queued[strings.ToLower(*item.Song.ProUUID)] = item
+		}
+	}
+
+	// A different playlist (or the first check) starts a new snapshot
+	previous := h.queueSync.seen
+	if h.queueSync.playlist != playlistUUID {
+		previous = nil
+	}
+
+	for _, item := range playlist.Items {
+		uuid := strings.ToLower(item.ID.UUID)
+		if !current[uuid] {
+			continue
+		}
+		if _, ok := queued[uuid]; ok {
+			continue
+		}
+		song, err := h.db.GetSongByProUUID(uuid)
+		if err != nil {
+			status.Unlinked = append(status.Unlinked, item.ID.Name)
+			continue
+		}
+		if previous == nil || previous[uuid] {
+			continue
+		}
+		if _, err := h.db.AddToQueue(song.ID); err != nil && err.Error() != "song already in queue" {
+			return fail(err)
+		}
+		status.Added = append(status.Added, song.Title)
+	}
+
+	for uuid := range previous {
+		item, ok := queued[uuid]
+		if !ok || current[uuid] {
+			continue
+		}
+		if err := h.db.RemoveFromQueue(item.ID); err != nil && err.Error() != "queue item not found" {
+			return fail(err)
+		}
+		status.Removed = append(status.Removed, item.Song.Title)
+	}
+
+	if len(status.Added) > 0 || len(status.Removed) > 0 {
+		log.Printf("🔄 Queue updated from ProPresenter playlist %q: %d added, %d removed", name, len(status.Added), len(status.Removed))
+	}
+
+	h.queueSync.seen = current
+	h.queueSync.playlist = playlistUUID
+	h.queueSync.status = status
+	return status, nil
+}
+
+// queuePlaylist resolves the Live Queue playlist the same way sending a song
+// to the queue does, but never creates it
+func (h *Handler) queuePlaylist(settings *models.Settings) (uuid, name string, err error) {
+	name = settings.ProPresenterPlaylist
+	if name == "" {
+		name = "Live Queue"
+	}
+
+	uuid = settings.ProPresenterPlaylistUUID
+	if uuid == "" || uuid == emptyPlaylistUUID {
+		uuid = settings.LivePlaylistUUID
+	}
+	if uuid != "" && uuid != emptyPlaylistUUID {
+		return uuid, name, nil
+	}
+
+	playlist, err := h.propresenter.FindPlaylist(name)
+	if err != nil {
+		return "", name, fmt.Errorf("queue playlist %q not found in ProPresenter", name)
+	}
+	return playlist.ID.UUID, name, nil
+}
+
+// GetQueueSync returns the result of the last queue check
+func (h *Handler) GetQueueSync(c *fiber.Ctx) error {
+	h.queueSync.mu.Lock()
+	defer h.queueSync.mu.Unlock()
+	return c.JSON(h.queueSync.status)
+}
+
+// SyncQueue checks the Live Queue playlist now
+func (h *Handler) SyncQueue(c *fiber.Ctx) error {
+	if h.propresenter == nil || !h.propresenter.IsEnabled() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+	if !h.propresenter.IsConnected() {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter is not connected"})
+	}
+
+	status, err := h.syncQueue()
+	if err != nil {
+		h.reportError(c, "Error checking the ProPresenter queue playlist", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error(), "status": status})
+	}
+	return c.JSON(status)
+}
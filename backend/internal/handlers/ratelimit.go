@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// APILimit rate-limits the API per client IP, with separate budgets per
+// minute for reads (GET and HEAD) and writes, so tablets searching during a
+// service can't use up what the booth needs to save and send songs. A limit
+// of 0 turns that budget off.
+func APILimit(readsPerMinute, writesPerMinute int) fiber.Handler {
+	reads := apiLimiter("read", readsPerMinute)
+	writes := apiLimiter("write", writesPerMinute)
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return reads(c)
+		}
+		return writes(c)
+	}
+}
+
+// apiLimiter is one of APILimit's budgets. Its 429 says which budget ran out
+// and when to try again, as does the Retry-After header.
+func apiLimiter(kind string, perMinute int) fiber.Handler {
+	if perMinute <= 0 {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	return limiter.New(limiter.Config{
+		Max:        perMinute,
+		Expiration: time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return kind + ":" + c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			retryAfter, _ := strconv.Atoi(c.GetRespHeader(fiber.HeaderRetryAfter))
+			return c.Status(429).JSON(fiber.Map{
+				"error":       "Rate limit exceeded, try again shortly",
+				"limit":       kind,
+				"per_minute":  perMinute,
+				"retry_after": retryAfter,
+			})
+		},
+	})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+)
+
+// GetRehearsalMode reports whether rehearsal mode is on and which playlist it uses
+func (h *Handler) GetRehearsalMode(c *fiber.Ctx) error {
+	response := fiber.Map{"rehearsal": h.live.IsRehearsal()}
+
+	if settings, err := h.db.GetSettings(); err == nil {
+		response["playlist"] = settings.RehearsalPlaylist
+	}
+
+	return c.JSON(response)
+}
+
+// SetRehearsalMode turns rehearsal mode on or off. While it is on, queue actions
+// go to the rehearsal playlist and live state is marked non-production.
+func (h *Handler) SetRehearsalMode(c *fiber.Ctx) error {
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Enabled == nil {
+		return c.Status(400).JSON(fiber.Map{"error": "enabled is required"})
+	}
+
+	changed := h.live.SetRehearsal(*req.Enabled)
+	if changed && *req.Enabled {
+		log.Println("Rehearsal mode enabled")
+	} else if changed {
+		log.Println("Rehearsal mode disabled")
+	}
+
+	return c.JSON(fiber.Map{
+		"success":   true,
+		"rehearsal": *req.Enabled,
+		"changed":   changed,
+	})
+}
+
+// sendToRehearsalPlaylist adds a song to the rehearsal playlist, creating it if needed.
+// Unlike the Live Queue path it never writes the playlist UUID back to settings.
+func (h *Handler) sendToRehearsalPlaylist(c *fiber.Ctx, pp *propresenter.Client, dry *propresenter.DryRunLog, song *models.Song, settings *models.Settings) error {
+	playlistName := settings.RehearsalPlaylist
+	if playlistName == "" {
+		playlistName = "Rehearsal"
+	}
+
+	playlist, err := pp.FindOrCreatePlaylist(playlistName)
+	if err != nil {
+		log.Printf("Error finding rehearsal playlist: %v", err)
+		return c.Status(503).JSON(fiber.Map{
+			"error":      "Failed to sync with ProPresenter",
+			"message":    err.Error(),
+			"song_title": song.Title,
+			"playlist":   playlistName,
+		})
+	}
+
+	if err := pp.AddToPlaylist(playlist.ID.UUID, *song.ProUUID); err != nil {
+		log.Printf("Error adding song to rehearsal playlist: %v", err)
+		return c.Status(503).JSON(fiber.Map{
+			"error":      "Failed to sync with ProPresenter",
+			"message":    err.Error(),
+			"song_title": song.Title,
+			"playlist":   playlistName,
+		})
+	}
+
+	response := fiber.Map{
+		"success":      true,
+		"message":      "Song added to rehearsal playlist",
+		"song_title":   song.Title,
+		"playlist":     playlistName,
+		"pp_item_uuid": *song.ProUUID,
+		"rehearsal":    true,
+	}
+	if dry != nil {
+		response["message"] = "Dry run: song would be added to rehearsal playlist"
+		return dryRunResponse(c, dry, response)
+	}
+	return c.JSON(response)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrInvalidConfig is returned by a ConfigReloader when the new
+// configuration has a mistake; nothing is applied then
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ConfigReloader reads the configuration again and applies it to the running
+// server, returning the names of the settings that changed
+type ConfigReloader func() ([]string, error)
+
+// SetConfigReloader sets what ReloadConfig runs
+func (h *Handler) SetConfigReloader(reload ConfigReloader) {
+	h.reloadConfig = reload
+}
+
+// ReloadConfig applies changes to the configuration without a restart, the
+// same as sending the server SIGHUP
+func (h *Handler) ReloadConfig(c *fiber.Ctx) error {
+	if h.reloadConfig == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Configuration reload is not available"})
+	}
+	changed, err := h.reloadConfig()
+	if errors.Is(err, ErrInvalidConfig) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		h.reportError(c, "Error reloading configuration", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reload configuration", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "Configuration reloaded", "changed": changed})
+}
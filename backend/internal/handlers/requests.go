@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// SongRequestLimit rate-limits song request submissions per client IP to
+// perHour (default 5)
+func SongRequestLimit(perHour int) fiber.Handler {
+	if perHour <= 0 {
+		perHour = 5
+	}
+	return limiter.New(limiter.Config{
+		Max:        perHour,
+		Expiration: time.Hour,
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(429).JSON(fiber.Map{"error": "Too many requests, please try again later"})
+		},
+	})
+}
+
+// voterKey identifies a client for vote counting without storing its IP
+func voterKey(c *fiber.Ctx) string {
+	sum := sha256.Sum256([]byte("song-request-voter:" + c.IP()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// requestTitleKey normalizes a requested title so "Amazing Grace!" and
+// "amazing grace" collapse into one request
+func requestTitleKey(title string) string {
+	if key := normalizeLyricLine(title); key != "" {
+		return key
+	}
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// publicSongRequest is what the congregation sees of a request; messages and
+// names stay in the admin inbox
+type publicSongRequest struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Votes  int    `json:"votes"`
+}
+
+// SubmitSongRequest takes a song request from the congregation: either a
+// song picked from the public catalog or a free-text title. It lands in the
+// admin inbox as pending, or counts as a vote when the same song has already
+// been requested.
+func (h *Handler) SubmitSongRequest(c *fiber.Ctx) error {
+	var req models.SubmitSongRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	req.Title = strings.TrimSpace(req.Title)
+	req.Message = strings.TrimSpace(req.Message)
+	req.RequesterName = strings.TrimSpace(req.RequesterName)
+	if utf8.RuneCountInString(req.Title) > 200 {
+		return c.Status(400).JSON(fiber.Map{"error": "title must be 200 characters or fewer"})
+	}
+	if utf8.RuneCountInString(req.Message) > 500 {
+		return c.Status(400).JSON(fiber.Map{"error": "message must be 500 characters or fewer"})
+	}
+	if utf8.RuneCountInString(req.RequesterName) > 100 {
+		return c.Status(400).JSON(fiber.Map{"error": "requester_name must be 100 characters or fewer"})
+	}
+
+	var songID *string
+	if id := strings.TrimSpace(req.SongID); id != "" {
+		// Only songs approved for the public catalog can be picked
+		song, err := h.db.GetSong(id)
+		if err != nil || !song.Public {
+			return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+		}
+		songID = &song.ID
+		req.Title = song.Title
+	}
+	if req.Title == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "song_id or title is required"})
+	}
+
+	request, duplicate, err := h.db.CreateSongRequest(songID, req.Title, requestTitleKey(req.Title), req.Message, req.RequesterName, voterKey(c))
+	if err != nil {
+		log.Printf("Error creating song request: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to submit request"})
+	}
+
+	status := 201
+	if duplicate {
+		status = 200
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"id":        request.ID,
+		"title":     request.Title,
+		"status":    request.Status,
+		"votes":     request.Votes,
+		"duplicate": duplicate,
+	})
+}
+
+// GetOpenSongRequests lists pending and planned requests for the congregation
+// to vote on, most voted first
+func (h *Handler) GetOpenSongRequests(c *fiber.Ctx) error {
+	requests, err := h.db.GetSongRequests([]string{models.SongRequestPending, models.SongRequestPlanned}, false)
+	if err != nil {
+		log.Printf("Error getting song requests: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get song requests"})
+	}
+
+	result := make([]publicSongRequest, len(requests))
+	for i, r := range requests {
+		result[i] = publicSongRequest{ID: r.ID, Title: r.Title, Status: r.Status, Votes: r.Votes}
+	}
+	return c.JSON(result)
+}
+
+// VoteSongRequest upvotes an open request, once per client
+func (h *Handler) VoteSongRequest(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request ID"})
+	}
+
+	request, added, err := h.db.VoteSongRequest(id, voterKey(c))
+	if err != nil {
+		switch err.Error() {
+		case "song request not found", "song request closed":
+			return c.Status(404).JSON(fiber.Map{"error": "Song request not found"})
+		}
+		log.Printf("Error voting for song request: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to record vote"})
+	}
+
+	return c.JSON(fiber.Map{"id": request.ID, "votes": request.Votes, "voted": added})
+}
+
+// GetSongRequests lists the request inbox, pending requests by default
+// (?status=all for everything), most voted first or ?sort=oldest
+func (h *Handler) GetSongRequests(c *fiber.Ctx) error {
+	var statuses []string
+	switch status := c.Query("status", models.SongRequestPending); status {
+	case "all":
+	case models.SongRequestPending, models.SongRequestPlanned, models.SongRequestSung, models.SongRequestRejected:
+		statuses = []string{status}
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "status must be pending, planned, sung, rejected or all"})
+	}
+
+	requests, err := h.db.GetSongRequests(statuses, c.Query("sort") == "oldest")
+	if err != nil {
+		log.Printf("Error getting song requests: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get song requests"})
+	}
+	return c.JSON(requests)
+}
+
+// AcceptSongRequest adds the requested song to the end of a setlist. Free-text
+// requests need song_id to say which library song they mean.
+func (h *Handler) AcceptSongRequest(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request ID"})
+	}
+
+	var req models.AcceptSongRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.SetlistID <= 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "setlist_id is required"})
+	}
+
+	request, err := h.db.GetSongRequest(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song request not found"})
+	}
+
+	songID := strings.TrimSpace(req.SongID)
+	if songID == "" && request.SongID != nil {
+		songID = *request.SongID
+	}
+	if songID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "song_id is required for requests not picked from the catalog"})
+	}
+	if _, err := h.db.GetSong(songID); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	accepted, err := h.db.AcceptSongRequest(id, songID, req.SetlistID)
+	if err != nil {
+		switch err.Error() {
+		case "setlist not found":
+			return c.Status(404).JSON(fiber.Map{"error": "Setlist not found"})
+		case "song request not pending":
+			return c.Status(409).JSON(fiber.Map{"error": "Song request is not pending"})
+		}
+		log.Printf("Error accepting song request: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to accept song request"})
+	}
+	return c.JSON(accepted)
+}
+
+// RejectSongRequest declines a request
+func (h *Handler) RejectSongRequest(c *fiber.Ctx) error {
+	return h.setSongRequestStatus(c, models.SongRequestRejected)
+}
+
+// UpdateSongRequestStatus moves a request to another status (status in the
+// body), e.g. to reopen it or mark it sung by hand
+func (h *Handler) UpdateSongRequestStatus(c *fiber.Ctx) error {
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	switch req.Status {
+	case models.SongRequestPending, models.SongRequestPlanned, models.SongRequestSung, models.SongRequestRejected:
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "status must be pending, planned, sung or rejected"})
+	}
+	return h.setSongRequestStatus(c, req.Status)
+}
+
+func (h *Handler) setSongRequestStatus(c *fiber.Ctx, status string) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request ID"})
+	}
+
+	request, err := h.db.SetSongRequestStatus(id, status)
+	if err != nil {
+		if err.Error() == "song request not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Song request not found"})
+		}
+		log.Printf("Error updating song request: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update song request"})
+	}
+	return c.JSON(request)
+}
+
+// ConvertSongRequest creates a draft song for a request that isn't in the
+// library yet and links the request to it. The draft has no lyrics and stays
+// out of the public catalog until someone finishes it.
+func (h *Handler) ConvertSongRequest(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request ID"})
+	}
+
+	var req models.ConvertSongRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	request, err := h.db.GetSongRequest(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song request not found"})
+	}
+	if request.SongID != nil {
+		return c.Status(409).JSON(fiber.Map{"error": "Song request already refers to a library song", "song_id": *request.SongID})
+	}
+
+	draft := models.CreateSongRequest{
+		Title:    strings.TrimSpace(req.Title),
+		Artist:   req.Artist,
+		Language: strings.TrimSpace(req.Language),
+		Library:  strings.TrimSpace(req.Library),
+	}
+	if draft.Title == "" {
+		draft.Title = request.Title
+	}
+	if draft.Language == "" {
+		draft.Language = "english"
+	}
+	if draft.Library == "" {
+		draft.Library = "Requests"
+	}
+	normalizeSongRequest(&draft)
+
+	song, err := h.db.CreateSong(&draft)
+	if err != nil {
+		log.Printf("Error creating draft song: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create song"})
+	}
+	if !h.skipTypesense && h.ts != nil {
+		if err := h.ts.IndexSong(song); err != nil {
+			h.reportError(c, "Error indexing song in Typesense", err)
+		}
+	}
+	h.backupManager.RecordEdits(1)
+
+	request, err = h.db.LinkSongRequest(id, song.ID)
+	if err != nil {
+		log.Printf("Error linking song request: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Song created but the request could not be linked", "song": song})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"song": song, "request": request})
+}
+
+// DeleteSongRequest removes a request from the inbox, e.g. spam
+func (h *Handler) DeleteSongRequest(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request ID"})
+	}
+
+	if err := h.db.DeleteSongRequest(id); err != nil {
+		if err.Error() == "song request not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Song request not found"})
+		}
+		log.Printf("Error deleting song request: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete song request"})
+	}
+	return c.JSON(fiber.Map{"message": "Song request deleted successfully"})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// songRevision is a revision with what changed from the one before it
+type songRevision struct {
+	models.SongRevision
+	Changed                 []string          `json:"changed"` // title, artist, language, display_lyrics, music_ministry_lyrics
+	DisplayLyricsDiff       []lyrics.LineDiff `json:"display_lyrics_diff,omitempty"`
+	MusicMinistryLyricsDiff []lyrics.LineDiff `json:"music_ministry_lyrics_diff,omitempty"`
+}
+
+// editorName is who is making a change, for the revision history: the
+// signed-in user, or whoever the X-Operator header names
+func editorName(c *fiber.Ctx) string {
+	if user := currentUser(c); user != nil {
+		return user.Name()
+	}
+	return strings.TrimSpace(c.Get(operatorHeader))
+}
+
+// compareRevisions describes how a revision differs from the previous one
+func compareRevisions(previous, revision *models.SongRevision) songRevision {
+	result := songRevision{SongRevision: *revision, Changed: make([]string, 0)}
+	if previous == nil {
+		return result
+	}
+	if previous.Title != revision.Title {
+		result.Changed = append(result.Changed, "title")
+	}
+	if stringValue(previous.Artist) != stringValue(revision.Artist) {
+		result.Changed = append(result.Changed, "artist")
+	}
+	if previous.Language != revision.Language {
+		result.Changed = append(result.Changed, "language")
+	}
+	if previous.DisplayLyrics != revision.DisplayLyrics {
+		result.Changed = append(result.Changed, "display_lyrics")
+		result.DisplayLyricsDiff = lyrics.DiffLines(previous.DisplayLyrics, revision.DisplayLyrics)
+	}
+	if previous.MusicMinistryLyrics != revision.MusicMinistryLyrics {
+		result.Changed = append(result.Changed, "music_ministry_lyrics")
+		result.MusicMinistryLyricsDiff = lyrics.DiffLines(previous.MusicMinistryLyrics, revision.MusicMinistryLyrics)
+	}
+	return result
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// GetSongRevisions lists the versions of a song's words, newest first, each
+// with the lines added and removed since the version before
+func (h *Handler) GetSongRevisions(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+	revisions, err := h.db.GetSongRevisions(id)
+	if err != nil {
+		log.Printf("Error getting song revisions: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get song revisions"})
+	}
+
+	result := make([]songRevision, len(revisions))
+	for i := range revisions {
+		var previous *models.SongRevision
+		if i+1 < len(revisions) {
+			previous = &revisions[i+1]
+		}
+		result[i] = compareRevisions(previous, &revisions[i])
+	}
+	return c.JSON(result)
+}
+
+// RestoreSongRevision puts a song's title, artist, language and lyrics back
+// as they were in a revision. Restoring saves a new revision, so it can be
+// undone the same way.
+func (h *Handler) RestoreSongRevision(c *fiber.Ctx) error {
+	id := c.Params("id")
+	number, err := strconv.Atoi(c.Params("rev"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid revision"})
+	}
+	revision, err := h.db.GetSongRevision(id, number)
+	if err != nil {
+		if err.Error() == "song revision not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Song revision not found"})
+		}
+		log.Printf("Error getting song revision: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get song revision"})
+	}
+
+	req := models.UpdateSongRequest{
+		Title:               &revision.Title,
+		Language:            &revision.Language,
+		DisplayLyrics:       &revision.DisplayLyrics,
+		MusicMinistryLyrics: &revision.MusicMinistryLyrics,
+		RevisionNote:        fmt.Sprintf("Restored revision %d", number),
+	}
+	if revision.Artist != nil {
+		req.Artist = revision.Artist
+	} else {
+		req.Clear = []string{"artist"}
+	}
+	return h.saveSongUpdate(c, id, &req)
+}
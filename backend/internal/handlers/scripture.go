@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/live"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+	"github.com/yourusername/audience-stage-teleprompter/internal/scripture"
+)
+
+// Where a passage can be presented
+const (
+	scriptureTargetBoth         = "both"
+	scriptureTargetTeleprompter = "teleprompter"
+	scriptureTargetProPresenter = "propresenter"
+)
+
+// GetScripture looks up a passage and shows how it splits into slides
+// (?ref=John 3:16-18&translation=kjv&max_chars=300)
+func (h *Handler) GetScripture(c *fiber.Ctx) error {
+	passage, slides, status, errMsg := h.fetchScripture(c.Query("ref"), c.Query("translation"), c.QueryInt("max_chars"))
+	if errMsg != "" {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+
+	return c.JSON(fiber.Map{
+		"passage": passage,
+		"slides":  slides,
+		"count":   len(slides),
+	})
+}
+
+// PresentScripture puts a passage on the teleprompter displays, into the
+// ProPresenter playlist, or both. With trigger set the ProPresenter
+// presentation also goes live.
+func (h *Handler) PresentScripture(c *fiber.Ctx) error {
+	var req struct {
+		Reference   string `json:"reference"`
+		Translation string `json:"translation"`
+		MaxChars    int    `json:"max_chars"`
+		Target      string `json:"target"` // "both" (default), "teleprompter" or "propresenter"
+		Trigger     bool   `json:"trigger"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	switch req.Target {
+	case "":
+		req.Target = scriptureTargetBoth
+	case scriptureTargetBoth, scriptureTargetTeleprompter, scriptureTargetProPresenter:
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "target must be both, teleprompter or propresenter"})
+	}
+
+	toProPresenter := req.Target != scriptureTargetTeleprompter
+	if toProPresenter && (h.propresenter == nil || !h.propresenter.IsEnabled()) {
+		return c.Status(503).JSON(fiber.Map{"error": "ProPresenter integration is not enabled"})
+	}
+
+	passage, slides, status, errMsg := h.fetchScripture(req.Reference, req.Translation, req.MaxChars)
+	if errMsg != "" {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+
+	title := fmt.Sprintf("%s (%s)", passage.Reference, passage.Translation)
+	texts := make([]string, len(slides))
+	for i, s := range slides {
+		texts[i] = s.Text
+	}
+	response := fiber.Map{
+		"success": true,
+		"title":   title,
+		"slides":  len(slides),
+	}
+
+	var presentationUUID string
+	if toProPresenter {
+		item, playlist, err := h.sendScriptureToProPresenter(title, texts, req.Trigger)
+		if err != nil {
+			h.reportError(c, "Error sending scripture to ProPresenter", err)
+			h.recordServiceEvent(c, models.ServiceEventError, "", title, "scripture failed: "+err.Error())
+			return c.Status(503).JSON(fiber.Map{
+				"error":   "Failed to sync with ProPresenter",
+				"message": err.Error(),
+				"title":   title,
+			})
+		}
+		presentationUUID = item.ID.UUID
+		response["playlist"] = playlist
+		response["pp_item_uuid"] = presentationUUID
+	}
+
+	// Displays show the slide text directly since there is no song to load
+	if req.Target != scriptureTargetProPresenter || req.Trigger {
+		h.advance.Stop()
+		h.live.SetCurrent(live.NowShowing{
+			Title:            title,
+			PresentationUUID: presentationUUID,
+			Slides:           texts,
+		})
+		h.publishStageNotes("")
+		h.publishStageCues("")
+		h.recordServiceEvent(c, models.ServiceEventTrigger, "", title, "")
+	}
+
+	return c.JSON(response)
+}
+
+// fetchScripture looks up a passage and splits it into slides. On failure it
+// returns the status code and message to respond with.
+func (h *Handler) fetchScripture(ref, translation string, maxChars int) (*scripture.Passage, []scripture.Slide, int, string) {
+	if ref == "" {
+		return nil, nil, 400, "reference is required"
+	}
+	if maxChars < 0 || maxChars > 2000 {
+		return nil, nil, 400, "max_chars must be between 1 and 2000"
+	}
+
+	passage, err := h.scripture.Fetch(ref, translation)
+	if errors.Is(err, scripture.ErrNotFound) {
+		return nil, nil, 404, "Passage not found"
+	}
+	if err != nil {
+		log.Printf("Error fetching scripture %q: %v", ref, err)
+		return nil, nil, 502, "Failed to fetch passage"
+	}
+
+	return passage, scripture.Slides(passage, maxChars), 0, ""
+}
+
+// sendScriptureToProPresenter reuses or creates a presentation for the passage
+// and adds it to the Live Queue, or the rehearsal playlist in rehearsal mode
+func (h *Handler) sendScriptureToProPresenter(title string, texts []string, trigger bool) (*propresenter.LibraryItem, string, error) {
+	item, err := h.propresenter.FindSongByTitle(title)
+	if err != nil {
+		item, err = h.propresenter.CreateSlidePresentation(title, "Scripture", texts)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	settings, err := h.db.GetSettings()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to retrieve settings: %w", err)
+	}
+	playlistName := settings.ProPresenterPlaylist
+	if h.live.IsRehearsal() {
+		playlistName = settings.RehearsalPlaylist
+		if playlistName == "" {
+			playlistName = "Rehearsal"
+		}
+	} else if playlistName == "" {
+		playlistName = "Live Queue"
+	}
+
+	playlist, err := h.propresenter.FindOrCreatePlaylist(playlistName)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := h.propresenter.AddToPlaylist(playlist.ID.UUID, item.ID.UUID); err != nil {
+		return nil, "", err
+	}
+
+	if trigger {
+		if err := h.propresenter.TriggerLibraryItem(item.ID.UUID); err != nil {
+			return nil, "", err
+		}
+	}
+	return item, playlistName, nil
+}
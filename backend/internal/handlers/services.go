@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/pdf"
+)
+
+// operatorHeader identifies who is running the service from the control UI
+const operatorHeader = "X-Operator"
+
+// StartService begins a new service; triggers and ProPresenter errors are logged against it
+func (h *Handler) StartService(c *fiber.Ctx) error {
+	var req models.CreateServiceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		req.Name = "Service " + time.Now().Format("2006-01-02")
+	}
+	if req.ServiceDate != "" {
+		if _, err := time.Parse("2006-01-02", req.ServiceDate); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "service_date must be a date (YYYY-MM-DD)"})
+		}
+	}
+
+	service, err := h.db.CreateService(&req)
+	if err != nil {
+		if err.Error() == "a service is already active" {
+			return c.Status(409).JSON(fiber.Map{"error": "A service is already active; archive it first"})
+		}
+		log.Printf("Error creating service: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to start service"})
+	}
+
+	return c.Status(201).JSON(service)
+}
+
+// GetServices lists all services
+func (h *Handler) GetServices(c *fiber.Ctx) error {
+	services, err := h.db.GetServices()
+	if err != nil {
+		log.Printf("Error getting services: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get services"})
+	}
+
+	return c.JSON(services)
+}
+
+// GetActiveService returns the running service
+func (h *Handler) GetActiveService(c *fiber.Ctx) error {
+	service, err := h.db.GetActiveService()
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "No active service"})
+	}
+
+	return c.JSON(service)
+}
+
+// GetService returns a service with its event log
+func (h *Handler) GetService(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid service ID"})
+	}
+
+	service, err := h.db.GetService(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Service not found"})
+	}
+
+	events, err := h.db.GetServiceEvents(id)
+	if err != nil {
+		log.Printf("Error getting service events: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get service events"})
+	}
+
+	return c.JSON(fiber.Map{
+		"service": service,
+		"events":  events,
+	})
+}
+
+// ArchiveService closes the service and generates its summary report
+func (h *Handler) ArchiveService(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid service ID"})
+	}
+
+	report, err := h.db.ArchiveService(id)
+	if err != nil {
+		switch err.Error() {
+		case "service not found":
+			return c.Status(404).JSON(fiber.Map{"error": "Service not found"})
+		case "service already archived":
+			return c.Status(409).JSON(fiber.Map{"error": "Service is already archived"})
+		}
+		log.Printf("Error archiving service: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to archive service"})
+	}
+
+	log.Printf("Service %d archived: %d songs, %d errors", id, len(report.Songs), len(report.Errors))
+	return c.JSON(report)
+}
+
+// GetServiceReport returns an archived service's report as JSON, or as PDF with ?format=pdf
+func (h *Handler) GetServiceReport(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid service ID"})
+	}
+
+	report, err := h.db.GetServiceReport(id)
+	if err != nil {
+		switch err.Error() {
+		case "service not found":
+			return c.Status(404).JSON(fiber.Map{"error": "Service not found"})
+		case "report not found":
+			return c.Status(404).JSON(fiber.Map{"error": "Report is generated when the service is archived"})
+		}
+		log.Printf("Error getting service report: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get service report"})
+	}
+
+	switch c.Query("format", "json") {
+	case "json":
+		return c.JSON(report)
+	case "pdf":
+		c.Set("Content-Type", "application/pdf")
+		c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="service-%d-report.pdf"`, id))
+		return c.Send(renderServiceReportPDF(report))
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "format must be json or pdf"})
+	}
+}
+
+// renderServiceReportPDF lays a service report out as a printable document
+func renderServiceReportPDF(report *models.ServiceReport) []byte {
+	doc := pdf.New(report.Name)
+	doc.Text(fmt.Sprintf("Service date: %s", report.ServiceDate))
+	doc.Text(fmt.Sprintf("Started: %s    Archived: %s",
+		report.StartedAt.Local().Format("15:04"), report.ArchivedAt.Local().Format("15:04")))
+	if len(report.Operators) > 0 {
+		doc.Text("Operators: " + strings.Join(report.Operators, ", "))
+	}
+	doc.Blank()
+
+	doc.Heading(fmt.Sprintf("Songs (%d)", len(report.Songs)))
+	if len(report.Songs) == 0 {
+		doc.Text("No songs were triggered.")
+	}
+	for _, song := range report.Songs {
+		times := make([]string, len(song.TriggeredAt))
+		for i, t := range song.TriggeredAt {
+			times[i] = t.Local().Format("15:04")
+		}
+		doc.Text(fmt.Sprintf("%d. %s  (%s)", song.Position, song.Title, strings.Join(times, ", ")))
+	}
+	doc.Blank()
+
+	doc.Heading(fmt.Sprintf("ProPresenter errors (%d)", len(report.Errors)))
+	if len(report.Errors) == 0 {
+		doc.Text("None.")
+	}
+	for _, event := range report.Errors {
+		doc.Text(fmt.Sprintf("%s  %s", event.OccurredAt.Local().Format("15:04:05"), event.Message))
+	}
+
+	return doc.Bytes()
+}
+
+// recordServiceEvent logs an event against the active service, if there is one.
+// Rehearsals are never recorded.
+func (h *Handler) recordServiceEvent(c *fiber.Ctx, eventType, songID, title, message string) {
+	if h.live.IsRehearsal() {
+		return
+	}
+
+	service, err := h.db.GetActiveService()
+	if err != nil {
+		return
+	}
+
+	event := &models.ServiceEvent{
+		ServiceID: service.ID,
+		EventType: eventType,
+		Title:     title,
+		Operator:  strings.TrimSpace(c.Get(operatorHeader)),
+		Message:   message,
+	}
+	if songID != "" {
+		event.SongID = &songID
+	}
+
+	if err := h.db.AddServiceEvent(event); err != nil {
+		log.Printf("Error recording service event: %v", err)
+	}
+}
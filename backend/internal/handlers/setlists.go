@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/export"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetSetlists lists all setlists
+func (h *Handler) GetSetlists(c *fiber.Ctx) error {
+	setlists, err := h.db.GetSetlists()
+	if err != nil {
+		log.Printf("Error getting setlists: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get setlists"})
+	}
+
+	return c.JSON(setlists)
+}
+
+// GetSetlistCalendar publishes upcoming dated setlists as an iCal feed that
+// musicians can subscribe to. ?time=HH:MM and ?duration=minutes place services
+// at a time of day; without them services are all-day events.
+func (h *Handler) GetSetlistCalendar(c *fiber.Ctx) error {
+	opts := export.ICalOptions{Name: c.Query("name", "Services")}
+	if t := c.Query("time"); t != "" {
+		start, err := time.Parse("15:04", t)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "time must be HH:MM"})
+		}
+		opts.Start = time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+		opts.Duration = 90 * time.Minute
+		if d := c.Query("duration"); d != "" {
+			minutes, err := strconv.Atoi(d)
+			if err != nil || minutes < 1 || minutes > 24*60 {
+				return c.Status(400).JSON(fiber.Map{"error": "duration must be between 1 and 1440 minutes"})
+			}
+			opts.Duration = time.Duration(minutes) * time.Minute
+		}
+	}
+
+	setlists, err := h.db.GetScheduledSetlists(time.Now().Format("2006-01-02"))
+	if err != nil {
+		log.Printf("Error getting scheduled setlists: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get setlists"})
+	}
+
+	c.Set("Content-Type", "text/calendar; charset=utf-8")
+	return c.SendString(export.ICal(setlists, opts))
+}
+
+// GetSetlist returns a setlist with its songs
+func (h *Handler) GetSetlist(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid setlist ID"})
+	}
+
+	setlist, err := h.db.GetSetlist(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Setlist not found"})
+	}
+
+	return c.JSON(setlist)
+}
+
+// CreateSetlist creates a setlist from an ordered list of song IDs
+func (h *Handler) CreateSetlist(c *fiber.Ctx) error {
+	req, errMsg := parseSetlistRequest(c)
+	if errMsg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": errMsg})
+	}
+
+	setlist, err := h.db.CreateSetlist(req)
+	if err != nil {
+		log.Printf("Error creating setlist: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create setlist"})
+	}
+
+	return c.Status(201).JSON(setlist)
+}
+
+// UpdateSetlist replaces a setlist's name, date and songs
+func (h *Handler) UpdateSetlist(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid setlist ID"})
+	}
+
+	req, errMsg := parseSetlistRequest(c)
+	if errMsg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": errMsg})
+	}
+
+	setlist, err := h.db.UpdateSetlist(id, req)
+	if err != nil {
+		if err.Error() == "setlist not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Setlist not found"})
+		}
+		log.Printf("Error updating setlist: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update setlist"})
+	}
+
+	return c.JSON(setlist)
+}
+
+// DeleteSetlist removes a setlist
+func (h *Handler) DeleteSetlist(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid setlist ID"})
+	}
+
+	if err := h.db.DeleteSetlist(id); err != nil {
+		if err.Error() == "setlist not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Setlist not found"})
+		}
+		log.Printf("Error deleting setlist: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete setlist"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Setlist deleted successfully"})
+}
+
+// ExportSetlist downloads a setlist (?format=chordpro|pdf|pptx)
+func (h *Handler) ExportSetlist(c *fiber.Ctx) error {
+	return h.exportSetlist(c, c.Query("format", "chordpro"))
+}
+
+// ExportSetlistPPTX downloads a setlist as a PowerPoint deck, for venues without ProPresenter
+func (h *Handler) ExportSetlistPPTX(c *fiber.Ctx) error {
+	return h.exportSetlist(c, "pptx")
+}
+
+func (h *Handler) exportSetlist(c *fiber.Ctx, format string) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid setlist ID"})
+	}
+
+	setlist, err := h.db.GetSetlist(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Setlist not found"})
+	}
+
+	switch format {
+	case "chordpro":
+		setAttachment(c, export.FileName(setlist.Name, ".cho"))
+		c.Set("Content-Type", "text/plain; charset=utf-8")
+		return c.SendString(export.ChordProSet(setlist.Songs))
+	case "pdf":
+		setAttachment(c, export.FileName(setlist.Name, ".pdf"))
+		c.Set("Content-Type", "application/pdf")
+		return c.Send(export.PDF(setlist.Name, setlist.Songs, pdfOptions(c)))
+	case "pptx":
+		tmpl, err := pptxTemplate(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		tmpl.CopyrightSlides, tmpl.CCLILicense = h.copyrightSlides(c)
+		deck, err := export.PPTX(setlist.Name, setlist.Songs, tmpl)
+		if err != nil {
+			log.Printf("Error exporting setlist %d to PowerPoint: %v", id, err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to export setlist"})
+		}
+		setAttachment(c, export.FileName(setlist.Name, ".pptx"))
+		c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.presentationml.presentation")
+		return c.Send(deck)
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "format must be chordpro, pdf or pptx"})
+	}
+}
+
+// basePPTXTemplate is the default slide template with the JSON file named by
+// PPTX_TEMPLATE applied. On error the defaults are returned with the error.
+func basePPTXTemplate() (export.PPTXTemplate, error) {
+	tmpl := export.DefaultPPTXTemplate
+
+	path := os.Getenv("PPTX_TEMPLATE")
+	if path == "" {
+		return tmpl, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return export.DefaultPPTXTemplate, fmt.Errorf("could not read PPTX_TEMPLATE %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return export.DefaultPPTXTemplate, fmt.Errorf("invalid PPTX_TEMPLATE %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// pptxTemplate builds the slide template from defaults, the JSON file named by
+// PPTX_TEMPLATE (if set) and finally any query parameter overrides
+func pptxTemplate(c *fiber.Ctx) (export.PPTXTemplate, error) {
+	tmpl, err := basePPTXTemplate()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	if v := c.Query("background"); v != "" {
+		tmpl.Background = strings.TrimPrefix(v, "#")
+	}
+	if v := c.Query("text_color"); v != "" {
+		tmpl.TextColor = strings.TrimPrefix(v, "#")
+	}
+	if v := c.Query("font"); v != "" {
+		tmpl.Font = v
+	}
+	if v := c.QueryInt("font_size", 0); v != 0 {
+		tmpl.FontSize = v
+	}
+	if v := c.Query("title_slides"); v != "" {
+		tmpl.TitleSlides = v == "true"
+	}
+	if v := c.Query("widescreen"); v != "" {
+		tmpl.Widescreen = v == "true"
+	}
+
+	return tmpl, tmpl.Validate()
+}
+
+// parseSetlistRequest reads and validates a setlist body, returning an error message on failure
+func parseSetlistRequest(c *fiber.Ctx) (*models.SetlistRequest, string) {
+	var req models.SetlistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, "Invalid request body"
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, "name is required"
+	}
+	if req.ServiceDate != nil {
+		if *req.ServiceDate == "" {
+			req.ServiceDate = nil
+		} else if _, err := time.Parse("2006-01-02", *req.ServiceDate); err != nil {
+			return nil, "service_date must be a date (YYYY-MM-DD)"
+		}
+	}
+	if req.SongIDs == nil {
+		req.SongIDs = make([]string, 0)
+	}
+	for i, id := range req.SongIDs {
+		if strings.TrimSpace(id) == "" {
+			return nil, fmt.Sprintf("song_ids[%d] is empty", i)
+		}
+	}
+
+	return &req, ""
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+)
+
+// PreviewSlides shows how a song's lyrics will be split into slides before they reach
+// ProPresenter. Editors can pass modified lyrics and options to try out changes.
+func (h *Handler) PreviewSlides(c *fiber.Ctx) error {
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	var req struct {
+		Lyrics           *string `json:"lyrics"`
+		Source           string  `json:"source"` // "display" (default) or "music_ministry"
+		MaxLinesPerSlide int     `json:"max_lines_per_slide"`
+		ExpandRepeats    *bool   `json:"expand_repeats"`
+		Balance          *bool   `json:"balance"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	text := song.DisplayLyrics
+	switch req.Source {
+	case "", "display":
+	case "music_ministry":
+		text = song.MusicMinistryLyrics
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "source must be display or music_ministry"})
+	}
+	if req.Lyrics != nil {
+		text = *req.Lyrics
+	}
+
+	opts := lyrics.DefaultSegmentOptions
+	if req.MaxLinesPerSlide != 0 {
+		if req.MaxLinesPerSlide < 1 || req.MaxLinesPerSlide > 12 {
+			return c.Status(400).JSON(fiber.Map{"error": "max_lines_per_slide must be between 1 and 12"})
+		}
+		opts.MaxLinesPerSlide = req.MaxLinesPerSlide
+	}
+	if req.ExpandRepeats != nil {
+		opts.ExpandRepeats = *req.ExpandRepeats
+	}
+	if req.Balance != nil {
+		opts.Balance = *req.Balance
+	}
+
+	slides, warnings := lyrics.Segment(text, opts)
+	if enabled, license := h.copyrightSlides(c); enabled {
+		if attribution := lyrics.CopyrightSlide(song, license); attribution != nil {
+			slides = append(slides, lyrics.Slide{Index: len(slides), Label: lyrics.CopyrightLabel, Lines: attribution})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"song_id":  song.ID,
+		"options":  opts,
+		"slides":   slides,
+		"count":    len(slides),
+		"warnings": warnings,
+	})
+}
@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"bufio"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/export"
+	"github.com/yourusername/audience-stage-teleprompter/internal/language"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetSongbooks lists all songbooks
+func (h *Handler) GetSongbooks(c *fiber.Ctx) error {
+	books, err := h.db.GetSongbooks()
+	if err != nil {
+		log.Printf("Error getting songbooks: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get songbooks"})
+	}
+
+	return c.JSON(books)
+}
+
+// GetSongbook returns a songbook's details
+func (h *Handler) GetSongbook(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid songbook ID"})
+	}
+
+	book, err := h.db.GetSongbook(id)
+	if err != nil {
+		return h.songbookError(c, err, "get")
+	}
+
+	return c.JSON(book)
+}
+
+// CreateSongbook adds a songbook
+func (h *Handler) CreateSongbook(c *fiber.Ctx) error {
+	req, errMsg := parseSongbookRequest(c)
+	if errMsg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": errMsg})
+	}
+
+	book, err := h.db.CreateSongbook(req)
+	if err != nil {
+		return h.songbookError(c, err, "create")
+	}
+
+	return c.Status(201).JSON(book)
+}
+
+// UpdateSongbook changes a songbook's name, abbreviation and description.
+// Songs numbered in it follow a rename.
+func (h *Handler) UpdateSongbook(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid songbook ID"})
+	}
+
+	req, errMsg := parseSongbookRequest(c)
+	if errMsg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": errMsg})
+	}
+
+	book, err := h.db.UpdateSongbook(id, req)
+	if err != nil {
+		return h.songbookError(c, err, "update")
+	}
+
+	return c.JSON(book)
+}
+
+// DeleteSongbook removes a songbook and its numbers; the songs are kept
+func (h *Handler) DeleteSongbook(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid songbook ID"})
+	}
+
+	if err := h.db.DeleteSongbook(id); err != nil {
+		return h.songbookError(c, err, "delete")
+	}
+
+	return c.JSON(fiber.Map{"message": "Songbook deleted successfully"})
+}
+
+// GetSongbookEntries lists a songbook's songs in number order
+func (h *Handler) GetSongbookEntries(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid songbook ID"})
+	}
+
+	if _, err := h.db.GetSongbook(id); err != nil {
+		return h.songbookError(c, err, "get")
+	}
+
+	entries, err := h.db.GetSongbookEntries(id)
+	if err != nil {
+		log.Printf("Error getting songbook %d entries: %v", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get songbook songs"})
+	}
+
+	return c.JSON(entries)
+}
+
+// GetSongbookPage returns the song at a number with the previous and next
+// numbers, so a songbook can be browsed page by page
+func (h *Handler) GetSongbookPage(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid songbook ID"})
+	}
+	number := c.Params("number")
+
+	book, err := h.db.GetSongbook(id)
+	if err != nil {
+		return h.songbookError(c, err, "get")
+	}
+
+	entries, err := h.db.GetSongbookEntries(id)
+	if err != nil {
+		log.Printf("Error getting songbook %d entries: %v", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get songbook songs"})
+	}
+
+	for i, entry := range entries {
+		if !strings.EqualFold(entry.Number, number) {
+			continue
+		}
+
+		song, err := h.db.GetSong(entry.SongID)
+		if err != nil {
+			log.Printf("Error getting song %s: %v", entry.SongID, err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to get song"})
+		}
+		if numbers, err := h.db.GetSongNumbers(song.ID); err == nil {
+			song.Numbers = numbers
+		}
+
+		page := models.SongbookPage{Songbook: *book, Number: entry.Number, Song: *song}
+		if i > 0 {
+			page.Previous = &entries[i-1].Number
+		}
+		if i < len(entries)-1 {
+			page.Next = &entries[i+1].Number
+		}
+		return c.JSON(page)
+	}
+
+	return c.Status(404).JSON(fiber.Map{"error": "Song number not found"})
+}
+
+// SetSongbookEntry puts a song at a number in a songbook
+func (h *Handler) SetSongbookEntry(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid songbook ID"})
+	}
+
+	var req models.SongbookEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.SongID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "song_id is required"})
+	}
+
+	number := strings.TrimPrefix(strings.TrimSpace(c.Params("number")), "#")
+	if !songNumberPattern.MatchString(number) {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid number (use digits, optionally followed by a letter)"})
+	}
+
+	if err := h.db.SetSongbookEntry(id, number, req.SongID); err != nil {
+		switch err.Error() {
+		case "song not found":
+			return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+		case "song number already used":
+			return c.Status(409).JSON(fiber.Map{"error": "Number is already used by another song"})
+		}
+		return h.songbookError(c, err, "update")
+	}
+
+	return c.JSON(fiber.Map{"message": "Song added to songbook"})
+}
+
+// DeleteSongbookEntry removes the song at a number from a songbook
+func (h *Handler) DeleteSongbookEntry(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid songbook ID"})
+	}
+
+	if err := h.db.DeleteSongbookEntry(id, c.Params("number")); err != nil {
+		if err.Error() == "song number not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Song number not found"})
+		}
+		return h.songbookError(c, err, "update")
+	}
+
+	return c.JSON(fiber.Map{"message": "Song removed from songbook"})
+}
+
+// ExportSongbook downloads a songbook in number order
+// (?format=chordpro|pdf|openlyrics). ChordPro and PDF titles are prefixed with
+// the song's number; OpenLyrics carries it in the songbook element.
+func (h *Handler) ExportSongbook(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid songbook ID"})
+	}
+
+	book, err := h.db.GetSongbook(id)
+	if err != nil {
+		return h.songbookError(c, err, "get")
+	}
+
+	songs, err := h.db.GetSongbookSongs(id)
+	if err != nil {
+		log.Printf("Error loading songbook %d for export: %v", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to export songbook"})
+	}
+
+	switch c.Query("format", "chordpro") {
+	case "chordpro":
+		setAttachment(c, export.FileName(book.Name, ".cho"))
+		c.Set("Content-Type", "text/plain; charset=utf-8")
+		return c.SendString(export.ChordProSet(numberedTitles(songs)))
+	case "pdf":
+		setAttachment(c, export.FileName(book.Name, ".pdf"))
+		c.Set("Content-Type", "application/pdf")
+		return c.Send(export.PDF(book.Name, numberedTitles(songs), pdfOptions(c)))
+	case "openlyrics":
+		setAttachment(c, export.FileName(book.Name, ".zip"))
+		c.Set("Content-Type", "application/zip")
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			lw, err := export.NewLibraryWriter(w, export.LibraryOpenLyrics)
+			if err != nil {
+				log.Printf("Error starting songbook export: %v", err)
+				return
+			}
+			for i := range songs {
+				if err := lw.AddSong(&songs[i]); err != nil {
+					log.Printf("Error exporting songbook %d: %v", id, err)
+					return
+				}
+			}
+			if err := lw.Close(); err != nil {
+				log.Printf("Error finishing songbook export: %v", err)
+				return
+			}
+			w.Flush()
+		})
+		return nil
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "format must be chordpro, pdf or openlyrics"})
+	}
+}
+
+// numberedTitles prefixes each song's title with its songbook number
+func numberedTitles(songs []models.Song) []models.Song {
+	out := make([]models.Song, len(songs))
+	for i, song := range songs {
+		if len(song.Numbers) > 0 {
+			song.Title = song.Numbers[0].Number + ". " + song.Title
+		}
+		out[i] = song
+	}
+	return out
+}
+
+func parseSongbookRequest(c *fiber.Ctx) (*models.SongbookRequest, string) {
+	var req models.SongbookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, "Invalid request body"
+	}
+
+	req.Name = language.NormalizeLine(req.Name)
+	req.Abbreviation = strings.TrimSpace(req.Abbreviation)
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Name == "" {
+		return nil, "Name is required"
+	}
+	return &req, ""
+}
+
+// songbookError maps songbook database errors to responses
+func (h *Handler) songbookError(c *fiber.Ctx, err error, action string) error {
+	switch err.Error() {
+	case "songbook not found":
+		return c.Status(404).JSON(fiber.Map{"error": "Songbook not found"})
+	case "songbook already exists":
+		return c.Status(409).JSON(fiber.Map{"error": "A songbook with that name already exists"})
+	}
+	log.Printf("Error (%s songbook): %v", action, err)
+	return c.Status(500).JSON(fiber.Map{"error": "Failed to " + action + " songbook"})
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/oidc"
+)
+
+// ssoLoginTTL is how long a sign-in may take at the provider
+const ssoLoginTTL = 10 * time.Minute
+
+// SSOConfig is single sign-on with an OpenID Connect provider such as Google
+// Workspace. Users are matched by their identity, then by email; new users
+// get the role of their groups.
+type SSOConfig struct {
+	Provider    *oidc.Provider
+	Groups      *oidc.GoogleGroups // looks up Workspace groups (optional)
+	GroupRoles  map[string]string  // lowercased group email or name -> role
+	DefaultRole string             // for users in no mapped group; empty refuses them
+	AfterLogin  string             // where the browser goes after signing in, default "/"
+}
+
+// sso holds the configuration and the sign-ins waiting for the provider
+type sso struct {
+	provider    *oidc.Provider
+	groups      *oidc.GoogleGroups
+	groupRoles  map[string]string
+	defaultRole string
+	afterLogin  string
+
+	mu      sync.Mutex
+	pending map[string]pendingSSOLogin // by state
+}
+
+type pendingSSOLogin struct {
+	login    *oidc.Login
+	redirect string
+	expires  time.Time
+}
+
+// SetSSOConfig turns on single sign-on
+func (h *Handler) SetSSOConfig(cfg SSOConfig) {
+	if cfg.AfterLogin == "" {
+		cfg.AfterLogin = "/"
+	}
+	h.sso.mu.Lock()
+	defer h.sso.mu.Unlock()
+	h.sso.provider = cfg.Provider
+	h.sso.groups = cfg.Groups
+	h.sso.groupRoles = cfg.GroupRoles
+	h.sso.defaultRole = cfg.DefaultRole
+	h.sso.afterLogin = cfg.AfterLogin
+	h.sso.pending = make(map[string]pendingSSOLogin)
+}
+
+// SSOLogin sends the browser to the provider to sign in. ?redirect= is a
+// path in this app to come back to afterwards.
+func (h *Handler) SSOLogin(c *fiber.Ctx) error {
+	if h.sso.provider == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Single sign-on is not configured"})
+	}
+	login, authURL, err := h.sso.provider.NewLogin()
+	if err != nil {
+		h.reportError(c, "Error starting single sign-on", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to start sign-in"})
+	}
+
+	// Only paths here, so the callback can't be used to send people elsewhere
+	redirect := c.Query("redirect")
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.Contains(redirect, "\\") {
+		redirect = ""
+	}
+
+	now := time.Now()
+	h.sso.mu.Lock()
+	for state, p := range h.sso.pending {
+		if now.After(p.expires) {
+			delete(h.sso.pending, state)
+		}
+	}
+	h.sso.pending[login.State] = pendingSSOLogin{login: login, redirect: redirect, expires: now.Add(ssoLoginTTL)}
+	h.sso.mu.Unlock()
+
+	return c.Redirect(authURL)
+}
+
+// SSOCallback finishes a sign-in at the provider: it verifies the identity,
+// finds or creates the user, applies the role of their groups, and starts a
+// session. Failures go back to the app with ?login_error=.
+func (h *Handler) SSOCallback(c *fiber.Ctx) error {
+	if h.sso.provider == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Single sign-on is not configured"})
+	}
+
+	h.sso.mu.Lock()
+	pending, ok := h.sso.pending[c.Query("state")]
+	delete(h.sso.pending, c.Query("state"))
+	h.sso.mu.Unlock()
+
+	fail := func(message string) error {
+		return c.Redirect(h.sso.afterLogin + "?login_error=" + url.QueryEscape(message))
+	}
+	if !ok || time.Now().After(pending.expires) {
+		return fail("The sign-in took too long, please try again")
+	}
+	if errParam := c.Query("error"); errParam != "" {
+		return fail("Sign-in was cancelled")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	claims, err := h.sso.provider.Exchange(ctx, pending.login, c.Query("code"))
+	if err != nil {
+		h.reportError(c, "Error verifying single sign-on", err)
+		return fail("Sign-in could not be verified")
+	}
+	if claims.Email == "" || !claims.EmailVerified {
+		return fail("Your account has no verified email address")
+	}
+
+	role, err := h.ssoRole(ctx, claims)
+	if err != nil {
+		h.reportError(c, "Error looking up groups for single sign-on", err)
+		return fail("Your groups could not be checked, please try again")
+	}
+
+	user, err := h.ssoUser(claims, role)
+	if err != nil {
+		if err.Error() == "not allowed" {
+			log.Printf("Single sign-on refused for %s: in no group with a role", claims.Email)
+			return fail("Your account isn't in a group that can use this app")
+		}
+		h.reportError(c, "Error signing in with single sign-on", err)
+		return fail("Sign-in failed")
+	}
+	if user.Disabled {
+		return fail("This account is disabled")
+	}
+
+	if _, _, err := h.startSession(c, user); err != nil {
+		h.reportError(c, "Error signing in with single sign-on", err)
+		return fail("Sign-in failed")
+	}
+	redirect := pending.redirect
+	if redirect == "" {
+		redirect = h.sso.afterLogin
+	}
+	return c.Redirect(redirect)
+}
+
+// ssoRole returns the most privileged role of the user's groups, or the
+// default role. Groups come from the ID token and, for Google, the
+// Workspace directory.
+func (h *Handler) ssoRole(ctx context.Context, claims *oidc.Claims) (string, error) {
+	if len(h.sso.groupRoles) == 0 {
+		return h.sso.defaultRole, nil
+	}
+	groups := append([]string(nil), claims.Groups...)
+	if h.sso.groups != nil {
+		more, err := h.sso.groups.Groups(ctx, claims.Email)
+		if err != nil {
+			return "", err
+		}
+		groups = append(groups, more...)
+	}
+
+	best := -1
+	for _, group := range groups {
+		role, ok := h.sso.groupRoles[strings.ToLower(group)]
+		if !ok {
+			continue
+		}
+		for i, r := range models.Roles {
+			if r == role && (best < 0 || i < best) {
+				best = i
+			}
+		}
+	}
+	if best < 0 {
+		return h.sso.defaultRole, nil
+	}
+	return models.Roles[best], nil
+}
+
+// ssoUser finds the user for an identity, linking an existing account with
+// the same email the first time, or creates one. With group roles set up,
+// the groups decide the role on every sign-in.
+func (h *Handler) ssoUser(claims *oidc.Claims, role string) (*models.User, error) {
+	user, err := h.db.GetUserByOIDCSubject(claims.Subject)
+	if err != nil && err.Error() == "user not found" {
+		user, err = h.db.GetUserByEmail(claims.Email)
+		if err == nil {
+			if user.SSO {
+				// Linked to another identity with the same email (a re-created account)
+				return nil, fmt.Errorf("identity already linked to another user")
+			}
+			if err := h.db.LinkOIDCSubject(user.ID, claims.Subject); err != nil {
+				return nil, err
+			}
+			log.Printf("🔗 %s linked to single sign-on", user.Username)
+		}
+	}
+	if err != nil && err.Error() != "user not found" {
+		return nil, err
+	}
+
+	if user == nil {
+		if role == "" {
+			return nil, fmt.Errorf("not allowed")
+		}
+		req := &models.CreateUserRequest{Username: claims.Email, DisplayName: claims.Name, Email: claims.Email, Role: role}
+		user, err = h.db.CreateOIDCUser(req, claims.Subject)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("👤 %s created by single sign-on as %s", user.Username, role)
+		return user, nil
+	}
+
+	if len(h.sso.groupRoles) > 0 {
+		if role == "" {
+			return nil, fmt.Errorf("not allowed")
+		}
+		if role != user.Role {
+			if user, err = h.db.UpdateUser(user.ID, &models.UpdateUserRequest{Role: &role}); err != nil {
+				return nil, err
+			}
+			log.Printf("👤 %s is now %s from their groups", user.Username, role)
+		}
+	}
+	return user, nil
+}
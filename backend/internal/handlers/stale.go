@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// staleCutoff is the start of the window a song must have been used in to
+// stay out of the archive
+func staleCutoff(months int) time.Time {
+	return time.Now().AddDate(0, -months, 0)
+}
+
+// archiveStale archives the given stale songs and drops them from the search
+// index
+func (h *Handler) archiveStale(songs []models.Song) (int64, error) {
+	if len(songs) == 0 {
+		return 0, nil
+	}
+	ids := make([]string, len(songs))
+	for i, song := range songs {
+		ids[i] = song.ID
+	}
+	archived, err := h.db.ArchiveSongs(ids)
+	if err != nil {
+		return 0, err
+	}
+	if h.ts != nil {
+		for _, id := range ids {
+			if err := h.ts.DeleteSong(id); err != nil {
+				h.reportError(nil, "Error removing archived song from Typesense", fmt.Errorf("song %s: %w", id, err))
+			}
+		}
+	}
+	return archived, nil
+}
+
+// StartArchivePolicy archives songs not used in the last months once a day.
+// months <= 0 leaves the policy off.
+func (h *Handler) StartArchivePolicy(months int) {
+	if months <= 0 {
+		return
+	}
+	go func() {
+		// Let the server settle before the first sweep
+		time.Sleep(time.Minute)
+		for {
+			songs, err := h.db.FindStaleSongs(staleCutoff(months))
+			if err == nil {
+				var archived int64
+				if archived, err = h.archiveStale(songs); err == nil && archived > 0 {
+					log.Printf("🗄️  Archived %d songs not used in %d months", archived, months)
+				}
+			}
+			if err != nil {
+				log.Printf("Error archiving stale songs: %v", err)
+			}
+			time.Sleep(24 * time.Hour)
+		}
+	}()
+}
+
+// ArchiveStaleSongs runs the archive policy now: songs not used in the last
+// ?months (default 36) are archived. dry_run=true only lists them.
+func (h *Handler) ArchiveStaleSongs(c *fiber.Ctx) error {
+	months := 36
+	if v := c.Query("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return c.Status(400).JSON(fiber.Map{"error": "months must be a positive number"})
+		}
+		months = n
+	}
+
+	songs, err := h.db.FindStaleSongs(staleCutoff(months))
+	if err != nil {
+		log.Printf("Error finding stale songs: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to find stale songs"})
+	}
+	matched := make([]fiber.Map, len(songs))
+	for i, song := range songs {
+		matched[i] = fiber.Map{"id": song.ID, "title": song.Title, "language": song.Language, "library": song.Library}
+	}
+
+	if c.Query("dry_run") == "true" {
+		return c.JSON(fiber.Map{"dry_run": true, "months": months, "count": len(songs), "songs": matched})
+	}
+
+	archived, err := h.archiveStale(songs)
+	if err != nil {
+		log.Printf("Error archiving stale songs: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to archive songs"})
+	}
+	return c.JSON(fiber.Map{"dry_run": false, "months": months, "archived": archived, "songs": matched})
+}
+
+// GetArchivedSongs lists archived songs
+func (h *Handler) GetArchivedSongs(c *fiber.Ctx) error {
+	songs, err := h.db.GetArchivedSongs()
+	if err != nil {
+		log.Printf("Error getting archived songs: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get archived songs"})
+	}
+	return c.JSON(songs)
+}
+
+// ArchiveSong archives one song by hand
+func (h *Handler) ArchiveSong(c *fiber.Ctx) error {
+	return h.setSongArchived(c, true)
+}
+
+// UnarchiveSong brings an archived song back into search
+func (h *Handler) UnarchiveSong(c *fiber.Ctx) error {
+	return h.setSongArchived(c, false)
+}
+
+func (h *Handler) setSongArchived(c *fiber.Ctx, archived bool) error {
+	song, err := h.db.SetSongArchived(c.Params("id"), archived)
+	if err != nil {
+		if err.Error() == "song not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+		}
+		log.Printf("Error archiving song: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update song"})
+	}
+
+	// IndexSong removes archived songs from the index and restores the rest
+	if h.ts != nil {
+		if err := h.ts.IndexSong(song); err != nil {
+			h.reportError(c, "Error updating song in Typesense", err)
+		}
+	}
+	return c.JSON(song)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/slowlog"
+)
+
+// recentEditsShown is how many recently edited songs the dashboard lists
+const recentEditsShown = 10
+
+// GetAdminStats gathers what the admin dashboard shows at a glance: the
+// library by language, recent edits, the search index, backups, the
+// ProPresenter connection and slow dependency calls. A part that can't be read
+// carries an "error" instead of failing the whole response.
+func (h *Handler) GetAdminStats(c *fiber.Ctx) error {
+	library, err := h.db.GetLibraryStats()
+	if err != nil {
+		log.Printf("Error getting library stats: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get library stats"})
+	}
+	activity, err := h.db.GetEditActivity(recentEditsShown)
+	if err != nil {
+		log.Printf("Error getting edit activity: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get edit activity"})
+	}
+
+	return c.JSON(fiber.Map{
+		"library":      library,
+		"activity":     activity,
+		"index":        h.indexStats(library.Active, library.LastEditAt),
+		"backups":      h.backupStats(),
+		"propresenter": h.proPresenterStats(),
+		"slow_calls":   slowlog.Snapshot(),
+	})
+}
+
+// indexStats compares the search index with the active songs it should hold
+func (h *Handler) indexStats(active int, lastEdit *time.Time) fiber.Map {
+	if h.ts == nil || h.skipTypesense {
+		return fiber.Map{"enabled": false}
+	}
+	stats := fiber.Map{"enabled": true, "ready": h.ts.Ready(), "expected": active}
+	if !h.ts.Ready() {
+		return stats
+	}
+
+	index, err := h.ts.Stats()
+	if err != nil {
+		stats["error"] = err.Error()
+		return stats
+	}
+	stats["documents"] = index.Documents
+	stats["last_indexed_at"] = index.LastIndexedAt
+	// Fresh when every active song is indexed and the newest edit made it in
+	stats["fresh"] = index.Documents == int64(active) && !h.ts.Stale() &&
+		(lastEdit == nil || (index.LastIndexedAt != nil && !index.LastIndexedAt.Before(lastEdit.Truncate(time.Second))))
+	return stats
+}
+
+// backupStats summarizes the backups on disk and the latest attempts
+func (h *Handler) backupStats() fiber.Map {
+	stats := fiber.Map{"status": h.backupManager.Status()}
+
+	backups, err := h.backupManager.ListBackups()
+	if err != nil {
+		stats["error"] = err.Error()
+		return stats
+	}
+	var size float64
+	var latest map[string]interface{}
+	latestAt := ""
+	for _, b := range backups {
+		if bytes, ok := b["size_bytes"].(float64); ok {
+			size += bytes
+		}
+		// Timestamps are YYYY-MM-DD_HH-MM-SS, so they sort as strings
+		if ts, _ := b["timestamp"].(string); latest == nil || ts > latestAt {
+			latest, latestAt = b, ts
+		}
+	}
+	stats["count"] = len(backups)
+	stats["total_bytes"] = int64(size)
+	stats["latest"] = latest
+	return stats
+}
+
+// proPresenterStats reports whether ProPresenter is connected and for how long
+func (h *Handler) proPresenterStats() fiber.Map {
+	if !h.propresenter.IsEnabled() {
+		return fiber.Map{"enabled": false}
+	}
+	since, connected := h.propresenter.ConnectedSince()
+	stats := fiber.Map{"enabled": true, "connected": connected}
+	if connected {
+		stats["connected_since"] = since
+		stats["uptime_seconds"] = int64(time.Since(since).Seconds())
+	}
+	return stats
+}
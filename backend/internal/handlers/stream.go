@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+)
+
+// jsonArrayWriter writes a JSON array one element at a time, so listings can
+// be sent as rows are scanned instead of building the whole slice first
+type jsonArrayWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+	n   int
+}
+
+func newJSONArrayWriter(w *bufio.Writer) *jsonArrayWriter {
+	return &jsonArrayWriter{w: w, enc: json.NewEncoder(w)}
+}
+
+// Write appends one element, opening the array on the first call
+func (a *jsonArrayWriter) Write(v interface{}) error {
+	sep := byte(',')
+	if a.n == 0 {
+		sep = '['
+	}
+	if err := a.w.WriteByte(sep); err != nil {
+		return err
+	}
+	a.n++
+	return a.enc.Encode(v)
+}
+
+// Close ends the array and flushes it; an empty array is written as []
+func (a *jsonArrayWriter) Close() error {
+	if a.n == 0 {
+		if _, err := a.w.WriteString("[]"); err != nil {
+			return err
+		}
+	} else if err := a.w.WriteByte(']'); err != nil {
+		return err
+	}
+	return a.w.Flush()
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/live"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+var timeSignaturePattern = regexp.MustCompile(`^([1-9]|1[0-9])/(2|4|8|16)$`)
+
+// GetCurrentTempo returns click metadata for the song on screen, so the
+// drummer's click app can follow along
+func (h *Handler) GetCurrentTempo(c *fiber.Ctx) error {
+	current := h.live.Current()
+	if current == nil || current.SongID == "" {
+		return c.Status(404).JSON(fiber.Map{"error": "No song is currently live"})
+	}
+
+	song, err := h.db.GetSong(current.SongID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	return c.JSON(songTempo(song))
+}
+
+// publishTempo broadcasts the tempo of a song that just went live
+func (h *Handler) publishTempo(song *models.Song) {
+	h.live.Publish(live.Event{Type: live.EventTempo, Data: songTempo(song)})
+}
+
+func songTempo(song *models.Song) models.Tempo {
+	return models.Tempo{
+		SongID:        song.ID,
+		Title:         song.Title,
+		BPM:           song.BPM,
+		TimeSignature: song.TimeSignature,
+		CountInBeats:  song.CountInBeats,
+	}
+}
+
+// validateTempoFields checks tempo values in a song request. A BPM of zero or an
+// empty time signature is allowed so updates can clear them.
+func validateTempoFields(bpm *int, timeSignature *string, countInBeats *int) error {
+	if bpm != nil && *bpm != 0 && (*bpm < 20 || *bpm > 300) {
+		return fmt.Errorf("bpm must be between 20 and 300")
+	}
+	if timeSignature != nil && *timeSignature != "" && !timeSignaturePattern.MatchString(*timeSignature) {
+		return fmt.Errorf("time_signature must look like 4/4 or 6/8")
+	}
+	if countInBeats != nil && (*countInBeats < 0 || *countInBeats > 16) {
+		return fmt.Errorf("count_in_beats must be between 0 and 16")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
+)
+
+// searchTokenization is the tokenization configured in the settings
+func searchTokenization(settings *models.Settings) search.Tokenization {
+	return search.Tokenization{
+		FieldLocales:    settings.SearchFieldLocales,
+		TokenSeparators: settings.SearchTokenSeparators,
+	}
+}
+
+// validateTokenizationUpdate checks the tokenization fields of a settings
+// update
+func validateTokenizationUpdate(req *models.UpdateSettingsRequest) error {
+	var t search.Tokenization
+	if req.SearchFieldLocales != nil {
+		t.FieldLocales = *req.SearchFieldLocales
+	}
+	if req.SearchTokenSeparators != nil {
+		t.TokenSeparators = *req.SearchTokenSeparators
+	}
+	return t.Validate()
+}
+
+// LoadSearchTokenization passes the tokenization settings to the search
+// backend. It reports whether the index was built with different settings
+// and needs a reindex for them to take effect.
+func (h *Handler) LoadSearchTokenization() bool {
+	if _, ok := h.ts.(search.Tokenizer); !ok {
+		return false
+	}
+	settings, err := h.db.GetSettings()
+	if err != nil {
+		h.reportError(nil, "Error loading search tokenization settings", err)
+		return false
+	}
+	return h.setTokenization(settings)
+}
+
+// ApplySearchTokenization loads the tokenization settings and starts a
+// reindex if the index was built with different ones
+func (h *Handler) ApplySearchTokenization() {
+	if h.LoadSearchTokenization() {
+		h.reindexForTokenization()
+	}
+}
+
+func (h *Handler) setTokenization(settings *models.Settings) bool {
+	tokenizer, ok := h.ts.(search.Tokenizer)
+	if !ok {
+		return false
+	}
+	rebuild, err := tokenizer.SetTokenization(context.Background(), searchTokenization(settings))
+	if err != nil {
+		h.reportError(nil, "Error checking search tokenization", err)
+		return false
+	}
+	return rebuild
+}
+
+func (h *Handler) reindexForTokenization() {
+	if h.skipTypesense {
+		log.Println("⚠️  Search tokenization changed; reindex to apply it")
+		return
+	}
+	job, _ := h.startReindex()
+	log.Printf("Search tokenization changed, reindex job %s started", job.Status().ID)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTrash lists the deleted songs, most recently deleted first
+func (h *Handler) GetTrash(c *fiber.Ctx) error {
+	songs, err := h.db.GetDeletedSongs()
+	if err != nil {
+		log.Printf("Error getting deleted songs: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get the trash"})
+	}
+	return c.JSON(songs)
+}
+
+// RestoreSong takes a song out of the trash with everything it had, and puts
+// it back in search
+func (h *Handler) RestoreSong(c *fiber.Ctx) error {
+	song, err := h.db.RestoreSong(c.Params("id"))
+	if err != nil {
+		if err.Error() == "song not in trash" {
+			return c.Status(404).JSON(fiber.Map{"error": "Song not in the trash"})
+		}
+		h.reportError(c, "Error restoring song", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to restore song"})
+	}
+
+	// IndexSong leaves archived songs out
+	if h.ts != nil {
+		if err := h.ts.IndexSong(song); err != nil {
+			h.reportError(c, "Error indexing restored song in Typesense", err)
+		}
+	}
+	return c.JSON(song)
+}
+
+// PurgeSong deletes a song in the trash for good
+func (h *Handler) PurgeSong(c *fiber.Ctx) error {
+	if err := h.db.PurgeSong(c.Params("id")); err != nil {
+		if err.Error() == "song not in trash" {
+			return c.Status(404).JSON(fiber.Map{"error": "Song not in the trash"})
+		}
+		h.reportError(c, "Error purging song", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete song"})
+	}
+	return c.JSON(fiber.Map{"message": "Song deleted for good"})
+}
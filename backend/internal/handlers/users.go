@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/auth"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// GetUsers lists every account
+func (h *Handler) GetUsers(c *fiber.Ctx) error {
+	users, err := h.db.GetUsers()
+	if err != nil {
+		h.reportError(c, "Error listing users", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to list users"})
+	}
+	return c.JSON(users)
+}
+
+// CreateUser adds an account. The new user chooses their own password at
+// first sign-in; without a password in the request a temporary one is
+// generated and returned once.
+func (h *Handler) CreateUser(c *fiber.Ctx) error {
+	var req models.CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Username = strings.TrimSpace(req.Username)
+	req.DisplayName = strings.TrimSpace(req.DisplayName)
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Username == "" || strings.ContainsAny(req.Username, " \t\n") {
+		return c.Status(400).JSON(fiber.Map{"error": "Username is required and can't contain spaces"})
+	}
+	if req.Role == "" {
+		req.Role = models.RoleViewer
+	}
+	if !models.ValidRole(req.Role) {
+		return c.Status(400).JSON(fiber.Map{"error": "Role must be one of " + strings.Join(models.Roles, ", ")})
+	}
+
+	password := req.Password
+	generated := password == ""
+	if generated {
+		var err error
+		if password, err = auth.TemporaryPassword(); err != nil {
+			h.reportError(c, "Error creating user", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to create user"})
+		}
+	} else if len(password) < auth.MinPasswordLength {
+		return c.Status(400).JSON(fiber.Map{"error": "Passwords need at least 8 characters"})
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		h.reportError(c, "Error creating user", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create user"})
+	}
+
+	user, err := h.db.CreateUser(&req, hash, true)
+	if err != nil {
+		if err.Error() == "username already taken" {
+			return c.Status(409).JSON(fiber.Map{"error": "That username is already taken"})
+		}
+		h.reportError(c, "Error creating user", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create user"})
+	}
+
+	response := fiber.Map{"user": user}
+	if generated {
+		response["temporary_password"] = password
+	}
+	return c.Status(201).JSON(response)
+}
+
+// UpdateUser changes an account's name, email, role or disabled state.
+// Disabling an account signs it out everywhere. The last enabled admin
+// can't be disabled or given another role.
+func (h *Handler) UpdateUser(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+	var req models.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Role != nil && !models.ValidRole(*req.Role) {
+		return c.Status(400).JSON(fiber.Map{"error": "Role must be one of " + strings.Join(models.Roles, ", ")})
+	}
+
+	user, err := h.db.GetUser(id)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+		}
+		h.reportError(c, "Error updating user", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update user"})
+	}
+
+	losesAdmin := (req.Role != nil && *req.Role != models.RoleAdmin) || (req.Disabled != nil && *req.Disabled)
+	if user.Role == models.RoleAdmin && !user.Disabled && losesAdmin {
+		admins, err := h.db.CountActiveAdmins()
+		if err != nil {
+			h.reportError(c, "Error updating user", err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to update user"})
+		}
+		if admins <= 1 {
+			return c.Status(409).JSON(fiber.Map{"error": "This is the last admin; make someone else an admin first"})
+		}
+	}
+
+	user, err = h.db.UpdateUser(id, &req)
+	if err != nil {
+		h.reportError(c, "Error updating user", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update user"})
+	}
+	if user.Disabled {
+		if _, err := h.db.DeleteUserSessions(id, 0); err != nil {
+			h.reportError(c, "Error signing out disabled user", err)
+		}
+	}
+	return c.JSON(user)
+}
+
+// ResetUserPassword replaces an account's password with a temporary one,
+// returned once, and signs it out everywhere. The user has to choose a new
+// password at the next sign-in.
+func (h *Handler) ResetUserPassword(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	password, err := auth.TemporaryPassword()
+	if err != nil {
+		h.reportError(c, "Error resetting password", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reset password"})
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		h.reportError(c, "Error resetting password", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reset password"})
+	}
+	if err := h.db.SetUserPassword(id, hash, true); err != nil {
+		if err.Error() == "user not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+		}
+		h.reportError(c, "Error resetting password", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reset password"})
+	}
+	ended, err := h.db.DeleteUserSessions(id, 0)
+	if err != nil {
+		h.reportError(c, "Error signing out user", err)
+	}
+	return c.JSON(fiber.Map{"temporary_password": password, "sessions_ended": ended})
+}
+
+// GetSessions lists signed-in sessions, optionally of one user (?user_id=)
+func (h *Handler) GetSessions(c *fiber.Ctx) error {
+	userID := c.QueryInt("user_id", 0)
+	sessions, err := h.db.GetActiveSessions(userID)
+	if err != nil {
+		h.reportError(c, "Error listing sessions", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to list sessions"})
+	}
+	return c.JSON(sessions)
+}
+
+// DeleteSession signs one session out
+func (h *Handler) DeleteSession(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid session ID"})
+	}
+	if err := h.db.DeleteSession(id); err != nil {
+		if err.Error() == "session not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "Session not found"})
+		}
+		h.reportError(c, "Error ending session", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to end session"})
+	}
+	return c.JSON(fiber.Map{"message": "Session ended"})
+}
+
+// DeleteUserSessions signs a user out everywhere
+func (h *Handler) DeleteUserSessions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+	ended, err := h.db.DeleteUserSessions(id, 0)
+	if err != nil {
+		h.reportError(c, "Error ending sessions", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to end sessions"})
+	}
+	return c.JSON(fiber.Map{"sessions_ended": ended})
+}
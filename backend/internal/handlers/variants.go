@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourusername/audience-stage-teleprompter/internal/language"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// SetTransliterateOnSave makes created, updated and imported songs in an
+// Indic script get a romanized variant straight away, instead of only when
+// one is asked for
+func (h *Handler) SetTransliterateOnSave(enabled bool) {
+	h.transliterateOnSave = enabled
+}
+
+func validVariant(variant string) bool {
+	return variant == models.VariantRomanized
+}
+
+// needsRomanizing reports whether lyrics are mostly in a script congregants
+// may not be able to read
+func needsRomanizing(lyrics string) bool {
+	return language.Guess(lyrics).Script != "latin"
+}
+
+// romanizedLyrics returns a song's romanized lyrics: the stored variant if it
+// has one, otherwise generated from the display lyrics
+func (h *Handler) romanizedLyrics(song *models.Song) (string, error) {
+	variant, err := h.db.GetLyricVariant(song.ID, models.VariantRomanized)
+	if err != nil {
+		return "", err
+	}
+	if variant != nil {
+		return variant.Lyrics, nil
+	}
+	return language.Romanize(song.DisplayLyrics), nil
+}
+
+// refreshRomanized regenerates a saved song's romanized variant when
+// transliteration on save is on. Hand-edited variants are left alone.
+func (h *Handler) refreshRomanized(song *models.Song) {
+	if !h.transliterateOnSave || !needsRomanizing(song.DisplayLyrics) {
+		return
+	}
+	if _, err := h.db.SetLyricVariant(song.ID, models.VariantRomanized, language.Romanize(song.DisplayLyrics), true, false); err != nil {
+		log.Printf("Error saving romanized lyrics for song %s: %v", song.ID, err)
+	}
+}
+
+// GetLyricVariants lists a song's stored lyric variants
+func (h *Handler) GetLyricVariants(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	variants, err := h.db.GetLyricVariants(id)
+	if err != nil {
+		log.Printf("Error getting lyric variants: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get lyric variants"})
+	}
+
+	return c.JSON(variants)
+}
+
+// GetLyricVariant returns one of a song's stored lyric variants
+func (h *Handler) GetLyricVariant(c *fiber.Ctx) error {
+	id, name := c.Params("id"), c.Params("variant")
+	if !validVariant(name) {
+		return c.Status(400).JSON(fiber.Map{"error": "Unknown lyric variant"})
+	}
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	variant, err := h.db.GetLyricVariant(id, name)
+	if err != nil {
+		log.Printf("Error getting lyric variant: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get lyric variant"})
+	}
+	if variant == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "No such lyric variant for this song"})
+	}
+
+	return c.JSON(variant)
+}
+
+// UpdateLyricVariant replaces a lyric variant by hand. Hand-edited variants
+// are no longer regenerated when the song is saved.
+func (h *Handler) UpdateLyricVariant(c *fiber.Ctx) error {
+	id, name := c.Params("id"), c.Params("variant")
+	if !validVariant(name) {
+		return c.Status(400).JSON(fiber.Map{"error": "Unknown lyric variant"})
+	}
+
+	var req models.LyricVariantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Lyrics = lyrics.SanitizeMarkup(language.Normalize(req.Lyrics))
+	if req.Lyrics == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Lyrics are required"})
+	}
+
+	if _, err := h.db.GetSong(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+
+	variant, err := h.db.SetLyricVariant(id, name, req.Lyrics, false, true)
+	if err != nil {
+		log.Printf("Error saving lyric variant: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save lyric variant"})
+	}
+
+	return c.JSON(variant)
+}
+
+// DeleteLyricVariant removes one of a song's lyric variants
+func (h *Handler) DeleteLyricVariant(c *fiber.Ctx) error {
+	name := c.Params("variant")
+	if !validVariant(name) {
+		return c.Status(400).JSON(fiber.Map{"error": "Unknown lyric variant"})
+	}
+
+	if err := h.db.DeleteLyricVariant(c.Params("id"), name); err != nil {
+		if err.Error() == "lyric variant not found" {
+			return c.Status(404).JSON(fiber.Map{"error": "No such lyric variant for this song"})
+		}
+		log.Printf("Error deleting lyric variant: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete lyric variant"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Lyric variant deleted successfully"})
+}
+
+// TransliterateSong generates and stores a song's romanized variant. A
+// variant edited by hand is only replaced with ?force=true.
+func (h *Handler) TransliterateSong(c *fiber.Ctx) error {
+	song, err := h.db.GetSong(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Song not found"})
+	}
+	if !needsRomanizing(song.DisplayLyrics) {
+		return c.Status(400).JSON(fiber.Map{"error": "Song lyrics are already in Latin script"})
+	}
+
+	force := c.Query("force") == "true"
+	variant, err := h.db.SetLyricVariant(song.ID, models.VariantRomanized, language.Romanize(song.DisplayLyrics), true, force)
+	if err != nil {
+		log.Printf("Error saving romanized lyrics: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save romanized lyrics"})
+	}
+	if !variant.Generated {
+		return c.Status(409).JSON(fiber.Map{
+			"error":   "Romanized lyrics were edited by hand; use force=true to regenerate them",
+			"variant": variant,
+		})
+	}
+
+	return c.JSON(variant)
+}
@@ -0,0 +1,211 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// BulkRow is one song of a bulk import. Row numbers a CSV row by its line
+// in the file (the header is line 1) and a JSON song by its position from 1.
+type BulkRow struct {
+	Row     int
+	Request models.CreateSongRequest
+	Error   string // why the row couldn't be read
+}
+
+// bulkColumns maps the CSV header names (and their aliases) to the fields
+// they fill
+var bulkColumns = map[string]string{
+	"title":                 "title",
+	"artist":                "artist",
+	"author":                "artist",
+	"library":               "library",
+	"language":              "language",
+	"display_lyrics":        "display_lyrics",
+	"lyrics":                "display_lyrics",
+	"music_ministry_lyrics": "music_ministry_lyrics",
+	"chords":                "music_ministry_lyrics",
+	"original_key":          "original_key",
+	"key":                   "original_key",
+	"performance_key":       "performance_key",
+	"bpm":                   "bpm",
+	"tempo":                 "bpm",
+	"time_signature":        "time_signature",
+	"count_in_beats":        "count_in_beats",
+	"copyright":             "copyright",
+	"ccli_number":           "ccli_number",
+	"ccli":                  "ccli_number",
+	"chordpro":              "chordpro",
+	"public":                "public",
+}
+
+// ParseBulkCSV reads songs from CSV with a header row naming the columns:
+// title, artist, library, language, display_lyrics (or lyrics),
+// music_ministry_lyrics (or chords), original_key (or key),
+// performance_key, bpm, time_signature, count_in_beats, copyright,
+// ccli_number, chordpro (a ChordPro chart) and public. Lyrics are quoted fields spanning several lines.
+// An unknown column fails the whole file, so a misspelt header doesn't
+// silently drop a field.
+func ParseBulkCSV(data []byte) ([]BulkRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // spreadsheet BOM
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the CSV is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+	fields := make([]string, len(header))
+	hasTitle := false
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		field, ok := bulkColumns[name]
+		if !ok && name != "" {
+			return nil, fmt.Errorf("unknown CSV column %q", header[i])
+		}
+		fields[i] = field
+		hasTitle = hasTitle || field == "title"
+	}
+	if !hasTitle {
+		return nil, fmt.Errorf("the CSV needs a title column")
+	}
+
+	var rows []BulkRow
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			parseErr, ok := err.(*csv.ParseError)
+			if !ok {
+				return nil, fmt.Errorf("error reading CSV: %w", err)
+			}
+			// A malformed quote can't be recovered from reliably, so the
+			// rest of the file is left out
+			return append(rows, BulkRow{Row: parseErr.StartLine, Error: parseErr.Err.Error() + "; rows after it weren't read"}), nil
+		}
+		line, _ := r.FieldPos(0)
+		if blankRecord(record) {
+			continue
+		}
+
+		row := BulkRow{Row: line}
+		for i, value := range record {
+			if i >= len(fields) {
+				if strings.TrimSpace(value) != "" {
+					row.Error = fmt.Sprintf("more columns than the header (%d)", len(fields))
+				}
+				continue
+			}
+			if err := setBulkField(&row.Request, fields[i], value); err != nil {
+				row.Error = err.Error()
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func blankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// setBulkField sets a song field from its CSV text; empty values leave the
+// field unset
+func setBulkField(req *models.CreateSongRequest, field, value string) error {
+	if field != "display_lyrics" && field != "music_ministry_lyrics" && field != "chordpro" {
+		value = strings.TrimSpace(value)
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	optional := func() *string { return &value }
+	number := func() (*int, error) {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a whole number, not %q", field, value)
+		}
+		return &n, nil
+	}
+
+	var err error
+	switch field {
+	case "title":
+		req.Title = value
+	case "artist":
+		req.Artist = optional()
+	case "library":
+		req.Library = value
+	case "language":
+		req.Language = strings.ToLower(value)
+	case "display_lyrics":
+		req.DisplayLyrics = value
+	case "music_ministry_lyrics":
+		req.MusicMinistryLyrics = value
+	case "original_key":
+		req.OriginalKey = optional()
+	case "performance_key":
+		req.PerformanceKey = optional()
+	case "bpm":
+		req.BPM, err = number()
+	case "time_signature":
+		req.TimeSignature = optional()
+	case "count_in_beats":
+		req.CountInBeats, err = number()
+	case "copyright":
+		req.Copyright = optional()
+	case "ccli_number":
+		req.CCLINumber = optional()
+	case "chordpro":
+		req.Chords = optional()
+	case "public":
+		if req.Public, err = strconv.ParseBool(strings.ToLower(value)); err != nil {
+			err = fmt.Errorf("public must be true or false, not %q", value)
+		}
+	}
+	return err
+}
+
+// ParseBulkJSON reads songs from a JSON array of song objects as sent to
+// POST /api/songs, or an object with the array under "songs". A song that
+// doesn't fit the fields becomes a row with an error instead of failing the
+// rest.
+func ParseBulkJSON(data []byte) ([]BulkRow, error) {
+	var songs []json.RawMessage
+	if err := json.Unmarshal(data, &songs); err != nil {
+		var wrapped struct {
+			Songs []json.RawMessage `json:"songs"`
+		}
+		if err2 := json.Unmarshal(data, &wrapped); err2 != nil || wrapped.Songs == nil {
+			return nil, fmt.Errorf("expected a JSON array of songs: %w", err)
+		}
+		songs = wrapped.Songs
+	}
+
+	rows := make([]BulkRow, len(songs))
+	for i, raw := range songs {
+		rows[i].Row = i + 1
+		if err := json.Unmarshal(raw, &rows[i].Request); err != nil {
+			rows[i].Error = "invalid song: " + err.Error()
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/language"
+)
+
+var sqliteMagic = []byte("SQLite format 3\x00")
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// ParseEasyWorship reads songs from EasyWorship databases. EasyWorship 6/7
+// keep songs in Songs.db and their words (as RTF) in SongWords.db, both SQLite;
+// older Access exports (.mdb) hold both in one file. SQLite is read with the
+// sqlite3 command line tool and Access with mdb-export (mdbtools).
+//
+// When language is "auto" (or empty) it is detected from each song's words.
+func ParseEasyWorship(files []File, lang, library string) ([]Song, error) {
+	dir, err := os.MkdirTemp("", "easyworship-import-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var songsDB, wordsDB, accessDB string
+	for _, f := range files {
+		name := strings.ToLower(path.Base(strings.ReplaceAll(f.Name, "\\", "/")))
+		target := ""
+		switch {
+		case name == "songs.db" && bytes.HasPrefix(f.Data, sqliteMagic):
+			target = filepath.Join(dir, "Songs.db")
+			songsDB = target
+		case name == "songwords.db" && bytes.HasPrefix(f.Data, sqliteMagic):
+			target = filepath.Join(dir, "SongWords.db")
+			wordsDB = target
+		case strings.HasSuffix(name, ".mdb") || strings.HasSuffix(name, ".accdb"):
+			target = filepath.Join(dir, "songs"+path.Ext(name))
+			accessDB = target
+		default:
+			continue
+		}
+		if err := os.WriteFile(target, f.Data, 0600); err != nil {
+			return nil, fmt.Errorf("error writing %s: %w", f.Name, err)
+		}
+	}
+
+	var records []easyWorshipRecord
+	switch {
+	case songsDB != "" && wordsDB != "":
+		records, err = readEasyWorshipSQLite(songsDB, wordsDB)
+	case accessDB != "":
+		records, err = readEasyWorshipAccess(accessDB)
+	case songsDB != "":
+		return nil, fmt.Errorf("SongWords.db is required alongside Songs.db")
+	default:
+		return nil, fmt.Errorf("no EasyWorship database found (expected Songs.db and SongWords.db, or an .mdb file)")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	songs := make([]Song, 0, len(records))
+	for _, r := range records {
+		lyrics := cleanEasyWorshipWords(r.words)
+		title := strings.TrimSpace(r.title)
+		if title == "" || lyrics == "" {
+			continue
+		}
+
+		songLang := lang
+		if songLang == "" || songLang == "auto" {
+			songLang = language.Detect(title + "\n" + lyrics)
+		}
+
+		song := Song{Source: fmt.Sprintf("EasyWorship song %s", r.id)}
+		song.Request.Title = title
+		song.Request.Language = songLang
+		song.Request.Library = library
+		song.Request.DisplayLyrics = lyrics
+		if author := strings.TrimSpace(r.author); author != "" {
+			song.Request.Artist = &author
+		}
+		songs = append(songs, song)
+	}
+
+	return songs, nil
+}
+
+type easyWorshipRecord struct {
+	id     string
+	title  string
+	author string
+	words  string
+}
+
+func readEasyWorshipSQLite(songsDB, wordsDB string) ([]easyWorshipRecord, error) {
+	songRows, err := runCSV("sqlite3", "-readonly", "-csv", "-header", songsDB,
+		"SELECT rowid AS id, title, COALESCE(author, '') AS author FROM song")
+	if err != nil {
+		return nil, fmt.Errorf("error reading Songs.db: %w", err)
+	}
+	wordRows, err := runCSV("sqlite3", "-readonly", "-csv", "-header", wordsDB,
+		"SELECT song_id, words FROM word")
+	if err != nil {
+		return nil, fmt.Errorf("error reading SongWords.db: %w", err)
+	}
+
+	words := make(map[string]string, len(wordRows))
+	for _, row := range wordRows {
+		words[row["song_id"]] = row["words"]
+	}
+
+	records := make([]easyWorshipRecord, 0, len(songRows))
+	for _, row := range songRows {
+		records = append(records, easyWorshipRecord{
+			id:     row["id"],
+			title:  row["title"],
+			author: row["author"],
+			words:  words[row["id"]],
+		})
+	}
+	return records, nil
+}
+
+func readEasyWorshipAccess(file string) ([]easyWorshipRecord, error) {
+	rows, err := runCSV("mdb-export", file, "Songs")
+	if err != nil {
+		return nil, fmt.Errorf("error reading Access database: %w", err)
+	}
+
+	records := make([]easyWorshipRecord, 0, len(rows))
+	for i, row := range rows {
+		id := row["rec_id"]
+		if id == "" {
+			id = fmt.Sprintf("%d", i+1)
+		}
+		records = append(records, easyWorshipRecord{
+			id:     id,
+			title:  row["title"],
+			author: row["author"],
+			words:  row["words"],
+		})
+	}
+	return records, nil
+}
+
+// runCSV runs a command that prints CSV with a header row and returns the rows
+// keyed by lower-cased column name
+func runCSV(name string, args ...string) ([]map[string]string, error) {
+	cmd := exec.Command(name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w, output: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	reader := csv.NewReader(bytes.NewReader(output))
+	reader.FieldsPerRecord = -1
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s output: %w", name, err)
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	header := lines[0]
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+
+	rows := make([]map[string]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		row := make(map[string]string, len(header))
+		for i, value := range line {
+			if i < len(header) {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// cleanEasyWorshipWords converts stored RTF words into our lyrics text
+func cleanEasyWorshipWords(words string) string {
+	text := strings.ReplaceAll(rtfToText(words), "\r\n", "\n")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = strings.Join(lines, "\n")
+
+	return strings.TrimSpace(blankLines.ReplaceAllString(text, "\n\n"))
+}
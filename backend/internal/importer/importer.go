@@ -0,0 +1,96 @@
+// Package importer converts song files from other worship software into
+// song requests for this system.
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// maxFileSize guards against zip bombs and accidental uploads of huge files
+const maxFileSize = 10 << 20
+
+// File is a single uploaded (or unzipped) file
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Song is a parsed song together with the file it came from
+type Song struct {
+	Source  string
+	Request models.CreateSongRequest
+}
+
+// ExpandZips returns the given files with any zip archives replaced by their contents.
+// Directories, hidden files and macOS metadata inside archives are skipped.
+func ExpandZips(files []File) ([]File, error) {
+	expanded := make([]File, 0, len(files))
+	for _, f := range files {
+		if !strings.EqualFold(path.Ext(f.Name), ".zip") {
+			expanded = append(expanded, f)
+			continue
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(f.Data), int64(len(f.Data)))
+		if err != nil {
+			return nil, fmt.Errorf("error reading zip %s: %w", f.Name, err)
+		}
+
+		for _, zf := range zr.File {
+			base := path.Base(zf.Name)
+			if zf.FileInfo().IsDir() || strings.HasPrefix(base, ".") || strings.HasPrefix(zf.Name, "__MACOSX/") {
+				continue
+			}
+			if zf.UncompressedSize64 > maxFileSize {
+				return nil, fmt.Errorf("file %s in %s is too large", zf.Name, f.Name)
+			}
+
+			rc, err := zf.Open()
+			if err != nil {
+				return nil, fmt.Errorf("error opening %s in %s: %w", zf.Name, f.Name, err)
+			}
+			data, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+			rc.Close()
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s in %s: %w", zf.Name, f.Name, err)
+			}
+
+			expanded = append(expanded, File{Name: zf.Name, Data: data})
+		}
+	}
+	return expanded, nil
+}
+
+// titleFromFileName is the fallback title for formats where it is optional
+func titleFromFileName(name string) string {
+	base := path.Base(strings.ReplaceAll(name, "\\", "/"))
+	return strings.TrimSpace(strings.TrimSuffix(base, path.Ext(base)))
+}
+
+// joinSections renders sections in the "Label\nline\nline" blocks used by the lyrics package
+func joinSections(sections []section) string {
+	blocks := make([]string, 0, len(sections))
+	for _, s := range sections {
+		if len(s.lines) == 0 {
+			continue
+		}
+		block := strings.Join(s.lines, "\n")
+		if s.label != "" {
+			block = s.label + "\n" + block
+		}
+		blocks = append(blocks, block)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+type section struct {
+	label string
+	lines []string
+}
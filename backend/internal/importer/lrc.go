@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+var (
+	// lrcTimestamp matches a line timestamp: [mm:ss], [mm:ss.xx] or [mm:ss:xx]
+	lrcTimestamp = regexp.MustCompile(`^\[(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	// lrcTag matches an ID tag such as [ti:Title] or [offset:+250]
+	lrcTag = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+	// lrcWordTime matches enhanced-LRC word timings like <00:12.34>
+	lrcWordTime = regexp.MustCompile(`<\d{1,3}:\d{1,2}(?:[.:]\d{1,3})?>`)
+)
+
+// LRC is a parsed LRC (timed lyrics) file
+type LRC struct {
+	Title    string
+	Artist   string
+	OffsetMs int
+	Lines    []models.TimedLine
+}
+
+// ParseLRC reads an LRC file. Lines may carry several timestamps (a repeated
+// chorus); each becomes its own timed line. Enhanced word timings are dropped
+// and lines are returned in time order.
+func ParseLRC(data []byte) (*LRC, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	lrc := &LRC{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var times []int
+		for {
+			m := lrcTimestamp.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+			times = append(times, lrcMillis(m[1], m[2], m[3]))
+			line = strings.TrimSpace(line[len(m[0]):])
+		}
+
+		if len(times) == 0 {
+			if m := lrcTag.FindStringSubmatch(line); m != nil {
+				lrc.applyTag(strings.ToLower(m[1]), strings.TrimSpace(m[2]))
+			}
+			continue
+		}
+
+		text := strings.Join(strings.Fields(lrcWordTime.ReplaceAllString(line, "")), " ")
+		for _, t := range times {
+			lrc.Lines = append(lrc.Lines, models.TimedLine{TimeMs: t, Text: text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading LRC file: %w", err)
+	}
+	if len(lrc.Lines) == 0 {
+		return nil, fmt.Errorf("no timed lines found")
+	}
+
+	sort.SliceStable(lrc.Lines, func(i, j int) bool {
+		return lrc.Lines[i].TimeMs < lrc.Lines[j].TimeMs
+	})
+	return lrc, nil
+}
+
+func (lrc *LRC) applyTag(tag, value string) {
+	switch tag {
+	case "ti":
+		lrc.Title = value
+	case "ar":
+		lrc.Artist = value
+	case "offset":
+		if n, err := strconv.Atoi(strings.TrimPrefix(value, "+")); err == nil {
+			lrc.OffsetMs = n
+		}
+	}
+}
+
+// lrcMillis converts timestamp parts to milliseconds. The fraction is read as
+// written: ".5" is 500ms, ".05" 50ms and ".005" 5ms.
+func lrcMillis(min, sec, frac string) int {
+	m, _ := strconv.Atoi(min)
+	s, _ := strconv.Atoi(sec)
+	ms := 0
+	if frac != "" {
+		f, _ := strconv.Atoi(frac)
+		for i := len(frac); i < 3; i++ {
+			f *= 10
+		}
+		ms = f
+	}
+	return (m*60+s)*1000 + ms
+}
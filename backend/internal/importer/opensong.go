@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/music"
+)
+
+type openSongXML struct {
+	XMLName   xml.Name `xml:"song"`
+	Title     string   `xml:"title"`
+	Author    string   `xml:"author"`
+	Lyrics    string   `xml:"lyrics"`
+	Key       string   `xml:"key"`
+	Tempo     string   `xml:"tempo"`
+	TimeSig   string   `xml:"timesig"`
+	Copyright string   `xml:"copyright"`
+	CCLI      string   `xml:"ccli"`
+}
+
+var openSongHeader = regexp.MustCompile(`^\[\s*([A-Za-z]+)\s*(\d*)\s*\]`)
+
+var openSongLabels = map[string]string{
+	"V": "Verse",
+	"C": "Chorus",
+	"B": "Bridge",
+	"P": "Pre-Chorus",
+	"T": "Tag",
+	"I": "Intro",
+	"E": "Ending",
+	"O": "Outro",
+}
+
+// IsOpenSong reports whether the data looks like an OpenSong song file.
+// OpenSong files usually have no extension, so the content is checked instead.
+func IsOpenSong(data []byte) bool {
+	head := data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	return bytes.Contains(head, []byte("<song"))
+}
+
+// ParseOpenSong converts an OpenSong XML song into a song request, mapping
+// [V1]/[C]/[B] style headers onto our verse/chorus/bridge sections
+func ParseOpenSong(f File, language, library string) (*Song, error) {
+	var doc openSongXML
+	decoder := xml.NewDecoder(bytes.NewReader(f.Data))
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		// Older OpenSong files declare latin-1 but are read well enough as UTF-8
+		return input, nil
+	}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenSong XML: %w", err)
+	}
+
+	title := strings.TrimSpace(doc.Title)
+	if title == "" {
+		title = titleFromFileName(f.Name)
+	}
+	lyrics := parseOpenSongLyrics(doc.Lyrics)
+	if lyrics == "" {
+		return nil, fmt.Errorf("song has no lyrics")
+	}
+
+	song := &Song{Source: f.Name}
+	req := &song.Request
+	req.Title = title
+	req.Language = language
+	req.Library = library
+	req.DisplayLyrics = lyrics
+	if author := strings.TrimSpace(doc.Author); author != "" {
+		req.Artist = &author
+	}
+	if key, err := music.ParseKey(doc.Key); err == nil {
+		name := key.String()
+		req.OriginalKey = &name
+	}
+	if bpm, err := strconv.Atoi(strings.TrimSpace(doc.Tempo)); err == nil && bpm >= 20 && bpm <= 300 {
+		req.BPM = &bpm
+	}
+	if sig := strings.TrimSpace(doc.TimeSig); sig != "" {
+		req.TimeSignature = &sig
+	}
+	if copyright := strings.TrimSpace(doc.Copyright); copyright != "" {
+		req.Copyright = &copyright
+	}
+	if ccli := strings.TrimSpace(doc.CCLI); ccli != "" {
+		req.CCLINumber = &ccli
+	}
+
+	return song, nil
+}
+
+// parseOpenSongLyrics drops chord and comment lines, strips OpenSong markup and
+// splits numbered multi-verse blocks ("1 ...", "2 ...") into separate verses
+func parseOpenSongLyrics(raw string) string {
+	var sections []section
+	var current *section
+	var numbered map[int][]string
+	var numberedLabel string
+
+	flushNumbered := func() {
+		if numbered == nil {
+			return
+		}
+		numbers := make([]int, 0, len(numbered))
+		for n := range numbered {
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+		for _, n := range numbers {
+			sections = append(sections, section{label: fmt.Sprintf("%s %d", numberedLabel, n), lines: numbered[n]})
+		}
+		numbered = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "["):
+			flushNumbered()
+			label := ""
+			if m := openSongHeader.FindStringSubmatch(line); m != nil {
+				label = openSongLabel(m[1], m[2])
+			}
+			sections = append(sections, section{label: label})
+			current = &sections[len(sections)-1]
+			continue
+		case strings.HasPrefix(line, "."), strings.HasPrefix(line, ";"):
+			continue
+		}
+
+		if current == nil {
+			sections = append(sections, section{})
+			current = &sections[len(sections)-1]
+		}
+
+		// Numbered lines belong to verse N of the current block
+		if len(line) > 0 && line[0] >= '1' && line[0] <= '9' {
+			if numbered == nil {
+				numbered = make(map[int][]string)
+				numberedLabel = current.label
+				if numberedLabel == "" || numberedLabel == "Verse 1" {
+					numberedLabel = "Verse"
+				}
+			}
+			n := int(line[0] - '0')
+			for _, text := range strings.Split(line[1:], "|") {
+				if text = cleanOpenSongLine(text); text != "" {
+					numbered[n] = append(numbered[n], text)
+				}
+			}
+			continue
+		}
+
+		for _, text := range strings.Split(line, "|") {
+			if text = cleanOpenSongLine(text); text != "" {
+				current.lines = append(current.lines, text)
+			}
+		}
+	}
+	flushNumbered()
+
+	return joinSections(sections)
+}
+
+func openSongLabel(code, number string) string {
+	label, ok := openSongLabels[strings.ToUpper(code[:1])]
+	if !ok || len(code) > 1 {
+		label = code
+	}
+	if number != "" {
+		label += " " + number
+	}
+	return label
+}
+
+// cleanOpenSongLine removes chord-alignment underscores and surrounding space
+func cleanOpenSongLine(text string) string {
+	text = strings.ReplaceAll(text, "_", "")
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Destinations whose contents are never visible text
+var rtfSkipDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "header": true, "footer": true, "listtable": true,
+	"listoverridetable": true, "generator": true, "themedata": true,
+}
+
+// Windows-1252 characters in the 0x80-0x9F range
+var cp1252 = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+type rtfState struct {
+	skip   bool
+	ucSkip int
+}
+
+// rtfToText extracts plain text from the RTF EasyWorship stores song words in.
+// Text that is not RTF is returned unchanged.
+func rtfToText(rtf string) string {
+	if !strings.HasPrefix(strings.TrimSpace(rtf), `{\rtf`) {
+		return rtf
+	}
+
+	var out strings.Builder
+	state := rtfState{ucSkip: 1}
+	var stack []rtfState
+	pendingSkip := 0 // fallback characters to drop after a \u escape
+
+	emit := func(r rune) {
+		if pendingSkip > 0 {
+			pendingSkip--
+			return
+		}
+		if !state.skip {
+			out.WriteRune(r)
+		}
+	}
+
+	for i := 0; i < len(rtf); i++ {
+		ch := rtf[i]
+		switch ch {
+		case '{':
+			stack = append(stack, state)
+			pendingSkip = 0
+			if strings.HasPrefix(rtf[i+1:], `\*`) {
+				state.skip = true
+			}
+		case '}':
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+			pendingSkip = 0
+		case '\r', '\n':
+			// line breaks in RTF source are not significant
+		case '\\':
+			if i+1 >= len(rtf) {
+				break
+			}
+			next := rtf[i+1]
+			switch {
+			case next == '\\' || next == '{' || next == '}':
+				emit(rune(next))
+				i++
+			case next == '\'':
+				if i+3 < len(rtf) {
+					if b, err := strconv.ParseUint(rtf[i+2:i+4], 16, 8); err == nil {
+						if r, ok := cp1252[byte(b)]; ok {
+							emit(r)
+						} else {
+							emit(rune(b))
+						}
+					}
+				}
+				i += 3
+			case next == '~':
+				emit(' ')
+				i++
+			case isASCIILetter(next):
+				j := i + 1
+				for j < len(rtf) && isASCIILetter(rtf[j]) {
+					j++
+				}
+				word := rtf[i+1 : j]
+				k := j
+				if k < len(rtf) && (rtf[k] == '-' || (rtf[k] >= '0' && rtf[k] <= '9')) {
+					k++
+					for k < len(rtf) && rtf[k] >= '0' && rtf[k] <= '9' {
+						k++
+					}
+				}
+				param, hasParam := 0, k > j
+				if hasParam {
+					param, _ = strconv.Atoi(rtf[j:k])
+				}
+				if k < len(rtf) && rtf[k] == ' ' {
+					k++
+				}
+				i = k - 1
+
+				switch word {
+				case "par", "line":
+					emit('\n')
+				case "tab":
+					emit('\t')
+				case "uc":
+					state.ucSkip = param
+				case "u":
+					if param < 0 {
+						param += 65536
+					}
+					emit(rune(param))
+					pendingSkip = state.ucSkip
+				default:
+					if rtfSkipDestinations[word] {
+						state.skip = true
+					}
+				}
+			default:
+				// other control symbols (\-, \_, \|, ...) carry no visible text
+				i++
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(rtf[i:])
+			emit(r)
+			i += size - 1
+		}
+	}
+
+	return out.String()
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/language"
+)
+
+type videoPsalmBook struct {
+	Text  string           `json:"Text"`
+	Songs []videoPsalmSong `json:"Songs"`
+}
+
+type videoPsalmSong struct {
+	Text      string            `json:"Text"` // title
+	Author    string            `json:"Author"`
+	Composer  string            `json:"Composer"`
+	Copyright string            `json:"Copyright"`
+	Verses    []videoPsalmVerse `json:"Verses"`
+}
+
+type videoPsalmVerse struct {
+	Text string `json:"Text"`
+	Tag  int    `json:"Tag"`
+}
+
+// VideoPsalm verse tags
+var videoPsalmTags = map[int]string{
+	1: "Verse",
+	2: "Chorus",
+	3: "Bridge",
+	4: "Pre-Chorus",
+	5: "Tag",
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ParseVideoPsalm reads a VideoPsalm songbook, either a .json export or a .vpc
+// bundle (a zip holding the .json). When language is "auto" (or empty) it is
+// detected per song, since bundles often mix languages.
+func ParseVideoPsalm(f File, lang, library string) ([]Song, error) {
+	data := f.Data
+	if bytes.HasPrefix(data, []byte("PK")) {
+		files, err := ExpandZips([]File{{Name: f.Name + ".zip", Data: data}})
+		if err != nil {
+			return nil, err
+		}
+		data = nil
+		for _, inner := range files {
+			if strings.EqualFold(path.Ext(inner.Name), ".json") {
+				data = inner.Data
+				break
+			}
+		}
+		if data == nil {
+			return nil, fmt.Errorf("no songbook .json found in %s", f.Name)
+		}
+	}
+
+	var book videoPsalmBook
+	if err := json.Unmarshal(quoteJSONKeys(bytes.TrimPrefix(data, utf8BOM)), &book); err != nil {
+		return nil, fmt.Errorf("invalid VideoPsalm songbook: %w", err)
+	}
+
+	if library == "" {
+		library = strings.TrimSpace(book.Text)
+	}
+	if library == "" {
+		library = "VideoPsalm"
+	}
+
+	songs := make([]Song, 0, len(book.Songs))
+	for i, s := range book.Songs {
+		title := strings.TrimSpace(s.Text)
+		lyrics := videoPsalmLyrics(s.Verses)
+		if title == "" || lyrics == "" {
+			continue
+		}
+
+		songLang := lang
+		if songLang == "" || songLang == "auto" {
+			songLang = language.Detect(title + "\n" + lyrics)
+		}
+
+		song := Song{Source: fmt.Sprintf("%s #%d", f.Name, i+1)}
+		song.Request.Title = title
+		song.Request.Language = songLang
+		song.Request.Library = library
+		song.Request.DisplayLyrics = lyrics
+		author := strings.TrimSpace(s.Author)
+		if author == "" {
+			author = strings.TrimSpace(s.Composer)
+		}
+		if author != "" {
+			song.Request.Artist = &author
+		}
+		if copyright := strings.TrimSpace(s.Copyright); copyright != "" {
+			song.Request.Copyright = &copyright
+		}
+		songs = append(songs, song)
+	}
+
+	return songs, nil
+}
+
+// videoPsalmLyrics numbers repeated section types (Verse 1, Verse 2, ...)
+func videoPsalmLyrics(verses []videoPsalmVerse) string {
+	counts := make(map[string]int)
+	totals := make(map[string]int)
+	for _, v := range verses {
+		totals[videoPsalmLabel(v.Tag)]++
+	}
+
+	sections := make([]section, 0, len(verses))
+	for _, v := range verses {
+		label := videoPsalmLabel(v.Tag)
+		counts[label]++
+		if totals[label] > 1 || label == "Verse" {
+			label = fmt.Sprintf("%s %d", label, counts[videoPsalmLabel(v.Tag)])
+		}
+
+		var lines []string
+		text := strings.NewReplacer("\r\n", "\n", "<br>", "\n", "<br/>", "\n").Replace(v.Text)
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		sections = append(sections, section{label: label, lines: lines})
+	}
+
+	return joinSections(sections)
+}
+
+func videoPsalmLabel(tag int) string {
+	if label, ok := videoPsalmTags[tag]; ok {
+		return label
+	}
+	return "Verse"
+}
+
+// quoteJSONKeys turns VideoPsalm's JavaScript-style object literals
+// ({Text:"..."}) into JSON by quoting bare keys outside of strings
+func quoteJSONKeys(data []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(data) + len(data)/8)
+
+	inString := false
+	expectKey := false
+	var containers []byte // open '{' and '[' so array values are never quoted
+	for i := 0; i < len(data); i++ {
+		ch := data[i]
+
+		if inString {
+			out.WriteByte(ch)
+			if ch == '\\' && i+1 < len(data) {
+				i++
+				out.WriteByte(data[i])
+			} else if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case ch == '"':
+			inString = true
+			expectKey = false
+			out.WriteByte(ch)
+		case ch == '{' || ch == '[':
+			containers = append(containers, ch)
+			expectKey = ch == '{'
+			out.WriteByte(ch)
+		case ch == '}' || ch == ']':
+			if len(containers) > 0 {
+				containers = containers[:len(containers)-1]
+			}
+			expectKey = false
+			out.WriteByte(ch)
+		case ch == ',':
+			expectKey = len(containers) > 0 && containers[len(containers)-1] == '{'
+			out.WriteByte(ch)
+		case expectKey && (isASCIILetter(ch) || ch == '_'):
+			j := i
+			for j < len(data) && (isASCIILetter(data[j]) || data[j] == '_' || (data[j] >= '0' && data[j] <= '9')) {
+				j++
+			}
+			out.WriteByte('"')
+			out.Write(data[i:j])
+			out.WriteByte('"')
+			i = j - 1
+			expectKey = false
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			out.WriteByte(ch)
+		default:
+			expectKey = false
+			out.WriteByte(ch)
+		}
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,196 @@
+// Package jobs runs long admin tasks (such as a full reindex) in the
+// background and keeps their progress for polling.
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job states
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Finished jobs kept for polling; older ones are forgotten
+const keepFinished = 20
+
+// Only the first errors are kept so a systemic failure can't grow without bound
+const maxErrors = 50
+
+// Status is a point-in-time copy of a job's progress
+type Status struct {
+	ID           string      `json:"id"`
+	Kind         string      `json:"kind"`
+	Status       string      `json:"status"`
+	Total        int         `json:"total"`
+	Processed    int         `json:"processed"`
+	Failed       int         `json:"failed"`
+	CurrentBatch int         `json:"current_batch"`
+	Batches      int         `json:"batches"`
+	Errors       []string    `json:"errors"`
+	Error        string      `json:"error,omitempty"`
+	Result       interface{} `json:"result,omitempty"`
+	StartedAt    time.Time   `json:"started_at"`
+	FinishedAt   *time.Time  `json:"finished_at,omitempty"`
+}
+
+// Job is a running task. The task function reports progress through it.
+type Job struct {
+	mu     sync.Mutex
+	status Status
+}
+
+// SetTotal records how many items the job will process
+func (j *Job) SetTotal(total, batches int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Total = total
+	j.status.Batches = batches
+}
+
+// StartBatch records which batch (1-based) is being worked on
+func (j *Job) StartBatch(batch int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.CurrentBatch = batch
+}
+
+// Done records processed items
+func (j *Job) Done(n int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Processed += n
+}
+
+// Fail records an item that could not be processed
+func (j *Job) Fail(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Processed++
+	j.status.Failed++
+	if len(j.status.Errors) < maxErrors {
+		j.status.Errors = append(j.status.Errors, err.Error())
+	}
+}
+
+// SetResult records what the job produced, e.g. a report
+func (j *Job) SetResult(result interface{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Result = result
+}
+
+// Status returns a copy of the job's progress
+func (j *Job) Status() Status {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	status.Errors = append([]string(nil), j.status.Errors...)
+	return status
+}
+
+func (j *Job) finish(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	j.status.FinishedAt = &now
+	j.status.Status = StatusCompleted
+	if err != nil {
+		j.status.Status = StatusFailed
+		j.status.Error = err.Error()
+	}
+}
+
+// Manager starts jobs and looks them up by ID
+type Manager struct {
+	mu    sync.Mutex
+	jobs  map[string]*Job
+	order []string
+}
+
+func NewManager() *Manager {
+	return &Manager{jobs: make(map[string]*Job)}
+}
+
+// Start runs fn in the background as a job of the given kind. Only one job of
+// a kind runs at a time: if one is already running it is returned with ok false.
+func (m *Manager) Start(kind string, fn func(*Job) error) (job *Job, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range m.order {
+		existing := m.jobs[id]
+		if s := existing.Status(); s.Kind == kind && s.Status == StatusRunning {
+			return existing, false
+		}
+	}
+
+	job = &Job{status: Status{
+		ID:        newID(),
+		Kind:      kind,
+		Status:    StatusRunning,
+		Errors:    make([]string, 0),
+		StartedAt: time.Now(),
+	}}
+	m.jobs[job.status.ID] = job
+	m.order = append(m.order, job.status.ID)
+	m.prune()
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Job %s (%s) panicked: %v", job.status.ID, kind, r)
+				job.finish(fmt.Errorf("job panicked: %v", r))
+			}
+		}()
+		err := fn(job)
+		if err != nil {
+			log.Printf("Job %s (%s) failed: %v", job.status.ID, kind, err)
+		}
+		job.finish(err)
+	}()
+
+	return job, true
+}
+
+// Get returns a job's progress
+func (m *Manager) Get(id string) (Status, bool) {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return Status{}, false
+	}
+	return job.Status(), true
+}
+
+// prune forgets the oldest finished jobs beyond keepFinished
+func (m *Manager) prune() {
+	finished := 0
+	for i := len(m.order) - 1; i >= 0; i-- {
+		id := m.order[i]
+		if m.jobs[id].Status().Status == StatusRunning {
+			continue
+		}
+		finished++
+		if finished > keepFinished {
+			delete(m.jobs, id)
+			m.order = append(m.order[:i], m.order[i+1:]...)
+		}
+	}
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
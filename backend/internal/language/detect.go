@@ -0,0 +1,145 @@
+// Package language makes a best-effort guess at a song's language from the
+// Unicode scripts used in its lyrics, telling Latin-script languages apart
+// with trigram profiles.
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Languages supported by the library, as stored in songs.language
+const (
+	English   = "english"
+	Malayalam = "malayalam"
+	Hindi     = "hindi"
+	Tamil     = "tamil"
+	Telugu    = "telugu"
+	Kannada   = "kannada"
+)
+
+// ISO 639-1 codes, as used by OpenLyrics and other interchange formats
+var codes = map[string]string{
+	English:    "en",
+	Malayalam:  "ml",
+	Hindi:      "hi",
+	Tamil:      "ta",
+	Telugu:     "te",
+	Kannada:    "kn",
+	Spanish:    "es",
+	French:     "fr",
+	German:     "de",
+	Portuguese: "pt",
+}
+
+var scripts = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Malayalam, Malayalam},
+	{unicode.Devanagari, Hindi},
+	{unicode.Tamil, Tamil},
+	{unicode.Telugu, Telugu},
+	{unicode.Kannada, Kannada},
+}
+
+// Result is a detected language and how sure the detector is of it
+type Result struct {
+	Language   string  `json:"language"`
+	Script     string  `json:"script"` // "latin" or the language's own script
+	Confidence float64 `json:"confidence"`
+}
+
+// Detect returns the most likely language of text. Text that is mostly in an
+// Indic script is that script's language; mostly Latin text is matched
+// against the trigram profiles, and text too short for them (or with no
+// letters at all) is treated as English.
+func Detect(text string) string {
+	return Guess(text).Language
+}
+
+// Guess detects the language of text with its confidence
+func Guess(text string) Result {
+	counts := make(map[string]int)
+	latin, total := 0, 0
+
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) {
+			continue
+		}
+		total++
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, s := range scripts {
+			if unicode.Is(s.table, r) {
+				counts[s.language]++
+				break
+			}
+		}
+	}
+	if total == 0 {
+		return Result{Language: English, Script: "latin"}
+	}
+
+	best, bestCount := English, latin
+	for _, s := range scripts {
+		if counts[s.language] > bestCount {
+			best, bestCount = s.language, counts[s.language]
+		}
+	}
+	share := float64(bestCount) / float64(total)
+	if best != English {
+		return Result{Language: best, Script: best, Confidence: share}
+	}
+
+	if latin < minTrigramLetters {
+		return Result{Language: English, Script: "latin"}
+	}
+	lang, confidence := guessLatin(text)
+	return Result{Language: lang, Script: "latin", Confidence: confidence * share}
+}
+
+// Confidence thresholds for flagging a declared language as wrong. Scripts
+// are unambiguous; the trigram margin is much smaller even when it is right.
+const (
+	scriptMismatchConfidence  = 0.6
+	trigramMismatchConfidence = 0.05
+)
+
+// Mismatch reports whether text confidently looks like another language
+// than declared, and which. Languages the detector doesn't know are only
+// checked for script, so a declared "swahili" isn't flagged for Latin text.
+func Mismatch(declared, text string) (Result, bool) {
+	g := Guess(text)
+	declared = strings.ToLower(strings.TrimSpace(declared))
+	if declared == "" || g.Language == declared {
+		return g, false
+	}
+	if g.Script != "latin" {
+		return g, g.Confidence >= scriptMismatchConfidence
+	}
+	if Code(declared) == "" {
+		return g, false
+	}
+	if isScriptLanguage(declared) {
+		// Indic lyrics written entirely in Latin letters
+		return g, g.Confidence > 0 || g.Language == English
+	}
+	return g, g.Confidence >= trigramMismatchConfidence
+}
+
+func isScriptLanguage(language string) bool {
+	for _, s := range scripts {
+		if s.language == language {
+			return true
+		}
+	}
+	return false
+}
+
+// Code returns the ISO 639-1 code for a language, or "" if it is unknown
+func Code(language string) string {
+	return codes[language]
+}
@@ -0,0 +1,199 @@
+package language
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Text pasted from Word documents, websites and older Indic input methods
+// arrives in many equivalent encodings. Normalizing before storage makes
+// duplicate detection, search and language detection see one form.
+//
+// The composition step is canonical composition (NFC) for Latin-1, Latin
+// Extended-A and the Indic scripts, which covers the library's languages
+// without pulling in a full Unicode normalization package.
+
+// Malayalam chillu letters written the pre-Unicode 5.1 way, as consonant +
+// virama + zero width joiner, map onto the atomic chillu characters
+var chillus = map[rune]rune{
+	0x0D23: 0x0D7A, // ണ് -> ൺ
+	0x0D28: 0x0D7B, // ന് -> ൻ
+	0x0D30: 0x0D7C, // ര് -> ർ
+	0x0D32: 0x0D7D, // ല് -> ൽ
+	0x0D33: 0x0D7E, // ള് -> ൾ
+	0x0D15: 0x0D7F, // ക് -> ൿ
+}
+
+const (
+	malayalamVirama = 0x0D4D
+	zwnj            = 0x200C
+	zwj             = 0x200D
+)
+
+var indicScripts = []*unicode.RangeTable{unicode.Devanagari, unicode.Malayalam, unicode.Tamil, unicode.Telugu, unicode.Kannada}
+
+// Normalize prepares multi-line text (lyrics) for storage: every line goes
+// through NormalizeLine, line endings become "\n", runs of blank lines
+// collapse to one (a single blank line separates sections) and leading and
+// trailing blank lines are removed
+func Normalize(text string) string {
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\u2028", "\n", "\u2029", "\n\n", "\u0085", "\n", "\v", "\n", "\f", "\n").Replace(text)
+
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = NormalizeLine(line)
+		if line == "" && (len(out) == 0 || out[len(out)-1] == "") {
+			continue
+		}
+		out = append(out, line)
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// NormalizeLine normalizes a single line of text such as a title: canonical
+// composition, atomic Malayalam chillus, no stray zero-width or direction
+// characters, and single ASCII spaces with no leading or trailing space
+func NormalizeLine(line string) string {
+	runes := []rune(line)
+	runes = decomposeExclusions(runes)
+	runes = unifyChillus(runes)
+	runes = dropInvisible(runes)
+	reorderMarks(runes)
+	runes = compose(runes)
+	return collapseSpaces(runes)
+}
+
+// decomposeExclusions splits composites that NFC keeps decomposed (Devanagari
+// nukta letters such as क़)
+func decomposeExclusions(runes []rune) []rune {
+	out := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		if parts, ok := compositionExclusions[r]; ok {
+			out = append(out, parts[0], parts[1])
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func unifyChillus(runes []rune) []rune {
+	out := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		if chillu, ok := chillus[runes[i]]; ok && i+2 < len(runes) && runes[i+1] == malayalamVirama && runes[i+2] == zwj {
+			out = append(out, chillu)
+			i += 2
+			continue
+		}
+		out = append(out, runes[i])
+	}
+	return out
+}
+
+// dropInvisible removes zero-width spaces, byte order marks, soft hyphens and
+// direction marks. Zero width (non-)joiners are kept only inside Indic words,
+// where they select conjunct or half forms.
+func dropInvisible(runes []rune) []rune {
+	out := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		switch r {
+		case 0x200B, 0x2060, 0xFEFF, 0x00AD, 0x180E, 0x200E, 0x200F:
+			continue
+		case zwj, zwnj:
+			if len(out) == 0 || i+1 == len(runes) || !isIndic(out[len(out)-1]) || !isIndic(runes[i+1]) {
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func isIndic(r rune) bool {
+	return unicode.IsOneOf(indicScripts, r)
+}
+
+// combiningClass returns a rune's canonical combining class (0 for starters)
+func combiningClass(r rune) uint8 {
+	if r < 0x0300 || r > 0x0DFF {
+		return 0
+	}
+	for _, c := range combiningClasses {
+		if r < c.lo {
+			return 0
+		}
+		if r <= c.hi {
+			return c.class
+		}
+	}
+	return 0
+}
+
+// reorderMarks puts each run of combining marks into canonical order
+func reorderMarks(runes []rune) {
+	for start := 0; start < len(runes); start++ {
+		if combiningClass(runes[start]) == 0 {
+			continue
+		}
+		end := start
+		for end < len(runes) && combiningClass(runes[end]) != 0 {
+			end++
+		}
+		run := runes[start:end]
+		sort.SliceStable(run, func(i, j int) bool { return combiningClass(run[i]) < combiningClass(run[j]) })
+		start = end
+	}
+}
+
+// compose applies canonical composition: each mark joins the last starter
+// unless a mark of the same or higher class sits between them
+func compose(runes []rune) []rune {
+	out := make([]rune, 0, len(runes))
+	starter := -1
+	var lastClass uint8
+
+	for _, r := range runes {
+		class := combiningClass(r)
+		if starter >= 0 {
+			adjacent := starter == len(out)-1
+			if adjacent || (lastClass != 0 && lastClass < class) {
+				if composite, ok := compositions[[2]rune{out[starter], r}]; ok {
+					out[starter] = composite
+					continue
+				}
+			}
+		}
+
+		if class == 0 {
+			starter = len(out)
+		}
+		lastClass = class
+		out = append(out, r)
+	}
+	return out
+}
+
+// collapseSpaces turns tabs and Unicode spaces into single ASCII spaces and
+// trims the ends
+func collapseSpaces(runes []rune) string {
+	var b strings.Builder
+	pending := false
+	for _, r := range runes {
+		if unicode.IsSpace(r) || unicode.Is(unicode.Zs, r) {
+			pending = b.Len() > 0
+			continue
+		}
+		if pending {
+			b.WriteByte(' ')
+			pending = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+// Normalization tables, derived from the Unicode 14.0 character database.
+
+package language
+
+// compositions maps a starter and combining mark to their canonical composite,
+// for Latin-1, Latin Extended-A and the Indic blocks (U+0900-U+0DFF)
+var compositions = map[[2]rune]rune{
+	{0x0041, 0x0300}: 0x00C0, {0x0041, 0x0301}: 0x00C1, {0x0041, 0x0302}: 0x00C2,
+	{0x0041, 0x0303}: 0x00C3, {0x0041, 0x0308}: 0x00C4, {0x0041, 0x030A}: 0x00C5,
+	{0x0043, 0x0327}: 0x00C7, {0x0045, 0x0300}: 0x00C8, {0x0045, 0x0301}: 0x00C9,
+	{0x0045, 0x0302}: 0x00CA, {0x0045, 0x0308}: 0x00CB, {0x0049, 0x0300}: 0x00CC,
+	{0x0049, 0x0301}: 0x00CD, {0x0049, 0x0302}: 0x00CE, {0x0049, 0x0308}: 0x00CF,
+	{0x004E, 0x0303}: 0x00D1, {0x004F, 0x0300}: 0x00D2, {0x004F, 0x0301}: 0x00D3,
+	{0x004F, 0x0302}: 0x00D4, {0x004F, 0x0303}: 0x00D5, {0x004F, 0x0308}: 0x00D6,
+	{0x0055, 0x0300}: 0x00D9, {0x0055, 0x0301}: 0x00DA, {0x0055, 0x0302}: 0x00DB,
+	{0x0055, 0x0308}: 0x00DC, {0x0059, 0x0301}: 0x00DD, {0x0061, 0x0300}: 0x00E0,
+	{0x0061, 0x0301}: 0x00E1, {0x0061, 0x0302}: 0x00E2, {0x0061, 0x0303}: 0x00E3,
+	{0x0061, 0x0308}: 0x00E4, {0x0061, 0x030A}: 0x00E5, {0x0063, 0x0327}: 0x00E7,
+	{0x0065, 0x0300}: 0x00E8, {0x0065, 0x0301}: 0x00E9, {0x0065, 0x0302}: 0x00EA,
+	{0x0065, 0x0308}: 0x00EB, {0x0069, 0x0300}: 0x00EC, {0x0069, 0x0301}: 0x00ED,
+	{0x0069, 0x0302}: 0x00EE, {0x0069, 0x0308}: 0x00EF, {0x006E, 0x0303}: 0x00F1,
+	{0x006F, 0x0300}: 0x00F2, {0x006F, 0x0301}: 0x00F3, {0x006F, 0x0302}: 0x00F4,
+	{0x006F, 0x0303}: 0x00F5, {0x006F, 0x0308}: 0x00F6, {0x0075, 0x0300}: 0x00F9,
+	{0x0075, 0x0301}: 0x00FA, {0x0075, 0x0302}: 0x00FB, {0x0075, 0x0308}: 0x00FC,
+	{0x0079, 0x0301}: 0x00FD, {0x0079, 0x0308}: 0x00FF, {0x0041, 0x0304}: 0x0100,
+	{0x0061, 0x0304}: 0x0101, {0x0041, 0x0306}: 0x0102, {0x0061, 0x0306}: 0x0103,
+	{0x0041, 0x0328}: 0x0104, {0x0061, 0x0328}: 0x0105, {0x0043, 0x0301}: 0x0106,
+	{0x0063, 0x0301}: 0x0107, {0x0043, 0x0302}: 0x0108, {0x0063, 0x0302}: 0x0109,
+	{0x0043, 0x0307}: 0x010A, {0x0063, 0x0307}: 0x010B, {0x0043, 0x030C}: 0x010C,
+	{0x0063, 0x030C}: 0x010D, {0x0044, 0x030C}: 0x010E, {0x0064, 0x030C}: 0x010F,
+	{0x0045, 0x0304}: 0x0112, {0x0065, 0x0304}: 0x0113, {0x0045, 0x0306}: 0x0114,
+	{0x0065, 0x0306}: 0x0115, {0x0045, 0x0307}: 0x0116, {0x0065, 0x0307}: 0x0117,
+	{0x0045, 0x0328}: 0x0118, {0x0065, 0x0328}: 0x0119, {0x0045, 0x030C}: 0x011A,
+	{0x0065, 0x030C}: 0x011B, {0x0047, 0x0302}: 0x011C, {0x0067, 0x0302}: 0x011D,
+	{0x0047, 0x0306}: 0x011E, {0x0067, 0x0306}: 0x011F, {0x0047, 0x0307}: 0x0120,
+	{0x0067, 0x0307}: 0x0121, {0x0047, 0x0327}: 0x0122, {0x0067, 0x0327}: 0x0123,
+	{0x0048, 0x0302}: 0x0124, {0x0068, 0x0302}: 0x0125, {0x0049, 0x0303}: 0x0128,
+	{0x0069, 0x0303}: 0x0129, {0x0049, 0x0304}: 0x012A, {0x0069, 0x0304}: 0x012B,
+	{0x0049, 0x0306}: 0x012C, {0x0069, 0x0306}: 0x012D, {0x0049, 0x0328}: 0x012E,
+	{0x0069, 0x0328}: 0x012F, {0x0049, 0x0307}: 0x0130, {0x004A, 0x0302}: 0x0134,
+	{0x006A, 0x0302}: 0x0135, {0x004B, 0x0327}: 0x0136, {0x006B, 0x0327}: 0x0137,
+	{0x004C, 0x0301}: 0x0139, {0x006C, 0x0301}: 0x013A, {0x004C, 0x0327}: 0x013B,
+	{0x006C, 0x0327}: 0x013C, {0x004C, 0x030C}: 0x013D, {0x006C, 0x030C}: 0x013E,
+	{0x004E, 0x0301}: 0x0143, {0x006E, 0x0301}: 0x0144, {0x004E, 0x0327}: 0x0145,
+	{0x006E, 0x0327}: 0x0146, {0x004E, 0x030C}: 0x0147, {0x006E, 0x030C}: 0x0148,
+	{0x004F, 0x0304}: 0x014C, {0x006F, 0x0304}: 0x014D, {0x004F, 0x0306}: 0x014E,
+	{0x006F, 0x0306}: 0x014F, {0x004F, 0x030B}: 0x0150, {0x006F, 0x030B}: 0x0151,
+	{0x0052, 0x0301}: 0x0154, {0x0072, 0x0301}: 0x0155, {0x0052, 0x0327}: 0x0156,
+	{0x0072, 0x0327}: 0x0157, {0x0052, 0x030C}: 0x0158, {0x0072, 0x030C}: 0x0159,
+	{0x0053, 0x0301}: 0x015A, {0x0073, 0x0301}: 0x015B, {0x0053, 0x0302}: 0x015C,
+	{0x0073, 0x0302}: 0x015D, {0x0053, 0x0327}: 0x015E, {0x0073, 0x0327}: 0x015F,
+	{0x0053, 0x030C}: 0x0160, {0x0073, 0x030C}: 0x0161, {0x0054, 0x0327}: 0x0162,
+	{0x0074, 0x0327}: 0x0163, {0x0054, 0x030C}: 0x0164, {0x0074, 0x030C}: 0x0165,
+	{0x0055, 0x0303}: 0x0168, {0x0075, 0x0303}: 0x0169, {0x0055, 0x0304}: 0x016A,
+	{0x0075, 0x0304}: 0x016B, {0x0055, 0x0306}: 0x016C, {0x0075, 0x0306}: 0x016D,
+	{0x0055, 0x030A}: 0x016E, {0x0075, 0x030A}: 0x016F, {0x0055, 0x030B}: 0x0170,
+	{0x0075, 0x030B}: 0x0171, {0x0055, 0x0328}: 0x0172, {0x0075, 0x0328}: 0x0173,
+	{0x0057, 0x0302}: 0x0174, {0x0077, 0x0302}: 0x0175, {0x0059, 0x0302}: 0x0176,
+	{0x0079, 0x0302}: 0x0177, {0x0059, 0x0308}: 0x0178, {0x005A, 0x0301}: 0x0179,
+	{0x007A, 0x0301}: 0x017A, {0x005A, 0x0307}: 0x017B, {0x007A, 0x0307}: 0x017C,
+	{0x005A, 0x030C}: 0x017D, {0x007A, 0x030C}: 0x017E, {0x0928, 0x093C}: 0x0929,
+	{0x0930, 0x093C}: 0x0931, {0x0933, 0x093C}: 0x0934, {0x09C7, 0x09BE}: 0x09CB,
+	{0x09C7, 0x09D7}: 0x09CC, {0x0B47, 0x0B56}: 0x0B48, {0x0B47, 0x0B3E}: 0x0B4B,
+	{0x0B47, 0x0B57}: 0x0B4C, {0x0B92, 0x0BD7}: 0x0B94, {0x0BC6, 0x0BBE}: 0x0BCA,
+	{0x0BC7, 0x0BBE}: 0x0BCB, {0x0BC6, 0x0BD7}: 0x0BCC, {0x0C46, 0x0C56}: 0x0C48,
+	{0x0CBF, 0x0CD5}: 0x0CC0, {0x0CC6, 0x0CD5}: 0x0CC7, {0x0CC6, 0x0CD6}: 0x0CC8,
+	{0x0CC6, 0x0CC2}: 0x0CCA, {0x0CCA, 0x0CD5}: 0x0CCB, {0x0D46, 0x0D3E}: 0x0D4A,
+	{0x0D47, 0x0D3E}: 0x0D4B, {0x0D46, 0x0D57}: 0x0D4C, {0x0DD9, 0x0DCA}: 0x0DDA,
+	{0x0DD9, 0x0DCF}: 0x0DDC, {0x0DDC, 0x0DCA}: 0x0DDD, {0x0DD9, 0x0DDF}: 0x0DDE,
+}
+
+// compositionExclusions are composites that NFC always keeps decomposed
+var compositionExclusions = map[rune][2]rune{
+	0x0958: {0x0915, 0x093C}, 0x0959: {0x0916, 0x093C}, 0x095A: {0x0917, 0x093C},
+	0x095B: {0x091C, 0x093C}, 0x095C: {0x0921, 0x093C}, 0x095D: {0x0922, 0x093C},
+	0x095E: {0x092B, 0x093C}, 0x095F: {0x092F, 0x093C}, 0x09DC: {0x09A1, 0x09BC},
+	0x09DD: {0x09A2, 0x09BC}, 0x09DF: {0x09AF, 0x09BC}, 0x0A33: {0x0A32, 0x0A3C},
+	0x0A36: {0x0A38, 0x0A3C}, 0x0A59: {0x0A16, 0x0A3C}, 0x0A5A: {0x0A17, 0x0A3C},
+	0x0A5B: {0x0A1C, 0x0A3C}, 0x0A5E: {0x0A2B, 0x0A3C}, 0x0B5C: {0x0B21, 0x0B3C},
+	0x0B5D: {0x0B22, 0x0B3C},
+}
+
+// combiningClasses lists the non-zero canonical combining classes of the
+// marks in U+0300-U+036F and U+0900-U+0DFF as inclusive ranges
+var combiningClasses = []struct {
+	lo, hi rune
+	class  uint8
+}{
+	{0x0300, 0x0314, 230},
+	{0x0315, 0x0315, 232},
+	{0x0316, 0x0319, 220},
+	{0x031A, 0x031A, 232},
+	{0x031B, 0x031B, 216},
+	{0x031C, 0x0320, 220},
+	{0x0321, 0x0322, 202},
+	{0x0323, 0x0326, 220},
+	{0x0327, 0x0328, 202},
+	{0x0329, 0x0333, 220},
+	{0x0334, 0x0338, 1},
+	{0x0339, 0x033C, 220},
+	{0x033D, 0x0344, 230},
+	{0x0345, 0x0345, 240},
+	{0x0346, 0x0346, 230},
+	{0x0347, 0x0349, 220},
+	{0x034A, 0x034C, 230},
+	{0x034D, 0x034E, 220},
+	{0x0350, 0x0352, 230},
+	{0x0353, 0x0356, 220},
+	{0x0357, 0x0357, 230},
+	{0x0358, 0x0358, 232},
+	{0x0359, 0x035A, 220},
+	{0x035B, 0x035B, 230},
+	{0x035C, 0x035C, 233},
+	{0x035D, 0x035E, 234},
+	{0x035F, 0x035F, 233},
+	{0x0360, 0x0361, 234},
+	{0x0362, 0x0362, 233},
+	{0x0363, 0x036F, 230},
+	{0x093C, 0x093C, 7},
+	{0x094D, 0x094D, 9},
+	{0x0951, 0x0951, 230},
+	{0x0952, 0x0952, 220},
+	{0x0953, 0x0954, 230},
+	{0x09BC, 0x09BC, 7},
+	{0x09CD, 0x09CD, 9},
+	{0x09FE, 0x09FE, 230},
+	{0x0A3C, 0x0A3C, 7},
+	{0x0A4D, 0x0A4D, 9},
+	{0x0ABC, 0x0ABC, 7},
+	{0x0ACD, 0x0ACD, 9},
+	{0x0B3C, 0x0B3C, 7},
+	{0x0B4D, 0x0B4D, 9},
+	{0x0BCD, 0x0BCD, 9},
+	{0x0C3C, 0x0C3C, 7},
+	{0x0C4D, 0x0C4D, 9},
+	{0x0C55, 0x0C55, 84},
+	{0x0C56, 0x0C56, 91},
+	{0x0CBC, 0x0CBC, 7},
+	{0x0CCD, 0x0CCD, 9},
+	{0x0D3B, 0x0D3C, 9},
+	{0x0D4D, 0x0D4D, 9},
+	{0x0DCA, 0x0DCA, 9},
+}
@@ -0,0 +1,250 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The Brahmic script blocks share one layout (inherited from ISCII), so a
+// letter's offset from the start of its block means the same sound in all of
+// them. Romanization follows the informal ASCII spelling used on lyric
+// sheets (aa, ee, oo, th, zh) rather than ISO 15919, since it is meant for
+// congregants singing along, not for linguists.
+
+// scriptBlock is a Brahmic Unicode block and its language
+type scriptBlock struct {
+	base     rune
+	language string
+}
+
+var scriptBlocks = []scriptBlock{
+	{0x0900, Hindi},
+	{0x0B80, Tamil},
+	{0x0C00, Telugu},
+	{0x0C80, Kannada},
+	{0x0D00, Malayalam},
+}
+
+// Kinds of letter at a block offset
+const (
+	letterOther = iota
+	letterVowel
+	letterConsonant
+	letterSign   // dependent vowel sign
+	letterVirama // suppresses the inherent vowel
+	letterNukta
+	letterFinal // a consonant without an inherent vowel (anusvara, chillu)
+)
+
+type letter struct {
+	kind  int
+	latin string
+}
+
+// brahmic maps block offsets to Latin; languages override a few below
+var brahmic = map[rune]letter{
+	0x01: {letterFinal, "n"},
+	0x02: {letterFinal, "m"},
+	0x03: {letterFinal, "h"},
+
+	0x05: {letterVowel, "a"}, 0x06: {letterVowel, "aa"}, 0x07: {letterVowel, "i"}, 0x08: {letterVowel, "ee"},
+	0x09: {letterVowel, "u"}, 0x0A: {letterVowel, "oo"}, 0x0B: {letterVowel, "ru"}, 0x0C: {letterVowel, "lu"},
+	0x0D: {letterVowel, "e"}, 0x0E: {letterVowel, "e"}, 0x0F: {letterVowel, "e"}, 0x10: {letterVowel, "ai"},
+	0x11: {letterVowel, "o"}, 0x12: {letterVowel, "o"}, 0x13: {letterVowel, "o"}, 0x14: {letterVowel, "au"},
+
+	0x15: {letterConsonant, "k"}, 0x16: {letterConsonant, "kh"}, 0x17: {letterConsonant, "g"}, 0x18: {letterConsonant, "gh"}, 0x19: {letterConsonant, "ng"},
+	0x1A: {letterConsonant, "ch"}, 0x1B: {letterConsonant, "chh"}, 0x1C: {letterConsonant, "j"}, 0x1D: {letterConsonant, "jh"}, 0x1E: {letterConsonant, "ny"},
+	0x1F: {letterConsonant, "t"}, 0x20: {letterConsonant, "th"}, 0x21: {letterConsonant, "d"}, 0x22: {letterConsonant, "dh"}, 0x23: {letterConsonant, "n"},
+	0x24: {letterConsonant, "t"}, 0x25: {letterConsonant, "th"}, 0x26: {letterConsonant, "d"}, 0x27: {letterConsonant, "dh"}, 0x28: {letterConsonant, "n"},
+	0x29: {letterConsonant, "n"},
+	0x2A: {letterConsonant, "p"}, 0x2B: {letterConsonant, "ph"}, 0x2C: {letterConsonant, "b"}, 0x2D: {letterConsonant, "bh"}, 0x2E: {letterConsonant, "m"},
+	0x2F: {letterConsonant, "y"}, 0x30: {letterConsonant, "r"}, 0x31: {letterConsonant, "r"}, 0x32: {letterConsonant, "l"},
+	0x33: {letterConsonant, "l"}, 0x34: {letterConsonant, "zh"}, 0x35: {letterConsonant, "v"},
+	0x36: {letterConsonant, "sh"}, 0x37: {letterConsonant, "sh"}, 0x38: {letterConsonant, "s"}, 0x39: {letterConsonant, "h"},
+
+	0x3C: {letterNukta, ""},
+
+	0x3E: {letterSign, "aa"}, 0x3F: {letterSign, "i"}, 0x40: {letterSign, "ee"}, 0x41: {letterSign, "u"},
+	0x42: {letterSign, "oo"}, 0x43: {letterSign, "ru"}, 0x44: {letterSign, "ru"}, 0x45: {letterSign, "e"},
+	0x46: {letterSign, "e"}, 0x47: {letterSign, "e"}, 0x48: {letterSign, "ai"}, 0x49: {letterSign, "o"},
+	0x4A: {letterSign, "o"}, 0x4B: {letterSign, "o"}, 0x4C: {letterSign, "au"},
+	0x4D: {letterVirama, ""},
+
+	0x60: {letterVowel, "ru"}, 0x61: {letterVowel, "lu"}, 0x62: {letterSign, "lu"}, 0x63: {letterSign, "lu"},
+	0x64: {letterOther, "."}, 0x65: {letterOther, "."},
+}
+
+// Language-specific readings of the shared offsets
+var brahmicOverrides = map[string]map[rune]letter{
+	Hindi: {
+		0x50: {letterOther, "om"},
+		// Nukta consonants, precomposed
+		0x58: {letterConsonant, "q"}, 0x59: {letterConsonant, "kh"}, 0x5A: {letterConsonant, "gh"}, 0x5B: {letterConsonant, "z"},
+		0x5C: {letterConsonant, "r"}, 0x5D: {letterConsonant, "rh"}, 0x5E: {letterConsonant, "f"}, 0x5F: {letterConsonant, "y"},
+	},
+	Tamil: {
+		0x1A: {letterConsonant, "s"}, // ச is mostly heard as s (இயேசு is "Yesu")
+		0x24: {letterConsonant, "th"},
+	},
+	Telugu: {
+		0x24: {letterConsonant, "th"},
+	},
+	Kannada: {
+		0x24: {letterConsonant, "th"},
+	},
+	Malayalam: {
+		0x1E: {letterConsonant, "nj"},
+		0x24: {letterConsonant, "th"},
+		0x4E: {letterFinal, "r"}, // dot reph
+		0x57: {letterSign, "u"},  // au length mark
+		// Chillu letters
+		0x7A: {letterFinal, "n"}, 0x7B: {letterFinal, "n"}, 0x7C: {letterFinal, "r"},
+		0x7D: {letterFinal, "l"}, 0x7E: {letterFinal, "l"}, 0x7F: {letterFinal, "k"},
+	},
+}
+
+// lookupBrahmic returns the language and letter for r, or false if r is not
+// a known letter of a supported script
+func lookupBrahmic(r rune) (string, letter, bool) {
+	for _, block := range scriptBlocks {
+		if r < block.base || r >= block.base+0x80 {
+			continue
+		}
+		offset := r - block.base
+		if l, ok := brahmicOverrides[block.language][offset]; ok {
+			return block.language, l, true
+		}
+		if offset >= 0x66 && offset <= 0x6F {
+			return block.language, letter{letterOther, string('0' + offset - 0x66)}, true
+		}
+		l, ok := brahmic[offset]
+		return block.language, l, ok
+	}
+	return "", letter{}, false
+}
+
+// Romanize spells Malayalam, Hindi, Tamil, Telugu and Kannada text in Latin
+// letters, line by line. Anything else, including Latin text and section
+// labels like [Chorus], is left as it is.
+func Romanize(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for i := 0; i < len(runes); i++ {
+		lang, l, ok := lookupBrahmic(runes[i])
+		if !ok {
+			b.WriteRune(runes[i])
+			continue
+		}
+
+		switch l.kind {
+		case letterConsonant:
+			latin := l.latin
+			if lang == Malayalam && isMalayalamNTA(runes, i) {
+				// ന്റ and ററ are read "nt" and "tt"
+				latin = "t"
+			}
+			b.WriteString(latin)
+			next := i + 1
+			if next < len(runes) && runes[next] == blockBase(lang)+0x3C {
+				next++ // nukta only shifts the consonant's sound
+			}
+			if !followedByVowelSign(runes, next, lang) && !dropInherentA(runes, next, lang) {
+				b.WriteString("a")
+			}
+			i = next - 1
+		case letterVirama:
+			// A Malayalam word ending in virama ends in a short u sound
+			if lang == Malayalam && atWordEnd(runes, i+1) {
+				b.WriteString("u")
+			}
+		case letterFinal:
+			latin := l.latin
+			if lang == Hindi && l.latin == "m" && !beforeLabial(runes, i+1) {
+				latin = "n"
+			}
+			b.WriteString(latin)
+		default:
+			b.WriteString(l.latin)
+		}
+	}
+	return b.String()
+}
+
+func blockBase(lang string) rune {
+	for _, block := range scriptBlocks {
+		if block.language == lang {
+			return block.base
+		}
+	}
+	return 0
+}
+
+// followedByVowelSign reports whether runes[i] replaces the inherent vowel
+func followedByVowelSign(runes []rune, i int, lang string) bool {
+	if i >= len(runes) {
+		return false
+	}
+	l2, l, ok := lookupBrahmic(runes[i])
+	return ok && l2 == lang && (l.kind == letterSign || l.kind == letterVirama)
+}
+
+// dropInherentA applies Hindi schwa deletion at the end of a word: राम is
+// "ram", not "rama". Single-letter words keep it.
+func dropInherentA(runes []rune, i int, lang string) bool {
+	if lang != Hindi || !atWordEnd(runes, i) {
+		return false
+	}
+	start := i - 1
+	for start > 0 {
+		if _, _, ok := lookupBrahmic(runes[start-1]); !ok {
+			break
+		}
+		start--
+	}
+	return i-start > 1
+}
+
+func atWordEnd(runes []rune, i int) bool {
+	if i >= len(runes) {
+		return true
+	}
+	if _, _, ok := lookupBrahmic(runes[i]); ok {
+		return false
+	}
+	return !unicode.IsLetter(runes[i]) && !unicode.IsMark(runes[i])
+}
+
+// beforeLabial reports whether the letter at i is p, ph, b, bh or m, before
+// which an anusvara is heard as m
+func beforeLabial(runes []rune, i int) bool {
+	if i >= len(runes) {
+		return false
+	}
+	_, l, ok := lookupBrahmic(runes[i])
+	return ok && l.kind == letterConsonant && strings.IndexByte("pbm", l.latin[0]) >= 0
+}
+
+// isMalayalamNTA reports whether the ṟa at i is part of ന്റ (nt) or റ്റ (tt),
+// where it is pronounced as a t
+func isMalayalamNTA(runes []rune, i int) bool {
+	const base = 0x0D00
+	if runes[i] != base+0x31 {
+		return false
+	}
+	if i+2 < len(runes) && runes[i+1] == base+0x4D && runes[i+2] == base+0x31 {
+		return true
+	}
+	if i == 0 {
+		return false
+	}
+	if runes[i-1] == base+0x7B {
+		return true
+	}
+	if i < 2 || runes[i-1] != base+0x4D {
+		return false
+	}
+	prev := runes[i-2]
+	return prev == base+0x28 || prev == base+0x31 || prev == base+0x7B
+}
@@ -0,0 +1,155 @@
+package language
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Latin-script languages told apart by trigram profiles
+const (
+	Spanish    = "spanish"
+	French     = "french"
+	German     = "german"
+	Portuguese = "portuguese"
+)
+
+// profileSize is how many of the most frequent trigrams make up a profile
+const profileSize = 300
+
+// minTrigramLetters is the least Latin text the trigram model is trusted
+// with; shorter text is assumed to be English
+const minTrigramLetters = 40
+
+// trigramSamples are short passages in each language, in the register of
+// worship lyrics, that the profiles are built from at startup
+var trigramSamples = map[string]string{
+	English: `Amazing grace how sweet the sound that saved a wretch like me. I once was lost but now am found,
+was blind but now I see. How great is our God, sing with me, how great is our God, and all will see
+how great, how great is our God. Holy, holy, holy, Lord God almighty, early in the morning our song
+shall rise to thee. Blessed assurance, Jesus is mine, oh what a foretaste of glory divine. This is my
+story, this is my song, praising my Saviour all the day long. When peace like a river attendeth my way,
+when sorrows like sea billows roll, whatever my lot, thou hast taught me to say, it is well with my soul.
+Great is thy faithfulness, morning by morning new mercies I see, all I have needed thy hand hath provided.
+The heart of worship is not the music but the people who gather to praise the name of the Lord together,
+thankful for everything that he has given and for the love that will never let us go.`,
+
+	Spanish: `Sublime gracia del Señor que a un pecador salvó, fui ciego mas hoy veo yo, perdido y él me halló.
+Cuán grande es él, mi corazón entona la canción, cuán grande es él. Santo, santo, santo, Señor
+omnipotente, siempre el labio mío loores te dará. Eres todo poderoso, eres grande y majestuoso,
+eres fuerte, invencible y no hay nadie como tú. Te alabaré, mi buen Jesús, por todo lo que has hecho
+en la cruz. Cristo vive, ya no hay muerte, la tumba está vacía y el que cree en él tendrá la vida eterna.
+Los que esperan en el Señor tendrán nuevas fuerzas, levantarán alas como las águilas, correrán y no se
+cansarán. Queremos que tu presencia llene este lugar y que todos los pueblos conozcan tu amor.`,
+
+	French: `Grâce infinie, ô quel beau son, un pécheur comme moi fut sauvé, j'étais perdu mais je suis retrouvé,
+j'étais aveugle et maintenant je vois. À toi la gloire, ô ressuscité, à toi la victoire pour l'éternité.
+Saint, saint, saint est le Seigneur, le Dieu tout puissant, toute la terre est remplie de sa gloire.
+Je veux chanter ton amour, Seigneur, chaque jour de ma vie, tu es mon rocher et mon libérateur.
+Que ton règne vienne, que ta volonté soit faite sur la terre comme au ciel. Nous élevons nos voix
+pour te louer, car tu es fidèle et ta bonté dure à toujours. Celui qui demeure sous l'abri du Très-Haut
+repose à l'ombre du Tout-Puissant, il est mon refuge et ma forteresse, mon Dieu en qui je me confie.`,
+
+	German: `Großer Gott, wir loben dich, Herr, wir preisen deine Stärke, vor dir neigt die Erde sich und bewundert
+deine Werke. Wie du warst vor aller Zeit, so bleibst du in Ewigkeit. Ein feste Burg ist unser Gott, ein gute
+Wehr und Waffen, er hilft uns frei aus aller Not, die uns jetzt hat betroffen. Lobe den Herren, den mächtigen
+König der Ehren, meine geliebete Seele, das ist mein Begehren. Herr, deine Liebe ist wie Gras und Ufer,
+wie Wind und Weite und wie ein Zuhause. Wir wollen dich anbeten und deinen Namen ehren, denn du bist
+heilig und gerecht und deine Gnade ist jeden Morgen neu. Der Herr ist mein Hirte, mir wird nichts mangeln,
+er weidet mich auf einer grünen Aue und führet mich zum frischen Wasser.`,
+
+	Portuguese: `Maravilhosa graça, que doce o som que salvou um pecador como eu, estava perdido mas fui encontrado,
+era cego mas agora vejo. Quão grande és tu, então minha alma canta a ti, Senhor, quão grande és tu.
+Santo, santo, santo, Deus onipotente, cantam de manhã nossas vozes com ardor. Tu és fiel, Senhor,
+as tuas misericórdias não têm fim, a cada manhã se renovam sobre mim. Eu te louvarei de todo o meu
+coração e contarei todas as tuas maravilhas. Não há ninguém como o nosso Deus, que fez os céus e a terra,
+e o seu amor permanece para sempre. Aqueles que esperam no Senhor renovam as suas forças, sobem com
+asas como águias, correm e não se cansam, caminham e não se fatigam.`,
+}
+
+type trigramProfile struct {
+	language string
+	ranks    map[string]int
+}
+
+var trigramProfiles = buildProfiles()
+
+func buildProfiles() []trigramProfile {
+	profiles := make([]trigramProfile, 0, len(trigramSamples))
+	for lang, sample := range trigramSamples {
+		profiles = append(profiles, trigramProfile{language: lang, ranks: rankTrigrams(sample)})
+	}
+	// Fixed order so ties resolve the same way every run
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].language < profiles[j].language })
+	return profiles
+}
+
+// rankTrigrams returns the profileSize most frequent letter trigrams of text
+// with their rank (0 is the most frequent). Words are padded with spaces so
+// beginnings and endings count.
+func rankTrigrams(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			counts[string(runes[i:i+3])]++
+		}
+	}
+
+	grams := make([]string, 0, len(counts))
+	for g := range counts {
+		grams = append(grams, g)
+	}
+	sort.Slice(grams, func(i, j int) bool {
+		if counts[grams[i]] != counts[grams[j]] {
+			return counts[grams[i]] > counts[grams[j]]
+		}
+		return grams[i] < grams[j]
+	})
+	if len(grams) > profileSize {
+		grams = grams[:profileSize]
+	}
+
+	ranks := make(map[string]int, len(grams))
+	for i, g := range grams {
+		ranks[g] = i
+	}
+	return ranks
+}
+
+// guessLatin ranks text against each profile using the out-of-place distance
+// and returns the closest language with a confidence between 0 and 1, based on
+// how clearly it beats the runner-up
+func guessLatin(text string) (string, float64) {
+	doc := rankTrigrams(text)
+	if len(doc) == 0 {
+		return English, 0
+	}
+
+	best, second := -1, -1
+	var bestLang string
+	for _, p := range trigramProfiles {
+		distance := 0
+		for g, rank := range doc {
+			if r, ok := p.ranks[g]; ok {
+				if r > rank {
+					distance += r - rank
+				} else {
+					distance += rank - r
+				}
+			} else {
+				distance += profileSize
+			}
+		}
+		switch {
+		case best < 0 || distance < best:
+			second, best, bestLang = best, distance, p.language
+		case second < 0 || distance < second:
+			second = distance
+		}
+	}
+	if second <= 0 {
+		return bestLang, 0
+	}
+	return bestLang, float64(second-best) / float64(second)
+}
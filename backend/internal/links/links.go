@@ -0,0 +1,155 @@
+// Package links validates songs' external reference links (YouTube, Spotify,
+// SongSelect, MultiTracks) and fetches oEmbed metadata for the ones that
+// support it.
+package links
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Link kinds
+const (
+	KindYouTube     = "youtube"
+	KindSpotify     = "spotify"
+	KindSongSelect  = "songselect"
+	KindMultiTracks = "multitracks"
+)
+
+// Kinds lists the supported link kinds
+var Kinds = []string{KindYouTube, KindSpotify, KindSongSelect, KindMultiTracks}
+
+// hosts lists the hosts accepted for each kind
+var hosts = map[string][]string{
+	KindYouTube:     {"youtube.com", "www.youtube.com", "m.youtube.com", "music.youtube.com", "youtu.be"},
+	KindSpotify:     {"open.spotify.com"},
+	KindSongSelect:  {"songselect.ccli.com"},
+	KindMultiTracks: {"multitracks.com", "www.multitracks.com"},
+}
+
+// oembedEndpoints are the providers' oEmbed APIs; SongSelect and MultiTracks
+// don't offer one
+var oembedEndpoints = map[string]string{
+	KindYouTube: "https://www.youtube.com/oembed",
+	KindSpotify: "https://open.spotify.com/oembed",
+}
+
+// ErrNoOEmbed is returned for kinds without an oEmbed provider
+var ErrNoOEmbed = errors.New("no oEmbed provider for this link")
+
+// Normalize checks a link and returns its kind and cleaned-up URL. An empty
+// kind is worked out from the host.
+func Normalize(kind, rawURL string) (string, string, error) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	rawURL = strings.TrimSpace(rawURL)
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", "", fmt.Errorf("%q is not an http or https URL", rawURL)
+	}
+	u.Scheme = "https"
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+
+	detected := KindOf(u.Host)
+	if kind == "" {
+		if detected == "" {
+			return "", "", fmt.Errorf("%q is not a %s link", rawURL, strings.Join(Kinds, ", "))
+		}
+		kind = detected
+	}
+	if _, ok := hosts[kind]; !ok {
+		return "", "", fmt.Errorf("kind must be one of %s", strings.Join(Kinds, ", "))
+	}
+	if detected != kind {
+		return "", "", fmt.Errorf("%q is not a %s link", rawURL, kind)
+	}
+	return kind, u.String(), nil
+}
+
+// KindOf returns the link kind served from host, or "" if none is
+func KindOf(host string) string {
+	host = strings.ToLower(host)
+	for kind, names := range hosts {
+		for _, name := range names {
+			if host == name {
+				return kind
+			}
+		}
+	}
+	return ""
+}
+
+// Metadata is what an oEmbed provider says about a link
+type Metadata struct {
+	Title        string `json:"title,omitempty"`
+	AuthorName   string `json:"author_name,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	HTML         string `json:"html,omitempty"` // embeddable preview player
+}
+
+// maxCached bounds the metadata cache; it is cleared when full
+const maxCached = 500
+
+// Fetcher looks up oEmbed metadata, caching results for the life of the process
+type Fetcher struct {
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*Metadata
+}
+
+// NewFetcher creates an oEmbed fetcher
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		cache:      make(map[string]*Metadata),
+	}
+}
+
+// Fetch returns the metadata for a normalized link
+func (f *Fetcher) Fetch(kind, link string) (*Metadata, error) {
+	endpoint, ok := oembedEndpoints[kind]
+	if !ok {
+		return nil, ErrNoOEmbed
+	}
+
+	f.mu.Lock()
+	cached := f.cache[link]
+	f.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	resp, err := f.httpClient.Get(endpoint + "?format=json&url=" + url.QueryEscape(link))
+	if err != nil {
+		return nil, fmt.Errorf("oEmbed request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("oEmbed provider returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var meta Metadata
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("invalid oEmbed response: %w", err)
+	}
+
+	f.mu.Lock()
+	if len(f.cache) >= maxCached {
+		f.cache = make(map[string]*Metadata)
+	}
+	f.cache[link] = &meta
+	f.mu.Unlock()
+
+	return &meta, nil
+}
@@ -0,0 +1,475 @@
+package live
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// Event types published on the live channel
+const (
+	EventState          = "state"
+	EventAlert          = "alert"
+	EventAlertDismissed = "alert_dismissed"
+	EventSlide          = "slide"
+	EventBlank          = "blank"
+	EventUnblank        = "unblank"
+	EventNotes          = "notes"
+	EventMode           = "mode"
+	EventPanic          = "panic"
+	EventTempo          = "tempo"
+	EventCues           = "cues"
+	EventAutoAdvance    = "auto_advance"
+	EventCommand        = "command"
+)
+
+// Display roles. Audience displays never receive presenter notes.
+const (
+	RoleAudience = "audience"
+	RoleStage    = "stage"
+)
+
+// NormalizeRole maps a requested display role onto a known role,
+// treating confidence monitors as stage displays and anything else as audience
+func NormalizeRole(role string) string {
+	switch strings.ToLower(strings.TrimSpace(role)) {
+	case "stage", "confidence":
+		return RoleStage
+	default:
+		return RoleAudience
+	}
+}
+
+// Event is a single message delivered to connected displays
+type Event struct {
+	Type      string      `json:"type"`
+	Displays  []string    `json:"displays,omitempty"` // empty means every display
+	Roles     []string    `json:"-"`                  // empty means every role
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Alert is a short announcement shown on top of the lyrics
+type Alert struct {
+	ID        string    `json:"id"`
+	Message   string    `json:"message"`
+	Priority  string    `json:"priority"`
+	Displays  []string  `json:"displays,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Remote control commands for a single display
+const (
+	CommandFontSize = "font_size" // size in px, or delta to step from the current size
+	CommandJumpTo   = "jump_to"   // section label or index in the current song
+	CommandReload   = "reload"
+	CommandTheme    = "theme"
+)
+
+// Command is a remote control instruction for one display
+type Command struct {
+	ID      string `json:"id"`
+	Command string `json:"command"`
+	Size    *int   `json:"size,omitempty"`
+	Delta   *int   `json:"delta,omitempty"`
+	Section string `json:"section,omitempty"`
+	Index   *int   `json:"index,omitempty"`
+	Theme   string `json:"theme,omitempty"`
+}
+
+// NowShowing describes what is currently on screen
+type NowShowing struct {
+	SongID           string    `json:"song_id,omitempty"`
+	Title            string    `json:"title,omitempty"`
+	PresentationUUID string    `json:"presentation_uuid,omitempty"`
+	Slides           []string  `json:"slides,omitempty"` // text of content outside the song library, e.g. scripture
+	SlideIndex       int       `json:"slide_index"`
+	Rehearsal        bool      `json:"rehearsal"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// State is the snapshot sent to a display when it connects
+type State struct {
+	Current   *NowShowing       `json:"current,omitempty"`
+	Blanked   bool              `json:"blanked"`
+	Rehearsal bool              `json:"rehearsal"` // non-production state; never counted in usage stats
+	Alerts    []Alert           `json:"alerts"`
+	Notes     []models.SongNote `json:"notes,omitempty"` // stage displays only
+	Cues      *models.SongCues  `json:"cues,omitempty"`  // stage displays only
+}
+
+// Subscriber is a connected display client
+type Subscriber struct {
+	Display string
+	Role    string
+	Events  chan Event
+}
+
+// Hub fans live events out to every connected display
+type Hub struct {
+	subscribers   map[*Subscriber]struct{}
+	alerts        map[string]Alert
+	nextAlertID   int
+	nextCommandID int
+	current       *NowShowing
+	notes         []models.SongNote
+	cues          *models.SongCues
+	blanked       bool
+	rehearsal     bool
+	mu            sync.RWMutex
+}
+
+// NewHub creates an empty live hub
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[*Subscriber]struct{}),
+		alerts:      make(map[string]Alert),
+	}
+}
+
+// Subscribe registers a display; display may be empty for anonymous clients
+func (h *Hub) Subscribe(display, role string) *Subscriber {
+	sub := &Subscriber{
+		Display: display,
+		Role:    NormalizeRole(role),
+		Events:  make(chan Event, 32),
+	}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe removes a display and closes its event channel
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+// SubscriberCount returns the number of connected displays
+func (h *Hub) SubscriberCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
+
+// ConnectedDisplays returns the names of displays with the live channel open
+func (h *Hub) ConnectedDisplays() map[string]bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	names := make(map[string]bool)
+	for sub := range h.subscribers {
+		if sub.Display != "" {
+			names[sub.Display] = true
+		}
+	}
+	return names
+}
+
+// SendCommand delivers a remote control command to one display. It returns
+// the command with its ID and whether the display had the live channel open
+// to receive it.
+func (h *Hub) SendCommand(display string, cmd Command) (Command, bool) {
+	h.mu.Lock()
+	h.nextCommandID++
+	cmd.ID = fmt.Sprintf("cmd-%d", h.nextCommandID)
+	h.mu.Unlock()
+
+	connected := h.ConnectedDisplays()[display]
+	h.Publish(Event{Type: EventCommand, Displays: []string{display}, Data: cmd})
+	return cmd, connected
+}
+
+// Publish delivers an event to every matching display without blocking.
+// Slow displays drop events rather than stalling the publisher.
+func (h *Hub) Publish(evt Event) {
+	if evt.Timestamp.IsZero() {
+		evt.Timestamp = time.Now()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers {
+		if !sub.wants(evt) {
+			continue
+		}
+		select {
+		case sub.Events <- evt:
+		default:
+			log.Printf("Live channel: dropping %s event for slow display %q", evt.Type, sub.Display)
+		}
+	}
+}
+
+// wants reports whether the event is addressed to this subscriber
+func (s *Subscriber) wants(evt Event) bool {
+	if len(evt.Roles) > 0 && !contains(evt.Roles, s.Role) {
+		return false
+	}
+	if len(evt.Displays) == 0 {
+		return true
+	}
+	return contains(evt.Displays, s.Display)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot returns the current live state as seen by the given display
+func (h *Hub) Snapshot(display, role string) State {
+	sub := &Subscriber{Display: display, Role: NormalizeRole(role)}
+	state := State{Alerts: make([]Alert, 0)}
+
+	h.mu.RLock()
+	if h.current != nil {
+		current := *h.current
+		state.Current = &current
+	}
+	if sub.Role == RoleStage {
+		state.Notes = append([]models.SongNote(nil), h.notes...)
+		state.Cues = h.cues
+	}
+	state.Blanked = h.blanked
+	state.Rehearsal = h.rehearsal
+	h.mu.RUnlock()
+
+	for _, alert := range h.ActiveAlerts() {
+		if sub.wants(Event{Displays: alert.Displays}) {
+			state.Alerts = append(state.Alerts, alert)
+		}
+	}
+	return state
+}
+
+// SendAlert stores an alert and broadcasts it to the targeted displays
+func (h *Hub) SendAlert(message, priority string, ttl time.Duration, displays []string) Alert {
+	now := time.Now()
+
+	h.mu.Lock()
+	h.nextAlertID++
+	alert := Alert{
+		ID:        fmt.Sprintf("alert-%d", h.nextAlertID),
+		Message:   message,
+		Priority:  priority,
+		Displays:  displays,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	h.alerts[alert.ID] = alert
+	h.mu.Unlock()
+
+	h.Publish(Event{Type: EventAlert, Displays: displays, Data: alert, Timestamp: now})
+	return alert
+}
+
+// DismissAlert removes an alert before it expires
+func (h *Hub) DismissAlert(id string) error {
+	h.mu.Lock()
+	alert, ok := h.alerts[id]
+	delete(h.alerts, id)
+	h.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("alert not found")
+	}
+
+	h.Publish(Event{Type: EventAlertDismissed, Displays: alert.Displays, Data: map[string]string{"id": id}})
+	return nil
+}
+
+// ActiveAlerts returns unexpired alerts, pruning expired ones
+func (h *Hub) ActiveAlerts() []Alert {
+	now := time.Now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	alerts := make([]Alert, 0, len(h.alerts))
+	for id, alert := range h.alerts {
+		if now.After(alert.ExpiresAt) {
+			delete(h.alerts, id)
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].CreatedAt.Before(alerts[j].CreatedAt)
+	})
+	return alerts
+}
+
+// SetCurrent records what was just put on screen and tells every display
+func (h *Hub) SetCurrent(now NowShowing) {
+	now.UpdatedAt = time.Now()
+
+	h.mu.Lock()
+	now.Rehearsal = h.rehearsal
+	h.current = &now
+	h.mu.Unlock()
+
+	h.Publish(Event{Type: EventSlide, Data: now, Timestamp: now.UpdatedAt})
+}
+
+// SetNotes replaces the presenter notes for the song on screen and sends
+// them to stage displays only
+func (h *Hub) SetNotes(songID string, notes []models.SongNote) {
+	if notes == nil {
+		notes = make([]models.SongNote, 0)
+	}
+
+	h.mu.Lock()
+	h.notes = notes
+	h.mu.Unlock()
+
+	h.Publish(Event{
+		Type:  EventNotes,
+		Roles: []string{RoleStage},
+		Data:  map[string]interface{}{"song_id": songID, "notes": notes},
+	})
+}
+
+// SetCues replaces the band cues for the song on screen and sends them to
+// stage displays only. cues is nil when the song has none.
+func (h *Hub) SetCues(songID string, cues *models.SongCues) {
+	h.mu.Lock()
+	h.cues = cues
+	h.mu.Unlock()
+
+	h.Publish(Event{
+		Type:  EventCues,
+		Roles: []string{RoleStage},
+		Data:  map[string]interface{}{"song_id": songID, "cues": cues},
+	})
+}
+
+// AdvanceSlide moves the current slide index forward or backward
+func (h *Hub) AdvanceSlide(delta int) {
+	h.mu.Lock()
+	if h.current == nil {
+		h.mu.Unlock()
+		return
+	}
+	now := *h.current
+	now.SlideIndex += delta
+	if now.SlideIndex < 0 {
+		now.SlideIndex = 0
+	}
+	now.UpdatedAt = time.Now()
+	h.current = &now
+	h.mu.Unlock()
+
+	h.Publish(Event{Type: EventSlide, Data: now, Timestamp: now.UpdatedAt})
+}
+
+// GoToSlide jumps to a slide of what is on screen
+func (h *Hub) GoToSlide(index int) {
+	h.mu.Lock()
+	if h.current == nil {
+		h.mu.Unlock()
+		return
+	}
+	now := *h.current
+	now.SlideIndex = index
+	if now.SlideIndex < 0 {
+		now.SlideIndex = 0
+	}
+	now.UpdatedAt = time.Now()
+	h.current = &now
+	h.mu.Unlock()
+
+	h.Publish(Event{Type: EventSlide, Data: now, Timestamp: now.UpdatedAt})
+}
+
+// Current returns what is on screen, or nil if nothing has been triggered
+func (h *Hub) Current() *NowShowing {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.current == nil {
+		return nil
+	}
+	current := *h.current
+	return &current
+}
+
+// SetBlanked blanks or restores every display. It returns false when the
+// displays were already in the requested state.
+func (h *Hub) SetBlanked(blanked bool) bool {
+	h.mu.Lock()
+	if h.blanked == blanked {
+		h.mu.Unlock()
+		return false
+	}
+	h.blanked = blanked
+	current := h.current
+	h.mu.Unlock()
+
+	evt := Event{Type: EventBlank}
+	if !blanked {
+		// Displays restore whatever was showing before the blank
+		evt = Event{Type: EventUnblank, Data: current}
+	}
+	h.Publish(evt)
+	return true
+}
+
+// IsBlanked reports whether displays are currently blanked
+func (h *Hub) IsBlanked() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.blanked
+}
+
+// SetRehearsal switches between rehearsal and production mode. It returns
+// false when the hub was already in the requested mode.
+func (h *Hub) SetRehearsal(rehearsal bool) bool {
+	h.mu.Lock()
+	if h.rehearsal == rehearsal {
+		h.mu.Unlock()
+		return false
+	}
+	h.rehearsal = rehearsal
+	h.mu.Unlock()
+
+	h.Publish(Event{Type: EventMode, Data: map[string]bool{"rehearsal": rehearsal}})
+	return true
+}
+
+// IsRehearsal reports whether the app is in rehearsal mode
+func (h *Hub) IsRehearsal() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.rehearsal
+}
+
+// Panic blanks every display and drops all alerts in one step, regardless of
+// the current state, so displays always receive the reset
+func (h *Hub) Panic() {
+	h.mu.Lock()
+	h.blanked = true
+	h.alerts = make(map[string]Alert)
+	h.mu.Unlock()
+
+	h.Publish(Event{Type: EventPanic})
+}
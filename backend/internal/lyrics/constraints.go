@@ -0,0 +1,121 @@
+package lyrics
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// DisplayWidth counts the characters a line takes up on screen: emphasis
+// markup is left out, and so are combining marks and joiners, which sit on
+// the letter before them (a Malayalam vowel sign is not a character of its own)
+func DisplayWidth(line string) int {
+	width := 0
+	for _, r := range PlainLine(line) {
+		if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Cf, r) {
+			continue
+		}
+		width++
+	}
+	return width
+}
+
+// stanza is a section of lyrics as written, with where it starts
+type stanza struct {
+	label     string
+	firstLine int
+	lines     []string
+}
+
+// CheckDisplay checks lyrics in a language against display profiles,
+// returning a warning for every line too wide and every stanza too long for
+// one slide of each profile
+func CheckDisplay(text, language string, profiles []models.DisplayProfile) []models.DisplayWarning {
+	warnings := make([]models.DisplayWarning, 0)
+	if len(profiles) == 0 {
+		return warnings
+	}
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+
+	type numbered struct {
+		number int
+		text   string
+		label  string
+	}
+	lines := make([]numbered, 0)
+	stanzas := make([]stanza, 0)
+	current := stanza{}
+	flush := func() {
+		if len(current.lines) > 0 {
+			stanzas = append(stanzas, current)
+		}
+		current = stanza{}
+	}
+
+	for i, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "":
+			flush()
+		case IsSectionLabel(line):
+			flush()
+			current.label = strings.TrimSuffix(strings.Trim(line, "[] "), ":")
+		case sectionReference.MatchString(line):
+			// "Repeat Chorus" is replaced by the chorus, never shown itself
+		default:
+			if len(current.lines) == 0 {
+				current.firstLine = i + 1
+			}
+			current.lines = append(current.lines, line)
+			// A trailing "x2" is taken off before the line is shown
+			shown := line
+			if m := repeatMarker.FindStringSubmatch(line); m != nil {
+				shown = strings.TrimSpace(m[1])
+			}
+			if shown != "" {
+				lines = append(lines, numbered{number: i + 1, text: shown, label: current.label})
+			}
+		}
+	}
+	flush()
+
+	for _, profile := range profiles {
+		limits := profile.LimitsFor(language)
+
+		if limits.MaxCharsPerLine > 0 {
+			for _, line := range lines {
+				if width := DisplayWidth(line.text); width > limits.MaxCharsPerLine {
+					warnings = append(warnings, models.DisplayWarning{
+						Profile: profile.Name, Kind: models.WarningLineTooLong,
+						Line: line.number, Section: line.label, Text: line.text,
+						Length: width, Limit: limits.MaxCharsPerLine,
+						Message: fmt.Sprintf("Line %d is %d characters; %s fits %d", line.number, width, profile.Name, limits.MaxCharsPerLine),
+					})
+				}
+			}
+		}
+
+		if limits.MaxLinesPerSlide > 0 {
+			for _, s := range stanzas {
+				shown, _ := expandLineRepeats(s.lines)
+				if len(shown) <= limits.MaxLinesPerSlide {
+					continue
+				}
+				name := s.label
+				if name == "" {
+					name = fmt.Sprintf("The stanza at line %d", s.firstLine)
+				}
+				warnings = append(warnings, models.DisplayWarning{
+					Profile: profile.Name, Kind: models.WarningTooManyLines,
+					Line: s.firstLine, Section: s.label,
+					Length: len(shown), Limit: limits.MaxLinesPerSlide,
+					Message: fmt.Sprintf("%s has %d lines; %s shows %d per slide, so it will be split", name, len(shown), profile.Name, limits.MaxLinesPerSlide),
+				})
+			}
+		}
+	}
+
+	return warnings
+}
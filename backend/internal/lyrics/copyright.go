@@ -0,0 +1,81 @@
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// CopyrightLabel is the section label of the attribution slide
+const CopyrightLabel = "Copyright"
+
+// copyrightPrefix matches the ways a copyright line may already be marked, so
+// the symbol isn't doubled
+var copyrightPrefix = regexp.MustCompile(`(?i)^(©|\(c\)|copyright)\s*`)
+
+// CopyrightSlide returns the lines of a song's attribution slide in the layout
+// CCLI asks for:
+//
+//	"Title"
+//	Words and music by Artist
+//	© 2004 Publisher
+//	CCLI Song # 1234567
+//	CCLI License # 7654321
+//
+// It returns nil when the song has neither a copyright nor a CCLI number, since
+// there is nothing to attribute. Missing lines are left out.
+func CopyrightSlide(song *models.Song, license string) []string {
+	copyright := ""
+	if song.Copyright != nil {
+		copyright = copyrightPrefix.ReplaceAllString(strings.TrimSpace(*song.Copyright), "")
+	}
+	ccli := ""
+	if song.CCLINumber != nil {
+		ccli = strings.TrimSpace(*song.CCLINumber)
+	}
+	if copyright == "" && ccli == "" {
+		return nil
+	}
+
+	lines := []string{`"` + song.Title + `"`}
+	if song.Artist != nil && strings.TrimSpace(*song.Artist) != "" {
+		lines = append(lines, "Words and music by "+strings.TrimSpace(*song.Artist))
+	}
+	if copyright != "" {
+		lines = append(lines, "© "+copyright)
+	}
+	if ccli != "" {
+		lines = append(lines, "CCLI Song # "+ccli)
+	}
+	if license = strings.TrimSpace(license); license != "" {
+		lines = append(lines, "CCLI License # "+license)
+	}
+	return lines
+}
+
+// Attribution is what an attribution slide says about a song
+type Attribution struct {
+	Artist     string
+	Copyright  string
+	CCLINumber string
+}
+
+// ParseCopyrightSlide reads back the lines CopyrightSlide writes, e.g. from a
+// presentation made by another program in the same layout. Lines it doesn't
+// recognize are ignored.
+func ParseCopyrightSlide(lines []string) Attribution {
+	var a Attribution
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Words and music by "):
+			a.Artist = strings.TrimSpace(strings.TrimPrefix(line, "Words and music by "))
+		case strings.HasPrefix(line, "CCLI Song #"):
+			a.CCLINumber = strings.TrimSpace(strings.TrimPrefix(line, "CCLI Song #"))
+		case copyrightPrefix.MatchString(line):
+			a.Copyright = strings.TrimSpace(copyrightPrefix.ReplaceAllString(line, ""))
+		}
+	}
+	return a
+}
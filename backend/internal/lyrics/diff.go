@@ -0,0 +1,104 @@
+package lyrics
+
+import "strings"
+
+// Kinds of line change in a diff
+const (
+	LineAdded   = "added"
+	LineRemoved = "removed"
+)
+
+// LineDiff is a line added or removed between two versions of lyrics.
+// OldLine and NewLine number it in the old and new version, from 1; a
+// removed line has no NewLine and an added line no OldLine.
+type LineDiff struct {
+	Op      string `json:"op"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+	Text    string `json:"text"`
+}
+
+// maxDiffCells bounds the comparison table; past it the changed middle of
+// the lyrics is reported as replaced wholesale
+const maxDiffCells = 4000000
+
+// DiffLines returns the lines removed from before and added in after, in
+// order, using the longest common sequence of lines. Unchanged lines are
+// left out.
+func DiffLines(before, after string) []LineDiff {
+	a, b := splitLines(before), splitLines(after)
+
+	// Lines shared at the start and end need no comparing
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+	endA, endB := len(a), len(b)
+	for endA > start && endB > start && a[endA-1] == b[endB-1] {
+		endA--
+		endB--
+	}
+	midA, midB := a[start:endA], b[start:endB]
+
+	diff := make([]LineDiff, 0)
+	removed := func(i int) {
+		diff = append(diff, LineDiff{Op: LineRemoved, OldLine: start + i + 1, Text: midA[i]})
+	}
+	added := func(j int) {
+		diff = append(diff, LineDiff{Op: LineAdded, NewLine: start + j + 1, Text: midB[j]})
+	}
+
+	if len(midA)*len(midB) > maxDiffCells {
+		for i := range midA {
+			removed(i)
+		}
+		for j := range midB {
+			added(j)
+		}
+		return diff
+	}
+
+	// common[i][j] is the length of the longest common sequence of midA[i:]
+	// and midB[j:]
+	common := make([][]int, len(midA)+1)
+	for i := range common {
+		common[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			switch {
+			case midA[i] == midB[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			i++
+			j++
+		case j == len(midB) || (i < len(midA) && common[i+1][j] >= common[i][j+1]):
+			removed(i)
+			i++
+		default:
+			added(j)
+			j++
+		}
+	}
+	return diff
+}
+
+// splitLines splits lyrics into lines; empty lyrics have none
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
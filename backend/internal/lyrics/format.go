@@ -0,0 +1,119 @@
+package lyrics
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Kinds of formatting fix
+const (
+	FixLineEndings        = "line_endings"        // \r\n or \r line breaks
+	FixTrailingWhitespace = "trailing_whitespace" // spaces or tabs at either end of a line
+	FixDoubleSpaces       = "double_spaces"
+	FixSmartQuotes        = "smart_quotes" // curly quotes and apostrophes
+	FixSectionLabels      = "section_labels"
+)
+
+// FormatFix counts the lines a kind of fix changed
+type FormatFix struct {
+	Kind  string `json:"kind"`
+	Lines int    `json:"lines"`
+}
+
+// LineChange is a line the formatter changed, numbered from 1
+type LineChange struct {
+	Line   int    `json:"line"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// FormatResult is formatted lyrics with what changed, for an editor to
+// confirm before saving
+type FormatResult struct {
+	Lyrics  string       `json:"lyrics"`
+	Changed bool         `json:"changed"`
+	Fixes   []FormatFix  `json:"fixes"`
+	Diff    []LineChange `json:"diff"`
+}
+
+var smartQuotes = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`,
+)
+
+// Format tidies the typing of lyrics without touching their words: straight
+// quotes, single spaces, no whitespace at line ends, "\n" line breaks and
+// section labels capitalized the same way ("verse 1" and "CHORUS" become
+// "Verse 1" and "Chorus"). Lines are never added or removed, so the diff
+// pairs each changed line with its original.
+func Format(text string) FormatResult {
+	counts := make(map[string]int)
+	if strings.Contains(text, "\r") {
+		counts[FixLineEndings] = strings.Count(text, "\r")
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+		text = strings.ReplaceAll(text, "\r", "\n")
+	}
+
+	lines := strings.Split(text, "\n")
+	diff := make([]LineChange, 0)
+	for i, before := range lines {
+		line := before
+
+		if trimmed := strings.TrimFunc(line, isBlank); trimmed != line {
+			counts[FixTrailingWhitespace]++
+			line = trimmed
+		}
+		if collapsed := strings.Join(strings.FieldsFunc(line, isBlank), " "); collapsed != line {
+			counts[FixDoubleSpaces]++
+			line = collapsed
+		}
+		if straight := smartQuotes.Replace(line); straight != line {
+			counts[FixSmartQuotes]++
+			line = straight
+		}
+		if IsSectionLabel(line) {
+			if label := capitalizeLabel(line); label != line {
+				counts[FixSectionLabels]++
+				line = label
+			}
+		}
+
+		if line != before {
+			diff = append(diff, LineChange{Line: i + 1, Before: before, After: line})
+			lines[i] = line
+		}
+	}
+
+	result := FormatResult{Lyrics: strings.Join(lines, "\n"), Fixes: make([]FormatFix, 0), Diff: diff}
+	for _, kind := range []string{FixLineEndings, FixTrailingWhitespace, FixDoubleSpaces, FixSmartQuotes, FixSectionLabels} {
+		if counts[kind] > 0 {
+			result.Fixes = append(result.Fixes, FormatFix{Kind: kind, Lines: counts[kind]})
+		}
+	}
+	result.Changed = len(result.Fixes) > 0
+	return result
+}
+
+func isBlank(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\u00a0'
+}
+
+// capitalizeLabel capitalizes each word of a section label, keeping its
+// brackets and punctuation: "[pre-chorus]" becomes "[Pre-Chorus]"
+func capitalizeLabel(label string) string {
+	runes := []rune(label)
+	start := true
+	for i, r := range runes {
+		if !unicode.IsLetter(r) {
+			start = true
+			continue
+		}
+		if start {
+			runes[i] = unicode.ToUpper(r)
+		} else {
+			runes[i] = unicode.ToLower(r)
+		}
+		start = false
+	}
+	return string(runes)
+}
@@ -0,0 +1,229 @@
+package lyrics
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Lyrics may carry a minimal emphasis markup for leader cues and repeated lines:
+//
+//	**bold**   *italic*   ==highlight==
+//
+// Markers apply within a single line. A backslash escapes a marker character
+// (\* or \=). ProPresenter and other plain-text outputs get the text with the
+// markers removed; teleprompter displays get styled spans or HTML.
+
+// Span is a run of text with one combination of styles
+type Span struct {
+	Text      string `json:"text"`
+	Bold      bool   `json:"bold,omitempty"`
+	Italic    bool   `json:"italic,omitempty"`
+	Highlight bool   `json:"highlight,omitempty"`
+}
+
+const (
+	markerBold      = "**"
+	markerItalic    = "*"
+	markerHighlight = "=="
+)
+
+var htmlTag = regexp.MustCompile(`</?[A-Za-z][A-Za-z0-9]*(\s[^<>]*)?/?>`)
+
+// piece is either literal text or a marker found on a line
+type piece struct {
+	text    string
+	marker  string
+	matched bool
+}
+
+// scanMarkup splits a line into literal text and markers. Markers of each kind
+// pair up in order (first with second, third with fourth); a leftover one is
+// unmatched and treated as literal text.
+func scanMarkup(line string) []piece {
+	var pieces []piece
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			pieces = append(pieces, piece{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && (line[i+1] == '*' || line[i+1] == '=' || line[i+1] == '\\'):
+			text.WriteByte(line[i+1])
+			i += 2
+		case strings.HasPrefix(line[i:], markerBold):
+			flush()
+			pieces = append(pieces, piece{marker: markerBold})
+			i += 2
+		case strings.HasPrefix(line[i:], markerHighlight):
+			flush()
+			pieces = append(pieces, piece{marker: markerHighlight})
+			i += 2
+		case line[i] == '*':
+			flush()
+			pieces = append(pieces, piece{marker: markerItalic})
+			i++
+		default:
+			text.WriteByte(line[i])
+			i++
+		}
+	}
+	flush()
+
+	open := make(map[string]int)
+	for i, p := range pieces {
+		if p.marker == "" {
+			continue
+		}
+		if j, ok := open[p.marker]; ok {
+			pieces[j].matched = true
+			pieces[i].matched = true
+			delete(open, p.marker)
+		} else {
+			open[p.marker] = i
+		}
+	}
+
+	return pieces
+}
+
+// ParseMarkup returns the styled spans of one line
+func ParseMarkup(line string) []Span {
+	spans := make([]Span, 0)
+	var style Span
+
+	for _, p := range scanMarkup(line) {
+		text := p.text
+		if p.marker != "" {
+			if !p.matched {
+				text = p.marker
+			} else {
+				switch p.marker {
+				case markerBold:
+					style.Bold = !style.Bold
+				case markerItalic:
+					style.Italic = !style.Italic
+				case markerHighlight:
+					style.Highlight = !style.Highlight
+				}
+				continue
+			}
+		}
+
+		if n := len(spans); n > 0 && spans[n-1].Bold == style.Bold && spans[n-1].Italic == style.Italic && spans[n-1].Highlight == style.Highlight {
+			spans[n-1].Text += text
+			continue
+		}
+		span := style
+		span.Text = text
+		spans = append(spans, span)
+	}
+
+	return spans
+}
+
+// HasMarkup reports whether text contains any emphasis markers
+func HasMarkup(text string) bool {
+	return strings.ContainsAny(text, "*\\") || strings.Contains(text, markerHighlight)
+}
+
+// PlainLine removes the markup from one line
+func PlainLine(line string) string {
+	if !HasMarkup(line) {
+		return line
+	}
+	var b strings.Builder
+	for _, span := range ParseMarkup(line) {
+		b.WriteString(span.Text)
+	}
+	return b.String()
+}
+
+// StripMarkup removes the markup from lyrics, line by line
+func StripMarkup(text string) string {
+	if !HasMarkup(text) {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = PlainLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HTMLLine renders one line as escaped HTML using <strong>, <em> and <mark>
+func HTMLLine(line string) string {
+	var b strings.Builder
+	for _, span := range ParseMarkup(line) {
+		text := html.EscapeString(span.Text)
+		if span.Italic {
+			text = "<em>" + text + "</em>"
+		}
+		if span.Bold {
+			text = "<strong>" + text + "</strong>"
+		}
+		if span.Highlight {
+			text = "<mark>" + text + "</mark>"
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// SanitizeMarkup cleans lyrics before they are stored: pasted HTML tags are
+// removed, markers that have no partner on their line are escaped so they
+// show literally, and empty emphasis pairs are dropped
+func SanitizeMarkup(text string) string {
+	text = htmlTag.ReplaceAllString(text, "")
+	if !HasMarkup(text) {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		pieces := scanMarkup(line)
+
+		var b strings.Builder
+		for j := 0; j < len(pieces); j++ {
+			p := pieces[j]
+			switch {
+			case p.marker == "":
+				b.WriteString(EscapeMarkup(p.text))
+			case !p.matched:
+				b.WriteString(EscapeMarkup(p.marker))
+			case j+1 < len(pieces) && pieces[j+1].marker == p.marker && pieces[j+1].matched:
+				j++ // empty pair such as "****"
+			default:
+				b.WriteString(p.marker)
+			}
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// EscapeMarkup escapes literal text so it is not read as markup, e.g. lyrics
+// imported from other programs. Backslashes are only escaped where they would
+// otherwise start an escape sequence.
+func EscapeMarkup(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '*':
+			b.WriteString(`\*`)
+		case c == '=' && i+1 < len(text) && text[i+1] == '=':
+			b.WriteString(`\=`)
+		case c == '\\' && (i+1 == len(text) || strings.IndexByte(`*=\`, text[i+1]) >= 0):
+			b.WriteString(`\\`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
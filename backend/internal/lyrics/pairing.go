@@ -0,0 +1,116 @@
+package lyrics
+
+import "github.com/yourusername/audience-stage-teleprompter/internal/models"
+
+// AutoAlign pairs sections of two songs. Sections with matching labels are paired
+// first; anything left over is paired by position.
+func AutoAlign(primary, secondary []Section) []models.SectionAlignment {
+	alignment := make([]models.SectionAlignment, 0, len(primary))
+	used := make(map[int]bool, len(secondary))
+
+	// Labelled sections: pair the n-th "chorus" with the n-th "chorus"
+	byLabel := make(map[string][]int)
+	for i, s := range secondary {
+		if s.Label != "" {
+			key := NormalizeLabel(s.Label)
+			byLabel[key] = append(byLabel[key], i)
+		}
+	}
+
+	matched := make([]int, len(primary))
+	for i, s := range primary {
+		matched[i] = -1
+		if s.Label == "" {
+			continue
+		}
+		key := NormalizeLabel(s.Label)
+		if candidates := byLabel[key]; len(candidates) > 0 {
+			matched[i] = candidates[0]
+			byLabel[key] = candidates[1:]
+			used[candidates[0]] = true
+		}
+	}
+
+	// Positional fallback for the rest
+	next := 0
+	for i := range primary {
+		if matched[i] == -1 {
+			for next < len(secondary) && used[next] {
+				next++
+			}
+			if next < len(secondary) {
+				matched[i] = next
+				used[next] = true
+				next++
+			}
+		}
+		alignment = append(alignment, models.SectionAlignment{Primary: i, Secondary: matched[i]})
+	}
+
+	return alignment
+}
+
+// ValidateAlignment checks every index refers to an existing section
+func ValidateAlignment(alignment []models.SectionAlignment, primaryCount, secondaryCount int) bool {
+	for _, a := range alignment {
+		if a.Primary < -1 || a.Primary >= primaryCount {
+			return false
+		}
+		if a.Secondary < -1 || a.Secondary >= secondaryCount {
+			return false
+		}
+		if a.Primary == -1 && a.Secondary == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// PairSlides builds the combined slide stream, interleaving each primary line with
+// the matching secondary line. linesPerSlide counts line pairs, not physical lines.
+func PairSlides(primary, secondary []Section, alignment []models.SectionAlignment, linesPerSlide int) []models.PairedSlide {
+	if linesPerSlide <= 0 {
+		linesPerSlide = 2
+	}
+
+	slides := make([]models.PairedSlide, 0)
+	for _, a := range alignment {
+		var p, s Section
+		if a.Primary >= 0 && a.Primary < len(primary) {
+			p = primary[a.Primary]
+		}
+		if a.Secondary >= 0 && a.Secondary < len(secondary) {
+			s = secondary[a.Secondary]
+		}
+
+		count := len(p.Lines)
+		if len(s.Lines) > count {
+			count = len(s.Lines)
+		}
+
+		for start := 0; start < count; start += linesPerSlide {
+			slide := models.PairedSlide{
+				Index:          len(slides),
+				PrimaryLabel:   p.Label,
+				SecondaryLabel: s.Label,
+				Lines:          make([]models.PairedLine, 0, linesPerSlide),
+			}
+			for i := start; i < start+linesPerSlide && i < count; i++ {
+				slide.Lines = append(slide.Lines, models.PairedLine{
+					Primary:   PlainLine(lineAt(p.Lines, i)),
+					Secondary: PlainLine(lineAt(s.Lines, i)),
+				})
+			}
+			slides = append(slides, slide)
+		}
+	}
+
+	return slides
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
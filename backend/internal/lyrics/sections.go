@@ -0,0 +1,62 @@
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Section is a stanza of lyrics, optionally labelled (Verse 1, Chorus, ...)
+type Section struct {
+	Label string   `json:"label,omitempty"`
+	Lines []string `json:"lines"`
+}
+
+// sectionLabel matches lines such as "Verse 1", "[Chorus]", "Bridge:", "V2" or "Pre-Chorus"
+var sectionLabel = regexp.MustCompile(`(?i)^\[?\s*((verse|chorus|pre-?chorus|bridge|tag|intro|outro|ending|refrain|interlude|coda)\s*\d*|[vcb]\s*\d+)\s*\]?:?$`)
+
+// IsSectionLabel reports whether a line is a section heading rather than a lyric
+func IsSectionLabel(line string) bool {
+	return sectionLabel.MatchString(strings.TrimSpace(line))
+}
+
+// ParseSections splits lyrics into stanzas separated by blank lines.
+// A heading line at the top of a stanza becomes that section's label.
+func ParseSections(text string) []Section {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	sections := make([]Section, 0)
+	current := Section{}
+
+	flush := func() {
+		if len(current.Lines) > 0 || current.Label != "" {
+			sections = append(sections, current)
+		}
+		current = Section{}
+	}
+
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			flush()
+			continue
+		}
+		if IsSectionLabel(line) {
+			// A heading always starts a new section, even without a blank line before it
+			flush()
+			current.Label = strings.TrimSuffix(strings.Trim(line, "[] "), ":")
+			continue
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	flush()
+
+	return sections
+}
+
+// NormalizeLabel lowercases a label and strips punctuation so "[Chorus]" matches "chorus:"
+func NormalizeLabel(label string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	label = strings.Trim(label, "[]:")
+	return strings.Join(strings.Fields(label), " ")
+}
@@ -0,0 +1,230 @@
+package lyrics
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Slide is one screen of lyrics produced by the segmenter. Lines are plain
+// text; when the lyrics use emphasis markup, HTML holds the styled lines.
+type Slide struct {
+	Index int      `json:"index"`
+	Label string   `json:"label,omitempty"`
+	Lines []string `json:"lines"`
+	HTML  []string `json:"html,omitempty"`
+}
+
+// Text joins the slide lines the way ProPresenter expects them
+func (s Slide) Text() string {
+	return strings.Join(s.Lines, "\n")
+}
+
+// SegmentOptions controls how lyrics are split into slides
+type SegmentOptions struct {
+	MaxLinesPerSlide int  `json:"max_lines_per_slide"`
+	ExpandRepeats    bool `json:"expand_repeats"` // expand "x2" markers and bare chorus references
+	Balance          bool `json:"balance"`        // split long stanzas into even chunks (3+3 rather than 4+2)
+}
+
+// DefaultSegmentOptions are used when creating ProPresenter presentations
+var DefaultSegmentOptions = SegmentOptions{
+	MaxLinesPerSlide: 4,
+	ExpandRepeats:    true,
+	Balance:          true,
+}
+
+var (
+	// repeatMarker matches a trailing "x2", "(x3)", "[2x]" or "×2" on a lyric line
+	repeatMarker = regexp.MustCompile(`(?i)^(.*?)(?:^|\s+)[\(\[]?\s*(?:[x×]\s*(\d+)|(\d+)\s*[x×])\s*[\)\]]?$`)
+	// sectionReference matches lines like "Repeat Chorus", "(Chorus)" or "Chorus x2"
+	sectionReference = regexp.MustCompile(`(?i)^[\(\[]?\s*(?:repeat\s+)?(chorus|refrain|pre-?chorus|bridge|tag)\s*(?:[x×]\s*(\d+)|(\d+)\s*[x×])?\s*[\)\]]?$`)
+)
+
+// maxRepeat caps expansion so a typo like "x20" can't flood the slide list
+const maxRepeat = 4
+
+// Segment turns lyrics into slides, applying repeat expansion and stanza balancing.
+// Warnings describe anything the editor may want to double-check.
+func Segment(text string, opts SegmentOptions) ([]Slide, []string) {
+	if opts.MaxLinesPerSlide <= 0 {
+		opts.MaxLinesPerSlide = DefaultSegmentOptions.MaxLinesPerSlide
+	}
+
+	sections := ParseSections(text)
+	warnings := make([]string, 0)
+
+	if opts.ExpandRepeats {
+		sections, warnings = expandRepeats(sections)
+	}
+
+	slides := make([]Slide, 0)
+	for _, section := range sections {
+		if len(section.Lines) == 0 {
+			continue
+		}
+		for _, chunk := range chunkLines(section.Lines, opts.MaxLinesPerSlide, opts.Balance) {
+			slide := Slide{
+				Index: len(slides),
+				Label: section.Label,
+				Lines: make([]string, len(chunk)),
+			}
+			styled := false
+			for i, line := range chunk {
+				slide.Lines[i] = PlainLine(line)
+				styled = styled || slide.Lines[i] != line
+			}
+			if styled {
+				slide.HTML = make([]string, len(chunk))
+				for i, line := range chunk {
+					slide.HTML[i] = HTMLLine(line)
+				}
+			}
+			slides = append(slides, slide)
+		}
+	}
+
+	return slides, warnings
+}
+
+// expandRepeats resolves chorus references and "xN" markers into real lines
+func expandRepeats(sections []Section) ([]Section, []string) {
+	warnings := make([]string, 0)
+	seen := make(map[string][]string)
+	expanded := make([]Section, 0, len(sections))
+
+	for _, section := range sections {
+		// "Chorus" heading with no lines: reuse the last section with that label
+		if len(section.Lines) == 0 && section.Label != "" {
+			lines, ok := seen[NormalizeLabel(section.Label)]
+			if !ok {
+				lines, ok = seen[NormalizeLabel(stripNumber(section.Label))]
+			}
+			if ok {
+				expanded = append(expanded, Section{Label: section.Label, Lines: lines})
+			} else {
+				warnings = append(warnings, fmt.Sprintf("Section %q has no lyrics and no earlier section to repeat", section.Label))
+			}
+			continue
+		}
+
+		// Single-line "Repeat Chorus" / "(Chorus x2)" stanzas
+		if len(section.Lines) == 1 {
+			if m := sectionReference.FindStringSubmatch(section.Lines[0]); m != nil {
+				key := NormalizeLabel(m[1])
+				lines, ok := seen[key]
+				if !ok {
+					warnings = append(warnings, fmt.Sprintf("%q refers to a %s that has not appeared yet", section.Lines[0], m[1]))
+					continue
+				}
+				times := repeatCount(m[2], m[3])
+				for i := 0; i < times; i++ {
+					expanded = append(expanded, Section{Label: strings.Title(key), Lines: lines})
+				}
+				continue
+			}
+		}
+
+		lines, sectionRepeats := expandLineRepeats(section.Lines)
+		if sectionRepeats > maxRepeat {
+			warnings = append(warnings, fmt.Sprintf("Repeat count in %q capped at %d", section.Label, maxRepeat))
+			sectionRepeats = maxRepeat
+		}
+
+		result := Section{Label: section.Label, Lines: lines}
+		if section.Label != "" {
+			seen[NormalizeLabel(section.Label)] = lines
+			seen[NormalizeLabel(stripNumber(section.Label))] = lines
+		}
+		for i := 0; i < sectionRepeats; i++ {
+			expanded = append(expanded, result)
+		}
+	}
+
+	return expanded, warnings
+}
+
+// expandLineRepeats repeats lines ending in "xN". A marker on a line of its own
+// repeats the whole stanza, which is returned as the section repeat count.
+func expandLineRepeats(lines []string) ([]string, int) {
+	result := make([]string, 0, len(lines))
+	sectionRepeats := 1
+
+	for i, line := range lines {
+		m := repeatMarker.FindStringSubmatch(line)
+		if m == nil {
+			result = append(result, line)
+			continue
+		}
+
+		times := repeatCount(m[2], m[3])
+		body := strings.TrimSpace(m[1])
+		if body == "" {
+			if i == len(lines)-1 {
+				sectionRepeats = times
+			}
+			continue
+		}
+		for j := 0; j < times; j++ {
+			result = append(result, body)
+		}
+	}
+
+	return result, sectionRepeats
+}
+
+func repeatCount(a, b string) int {
+	value := a
+	if value == "" {
+		value = b
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 1
+	}
+	if n > maxRepeat {
+		return maxRepeat
+	}
+	return n
+}
+
+// chunkLines splits a stanza into slides of at most max lines
+func chunkLines(lines []string, max int, balance bool) [][]string {
+	if len(lines) <= max {
+		return [][]string{lines}
+	}
+
+	chunks := make([][]string, 0)
+	if !balance {
+		for start := 0; start < len(lines); start += max {
+			end := start + max
+			if end > len(lines) {
+				end = len(lines)
+			}
+			chunks = append(chunks, lines[start:end])
+		}
+		return chunks
+	}
+
+	count := (len(lines) + max - 1) / max
+	size := len(lines) / count
+	extra := len(lines) % count
+	start := 0
+	for i := 0; i < count; i++ {
+		n := size
+		if i < extra {
+			n++
+		}
+		chunks = append(chunks, lines[start:start+n])
+		start += n
+	}
+	return chunks
+}
+
+var trailingNumber = regexp.MustCompile(`\s*\d+$`)
+
+// stripNumber turns "Chorus 2" into "Chorus" so numbered repeats share lyrics
+func stripNumber(label string) string {
+	return trailingNumber.ReplaceAllString(strings.TrimSpace(label), "")
+}
@@ -0,0 +1,169 @@
+// Package maintenance pauses changes to the library while it is restored,
+// migrated or reindexed. Reads keep working, and so does everything a
+// service needs live: ProPresenter control, displays and alerts.
+package maintenance
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultMessage is shown to users when an admin gives no message
+const DefaultMessage = "The song library is being updated. Changes are paused for a few minutes; the live display keeps working."
+
+// retryAfter is the Retry-After sent with 503s, in seconds
+const retryAfter = "60"
+
+// OpenRoutes are the write endpoints that stay open during maintenance: the
+// admin tasks maintenance is for, running the live display, and previews
+// that don't save anything. A "*" segment matches any single path segment;
+// entries match the path and everything under it.
+var OpenRoutes = []string{
+	"/api/admin/maintenance",
+	"/api/auth",
+	"/api/admin/import-archive",
+	"/api/admin/seed-demo",
+	"/api/admin/reindex",
+	"/api/admin/backups",
+	"/api/admin/consistency",
+	"/api/admin/index/cleanup",
+	"/api/propresenter",
+	"/api/live",
+	"/api/displays",
+	"/api/audio/stop",
+	"/api/songs/*/audio/play",
+	"/api/scripture/present",
+	"/api/lyrics/format",
+	"/api/lyrics/display-check",
+	"/api/songs/*/preview-slides",
+}
+
+// Status describes the current maintenance window
+type Status struct {
+	Enabled bool       `json:"enabled"`
+	Reason  string     `json:"reason,omitempty"` // e.g. "restore", "reindex"
+	Message string     `json:"message,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
+// Mode is the server's maintenance switch. The zero value is off.
+type Mode struct {
+	mu     sync.Mutex
+	status Status
+	change int // counts Enable and Disable calls, so Begin knows if an admin stepped in
+}
+
+// New returns a Mode that is off
+func New() *Mode {
+	return &Mode{}
+}
+
+// Enable turns maintenance on. An empty message uses DefaultMessage.
+func (m *Mode) Enable(reason, message string) {
+	if message == "" {
+		message = DefaultMessage
+	}
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	since := m.status.Since
+	if !m.status.Enabled {
+		since = &now
+	}
+	m.status = Status{Enabled: true, Reason: reason, Message: message, Since: since}
+	m.change++
+}
+
+// Disable turns maintenance off
+func (m *Mode) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = Status{}
+	m.change++
+}
+
+// Begin turns maintenance on for an operation the server runs itself, such
+// as an archive import. The returned func turns it off again, unless an
+// admin turned it on before or changed it since.
+func (m *Mode) Begin(reason string) (end func()) {
+	if m == nil {
+		return func() {}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.status.Enabled {
+		return func() {}
+	}
+	now := time.Now()
+	m.status = Status{Enabled: true, Reason: reason, Message: DefaultMessage, Since: &now}
+	m.change++
+	began := m.change
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.change == began {
+			m.status = Status{}
+			m.change++
+		}
+	}
+}
+
+// Status returns the current maintenance window
+func (m *Mode) Status() Status {
+	if m == nil {
+		return Status{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Middleware rejects writes outside OpenRoutes with 503 while maintenance
+// is on
+func (m *Mode) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+		status := m.Status()
+		if !status.Enabled || open(c.Path()) {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderRetryAfter, retryAfter)
+		return c.Status(503).JSON(fiber.Map{
+			"error":       "The server is in maintenance mode",
+			"maintenance": status,
+		})
+	}
+}
+
+// open reports whether path is under one of OpenRoutes
+func open(path string) bool {
+	parts := strings.Split(strings.Trim(strings.ToLower(path), "/"), "/")
+	for _, route := range OpenRoutes {
+		if matches(strings.Split(strings.Trim(route, "/"), "/"), parts) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(route, parts []string) bool {
+	if len(parts) < len(route) {
+		return false
+	}
+	for i, segment := range route {
+		if segment != "*" && segment != parts[i] {
+			return false
+		}
+	}
+	return true
+}
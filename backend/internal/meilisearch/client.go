@@ -0,0 +1,448 @@
+// Package meilisearch is a search backend for Meilisearch, for deployments
+// that already run it instead of Typesense. It talks to the REST API
+// directly.
+package meilisearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
+	"github.com/yourusername/audience-stage-teleprompter/internal/slowlog"
+)
+
+// ErrUnavailable is returned while Meilisearch has not come up yet
+var ErrUnavailable = errors.New("meilisearch is not available")
+
+const indexName = "songs"
+
+// batchSize is how many songs go into one documents request
+const batchSize = 500
+
+// taskTimeout bounds how long a reindex waits for Meilisearch to apply a batch
+const taskTimeout = 2 * time.Minute
+
+// settings makes the index search and filter the fields the handlers use
+var settings = map[string]interface{}{
+	"searchableAttributes": []string{"title", "artist", "lyrics"},
+	"filterableAttributes": []string{"language"},
+	"sortableAttributes":   []string{"updated_at"},
+}
+
+// Client is the Meilisearch search backend
+type Client struct {
+	*search.Availability
+	host       string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// Connect creates a client without failing when Meilisearch is unreachable.
+// Like the Typesense client it starts degraded and keeps retrying in the
+// background until the index can be set up.
+func Connect(apiKey, host string) *Client {
+	c := &Client{
+		Availability: search.NewAvailability(false),
+		host:         strings.TrimRight(host, "/"),
+		apiKey:       apiKey,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if err := c.initIndex(); err != nil {
+		log.Printf("⚠️  Meilisearch unavailable, starting with database search: %v", err)
+		go c.Retry("Meilisearch", c.initIndex)
+		return c
+	}
+
+	c.Availability = search.NewAvailability(true)
+	log.Println("Meilisearch client initialized")
+	return c
+}
+
+// apiError is Meilisearch's error response
+type apiError struct {
+	Status  int
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("meilisearch %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// task is an enqueued write; Meilisearch applies writes asynchronously
+type task struct {
+	UID    int64  `json:"taskUid"`
+	Status string `json:"status"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// do sends a request and decodes the JSON response into out (if not nil)
+func (c *Client) do(method, path string, body, out interface{}) error {
+	return c.doContext(context.Background(), method, path, body, out)
+}
+
+func (c *Client) doContext(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.host+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		apiErr := &apiError{Status: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(apiErr)
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// isNotFound reports whether err is Meilisearch saying the index or document doesn't exist
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// wait polls a task until Meilisearch has applied it
+func (c *Client) wait(t task) error {
+	deadline := time.Now().Add(taskTimeout)
+	delay := 50 * time.Millisecond
+	for {
+		var current task
+		if err := c.do(http.MethodGet, fmt.Sprintf("/tasks/%d", t.UID), nil, &current); err != nil {
+			return err
+		}
+		switch current.Status {
+		case "succeeded":
+			return nil
+		case "failed", "canceled":
+			if current.Error != nil {
+				return errors.New(current.Error.Message)
+			}
+			return fmt.Errorf("task %d %s", t.UID, current.Status)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("task %d still %s after %v", t.UID, current.Status, taskTimeout)
+		}
+		time.Sleep(delay)
+		if delay < time.Second {
+			delay *= 2
+		}
+	}
+}
+
+// initIndex creates the songs index and applies its settings if needed
+func (c *Client) initIndex() error {
+	err := c.do(http.MethodGet, "/indexes/"+indexName, nil, nil)
+	if err == nil {
+		log.Println("Index already exists")
+		return nil
+	}
+	if !isNotFound(err) {
+		return err
+	}
+
+	var created task
+	if err := c.do(http.MethodPost, "/indexes", map[string]string{"uid": indexName, "primaryKey": "id"}, &created); err != nil {
+		return fmt.Errorf("error creating index: %w", err)
+	}
+	if err := c.wait(created); err != nil {
+		return fmt.Errorf("error creating index: %w", err)
+	}
+
+	var updated task
+	if err := c.do(http.MethodPatch, "/indexes/"+indexName+"/settings", settings, &updated); err != nil {
+		return fmt.Errorf("error configuring index: %w", err)
+	}
+	if err := c.wait(updated); err != nil {
+		return fmt.Errorf("error configuring index: %w", err)
+	}
+
+	log.Println("Meilisearch index created successfully")
+	return nil
+}
+
+// available returns ErrUnavailable while degraded. Writes pass write=true so
+// a skipped change marks the index stale.
+func (c *Client) available(write bool) error {
+	return c.Check(write, ErrUnavailable)
+}
+
+// CheckReady verifies Meilisearch is reachable and the songs index exists
+func (c *Client) CheckReady(ctx context.Context) error {
+	if err := c.available(false); err != nil {
+		return err
+	}
+	if err := c.doContext(ctx, http.MethodGet, "/indexes/"+indexName, nil, nil); err != nil {
+		return fmt.Errorf("songs index not available: %w", err)
+	}
+	return nil
+}
+
+// IndexSong upserts a song's document, or removes it if the song is archived.
+// Meilisearch applies it shortly after.
+func (c *Client) IndexSong(song *models.Song) error {
+	if err := c.available(true); err != nil {
+		return err
+	}
+	defer slowlog.Observe(slowlog.Meilisearch, "index song", time.Now())
+
+	// Archived songs are kept out of search; the document may already be gone
+	if song.ArchivedAt != nil {
+		c.do(http.MethodDelete, "/indexes/"+indexName+"/documents/"+url.PathEscape(song.ID), nil, nil)
+		return nil
+	}
+
+	docs := []map[string]interface{}{search.Document(song)}
+	if err := c.do(http.MethodPost, "/indexes/"+indexName+"/documents", docs, nil); err != nil {
+		return fmt.Errorf("error indexing song: %w", err)
+	}
+	return nil
+}
+
+// DeleteSong removes a song's document
+func (c *Client) DeleteSong(id string) error {
+	if err := c.available(true); err != nil {
+		return err
+	}
+	defer slowlog.Observe(slowlog.Meilisearch, "delete song", time.Now())
+	if err := c.do(http.MethodDelete, "/indexes/"+indexName+"/documents/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("error deleting song from index: %w", err)
+	}
+	return nil
+}
+
+// IndexSongs upserts many songs in batches
+func (c *Client) IndexSongs(songs []models.Song) error {
+	if err := c.available(true); err != nil {
+		return err
+	}
+	progress := &search.Counter{}
+	c.indexBatches(songs, progress)
+	return progress.Err()
+}
+
+// ReindexAll rebuilds the index and returns the first song that failed
+func (c *Client) ReindexAll(songs []models.Song) error {
+	progress := &search.Counter{}
+	if err := c.Reindex(songs, progress); err != nil {
+		return err
+	}
+	return progress.Err()
+}
+
+// Reindex drops and rebuilds the index from songs, reporting progress batch
+// by batch
+func (c *Client) Reindex(songs []models.Song, progress search.ReindexProgress) error {
+	if err := c.available(true); err != nil {
+		return err
+	}
+	log.Println("Starting full reindex...")
+	started := time.Now()
+
+	var deleted task
+	if err := c.do(http.MethodDelete, "/indexes/"+indexName, nil, &deleted); err != nil {
+		log.Printf("Warning: could not delete existing index: %v", err)
+	} else if err := c.wait(deleted); err != nil {
+		log.Printf("Warning: could not delete existing index: %v", err)
+	}
+
+	if err := c.initIndex(); err != nil {
+		return fmt.Errorf("error recreating index: %w", err)
+	}
+
+	c.indexBatches(songs, progress)
+
+	log.Printf("Reindex complete: %d songs processed in %s", len(songs), time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// indexBatches sends songs in batches, waiting for each to be applied so
+// failures are reported against the batch's songs
+func (c *Client) indexBatches(songs []models.Song, progress search.ReindexProgress) {
+	songs = search.Searchable(songs)
+	batches := (len(songs) + batchSize - 1) / batchSize
+	progress.SetTotal(len(songs), batches)
+
+	for batch := 0; batch < batches; batch++ {
+		progress.StartBatch(batch + 1)
+		start := batch * batchSize
+		end := start + batchSize
+		if end > len(songs) {
+			end = len(songs)
+		}
+
+		if err := c.importBatch(songs[start:end]); err != nil {
+			for _, song := range songs[start:end] {
+				progress.Fail(fmt.Errorf("song %s (%s): error importing batch: %w", song.ID, song.Title, err))
+			}
+			continue
+		}
+		progress.Done(end - start)
+		log.Printf("Indexed %d/%d songs", end, len(songs))
+	}
+}
+
+func (c *Client) importBatch(songs []models.Song) error {
+	defer slowlog.Observe(slowlog.Meilisearch, "import batch", time.Now())
+
+	docs := make([]map[string]interface{}, len(songs))
+	for i := range songs {
+		docs[i] = search.Document(&songs[i])
+	}
+	var added task
+	if err := c.do(http.MethodPost, "/indexes/"+indexName+"/documents", docs, &added); err != nil {
+		return err
+	}
+	return c.wait(added)
+}
+
+// Search finds songs by title, artist and lyrics, optionally in some languages
+func (c *Client) Search(query string, languages []string) (*search.Result, error) {
+	if err := c.available(false); err != nil {
+		return nil, err
+	}
+	defer slowlog.Observe(slowlog.Meilisearch, "search", time.Now())
+
+	if query == "*" {
+		query = "" // Typesense's match-all
+	}
+	body := map[string]interface{}{"q": query, "limit": 50}
+
+	// Language filter, matching the stored value however it was capitalized
+	if len(languages) > 0 {
+		values := make([]string, 0, len(languages)*3)
+		seen := make(map[string]bool)
+		for _, lang := range languages {
+			lo := strings.ToLower(strings.TrimSpace(lang))
+			if lo == "" {
+				continue
+			}
+			for _, v := range []string{strings.TrimSpace(lang), lo, strings.Title(lo)} {
+				if !seen[v] {
+					seen[v] = true
+					values = append(values, fmt.Sprintf("%q", v))
+				}
+			}
+		}
+		if len(values) > 0 {
+			body["filter"] = fmt.Sprintf("language IN [%s]", strings.Join(values, ", "))
+		}
+	}
+
+	var result struct {
+		Hits               []map[string]interface{} `json:"hits"`
+		EstimatedTotalHits int                      `json:"estimatedTotalHits"`
+		ProcessingTimeMs   int                      `json:"processingTimeMs"`
+	}
+	if err := c.do(http.MethodPost, "/indexes/"+indexName+"/search", body, &result); err != nil {
+		return nil, fmt.Errorf("error searching: %w", err)
+	}
+
+	songs := make([]models.Song, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		songs = append(songs, search.SongFromDocument(hit))
+	}
+
+	return &search.Result{
+		Songs:      songs,
+		TotalFound: result.EstimatedTotalHits,
+		SearchTime: result.ProcessingTimeMs,
+	}, nil
+}
+
+// IndexedSongs lists every document in the songs index
+func (c *Client) IndexedSongs() ([]search.IndexedSong, error) {
+	if err := c.available(false); err != nil {
+		return nil, err
+	}
+	defer slowlog.Observe(slowlog.Meilisearch, "export documents", time.Now())
+
+	songs := make([]search.IndexedSong, 0)
+	const page = 1000
+	for offset := 0; ; offset += page {
+		var result struct {
+			Results []search.IndexedSong `json:"results"`
+			Total   int                  `json:"total"`
+		}
+		path := fmt.Sprintf("/indexes/%s/documents?fields=id,title,updated_at&limit=%d&offset=%d", indexName, page, offset)
+		if err := c.do(http.MethodGet, path, nil, &result); err != nil {
+			return nil, fmt.Errorf("error exporting index: %w", err)
+		}
+		songs = append(songs, result.Results...)
+		if len(result.Results) < page || len(songs) >= result.Total {
+			return songs, nil
+		}
+	}
+}
+
+// Stats returns the document count and the newest document's update time
+func (c *Client) Stats() (*search.IndexStats, error) {
+	if err := c.available(false); err != nil {
+		return nil, err
+	}
+	defer slowlog.Observe(slowlog.Meilisearch, "index stats", time.Now())
+
+	var indexStats struct {
+		NumberOfDocuments int64 `json:"numberOfDocuments"`
+	}
+	if err := c.do(http.MethodGet, "/indexes/"+indexName+"/stats", nil, &indexStats); err != nil {
+		return nil, fmt.Errorf("error retrieving index stats: %w", err)
+	}
+	stats := &search.IndexStats{Documents: indexStats.NumberOfDocuments}
+
+	var newest struct {
+		Hits []struct {
+			UpdatedAt int64 `json:"updated_at"`
+		} `json:"hits"`
+	}
+	body := map[string]interface{}{
+		"q":                    "",
+		"limit":                1,
+		"sort":                 []string{"updated_at:desc"},
+		"attributesToRetrieve": []string{"updated_at"},
+	}
+	if err := c.do(http.MethodPost, "/indexes/"+indexName+"/search", body, &newest); err != nil {
+		return nil, fmt.Errorf("error finding newest document: %w", err)
+	}
+	if len(newest.Hits) > 0 {
+		t := time.Unix(newest.Hits[0].UpdatedAt, 0)
+		stats.LastIndexedAt = &t
+	}
+	return stats, nil
+}
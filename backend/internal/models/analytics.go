@@ -0,0 +1,30 @@
+package models
+
+// Usage analytics models
+
+type MonthlySongCount struct {
+	Month       string `json:"month"` // YYYY-MM
+	Uses        int    `json:"uses"`
+	UniqueSongs int    `json:"unique_songs"`
+}
+
+type SongUsageCount struct {
+	SongID   string  `json:"song_id"`
+	Title    string  `json:"title"`
+	Language string  `json:"language"`
+	Uses     int     `json:"uses"`
+	LastUsed *string `json:"last_used,omitempty"` // YYYY-MM-DD
+}
+
+type LanguageMonthCount struct {
+	Month    string `json:"month"` // YYYY-MM
+	Language string `json:"language"`
+	Uses     int    `json:"uses"`
+}
+
+type SetLengthStats struct {
+	Services        int     `json:"services"`
+	AverageSongs    float64 `json:"average_songs"`
+	ShortestService int     `json:"shortest_service"`
+	LongestService  int     `json:"longest_service"`
+}
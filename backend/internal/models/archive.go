@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Migration archive format. Bump ArchiveVersion when a section is added or
+// changes shape; imports accept any version up to the current one.
+//
+//	1: initial format
+//	2: songs carry their songbook numbers
+//	3: songbooks section
+//	4: songs carry their background media and look
+//	5: song cues section
+//	6: song timings section
+//	7: song timed lyrics (LRC) section
+//	8: song audio tracks section (uploaded files are not included)
+//	9: songs carry their external links
+//	10: songs carry copyright and CCLI song number; settings carry the CCLI license
+//	11: CCLI usage log section
+//	12: songs carry their public API flag
+//	13: songs carry their archived time
+//	14: lyric variants section
+const (
+	ArchiveFormat  = "audience-stage-teleprompter-archive"
+	ArchiveVersion = 14
+)
+
+// Archive is a database-independent copy of everything needed to move an
+// installation to a new server. IDs are kept so references between sections
+// (pairs, notes, setlists, usage) survive the move. Exports stream the songs
+// after the other sections.
+type Archive struct {
+	Format      string           `json:"format"`
+	Version     int              `json:"version"`
+	ExportedAt  time.Time        `json:"exported_at"`
+	Songs       []Song           `json:"songs,omitempty"`
+	Songbooks   []Songbook       `json:"songbooks"`
+	SongPairs   []SongPair       `json:"song_pairs"` // translation variants
+	SongNotes   []SongNote       `json:"song_notes"`
+	SongCues    []SongCues       `json:"song_cues"`
+	SongTimings []SongTiming     `json:"song_timings"`
+	TimedLyrics []TimedLyrics    `json:"timed_lyrics"`
+	Variants    []LyricVariant   `json:"lyric_variants"`
+	SongAudio   []SongAudio      `json:"song_audio"`
+	Setlists    []ArchiveSetlist `json:"setlists"`
+	Settings    *ArchiveSettings `json:"settings,omitempty"`
+	SongUsage   []ArchiveUsage   `json:"song_usage"`
+	CCLIUsage   []CCLIUsage      `json:"ccli_usage"`
+	Services    []ArchiveService `json:"services"`
+}
+
+// ArchiveSetlist stores a setlist by song ID rather than full songs
+type ArchiveSetlist struct {
+	Name        string    `json:"name"`
+	ServiceDate *string   `json:"service_date,omitempty"`
+	SongIDs     []string  `json:"song_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ArchiveSettings holds the settings that make sense on another machine
+type ArchiveSettings struct {
+	ProPresenterHost     string `json:"propresenter_host"`
+	ProPresenterPort     int    `json:"propresenter_port"`
+	ProPresenterPlaylist string `json:"propresenter_playlist"`
+	RehearsalPlaylist    string `json:"rehearsal_playlist"`
+	CCLILicense          string `json:"ccli_license,omitempty"`
+	CopyrightSlide       bool   `json:"copyright_slide,omitempty"`
+}
+
+// ArchiveUsage is one song_usage row
+type ArchiveUsage struct {
+	SongID      string    `json:"song_id"`
+	ServiceDate string    `json:"service_date"` // YYYY-MM-DD
+	UsedAt      time.Time `json:"used_at"`
+}
+
+// ArchiveService is a service with its event log and, once archived, its report
+type ArchiveService struct {
+	Service
+	Events []ServiceEvent  `json:"events"`
+	Report json.RawMessage `json:"report,omitempty"`
+}
+
+// ArchiveImportResult counts what an import restored
+type ArchiveImportResult struct {
+	Songs       int  `json:"songs"`
+	SongNumbers int  `json:"song_numbers"`
+	SongLinks   int  `json:"song_links"`
+	Songbooks   int  `json:"songbooks"`
+	SongPairs   int  `json:"song_pairs"`
+	SongNotes   int  `json:"song_notes"`
+	SongCues    int  `json:"song_cues"`
+	SongTimings int  `json:"song_timings"`
+	TimedLyrics int  `json:"timed_lyrics"`
+	Variants    int  `json:"lyric_variants"`
+	SongAudio   int  `json:"song_audio"`
+	Setlists    int  `json:"setlists"`
+	Settings    bool `json:"settings"`
+	SongUsage   int  `json:"song_usage"`
+	CCLIUsage   int  `json:"ccli_usage"`
+	Services    int  `json:"services"`
+}
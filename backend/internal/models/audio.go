@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// SongAudio links a song to its original recording. The local player uses the
+// uploaded file, or the URL when there is none; ProPresenter plays its own
+// audio item.
+type SongAudio struct {
+	SongID            string    `json:"song_id"`
+	URL               *string   `json:"url,omitempty"`
+	FileName          *string   `json:"file_name,omitempty"`
+	ProPresenterAudio *string   `json:"propresenter_audio,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// SongAudioRequest sets a track's URL and ProPresenter item; files are uploaded separately
+type SongAudioRequest struct {
+	URL               *string `json:"url,omitempty"`                // empty string clears
+	ProPresenterAudio *string `json:"propresenter_audio,omitempty"` // empty string clears
+}
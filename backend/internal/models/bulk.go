@@ -0,0 +1,13 @@
+package models
+
+// BulkDeleteRequest selects songs to delete. At least one filter is
+// required. Without DryRun=false only a preview is returned; deleting needs
+// the Confirm token from that preview, so nothing is removed unseen.
+type BulkDeleteRequest struct {
+	Language     string  `json:"language,omitempty"`
+	Library      string  `json:"library,omitempty"`
+	NotUsedSince *string `json:"not_used_since,omitempty"` // YYYY-MM-DD; songs not shown live on or after this date
+	DryRun       *bool   `json:"dry_run,omitempty"`        // defaults to true
+	Confirm      string  `json:"confirm,omitempty"`
+	SkipBackup   bool    `json:"skip_backup,omitempty"` // don't take a backup before deleting
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// CCLIUsage is one reported use: a song going live outside rehearsal. The
+// song's details are copied at the time; SongID is nil once the song is deleted.
+type CCLIUsage struct {
+	SongID     *string   `json:"song_id,omitempty"`
+	Title      string    `json:"title"`
+	Artist     *string   `json:"artist,omitempty"`
+	Copyright  *string   `json:"copyright,omitempty"`
+	CCLINumber *string   `json:"ccli_number,omitempty"`
+	UsedAt     time.Time `json:"used_at"`
+}
+
+// CCLIReportRow is one song's line in a CCLI usage report. Digital counts the
+// days the song was projected; the other CCLI categories are always zero here.
+type CCLIReportRow struct {
+	CCLINumber string  `json:"ccli_number"`
+	Title      string  `json:"title"`
+	Artist     *string `json:"artist,omitempty"`
+	Copyright  *string `json:"copyright,omitempty"`
+	Digital    int     `json:"digital"`
+	FirstUsed  string  `json:"first_used"` // YYYY-MM-DD
+	LastUsed   string  `json:"last_used"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// SongCues are structured band cues for a song, kept apart from the lyrics and
+// shown on stage displays and in the queue
+type SongCues struct {
+	SongID     string    `json:"song_id"`
+	IntroBars  *int      `json:"intro_bars,omitempty"`
+	StartsWith string    `json:"starts_with"`
+	Dynamics   string    `json:"dynamics"`
+	Ending     string    `json:"ending"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type SongCuesRequest struct {
+	IntroBars  *int   `json:"intro_bars,omitempty"`
+	StartsWith string `json:"starts_with"`
+	Dynamics   string `json:"dynamics"`
+	Ending     string `json:"ending"`
+}
+
+// SongEndings are the accepted ending types; empty means not specified
+var SongEndings = []string{
+	"cold",   // hard stop on the last chord
+	"fade",   // fade out
+	"ritard", // slow down into the last chord
+	"hold",   // hold the last chord
+	"tag",    // repeat the last line
+	"vamp",   // loop until the leader cues out
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Display health, worked out when the registry is read
+const (
+	DisplayOnline  = "online"
+	DisplayStale   = "stale"   // missed a few heartbeats
+	DisplayOffline = "offline" // missed many; likely off or crashed
+)
+
+// Display is a registered teleprompter or stage display client
+type Display struct {
+	Name         string    `json:"name"`
+	Role         string    `json:"role"`
+	Resolution   string    `json:"resolution,omitempty"`
+	UserAgent    string    `json:"user_agent,omitempty"`
+	Address      string    `json:"address,omitempty"`
+	SongID       *string   `json:"song_id,omitempty"` // what the display reports showing
+	SongTitle    string    `json:"song_title,omitempty"`
+	SlideIndex   *int      `json:"slide_index,omitempty"`
+	RegisteredAt time.Time `json:"registered_at"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+
+	// Worked out for the admin view
+	Status    string `json:"status,omitempty"`
+	Connected bool   `json:"connected"`         // has the live channel open
+	OutOfSync bool   `json:"out_of_sync"`       // showing something other than what is live
+	Problem   string `json:"problem,omitempty"` // why it needs attention
+}
+
+// RegisterDisplay is sent by a display client when it starts
+type RegisterDisplay struct {
+	Name       string `json:"name"`
+	Role       string `json:"role"`
+	Resolution string `json:"resolution"`
+}
+
+// DisplayHeartbeat is sent periodically with what the display is showing
+type DisplayHeartbeat struct {
+	SongID     string `json:"song_id,omitempty"`
+	SongTitle  string `json:"song_title,omitempty"`
+	SlideIndex *int   `json:"slide_index,omitempty"`
+	Resolution string `json:"resolution,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// DuplicateSong identifies a song in a duplicate report
+type DuplicateSong struct {
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Artist   *string `json:"artist,omitempty"`
+	Language string  `json:"language"`
+	Library  string  `json:"library"`
+	Archived bool    `json:"archived,omitempty"`
+}
+
+// DuplicateCandidate is an existing song that a new song looks like
+type DuplicateCandidate struct {
+	DuplicateSong
+	TitleMatch       bool    `json:"title_match"`
+	LyricsSimilarity float64 `json:"lyrics_similarity"` // 0 to 1
+}
+
+// DuplicatePair is two songs in the library that look like the same song
+type DuplicatePair struct {
+	Songs            [2]DuplicateSong `json:"songs"`
+	TitleMatch       bool             `json:"title_match"`
+	LyricsSimilarity float64          `json:"lyrics_similarity"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Review states of a proposed song edit
+const (
+	EditDraft    = "draft"    // still being written; not in the review queue
+	EditPending  = "pending"  // waiting for a reviewer
+	EditApproved = "approved" // applied to the song
+	EditRejected = "rejected"
+)
+
+// SongEdit is a change to a song proposed by a contributor. It only reaches
+// the song, and so search and ProPresenter, once a reviewer approves it.
+type SongEdit struct {
+	ID          int               `json:"id"`
+	SongID      string            `json:"song_id"`
+	SongTitle   string            `json:"song_title"`
+	Status      string            `json:"status"`
+	Contributor string            `json:"contributor"`
+	Note        string            `json:"note,omitempty"`
+	Changes     UpdateSongRequest `json:"changes"`
+	Reviewer    string            `json:"reviewer,omitempty"`
+	ReviewNote  string            `json:"review_note,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	SubmittedAt *time.Time        `json:"submitted_at,omitempty"`
+	ReviewedAt  *time.Time        `json:"reviewed_at,omitempty"`
+
+	// Current holds the song's present values of the changed fields, for
+	// reviewing a single edit
+	Current map[string]interface{} `json:"current,omitempty"`
+}
+
+// SongEditRequest proposes or revises an edit
+type SongEditRequest struct {
+	Contributor string            `json:"contributor"`
+	Note        string            `json:"note"`
+	Changes     UpdateSongRequest `json:"changes"`
+	Submit      bool              `json:"submit"` // send for review now instead of saving a draft
+}
+
+// SongEditReview is a reviewer's decision
+type SongEditReview struct {
+	Note string `json:"note"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SongFilter narrows and orders a song listing. Zero values don't filter.
+type SongFilter struct {
+	Languages    []string
+	Library      string
+	UpdatedSince *time.Time
+	Archived     *bool  // only archived songs, or only songs not archived
+	Sort         string // a song field; see SongSortFields
+	Descending   bool
+	Limit        int // a page of at most this many songs; 0 for all
+	Offset       int
+}
+
+// SongPage is one page of a song listing
+type SongPage struct {
+	Songs      []interface{} `json:"songs"` // songs, or songs narrowed to the fields asked for
+	Page       int           `json:"page"`
+	PerPage    int           `json:"per_page"`
+	Total      int           `json:"total"` // songs matching the filter across all pages
+	TotalPages int           `json:"total_pages"`
+}
+
+// SongSortFields are the fields a song listing can be sorted by
+var SongSortFields = map[string]bool{
+	"title":      true,
+	"artist":     true,
+	"language":   true,
+	"library":    true,
+	"created_at": true,
+	"updated_at": true,
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// EditLock is an editor's advisory claim on a song. The token is only shown
+// to the holder, who sends it back to renew or release the lock.
+type EditLock struct {
+	SongID     string    `json:"song_id"`
+	Holder     string    `json:"holder"`
+	Token      string    `json:"token,omitempty"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	TakenFrom  string    `json:"taken_from,omitempty"` // previous holder, on takeover
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SongNote is a presenter note for a song or one of its sections.
+// Notes are only ever sent to stage/confidence displays.
+type SongNote struct {
+	ID           int       `json:"id" db:"id"`
+	SongID       string    `json:"song_id" db:"song_id"`
+	SectionIndex *int      `json:"section_index,omitempty" db:"section_index"`
+	SectionLabel string    `json:"section_label,omitempty" db:"section_label"`
+	Note         string    `json:"note" db:"note"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type SongNoteRequest struct {
+	SectionIndex *int   `json:"section_index,omitempty"`
+	SectionLabel string `json:"section_label,omitempty"`
+	Note         string `json:"note"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// SongPair links a song with its translation for bilingual services
+type SongPair struct {
+	ID              int                `json:"id" db:"id"`
+	PrimarySongID   string             `json:"primary_song_id" db:"primary_song_id"`
+	SecondarySongID string             `json:"secondary_song_id" db:"secondary_song_id"`
+	Alignment       []SectionAlignment `json:"alignment" db:"alignment"`
+	CreatedAt       time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// SectionAlignment maps a section index in the primary song to one in the secondary song.
+// An index of -1 means the section has no counterpart.
+type SectionAlignment struct {
+	Primary   int `json:"primary"`
+	Secondary int `json:"secondary"`
+}
+
+type UpdateSongPairRequest struct {
+	PairedSongID string             `json:"paired_song_id"`
+	Alignment    []SectionAlignment `json:"alignment,omitempty"` // auto-aligned when omitted
+}
+
+// PairedLine is one line of the primary song shown with its translation
+type PairedLine struct {
+	Primary   string `json:"primary"`
+	Secondary string `json:"secondary"`
+}
+
+// PairedSlide is a bilingual slide in the combined stream
+type PairedSlide struct {
+	Index          int          `json:"index"`
+	PrimaryLabel   string       `json:"primary_label,omitempty"`
+	SecondaryLabel string       `json:"secondary_label,omitempty"`
+	Lines          []PairedLine `json:"lines"`
+}
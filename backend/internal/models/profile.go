@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// DisplayLimits is how much text fits on a screen. Zero means no limit.
+type DisplayLimits struct {
+	MaxCharsPerLine  int `json:"max_chars_per_line"`
+	MaxLinesPerSlide int `json:"max_lines_per_slide"`
+}
+
+// DisplayProfile describes a screen lyrics are shown on, with limits that
+// can differ by language (Malayalam lines run wider than English ones)
+type DisplayProfile struct {
+	Name string `json:"name"`
+	DisplayLimits
+	Languages map[string]DisplayLimits `json:"languages"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// LimitsFor returns the profile's limits for lyrics in a language. A
+// language's own limits replace the profile's, one by one, where set.
+func (p *DisplayProfile) LimitsFor(language string) DisplayLimits {
+	limits := p.DisplayLimits
+	if own, ok := p.Languages[language]; ok {
+		if own.MaxCharsPerLine > 0 {
+			limits.MaxCharsPerLine = own.MaxCharsPerLine
+		}
+		if own.MaxLinesPerSlide > 0 {
+			limits.MaxLinesPerSlide = own.MaxLinesPerSlide
+		}
+	}
+	return limits
+}
+
+// DisplayProfileRequest creates or replaces a display profile
+type DisplayProfileRequest struct {
+	DisplayLimits
+	Languages map[string]DisplayLimits `json:"languages"`
+}
+
+// Kinds of display warning
+const (
+	WarningLineTooLong  = "line_too_long"
+	WarningTooManyLines = "too_many_lines" // a stanza that will be split across slides
+)
+
+// DisplayWarning points at lyrics that won't fit a display profile
+type DisplayWarning struct {
+	Profile string `json:"profile"`
+	Kind    string `json:"kind"`
+	Line    int    `json:"line,omitempty"` // numbered from 1 in the lyrics as saved
+	Section string `json:"section,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Length  int    `json:"length"`
+	Limit   int    `json:"limit"`
+	Message string `json:"message"`
+}
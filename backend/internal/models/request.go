@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Song request statuses. Accepted requests are planned into a setlist and
+// become sung once the song is triggered live.
+const (
+	SongRequestPending  = "pending"
+	SongRequestPlanned  = "planned"
+	SongRequestSung     = "sung"
+	SongRequestRejected = "rejected"
+)
+
+// SongRequest is a song asked for by someone in the congregation
+type SongRequest struct {
+	ID            int        `json:"id" db:"id"`
+	SongID        *string    `json:"song_id,omitempty" db:"song_id"`
+	Title         string     `json:"title" db:"title"`
+	Message       string     `json:"message,omitempty" db:"message"`
+	RequesterName string     `json:"requester_name,omitempty" db:"requester_name"`
+	Status        string     `json:"status" db:"status"`
+	SetlistID     *int       `json:"setlist_id,omitempty" db:"setlist_id"` // setlist the song was added to on accept
+	Votes         int        `json:"votes" db:"-"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	SungAt        *time.Time `json:"sung_at,omitempty" db:"sung_at"`
+}
+
+// SubmitSongRequest is what a congregant sends: a song from the public
+// catalog, or a free-text title
+type SubmitSongRequest struct {
+	SongID        string `json:"song_id,omitempty"`
+	Title         string `json:"title,omitempty"`
+	Message       string `json:"message,omitempty"`
+	RequesterName string `json:"requester_name,omitempty"`
+}
+
+// AcceptSongRequest adds a request's song to a setlist. SongID matches a
+// free-text request to a library song.
+type AcceptSongRequest struct {
+	SetlistID int    `json:"setlist_id"`
+	SongID    string `json:"song_id,omitempty"`
+}
+
+// ConvertSongRequest creates a draft song from a request for a song that
+// isn't in the library
+type ConvertSongRequest struct {
+	Title    string  `json:"title,omitempty"` // defaults to the requested title
+	Artist   *string `json:"artist,omitempty"`
+	Language string  `json:"language,omitempty"` // defaults to english
+	Library  string  `json:"library,omitempty"`  // defaults to "Requests"
+}
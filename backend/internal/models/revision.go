@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SongRevision is a saved version of a song's title, artist, language and
+// lyrics
+type SongRevision struct {
+	SongID              string    `json:"song_id"`
+	Revision            int       `json:"revision"`
+	Title               string    `json:"title"`
+	Artist              *string   `json:"artist,omitempty"`
+	Language            string    `json:"language"`
+	DisplayLyrics       string    `json:"display_lyrics"`
+	MusicMinistryLyrics string    `json:"music_ministry_lyrics"`
+	EditedBy            string    `json:"edited_by,omitempty"`
+	Note                string    `json:"note,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
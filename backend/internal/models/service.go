@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Service event types
+const (
+	ServiceEventTrigger = "trigger"
+	ServiceEventError   = "error"
+)
+
+type Service struct {
+	ID          int        `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name"`
+	ServiceDate string     `json:"service_date" db:"service_date"` // YYYY-MM-DD
+	Status      string     `json:"status" db:"status"`
+	StartedAt   time.Time  `json:"started_at" db:"started_at"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+}
+
+type CreateServiceRequest struct {
+	Name        string `json:"name"`
+	ServiceDate string `json:"service_date,omitempty"` // YYYY-MM-DD, defaults to today
+}
+
+// ServiceEvent is something that happened on screen during a service
+type ServiceEvent struct {
+	ID         int       `json:"id" db:"id"`
+	ServiceID  int       `json:"service_id" db:"service_id"`
+	EventType  string    `json:"event_type" db:"event_type"`
+	SongID     *string   `json:"song_id,omitempty" db:"song_id"`
+	Title      string    `json:"title,omitempty" db:"title"`
+	Operator   string    `json:"operator,omitempty" db:"operator"`
+	Message    string    `json:"message,omitempty" db:"message"`
+	OccurredAt time.Time `json:"occurred_at" db:"occurred_at"`
+}
+
+// ServiceReport summarises an archived service
+type ServiceReport struct {
+	ServiceID   int                 `json:"service_id"`
+	Name        string              `json:"name"`
+	ServiceDate string              `json:"service_date"`
+	StartedAt   time.Time           `json:"started_at"`
+	ArchivedAt  time.Time           `json:"archived_at"`
+	Songs       []ServiceReportSong `json:"songs"`
+	Operators   []string            `json:"operators"`
+	Errors      []ServiceEvent      `json:"errors"`
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+// ServiceReportSong is one song in the order it was first shown
+type ServiceReportSong struct {
+	Position    int         `json:"position"`
+	SongID      *string     `json:"song_id,omitempty"`
+	Title       string      `json:"title"`
+	TriggeredAt []time.Time `json:"triggered_at"`
+}
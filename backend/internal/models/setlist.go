@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type Setlist struct {
+	ID          int       `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	ServiceDate *string   `json:"service_date,omitempty" db:"service_date"` // YYYY-MM-DD
+	Songs       []Song    `json:"songs" db:"-"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type SetlistRequest struct {
+	Name        string   `json:"name"`
+	ServiceDate *string  `json:"service_date,omitempty"`
+	SongIDs     []string `json:"song_ids"`
+}
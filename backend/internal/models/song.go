@@ -3,37 +3,115 @@ package models
 import "time"
 
 type Song struct {
-	ID                  string    `json:"id" db:"id"`
-	Title               string    `json:"title" db:"title"`
-	FileName            *string   `json:"file_name,omitempty" db:"file_name"`
-	Library             string    `json:"library" db:"library"`
-	Language            string    `json:"language" db:"language"`
-	ProUUID             *string   `json:"pro_uuid,omitempty" db:"pro_uuid"`
-	DisplayLyrics       string    `json:"display_lyrics" db:"display_lyrics"`
-	MusicMinistryLyrics string    `json:"music_ministry_lyrics" db:"music_ministry_lyrics"`
-	Artist              *string   `json:"artist,omitempty" db:"artist"`
-	CreatedAt           time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+	ID                  string     `json:"id" db:"id"`
+	Title               string     `json:"title" db:"title"`
+	FileName            *string    `json:"file_name,omitempty" db:"file_name"`
+	Library             string     `json:"library" db:"library"`
+	Language            string     `json:"language" db:"language"`
+	ProUUID             *string    `json:"pro_uuid,omitempty" db:"pro_uuid"`
+	DisplayLyrics       string     `json:"display_lyrics" db:"display_lyrics"`
+	MusicMinistryLyrics string     `json:"music_ministry_lyrics" db:"music_ministry_lyrics"`
+	Artist              *string    `json:"artist,omitempty" db:"artist"`
+	OriginalKey         *string    `json:"original_key,omitempty" db:"original_key"`
+	PerformanceKey      *string    `json:"performance_key,omitempty" db:"performance_key"`
+	BPM                 *int       `json:"bpm,omitempty" db:"bpm"`
+	TimeSignature       *string    `json:"time_signature,omitempty" db:"time_signature"`
+	CountInBeats        *int       `json:"count_in_beats,omitempty" db:"count_in_beats"`
+	BackgroundMedia     *string    `json:"background_media,omitempty" db:"background_media"` // ProPresenter media item UUID or name
+	Look                *string    `json:"look,omitempty" db:"look"`                         // ProPresenter look UUID or name
+	Copyright           *string    `json:"copyright,omitempty" db:"copyright"`               // e.g. "2004 worshiptogether.com songs"
+	CCLINumber          *string    `json:"ccli_number,omitempty" db:"ccli_number"`           // CCLI song number
+	Chords              *string    `json:"chords,omitempty" db:"chords"`                     // chord chart in ChordPro
+	Public              bool       `json:"public" db:"public"`                               // approved for the public API
+	ArchivedAt          *time.Time `json:"archived_at,omitempty" db:"archived_at"`           // archived songs are left out of search
+	DeletedAt           *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`             // deleted songs are in the trash
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Numbers and Links are loaded separately (single-song responses, exports), not by listings
+	Numbers []SongNumber `json:"numbers,omitempty"`
+	Links   []SongLink   `json:"links,omitempty"`
+
+	// LanguageWarning is set on create when the lyrics look like another language
+	LanguageWarning string `json:"language_warning,omitempty"`
+	// Lock is someone's advisory edit lock, on single-song responses
+	Lock        *EditLock `json:"lock,omitempty"`
+	LockWarning string    `json:"lock_warning,omitempty"` // set when saved without holding the lock
+	// DisplayWarnings point at lyrics that won't fit a display profile, on save
+	DisplayWarnings []DisplayWarning `json:"display_warnings,omitempty"`
+}
+
+// SongNumber is a song's number in a songbook, e.g. Kristheeya Keerthanangal #123
+type SongNumber struct {
+	Songbook string `json:"songbook"`
+	Number   string `json:"number"`
+}
+
+// SongLink is an external reference (YouTube, Spotify, SongSelect or
+// MultiTracks). The metadata comes from the provider's oEmbed API where there
+// is one, so the planning UI can show cover art and a preview player.
+type SongLink struct {
+	Kind         string     `json:"kind"`
+	URL          string     `json:"url"`
+	Title        *string    `json:"title,omitempty"`
+	AuthorName   *string    `json:"author_name,omitempty"`
+	ThumbnailURL *string    `json:"thumbnail_url,omitempty"`
+	EmbedHTML    *string    `json:"embed_html,omitempty"`
+	FetchedAt    *time.Time `json:"fetched_at,omitempty"`
 }
 
 type CreateSongRequest struct {
-	Title               string  `json:"title"`
-	FileName            *string `json:"file_name,omitempty"`
-	Library             string  `json:"library"`
-	Language            string  `json:"language"`
-	ProUUID             *string `json:"pro_uuid,omitempty"`
-	DisplayLyrics       string  `json:"display_lyrics"`
-	MusicMinistryLyrics string  `json:"music_ministry_lyrics"`
-	Artist              *string `json:"artist,omitempty"`
+	Title               string       `json:"title"`
+	FileName            *string      `json:"file_name,omitempty"`
+	Library             string       `json:"library"`
+	Language            string       `json:"language"`
+	ProUUID             *string      `json:"pro_uuid,omitempty"`
+	DisplayLyrics       string       `json:"display_lyrics"`
+	MusicMinistryLyrics string       `json:"music_ministry_lyrics"`
+	Artist              *string      `json:"artist,omitempty"`
+	OriginalKey         *string      `json:"original_key,omitempty"`
+	PerformanceKey      *string      `json:"performance_key,omitempty"`
+	BPM                 *int         `json:"bpm,omitempty"`
+	TimeSignature       *string      `json:"time_signature,omitempty"`
+	CountInBeats        *int         `json:"count_in_beats,omitempty"`
+	BackgroundMedia     *string      `json:"background_media,omitempty"`
+	Look                *string      `json:"look,omitempty"`
+	Copyright           *string      `json:"copyright,omitempty"`
+	CCLINumber          *string      `json:"ccli_number,omitempty"`
+	Chords              *string      `json:"chords,omitempty"` // ChordPro
+	Public              bool         `json:"public,omitempty"`
+	Numbers             []SongNumber `json:"numbers,omitempty"`
+	Links               []SongLink   `json:"links,omitempty"` // kind and url; metadata is fetched
 }
 
 type UpdateSongRequest struct {
-	Title               *string `json:"title,omitempty"`
-	Library             *string `json:"library,omitempty"`
-	Language            *string `json:"language,omitempty"`
-	DisplayLyrics       *string `json:"display_lyrics,omitempty"`
-	MusicMinistryLyrics *string `json:"music_ministry_lyrics,omitempty"`
-	Artist              *string `json:"artist,omitempty"`
+	Title               *string       `json:"title,omitempty"`
+	Library             *string       `json:"library,omitempty"`
+	Language            *string       `json:"language,omitempty"`
+	DisplayLyrics       *string       `json:"display_lyrics,omitempty"`
+	MusicMinistryLyrics *string       `json:"music_ministry_lyrics,omitempty"`
+	Artist              *string       `json:"artist,omitempty"`
+	OriginalKey         *string       `json:"original_key,omitempty"`
+	PerformanceKey      *string       `json:"performance_key,omitempty"`
+	BPM                 *int          `json:"bpm,omitempty"`
+	TimeSignature       *string       `json:"time_signature,omitempty"`
+	CountInBeats        *int          `json:"count_in_beats,omitempty"`
+	BackgroundMedia     *string       `json:"background_media,omitempty"` // empty string clears
+	Look                *string       `json:"look,omitempty"`             // empty string clears
+	Copyright           *string       `json:"copyright,omitempty"`        // empty string clears
+	CCLINumber          *string       `json:"ccli_number,omitempty"`      // empty string clears
+	Chords              *string       `json:"chords,omitempty"`           // ChordPro; empty string clears
+	Public              *bool         `json:"public,omitempty"`           // approves the song for the public API
+	Numbers             *[]SongNumber `json:"numbers,omitempty"`          // replaces all references when set
+	Links               *[]SongLink   `json:"links,omitempty"`            // replaces all links when set
+
+	// Clear names nullable fields to set to null, from explicit nulls in a
+	// merge patch
+	Clear []string `json:"-"`
+
+	// EditedBy and RevisionNote are recorded in the song's revision history
+	EditedBy     string `json:"-"`
+	RevisionNote string `json:"-"`
 }
 
 type SearchRequest struct {
@@ -42,22 +120,34 @@ type SearchRequest struct {
 }
 
 type Settings struct {
-	ID                       int       `json:"id" db:"id"`
-	LaptopBIP                string    `json:"laptop_b_ip" db:"laptop_b_ip"`
-	LaptopBPort              int       `json:"laptop_b_port" db:"laptop_b_port"`
-	LivePlaylistUUID         string    `json:"live_playlist_uuid" db:"live_playlist_uuid"`
-	ProPresenterHost         string    `json:"propresenter_host" db:"propresenter_host"`
-	ProPresenterPort         int       `json:"propresenter_port" db:"propresenter_port"`
-	ProPresenterPlaylist     string    `json:"propresenter_playlist" db:"propresenter_playlist"`
-	ProPresenterPlaylistUUID string    `json:"propresenter_playlist_uuid" db:"propresenter_playlist_uuid"`
-	UpdatedAt                time.Time `json:"updated_at" db:"updated_at"`
+	ID                         int               `json:"id" db:"id"`
+	LaptopBIP                  string            `json:"laptop_b_ip" db:"laptop_b_ip"`
+	LaptopBPort                int               `json:"laptop_b_port" db:"laptop_b_port"`
+	LivePlaylistUUID           string            `json:"live_playlist_uuid" db:"live_playlist_uuid"`
+	ProPresenterHost           string            `json:"propresenter_host" db:"propresenter_host"`
+	ProPresenterPort           int               `json:"propresenter_port" db:"propresenter_port"`
+	ProPresenterPlaylist       string            `json:"propresenter_playlist" db:"propresenter_playlist"`
+	ProPresenterPlaylistUUID   string            `json:"propresenter_playlist_uuid" db:"propresenter_playlist_uuid"`
+	RehearsalPlaylist          string            `json:"rehearsal_playlist" db:"rehearsal_playlist"`
+	CCLILicense                string            `json:"ccli_license" db:"ccli_license"`
+	CopyrightSlide             bool              `json:"copyright_slide" db:"copyright_slide"`                           // append attribution slides unless a request says otherwise
+	SearchFieldLocales         map[string]string `json:"search_field_locales" db:"search_field_locales"`                 // Typesense locale per text field, e.g. {"lyrics": "ml"}
+	SearchTokenSeparators      string            `json:"search_token_separators" db:"search_token_separators"`           // characters that split words besides spaces
+	ProPresenterHealthInterval int               `json:"propresenter_health_interval" db:"propresenter_health_interval"` // seconds between connection checks
+	UpdatedAt                  time.Time         `json:"updated_at" db:"updated_at"`
 }
 
 type UpdateSettingsRequest struct {
-	ProPresenterHost         *string `json:"propresenter_host,omitempty"`
-	ProPresenterPort         *int    `json:"propresenter_port,omitempty"`
-	ProPresenterPlaylist     *string `json:"propresenter_playlist,omitempty"`
-	ProPresenterPlaylistUUID *string `json:"propresenter_playlist_uuid,omitempty"`
+	ProPresenterHost           *string            `json:"propresenter_host,omitempty"`
+	ProPresenterPort           *int               `json:"propresenter_port,omitempty"`
+	ProPresenterPlaylist       *string            `json:"propresenter_playlist,omitempty"`
+	ProPresenterPlaylistUUID   *string            `json:"propresenter_playlist_uuid,omitempty"`
+	RehearsalPlaylist          *string            `json:"rehearsal_playlist,omitempty"`
+	CCLILicense                *string            `json:"ccli_license,omitempty"`
+	CopyrightSlide             *bool              `json:"copyright_slide,omitempty"`
+	SearchFieldLocales         *map[string]string `json:"search_field_locales,omitempty"`
+	SearchTokenSeparators      *string            `json:"search_token_separators,omitempty"`
+	ProPresenterHealthInterval *int               `json:"propresenter_health_interval,omitempty"`
 }
 
 // Queue Models
@@ -66,6 +156,7 @@ type QueueItem struct {
 	SongID    string    `json:"song_id" db:"song_id"`
 	Position  int       `json:"position" db:"position"`
 	Song      *Song     `json:"song,omitempty" db:"-"`
+	Cues      *SongCues `json:"cues,omitempty" db:"-"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -82,3 +173,12 @@ type QueueItemPosition struct {
 	ID       int `json:"id"`
 	Position int `json:"position"`
 }
+
+// Tempo is the click metadata for a song, broadcast when it goes live
+type Tempo struct {
+	SongID        string  `json:"song_id"`
+	Title         string  `json:"title"`
+	BPM           *int    `json:"bpm"`
+	TimeSignature *string `json:"time_signature"`
+	CountInBeats  *int    `json:"count_in_beats"`
+}
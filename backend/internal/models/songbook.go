@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Songbook is a hymnal or other numbered collection of songs
+type Songbook struct {
+	ID           int       `json:"id" db:"id"`
+	Name         string    `json:"name" db:"name"`
+	Abbreviation string    `json:"abbreviation" db:"abbreviation"`
+	Description  string    `json:"description" db:"description"`
+	SongCount    int       `json:"song_count" db:"-"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type SongbookRequest struct {
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	Description  string `json:"description"`
+}
+
+// SongbookEntry is one numbered song in a songbook listing
+type SongbookEntry struct {
+	Number   string `json:"number"`
+	SongID   string `json:"song_id"`
+	Title    string `json:"title"`
+	Language string `json:"language"`
+}
+
+// SongbookPage is a single entry with its neighbours, for paging through a
+// songbook in order
+type SongbookPage struct {
+	Songbook Songbook `json:"songbook"`
+	Number   string   `json:"number"`
+	Song     Song     `json:"song"`
+	Previous *string  `json:"previous,omitempty"`
+	Next     *string  `json:"next,omitempty"`
+}
+
+type SongbookEntryRequest struct {
+	SongID string `json:"song_id"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Admin dashboard models
+
+type LibraryStats struct {
+	Songs      int             `json:"songs"`
+	Active     int             `json:"active"` // not archived; what the search index should hold
+	Archived   int             `json:"archived"`
+	Public     int             `json:"public"`
+	Trash      int             `json:"trash"` // songs in the trash, not counted in the others
+	ByLanguage []LanguageCount `json:"by_language"`
+	LastEditAt *time.Time      `json:"last_edit_at,omitempty"` // newest update of an active song
+}
+
+type LanguageCount struct {
+	Language string `json:"language"`
+	Songs    int    `json:"songs"`
+}
+
+type EditActivity struct {
+	EditedLastDay   int          `json:"edited_last_day"`
+	EditedLastWeek  int          `json:"edited_last_week"`
+	EditedLastMonth int          `json:"edited_last_month"`
+	CreatedLastWeek int          `json:"created_last_week"`
+	Recent          []RecentEdit `json:"recent"`
+}
+
+type RecentEdit struct {
+	SongID    string    `json:"song_id"`
+	Title     string    `json:"title"`
+	Language  string    `json:"language"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
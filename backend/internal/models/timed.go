@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// TimedLine is one lyric line with when it starts, in milliseconds from the
+// start of the recording
+type TimedLine struct {
+	TimeMs int    `json:"time_ms"`
+	Text   string `json:"text"`
+}
+
+// TimedLyrics are a song's synced lyrics, usually imported from an LRC file
+type TimedLyrics struct {
+	SongID    string      `json:"song_id"`
+	Lines     []TimedLine `json:"lines"`
+	OffsetMs  int         `json:"offset_ms"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// SongTiming holds how long each of a song's slides stays up during
+// auto-advance. SlideSeconds is indexed by slide; a zero or missing entry
+// uses DefaultSeconds.
+type SongTiming struct {
+	SongID         string    `json:"song_id"`
+	DefaultSeconds int       `json:"default_seconds"`
+	SlideSeconds   []int     `json:"slide_seconds"`
+	Loop           bool      `json:"loop"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type SongTimingRequest struct {
+	DefaultSeconds int   `json:"default_seconds"`
+	SlideSeconds   []int `json:"slide_seconds"`
+	Loop           bool  `json:"loop"`
+}
+
+// Seconds returns how long slide index stays up
+func (t *SongTiming) Seconds(index int) int {
+	if index < len(t.SlideSeconds) && t.SlideSeconds[index] > 0 {
+		return t.SlideSeconds[index]
+	}
+	return t.DefaultSeconds
+}
@@ -0,0 +1,124 @@
+package models
+
+import "time"
+
+// User roles
+const (
+	RoleAdmin    = "admin"    // manages users, settings and backups
+	RoleEditor   = "editor"   // changes songs and setlists
+	RoleOperator = "operator" // runs the live display and ProPresenter
+	RoleViewer   = "viewer"   // reads only
+)
+
+// Roles lists every role, most privileged first
+var Roles = []string{RoleAdmin, RoleEditor, RoleOperator, RoleViewer}
+
+// ValidRole reports whether role is one of Roles
+func ValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RolesAtLeast lists role and the roles more privileged than it, for
+// routes any of them may use
+func RolesAtLeast(role string) []string {
+	for i, r := range Roles {
+		if r == role {
+			return Roles[:i+1]
+		}
+	}
+	return nil
+}
+
+// RoleCovers reports whether role can do everything other can
+func RoleCovers(role, other string) bool {
+	for _, r := range RolesAtLeast(other) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// User is an account that can sign in
+type User struct {
+	ID                int        `json:"id"`
+	Username          string     `json:"username"`
+	DisplayName       string     `json:"display_name"`
+	Email             string     `json:"email,omitempty"`
+	Role              string     `json:"role"`
+	Disabled          bool       `json:"disabled"`
+	MustResetPassword bool       `json:"must_reset_password"`
+	HasPassword       bool       `json:"has_password"`
+	SSO               bool       `json:"sso"` // linked to a single sign-on identity
+	LastLoginAt       *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	PasswordHash string `json:"-"`
+	OIDCSubject  string `json:"-"`
+}
+
+// Name is how the user is shown, e.g. in edit history
+func (u *User) Name() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Username
+}
+
+// CreateUserRequest creates an account. Without a password a temporary one
+// is generated; either way the user chooses their own at first sign-in.
+type CreateUserRequest struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	Password    string `json:"password"`
+}
+
+// UpdateUserRequest changes an account; nil fields are left as they are
+type UpdateUserRequest struct {
+	DisplayName *string `json:"display_name"`
+	Email       *string `json:"email"`
+	Role        *string `json:"role"`
+	Disabled    *bool   `json:"disabled"`
+}
+
+// UserSession is a signed-in browser or device
+type UserSession struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	Username   string    `json:"username"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// APIKey lets a script or device call the API as its user, with a role no
+// higher than theirs
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Username   string     `json:"username"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	Prefix     string     `json:"prefix"` // the start of the key, to recognise it by
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateAPIKeyRequest creates an API key. Role defaults to the user's own;
+// without ExpiresInDays the key doesn't expire.
+type CreateAPIKeyRequest struct {
+	Name          string `json:"name"`
+	Role          string `json:"role"`
+	ExpiresInDays int    `json:"expires_in_days"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Lyric variants a song can have
+const (
+	VariantRomanized = "romanized"
+)
+
+// LyricVariant is an alternative rendering of a song's display lyrics
+type LyricVariant struct {
+	SongID    string    `json:"song_id"`
+	Variant   string    `json:"variant"`
+	Lyrics    string    `json:"lyrics"`
+	Generated bool      `json:"generated"` // false once edited by hand
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LyricVariantRequest replaces a variant's lyrics by hand
+type LyricVariantRequest struct {
+	Lyrics string `json:"lyrics"`
+}
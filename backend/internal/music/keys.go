@@ -0,0 +1,101 @@
+// Package music handles musical keys for the music ministry view:
+// parsing key names, transposition distance and capo suggestions.
+package music
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Key is a parsed musical key
+type Key struct {
+	Root  int // semitones above C, 0-11
+	Minor bool
+}
+
+var keyPattern = regexp.MustCompile(`^([A-Ga-g])([#♯b♭]?)\s*(m|min|minor|maj|major)?$`)
+
+var naturals = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+
+// Preferred spellings, following the keys bands actually read charts in
+var majorNames = [12]string{"C", "Db", "D", "Eb", "E", "F", "F#", "G", "Ab", "A", "Bb", "B"}
+var minorNames = [12]string{"Cm", "C#m", "Dm", "Ebm", "Em", "Fm", "F#m", "Gm", "G#m", "Am", "Bbm", "Bm"}
+
+// Open chord shapes guitarists are comfortable playing with a capo
+var majorShapes = []int{0, 2, 4, 7, 9} // C, D, E, G, A
+var minorShapes = []int{2, 4, 9}       // Dm, Em, Am
+
+// Capos above the 7th fret sound thin, so higher options are not suggested
+const maxCapo = 7
+
+// ParseKey parses names like "G", "Bb", "F#m", "c minor"
+func ParseKey(name string) (Key, error) {
+	m := keyPattern.FindStringSubmatch(strings.TrimSpace(name))
+	if m == nil {
+		return Key{}, fmt.Errorf("invalid key %q", name)
+	}
+
+	root := naturals[strings.ToUpper(m[1])[0]]
+	switch m[2] {
+	case "#", "♯":
+		root++
+	case "b", "♭":
+		root--
+	}
+
+	quality := strings.ToLower(m[3])
+	return Key{
+		Root:  (root + 12) % 12,
+		Minor: quality == "m" || quality == "min" || quality == "minor",
+	}, nil
+}
+
+// String returns the preferred spelling of the key
+func (k Key) String() string {
+	if k.Minor {
+		return minorNames[k.Root]
+	}
+	return majorNames[k.Root]
+}
+
+// Transpose returns the key moved by the given number of semitones
+func (k Key) Transpose(semitones int) Key {
+	return Key{Root: ((k.Root+semitones)%12 + 12) % 12, Minor: k.Minor}
+}
+
+// Interval returns the smallest semitone move from one key to another (-5..+6)
+func Interval(from, to Key) int {
+	diff := ((to.Root-from.Root)%12 + 12) % 12
+	if diff > 6 {
+		diff -= 12
+	}
+	return diff
+}
+
+// CapoOption is a way to play a key with open chord shapes
+type CapoOption struct {
+	Capo  int    `json:"capo"`
+	Shape string `json:"shape"`
+}
+
+// CapoOptions lists open shapes that sound in the given key, lowest capo first
+func CapoOptions(k Key) []CapoOption {
+	shapes := majorShapes
+	if k.Minor {
+		shapes = minorShapes
+	}
+
+	options := make([]CapoOption, 0, len(shapes))
+	for _, shape := range shapes {
+		capo := ((k.Root-shape)%12 + 12) % 12
+		if capo > maxCapo {
+			continue
+		}
+		options = append(options, CapoOption{Capo: capo, Shape: Key{Root: shape, Minor: k.Minor}.String()})
+	}
+
+	sort.Slice(options, func(i, j int) bool { return options[i].Capo < options[j].Capo })
+	return options
+}
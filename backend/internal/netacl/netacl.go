@@ -0,0 +1,117 @@
+// Package netacl restricts route groups to client networks, so admin and
+// ProPresenter control can be limited to the booth while guest Wi-Fi only
+// reaches what it needs.
+package netacl
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Rule allows requests under Prefix only from Nets
+type Rule struct {
+	Prefix string
+	Nets   []*net.IPNet
+}
+
+// Parse reads rules written as "prefix=cidr,cidr;prefix=cidr", e.g.
+// "/api/admin=10.0.10.0/24;/api/propresenter=10.0.10.0/24,127.0.0.1". Bare
+// addresses are single hosts. An empty spec means no rules.
+func Parse(spec string) ([]Rule, error) {
+	var rules []Rule
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		prefix, list, ok := strings.Cut(part, "=")
+		prefix = strings.ToLower(strings.TrimSpace(prefix))
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("rule %q must look like /path=cidr,cidr", part)
+		}
+		prefix = strings.TrimRight(prefix, "/") // "/" covers everything
+
+		rule := Rule{Prefix: prefix}
+		for _, entry := range strings.Split(list, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			if !strings.Contains(entry, "/") {
+				ip := net.ParseIP(entry)
+				if ip == nil {
+					return nil, fmt.Errorf("rule %q: %q is not an IP address or CIDR", part, entry)
+				}
+				if ip.To4() != nil {
+					entry += "/32"
+				} else {
+					entry += "/128"
+				}
+			}
+			_, ipnet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %q is not an IP address or CIDR", part, entry)
+			}
+			rule.Nets = append(rule.Nets, ipnet)
+		}
+		if len(rule.Nets) == 0 {
+			return nil, fmt.Errorf("rule %q lists no networks", part)
+		}
+		rules = append(rules, rule)
+	}
+
+	// Longest prefix first, so /api/admin/backups can be tighter than /api/admin
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].Prefix) > len(rules[j].Prefix) })
+	return rules, nil
+}
+
+// match returns the most specific rule covering path, or nil
+func match(rules []Rule, path string) *Rule {
+	path = strings.ToLower(path) // routing is case-insensitive
+	for i := range rules {
+		p := rules[i].Prefix
+		if p == "" || path == p || strings.HasPrefix(path, p+"/") {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// Allowed reports whether ip may reach path under rules
+func Allowed(rules []Rule, path string, ip net.IP) bool {
+	rule := match(rules, path)
+	if rule == nil {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, n := range rule.Nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware rejects requests from outside the networks of the rule matching
+// their path with 403. It uses the connection's address, not forwarded
+// headers, so it can't be spoofed by clients.
+func Middleware(rules []Rule) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(rules) == 0 {
+			return c.Next()
+		}
+		ip := net.ParseIP(c.IP())
+		if !Allowed(rules, c.Path(), ip) {
+			log.Printf("⛔ Blocked %s %s from %s (network ACL)", c.Method(), c.Path(), c.IP())
+			return c.Status(403).JSON(fiber.Map{"error": "Access from this network is not allowed"})
+		}
+		return c.Next()
+	}
+}
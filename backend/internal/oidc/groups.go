@@ -0,0 +1,115 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/googleauth"
+)
+
+const directoryGroupsURL = "https://admin.googleapis.com/admin/directory/v1/groups"
+
+// GoogleGroups looks up a user's Google Workspace groups, which Google's ID
+// tokens don't include. It uses the Admin SDK Directory API with a service
+// account that has domain-wide delegation for the
+// admin.directory.group.readonly scope, acting as a Workspace admin.
+type GoogleGroups struct {
+	token      *googleauth.TokenSource
+	httpClient *http.Client
+}
+
+// NewGoogleGroups reads the service account key file; adminEmail is the
+// Workspace admin the service account acts as
+func NewGoogleGroups(credentialsFile, adminEmail string) (*GoogleGroups, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading google groups credentials: %w", err)
+	}
+	if adminEmail == "" {
+		return nil, fmt.Errorf("a workspace admin email is required to look up groups")
+	}
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	token, err := googleauth.NewTokenSource(data, []string{"https://www.googleapis.com/auth/admin.directory.group.readonly"}, adminEmail, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &GoogleGroups{token: token, httpClient: httpClient}, nil
+}
+
+// Groups returns the email addresses of the groups a user belongs to
+func (g *GoogleGroups) Groups(ctx context.Context, email string) ([]string, error) {
+	token, err := g.token.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0)
+	pageToken := ""
+	for {
+		query := url.Values{"userKey": {email}, "maxResults": {"200"}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, directoryGroupsURL+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := g.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up google groups: %w", err)
+		}
+
+		var page struct {
+			Groups []struct {
+				Email string `json:"email"`
+			} `json:"groups"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("error looking up google groups, status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding google groups: %w", err)
+		}
+
+		for _, group := range page.Groups {
+			groups = append(groups, group.Email)
+		}
+		if page.NextPageToken == "" {
+			return groups, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// ParseGroupRoles reads a group to role mapping written as
+// "group=role,group=role", e.g. "worship-admins@church.org=admin". Group
+// names are matched without regard to case.
+func ParseGroupRoles(spec string) (map[string]string, error) {
+	roles := make(map[string]string)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		group, role, ok := strings.Cut(part, "=")
+		group, role = strings.ToLower(strings.TrimSpace(group)), strings.TrimSpace(role)
+		if !ok || group == "" || role == "" {
+			return nil, fmt.Errorf("%q must look like group=role", part)
+		}
+		roles[group] = role
+	}
+	return roles, nil
+}
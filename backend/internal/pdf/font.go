@@ -0,0 +1,365 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// Font is a TrueType font embedded in the document for text outside Latin-1
+// (Malayalam, Hindi, Tamil, ...). The whole font file is embedded, and text is
+// written as glyph IDs with a ToUnicode map so it stays searchable and copyable.
+type Font struct {
+	name       string
+	data       []byte
+	unitsPerEm uint16
+	ascent     int16
+	descent    int16
+	bbox       [4]int16
+	glyphs     map[rune]uint16
+	advances   []uint16
+}
+
+var psNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9\-]+`)
+
+// LoadFont reads a TrueType (.ttf) font file
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading font: %w", err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return ParseFont(name, data)
+}
+
+// ParseFont parses TrueType font data. The PostScript name stored in the font
+// is used when present, otherwise the given fallback name.
+func ParseFont(fallbackName string, data []byte) (*Font, error) {
+	if len(data) < 12 {
+		return nil, errors.New("font file is too short")
+	}
+	switch string(data[:4]) {
+	case "\x00\x01\x00\x00", "true":
+	case "OTTO":
+		return nil, errors.New("CFF-based OpenType fonts are not supported, use a TrueType (.ttf) font")
+	case "ttcf":
+		return nil, errors.New("font collections (.ttc) are not supported")
+	default:
+		return nil, errors.New("not a TrueType font")
+	}
+
+	tables := make(map[string][]byte)
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := 12 + i*16
+		if rec+16 > len(data) {
+			return nil, errors.New("truncated table directory")
+		}
+		offset := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("table %q is out of range", data[rec:rec+4])
+		}
+		tables[string(data[rec:rec+4])] = data[offset : offset+length]
+	}
+	for _, required := range []string{"head", "hhea", "hmtx", "maxp", "cmap"} {
+		if _, ok := tables[required]; !ok {
+			return nil, fmt.Errorf("font has no %s table", required)
+		}
+	}
+
+	f := &Font{data: data}
+
+	head := tables["head"]
+	if len(head) < 54 {
+		return nil, errors.New("invalid head table")
+	}
+	f.unitsPerEm = binary.BigEndian.Uint16(head[18:])
+	if f.unitsPerEm == 0 {
+		return nil, errors.New("invalid unitsPerEm")
+	}
+	for i := range f.bbox {
+		f.bbox[i] = int16(binary.BigEndian.Uint16(head[36+i*2:]))
+	}
+
+	hhea := tables["hhea"]
+	if len(hhea) < 36 {
+		return nil, errors.New("invalid hhea table")
+	}
+	f.ascent = int16(binary.BigEndian.Uint16(hhea[4:]))
+	f.descent = int16(binary.BigEndian.Uint16(hhea[6:]))
+	numHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+
+	maxp := tables["maxp"]
+	if len(maxp) < 6 {
+		return nil, errors.New("invalid maxp table")
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+
+	hmtx := tables["hmtx"]
+	if numHMetrics == 0 || len(hmtx) < numHMetrics*4 {
+		return nil, errors.New("invalid hmtx table")
+	}
+	f.advances = make([]uint16, numGlyphs)
+	for gid := range f.advances {
+		metric := gid
+		if metric >= numHMetrics {
+			metric = numHMetrics - 1
+		}
+		f.advances[gid] = binary.BigEndian.Uint16(hmtx[metric*4:])
+	}
+
+	glyphs, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	f.glyphs = glyphs
+
+	f.name = psNameUnsafe.ReplaceAllString(postScriptName(tables["name"]), "")
+	if f.name == "" {
+		f.name = psNameUnsafe.ReplaceAllString(fallbackName, "")
+	}
+	if f.name == "" {
+		f.name = "EmbeddedFont"
+	}
+
+	return f, nil
+}
+
+// Name returns the font's PostScript name
+func (f *Font) Name() string {
+	return f.name
+}
+
+// Has reports whether the font has a glyph for r
+func (f *Font) Has(r rune) bool {
+	_, ok := f.glyphs[r]
+	return ok
+}
+
+// width returns a glyph's advance in PDF text space units (1/1000 em)
+func (f *Font) width(gid uint16) int {
+	if int(gid) >= len(f.advances) {
+		return 0
+	}
+	return int(f.advances[gid]) * 1000 / int(f.unitsPerEm)
+}
+
+func (f *Font) scale(v int16) int {
+	return int(v) * 1000 / int(f.unitsPerEm)
+}
+
+// parseCmap reads the Unicode character map, preferring the full-repertoire
+// format 12 subtable over the BMP-only format 4
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, errors.New("invalid cmap table")
+	}
+
+	best, bestRank := -1, 0
+	count := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < count; i++ {
+		rec := 4 + i*8
+		if rec+8 > len(cmap) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(cmap[rec:])
+		encoding := binary.BigEndian.Uint16(cmap[rec+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[rec+4:]))
+		if offset+2 > len(cmap) {
+			continue
+		}
+		format := binary.BigEndian.Uint16(cmap[offset:])
+
+		rank := 0
+		switch {
+		case format == 12 && (platform == 0 || (platform == 3 && encoding == 10)):
+			rank = 3
+		case format == 4 && (platform == 0 || (platform == 3 && encoding == 1)):
+			rank = 2
+		}
+		if rank > bestRank {
+			best, bestRank = offset, rank
+		}
+	}
+	if best < 0 {
+		return nil, errors.New("font has no Unicode character map")
+	}
+
+	glyphs := make(map[rune]uint16)
+	sub := cmap[best:]
+	switch binary.BigEndian.Uint16(sub) {
+	case 4:
+		if len(sub) < 14 {
+			return nil, errors.New("invalid cmap format 4")
+		}
+		segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+		ends := 14
+		starts := ends + segCount*2 + 2
+		deltas := starts + segCount*2
+		rangeOffsets := deltas + segCount*2
+		if rangeOffsets+segCount*2 > len(sub) {
+			return nil, errors.New("truncated cmap format 4")
+		}
+		for s := 0; s < segCount; s++ {
+			end := int(binary.BigEndian.Uint16(sub[ends+s*2:]))
+			start := int(binary.BigEndian.Uint16(sub[starts+s*2:]))
+			delta := int(binary.BigEndian.Uint16(sub[deltas+s*2:]))
+			rangeOffset := int(binary.BigEndian.Uint16(sub[rangeOffsets+s*2:]))
+			for c := start; c <= end && c != 0xFFFF; c++ {
+				var gid int
+				if rangeOffset == 0 {
+					gid = (c + delta) & 0xFFFF
+				} else {
+					at := rangeOffsets + s*2 + rangeOffset + (c-start)*2
+					if at+2 > len(sub) {
+						continue
+					}
+					gid = int(binary.BigEndian.Uint16(sub[at:]))
+					if gid != 0 {
+						gid = (gid + delta) & 0xFFFF
+					}
+				}
+				if gid != 0 {
+					glyphs[rune(c)] = uint16(gid)
+				}
+			}
+		}
+	case 12:
+		if len(sub) < 16 {
+			return nil, errors.New("invalid cmap format 12")
+		}
+		groups := int(binary.BigEndian.Uint32(sub[12:]))
+		if 16+groups*12 > len(sub) {
+			return nil, errors.New("truncated cmap format 12")
+		}
+		for g := 0; g < groups; g++ {
+			rec := sub[16+g*12:]
+			start := binary.BigEndian.Uint32(rec)
+			end := binary.BigEndian.Uint32(rec[4:])
+			gid := binary.BigEndian.Uint32(rec[8:])
+			for c := start; c <= end && c <= 0x10FFFF; c++ {
+				glyphs[rune(c)] = uint16(gid + c - start)
+			}
+		}
+	}
+
+	return glyphs, nil
+}
+
+// postScriptName reads name ID 6 from the name table, if any
+func postScriptName(table []byte) string {
+	if len(table) < 6 {
+		return ""
+	}
+	count := int(binary.BigEndian.Uint16(table[2:]))
+	storage := int(binary.BigEndian.Uint16(table[4:]))
+	for i := 0; i < count; i++ {
+		rec := 6 + i*12
+		if rec+12 > len(table) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(table[rec:])
+		nameID := binary.BigEndian.Uint16(table[rec+6:])
+		length := int(binary.BigEndian.Uint16(table[rec+8:]))
+		offset := storage + int(binary.BigEndian.Uint16(table[rec+10:]))
+		if nameID != 6 || offset+length > len(table) {
+			continue
+		}
+		raw := table[offset : offset+length]
+		switch platform {
+		case 0, 3:
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(raw[j*2:])
+			}
+			return string(utf16.Decode(units))
+		case 1:
+			return string(raw)
+		}
+	}
+	return ""
+}
+
+// encode converts text into glyph IDs, recording which glyphs are used
+func (f *Font) encode(text string, used map[uint16]rune) string {
+	var b strings.Builder
+	for _, r := range shape(text) {
+		if r == '\t' {
+			r = ' '
+		}
+		gid := f.glyphs[r]
+		if _, seen := used[gid]; !seen && gid != 0 {
+			used[gid] = r
+		}
+		fmt.Fprintf(&b, "%04X", gid)
+	}
+	return b.String()
+}
+
+// objects returns the PDF objects for the font: the Type0 font (first, at
+// number base), its descendant CIDFont, descriptor, font file and ToUnicode map
+func (f *Font) objects(base int, used map[uint16]rune) []string {
+	gids := make([]int, 0, len(used))
+	for gid := range used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	var widths strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", gid, f.width(uint16(gid)))
+	}
+
+	var fontFile bytes.Buffer
+	zw := zlib.NewWriter(&fontFile)
+	zw.Write(f.data)
+	zw.Close()
+
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	cmap.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	cmap.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	cmap.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(gids); start += 100 {
+		end := start + 100
+		if end > len(gids) {
+			end = len(gids)
+		}
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for _, gid := range gids[start:end] {
+			fmt.Fprintf(&cmap, "<%04X> <%s>\n", gid, utf16Hex(used[uint16(gid)]))
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+
+	return []string{
+		fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			f.name, base+1, base+4),
+		fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW %d /W [%s] >>",
+			f.name, base+2, f.width(0), widths.String()),
+		fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			f.name, f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+			f.scale(f.ascent), f.scale(f.descent), f.scale(f.ascent), base+3),
+		fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream", fontFile.Len(), len(f.data), fontFile.String()),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", cmap.Len(), cmap.String()),
+	}
+}
+
+func utf16Hex(r rune) string {
+	var b strings.Builder
+	for _, unit := range utf16.Encode([]rune{r}) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	return b.String()
+}
@@ -0,0 +1,276 @@
+// Package pdf writes simple text-only PDF documents (headings, paragraphs and
+// blank lines on A4 pages) without any external dependencies.
+//
+// Text uses the standard Helvetica fonts with WinAnsi encoding. Lines with
+// characters outside Latin-1 use the TrueType fonts set with SetFonts when
+// there are any, otherwise those characters are replaced with '?'.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	pageWidth    = 595.0 // A4 in points
+	pageHeight   = 842.0
+	margin       = 56.0
+	bodySize     = 11.0
+	headingSize  = 15.0
+	titleSize    = 20.0
+	lineSpacing  = 1.35
+	wrapAtChars  = 95
+	fontRegular  = "F1"
+	fontBold     = "F2"
+	fontMono     = "F3"
+	bottomMargin = margin
+)
+
+type line struct {
+	font string
+	face *Font // embedded font, used instead of font when set
+	size float64
+	text string
+	y    float64
+}
+
+// Document is a PDF being built page by page
+type Document struct {
+	pages   [][]line
+	y       float64
+	regular *Font
+	bold    *Font
+}
+
+// New starts a document whose first page shows the given title
+func New(title string) *Document {
+	d := &Document{}
+	d.newPage()
+	d.Title(title)
+	return d
+}
+
+// SetFonts sets the TrueType fonts used from now on for lines that need
+// characters outside Latin-1. A nil bold font falls back to the regular one,
+// and a nil regular font goes back to Helvetica.
+func (d *Document) SetFonts(regular, bold *Font) {
+	if bold == nil {
+		bold = regular
+	}
+	d.regular, d.bold = regular, bold
+}
+
+// Title adds a large bold title followed by a blank line
+func (d *Document) Title(text string) {
+	if text == "" {
+		return
+	}
+	d.add(fontBold, titleSize, text)
+	d.Blank()
+}
+
+// Heading adds a bold section heading
+func (d *Document) Heading(text string) {
+	d.add(fontBold, headingSize, text)
+}
+
+// Strong adds a bold line at body size
+func (d *Document) Strong(text string) {
+	d.add(fontBold, bodySize, text)
+}
+
+// Mono adds a line in bold Courier, for text that must keep its column
+// alignment such as chord lines
+func (d *Document) Mono(text string) {
+	d.add(fontMono, bodySize, text)
+}
+
+// Text adds a paragraph, wrapping long lines and honouring embedded newlines
+func (d *Document) Text(text string) {
+	for _, para := range strings.Split(text, "\n") {
+		for _, l := range wrap(para, wrapAtChars) {
+			d.add(fontRegular, bodySize, l)
+		}
+	}
+}
+
+// Blank adds an empty line
+func (d *Document) Blank() {
+	d.advance(bodySize)
+}
+
+// PageBreak starts a new page
+func (d *Document) PageBreak() {
+	d.newPage()
+}
+
+// Bytes renders the document
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	offsets := []int{}
+
+	writeObj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Embedded fonts are numbered in order of first use and their glyphs
+	// encoded up front, since each font's widths and ToUnicode map cover only
+	// the glyphs the document uses
+	var faces []*Font
+	resources := make(map[*Font]string)
+	used := make(map[*Font]map[uint16]rune)
+	encoded := make([][]string, len(d.pages))
+	for i, page := range d.pages {
+		encoded[i] = make([]string, len(page))
+		for j, l := range page {
+			if l.face == nil {
+				continue
+			}
+			if _, ok := resources[l.face]; !ok {
+				faces = append(faces, l.face)
+				resources[l.face] = fmt.Sprintf("E%d", len(faces))
+				used[l.face] = make(map[uint16]rune)
+			}
+			encoded[i][j] = l.face.encode(l.text, used[l.face])
+		}
+	}
+
+	// 1 catalog, 2 page tree, 3-5 standard fonts, 5 objects per embedded
+	// font, then a page and content object per page
+	const fontObjects = 5
+	firstPage := 6 + len(faces)*fontObjects
+
+	fonts := fmt.Sprintf("/%s 3 0 R /%s 4 0 R /%s 5 0 R", fontRegular, fontBold, fontMono)
+	for i, face := range faces {
+		fonts += fmt.Sprintf(" /%s %d 0 R", resources[face], 6+i*fontObjects)
+	}
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+	writeObj("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	for i, face := range faces {
+		for _, obj := range face.objects(6+i*fontObjects, used[face]) {
+			writeObj(obj)
+		}
+	}
+
+	for i, page := range d.pages {
+		var content bytes.Buffer
+		for j, l := range page {
+			if l.face != nil {
+				fmt.Fprintf(&content, "BT /%s %.1f Tf %.1f %.1f Td <%s> Tj ET\n", resources[l.face], l.size, margin, l.y, encoded[i][j])
+				continue
+			}
+			fmt.Fprintf(&content, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", l.font, l.size, margin, l.y, escape(l.text))
+		}
+
+		writeObj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fonts, firstPage+i*2+1))
+		writeObj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, nil)
+	d.y = pageHeight - margin
+}
+
+func (d *Document) advance(size float64) {
+	d.y -= size * lineSpacing
+	if d.y < bottomMargin {
+		d.newPage()
+		d.y -= size * lineSpacing
+	}
+}
+
+func (d *Document) add(font string, size float64, text string) {
+	d.advance(size)
+	l := line{font: font, size: size, text: text, y: d.y}
+	if d.regular != nil && !isLatin1(text) {
+		l.face = d.regular
+		if font == fontBold {
+			l.face = d.bold
+		}
+	}
+	page := len(d.pages) - 1
+	d.pages[page] = append(d.pages[page], l)
+}
+
+func isLatin1(text string) bool {
+	for _, r := range text {
+		if r > 255 {
+			return false
+		}
+	}
+	return true
+}
+
+// wrap splits text into lines of at most width characters, breaking on spaces
+func wrap(text string, width int) []string {
+	if utf8.RuneCountInString(text) <= width {
+		return []string{text}
+	}
+
+	var lines []string
+	var current []string
+	length := 0
+	for _, word := range strings.Fields(text) {
+		wordLen := utf8.RuneCountInString(word)
+		if length > 0 && length+1+wordLen > width {
+			lines = append(lines, strings.Join(current, " "))
+			current, length = nil, 0
+		}
+		if length > 0 {
+			length++
+		}
+		current = append(current, word)
+		length += wordLen
+	}
+	if len(current) > 0 {
+		lines = append(lines, strings.Join(current, " "))
+	}
+	return lines
+}
+
+// escape converts text to a WinAnsi PDF string literal body
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 32:
+			// drop control characters
+		case r < 128:
+			b.WriteRune(r)
+		case r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
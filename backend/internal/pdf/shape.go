@@ -0,0 +1,64 @@
+package pdf
+
+// The writer does no OpenType shaping, so conjuncts in Indic scripts are shown
+// with a visible virama. The one fix-up applied is moving vowel signs that are
+// written before their consonant cluster (and the leading half of two-part
+// vowels) into visual order, which otherwise makes words unreadable.
+
+var twoPartVowels = map[rune][2]rune{
+	0x0BCA: {0x0BC6, 0x0BBE}, // Tamil o
+	0x0BCB: {0x0BC7, 0x0BBE}, // Tamil oo
+	0x0BCC: {0x0BC6, 0x0BD7}, // Tamil au
+	0x0D4A: {0x0D46, 0x0D3E}, // Malayalam o
+	0x0D4B: {0x0D47, 0x0D3E}, // Malayalam oo
+	0x0D4C: {0x0D46, 0x0D57}, // Malayalam au
+}
+
+func isPreBaseVowel(r rune) bool {
+	switch r {
+	case 0x093F, // Devanagari i
+		0x0BC6, 0x0BC7, 0x0BC8, // Tamil e, ee, ai
+		0x0D46, 0x0D47, 0x0D48: // Malayalam e, ee, ai
+		return true
+	}
+	return false
+}
+
+func isConsonant(r rune) bool {
+	return (r >= 0x0915 && r <= 0x0939) || (r >= 0x0958 && r <= 0x095F) ||
+		(r >= 0x0B95 && r <= 0x0BB9) ||
+		(r >= 0x0D15 && r <= 0x0D3A)
+}
+
+func isVirama(r rune) bool {
+	return r == 0x094D || r == 0x0BCD || r == 0x0D4D
+}
+
+// shape returns text in visual order for fonts drawn glyph by glyph
+func shape(text string) []rune {
+	out := make([]rune, 0, len(text))
+	for _, r := range text {
+		if parts, ok := twoPartVowels[r]; ok {
+			out = append(out, parts[0], parts[1])
+			continue
+		}
+		out = append(out, r)
+	}
+
+	for i, r := range out {
+		if !isPreBaseVowel(r) || i == 0 || !isConsonant(out[i-1]) {
+			continue
+		}
+
+		// Walk back over the consonant cluster: C (virama C)*
+		start := i - 1
+		for start >= 2 && isVirama(out[start-1]) && isConsonant(out[start-2]) {
+			start -= 2
+		}
+
+		copy(out[start+1:i+1], out[start:i])
+		out[start] = r
+	}
+
+	return out
+}
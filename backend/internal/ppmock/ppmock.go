@@ -0,0 +1,519 @@
+// Package ppmock is a stand-in for ProPresenter's REST API, covering the
+// calls the propresenter client makes: library, presentations, playlists,
+// triggers, looks, media and audio, clearing layers and status, including
+// the status stream. It keeps its state in memory, so every ProPresenter
+// handler can be tried on a laptop without a ProPresenter machine. It is not
+// a full emulation; responses have the shapes the client decodes, not every
+// field ProPresenter sends.
+package ppmock
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+)
+
+// Server is a simulated ProPresenter
+type Server struct {
+	mu sync.Mutex
+
+	presentations []*propresenter.Presentation
+	playlists     []*propresenter.Playlist
+	looks         []propresenter.Look
+	media         []mediaPlaylist
+	audio         []mediaPlaylist
+
+	state State
+
+	latency time.Duration
+	logging bool
+}
+
+// mediaPlaylist is a media or audio playlist with its items
+type mediaPlaylist struct {
+	ID    propresenter.PlaylistID
+	Items []propresenter.MediaItem
+}
+
+// State is what the simulated ProPresenter is showing, returned by GET /mock/state
+type State struct {
+	Presentation *propresenter.PresentationID `json:"presentation,omitempty"`
+	SlideIndex   int                          `json:"slide_index"`
+	SlideText    string                       `json:"slide_text,omitempty"`
+	Look         string                       `json:"look,omitempty"`
+	Media        string                       `json:"media,omitempty"`
+	Audio        string                       `json:"audio,omitempty"`
+	Cleared      []string                     `json:"cleared"` // layers cleared since the last trigger
+	TimersStop   int                          `json:"timers_stopped"`
+	Requests     int                          `json:"requests"`
+}
+
+// New returns a simulator with an empty library and playlist named playlist
+// (the Live Queue), plus a few looks, media and audio items
+func New(playlist string) *Server {
+	if playlist == "" {
+		playlist = "Live Queue"
+	}
+	s := &Server{state: State{Cleared: make([]string, 0)}}
+	s.playlists = append(s.playlists, &propresenter.Playlist{
+		ID: propresenter.PlaylistID{UUID: newUUID(), Name: playlist, Type: "playlist"},
+	})
+	for _, name := range []string{"Lyrics Only", "Lyrics + Background", "Stage Only"} {
+		s.looks = append(s.looks, propresenter.Look{ID: propresenter.LibraryItemID{UUID: newUUID(), Name: name}})
+	}
+	s.media = []mediaPlaylist{newMediaPlaylist("Backgrounds", "Blue Motion", "Sunrise Still", "Worship Lights")}
+	s.audio = []mediaPlaylist{newMediaPlaylist("Tracks", "Click 72", "Pad in D")}
+	return s
+}
+
+// SetLatency delays every response, to try timeouts and slow-call logging
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetLogging logs every request
+func (s *Server) SetLogging(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logging = on
+}
+
+// AddPresentation adds a presentation to the library and returns it. An
+// empty UUID gets a new one.
+func (s *Server) AddPresentation(p propresenter.Presentation) *propresenter.Presentation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addPresentationLocked(p)
+}
+
+func (s *Server) addPresentationLocked(p propresenter.Presentation) *propresenter.Presentation {
+	if p.ID.UUID == "" {
+		p.ID.UUID = newUUID()
+	}
+	s.presentations = append(s.presentations, &p)
+	return &p
+}
+
+// State returns what the simulator is showing
+func (s *Server) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.state
+	state.Cleared = append([]string(nil), s.state.Cleared...)
+	return state
+}
+
+// ServeHTTP routes a ProPresenter API request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.state.Requests++
+	latency, logging := s.latency, s.logging
+	s.mu.Unlock()
+
+	if logging {
+		log.Printf("%s %s", r.Method, r.URL.RequestURI())
+	}
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || (parts[0] != "v1" && parts[0] != "mock") {
+		http.NotFound(w, r)
+		return
+	}
+	if parts[0] == "mock" {
+		s.serveMock(w, r, parts[1:])
+		return
+	}
+	if r.Method == http.MethodPost && match(parts[1:], "status", "updates") {
+		s.statusUpdates(w, r) // stays open, so it takes s.mu only while reading
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serveAPI(w, r, parts[1:])
+}
+
+// serveAPI handles /v1/...; s.mu is held
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request, parts []string) {
+	get := r.Method == http.MethodGet
+	switch {
+	case get && match(parts, "status"):
+		writeJSON(w, http.StatusOK, map[string]string{"name": "ppmock"})
+	case get && match(parts, "status", "slide"):
+		s.slideStatus(w)
+
+	case get && match(parts, "library"):
+		s.library(w, r.URL.Query().Get("q"))
+	case get && match(parts, "trigger", "library", "*"):
+		s.triggerSlide(w, parts[2], 0)
+	case get && match(parts, "trigger", "next"):
+		s.moveSlide(w, 1)
+	case get && match(parts, "trigger", "previous"):
+		s.moveSlide(w, -1)
+
+	case r.Method == http.MethodPost && match(parts, "presentation"):
+		s.createPresentation(w, r)
+	case get && match(parts, "presentation", "*"):
+		if p := s.presentation(parts[1]); p != nil {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"presentation": p})
+		} else {
+			writeError(w, http.StatusNotFound, "presentation not found")
+		}
+	case get && match(parts, "presentation", "*", "*", "trigger"):
+		index, err := strconv.Atoi(parts[2])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid slide index")
+			return
+		}
+		s.triggerSlide(w, parts[1], index)
+
+	case get && match(parts, "playlists"):
+		playlists := make([]propresenter.Playlist, 0, len(s.playlists))
+		for _, pl := range s.playlists {
+			playlists = append(playlists, propresenter.Playlist{ID: pl.ID})
+		}
+		writeJSON(w, http.StatusOK, playlists)
+	case r.Method == http.MethodPost && match(parts, "playlists"):
+		s.createPlaylist(w, r)
+	case get && match(parts, "playlist", "*"):
+		if pl := s.playlist(parts[1]); pl != nil {
+			writeJSON(w, http.StatusOK, pl)
+		} else {
+			writeError(w, http.StatusNotFound, "playlist not found")
+		}
+	case r.Method == http.MethodPut && match(parts, "playlist", "*"):
+		s.addToPlaylist(w, r, parts[1])
+
+	case get && match(parts, "looks"):
+		writeJSON(w, http.StatusOK, s.looks)
+	case get && match(parts, "look", "*", "trigger"):
+		for _, look := range s.looks {
+			if look.ID.UUID == parts[1] || strings.EqualFold(look.ID.Name, parts[1]) {
+				s.state.Look = look.ID.Name
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "look not found")
+
+	case get && (match(parts, "media", "playlists") || match(parts, "audio", "playlists")):
+		writeJSON(w, http.StatusOK, listMediaPlaylists(s.mediaKind(parts[0])))
+	case get && (match(parts, "media", "playlist", "*") || match(parts, "audio", "playlist", "*")):
+		for _, pl := range s.mediaKind(parts[0]) {
+			if pl.ID.UUID == parts[2] {
+				writeJSON(w, http.StatusOK, map[string]interface{}{"id": pl.ID, "items": pl.Items})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "playlist not found")
+	case get && (match(parts, "media", "playlist", "*", "*", "trigger") || match(parts, "audio", "playlist", "*", "*", "trigger")):
+		s.triggerMedia(w, parts[0], parts[2], parts[3])
+
+	case get && match(parts, "clear", "layer", "*"):
+		s.clearLayer(w, parts[2])
+	case get && match(parts, "timers", "stop"):
+		s.state.TimersStop++
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// serveMock handles the simulator's own /mock/... endpoints
+func (s *Server) serveMock(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case r.Method == http.MethodGet && match(parts, "state"):
+		writeJSON(w, http.StatusOK, s.State())
+	case r.Method == http.MethodDelete && match(parts, "playlist", "*", "*"):
+		// What an operator removing an item in ProPresenter would do
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		pl := s.playlist(parts[1])
+		if pl == nil {
+			writeError(w, http.StatusNotFound, "playlist not found")
+			return
+		}
+		for i, item := range pl.Items {
+			if item.ID.UUID == parts[2] {
+				pl.Items = append(pl.Items[:i], pl.Items[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "playlist item not found")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) library(w http.ResponseWriter, query string) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	items := make([]propresenter.LibraryItem, 0, len(s.presentations))
+	for _, p := range s.presentations {
+		if query != "" && !strings.Contains(strings.ToLower(p.ID.Name), query) {
+			continue
+		}
+		items = append(items, propresenter.LibraryItem{
+			ID:   propresenter.LibraryItemID{UUID: p.ID.UUID, Name: p.ID.Name, Type: "presentation"},
+			Type: "presentation",
+		})
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *Server) presentation(uuid string) *propresenter.Presentation {
+	for _, p := range s.presentations {
+		if strings.EqualFold(p.ID.UUID, uuid) {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *Server) createPresentation(w http.ResponseWriter, r *http.Request) {
+	var p propresenter.Presentation
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid presentation")
+		return
+	}
+	if strings.TrimSpace(p.ID.Name) == "" {
+		writeError(w, http.StatusBadRequest, "presentation name is required")
+		return
+	}
+	p.ID.UUID = ""
+	writeJSON(w, http.StatusCreated, s.addPresentationLocked(p))
+}
+
+// slides lists a presentation's slides across its groups
+func slides(p *propresenter.Presentation) []propresenter.Slide {
+	var all []propresenter.Slide
+	for _, group := range p.Groups {
+		all = append(all, group.Slides...)
+	}
+	return all
+}
+
+func (s *Server) triggerSlide(w http.ResponseWriter, uuid string, index int) {
+	p := s.presentation(uuid)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "presentation not found")
+		return
+	}
+	all := slides(p)
+	if index < 0 || (len(all) > 0 && index >= len(all)) {
+		writeError(w, http.StatusBadRequest, "slide index out of range")
+		return
+	}
+	s.show(p, index)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) moveSlide(w http.ResponseWriter, delta int) {
+	if s.state.Presentation != nil {
+		if p := s.presentation(s.state.Presentation.UUID); p != nil {
+			index := s.state.SlideIndex + delta
+			if index >= 0 && index < len(slides(p)) {
+				s.show(p, index)
+			}
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// show puts a slide on screen
+func (s *Server) show(p *propresenter.Presentation, index int) {
+	id := p.ID
+	s.state.Presentation = &id
+	s.state.SlideIndex = index
+	s.state.SlideText = ""
+	if all := slides(p); index < len(all) {
+		s.state.SlideText = all[index].Text
+	}
+	s.state.Cleared = make([]string, 0)
+}
+
+func (s *Server) slideStatus(w http.ResponseWriter) {
+	type slide struct {
+		Text string `json:"text"`
+	}
+	status := map[string]*slide{"current": nil, "next": nil}
+	if s.state.Presentation != nil && !cleared(s.state.Cleared, "slide") {
+		if p := s.presentation(s.state.Presentation.UUID); p != nil {
+			all := slides(p)
+			if s.state.SlideIndex < len(all) {
+				status["current"] = &slide{Text: all[s.state.SlideIndex].Text}
+			}
+			if s.state.SlideIndex+1 < len(all) {
+				status["next"] = &slide{Text: all[s.state.SlideIndex+1].Text}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) playlist(uuid string) *propresenter.Playlist {
+	for _, pl := range s.playlists {
+		if strings.EqualFold(pl.ID.UUID, uuid) {
+			return pl
+		}
+	}
+	return nil
+}
+
+func (s *Server) createPlaylist(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "playlist name is required")
+		return
+	}
+	pl := &propresenter.Playlist{ID: propresenter.PlaylistID{UUID: newUUID(), Name: req.Name, Type: "playlist"}}
+	s.playlists = append(s.playlists, pl)
+	writeJSON(w, http.StatusCreated, pl)
+}
+
+// addToPlaylist appends the posted items, which is how the client uses PUT
+func (s *Server) addToPlaylist(w http.ResponseWriter, r *http.Request, uuid string) {
+	pl := s.playlist(uuid)
+	if pl == nil {
+		writeError(w, http.StatusNotFound, "playlist not found")
+		return
+	}
+	var items []propresenter.PlaylistItem
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid playlist items")
+		return
+	}
+	for _, item := range items {
+		p := s.presentation(item.ID.UUID)
+		if p == nil {
+			writeError(w, http.StatusNotFound, "presentation not found: "+item.ID.UUID)
+			return
+		}
+		item.ID.Name = p.ID.Name
+		item.IsEnabled = true
+		pl.Items = append(pl.Items, item)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) mediaKind(kind string) []mediaPlaylist {
+	if kind == "audio" {
+		return s.audio
+	}
+	return s.media
+}
+
+func listMediaPlaylists(playlists []mediaPlaylist) []map[string]interface{} {
+	list := make([]map[string]interface{}, 0, len(playlists))
+	for _, pl := range playlists {
+		list = append(list, map[string]interface{}{"id": pl.ID})
+	}
+	return list
+}
+
+func (s *Server) triggerMedia(w http.ResponseWriter, kind, playlist, item string) {
+	for _, pl := range s.mediaKind(kind) {
+		if pl.ID.UUID != playlist {
+			continue
+		}
+		for _, it := range pl.Items {
+			if it.ID.UUID == item {
+				if kind == "audio" {
+					s.state.Audio = it.ID.Name
+				} else {
+					s.state.Media = it.ID.Name
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, kind+" item not found")
+}
+
+func (s *Server) clearLayer(w http.ResponseWriter, layer string) {
+	known := false
+	for _, l := range propresenter.Layers {
+		if l == layer {
+			known = true
+		}
+	}
+	if !known {
+		writeError(w, http.StatusBadRequest, "unknown layer: "+layer)
+		return
+	}
+	switch layer {
+	case "media":
+		s.state.Media = ""
+	case "audio":
+		s.state.Audio = ""
+	}
+	if !cleared(s.state.Cleared, layer) {
+		s.state.Cleared = append(s.state.Cleared, layer)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func cleared(layers []string, layer string) bool {
+	for _, l := range layers {
+		if l == layer {
+			return true
+		}
+	}
+	return false
+}
+
+func newMediaPlaylist(name string, items ...string) mediaPlaylist {
+	pl := mediaPlaylist{ID: propresenter.PlaylistID{UUID: newUUID(), Name: name, Type: "playlist"}}
+	for _, item := range items {
+		pl.Items = append(pl.Items, propresenter.MediaItem{ID: propresenter.LibraryItemID{UUID: newUUID(), Name: item}})
+	}
+	return pl
+}
+
+// match reports whether the path parts equal pattern, where "*" matches any part
+func match(parts []string, pattern ...string) bool {
+	if len(parts) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	http.Error(w, msg, status)
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
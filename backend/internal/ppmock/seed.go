@@ -0,0 +1,43 @@
+package ppmock
+
+import (
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/demo"
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+)
+
+// SeedDemo adds a presentation for every song in the demo library, split
+// into slide groups the way the client creates presentations, so the demo
+// library and the simulator can be used together. Songs are matched to
+// presentations by title.
+func (s *Server) SeedDemo() int {
+	archive := demo.Archive(time.Now(), nil)
+	for _, song := range archive.Songs {
+		s.AddPresentation(propresenter.Presentation{
+			ID:     propresenter.PresentationID{Name: song.Title},
+			Groups: groups(song.DisplayLyrics),
+		})
+	}
+	return len(archive.Songs)
+}
+
+// groups splits lyrics into slide groups, one per run of slides with the
+// same section label
+func groups(text string) []propresenter.SlideGroup {
+	segmented, _ := lyrics.Segment(text, lyrics.DefaultSegmentOptions)
+	groups := make([]propresenter.SlideGroup, 0)
+	for i, slide := range segmented {
+		name := slide.Label
+		if name == "" {
+			name = "Lyrics"
+		}
+		if i == 0 || segmented[i-1].Label != slide.Label {
+			groups = append(groups, propresenter.SlideGroup{Name: name})
+		}
+		last := &groups[len(groups)-1]
+		last.Slides = append(last.Slides, propresenter.Slide{Enabled: true, Text: slide.Text()})
+	}
+	return groups
+}
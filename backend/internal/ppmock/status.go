@@ -0,0 +1,107 @@
+package ppmock
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/propresenter"
+)
+
+// statusPoll is how often an open status stream looks for changes
+const statusPoll = 200 * time.Millisecond
+
+// statusUpdates serves POST /v1/status/updates: the body lists the status
+// endpoints to follow, and each one's value is sent when the stream opens and
+// again whenever it changes, until the client goes away
+func (s *Server) statusUpdates(w http.ResponseWriter, r *http.Request) {
+	var endpoints []string
+	if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid endpoint list")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	sent := make(map[string]interface{})
+	ticker := time.NewTicker(statusPoll)
+	defer ticker.Stop()
+	for {
+		s.mu.Lock()
+		values := make(map[string]interface{}, len(endpoints))
+		for _, endpoint := range endpoints {
+			if value, ok := s.statusValue(endpoint); ok {
+				values[endpoint] = value
+			}
+		}
+		s.mu.Unlock()
+
+		for _, endpoint := range endpoints {
+			value, ok := values[endpoint]
+			if previous, seen := sent[endpoint]; !ok || (seen && reflect.DeepEqual(previous, value)) {
+				continue
+			}
+			sent[endpoint] = value
+			message, _ := json.Marshal(map[string]interface{}{"url": endpoint, "data": value})
+			if _, err := w.Write(append(message, "\r\n\r\n"...)); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// statusValue is the current value of a status endpoint, in the shape
+// ProPresenter streams it; s.mu is held
+func (s *Server) statusValue(endpoint string) (interface{}, bool) {
+	var current *propresenter.Presentation
+	if s.state.Presentation != nil && !cleared(s.state.Cleared, "slide") {
+		current = s.presentation(s.state.Presentation.UUID)
+	}
+
+	switch endpoint {
+	case "presentation/active":
+		if current == nil {
+			return map[string]interface{}{"presentation": nil}, true
+		}
+		return map[string]interface{}{"presentation": map[string]interface{}{"id": current.ID}}, true
+	case "presentation/slide_index":
+		if current == nil {
+			return map[string]interface{}{"presentation_index": nil}, true
+		}
+		return map[string]interface{}{"presentation_index": map[string]interface{}{
+			"index":           s.state.SlideIndex,
+			"presentation_id": current.ID,
+		}}, true
+	case "playlist/active":
+		if current != nil {
+			for _, pl := range s.playlists {
+				for _, item := range pl.Items {
+					if strings.EqualFold(item.ID.UUID, current.ID.UUID) {
+						return map[string]interface{}{"presentation": map[string]interface{}{
+							"playlist": pl.ID,
+							"item":     item.ID,
+						}}, true
+					}
+				}
+			}
+		}
+		return map[string]interface{}{"presentation": nil}, true
+	}
+	return nil, false
+}
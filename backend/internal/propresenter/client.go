@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
 )
 
 // Client handles communication with ProPresenter API
@@ -21,6 +24,12 @@ type Client struct {
 	connected  bool
 	lastCheck  time.Time
 	mu         sync.RWMutex
+	library    libraryCache
+	dryRun     *dryRunTransport
+	health     *healthCheck
+	status     *statusListener
+
+	connectedSince time.Time // when the current connection came up
 }
 
 // Config holds ProPresenter configuration
@@ -105,66 +114,84 @@ type SearchResult struct {
 
 // New creates a new ProPresenter client
 func New(config *Config) *Client {
+	// The HTTP client is set up even when disabled, so Reconfigure can enable it later
+	dryRun := &dryRunTransport{next: &timedTransport{next: &http.Transport{
+		MaxIdleConns:          10,
+		MaxIdleConnsPerHost:   5,
+		IdleConnTimeout:       30 * time.Second,
+		DisableKeepAlives:     false,
+		ResponseHeaderTimeout: 3 * time.Second,
+	}}}
+	httpClient := &http.Client{
+		Timeout:   5 * time.Second, // Shorter timeout for production
+		Transport: dryRun,
+	}
+
 	if config == nil || !config.Enabled {
-		return &Client{enabled: false}
+		return &Client{enabled: false, httpClient: httpClient, dryRun: dryRun, health: newHealthCheck(), status: newStatusListener(dryRun.next)}
 	}
 
 	baseURL := fmt.Sprintf("http://%s:%s", config.Host, config.Port)
 	
 	client := &Client{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 5 * time.Second, // Shorter timeout for production
-			Transport: &http.Transport{
-				MaxIdleConns:        10,
-				MaxIdleConnsPerHost:  5,
-				IdleConnTimeout:      30 * time.Second,
-				DisableKeepAlives:    false,
-				ResponseHeaderTimeout: 3 * time.Second,
-			},
-		},
-		enabled:   true,
-		config:    config,
-		connected: false,
+		baseURL:    baseURL,
+		httpClient: httpClient,
+		dryRun:     dryRun,
+		health:     newHealthCheck(),
+		status:     newStatusListener(dryRun.next), // the stream only reads, so it skips dry-run
+		enabled:    true,
+		config:     config,
+		connected:  false,
 	}
 	
-	// Check connection on initialization
-	if err := client.Health(); err == nil {
-		client.mu.Lock()
-		client.connected = true
-		client.lastCheck = time.Now()
-		client.mu.Unlock()
-	}
+	// Connect in the background so startup isn't held up when the ProPresenter
+	// machine is off; the periodic health check keeps trying after that
+	go client.refreshConnection()
 	
 	return client
 }
 
 // Reconfigure updates the client configuration and checks connection
 func (c *Client) Reconfigure(config *Config) error {
+	// Reconnect the status stream once the new configuration is in place;
+	// deferred first so it runs after the unlock
+	defer c.restartStatusStream()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	
 	if config == nil || !config.Enabled || config.Host == "" {
 		c.enabled = false
-		c.connected = false
+		c.setConnectedLocked(false)
 		return nil
 	}
 	
 	c.config = config
 	c.baseURL = fmt.Sprintf("http://%s:%s", config.Host, config.Port)
 	c.enabled = true
+	c.InvalidateLibrary()
 	
 	// Check connection with new configuration
 	if err := c.healthCheckLocked(); err == nil {
-		c.connected = true
+		c.setConnectedLocked(true)
 		c.lastCheck = time.Now()
 	} else {
-		c.connected = false
+		c.setConnectedLocked(false)
 	}
 	
 	return nil
 }
 
+// CurrentConfig returns a copy of the configuration in use, or nil while disabled
+func (c *Client) CurrentConfig() *Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.enabled || c.config == nil {
+		return nil
+	}
+	config := *c.config
+	return &config
+}
+
 // IsConnected returns whether ProPresenter is currently connected
 func (c *Client) IsConnected() bool {
 	c.mu.RLock()
@@ -172,9 +199,30 @@ func (c *Client) IsConnected() bool {
 	return c.connected
 }
 
+// ConnectedSince returns when the current connection to ProPresenter came up,
+// and false while it is not connected
+func (c *Client) ConnectedSince() (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connectedSince, c.connected
+}
+
+// setConnectedLocked records the connection state, noting when it comes up (must be called with lock held)
+func (c *Client) setConnectedLocked(connected bool) {
+	if connected && !c.connected {
+		c.connectedSince = time.Now()
+	}
+	c.connected = connected
+}
+
 // healthCheckLocked performs health check without acquiring lock (must be called with lock held)
 func (c *Client) healthCheckLocked() error {
-	resp, err := c.httpClient.Get(c.baseURL + "/v1/status")
+	return c.ping(c.baseURL)
+}
+
+// ping checks that ProPresenter answers at baseURL
+func (c *Client) ping(baseURL string) error {
+	resp, err := c.httpClient.Get(baseURL + "/v1/status")
 	if err != nil {
 		return fmt.Errorf("ProPresenter not reachable: %w", err)
 	}
@@ -187,27 +235,39 @@ func (c *Client) healthCheckLocked() error {
 	return nil
 }
 
-// StartPeriodicHealthCheck starts a goroutine that checks ProPresenter health periodically
-func (c *Client) StartPeriodicHealthCheck(interval time.Duration) {
-	if !c.enabled {
+// refreshConnection updates the connected state without holding the lock
+// during the request, so status reads aren't blocked while ProPresenter is
+// slow or off
+func (c *Client) refreshConnection() {
+	c.mu.RLock()
+	enabled, baseURL := c.enabled, c.baseURL
+	c.mu.RUnlock()
+	if !enabled {
 		return
 	}
-	
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-		
-		for range ticker.C {
-			c.mu.Lock()
-			if err := c.healthCheckLocked(); err == nil {
-				c.connected = true
-				c.lastCheck = time.Now()
-			} else {
-				c.connected = false
-			}
-			c.mu.Unlock()
+	if c.streaming() {
+		// The open status stream already shows ProPresenter is there
+		c.mu.Lock()
+		c.lastCheck = time.Now()
+		c.mu.Unlock()
+		return
+	}
+
+	err := c.ping(baseURL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.baseURL != baseURL {
+		return // reconfigured meanwhile; that check wins
+	}
+	wasConnected := c.connected
+	c.setConnectedLocked(err == nil)
+	if err == nil {
+		c.lastCheck = time.Now()
+		if !wasConnected {
+			log.Printf("✅ ProPresenter connected: %s", baseURL)
 		}
-	}()
+	}
 }
 
 // IsEnabled returns whether ProPresenter integration is enabled
@@ -217,12 +277,8 @@ func (c *Client) IsEnabled() bool {
 	return c.enabled
 }
 
-// GetLibrary fetches all library items from ProPresenter
-func (c *Client) GetLibrary() ([]LibraryItem, error) {
-	if !c.enabled {
-		return nil, fmt.Errorf("ProPresenter integration is not enabled")
-	}
-
+// fetchLibrary fetches all library items from ProPresenter, bypassing the cache
+func (c *Client) fetchLibrary() ([]LibraryItem, error) {
 	resp, err := c.httpClient.Get(c.baseURL + "/v1/library")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch library: %w", err)
@@ -242,32 +298,6 @@ func (c *Client) GetLibrary() ([]LibraryItem, error) {
 	return items, nil
 }
 
-// SearchLibrary searches the library by name
-func (c *Client) SearchLibrary(query string) ([]LibraryItem, error) {
-	if !c.enabled {
-		return nil, fmt.Errorf("ProPresenter integration is not enabled")
-	}
-
-	encodedQuery := url.QueryEscape(query)
-	resp, err := c.httpClient.Get(c.baseURL + "/v1/library?q=" + encodedQuery)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search library: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var items []LibraryItem
-	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
-		return nil, fmt.Errorf("failed to decode search results: %w", err)
-	}
-
-	return items, nil
-}
-
 // FindSongByTitle searches for a song by exact title match
 func (c *Client) FindSongByTitle(title string) (*LibraryItem, error) {
 	items, err := c.SearchLibrary(title)
@@ -484,49 +514,118 @@ func (c *Client) ClearLayer(layer string) error {
 	return nil
 }
 
-// CreatePresentation creates a new presentation in ProPresenter with the given lyrics
-func (c *Client) CreatePresentation(title string, lyrics string) (*LibraryItem, error) {
+// Layers lists every ProPresenter output layer that can be cleared
+var Layers = []string{"audio", "props", "messages", "announcements", "slide", "media", "video_input"}
+
+// StopAllTimers stops every running timer
+func (c *Client) StopAllTimers() error {
+	if !c.enabled {
+		return fmt.Errorf("ProPresenter integration is not enabled")
+	}
+
+	resp, err := c.httpClient.Get(c.baseURL + "/v1/timers/stop")
+	if err != nil {
+		return fmt.Errorf("failed to stop timers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to stop timers, status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// TriggerPresentationSlide triggers a specific slide of a presentation
+func (c *Client) TriggerPresentationSlide(uuid string, index int) error {
+	if !c.enabled {
+		return fmt.Errorf("ProPresenter integration is not enabled")
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/presentation/%s/%d/trigger", c.baseURL, uuid, index)
+
+	resp, err := c.httpClient.Get(endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to trigger slide: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to trigger slide, status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// CreatePresentation creates a new presentation in ProPresenter with the given
+// lyrics. A non-empty copyright adds a final attribution slide.
+func (c *Client) CreatePresentation(title string, text string, copyright []string) (*LibraryItem, error) {
 	if !c.enabled {
 		return nil, fmt.Errorf("ProPresenter integration is not enabled")
 	}
 
-	// Split lyrics into slides (by double newline or paragraph breaks)
-	lines := strings.Split(lyrics, "\n\n")
-	if len(lines) == 1 {
-		// If no double newlines, split by single newlines
-		lines = strings.Split(lyrics, "\n")
+	// Split lyrics into slides using the shared segmentation pipeline
+	segmented, _ := lyrics.Segment(text, lyrics.DefaultSegmentOptions)
+	if len(segmented) == 0 {
+		return nil, fmt.Errorf("no valid slides created from lyrics")
 	}
 
-	// Create slide groups - one group with all slides
-	slides := make([]Slide, 0)
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			slides = append(slides, Slide{
-				Enabled: true,
-				Text:    line,
-				Notes:   "",
-			})
+	// Consecutive slides with the same label form one group (Verse 1, Chorus, ...)
+	groups := make([]SlideGroup, 0)
+	for i, s := range segmented {
+		name := s.Label
+		if name == "" {
+			name = "Lyrics"
 		}
+		if i == 0 || segmented[i-1].Label != s.Label {
+			groups = append(groups, SlideGroup{Name: name, Color: ""})
+		}
+		last := &groups[len(groups)-1]
+		last.Slides = append(last.Slides, Slide{
+			Enabled: true,
+			Text:    s.Text(),
+			Notes:   "",
+		})
+	}
+	if len(copyright) > 0 {
+		groups = append(groups, SlideGroup{
+			Name:   lyrics.CopyrightLabel,
+			Slides: []Slide{{Enabled: true, Text: strings.Join(copyright, "\n")}},
+		})
 	}
 
-	if len(slides) == 0 {
-		return nil, fmt.Errorf("no valid slides created from lyrics")
+	return c.createPresentation(title, groups)
+}
+
+// CreateSlidePresentation creates a presentation with one group holding the
+// given slide texts as they are, for content such as scripture that is
+// already split into slides
+func (c *Client) CreateSlidePresentation(title, groupName string, texts []string) (*LibraryItem, error) {
+	if !c.enabled {
+		return nil, fmt.Errorf("ProPresenter integration is not enabled")
+	}
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("no slides to create")
 	}
 
+	group := SlideGroup{Name: groupName}
+	for _, text := range texts {
+		group.Slides = append(group.Slides, Slide{Enabled: true, Text: text})
+	}
+	return c.createPresentation(title, []SlideGroup{group})
+}
+
+// createPresentation posts a presentation and looks up the library item for it
+func (c *Client) createPresentation(title string, groups []SlideGroup) (*LibraryItem, error) {
 	// Create presentation structure
 	presentation := Presentation{
 		ID: PresentationID{
 			UUID: "",
 			Name: title,
 		},
-		Groups: []SlideGroup{
-			{
-				Name:   "Lyrics",
-				Color:  "",
-				Slides: slides,
-			},
-		},
+		Groups: groups,
 	}
 
 	bodyBytes, err := json.Marshal(presentation)
@@ -550,6 +649,7 @@ func (c *Client) CreatePresentation(title string, lyrics string) (*LibraryItem,
 	// So we need to search for it by name after creation
 	// Wait a brief moment for ProPresenter to index it
 	time.Sleep(500 * time.Millisecond)
+	c.InvalidateLibrary()
 	
 	// Try to find the presentation we just created by searching for it
 	var item *LibraryItem
@@ -557,6 +657,7 @@ func (c *Client) CreatePresentation(title string, lyrics string) (*LibraryItem,
 	for attempt := 0; attempt < 5; attempt++ {
 		if attempt > 0 {
 			time.Sleep(300 * time.Millisecond)
+			c.InvalidateLibrary()
 		}
 		item, err = c.FindSongByTitle(title)
 		if err == nil {
@@ -583,10 +684,12 @@ func (c *Client) CreatePresentation(title string, lyrics string) (*LibraryItem,
 	return nil, fmt.Errorf("created presentation but couldn't find it: %w", err)
 }
 
-// SendToLiveQueue finds an existing song in the library and adds it to the playlist
-// Returns the library item UUID
+// SendToLiveQueue adds a song's presentation to the playlist and returns its
+// library item UUID. proUUID is the presentation linked to the song (see the
+// library sync); only songs without one are looked up in the library by
+// title, which can pick the wrong presentation when titles repeat.
 // Includes retry logic for production resilience
-func (c *Client) SendToLiveQueue(songTitle string, playlistName string, lyrics string) (string, error) {
+func (c *Client) SendToLiveQueue(proUUID string, songTitle string, playlistName string) (string, error) {
 	if !c.enabled {
 		return "", fmt.Errorf("ProPresenter integration is not enabled")
 	}
@@ -595,26 +698,31 @@ func (c *Client) SendToLiveQueue(songTitle string, playlistName string, lyrics s
 		playlistName = "Live Queue"
 	}
 
-	if songTitle == "" {
-		return "", fmt.Errorf("song title is required")
+	if proUUID == "" && songTitle == "" {
+		return "", fmt.Errorf("song UUID or title is required")
 	}
 
-	var item *LibraryItem
 	var playlist *Playlist
 	var err error
 
-	// Find existing song in library (no presentation creation)
-	for attempt := 0; attempt < 3; attempt++ {
-		if attempt > 0 {
-			time.Sleep(300 * time.Millisecond)
+	itemUUID := proUUID
+	if itemUUID == "" {
+		// Find existing song in library (no presentation creation)
+		var item *LibraryItem
+		for attempt := 0; attempt < 3; attempt++ {
+			if attempt > 0 {
+				time.Sleep(300 * time.Millisecond)
+				c.InvalidateLibrary() // the song may have been added since the cache was filled
+			}
+			item, err = c.FindSongByTitle(songTitle)
+			if err == nil {
+				break
+			}
 		}
-		item, err = c.FindSongByTitle(songTitle)
-		if err == nil {
-			break
+		if err != nil {
+			return "", fmt.Errorf("song '%s' not found in ProPresenter library: %w", songTitle, err)
 		}
-	}
-	if err != nil {
-		return "", fmt.Errorf("song '%s' not found in ProPresenter library: %w", songTitle, err)
+		itemUUID = item.ID.UUID
 	}
 
 	// Retry finding/creating playlist
@@ -636,9 +744,9 @@ func (c *Client) SendToLiveQueue(songTitle string, playlistName string, lyrics s
 		if attempt > 0 {
 			time.Sleep(300 * time.Millisecond)
 		}
-		err = c.AddToPlaylist(playlist.ID.UUID, item.ID.UUID)
+		err = c.AddToPlaylist(playlist.ID.UUID, itemUUID)
 		if err == nil {
-			return item.ID.UUID, nil
+			return itemUUID, nil
 		}
 	}
 
@@ -652,7 +760,7 @@ func (c *Client) Health() error {
 	defer c.mu.Unlock()
 	
 	if !c.enabled {
-		c.connected = false
+		c.setConnectedLocked(false)
 		return fmt.Errorf("ProPresenter integration is not enabled")
 	}
 
@@ -669,13 +777,13 @@ func (c *Client) Health() error {
 		}
 		
 		// Success
-		c.connected = true
+		c.setConnectedLocked(true)
 		c.lastCheck = time.Now()
 		return nil
 	}
 
 	// Failed after retries
-	c.connected = false
+	c.setConnectedLocked(false)
 	return lastErr
 }
 
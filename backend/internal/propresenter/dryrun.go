@@ -0,0 +1,133 @@
+package propresenter
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// In dry-run mode calls that change what ProPresenter shows or holds
+// (triggers, clearing layers, stopping timers, creating or changing
+// playlists and presentations) are logged instead of sent. Reads still go to
+// ProPresenter, so lookups such as finding a song or playlist are checked for
+// real. New operators can practice against the production machine this way.
+
+// Operation is a ProPresenter call that was logged instead of sent
+type Operation struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   string `json:"body,omitempty"`
+}
+
+// DryRunLog collects the operations of a dry run
+type DryRunLog struct {
+	mu  sync.Mutex
+	ops []Operation
+}
+
+// Operations returns the logged operations in the order they were made
+func (l *DryRunLog) Operations() []Operation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append(make([]Operation, 0, len(l.ops)), l.ops...)
+}
+
+func (l *DryRunLog) add(op Operation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ops = append(l.ops, op)
+}
+
+// dryRunTransport answers mutating requests itself while dry-run mode is on,
+// or always when it belongs to a DryRun copy of the client
+type dryRunTransport struct {
+	next    http.RoundTripper
+	enabled atomic.Bool
+	always  bool
+	log     *DryRunLog
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !mutates(req) || !(t.always || t.enabled.Load()) {
+		return t.next.RoundTrip(req)
+	}
+
+	op := Operation{Method: req.Method, Path: req.URL.RequestURI()}
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		op.Body = string(body)
+	}
+	log.Printf("🧪 ProPresenter dry run: %s %s", op.Method, op.Path)
+	if t.log != nil {
+		t.log.add(op)
+	}
+
+	// Triggers answer 204; creating things answers an empty object, which the
+	// client decodes without complaint
+	resp := &http.Response{
+		StatusCode: http.StatusNoContent,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}
+	if req.Method != http.MethodGet {
+		resp.StatusCode = http.StatusOK
+		resp.Header.Set("Content-Type", "application/json")
+		resp.Body = io.NopCloser(strings.NewReader("{}"))
+	}
+	resp.Status = http.StatusText(resp.StatusCode)
+	return resp, nil
+}
+
+// mutates reports whether a request changes ProPresenter. ProPresenter
+// triggers with GET, so the path decides for those.
+func mutates(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return true
+	}
+	path := req.URL.Path
+	return strings.HasSuffix(path, "/trigger") ||
+		strings.HasPrefix(path, "/v1/trigger/") ||
+		strings.HasPrefix(path, "/v1/clear/") ||
+		path == "/v1/timers/stop"
+}
+
+// SetDryRun turns dry-run mode on or off for every call made through the client
+func (c *Client) SetDryRun(on bool) {
+	c.dryRun.enabled.Store(on)
+}
+
+// DryRunEnabled reports whether dry-run mode is on
+func (c *Client) DryRunEnabled() bool {
+	return c.dryRun.enabled.Load()
+}
+
+// DryRun returns a copy of the client that logs its mutating calls to the
+// returned log instead of sending them, for a single request. The copy
+// shares the connection but not the library cache.
+func (c *Client) DryRun() (*Client, *DryRunLog) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	record := &DryRunLog{ops: make([]Operation, 0)}
+	dry := &Client{
+		baseURL:        c.baseURL,
+		enabled:        c.enabled,
+		config:         c.config,
+		connected:      c.connected,
+		lastCheck:      c.lastCheck,
+		connectedSince: c.connectedSince,
+		dryRun:         &dryRunTransport{always: true, log: record},
+		status:         c.status,
+	}
+	if c.httpClient != nil {
+		dry.dryRun.next = c.dryRun.next
+		dry.httpClient = &http.Client{Timeout: c.httpClient.Timeout, Transport: dry.dryRun}
+	}
+	return dry, record
+}
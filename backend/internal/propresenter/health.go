@@ -0,0 +1,159 @@
+package propresenter
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultHealthCheckInterval is how often the connection is checked unless
+// the settings say otherwise
+const DefaultHealthCheckInterval = 30 * time.Second
+
+// MinHealthCheckInterval keeps the check from flooding ProPresenter
+const MinHealthCheckInterval = 5 * time.Second
+
+// healthCheck is the state of the periodic connection check
+type healthCheck struct {
+	mu       sync.Mutex
+	interval time.Duration
+	paused   bool
+	running  bool
+	wake     chan struct{} // tells the loop the interval or pause changed
+}
+
+func newHealthCheck() *healthCheck {
+	return &healthCheck{interval: DefaultHealthCheckInterval, wake: make(chan struct{}, 1)}
+}
+
+// HealthCheckStatus describes the periodic connection check
+type HealthCheckStatus struct {
+	Running   bool       `json:"running"`
+	Paused    bool       `json:"paused"`
+	Interval  int        `json:"interval_seconds"`
+	LastCheck *time.Time `json:"last_check,omitempty"` // last successful check
+}
+
+// StartPeriodicHealthCheck checks the connection every interval until ctx
+// is done. It runs while the integration is disabled too, doing nothing
+// until Reconfigure enables it. Starting it twice has no effect.
+func (c *Client) StartPeriodicHealthCheck(ctx context.Context, interval time.Duration) {
+	h := c.health
+	h.mu.Lock()
+	if h.running {
+		h.mu.Unlock()
+		return
+	}
+	h.running = true
+	h.mu.Unlock()
+	c.SetHealthCheckInterval(interval)
+
+	go func() {
+		defer func() {
+			h.mu.Lock()
+			h.running = false
+			h.mu.Unlock()
+		}()
+
+		for {
+			h.mu.Lock()
+			interval, paused := h.interval, h.paused
+			h.mu.Unlock()
+
+			// A paused check waits for resume or shutdown
+			var tick <-chan time.Time
+			var timer *time.Timer
+			if !paused {
+				timer = time.NewTimer(interval)
+				tick = timer.C
+			}
+
+			select {
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			case <-h.wake:
+				if timer != nil {
+					timer.Stop()
+				}
+			case <-tick:
+				c.refreshConnection()
+			}
+		}
+	}()
+}
+
+// SetHealthCheckInterval changes how often the connection is checked, from
+// the next check on. Intervals under MinHealthCheckInterval are raised to it.
+func (c *Client) SetHealthCheckInterval(interval time.Duration) {
+	if interval < MinHealthCheckInterval {
+		interval = MinHealthCheckInterval
+	}
+	h := c.health
+	h.mu.Lock()
+	changed := h.interval != interval
+	h.interval = interval
+	h.mu.Unlock()
+	if changed {
+		h.poke()
+	}
+}
+
+// PauseHealthCheck stops the periodic check until ResumeHealthCheck. The
+// connection state stays as it was last seen.
+func (c *Client) PauseHealthCheck() {
+	c.setHealthCheckPaused(true)
+	log.Println("⏸️  ProPresenter health check paused")
+}
+
+// ResumeHealthCheck restarts a paused check, checking right away
+func (c *Client) ResumeHealthCheck() {
+	if c.setHealthCheckPaused(false) {
+		go c.refreshConnection()
+	}
+	log.Println("▶️  ProPresenter health check resumed")
+}
+
+// setHealthCheckPaused reports whether the paused state changed
+func (c *Client) setHealthCheckPaused(paused bool) bool {
+	h := c.health
+	h.mu.Lock()
+	changed := h.paused != paused
+	h.paused = paused
+	h.mu.Unlock()
+	if changed {
+		h.poke()
+	}
+	return changed
+}
+
+// HealthCheckStatus describes the periodic check
+func (c *Client) HealthCheckStatus() HealthCheckStatus {
+	h := c.health
+	h.mu.Lock()
+	status := HealthCheckStatus{
+		Running:  h.running,
+		Paused:   h.paused,
+		Interval: int(h.interval / time.Second),
+	}
+	h.mu.Unlock()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.lastCheck.IsZero() {
+		last := c.lastCheck
+		status.LastCheck = &last
+	}
+	return status
+}
+
+// poke wakes the loop without blocking; one pending wake is enough
+func (h *healthCheck) poke() {
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+}
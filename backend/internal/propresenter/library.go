@@ -0,0 +1,71 @@
+package propresenter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// libraryTTL is how long a fetched library is reused. Listing the library is
+// one of the heavier calls on the presentation machine, and title lookups
+// happen on every send to the Live Queue.
+const libraryTTL = 30 * time.Second
+
+// libraryCache holds the last fetched library. It has its own lock so a slow
+// fetch doesn't block the connection state guarded by Client.mu.
+type libraryCache struct {
+	mu        sync.Mutex
+	items     []LibraryItem
+	fetchedAt time.Time
+}
+
+// GetLibrary returns all library items, from the cache while it is fresh
+func (c *Client) GetLibrary() ([]LibraryItem, error) {
+	if !c.enabled {
+		return nil, fmt.Errorf("ProPresenter integration is not enabled")
+	}
+
+	c.library.mu.Lock()
+	defer c.library.mu.Unlock()
+
+	if c.library.items == nil || time.Since(c.library.fetchedAt) > libraryTTL {
+		items, err := c.fetchLibrary()
+		if err != nil {
+			return nil, err
+		}
+		if items == nil {
+			items = make([]LibraryItem, 0)
+		}
+		c.library.items = items
+		c.library.fetchedAt = time.Now()
+	}
+
+	return append([]LibraryItem(nil), c.library.items...), nil
+}
+
+// SearchLibrary returns the library items whose name contains query
+// (case-insensitive), matched against the cached library
+func (c *Client) SearchLibrary(query string) ([]LibraryItem, error) {
+	items, err := c.GetLibrary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search library: %w", err)
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	matches := make([]LibraryItem, 0)
+	for _, item := range items {
+		if strings.Contains(strings.ToLower(item.ID.Name), query) {
+			matches = append(matches, item)
+		}
+	}
+	return matches, nil
+}
+
+// InvalidateLibrary drops the cached library so the next lookup fetches it
+// again, e.g. after a presentation was created
+func (c *Client) InvalidateLibrary() {
+	c.library.mu.Lock()
+	defer c.library.mu.Unlock()
+	c.library.items = nil
+}
@@ -0,0 +1,155 @@
+package propresenter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Look is a ProPresenter look (a saved combination of screen layers)
+type Look struct {
+	ID LibraryItemID `json:"id"`
+}
+
+// MediaItem is an item in one of ProPresenter's media or audio playlists
+type MediaItem struct {
+	ID       LibraryItemID `json:"id"`
+	Type     string        `json:"type,omitempty"`
+	Playlist PlaylistID    `json:"playlist"`
+}
+
+// mediaPlaylist is a media or audio playlist as listed by /v1/media/playlists
+// and /v1/audio/playlists; folders hold their playlists in children
+type mediaPlaylist struct {
+	ID       PlaylistID      `json:"id"`
+	Children []mediaPlaylist `json:"children,omitempty"`
+}
+
+// GetLooks lists the configured looks
+func (c *Client) GetLooks() ([]Look, error) {
+	var looks []Look
+	if err := c.getJSON("/v1/looks", &looks); err != nil {
+		return nil, fmt.Errorf("failed to fetch looks: %w", err)
+	}
+	return looks, nil
+}
+
+// TriggerLook makes a look live. id may be the look's UUID or name.
+func (c *Client) TriggerLook(id string) error {
+	return c.trigger(fmt.Sprintf("/v1/look/%s/trigger", url.PathEscape(id)), "look")
+}
+
+// GetMediaItems lists every item in the media playlists
+func (c *Client) GetMediaItems() ([]MediaItem, error) {
+	return c.playlistItems("media")
+}
+
+// TriggerMedia puts a media item on the background layer. id may be the
+// item's UUID or name; the playlist holding it is looked up first.
+func (c *Client) TriggerMedia(id string) error {
+	return c.triggerPlaylistItem("media", id)
+}
+
+// GetAudioItems lists every item in the audio playlists
+func (c *Client) GetAudioItems() ([]MediaItem, error) {
+	return c.playlistItems("audio")
+}
+
+// TriggerAudio plays an audio playlist item. id may be the item's UUID or name.
+func (c *Client) TriggerAudio(id string) error {
+	return c.triggerPlaylistItem("audio", id)
+}
+
+// playlistItems lists every item in the media or audio playlists
+func (c *Client) playlistItems(kind string) ([]MediaItem, error) {
+	var playlists []mediaPlaylist
+	if err := c.getJSON("/v1/"+kind+"/playlists", &playlists); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s playlists: %w", kind, err)
+	}
+
+	items := make([]MediaItem, 0)
+	var walk func([]mediaPlaylist) error
+	walk = func(playlists []mediaPlaylist) error {
+		for _, pl := range playlists {
+			if len(pl.Children) > 0 {
+				if err := walk(pl.Children); err != nil {
+					return err
+				}
+				continue
+			}
+
+			var contents struct {
+				Items []MediaItem `json:"items"`
+			}
+			if err := c.getJSON("/v1/"+kind+"/playlist/"+url.PathEscape(pl.ID.UUID), &contents); err != nil {
+				return fmt.Errorf("failed to fetch %s playlist %s: %w", kind, pl.ID.Name, err)
+			}
+			for _, item := range contents.Items {
+				item.Playlist = pl.ID
+				items = append(items, item)
+			}
+		}
+		return nil
+	}
+	if err := walk(playlists); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// triggerPlaylistItem finds a media or audio item by UUID or name and triggers it
+func (c *Client) triggerPlaylistItem(kind, id string) error {
+	items, err := c.playlistItems(kind)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.ID.UUID == id || strings.EqualFold(item.ID.Name, id) {
+			endpoint := fmt.Sprintf("/v1/%s/playlist/%s/%s/trigger", kind, url.PathEscape(item.Playlist.UUID), url.PathEscape(item.ID.UUID))
+			return c.trigger(endpoint, kind)
+		}
+	}
+	return fmt.Errorf("%s item not found: %s", kind, id)
+}
+
+// getJSON fetches an API path and decodes the response into v
+func (c *Client) getJSON(path string, v interface{}) error {
+	if !c.enabled {
+		return fmt.Errorf("ProPresenter integration is not enabled")
+	}
+
+	resp, err := c.httpClient.Get(c.baseURL + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// trigger calls a trigger endpoint; what names the thing for error messages
+func (c *Client) trigger(path, what string) error {
+	if !c.enabled {
+		return fmt.Errorf("ProPresenter integration is not enabled")
+	}
+
+	resp, err := c.httpClient.Get(c.baseURL + path)
+	if err != nil {
+		return fmt.Errorf("failed to trigger %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to trigger %s, status %d: %s", what, resp.StatusCode, string(body))
+	}
+	return nil
+}
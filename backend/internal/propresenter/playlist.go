@@ -0,0 +1,30 @@
+package propresenter
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetPlaylist fetches a playlist with its items, by UUID
+func (c *Client) GetPlaylist(uuid string) (*Playlist, error) {
+	var playlist Playlist
+	if err := c.getJSON("/v1/playlist/"+url.PathEscape(uuid), &playlist); err != nil {
+		return nil, fmt.Errorf("failed to fetch playlist: %w", err)
+	}
+	return &playlist, nil
+}
+
+// FindPlaylist finds a playlist by name without creating it
+func (c *Client) FindPlaylist(name string) (*Playlist, error) {
+	playlists, err := c.GetPlaylists()
+	if err != nil {
+		return nil, err
+	}
+	for _, pl := range playlists {
+		if strings.EqualFold(strings.TrimSpace(pl.ID.Name), strings.TrimSpace(name)) {
+			return &pl, nil
+		}
+	}
+	return nil, fmt.Errorf("playlist not found: %s", name)
+}
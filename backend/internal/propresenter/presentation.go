@@ -0,0 +1,182 @@
+package propresenter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/lyrics"
+)
+
+// presentationResponse wraps the presentation returned by GET /v1/presentation/{uuid}
+type presentationResponse struct {
+	Presentation Presentation `json:"presentation"`
+}
+
+// GetPresentation fetches a presentation with its groups and slides
+func (c *Client) GetPresentation(uuid string) (*Presentation, error) {
+	if !c.enabled {
+		return nil, fmt.Errorf("ProPresenter integration is not enabled")
+	}
+
+	resp, err := c.httpClient.Get(c.baseURL + "/v1/presentation/" + url.PathEscape(uuid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presentation: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("presentation not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get presentation, status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result presentationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode presentation: %w", err)
+	}
+	return &result.Presentation, nil
+}
+
+// GroupSlideIndex returns the index of the first slide of the named group.
+// Names match the way section labels do, so "chorus" finds "Chorus"; a name
+// without a number ("Verse") falls back to the first numbered group ("Verse 1").
+func (p *Presentation) GroupSlideIndex(name string) (int, *SlideGroup, error) {
+	want := lyrics.NormalizeLabel(name)
+	if want == "" {
+		return 0, nil, fmt.Errorf("slide group not found")
+	}
+
+	match := -1
+	for i, group := range p.Groups {
+		got := lyrics.NormalizeLabel(group.Name)
+		if got == want {
+			match = i
+			break
+		}
+		if match < 0 && strings.HasPrefix(got, want+" ") {
+			match = i
+		}
+	}
+	if match < 0 || len(p.Groups[match].Slides) == 0 {
+		return 0, nil, fmt.Errorf("slide group not found")
+	}
+
+	// Slides are numbered across the whole presentation
+	index := 0
+	for _, group := range p.Groups[:match] {
+		index += len(group.Slides)
+	}
+	return index, &p.Groups[match], nil
+}
+
+// TriggerGroup triggers the first slide of a presentation's slide group and
+// returns its index
+func (c *Client) TriggerGroup(uuid, name string) (int, *SlideGroup, error) {
+	presentation, err := c.GetPresentation(uuid)
+	if err != nil {
+		return 0, nil, err
+	}
+	index, group, err := presentation.GroupSlideIndex(name)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := c.TriggerPresentationSlide(uuid, index); err != nil {
+		return 0, nil, err
+	}
+	return index, group, nil
+}
+
+// PresentationDetail is a presentation laid out for the UI, with every
+// slide numbered the way TriggerPresentationSlide counts them
+type PresentationDetail struct {
+	UUID       string        `json:"uuid"`
+	Name       string        `json:"name"`
+	SlideCount int           `json:"slide_count"`
+	Groups     []GroupDetail `json:"groups"`
+}
+
+// GroupDetail is one slide group of a PresentationDetail
+type GroupDetail struct {
+	Name       string        `json:"name"`
+	Label      string        `json:"label"` // normalized like song section labels, for matching
+	Color      string        `json:"color,omitempty"`
+	SlideIndex int           `json:"slide_index"` // index of the group's first slide
+	SlideCount int           `json:"slide_count"`
+	Slides     []SlideDetail `json:"slides"`
+}
+
+// SlideDetail is one slide of a GroupDetail
+type SlideDetail struct {
+	Index   int    `json:"index"`
+	Text    string `json:"text"`
+	Notes   string `json:"notes,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Detail numbers the presentation's slides and counts them per group
+func (p *Presentation) Detail() *PresentationDetail {
+	detail := &PresentationDetail{
+		UUID:   p.ID.UUID,
+		Name:   p.ID.Name,
+		Groups: make([]GroupDetail, 0, len(p.Groups)),
+	}
+	for _, group := range p.Groups {
+		g := GroupDetail{
+			Name:       group.Name,
+			Label:      lyrics.NormalizeLabel(group.Name),
+			Color:      group.Color,
+			SlideIndex: detail.SlideCount,
+			SlideCount: len(group.Slides),
+			Slides:     make([]SlideDetail, 0, len(group.Slides)),
+		}
+		for _, slide := range group.Slides {
+			g.Slides = append(g.Slides, SlideDetail{
+				Index:   detail.SlideCount,
+				Text:    slide.Text,
+				Notes:   slide.Notes,
+				Enabled: slide.Enabled,
+			})
+			detail.SlideCount++
+		}
+		detail.Groups = append(detail.Groups, g)
+	}
+	return detail
+}
+
+// Lyrics rebuilds song lyrics from the presentation's slide groups, the
+// reverse of CreatePresentation: each group becomes a stanza with the lines of
+// its slides in order, headed by the group's name when that is a section label
+// (Verse 1, Chorus). The lines of the attribution slide are returned apart.
+func (p *Presentation) Lyrics() (text string, attribution []string) {
+	stanzas := make([]string, 0, len(p.Groups))
+	for _, group := range p.Groups {
+		lines := make([]string, 0)
+		for _, slide := range group.Slides {
+			slideText := strings.ReplaceAll(slide.Text, "\r\n", "\n")
+			for _, line := range strings.Split(strings.ReplaceAll(slideText, "\r", "\n"), "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					lines = append(lines, line)
+				}
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		name := strings.TrimSpace(group.Name)
+		if strings.EqualFold(name, lyrics.CopyrightLabel) {
+			attribution = lines
+			continue
+		}
+		if lyrics.IsSectionLabel(name) {
+			lines = append([]string{name}, lines...)
+		}
+		stanzas = append(stanzas, strings.Join(lines, "\n"))
+	}
+	return strings.Join(stanzas, "\n\n"), attribution
+}
@@ -0,0 +1,322 @@
+package propresenter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ProPresenter streams status changes from POST /v1/status/updates: the body
+// lists the endpoints to follow and the response is a chunked stream of
+// {"url": ..., "data": ...} messages, one each time one of them changes. The
+// listener keeps the last value of each in the client, so the UI can ask what
+// is on screen without a round trip to the presentation machine, and an open
+// stream stands in for the periodic /v1/status ping.
+
+// statusEndpoints are the status endpoints the listener follows
+var statusEndpoints = []string{"presentation/active", "presentation/slide_index", "playlist/active"}
+
+// statusRetryDelay is the first wait before reconnecting a dropped stream;
+// it doubles up to statusMaxRetryDelay while ProPresenter stays away
+const (
+	statusRetryDelay    = 2 * time.Second
+	statusMaxRetryDelay = time.Minute
+)
+
+// LiveStatus is what ProPresenter reported last through the status stream
+type LiveStatus struct {
+	Streaming    bool            `json:"streaming"` // whether the status stream is open
+	Presentation *PresentationID `json:"presentation,omitempty"`
+	SlideIndex   *int            `json:"slide_index,omitempty"`
+	Playlist     *PlaylistID     `json:"playlist,omitempty"`
+	PlaylistItem *PlaylistItemID `json:"playlist_item,omitempty"`
+	UpdatedAt    *time.Time      `json:"updated_at,omitempty"`
+	Error        string          `json:"error,omitempty"` // why the stream last closed
+}
+
+// statusListener holds the streamed state. It has its own lock so reading it
+// never waits on Client.mu.
+type statusListener struct {
+	mu      sync.Mutex
+	status  LiveStatus
+	running bool
+	restart chan struct{} // closes the current stream, e.g. after Reconfigure
+	client  *http.Client  // without a timeout; the stream stays open
+}
+
+func newStatusListener(transport http.RoundTripper) *statusListener {
+	return &statusListener{
+		restart: make(chan struct{}, 1),
+		client:  &http.Client{Transport: transport},
+	}
+}
+
+// statusUpdate is one message of the status stream
+type statusUpdate struct {
+	URL  string          `json:"url"`
+	Data json.RawMessage `json:"data"`
+}
+
+// StartStatusListener follows ProPresenter's status stream until ctx is done,
+// reconnecting whenever it drops. It runs while the integration is disabled
+// too, waiting until Reconfigure enables it. Starting it twice has no effect.
+func (c *Client) StartStatusListener(ctx context.Context) {
+	l := c.status
+	l.mu.Lock()
+	if l.running {
+		l.mu.Unlock()
+		return
+	}
+	l.running = true
+	l.mu.Unlock()
+
+	go func() {
+		defer func() {
+			l.mu.Lock()
+			l.running = false
+			l.status.Streaming = false
+			l.mu.Unlock()
+		}()
+
+		delay := statusRetryDelay
+		for {
+			c.mu.RLock()
+			enabled, baseURL := c.enabled, c.baseURL
+			c.mu.RUnlock()
+
+			if enabled {
+				opened, err := c.streamStatus(ctx, baseURL)
+				if ctx.Err() != nil {
+					return
+				}
+				if opened {
+					delay = statusRetryDelay
+				}
+				if err != nil {
+					l.mu.Lock()
+					l.status.Error = err.Error()
+					l.mu.Unlock()
+				}
+				// The stream was standing in for the ping; find out now
+				// whether ProPresenter went away or only the stream did
+				go c.refreshConnection()
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-l.restart:
+				timer.Stop()
+				delay = statusRetryDelay
+			case <-timer.C:
+				if delay *= 2; delay > statusMaxRetryDelay {
+					delay = statusMaxRetryDelay
+				}
+			}
+		}
+	}()
+}
+
+// streamStatus reads the status stream from baseURL until it closes, ctx is
+// done or the listener is restarted. opened reports whether ProPresenter
+// accepted the stream.
+func (c *Client) streamStatus(ctx context.Context, baseURL string) (opened bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	l := c.status
+	// A restart requested before this stream opened is already served by it
+	select {
+	case <-l.restart:
+	default:
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-l.restart:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	body, _ := json.Marshal(statusEndpoints)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/status/updates", bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("status stream not reachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("status stream returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	l.mu.Lock()
+	l.status = LiveStatus{Streaming: true}
+	l.mu.Unlock()
+	c.markStreamConnected(baseURL)
+	log.Printf("📡 ProPresenter status stream open: %s", baseURL)
+
+	defer func() {
+		l.mu.Lock()
+		l.status.Streaming = false
+		l.mu.Unlock()
+	}()
+
+	// Messages are separated by blank lines, which the decoder skips
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var update statusUpdate
+		if err := decoder.Decode(&update); err != nil {
+			if ctx.Err() != nil {
+				return true, nil
+			}
+			if err == io.EOF {
+				return true, fmt.Errorf("status stream closed by ProPresenter")
+			}
+			return true, fmt.Errorf("status stream broken: %w", err)
+		}
+		l.apply(update)
+	}
+}
+
+// apply records one status message; unknown endpoints are ignored
+func (l *statusListener) apply(update statusUpdate) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch update.URL {
+	case "presentation/active":
+		var data struct {
+			Presentation *struct {
+				ID PresentationID `json:"id"`
+			} `json:"presentation"`
+		}
+		if json.Unmarshal(update.Data, &data) != nil {
+			return
+		}
+		if data.Presentation == nil || data.Presentation.ID.UUID == "" {
+			l.status.Presentation = nil
+			l.status.SlideIndex = nil
+		} else {
+			id := data.Presentation.ID
+			if l.status.Presentation == nil || l.status.Presentation.UUID != id.UUID {
+				l.status.SlideIndex = nil
+			}
+			l.status.Presentation = &id
+		}
+
+	case "presentation/slide_index":
+		var data struct {
+			PresentationIndex *struct {
+				Index          int             `json:"index"`
+				PresentationID *PresentationID `json:"presentation_id"`
+			} `json:"presentation_index"`
+		}
+		if json.Unmarshal(update.Data, &data) != nil {
+			return
+		}
+		if data.PresentationIndex == nil {
+			l.status.SlideIndex = nil
+			break
+		}
+		index := data.PresentationIndex.Index
+		l.status.SlideIndex = &index
+		if id := data.PresentationIndex.PresentationID; id != nil && id.UUID != "" {
+			l.status.Presentation = id
+		}
+
+	case "playlist/active":
+		var data struct {
+			Presentation *struct {
+				Playlist *PlaylistID     `json:"playlist"`
+				Item     *PlaylistItemID `json:"item"`
+			} `json:"presentation"`
+		}
+		if json.Unmarshal(update.Data, &data) != nil {
+			return
+		}
+		if data.Presentation == nil {
+			l.status.Playlist = nil
+			l.status.PlaylistItem = nil
+		} else {
+			l.status.Playlist = data.Presentation.Playlist
+			l.status.PlaylistItem = data.Presentation.Item
+		}
+
+	default:
+		return
+	}
+
+	now := time.Now()
+	l.status.UpdatedAt = &now
+}
+
+// markStreamConnected records that ProPresenter answered at baseURL
+func (c *Client) markStreamConnected(baseURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.baseURL != baseURL || !c.enabled {
+		return
+	}
+	if !c.connected {
+		log.Printf("✅ ProPresenter connected: %s", baseURL)
+	}
+	c.setConnectedLocked(true)
+	c.lastCheck = time.Now()
+}
+
+// streaming reports whether the status stream is open
+func (c *Client) streaming() bool {
+	c.status.mu.Lock()
+	defer c.status.mu.Unlock()
+	return c.status.status.Streaming
+}
+
+// restartStatusStream closes the current stream so the listener reconnects
+// with the current configuration
+func (c *Client) restartStatusStream() {
+	select {
+	case c.status.restart <- struct{}{}:
+	default:
+	}
+}
+
+// LiveStatus returns what ProPresenter last reported through the status stream
+func (c *Client) LiveStatus() LiveStatus {
+	c.status.mu.Lock()
+	defer c.status.mu.Unlock()
+	status := c.status.status
+	if status.Presentation != nil {
+		id := *status.Presentation
+		status.Presentation = &id
+	}
+	if status.SlideIndex != nil {
+		index := *status.SlideIndex
+		status.SlideIndex = &index
+	}
+	if status.Playlist != nil {
+		id := *status.Playlist
+		status.Playlist = &id
+	}
+	if status.PlaylistItem != nil {
+		id := *status.PlaylistItem
+		status.PlaylistItem = &id
+	}
+	return status
+}
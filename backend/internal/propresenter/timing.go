@@ -0,0 +1,32 @@
+package propresenter
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/slowlog"
+)
+
+// timedTransport times every request to ProPresenter for the slow call log
+type timedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *timedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	defer slowlog.Observe(slowlog.ProPresenter, req.Method+" "+endpoint(req.URL.Path), time.Now())
+	return t.next.RoundTrip(req)
+}
+
+// endpoint replaces the IDs in a path with :id so calls to the same endpoint
+// are counted together
+func endpoint(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.IndexFunc(part, unicode.IsDigit) >= 0 && (len(part) >= 8 || strings.Trim(part, "0123456789") == "") {
+			parts[i] = ":id"
+		}
+	}
+	return strings.Join(parts, "/")
+}
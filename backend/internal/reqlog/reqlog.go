@@ -0,0 +1,79 @@
+// Package reqlog logs HTTP requests at a level that can be changed while the
+// server runs
+package reqlog
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Levels, most verbose first
+const (
+	Debug = "debug" // every request, with its query string and client IP
+	Info  = "info"  // every request
+	Warn  = "warn"  // requests that failed (status 400 and up)
+	Error = "error" // server errors only (status 500 and up)
+)
+
+var levels = []string{Debug, Info, Warn, Error}
+
+var level atomic.Int32 // index into levels
+
+func init() {
+	SetLevel(Info)
+}
+
+// ValidLevel reports whether name is one of the levels
+func ValidLevel(name string) bool {
+	return indexOf(name) >= 0
+}
+
+func indexOf(name string) int {
+	for i, l := range levels {
+		if l == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetLevel changes which requests are logged; unknown levels are ignored
+func SetLevel(name string) {
+	if i := indexOf(name); i >= 0 {
+		level.Store(int32(i))
+	}
+}
+
+// Level returns the current level
+func Level() string {
+	return levels[level.Load()]
+}
+
+// Middleware logs each request once it has been handled. Errors returned by
+// the handlers go to the app's error handler first so the logged status is
+// the one sent.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		current := levels[level.Load()]
+		switch {
+		case current == Error && status < 500, current == Warn && status < 400:
+			return nil
+		case current == Debug:
+			log.Printf("%d - %v %s %s %s", status, time.Since(start).Round(time.Microsecond), c.Method(), c.OriginalURL(), c.IP())
+		default:
+			log.Printf("%d - %v %s %s", status, time.Since(start).Round(time.Microsecond), c.Method(), c.Path())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,385 @@
+// Package s3 copies backups to an S3-compatible bucket: AWS S3, MinIO,
+// Backblaze B2, Wasabi and the like. It talks to the S3 REST API directly,
+// signing requests with AWS Signature Version 4.
+//
+// Each backup is sent in a single PUT, which S3 allows for objects up to
+// 5 GB; a lyrics library's dump is far below that.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/backup"
+)
+
+// TargetName identifies S3 copies in the backups API
+const TargetName = "s3"
+
+// DefaultRegion is used when no region is configured; MinIO accepts it too
+const DefaultRegion = "us-east-1"
+
+// Config says where backups go
+type Config struct {
+	Bucket          string
+	Endpoint        string // e.g. "http://minio:9000"; empty means AWS
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Prefix          string // key prefix, e.g. "teleprompter/"
+	PathStyle       bool   // bucket in the path rather than the host name, as MinIO needs
+}
+
+// EnvConfig reads the S3_* variables. ok is false when S3_BUCKET isn't set,
+// meaning no S3 copies are wanted. Path-style addressing is the default with
+// a custom endpoint, since MinIO needs it; S3_PATH_STYLE overrides it.
+func EnvConfig() (config Config, ok bool) {
+	config = Config{
+		Bucket:          os.Getenv("S3_BUCKET"),
+		Endpoint:        os.Getenv("S3_ENDPOINT"),
+		Region:          os.Getenv("S3_REGION"),
+		AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		Prefix:          os.Getenv("S3_PREFIX"),
+	}
+	config.PathStyle = config.Endpoint != ""
+	if v := os.Getenv("S3_PATH_STYLE"); v != "" {
+		config.PathStyle = v == "true"
+	}
+	if config.Prefix != "" && !strings.HasSuffix(config.Prefix, "/") {
+		config.Prefix += "/"
+	}
+	return config, config.Bucket != ""
+}
+
+// Client uploads backups to one bucket
+type Client struct {
+	config     Config
+	base       *url.URL // endpoint the bucket is reached at
+	httpClient *http.Client
+}
+
+// New checks the configuration and that the bucket can be reached
+func New(config Config) (*Client, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 access key ID and secret access key are required")
+	}
+	if config.Region == "" {
+		config.Region = DefaultRegion
+	}
+
+	var base *url.URL
+	if config.Endpoint == "" {
+		host := "s3." + config.Region + ".amazonaws.com"
+		if !config.PathStyle {
+			host = config.Bucket + "." + host
+		}
+		base = &url.URL{Scheme: "https", Host: host}
+	} else {
+		endpoint := config.Endpoint
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "https://" + endpoint
+		}
+		parsed, err := url.Parse(endpoint)
+		if err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+		}
+		base = &url.URL{Scheme: parsed.Scheme, Host: parsed.Host}
+		if !config.PathStyle {
+			base.Host = config.Bucket + "." + base.Host
+		}
+	}
+
+	c := &Client{config: config, base: base, httpClient: &http.Client{Timeout: 2 * time.Minute}}
+	if err := c.checkBucket(context.Background()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Name implements backup.Target
+func (c *Client) Name() string {
+	return TargetName
+}
+
+// objectURL is the URL of a key in the bucket (the bucket itself for "")
+func (c *Client) objectURL(key string, query url.Values) *url.URL {
+	u := *c.base
+	path := "/"
+	if c.config.PathStyle {
+		path += c.config.Bucket + "/"
+	}
+	u.Path = path + key
+	u.RawPath = uriEncode(path, false) + uriEncode(key, false)
+	if query != nil {
+		u.RawQuery = canonicalQuery(query)
+	}
+	return &u
+}
+
+// apiError is S3's XML error response
+type apiError struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func errorFrom(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	var e apiError
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("s3 %d: %s: %s", resp.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Errorf("s3 %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// do signs and sends a request whose body has the given SHA-256, returning
+// the response for a 2xx status
+func (c *Client) do(req *http.Request, payloadHash string, client *http.Client) (*http.Response, error) {
+	c.sign(req, payloadHash, time.Now().UTC())
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, errorFrom(resp)
+	}
+	return resp, nil
+}
+
+// emptyHash is the SHA-256 of an empty body
+var emptyHash = hex.EncodeToString(sha256.New().Sum(nil))
+
+// checkBucket makes sure the bucket exists and the keys can list it
+func (c *Client) checkBucket(ctx context.Context) error {
+	query := url.Values{"list-type": {"2"}, "max-keys": {"1"}, "prefix": {c.config.Prefix}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.objectURL("", query).String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, emptyHash, c.httpClient)
+	if err != nil {
+		return fmt.Errorf("s3 bucket not reachable: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Upload copies a file into the bucket under the prefix
+func (c *Client) Upload(ctx context.Context, path string) (*backup.RemoteBackup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature covers the body, so hash it first and rewind
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return nil, fmt.Errorf("error reading backup: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	key := c.config.Prefix + filepath.Base(path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.objectURL(key, nil).String(), f)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	// The client timeout is too short for big dumps; ctx bounds the upload
+	resp, err := c.do(req, hex.EncodeToString(hash.Sum(nil)), &http.Client{Transport: c.httpClient.Transport})
+	if err != nil {
+		return nil, fmt.Errorf("error uploading to s3: %w", err)
+	}
+	resp.Body.Close()
+
+	return &backup.RemoteBackup{
+		Target:    TargetName,
+		ID:        key,
+		Name:      filepath.Base(path),
+		SizeBytes: info.Size(),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// listResult is the part of a ListObjectsV2 response we use
+type listResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the backups under the prefix, newest first
+func (c *Client) List(ctx context.Context) ([]backup.RemoteBackup, error) {
+	backups := make([]backup.RemoteBackup, 0)
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {c.config.Prefix + "backup_"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.objectURL("", query).String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.do(req, emptyHash, c.httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("error listing s3 backups: %w", err)
+		}
+		var page listResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding s3 listing: %w", err)
+		}
+
+		for _, object := range page.Contents {
+			backups = append(backups, backup.RemoteBackup{
+				Target:    TargetName,
+				ID:        object.Key,
+				Name:      strings.TrimPrefix(object.Key, c.config.Prefix),
+				SizeBytes: object.Size,
+				CreatedAt: object.LastModified,
+			})
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// Delete removes a backup from the bucket; id is its key
+func (c *Client) Delete(ctx context.Context, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.objectURL(id, nil).String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, emptyHash, c.httpClient)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// sign adds AWS Signature Version 4 headers to a request
+func (c *Client) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders bytes.Buffer
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.config.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.config.SecretAccessKey), date)
+	key = hmacSHA256(key, c.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes a query string the way Signature Version 4 expects:
+// sorted by name, with every reserved character percent-encoded
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and '/'
+// unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch >= 'A' && ch <= 'Z', ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~':
+			b.WriteByte(ch)
+		case ch == '/' && !encodeSlash:
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,221 @@
+package scripture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for the passage API. Any service answering in the bible-api.com
+// format can be used by pointing SCRIPTURE_API_URL at it.
+const (
+	DefaultAPIURL      = "https://bible-api.com"
+	DefaultTranslation = "kjv"
+)
+
+// DefaultMaxChars is the slide length used when none is given; about four
+// lines on a typical lower-third
+const DefaultMaxChars = 300
+
+// maxCached bounds the passage cache; it is cleared when full
+const maxCached = 200
+
+// ErrNotFound is returned when the API doesn't recognise a reference
+var ErrNotFound = errors.New("passage not found")
+
+// Verse is one verse of a passage
+type Verse struct {
+	Book    string `json:"book"`
+	Chapter int    `json:"chapter"`
+	Verse   int    `json:"verse"`
+	Text    string `json:"text"`
+}
+
+// Passage is the text of a reference in one translation
+type Passage struct {
+	Reference   string  `json:"reference"`
+	Translation string  `json:"translation"`
+	Verses      []Verse `json:"verses"`
+}
+
+// Slide is one screen of a passage. Reference covers just the verses on it.
+type Slide struct {
+	Index     int    `json:"index"`
+	Reference string `json:"reference"`
+	Text      string `json:"text"`
+}
+
+// Client fetches passages from the configured API. Passages don't change, so
+// they are cached for the life of the process.
+type Client struct {
+	baseURL     string
+	translation string
+	httpClient  *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*Passage
+}
+
+// New creates a scripture client; empty arguments use the defaults
+func New(baseURL, translation string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	if translation == "" {
+		translation = DefaultTranslation
+	}
+	return &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		translation: strings.ToLower(translation),
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		cache:       make(map[string]*Passage),
+	}
+}
+
+// Translation returns the translation used when a request doesn't name one
+func (c *Client) Translation() string {
+	return c.translation
+}
+
+// apiResponse is the bible-api.com passage format
+type apiResponse struct {
+	Reference     string `json:"reference"`
+	TranslationID string `json:"translation_id"`
+	Verses        []struct {
+		BookName string `json:"book_name"`
+		Chapter  int    `json:"chapter"`
+		Verse    int    `json:"verse"`
+		Text     string `json:"text"`
+	} `json:"verses"`
+	Error string `json:"error"`
+}
+
+// Fetch looks up a reference such as "John 3:16-18" or "Psalm 23". An empty
+// translation uses the client's default.
+func (c *Client) Fetch(reference, translation string) (*Passage, error) {
+	reference = strings.Join(strings.Fields(reference), " ")
+	if reference == "" {
+		return nil, fmt.Errorf("reference is required")
+	}
+	if translation == "" {
+		translation = c.translation
+	}
+	translation = strings.ToLower(translation)
+
+	key := translation + "|" + strings.ToLower(reference)
+	c.mu.Lock()
+	cached := c.cache[key]
+	c.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/%s?translation=%s", c.baseURL, url.PathEscape(reference), url.QueryEscape(translation))
+	resp, err := c.httpClient.Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch passage: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("scripture API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode passage: %w", err)
+	}
+	if data.Error != "" || len(data.Verses) == 0 {
+		return nil, ErrNotFound
+	}
+
+	passage := &Passage{
+		Reference:   data.Reference,
+		Translation: strings.ToUpper(translation),
+		Verses:      make([]Verse, 0, len(data.Verses)),
+	}
+	for _, v := range data.Verses {
+		passage.Verses = append(passage.Verses, Verse{
+			Book:    v.BookName,
+			Chapter: v.Chapter,
+			Verse:   v.Verse,
+			Text:    strings.Join(strings.Fields(v.Text), " "),
+		})
+	}
+
+	c.mu.Lock()
+	if len(c.cache) >= maxCached {
+		c.cache = make(map[string]*Passage)
+	}
+	c.cache[key] = passage
+	c.mu.Unlock()
+
+	return passage, nil
+}
+
+// Slides splits a passage into slides of at most maxChars characters, keeping
+// verses whole. Each verse starts with its number; a verse longer than
+// maxChars gets a slide of its own.
+func Slides(p *Passage, maxChars int) []Slide {
+	if maxChars <= 0 {
+		maxChars = DefaultMaxChars
+	}
+
+	slides := make([]Slide, 0)
+	var verses []Verse
+	var text strings.Builder
+
+	flush := func() {
+		if len(verses) == 0 {
+			return
+		}
+		slides = append(slides, Slide{
+			Index:     len(slides),
+			Reference: reference(verses),
+			Text:      text.String(),
+		})
+		verses = nil
+		text.Reset()
+	}
+
+	for _, v := range p.Verses {
+		line := fmt.Sprintf("%d %s", v.Verse, v.Text)
+		if len(verses) > 0 && len([]rune(text.String()))+1+len([]rune(line)) > maxChars {
+			flush()
+		}
+		if len(verses) > 0 {
+			text.WriteString(" ")
+		}
+		text.WriteString(line)
+		verses = append(verses, v)
+	}
+	flush()
+
+	return slides
+}
+
+// reference formats a run of verses as "John 3:16-18", "John 3:36-4:2" or
+// "John 3:16"
+func reference(verses []Verse) string {
+	first, last := verses[0], verses[len(verses)-1]
+	ref := fmt.Sprintf("%s %d:%d", first.Book, first.Chapter, first.Verse)
+	switch {
+	case last.Book != first.Book:
+		ref += fmt.Sprintf(" - %s %d:%d", last.Book, last.Chapter, last.Verse)
+	case last.Chapter != first.Chapter:
+		ref += fmt.Sprintf("-%d:%d", last.Chapter, last.Verse)
+	case last.Verse != first.Verse:
+		ref += fmt.Sprintf("-%d", last.Verse)
+	}
+	return ref
+}
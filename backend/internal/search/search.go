@@ -0,0 +1,293 @@
+// Package search defines the interface the handlers use for full-text song
+// search, so the engine behind it (Typesense, Meilisearch, the embedded
+// index) is a deployment choice. Songs are always read from the database;
+// the search engine only holds documents for finding them.
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+)
+
+// Backend is a search engine holding one document per active song
+type Backend interface {
+	// CheckReady verifies the engine is reachable and its index exists
+	CheckReady(ctx context.Context) error
+	// Ready reports whether the index can be used
+	Ready() bool
+	// Stale reports whether songs changed while the engine was unavailable
+	// and have not been reindexed yet
+	Stale() bool
+	// OnReady registers fn to run when a degraded backend becomes ready
+	OnReady(fn func(stale bool))
+
+	// IndexSong upserts a song's document; archived songs are removed instead
+	IndexSong(song *models.Song) error
+	// IndexSongs upserts many songs in batches
+	IndexSongs(songs []models.Song) error
+	DeleteSong(id string) error
+	// Reindex drops and rebuilds the index, reporting progress batch by batch
+	Reindex(songs []models.Song, progress ReindexProgress) error
+	// ReindexAll is Reindex, returning the first song that failed
+	ReindexAll(songs []models.Song) error
+
+	Search(query string, languages []string) (*Result, error)
+	// IndexedSongs lists every document in the index
+	IndexedSongs() ([]IndexedSong, error)
+	Stats() (*IndexStats, error)
+}
+
+// Tokenization controls how song text is split into words. Backends whose
+// tokenization is configurable implement Tokenizer.
+type Tokenization struct {
+	// FieldLocales sets the locale of text fields (title, artist, lyrics,
+	// content), e.g. {"lyrics": "ml"}; unset fields use the default tokenizer
+	FieldLocales map[string]string
+	// TokenSeparators are characters that split words besides whitespace
+	TokenSeparators string
+}
+
+// TextFields are the document fields a locale can be set on
+var TextFields = []string{"title", "artist", "lyrics", "content"}
+
+// Validate checks the field names and that locales look like ISO 639-1 codes
+func (t Tokenization) Validate() error {
+	for field, locale := range t.FieldLocales {
+		known := false
+		for _, f := range TextFields {
+			known = known || f == field
+		}
+		if !known {
+			return fmt.Errorf("unknown search field %q, expected one of %s", field, strings.Join(TextFields, ", "))
+		}
+		if locale != "" && (len(locale) != 2 || strings.Trim(locale, "abcdefghijklmnopqrstuvwxyz") != "") {
+			return fmt.Errorf("locale %q for %s is not a two-letter language code", locale, field)
+		}
+	}
+	for _, r := range t.TokenSeparators {
+		if r > 127 || !unicode.IsPunct(r) && !unicode.IsSymbol(r) {
+			return fmt.Errorf("token separators must be ASCII punctuation, got %q", r)
+		}
+	}
+	return nil
+}
+
+// Tokenizer is implemented by backends whose tokenization is configurable
+type Tokenizer interface {
+	// SetTokenization applies t to collections created from now on, and
+	// reports whether the existing index was built differently and has to be
+	// rebuilt for t to take effect. While the backend is unavailable it only
+	// stores t.
+	SetTokenization(ctx context.Context, t Tokenization) (bool, error)
+}
+
+// Result is a page of search hits. Songs carry only what the index stores.
+type Result struct {
+	Songs      []models.Song `json:"songs"`
+	TotalFound int           `json:"total_found"`
+	SearchTime int           `json:"search_time_ms"`
+}
+
+// IndexedSong is the part of a search document needed to tell whether it is
+// in step with the database
+type IndexedSong struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// IndexStats describes the size and freshness of the index
+type IndexStats struct {
+	Documents     int64      `json:"documents"`
+	LastIndexedAt *time.Time `json:"last_indexed_at,omitempty"` // updated_at of the newest document
+}
+
+// ReindexProgress receives progress updates from Reindex. Batches may be
+// indexed concurrently, so implementations must be safe for concurrent use.
+type ReindexProgress interface {
+	SetTotal(total, batches int)
+	StartBatch(batch int)
+	Done(n int)
+	Fail(err error)
+}
+
+// Document builds the search document for a song
+func Document(song *models.Song) map[string]interface{} {
+	doc := map[string]interface{}{
+		"id":         song.ID,
+		"title":      song.Title,
+		"lyrics":     song.DisplayLyrics,
+		"language":   song.Language,
+		"content":    song.MusicMinistryLyrics,
+		"updated_at": song.UpdatedAt.Unix(),
+	}
+
+	if song.Artist != nil {
+		doc["artist"] = *song.Artist
+	}
+
+	return doc
+}
+
+// SongFromDocument is the reverse of Document, for search hits
+func SongFromDocument(doc map[string]interface{}) models.Song {
+	song := models.Song{
+		Library:   "",         // Not stored in the index, will be empty
+		CreatedAt: time.Now(), // Not stored in the index, using current time as default
+	}
+	song.ID, _ = doc["id"].(string)
+	song.Title, _ = doc["title"].(string)
+	song.DisplayLyrics, _ = doc["lyrics"].(string)
+	song.Language, _ = doc["language"].(string)
+	song.MusicMinistryLyrics, _ = doc["content"].(string)
+
+	if artist, ok := doc["artist"].(string); ok {
+		song.Artist = &artist
+	}
+
+	if updatedAt, ok := doc["updated_at"].(float64); ok {
+		song.UpdatedAt = time.Unix(int64(updatedAt), 0)
+	} else {
+		song.UpdatedAt = time.Now()
+	}
+
+	return song
+}
+
+// Searchable drops archived songs, which are kept out of the index
+func Searchable(songs []models.Song) []models.Song {
+	for i := range songs {
+		if songs[i].ArchivedAt == nil {
+			continue
+		}
+		kept := make([]models.Song, 0, len(songs))
+		for _, song := range songs {
+			if song.ArchivedAt == nil {
+				kept = append(kept, song)
+			}
+		}
+		return kept
+	}
+	return songs
+}
+
+// Counter is the progress sink for synchronous reindexes
+type Counter struct {
+	mu     sync.Mutex
+	failed int
+	first  error
+}
+
+func (r *Counter) SetTotal(total, batches int) {}
+func (r *Counter) StartBatch(batch int)        {}
+func (r *Counter) Done(n int)                  {}
+func (r *Counter) Fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failed == 0 {
+		r.first = err
+	}
+	r.failed++
+}
+
+// Err summarizes the failures, or is nil if every song was indexed
+func (r *Counter) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failed > 0 {
+		return fmt.Errorf("%d songs could not be indexed, first error: %w", r.failed, r.first)
+	}
+	return nil
+}
+
+// Retry delays while waiting for a search engine at startup
+const (
+	connectRetryMin = 5 * time.Second
+	connectRetryMax = time.Minute
+)
+
+// Availability tracks whether a backend's index is usable and whether writes
+// were skipped while it was not. Backends that start degraded embed it.
+type Availability struct {
+	mu      sync.Mutex
+	ready   bool
+	stale   bool
+	onReady func(stale bool)
+}
+
+// NewAvailability starts ready or degraded
+func NewAvailability(ready bool) *Availability {
+	return &Availability{ready: ready}
+}
+
+// Ready reports whether the index can be used
+func (a *Availability) Ready() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.ready
+}
+
+// Stale reports whether writes were skipped while degraded
+func (a *Availability) Stale() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stale
+}
+
+// OnReady registers fn to run when a degraded backend becomes ready. stale is
+// true if songs were changed meanwhile, meaning the index needs a rebuild.
+func (a *Availability) OnReady(fn func(stale bool)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onReady = fn
+}
+
+// Check returns unavailable while degraded. Writes pass write=true so a
+// skipped change marks the index stale.
+func (a *Availability) Check(write bool, unavailable error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ready {
+		return nil
+	}
+	if write {
+		a.stale = true
+	}
+	return unavailable
+}
+
+// Retry keeps calling connect with growing delays and switches to ready once
+// it succeeds. name is the engine's name for the log.
+func (a *Availability) Retry(name string, connect func() error) {
+	delay := connectRetryMin
+	for {
+		time.Sleep(delay)
+
+		if err := connect(); err != nil {
+			log.Printf("%s still unavailable (retrying in %v): %v", name, delay, err)
+			if delay *= 2; delay > connectRetryMax {
+				delay = connectRetryMax
+			}
+			continue
+		}
+
+		a.mu.Lock()
+		a.ready = true
+		stale, onReady := a.stale, a.onReady
+		a.stale = false
+		a.mu.Unlock()
+
+		log.Printf("✅ %s is available, search switched to %s", name, name)
+		if onReady != nil {
+			onReady(stale)
+		}
+		return
+	}
+}
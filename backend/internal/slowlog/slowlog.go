@@ -0,0 +1,139 @@
+// Package slowlog logs and counts database queries, search engine requests
+// and ProPresenter calls that take longer than a configured threshold, so a
+// degrading dependency shows up before a service rather than during one.
+package slowlog
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of calls that are timed
+const (
+	DB           = "db"
+	Typesense    = "typesense"
+	Meilisearch  = "meilisearch"
+	ProPresenter = "propresenter"
+)
+
+// Default thresholds; a threshold of 0 turns logging off for that kind
+var defaultThresholds = map[string]time.Duration{
+	DB:           500 * time.Millisecond,
+	Typesense:    500 * time.Millisecond,
+	Meilisearch:  500 * time.Millisecond,
+	ProPresenter: time.Second,
+}
+
+// maxRecent bounds the slow calls kept for the admin stats
+const maxRecent = 50
+
+// maxNameLen shortens long queries in logs and stats
+const maxNameLen = 120
+
+// Call is one slow call
+type Call struct {
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	DurationMS int64     `json:"duration_ms"`
+	At         time.Time `json:"at"`
+}
+
+// KindStats counts the slow calls of one kind since startup
+type KindStats struct {
+	ThresholdMS int64            `json:"threshold_ms"`
+	Count       int64            `json:"count"`
+	SlowestMS   int64            `json:"slowest_ms"`
+	ByName      map[string]int64 `json:"by_name"`
+}
+
+// Stats is a snapshot of the counters, with the latest slow calls first
+type Stats struct {
+	Kinds  map[string]KindStats `json:"kinds"`
+	Recent []Call               `json:"recent"`
+}
+
+var (
+	mu         sync.Mutex
+	thresholds = copyThresholds(defaultThresholds)
+	counts     = make(map[string]*KindStats)
+	recent     []Call
+)
+
+func copyThresholds(m map[string]time.Duration) map[string]time.Duration {
+	c := make(map[string]time.Duration, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// SetThreshold changes how long a call of a kind may take before it is logged
+func SetThreshold(kind string, threshold time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	thresholds[kind] = threshold
+}
+
+// Observe records a call that started at start. Use it as
+// defer slowlog.Observe(slowlog.DB, query, time.Now()).
+func Observe(kind, name string, start time.Time) {
+	elapsed := time.Since(start)
+
+	mu.Lock()
+	threshold := thresholds[kind]
+	if threshold <= 0 || elapsed < threshold {
+		mu.Unlock()
+		return
+	}
+	name = shorten(name)
+	stats, ok := counts[kind]
+	if !ok {
+		stats = &KindStats{ByName: make(map[string]int64)}
+		counts[kind] = stats
+	}
+	stats.Count++
+	stats.ByName[name]++
+	if ms := elapsed.Milliseconds(); ms > stats.SlowestMS {
+		stats.SlowestMS = ms
+	}
+	recent = append(recent, Call{Kind: kind, Name: name, DurationMS: elapsed.Milliseconds(), At: start})
+	if len(recent) > maxRecent {
+		recent = recent[len(recent)-maxRecent:]
+	}
+	mu.Unlock()
+
+	log.Printf("🐢 Slow %s call (%dms, threshold %dms): %s", kind, elapsed.Milliseconds(), threshold.Milliseconds(), name)
+}
+
+// Snapshot returns the counters for every kind with a threshold
+func Snapshot() Stats {
+	mu.Lock()
+	defer mu.Unlock()
+
+	stats := Stats{Kinds: make(map[string]KindStats), Recent: make([]Call, 0, len(recent))}
+	for kind, threshold := range thresholds {
+		s := KindStats{ThresholdMS: threshold.Milliseconds(), ByName: make(map[string]int64)}
+		if c, ok := counts[kind]; ok {
+			s.Count, s.SlowestMS = c.Count, c.SlowestMS
+			for name, n := range c.ByName {
+				s.ByName[name] = n
+			}
+		}
+		stats.Kinds[kind] = s
+	}
+	for i := len(recent) - 1; i >= 0; i-- {
+		stats.Recent = append(stats.Recent, recent[i])
+	}
+	return stats
+}
+
+// shorten collapses a query's whitespace and cuts it to maxNameLen
+func shorten(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if len(name) > maxNameLen {
+		name = name[:maxNameLen] + "…"
+	}
+	return name
+}
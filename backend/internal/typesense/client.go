@@ -2,31 +2,72 @@ package typesense
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/typesense/typesense-go/typesense"
 	"github.com/typesense/typesense-go/typesense/api"
 	"github.com/typesense/typesense-go/typesense/api/pointer"
 	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
+	"github.com/yourusername/audience-stage-teleprompter/internal/slowlog"
 )
 
+// Client is the Typesense search backend
 type Client struct {
-	client *typesense.Client
+	*search.Availability
+	client      *typesense.Client
+	importer    *typesense.Client // longer timeout, for batch imports
+	concurrency int
+	batchSize   int
+	perLanguage bool // one collection per language, see partition.go
+	partitions  partitions
+	tokenizer   tokenizer
 }
 
 const collectionName = "songs"
 
-func New(apiKey, host string) (*Client, error) {
-	client := typesense.NewClient(
+// searchLimit is how many hits a search returns
+const searchLimit = 50
+
+// defaultIndexConcurrency is how many import requests run at once during a
+// reindex or bulk import
+const defaultIndexConcurrency = 4
+
+// defaultIndexBatchSize is how many songs go into one import request
+const defaultIndexBatchSize = 500
+
+// Searches and single documents get a short timeout so a struggling
+// Typesense falls back to database search quickly; an import request of a
+// whole batch is given longer
+const (
+	requestTimeout = 5 * time.Second
+	importTimeout  = 2 * time.Minute
+)
+
+func newSDKClient(apiKey, host string, timeout time.Duration) *typesense.Client {
+	return typesense.NewClient(
 		typesense.WithServer(host),
 		typesense.WithAPIKey(apiKey),
-		typesense.WithConnectionTimeout(5*time.Second),
+		typesense.WithConnectionTimeout(timeout),
 	)
+}
 
-	tc := &Client{client: client}
+func New(apiKey, host string, perLanguage bool) (*Client, error) {
+	tc := &Client{
+		Availability: search.NewAvailability(true),
+		client:       newSDKClient(apiKey, host, requestTimeout),
+		importer:     newSDKClient(apiKey, host, importTimeout),
+		concurrency:  defaultIndexConcurrency,
+		batchSize:    defaultIndexBatchSize,
+		perLanguage:  perLanguage,
+	}
 
 	// Initialize schema
 	if err := tc.initSchema(); err != nil {
@@ -39,6 +80,10 @@ func New(apiKey, host string) (*Client, error) {
 
 func (c *Client) initSchema() error {
 	ctx := context.Background()
+	if c.perLanguage {
+		// Language collections are created as songs in each language are indexed
+		return c.refreshPartitions(ctx)
+	}
 
 	// Check if collection exists
 	_, err := c.client.Collection(collectionName).Retrieve(ctx)
@@ -47,9 +92,20 @@ func (c *Client) initSchema() error {
 		return nil
 	}
 
-	// Create collection
+	if err := c.createCollection(ctx, collectionName, ""); err != nil {
+		return err
+	}
+
+	log.Println("Typesense collection created successfully")
+	return nil
+}
+
+// createCollection creates a songs collection. Text fields are tokenized for
+// locale, or with the default tokenizer if it is empty, unless the
+// tokenization settings give a field its own locale.
+func (c *Client) createCollection(ctx context.Context, name, locale string) error {
 	schema := &api.CollectionSchema{
-		Name: collectionName,
+		Name: name,
 		Fields: []api.Field{
 			{
 				Name: "id",
@@ -85,62 +141,219 @@ func (c *Client) initSchema() error {
 		DefaultSortingField: pointer.String("updated_at"),
 	}
 
-	_, err = c.client.Collections().Create(ctx, schema)
-	if err != nil {
+	c.applyTokenization(schema, locale)
+
+	if _, err := c.client.Collections().Create(ctx, schema); err != nil {
 		return fmt.Errorf("error creating collection: %w", err)
 	}
+	return nil
+}
 
-	log.Println("Typesense collection created successfully")
+// CheckReady verifies Typesense is reachable and the songs collection exists
+func (c *Client) CheckReady(ctx context.Context) error {
+	if err := c.available(false); err != nil {
+		return err
+	}
+	if c.perLanguage {
+		return c.refreshPartitions(ctx)
+	}
+	if _, err := c.client.Collection(collectionName).Retrieve(ctx); err != nil {
+		return fmt.Errorf("songs collection not available: %w", err)
+	}
 	return nil
 }
 
+// SetIndexConcurrency sets how many batches are sent to Typesense in parallel
+// when indexing many songs. Values below 1 are ignored.
+func (c *Client) SetIndexConcurrency(n int) {
+	if n >= 1 {
+		c.concurrency = n
+	}
+}
+
+// SetIndexBatchSize sets how many songs go into one import request when
+// indexing many songs. Values below 1 are ignored.
+func (c *Client) SetIndexBatchSize(n int) {
+	if n >= 1 {
+		c.batchSize = n
+	}
+}
+
 func (c *Client) IndexSong(song *models.Song) error {
+	if err := c.available(true); err != nil {
+		return err
+	}
+	defer slowlog.Observe(slowlog.Typesense, "index song", time.Now())
 	ctx := context.Background()
 
-	doc := map[string]interface{}{
-		"id":         song.ID,
-		"title":      song.Title,
-		"lyrics":     song.DisplayLyrics,
-		"language":   song.Language,
-		"content":    song.MusicMinistryLyrics,
-		"updated_at": song.UpdatedAt.Unix(),
+	// Archived songs are kept out of search; the document may already be gone
+	if song.ArchivedAt != nil {
+		c.deleteDocument(ctx, song.ID, "")
+		return nil
 	}
 
-	if song.Artist != nil {
-		doc["artist"] = *song.Artist
+	name, err := c.ensureCollection(ctx, song.Language)
+	if err != nil {
+		return fmt.Errorf("error indexing song: %w", err)
 	}
-
-	_, err := c.client.Collection(collectionName).Documents().Upsert(ctx, doc)
+	_, err = c.client.Collection(name).Documents().Upsert(ctx, search.Document(song))
 	if err != nil {
 		return fmt.Errorf("error indexing song: %w", err)
 	}
 
+	// A song whose language changed is still in its old language's collection
+	if c.perLanguage {
+		c.deleteDocument(ctx, song.ID, name)
+	}
+
 	return nil
 }
 
 func (c *Client) DeleteSong(id string) error {
-	ctx := context.Background()
-	_, err := c.client.Collection(collectionName).Document(id).Delete(ctx)
-	if err != nil {
+	if err := c.available(true); err != nil {
+		return err
+	}
+	defer slowlog.Observe(slowlog.Typesense, "delete song", time.Now())
+	if err := c.deleteDocument(context.Background(), id, ""); err != nil {
 		return fmt.Errorf("error deleting song from index: %w", err)
 	}
 	return nil
 }
 
-type SearchResult struct {
-	Songs      []models.Song `json:"songs"`
-	TotalFound int           `json:"total_found"`
-	SearchTime int           `json:"search_time_ms"`
+// deleteDocument removes a song's document from every collection but except.
+// It fails only if none of them could delete it.
+func (c *Client) deleteDocument(ctx context.Context, id, except string) error {
+	names, err := c.collections(ctx)
+	if err != nil {
+		return err
+	}
+	var failed error
+	for _, name := range names {
+		if name == except {
+			continue
+		}
+		if _, err := c.client.Collection(name).Document(id).Delete(ctx); err != nil {
+			failed = err
+			continue
+		}
+		return nil
+	}
+	return failed
+}
+
+// IndexedSongs lists every document in the songs collection
+func (c *Client) IndexedSongs() ([]search.IndexedSong, error) {
+	if err := c.available(false); err != nil {
+		return nil, err
+	}
+	defer slowlog.Observe(slowlog.Typesense, "export documents", time.Now())
+
+	ctx := context.Background()
+	names, err := c.collections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	songs := make([]search.IndexedSong, 0)
+	for _, name := range names {
+		if songs, err = c.exportCollection(ctx, name, songs); err != nil {
+			return nil, err
+		}
+	}
+	return songs, nil
+}
+
+// exportCollection appends every document in a collection to songs
+func (c *Client) exportCollection(ctx context.Context, name string, songs []search.IndexedSong) ([]search.IndexedSong, error) {
+	body, err := c.client.Collection(name).Documents().Export(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting index: %w", err)
+	}
+	defer body.Close()
+
+	decoder := json.NewDecoder(body)
+	for {
+		var doc search.IndexedSong
+		if err := decoder.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading exported index: %w", err)
+		}
+		songs = append(songs, doc)
+	}
+	return songs, nil
 }
 
-func (c *Client) Search(query string, languages []string) (*SearchResult, error) {
+// Stats returns the document count and the newest document's update time
+func (c *Client) Stats() (*search.IndexStats, error) {
+	if err := c.available(false); err != nil {
+		return nil, err
+	}
+	defer slowlog.Observe(slowlog.Typesense, "collection stats", time.Now())
 	ctx := context.Background()
+	names, err := c.collections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &search.IndexStats{}
+	for _, name := range names {
+		if err := c.collectionStats(ctx, name, stats); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+// collectionStats adds a collection's documents to stats and moves
+// LastIndexedAt forward if its newest document is newer
+func (c *Client) collectionStats(ctx context.Context, name string, stats *search.IndexStats) error {
+	collection, err := c.client.Collection(name).Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving collection: %w", err)
+	}
+	if collection.NumDocuments != nil {
+		stats.Documents += *collection.NumDocuments
+	}
+
+	result, err := c.client.Collection(name).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:             "*",
+		QueryBy:       "title",
+		SortBy:        pointer.String("updated_at:desc"),
+		PerPage:       pointer.Int(1),
+		IncludeFields: pointer.String("updated_at"),
+	})
+	if err != nil {
+		return fmt.Errorf("error finding newest document: %w", err)
+	}
+	if result.Hits != nil && len(*result.Hits) > 0 {
+		if updatedAt, ok := (*(*result.Hits)[0].Document)["updated_at"].(float64); ok {
+			t := time.Unix(int64(updatedAt), 0)
+			if stats.LastIndexedAt == nil || t.After(*stats.LastIndexedAt) {
+				stats.LastIndexedAt = &t
+			}
+		}
+	}
+	return nil
+}
+
+// Search finds songs by title, artist and lyrics, optionally in some languages
+func (c *Client) Search(query string, languages []string) (*search.Result, error) {
+	if err := c.available(false); err != nil {
+		return nil, err
+	}
+	defer slowlog.Observe(slowlog.Typesense, "search", time.Now())
+	ctx := context.Background()
+
+	if c.perLanguage {
+		return c.searchPartitions(ctx, query, languages)
+	}
 
 	searchParams := &api.SearchCollectionParams{
 		Q:       query,
 		QueryBy: "title,artist,lyrics",
 		Prefix:  pointer.String("true"),
-		PerPage: pointer.Int(50),
+		PerPage: pointer.Int(searchLimit),
 		// Keep default text match ordering, but allow for score ties to be stable
 		HighlightStartTag: pointer.String(""),
 		HighlightEndTag:   pointer.String(""),
@@ -188,28 +401,7 @@ func (c *Client) Search(query string, languages []string) (*SearchResult, error)
 	songs := make([]models.Song, 0)
 	if result.Hits != nil {
 		for _, hit := range *result.Hits {
-			doc := *hit.Document
-			song := models.Song{
-				ID:                  doc["id"].(string),
-				Title:               doc["title"].(string),
-				Library:             "", // Not stored in Typesense, will be empty
-				DisplayLyrics:       doc["lyrics"].(string),
-				Language:            doc["language"].(string),
-				MusicMinistryLyrics: doc["content"].(string),
-				CreatedAt:           time.Now(), // Not stored in Typesense, using current time as default
-			}
-
-			if artist, ok := doc["artist"].(string); ok {
-				song.Artist = &artist
-			}
-
-			if updatedAt, ok := doc["updated_at"].(float64); ok {
-				song.UpdatedAt = time.Unix(int64(updatedAt), 0)
-			} else {
-				song.UpdatedAt = time.Now()
-			}
-
-			songs = append(songs, song)
+			songs = append(songs, search.SongFromDocument(*hit.Document))
 		}
 	}
 
@@ -223,21 +415,46 @@ func (c *Client) Search(query string, languages []string) (*SearchResult, error)
 		totalFound = *result.Found
 	}
 
-	return &SearchResult{
+	return &search.Result{
 		Songs:      songs,
 		TotalFound: totalFound,
 		SearchTime: searchTimeMs,
 	}, nil
 }
 
+// IndexSongs upserts many songs using batched import requests
+func (c *Client) IndexSongs(songs []models.Song) error {
+	if err := c.available(true); err != nil {
+		return err
+	}
+	progress := &search.Counter{}
+	c.indexBatches(songs, progress)
+	return progress.Err()
+}
+
+// ReindexAll rebuilds the collection and returns the first song that failed
 func (c *Client) ReindexAll(songs []models.Song) error {
+	progress := &search.Counter{}
+	if err := c.Reindex(songs, progress); err != nil {
+		return err
+	}
+	return progress.Err()
+}
+
+// Reindex drops and rebuilds the collection from songs, reporting progress
+// batch by batch. A song that fails to index is reported and skipped; only
+// failing to recreate the collection stops the reindex.
+func (c *Client) Reindex(songs []models.Song, progress search.ReindexProgress) error {
+	if err := c.available(true); err != nil {
+		return err
+	}
 	ctx := context.Background()
 	log.Println("Starting full reindex...")
+	started := time.Now()
 
-	// Delete existing collection
-	_, err := c.client.Collection(collectionName).Delete(ctx)
-	if err != nil {
-		log.Printf("Warning: could not delete existing collection: %v", err)
+	// Delete existing collections, including those of the other layout
+	if err := c.dropCollections(ctx); err != nil {
+		log.Printf("Warning: could not delete existing collections: %v", err)
 	}
 
 	// Recreate schema
@@ -245,16 +462,144 @@ func (c *Client) ReindexAll(songs []models.Song) error {
 		return fmt.Errorf("error recreating schema: %w", err)
 	}
 
-	// Index all songs
-	for i, song := range songs {
-		if err := c.IndexSong(&song); err != nil {
-			return fmt.Errorf("error indexing song %s: %w", song.ID, err)
+	c.indexBatches(songs, progress)
+
+	log.Printf("Reindex complete: %d songs processed in %s", len(songs), time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// dropCollections deletes the songs collection and every language collection
+func (c *Client) dropCollections(ctx context.Context) error {
+	all, err := c.client.Collections().Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing collections: %w", err)
+	}
+	for _, collection := range all {
+		if collection.Name != collectionName && !strings.HasPrefix(collection.Name, partitionPrefix) {
+			continue
 		}
-		if (i+1)%100 == 0 {
-			log.Printf("Indexed %d/%d songs", i+1, len(songs))
+		if _, err := c.client.Collection(collection.Name).Delete(ctx); err != nil {
+			return fmt.Errorf("error deleting collection %s: %w", collection.Name, err)
 		}
 	}
-
-	log.Printf("Reindex complete: %d songs indexed", len(songs))
 	return nil
 }
+
+// importJob is one import request: a batch of songs for one collection
+type importJob struct {
+	collection string
+	songs      []models.Song
+}
+
+// indexBatches splits songs into batches and imports them with a bounded
+// pool of workers
+func (c *Client) indexBatches(songs []models.Song, progress search.ReindexProgress) {
+	songs = search.Searchable(songs)
+	jobs, failed := c.importJobs(songs)
+	progress.SetTotal(len(songs), len(jobs))
+	for _, err := range failed {
+		progress.Fail(err)
+	}
+
+	workers := c.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	var indexed int64
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range queue {
+				progress.StartBatch(batch + 1)
+
+				job := jobs[batch]
+				c.importBatch(job.collection, job.songs, progress)
+
+				done := atomic.AddInt64(&indexed, int64(len(job.songs)))
+				log.Printf("Indexed %d/%d songs", done, len(songs))
+			}
+		}()
+	}
+
+	for batch := range jobs {
+		queue <- batch
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// importJobs groups songs by collection and splits each group into batches.
+// Songs whose collection can't be created are returned as failures.
+func (c *Client) importJobs(songs []models.Song) ([]importJob, []error) {
+	groups := map[string][]models.Song{}
+	var order []string
+	var failed []error
+	for _, song := range songs {
+		name, err := c.ensureCollection(context.Background(), song.Language)
+		if err != nil {
+			failed = append(failed, fmt.Errorf("song %s (%s): %w", song.ID, song.Title, err))
+			continue
+		}
+		if _, ok := groups[name]; !ok {
+			order = append(order, name)
+		}
+		groups[name] = append(groups[name], song)
+	}
+
+	var jobs []importJob
+	for _, name := range order {
+		group := groups[name]
+		for start := 0; start < len(group); start += c.batchSize {
+			end := start + c.batchSize
+			if end > len(group) {
+				end = len(group)
+			}
+			jobs = append(jobs, importJob{collection: name, songs: group[start:end]})
+		}
+	}
+	return jobs, failed
+}
+
+// importBatch upserts one batch of songs in a single request and reports the
+// result of each song
+func (c *Client) importBatch(collection string, songs []models.Song, progress search.ReindexProgress) {
+	defer slowlog.Observe(slowlog.Typesense, "import batch", time.Now())
+	ctx := context.Background()
+
+	docs := make([]interface{}, len(songs))
+	for i := range songs {
+		docs[i] = search.Document(&songs[i])
+	}
+
+	results, err := c.importer.Collection(collection).Documents().Import(ctx, docs, &api.ImportDocumentsParams{
+		Action:    pointer.String("upsert"),
+		BatchSize: pointer.Int(len(docs)),
+	})
+	if err == nil && len(results) != len(songs) {
+		err = fmt.Errorf("expected %d import results, got %d", len(songs), len(results))
+	}
+	if err != nil {
+		for i := range songs {
+			progress.Fail(fmt.Errorf("song %s (%s): error importing batch: %w", songs[i].ID, songs[i].Title, err))
+		}
+		return
+	}
+
+	done := 0
+	for i, result := range results {
+		if result.Success {
+			done++
+			continue
+		}
+		progress.Fail(fmt.Errorf("song %s (%s): %s", songs[i].ID, songs[i].Title, result.Error))
+	}
+	progress.Done(done)
+}
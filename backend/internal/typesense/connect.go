@@ -0,0 +1,43 @@
+package typesense
+
+import (
+	"errors"
+	"log"
+
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
+)
+
+// ErrUnavailable is returned while Typesense has not come up yet
+var ErrUnavailable = errors.New("typesense is not available")
+
+// Connect creates a client without failing when Typesense is unreachable.
+// perLanguage keeps each language in its own collection (see partition.go). If
+// the schema can't be initialized yet the client starts degraded: Ready
+// reports false, calls return ErrUnavailable, and the connection is retried
+// in the background until it succeeds.
+func Connect(apiKey, host string, perLanguage bool) *Client {
+	tc := &Client{
+		client:       newSDKClient(apiKey, host, requestTimeout),
+		importer:     newSDKClient(apiKey, host, importTimeout),
+		Availability: search.NewAvailability(false),
+		concurrency:  defaultIndexConcurrency,
+		batchSize:    defaultIndexBatchSize,
+		perLanguage:  perLanguage,
+	}
+
+	if err := tc.initSchema(); err != nil {
+		log.Printf("⚠️  Typesense unavailable, starting with database search: %v", err)
+		go tc.Retry("Typesense", tc.initSchema)
+		return tc
+	}
+
+	tc.Availability = search.NewAvailability(true)
+	log.Println("Typesense client initialized")
+	return tc
+}
+
+// available returns ErrUnavailable while degraded. Writes pass write=true so
+// a skipped change marks the index stale.
+func (c *Client) available(write bool) error {
+	return c.Check(write, ErrUnavailable)
+}
@@ -0,0 +1,232 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/typesense/typesense-go/typesense/api"
+	"github.com/typesense/typesense-go/typesense/api/pointer"
+	"github.com/yourusername/audience-stage-teleprompter/internal/language"
+	"github.com/yourusername/audience-stage-teleprompter/internal/models"
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
+)
+
+// With perLanguage set, songs are kept in one collection per language
+// (songs_english, songs_malayalam, ...) instead of the single songs
+// collection, and each collection tokenizes its text with the language's
+// locale. The default tokenizer splits Malayalam and Devanagari words apart
+// at vowel signs and conjuncts, so whole-word matches in those scripts rank
+// poorly. Searches query the language collections in one multi_search
+// request and merge the hits by text match score.
+
+// partitionPrefix starts the name of every language collection
+const partitionPrefix = collectionName + "_"
+
+// partitionRefresh is how long the list of language collections is trusted
+// before it is read again, so collections created by ast are picked up
+const partitionRefresh = time.Minute
+
+// partitions remembers which language collections exist
+type partitions struct {
+	mu        sync.Mutex
+	names     map[string]bool
+	refreshed time.Time
+}
+
+// partitionName is the collection for a language
+func partitionName(lang string) string {
+	key := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(strings.TrimSpace(lang)))
+	if key == "" {
+		key = "unknown"
+	}
+	return partitionPrefix + key
+}
+
+// partitionLocale is the Typesense locale for a language's collection.
+// English and unknown languages keep the default tokenizer.
+func partitionLocale(lang string) string {
+	code := language.Code(strings.ToLower(strings.TrimSpace(lang)))
+	if code == "en" {
+		return ""
+	}
+	return code
+}
+
+// collectionFor is the collection a song in lang belongs in
+func (c *Client) collectionFor(lang string) string {
+	if !c.perLanguage {
+		return collectionName
+	}
+	return partitionName(lang)
+}
+
+// refreshPartitions reads the list of language collections from Typesense
+func (c *Client) refreshPartitions(ctx context.Context) error {
+	all, err := c.client.Collections().Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing collections: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for _, collection := range all {
+		if strings.HasPrefix(collection.Name, partitionPrefix) {
+			names[collection.Name] = true
+		}
+	}
+
+	c.partitions.mu.Lock()
+	c.partitions.names = names
+	c.partitions.refreshed = time.Now()
+	c.partitions.mu.Unlock()
+	return nil
+}
+
+// collections lists the collections holding songs, in name order
+func (c *Client) collections(ctx context.Context) ([]string, error) {
+	if !c.perLanguage {
+		return []string{collectionName}, nil
+	}
+
+	c.partitions.mu.Lock()
+	stale := time.Since(c.partitions.refreshed) > partitionRefresh
+	c.partitions.mu.Unlock()
+	if stale {
+		if err := c.refreshPartitions(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	c.partitions.mu.Lock()
+	defer c.partitions.mu.Unlock()
+	names := make([]string, 0, len(c.partitions.names))
+	for name := range c.partitions.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ensureCollection returns the collection for lang, creating it the first
+// time a song in that language is indexed
+func (c *Client) ensureCollection(ctx context.Context, lang string) (string, error) {
+	name := c.collectionFor(lang)
+	if !c.perLanguage {
+		return name, nil
+	}
+
+	c.partitions.mu.Lock()
+	known := c.partitions.names[name]
+	c.partitions.mu.Unlock()
+	if known {
+		return name, nil
+	}
+
+	if _, err := c.client.Collection(name).Retrieve(ctx); err != nil {
+		if err := c.createCollection(ctx, name, partitionLocale(lang)); err != nil {
+			// Another request may have created it meanwhile
+			if _, rerr := c.client.Collection(name).Retrieve(ctx); rerr != nil {
+				return "", err
+			}
+		}
+	}
+
+	c.partitions.mu.Lock()
+	if c.partitions.names == nil {
+		c.partitions.names = make(map[string]bool)
+	}
+	c.partitions.names[name] = true
+	c.partitions.mu.Unlock()
+	return name, nil
+}
+
+// searchPartitions searches the collections of the given languages, or all
+// of them, in one multi_search request. Text match scores are computed the
+// same way in every collection, so the hits are merged by score.
+func (c *Client) searchPartitions(ctx context.Context, query string, languages []string) (*search.Result, error) {
+	names, err := c.collections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(languages) > 0 {
+		wanted := make(map[string]bool)
+		for _, lang := range languages {
+			if strings.TrimSpace(lang) != "" {
+				wanted[partitionName(lang)] = true
+			}
+		}
+		kept := make([]string, 0, len(wanted))
+		for _, name := range names {
+			if wanted[name] {
+				kept = append(kept, name)
+			}
+		}
+		names = kept
+	}
+
+	merged := &search.Result{Songs: make([]models.Song, 0)}
+	if len(names) == 0 {
+		return merged, nil
+	}
+
+	searches := make([]api.MultiSearchCollectionParameters, len(names))
+	for i, name := range names {
+		searches[i] = api.MultiSearchCollectionParameters{
+			Collection:        name,
+			Q:                 pointer.String(query),
+			QueryBy:           pointer.String("title,artist,lyrics"),
+			Prefix:            pointer.String("true"),
+			PerPage:           pointer.Int(searchLimit),
+			HighlightStartTag: pointer.String(""),
+			HighlightEndTag:   pointer.String(""),
+		}
+	}
+
+	result, err := c.client.MultiSearch.Perform(ctx, &api.MultiSearchParams{}, api.MultiSearchSearchesParameter{Searches: searches})
+	if err != nil {
+		return nil, fmt.Errorf("error searching: %w", err)
+	}
+
+	var hits []api.SearchResultHit
+	for _, r := range result.Results {
+		if r.Found != nil {
+			merged.TotalFound += *r.Found
+		}
+		// The collections are searched in parallel
+		if r.SearchTimeMs != nil && *r.SearchTimeMs > merged.SearchTime {
+			merged.SearchTime = *r.SearchTimeMs
+		}
+		if r.Hits != nil {
+			hits = append(hits, *r.Hits...)
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return textMatch(hits[i]) > textMatch(hits[j])
+	})
+	if len(hits) > searchLimit {
+		hits = hits[:searchLimit]
+	}
+	for _, hit := range hits {
+		if hit.Document != nil {
+			merged.Songs = append(merged.Songs, search.SongFromDocument(*hit.Document))
+		}
+	}
+	return merged, nil
+}
+
+func textMatch(hit api.SearchResultHit) int64 {
+	if hit.TextMatch == nil {
+		return 0
+	}
+	return *hit.TextMatch
+}
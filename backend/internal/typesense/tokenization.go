@@ -0,0 +1,127 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/typesense/typesense-go/typesense/api"
+	"github.com/yourusername/audience-stage-teleprompter/internal/search"
+)
+
+// Typesense fixes a collection's locales and token separators when it is
+// created, so changed settings only take effect after a reindex recreates
+// the collections.
+
+// tokenizer holds the tokenization settings new collections are created with
+type tokenizer struct {
+	mu       sync.Mutex
+	settings search.Tokenization
+}
+
+func (t *tokenizer) get() search.Tokenization {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.settings
+}
+
+// fieldLocales is the locale of each text field in a collection whose
+// language's locale is locale ("" for the songs collection). Locales set per
+// field in the settings take precedence.
+func (c *Client) fieldLocales(locale string) map[string]string {
+	settings := c.tokenizer.get()
+	locales := make(map[string]string, len(search.TextFields))
+	for _, field := range search.TextFields {
+		if l, ok := settings.FieldLocales[field]; ok {
+			locales[field] = l
+		} else {
+			locales[field] = locale
+		}
+	}
+	return locales
+}
+
+// separators is the token separator setting in Typesense's form, one
+// character per entry, sorted
+func separators(s string) []string {
+	seps := make([]string, 0, len(s))
+	for _, r := range s {
+		seps = append(seps, string(r))
+	}
+	sort.Strings(seps)
+	return seps
+}
+
+// applyTokenization sets the locales and token separators on the schema of
+// a new collection
+func (c *Client) applyTokenization(schema *api.CollectionSchema, locale string) {
+	locales := c.fieldLocales(locale)
+	for i, field := range schema.Fields {
+		if l := locales[field.Name]; l != "" {
+			l := l
+			schema.Fields[i].Locale = &l
+		}
+	}
+	if seps := separators(c.tokenizer.get().TokenSeparators); len(seps) > 0 {
+		schema.TokenSeparators = &seps
+	}
+}
+
+// tokenizedAs reports whether an existing collection was created with the
+// current settings
+func (c *Client) tokenizedAs(collection *api.CollectionResponse, locale string) bool {
+	locales := c.fieldLocales(locale)
+	for _, field := range collection.Fields {
+		want, text := locales[field.Name]
+		if !text {
+			continue
+		}
+		have := ""
+		if field.Locale != nil {
+			have = *field.Locale
+		}
+		if have != want {
+			return false
+		}
+	}
+
+	var have []string
+	if collection.TokenSeparators != nil {
+		have = append(have, *collection.TokenSeparators...)
+	}
+	sort.Strings(have)
+	return strings.Join(have, "") == strings.Join(separators(c.tokenizer.get().TokenSeparators), "")
+}
+
+// SetTokenization changes the tokenization of collections created from now
+// on and reports whether any existing collection differs from it
+func (c *Client) SetTokenization(ctx context.Context, t search.Tokenization) (bool, error) {
+	c.tokenizer.mu.Lock()
+	c.tokenizer.settings = t
+	c.tokenizer.mu.Unlock()
+
+	if !c.Ready() {
+		return false, nil
+	}
+
+	names, err := c.collections(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, name := range names {
+		collection, err := c.client.Collection(name).Retrieve(ctx)
+		if err != nil {
+			return false, fmt.Errorf("error retrieving collection %s: %w", name, err)
+		}
+		locale := ""
+		if c.perLanguage {
+			locale = partitionLocale(strings.TrimPrefix(name, partitionPrefix))
+		}
+		if !c.tokenizedAs(collection, locale) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
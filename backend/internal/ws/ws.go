@@ -0,0 +1,279 @@
+// Package ws is a small WebSocket (RFC 6455) server for fiber handlers. It
+// supports what the app's editors and displays need - text and binary
+// messages, pings and closing - without extensions or compression.
+package ws
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaxMessageSize caps a single incoming message; a song's lyrics are far smaller
+const MaxMessageSize = 1 << 20
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Frame opcodes
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+var (
+	ErrNotWebSocket    = errors.New("not a websocket handshake")
+	ErrForbiddenOrigin = errors.New("websocket origin not allowed")
+	ErrTooLarge        = errors.New("websocket message too large")
+	ErrProtocol        = errors.New("websocket protocol error")
+)
+
+var allowedOrigins atomic.Value // map[string]bool
+
+func init() {
+	SetAllowedOrigins("")
+}
+
+// SetAllowedOrigins sets the origins, besides the server's own, whose pages
+// may open a WebSocket, from a comma-separated list like CORS_ORIGINS.
+// Browsers send cookies with a handshake from any site and sockets are
+// authenticated by the session cookie, so "*" allows no other origin.
+func SetAllowedOrigins(origins string) {
+	allowed := make(map[string]bool)
+	for _, origin := range strings.Split(origins, ",") {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		if origin != "" && origin != "*" {
+			allowed[origin] = true
+		}
+	}
+	allowedOrigins.Store(allowed)
+}
+
+// OriginAllowed reports whether a handshake's Origin may connect: the
+// server's own host, or an allowed origin. Requests without an Origin come
+// from programs rather than web pages and are let through.
+func OriginAllowed(c *fiber.Ctx) bool {
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, c.Hostname()) {
+		return true
+	}
+	return allowedOrigins.Load().(map[string]bool)[strings.ToLower(strings.TrimRight(origin, "/"))]
+}
+
+// IsUpgrade reports whether the request asks to switch to a WebSocket
+func IsUpgrade(c *fiber.Ctx) bool {
+	if !strings.EqualFold(c.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, token := range strings.Split(c.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// Upgrade answers the handshake and runs handler on the connection once the
+// response has been sent. The connection is closed when handler returns.
+// Handshakes from origins OriginAllowed refuses fail with ErrForbiddenOrigin.
+func Upgrade(c *fiber.Ctx, handler func(*Conn)) error {
+	key := c.Get("Sec-WebSocket-Key")
+	if !IsUpgrade(c) || c.Get("Sec-WebSocket-Version") != "13" || key == "" {
+		return ErrNotWebSocket
+	}
+	if !OriginAllowed(c) {
+		return ErrForbiddenOrigin
+	}
+
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	c.Set("Upgrade", "websocket")
+	c.Set("Connection", "Upgrade")
+	c.Set("Sec-WebSocket-Accept", base64.StdEncoding.EncodeToString(sum[:]))
+	c.Status(101)
+
+	c.Context().Hijack(func(nc net.Conn) {
+		// Clear the server's read timeout; the connection is long-lived
+		_ = nc.SetDeadline(time.Time{})
+		conn := &Conn{conn: nc, r: bufio.NewReader(nc)}
+		defer conn.conn.Close()
+		handler(conn)
+	})
+	return nil
+}
+
+// Conn is an open WebSocket connection. Reads must come from one goroutine;
+// writes may come from any.
+type Conn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	mu   sync.Mutex
+}
+
+// ReadMessage returns the next text or binary message, answering pings along
+// the way. It returns io.EOF once the client closes the connection.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var message []byte
+	started := false
+
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			_ = c.writeFrame(opClose, payload)
+			return nil, io.EOF
+		case opText, opBinary:
+			if started {
+				return nil, ErrProtocol
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				return nil, ErrProtocol
+			}
+		default:
+			return nil, ErrProtocol
+		}
+
+		if len(message)+len(payload) > MaxMessageSize {
+			return nil, ErrTooLarge
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// ReadJSON reads the next message into v
+func (c *Conn) ReadJSON(v interface{}) error {
+	message, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(message, v)
+}
+
+// WriteMessage sends a text message
+func (c *Conn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// WriteJSON sends v as a text message
+func (c *Conn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(data)
+}
+
+// Ping checks the connection is still alive and keeps proxies from closing it
+func (c *Conn) Ping() error {
+	return c.writeFrame(opPing, nil)
+}
+
+// Close sends a normal closure to the client and closes the connection
+func (c *Conn) Close() error {
+	_ = c.writeFrame(opClose, []byte{0x03, 0xE8})
+	return c.conn.Close()
+}
+
+// readFrame reads one frame. Clients must mask every frame they send.
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.r, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := head[0]&0x80 != 0
+	op := head[0] & 0x0F
+	if head[0]&0x70 != 0 || head[1]&0x80 == 0 {
+		return false, 0, nil, ErrProtocol
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > MaxMessageSize {
+		return false, 0, nil, ErrTooLarge
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame sends one unfragmented, unmasked frame in a single write
+func (c *Conn) writeFrame(op byte, payload []byte) error {
+	frame := make([]byte, 2, 10+len(payload))
+	frame[0] = 0x80 | op
+	switch n := len(payload); {
+	case n < 126:
+		frame[1] = byte(n)
+	case n <= 0xFFFF:
+		frame[1] = 126
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame[1] = 127
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, payload...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.conn.Write(frame)
+	return err
+}
@@ -8,9 +8,13 @@ import SongForm from '@/components/SongForm';
 import SongFullScreen from '@/components/SongFullScreen';
 import SettingsDialog from '@/components/SettingsDialog';
 import QueuePanel from '@/components/QueuePanel';
+import AuthGate from '@/components/AuthGate';
 
-export default function Home() {
+function Home() {
   const [songs, setSongs] = useState<Song[]>([]);
+  const [songsPage, setSongsPage] = useState(0);
+  const [hasMoreSongs, setHasMoreSongs] = useState(false);
+  const [loadingMoreSongs, setLoadingMoreSongs] = useState(false);
   const [selectedSong, setSelectedSong] = useState<Song | null>(null);
   const [liveSong, setLiveSong] = useState<Song | null>(null);
   const [showForm, setShowForm] = useState(false);
@@ -145,7 +149,7 @@ export default function Home() {
     fetchQueue();
   };
 
-  // Load all songs and queue on mount
+  // Load the first page of songs and the queue on mount
   useEffect(() => {
     loadSongs();
     checkProPresenterStatus();
@@ -220,11 +224,14 @@ export default function Home() {
     }
   }, [zoomLevel, selectedSong]);
 
+  // Load the first page of songs; more pages are loaded on request
   const loadSongs = async () => {
     try {
       setLoading(true);
-      const allSongs = await songsApi.getAll();
-      setSongs(allSongs);
+      const first = await songsApi.getPage(1);
+      setSongs(first.songs);
+      setSongsPage(first.page);
+      setHasMoreSongs(first.page < first.total_pages);
     } catch (error) {
       console.error('Error loading songs:', error);
     } finally {
@@ -232,6 +239,24 @@ export default function Home() {
     }
   };
 
+  const loadMoreSongs = async () => {
+    try {
+      setLoadingMoreSongs(true);
+      const next = await songsApi.getPage(songsPage + 1);
+      setSongs(prevSongs => {
+        // Songs added or moved up since the last page are already listed
+        const listed = new Set(prevSongs.map(song => song.id));
+        return [...prevSongs, ...next.songs.filter(song => !listed.has(song.id))];
+      });
+      setSongsPage(next.page);
+      setHasMoreSongs(next.page < next.total_pages);
+    } catch (error) {
+      console.error('Error loading more songs:', error);
+    } finally {
+      setLoadingMoreSongs(false);
+    }
+  };
+
   const handleSearch = useCallback(async (query: string, languages: string[]) => {
     const trimmed = query.trim();
     const hasLanguages = languages.length > 0;
@@ -960,6 +985,9 @@ export default function Home() {
                   queuedSongIds={queuedSongIds}
                   selectedSongId={selectedSong?.id}
                   loading={loading}
+                  hasMore={!searchResults && hasMoreSongs}
+                  loadingMore={loadingMoreSongs}
+                  onLoadMore={loadMoreSongs}
                 />
               </div>
             </div>
@@ -1124,3 +1152,11 @@ export default function Home() {
     </>
   );
 }
+
+export default function Page() {
+  return (
+    <AuthGate>
+      <Home />
+    </AuthGate>
+  );
+}
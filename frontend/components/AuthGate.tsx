@@ -0,0 +1,156 @@
+'use client';
+
+import { useState, useEffect, FormEvent, ReactNode } from 'react';
+import { authApi, SIGNED_OUT_EVENT } from '@/lib/api';
+
+type AuthState = 'checking' | 'signed-out' | 'reset-password' | 'signed-in' | 'open';
+
+const inputClass = 'w-full px-4 py-3 bg-[#16171b] text-gray-100 rounded-lg border border-[#2a2c31] focus:ring-2 focus:ring-[#3a3c42] focus:border-[#3a3c42] placeholder-gray-500';
+
+function errorMessage(error: any, fallback: string): string {
+  return error?.response?.data?.error || fallback;
+}
+
+// Shows its children once signed in, and a sign-in form until then
+export default function AuthGate({ children }: { children: ReactNode }) {
+  const [state, setState] = useState<AuthState>('checking');
+  const [username, setUsername] = useState('');
+  const [password, setPassword] = useState('');
+  const [newPassword, setNewPassword] = useState('');
+  // Whether the password just signed in with is the one to replace
+  const [passwordKnown, setPasswordKnown] = useState(false);
+  const [error, setError] = useState('');
+  const [submitting, setSubmitting] = useState(false);
+
+  useEffect(() => {
+    authApi.me()
+      .then((status) => {
+        if (!status.auth_enabled) {
+          setState('open');
+        } else if (!status.user) {
+          setState('signed-out');
+        } else {
+          setState(status.user.must_reset_password ? 'reset-password' : 'signed-in');
+        }
+      })
+      .catch((err) => {
+        console.error('Error checking sign-in:', err);
+        setState('signed-out');
+      });
+
+    const handleSignedOut = () => setState((current) => (current === 'open' ? current : 'signed-out'));
+    window.addEventListener(SIGNED_OUT_EVENT, handleSignedOut);
+    return () => window.removeEventListener(SIGNED_OUT_EVENT, handleSignedOut);
+  }, []);
+
+  const handleSignIn = async (e: FormEvent) => {
+    e.preventDefault();
+    setSubmitting(true);
+    setError('');
+    try {
+      const user = await authApi.login(username, password);
+      setPasswordKnown(true);
+      setState(user.must_reset_password ? 'reset-password' : 'signed-in');
+    } catch (err) {
+      setError(errorMessage(err, 'Failed to sign in'));
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  const handleResetPassword = async (e: FormEvent) => {
+    e.preventDefault();
+    setSubmitting(true);
+    setError('');
+    try {
+      await authApi.changePassword(password, newPassword);
+      setNewPassword('');
+      setState('signed-in');
+    } catch (err) {
+      setError(errorMessage(err, 'Failed to change password'));
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  if (state === 'checking') {
+    return null;
+  }
+  if (state === 'signed-in') {
+    return <>{children}</>;
+  }
+  if (state === 'open') {
+    return (
+      <>
+        <div className="bg-red-700 text-white text-sm text-center px-4 py-1">
+          Sign-in is disabled (AUTH_DISABLED): anyone who can reach this server can change everything
+        </div>
+        {children}
+      </>
+    );
+  }
+
+  const resetting = state === 'reset-password';
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-[#0f1013] p-6">
+      <form
+        onSubmit={resetting ? handleResetPassword : handleSignIn}
+        className="w-full max-w-sm space-y-4 bg-[#141518] border border-[#24262c] rounded-xl p-6"
+      >
+        <h1 className="text-xl font-semibold text-white">
+          {resetting ? 'Choose a new password' : 'Sign in'}
+        </h1>
+        {resetting ? (
+          <>
+            {!passwordKnown && (
+              <input
+                type="password"
+                placeholder="Current password"
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                className={inputClass}
+                autoFocus
+              />
+            )}
+            <input
+              type="password"
+              placeholder="New password (at least 8 characters)"
+              value={newPassword}
+              onChange={(e) => setNewPassword(e.target.value)}
+              className={inputClass}
+              autoFocus={passwordKnown}
+            />
+          </>
+        ) : (
+          <>
+            <input
+              type="text"
+              placeholder="Username"
+              value={username}
+              onChange={(e) => setUsername(e.target.value)}
+              className={inputClass}
+              autoComplete="username"
+              autoFocus
+            />
+            <input
+              type="password"
+              placeholder="Password"
+              value={password}
+              onChange={(e) => setPassword(e.target.value)}
+              className={inputClass}
+              autoComplete="current-password"
+            />
+          </>
+        )}
+        {error && <p className="text-sm text-red-400">{error}</p>}
+        <button
+          type="submit"
+          disabled={submitting}
+          className="w-full py-2.5 rounded-lg bg-[#2c2d32] border border-[#3a3c42] text-gray-100 font-medium hover:bg-[#34353b] disabled:opacity-50"
+        >
+          {submitting ? 'Please wait...' : resetting ? 'Change password' : 'Sign in'}
+        </button>
+      </form>
+    </div>
+  );
+}
@@ -11,9 +11,12 @@ interface SongListProps {
   onSendToLive?: (song: Song) => void;
   onAddToQueue?: (song: Song) => void;
   queuedSongIds?: Set<string>;
+  hasMore?: boolean;
+  loadingMore?: boolean;
+  onLoadMore?: () => void;
 }
 
-export default function SongList({ songs, onSelectSong, selectedSongId, loading, onEdit, onSendToLive, onAddToQueue, queuedSongIds }: SongListProps) {
+export default function SongList({ songs, onSelectSong, selectedSongId, loading, onEdit, onSendToLive, onAddToQueue, queuedSongIds, hasMore, loadingMore, onLoadMore }: SongListProps) {
   if (loading) {
     return (
       <div className="bg-[#1a1b1f] rounded-xl border border-[#2a2c31] p-6 text-center">
@@ -116,6 +119,17 @@ export default function SongList({ songs, onSelectSong, selectedSongId, loading,
             </div>
           </div>
         ))}
+        {hasMore && onLoadMore && (
+          <div className="p-3 text-center">
+            <button
+              onClick={onLoadMore}
+              disabled={loadingMore}
+              className="px-4 py-1.5 rounded-md border border-[#2a2c31] text-sm text-gray-300 hover:text-gray-100 hover:border-[#3a3c42] transition-colors disabled:opacity-50"
+            >
+              {loadingMore ? 'Loading...' : 'Load more songs'}
+            </button>
+          </div>
+        )}
       </div>
     </div>
   );
@@ -12,6 +12,78 @@ const api = axios.create({
   timeout: 30000, // 30 second timeout
 });
 
+// The session token from signing in, sent as a bearer token so the API can be
+// on another origin without cookies
+const TOKEN_KEY = 'ast-session-token';
+
+// Fired when the API answers 401, so the page can ask to sign in again
+export const SIGNED_OUT_EVENT = 'ast-signed-out';
+
+api.interceptors.request.use((config) => {
+  const token = typeof window !== 'undefined' ? localStorage.getItem(TOKEN_KEY) : null;
+  if (token) {
+    config.headers.Authorization = `Bearer ${token}`;
+  }
+  return config;
+});
+
+api.interceptors.response.use(undefined, (error) => {
+  if (error.response?.status === 401 && typeof window !== 'undefined' && !error.config?.url?.startsWith('/auth/')) {
+    localStorage.removeItem(TOKEN_KEY);
+    window.dispatchEvent(new Event(SIGNED_OUT_EVENT));
+  }
+  return Promise.reject(error);
+});
+
+export interface User {
+  id: number;
+  username: string;
+  display_name: string;
+  role: string;
+  must_reset_password: boolean;
+}
+
+export interface AuthStatus {
+  user?: User;
+  auth_enabled: boolean;
+}
+
+export const authApi = {
+  // The signed-in user, or none (auth_enabled is false when the server runs without sign-in)
+  me: async (): Promise<AuthStatus> => {
+    try {
+      const response = await api.get<AuthStatus>('/auth/me');
+      return response.data;
+    } catch (error: any) {
+      if (error.response?.status === 401) {
+        return { auth_enabled: error.response.data?.auth_enabled ?? true };
+      }
+      throw error;
+    }
+  },
+
+  // Sign in and keep the session token
+  login: async (username: string, password: string): Promise<User> => {
+    const response = await api.post<{ user: User; token: string }>('/auth/login', { username, password });
+    localStorage.setItem(TOKEN_KEY, response.data.token);
+    return response.data.user;
+  },
+
+  // Replace a temporary password
+  changePassword: async (currentPassword: string, newPassword: string): Promise<void> => {
+    await api.post('/auth/password', { current_password: currentPassword, new_password: newPassword });
+  },
+
+  // End the session
+  logout: async (): Promise<void> => {
+    try {
+      await api.post('/auth/logout');
+    } finally {
+      localStorage.removeItem(TOKEN_KEY);
+    }
+  },
+};
+
 export interface Song {
   id: string;
   title: string;
@@ -46,6 +118,14 @@ export interface UpdateSongRequest {
   artist?: string;
 }
 
+export interface SongPage {
+  songs: Song[];
+  page: number;
+  per_page: number;
+  total: number;
+  total_pages: number;
+}
+
 export interface SearchResult {
   songs: Song[];
   total_found: number;
@@ -60,9 +140,9 @@ export const songsApi = {
     return response.data;
   },
 
-  // Get all songs
-  getAll: async (): Promise<Song[]> => {
-    const response = await api.get<Song[]>('/songs');
+  // Get a page of songs, newest changes first
+  getPage: async (page = 1, perPage = 100): Promise<SongPage> => {
+    const response = await api.get<SongPage>('/songs', { params: { page, per_page: perPage } });
     return response.data;
   },
 